@@ -21,6 +21,8 @@ import (
 	"github.com/harness/gitness/app/auth"
 	"github.com/harness/gitness/app/auth/authz"
 	"github.com/harness/gitness/app/paths"
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
 
@@ -66,3 +68,53 @@ func IsRepoOwner(
 
 	return err == nil, nil
 }
+
+// CheckRepoMaintainerEdit checks if the principal is allowed to push to a branch of a fork as a maintainer
+// of its upstream repository. That's the case if the principal has push access to the upstream repository
+// and the branch is the source branch of an open pull request into the upstream that allows maintainer edits.
+// If the branch is empty, any such pull request grants the access.
+// Returns nil if the access is granted, otherwise returns an error.
+// NotAuthenticated, NotAuthorized, or any underlying error.
+func CheckRepoMaintainerEdit(
+	ctx context.Context,
+	authorizer authz.Authorizer,
+	repoStore store.RepoStore,
+	pullreqStore store.PullReqStore,
+	session *auth.Session,
+	fork *types.Repository,
+	branch string,
+) error {
+	if fork.ForkID == 0 {
+		return ErrNotAuthorized
+	}
+
+	upstream, err := repoStore.Find(ctx, fork.ForkID)
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return ErrNotAuthorized
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find upstream repository: %w", err)
+	}
+
+	if err = CheckRepo(ctx, authorizer, session, upstream, enum.PermissionRepoPush); err != nil {
+		return err
+	}
+
+	prs, err := pullreqStore.List(ctx, &types.PullReqFilter{
+		SourceRepoID: fork.ID,
+		SourceBranch: branch,
+		TargetRepoID: upstream.ID,
+		States:       []enum.PullReqState{enum.PullReqStateOpen},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pull requests opened from the fork: %w", err)
+	}
+
+	for _, pr := range prs {
+		if pr.AllowMaintainerEdit {
+			return nil
+		}
+	}
+
+	return ErrNotAuthorized
+}
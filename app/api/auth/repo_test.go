@@ -0,0 +1,216 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+func TestCheckRepoMaintainerEdit(t *testing.T) {
+	upstream := &types.Repository{ID: 1, Path: "space/upstream"}
+	fork := &types.Repository{ID: 2, Path: "other/fork", ForkID: upstream.ID}
+
+	prAllowingEdits := &types.PullReq{
+		SourceRepoID:        fork.ID,
+		SourceBranch:        "feature",
+		TargetRepoID:        upstream.ID,
+		State:               enum.PullReqStateOpen,
+		AllowMaintainerEdit: true,
+	}
+
+	tests := []struct {
+		name      string
+		fork      *types.Repository
+		repos     []*types.Repository
+		pushRepos []string
+		prs       []*types.PullReq
+		branch    string
+		expErr    error
+	}{
+		{
+			name:      "not-a-fork",
+			fork:      upstream,
+			repos:     []*types.Repository{upstream},
+			pushRepos: []string{upstream.Path},
+			prs:       []*types.PullReq{prAllowingEdits},
+			branch:    "feature",
+			expErr:    ErrNotAuthorized,
+		},
+		{
+			name:      "upstream-deleted",
+			fork:      fork,
+			pushRepos: []string{upstream.Path},
+			prs:       []*types.PullReq{prAllowingEdits},
+			branch:    "feature",
+			expErr:    ErrNotAuthorized,
+		},
+		{
+			name:   "no-push-access-to-upstream",
+			fork:   fork,
+			repos:  []*types.Repository{upstream},
+			prs:    []*types.PullReq{prAllowingEdits},
+			branch: "feature",
+			expErr: ErrNotAuthorized,
+		},
+		{
+			name:      "no-pull-request",
+			fork:      fork,
+			repos:     []*types.Repository{upstream},
+			pushRepos: []string{upstream.Path},
+			branch:    "feature",
+			expErr:    ErrNotAuthorized,
+		},
+		{
+			name:      "other-branch",
+			fork:      fork,
+			repos:     []*types.Repository{upstream},
+			pushRepos: []string{upstream.Path},
+			prs:       []*types.PullReq{prAllowingEdits},
+			branch:    "main",
+			expErr:    ErrNotAuthorized,
+		},
+		{
+			name:      "maintainer-edits-not-allowed",
+			fork:      fork,
+			repos:     []*types.Repository{upstream},
+			pushRepos: []string{upstream.Path},
+			prs: []*types.PullReq{{
+				SourceRepoID: fork.ID,
+				SourceBranch: "feature",
+				TargetRepoID: upstream.ID,
+				State:        enum.PullReqStateOpen,
+			}},
+			branch: "feature",
+			expErr: ErrNotAuthorized,
+		},
+		{
+			name:      "pull-request-closed",
+			fork:      fork,
+			repos:     []*types.Repository{upstream},
+			pushRepos: []string{upstream.Path},
+			prs: []*types.PullReq{{
+				SourceRepoID:        fork.ID,
+				SourceBranch:        "feature",
+				TargetRepoID:        upstream.ID,
+				State:               enum.PullReqStateClosed,
+				AllowMaintainerEdit: true,
+			}},
+			branch: "feature",
+			expErr: ErrNotAuthorized,
+		},
+		{
+			name:      "maintainer-edits-allowed",
+			fork:      fork,
+			repos:     []*types.Repository{upstream},
+			pushRepos: []string{upstream.Path},
+			prs:       []*types.PullReq{prAllowingEdits},
+			branch:    "feature",
+		},
+		{
+			name:      "any-branch",
+			fork:      fork,
+			repos:     []*types.Repository{upstream},
+			pushRepos: []string{upstream.Path},
+			prs:       []*types.PullReq{prAllowingEdits},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := CheckRepoMaintainerEdit(context.Background(),
+				fakeRepoAuthorizer{pushRepos: test.pushRepos},
+				fakeRepoStore{repos: test.repos},
+				fakePullReqStore{prs: test.prs},
+				&auth.Session{Principal: types.Principal{ID: 1}},
+				test.fork,
+				test.branch,
+			)
+			if !errors.Is(err, test.expErr) {
+				t.Errorf("expected error %v, got %v", test.expErr, err)
+			}
+		})
+	}
+}
+
+// fakeRepoAuthorizer grants the push permission for the repositories with the provided paths.
+type fakeRepoAuthorizer struct {
+	pushRepos []string
+}
+
+func (f fakeRepoAuthorizer) Check(
+	_ context.Context,
+	_ *auth.Session,
+	scope *types.Scope,
+	resource *types.Resource,
+	permission enum.Permission,
+) (bool, error) {
+	if permission != enum.PermissionRepoPush || resource.Type != enum.ResourceTypeRepo {
+		return false, nil
+	}
+	for _, path := range f.pushRepos {
+		if path == scope.SpacePath+"/"+resource.Identifier {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f fakeRepoAuthorizer) CheckAll(context.Context, *auth.Session, ...types.PermissionCheck) (bool, error) {
+	return false, nil
+}
+
+type fakeRepoStore struct {
+	store.RepoStore
+	repos []*types.Repository
+}
+
+func (f fakeRepoStore) Find(_ context.Context, id int64) (*types.Repository, error) {
+	for _, repo := range f.repos {
+		if repo.ID == id {
+			return repo, nil
+		}
+	}
+	return nil, gitness_store.ErrResourceNotFound
+}
+
+type fakePullReqStore struct {
+	store.PullReqStore
+	prs []*types.PullReq
+}
+
+func (f fakePullReqStore) List(_ context.Context, filter *types.PullReqFilter) ([]*types.PullReq, error) {
+	var prs []*types.PullReq
+	for _, pr := range f.prs {
+		if pr.SourceRepoID != filter.SourceRepoID || pr.TargetRepoID != filter.TargetRepoID ||
+			(filter.SourceBranch != "" && pr.SourceBranch != filter.SourceBranch) {
+			continue
+		}
+		for _, state := range filter.States {
+			if pr.State == state {
+				prs = append(prs, pr)
+				break
+			}
+		}
+	}
+	return prs, nil
+}
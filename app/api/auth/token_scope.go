@@ -0,0 +1,118 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/auth/authz"
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/check"
+	"github.com/harness/gitness/types/enum"
+)
+
+// ResolveTokenScope validates the requested scope of a new token and resolves its spaces and repositories.
+// The principal creating the token needs to have view access to all spaces and repositories of the scope.
+// Returns nil if the token isn't scoped.
+func ResolveTokenScope(
+	ctx context.Context,
+	authorizer authz.Authorizer,
+	session *auth.Session,
+	spaceStore store.SpaceStore,
+	repoFinder refcache.RepoFinder,
+	in *types.TokenScopeInput,
+) (*types.TokenScope, error) {
+	// scoped sessions can't be used to create new tokens, otherwise the scope could be escaped.
+	if session.Metadata != nil && session.Metadata.ImpactsAuthorization() {
+		return nil, ErrNotAuthorized
+	}
+
+	if err := check.TokenScope(in); err != nil {
+		return nil, err
+	}
+
+	if in == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	scope := &types.TokenScope{
+		Permissions: deduplicatePermissions(in.Permissions),
+	}
+
+	spaceIDs := make(map[int64]struct{}, len(in.Spaces))
+	for _, spaceRef := range in.Spaces {
+		space, err := spaceStore.FindByRef(ctx, spaceRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find space %q: %w", spaceRef, err)
+		}
+
+		if err = CheckSpace(ctx, authorizer, session, space, enum.PermissionSpaceView); err != nil {
+			return nil, err
+		}
+
+		if _, ok := spaceIDs[space.ID]; ok {
+			continue
+		}
+
+		spaceIDs[space.ID] = struct{}{}
+		scope.SpaceIDs = append(scope.SpaceIDs, space.ID)
+		scope.Spaces = append(scope.Spaces, space.Path)
+	}
+
+	repoIDs := make(map[int64]struct{}, len(in.Repos))
+	for _, repoRef := range in.Repos {
+		repo, err := repoFinder.FindByRef(ctx, repoRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find repository %q: %w", repoRef, err)
+		}
+
+		if err = CheckRepo(ctx, authorizer, session, repo, enum.PermissionRepoView); err != nil {
+			return nil, err
+		}
+
+		if _, ok := repoIDs[repo.ID]; ok {
+			continue
+		}
+
+		repoIDs[repo.ID] = struct{}{}
+		scope.RepoIDs = append(scope.RepoIDs, repo.ID)
+		scope.Repos = append(scope.Repos, repo.Path)
+	}
+
+	return scope, nil
+}
+
+func deduplicatePermissions(permissions []enum.Permission) []enum.Permission {
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	seen := make(map[enum.Permission]struct{}, len(permissions))
+	result := make([]enum.Permission, 0, len(permissions))
+	for _, p := range permissions {
+		if _, ok := seen[p]; ok {
+			continue
+		}
+
+		seen[p] = struct{}{}
+		result = append(result, p)
+	}
+
+	return result
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/audit"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types/enum"
+)
+
+// ListSpace lists the audit events of a space and all of its descendants.
+func (c *Controller) ListSpace(
+	ctx context.Context,
+	session *auth.Session,
+	spaceRef string,
+	filter *audit.EventFilter,
+) ([]*audit.Event, int64, error) {
+	space, err := c.spaceCache.Get(ctx, spaceRef)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find space: %w", err)
+	}
+
+	if err = apiauth.CheckSpace(ctx, c.authorizer, session, space, enum.PermissionSpaceEdit); err != nil {
+		return nil, 0, fmt.Errorf("access check failed: %w", err)
+	}
+
+	// the descendants include the space itself.
+	filter.SpaceIDs, err = c.spaceStore.GetDescendantsIDs(ctx, space.ID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get descendant spaces: %w", err)
+	}
+
+	return c.list(ctx, filter)
+}
+
+// List lists the audit events of the whole system. Available to system admins only.
+func (c *Controller) List(
+	ctx context.Context,
+	session *auth.Session,
+	filter *audit.EventFilter,
+) ([]*audit.Event, int64, error) {
+	if session == nil || !session.Principal.Admin {
+		return nil, 0, apiauth.ErrNotAuthorized
+	}
+
+	filter.SpaceIDs = nil
+
+	return c.list(ctx, filter)
+}
+
+func (c *Controller) list(
+	ctx context.Context,
+	filter *audit.EventFilter,
+) ([]*audit.Event, int64, error) {
+	var count int64
+	var events []*audit.Event
+
+	err := c.tx.WithTx(ctx, func(ctx context.Context) (err error) {
+		count, err = c.auditStore.Count(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to count audit events: %w", err)
+		}
+
+		events, err = c.auditStore.List(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to list audit events: %w", err)
+		}
+
+		return nil
+	}, dbtx.TxDefaultReadOnly)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, count, nil
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/harness/gitness/app/api/usererror"
 	"github.com/harness/gitness/app/auth"
+	checkevents "github.com/harness/gitness/app/events/check"
 	"github.com/harness/gitness/git"
 	"github.com/harness/gitness/store"
 	"github.com/harness/gitness/types"
@@ -184,6 +185,13 @@ func (c *Controller) Report(
 
 	c.sseStreamer.Publish(ctx, repo.ParentID, enum.SSETypeStatusCheckReportUpdated, statusCheckReport)
 
+	c.eventReporter.Reported(ctx, &checkevents.ReportedPayload{
+		RepoID:     repo.ID,
+		CommitSHA:  commitSHA,
+		Identifier: in.Identifier,
+		Status:     in.Status,
+	})
+
 	return statusCheckReport, nil
 }
 
@@ -18,6 +18,8 @@ import (
 	"github.com/harness/gitness/app/auth/authz"
 	"github.com/harness/gitness/app/pipeline/canceler"
 	"github.com/harness/gitness/app/pipeline/commit"
+	"github.com/harness/gitness/app/pipeline/manager"
+	"github.com/harness/gitness/app/pipeline/scheduler"
 	"github.com/harness/gitness/app/pipeline/triggerer"
 	"github.com/harness/gitness/app/services/refcache"
 	"github.com/harness/gitness/app/store"
@@ -35,6 +37,9 @@ type Controller struct {
 	stageStore     store.StageStore
 	pipelineStore  store.PipelineStore
 	repoFinder     refcache.RepoFinder
+	scheduler      scheduler.Scheduler
+	// executionManager completes the execution when a stage gets declined.
+	executionManager manager.ExecutionManager
 }
 
 func NewController(
@@ -48,6 +53,8 @@ func NewController(
 	stageStore store.StageStore,
 	pipelineStore store.PipelineStore,
 	repoFinder refcache.RepoFinder,
+	scheduler scheduler.Scheduler,
+	executionManager manager.ExecutionManager,
 ) *Controller {
 	return &Controller{
 		tx:             tx,
@@ -60,5 +67,8 @@ func NewController(
 		stageStore:     stageStore,
 		pipelineStore:  pipelineStore,
 		repoFinder:     repoFinder,
+		scheduler:      scheduler,
+
+		executionManager: executionManager,
 	}
 }
@@ -0,0 +1,145 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/pipeline/triggerer"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+// Rerun creates a new execution with the same commit, ref, params and trigger information as the
+// parent execution.
+func (c *Controller) Rerun(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pipelineIdentifier string,
+	executionNum int64,
+) (*types.Execution, error) {
+	pipeline, parent, err := c.findRerunParent(ctx, session, repoRef, pipelineIdentifier, executionNum)
+	if err != nil {
+		return nil, err
+	}
+
+	execution, err := c.triggerer.Trigger(ctx, pipeline, rerunHook(session, parent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to trigger execution: %w", err)
+	}
+	if execution == nil {
+		return nil, usererror.BadRequest("No pipeline stages match the execution")
+	}
+
+	return execution, nil
+}
+
+// RerunFailed creates a new execution like Rerun, but reuses the results of the successful stages
+// of the parent execution and only runs its failed stages and the stages depending on them.
+func (c *Controller) RerunFailed(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pipelineIdentifier string,
+	executionNum int64,
+) (*types.Execution, error) {
+	pipeline, parent, err := c.findRerunParent(ctx, session, repoRef, pipelineIdentifier, executionNum)
+	if err != nil {
+		return nil, err
+	}
+
+	if !parent.Status.IsDone() {
+		return nil, usererror.BadRequest("Execution is still in progress")
+	}
+
+	stages, err := c.stageStore.ListWithSteps(ctx, parent.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stages of execution %d: %w", executionNum, err)
+	}
+
+	execution, err := c.triggerer.RerunFailed(ctx, pipeline, rerunHook(session, parent), stages)
+	if errors.Is(err, triggerer.ErrNoFailedStages) {
+		return nil, usererror.BadRequest("Execution has no failed stages")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to rerun failed stages: %w", err)
+	}
+
+	return execution, nil
+}
+
+func (c *Controller) findRerunParent(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pipelineIdentifier string,
+	executionNum int64,
+) (*types.Pipeline, *types.Execution, error) {
+	repo, err := c.repoFinder.FindByRef(ctx, repoRef)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find repo by ref: %w", err)
+	}
+
+	err = apiauth.CheckPipeline(ctx, c.authorizer, session, repo.Path, pipelineIdentifier, enum.PermissionPipelineExecute)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to authorize: %w", err)
+	}
+
+	pipeline, err := c.pipelineStore.FindByIdentifier(ctx, repo.ID, pipelineIdentifier)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find pipeline: %w", err)
+	}
+
+	execution, err := c.executionStore.FindByNumber(ctx, pipeline.ID, executionNum)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find execution %d: %w", executionNum, err)
+	}
+
+	return pipeline, execution, nil
+}
+
+// rerunHook returns the hook of a rerun of the parent execution, which is linked back to it.
+func rerunHook(session *auth.Session, parent *types.Execution) *triggerer.Hook {
+	return &triggerer.Hook{
+		Parent:       parent.Number,
+		Trigger:      parent.Trigger,
+		TriggeredBy:  session.Principal.ID,
+		Action:       parent.Action,
+		Link:         parent.Link,
+		Timestamp:    parent.Timestamp,
+		Title:        parent.Title,
+		Message:      parent.Message,
+		Before:       parent.Before,
+		After:        parent.After,
+		Ref:          parent.Ref,
+		Fork:         parent.Fork,
+		Source:       parent.Source,
+		Target:       parent.Target,
+		AuthorLogin:  parent.Author,
+		AuthorName:   parent.AuthorName,
+		AuthorEmail:  parent.AuthorEmail,
+		AuthorAvatar: parent.AuthorAvatar,
+		Debug:        parent.Debug,
+		Cron:         parent.Cron,
+		Sender:       session.Principal.UID,
+		Params:       parent.Params,
+	}
+}
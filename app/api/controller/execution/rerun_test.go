@@ -0,0 +1,121 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package execution
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/pipeline/triggerer"
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+type fakeRerunStageStore struct {
+	fakeStageStore
+	stages []*types.Stage
+}
+
+func (s *fakeRerunStageStore) ListWithSteps(context.Context, int64) ([]*types.Stage, error) {
+	return s.stages, nil
+}
+
+type fakeTriggerer struct {
+	triggerer.Triggerer
+	hook         *triggerer.Hook
+	parentStages []*types.Stage
+	err          error
+}
+
+func (t *fakeTriggerer) RerunFailed(
+	_ context.Context,
+	_ *types.Pipeline,
+	hook *triggerer.Hook,
+	parentStages []*types.Stage,
+) (*types.Execution, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	t.hook = hook
+	t.parentStages = parentStages
+	return &types.Execution{Number: 2, Parent: hook.Parent}, nil
+}
+
+func TestRerunFailed(t *testing.T) {
+	session := &auth.Session{Principal: types.Principal{ID: 7, UID: "user", Type: enum.PrincipalTypeUser}}
+	parentStages := []*types.Stage{
+		{Name: "build", Status: enum.CIStatusSuccess},
+		{Name: "test", Status: enum.CIStatusFailure},
+	}
+
+	tests := []struct {
+		name         string
+		parentStatus enum.CIStatus
+		triggerErr   error
+		wantStatus   int
+	}{
+		{
+			name:         "failed-execution",
+			parentStatus: enum.CIStatusFailure,
+		},
+		{
+			name:         "execution-in-progress",
+			parentStatus: enum.CIStatusRunning,
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:         "execution-blocked",
+			parentStatus: enum.CIStatusBlocked,
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:         "no-failed-stages",
+			parentStatus: enum.CIStatusSuccess,
+			triggerErr:   triggerer.ErrNoFailedStages,
+			wantStatus:   http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trigger := &fakeTriggerer{err: test.triggerErr}
+			ctrl := NewController(nil, fakeAuthorizer{}, fakeExecutionStore{status: test.parentStatus}, nil, nil, nil,
+				trigger, &fakeRerunStageStore{stages: parentStages}, fakePipelineStore{},
+				refcache.NewRepoFinder(fakeRepoStore{}, nil), nil, nil)
+
+			execution, err := ctrl.RerunFailed(context.Background(), session, "1", "pipeline", 1)
+			if test.wantStatus != 0 {
+				requireErrorStatus(t, err, test.wantStatus)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if execution.Parent != 1 || trigger.hook.Parent != 1 {
+				t.Errorf("want the rerun linked to execution 1, got %d", trigger.hook.Parent)
+			}
+			if trigger.hook.TriggeredBy != session.Principal.ID {
+				t.Errorf("want the rerun triggered by %d, got %d", session.Principal.ID, trigger.hook.TriggeredBy)
+			}
+			if len(trigger.parentStages) != len(parentStages) {
+				t.Errorf("want %d parent stages, got %d", len(parentStages), len(trigger.parentStages))
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+// Approve approves a stage that's blocked on a manual approval and schedules it for execution.
+func (c *Controller) Approve(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pipelineIdentifier string,
+	executionNum int64,
+	stageNum int64,
+) (*types.Stage, error) {
+	stage, err := c.findBlockedStage(ctx, session, repoRef, pipelineIdentifier, executionNum, stageNum)
+	if err != nil {
+		return nil, err
+	}
+
+	stage.Status = enum.CIStatusPending
+	stage.ApprovalBy = &session.Principal.ID
+	stage.ApprovalDecided = time.Now().UnixMilli()
+
+	err = c.stageStore.Update(ctx, stage)
+	if errors.Is(err, store.ErrVersionConflict) {
+		return nil, errStageDecidedConcurrently(stageNum)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update stage: %w", err)
+	}
+
+	err = c.scheduler.Schedule(ctx, stage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule stage: %w", err)
+	}
+
+	return stage, nil
+}
+
+// Decline declines a stage that's blocked on a manual approval, which fails it and its downstream stages.
+func (c *Controller) Decline(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pipelineIdentifier string,
+	executionNum int64,
+	stageNum int64,
+) (*types.Stage, error) {
+	stage, err := c.findBlockedStage(ctx, session, repoRef, pipelineIdentifier, executionNum, stageNum)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	stage.Status = enum.CIStatusDeclined
+	stage.Started = now
+	stage.Stopped = now
+	stage.ApprovalBy = &session.Principal.ID
+	stage.ApprovalDecided = now
+
+	// the teardown persists the stage, skips the downstream stages and completes the execution.
+	err = c.executionManager.AfterStage(ctx, stage)
+	if errors.Is(err, store.ErrVersionConflict) {
+		return nil, errStageDecidedConcurrently(stageNum)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete declined stage: %w", err)
+	}
+
+	return stage, nil
+}
+
+// errStageDecidedConcurrently is returned if the stage was approved or declined by someone else
+// since it was read, so the first decision wins.
+func errStageDecidedConcurrently(stageNum int64) error {
+	return usererror.Conflict(fmt.Sprintf("Stage %d was approved or declined concurrently", stageNum))
+}
+
+func (c *Controller) findBlockedStage(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pipelineIdentifier string,
+	executionNum int64,
+	stageNum int64,
+) (*types.Stage, error) {
+	repo, err := c.repoFinder.FindByRef(ctx, repoRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find repo by ref: %w", err)
+	}
+
+	err = apiauth.CheckPipeline(ctx, c.authorizer, session, repo.Path, pipelineIdentifier, enum.PermissionPipelineExecute)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authorize: %w", err)
+	}
+
+	pipeline, err := c.pipelineStore.FindByIdentifier(ctx, repo.ID, pipelineIdentifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pipeline: %w", err)
+	}
+
+	execution, err := c.executionStore.FindByNumber(ctx, pipeline.ID, executionNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find execution %d: %w", executionNum, err)
+	}
+
+	stage, err := c.stageStore.FindByNumber(ctx, execution.ID, int(stageNum))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stage %d: %w", stageNum, err)
+	}
+
+	if stage.Status != enum.CIStatusBlocked {
+		return nil, usererror.BadRequestf("Stage %d isn't waiting for an approval", stageNum)
+	}
+
+	return stage, nil
+}
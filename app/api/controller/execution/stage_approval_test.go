@@ -0,0 +1,291 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package execution
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/pipeline/manager"
+	"github.com/harness/gitness/app/pipeline/scheduler"
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+type fakeAuthorizer struct {
+	denied bool
+}
+
+func (a fakeAuthorizer) Check(
+	context.Context, *auth.Session, *types.Scope, *types.Resource, enum.Permission,
+) (bool, error) {
+	return !a.denied, nil
+}
+
+func (a fakeAuthorizer) CheckAll(context.Context, *auth.Session, ...types.PermissionCheck) (bool, error) {
+	return !a.denied, nil
+}
+
+type fakeRepoStore struct {
+	store.RepoStore
+}
+
+func (fakeRepoStore) Find(_ context.Context, id int64) (*types.Repository, error) {
+	return &types.Repository{ID: id, ParentID: 1, Identifier: "repo", Path: "space/repo"}, nil
+}
+
+type fakePipelineStore struct {
+	store.PipelineStore
+}
+
+func (fakePipelineStore) FindByIdentifier(_ context.Context, repoID int64, identifier string) (*types.Pipeline, error) {
+	return &types.Pipeline{ID: 1, RepoID: repoID, Identifier: identifier}, nil
+}
+
+type fakeExecutionStore struct {
+	store.ExecutionStore
+	status enum.CIStatus
+}
+
+func (s fakeExecutionStore) FindByNumber(_ context.Context, pipelineID int64, num int64) (*types.Execution, error) {
+	return &types.Execution{ID: 1, PipelineID: pipelineID, Number: num, Status: s.status}, nil
+}
+
+// fakeStageStore keeps a single stage and checks its version like the database store.
+type fakeStageStore struct {
+	store.StageStore
+	stage types.Stage
+	// updateFn is called before an update, e.g. to simulate a concurrent update.
+	updateFn func()
+}
+
+func (s *fakeStageStore) FindByNumber(_ context.Context, _ int64, stageNum int) (*types.Stage, error) {
+	if int64(stageNum) != s.stage.Number {
+		return nil, gitness_store.ErrResourceNotFound
+	}
+	stage := s.stage
+	return &stage, nil
+}
+
+func (s *fakeStageStore) Update(_ context.Context, stage *types.Stage) error {
+	if s.updateFn != nil {
+		s.updateFn()
+	}
+	if stage.Version != s.stage.Version {
+		return gitness_store.ErrVersionConflict
+	}
+	stage.Version++
+	s.stage = *stage
+	return nil
+}
+
+type fakeScheduler struct {
+	scheduler.Scheduler
+	scheduled []*types.Stage
+}
+
+func (s *fakeScheduler) Schedule(_ context.Context, stage *types.Stage) error {
+	s.scheduled = append(s.scheduled, stage)
+	return nil
+}
+
+// fakeExecutionManager persists the stage like the teardown of the execution manager.
+type fakeExecutionManager struct {
+	manager.ExecutionManager
+	stageStore *fakeStageStore
+	completed  []*types.Stage
+}
+
+func (m *fakeExecutionManager) AfterStage(ctx context.Context, stage *types.Stage) error {
+	if err := m.stageStore.Update(ctx, stage); err != nil {
+		return err
+	}
+	m.completed = append(m.completed, stage)
+	return nil
+}
+
+func setupStageApprovalTest(denied bool) (*Controller, *fakeStageStore, *fakeScheduler, *fakeExecutionManager) {
+	stageStore := &fakeStageStore{stage: types.Stage{
+		ID:               1,
+		ExecutionID:      1,
+		Number:           2,
+		Name:             "deploy",
+		Status:           enum.CIStatusBlocked,
+		ApprovalRequired: true,
+		Version:          1,
+	}}
+	sched := &fakeScheduler{}
+	executionManager := &fakeExecutionManager{stageStore: stageStore}
+
+	ctrl := NewController(nil, fakeAuthorizer{denied: denied}, fakeExecutionStore{}, nil, nil, nil, nil,
+		stageStore, fakePipelineStore{}, refcache.NewRepoFinder(fakeRepoStore{}, nil), sched, executionManager)
+
+	return ctrl, stageStore, sched, executionManager
+}
+
+var testApprovalSession = &auth.Session{
+	Principal: types.Principal{ID: 7, UID: "approver", Type: enum.PrincipalTypeUser},
+}
+
+func requireErrorStatus(t *testing.T, err error, status int) {
+	t.Helper()
+
+	uErr := &usererror.Error{}
+	if !errors.As(err, &uErr) || uErr.Status != status {
+		t.Fatalf("want error with status %d, got %v", status, err)
+	}
+}
+
+func TestApprove(t *testing.T) {
+	ctx := context.Background()
+	ctrl, stageStore, sched, _ := setupStageApprovalTest(false)
+
+	stage, err := ctrl.Approve(ctx, testApprovalSession, "1", "pipeline", 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if stage.Status != enum.CIStatusPending || stageStore.stage.Status != enum.CIStatusPending {
+		t.Errorf("want stage status %s, got %s", enum.CIStatusPending, stageStore.stage.Status)
+	}
+	if stageStore.stage.ApprovalBy == nil || *stageStore.stage.ApprovalBy != testApprovalSession.Principal.ID {
+		t.Errorf("want stage approved by %d, got %v", testApprovalSession.Principal.ID, stageStore.stage.ApprovalBy)
+	}
+	if stageStore.stage.ApprovalDecided == 0 {
+		t.Errorf("expected the approval time to be set")
+	}
+	if len(sched.scheduled) != 1 {
+		t.Errorf("want the stage scheduled once, got %d", len(sched.scheduled))
+	}
+
+	// the stage isn't blocked anymore.
+	_, err = ctrl.Approve(ctx, testApprovalSession, "1", "pipeline", 1, 2)
+	requireErrorStatus(t, err, http.StatusBadRequest)
+
+	_, err = ctrl.Decline(ctx, testApprovalSession, "1", "pipeline", 1, 2)
+	requireErrorStatus(t, err, http.StatusBadRequest)
+
+	if len(sched.scheduled) != 1 {
+		t.Errorf("want the stage scheduled once, got %d", len(sched.scheduled))
+	}
+}
+
+func TestDecline(t *testing.T) {
+	ctx := context.Background()
+	ctrl, stageStore, sched, executionManager := setupStageApprovalTest(false)
+
+	stage, err := ctrl.Decline(ctx, testApprovalSession, "1", "pipeline", 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if stage.Status != enum.CIStatusDeclined || stageStore.stage.Status != enum.CIStatusDeclined {
+		t.Errorf("want stage status %s, got %s", enum.CIStatusDeclined, stageStore.stage.Status)
+	}
+	if stageStore.stage.Started == 0 || stageStore.stage.Stopped == 0 {
+		t.Errorf("expected the declined stage to be started and stopped")
+	}
+	if len(executionManager.completed) != 1 {
+		t.Errorf("want the stage completed once, got %d", len(executionManager.completed))
+	}
+	if len(sched.scheduled) != 0 {
+		t.Errorf("expected the declined stage not to be scheduled")
+	}
+
+	_, err = ctrl.Approve(ctx, testApprovalSession, "1", "pipeline", 1, 2)
+	requireErrorStatus(t, err, http.StatusBadRequest)
+}
+
+func TestStageApproval_NotAuthorized(t *testing.T) {
+	ctx := context.Background()
+	ctrl, stageStore, _, _ := setupStageApprovalTest(true)
+
+	if _, err := ctrl.Approve(ctx, testApprovalSession, "1", "pipeline", 1, 2); !errors.Is(err,
+		apiauth.ErrNotAuthorized) {
+		t.Fatalf("want error %v, got %v", apiauth.ErrNotAuthorized, err)
+	}
+
+	if _, err := ctrl.Decline(ctx, testApprovalSession, "1", "pipeline", 1, 2); !errors.Is(err,
+		apiauth.ErrNotAuthorized) {
+		t.Fatalf("want error %v, got %v", apiauth.ErrNotAuthorized, err)
+	}
+
+	if stageStore.stage.Status != enum.CIStatusBlocked {
+		t.Errorf("want stage status %s, got %s", enum.CIStatusBlocked, stageStore.stage.Status)
+	}
+}
+
+func TestStageApproval_NotBlocked(t *testing.T) {
+	ctx := context.Background()
+	ctrl, stageStore, _, _ := setupStageApprovalTest(false)
+	stageStore.stage.Status = enum.CIStatusRunning
+
+	_, err := ctrl.Approve(ctx, testApprovalSession, "1", "pipeline", 1, 2)
+	requireErrorStatus(t, err, http.StatusBadRequest)
+
+	_, err = ctrl.Decline(ctx, testApprovalSession, "1", "pipeline", 1, 2)
+	requireErrorStatus(t, err, http.StatusBadRequest)
+}
+
+func TestStageApproval_Concurrent(t *testing.T) {
+	tests := []struct {
+		name   string
+		decide func(ctrl *Controller) (*types.Stage, error)
+	}{
+		{
+			name: "approve",
+			decide: func(ctrl *Controller) (*types.Stage, error) {
+				return ctrl.Approve(context.Background(), testApprovalSession, "1", "pipeline", 1, 2)
+			},
+		},
+		{
+			name: "decline",
+			decide: func(ctrl *Controller) (*types.Stage, error) {
+				return ctrl.Decline(context.Background(), testApprovalSession, "1", "pipeline", 1, 2)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl, stageStore, sched, executionManager := setupStageApprovalTest(false)
+
+			// someone else decides on the stage after it was read.
+			stageStore.updateFn = func() {
+				stageStore.updateFn = nil
+				stageStore.stage.Status = enum.CIStatusDeclined
+				stageStore.stage.Version++
+			}
+
+			_, err := test.decide(ctrl)
+			requireErrorStatus(t, err, http.StatusConflict)
+
+			if stageStore.stage.Status != enum.CIStatusDeclined {
+				t.Errorf("want the first decision %s kept, got %s", enum.CIStatusDeclined, stageStore.stage.Status)
+			}
+			if len(sched.scheduled) != 0 || len(executionManager.completed) != 0 {
+				t.Errorf("expected the stage not to be scheduled or completed again")
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"github.com/harness/gitness/app/auth/authz"
 	"github.com/harness/gitness/app/pipeline/canceler"
 	"github.com/harness/gitness/app/pipeline/commit"
+	"github.com/harness/gitness/app/pipeline/manager"
+	"github.com/harness/gitness/app/pipeline/scheduler"
 	"github.com/harness/gitness/app/pipeline/triggerer"
 	"github.com/harness/gitness/app/services/refcache"
 	"github.com/harness/gitness/app/store"
@@ -42,7 +44,10 @@ func ProvideController(
 	stageStore store.StageStore,
 	pipelineStore store.PipelineStore,
 	repoFinder refcache.RepoFinder,
+	scheduler scheduler.Scheduler,
+	executionManager manager.ExecutionManager,
 ) *Controller {
 	return NewController(tx, authorizer, executionStore, checkStore,
-		canceler, commitService, triggerer, stageStore, pipelineStore, repoFinder)
+		canceler, commitService, triggerer, stageStore, pipelineStore, repoFinder,
+		scheduler, executionManager)
 }
@@ -24,7 +24,9 @@ import (
 	"github.com/harness/gitness/app/auth/authz"
 	eventsgit "github.com/harness/gitness/app/events/git"
 	eventsrepo "github.com/harness/gitness/app/events/repo"
+	"github.com/harness/gitness/app/services/mirror"
 	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/app/services/publickey"
 	"github.com/harness/gitness/app/services/settings"
 	"github.com/harness/gitness/app/sse"
 	"github.com/harness/gitness/app/store"
@@ -54,6 +56,8 @@ type Controller struct {
 	updateExtender      UpdateExtender
 	postReceiveExtender PostReceiveExtender
 	sseStreamer         sse.Streamer
+	publicKeySvc        publickey.Service
+	mirrorSvc           *mirror.Service
 }
 
 func NewController(
@@ -72,6 +76,8 @@ func NewController(
 	updateExtender UpdateExtender,
 	postReceiveExtender PostReceiveExtender,
 	sseStreamer sse.Streamer,
+	publicKeySvc publickey.Service,
+	mirrorSvc *mirror.Service,
 ) *Controller {
 	return &Controller{
 		authorizer:          authorizer,
@@ -89,6 +95,8 @@ func NewController(
 		updateExtender:      updateExtender,
 		postReceiveExtender: postReceiveExtender,
 		sseStreamer:         sseStreamer,
+		publicKeySvc:        publicKeySvc,
+		mirrorSvc:           mirrorSvc,
 	}
 }
 
@@ -36,4 +36,9 @@ type RestrictedGIT interface {
 		ctx context.Context,
 		params *git.FindOversizeFilesParams,
 	) (*git.FindOversizeFilesOutput, error)
+	ListCommitSignatures(
+		ctx context.Context,
+		params *git.ListCommitSignaturesParams,
+	) (*git.ListCommitSignaturesOutput, error)
+	ListCommitPaths(ctx context.Context, params *git.ListCommitPathsParams) (*git.ListCommitPathsOutput, error)
 }
@@ -66,6 +66,13 @@ func (c *Controller) PostReceive(
 		c.reportReferenceEvents(ctx, rgit, repo, in.PrincipalID, in.PostReceiveInput)
 	}
 
+	// schedule push mirrors if repo is in an active state (best effort)
+	if repo.State == enum.RepoStateActive {
+		if err := c.mirrorSvc.SyncPushMirrors(ctx, repo.ID); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("failed to schedule synchronization of push mirrors")
+		}
+	}
+
 	// handle branch updates related to PRs - best effort
 	c.handlePRMessaging(ctx, repo, in.PostReceiveInput, &out)
 
@@ -20,11 +20,16 @@ import (
 	"strings"
 
 	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/controller"
 	"github.com/harness/gitness/app/api/controller/limiter"
 	"github.com/harness/gitness/app/api/usererror"
 	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/services/mirror"
 	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git"
 	"github.com/harness/gitness/git/hook"
+	"github.com/harness/gitness/store"
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
 
@@ -52,6 +57,18 @@ func (c *Controller) PreReceive(
 		return output, nil
 	}
 
+	// Pull mirrors are read-only, only the mirror synchronization is allowed to update references.
+	if !mirror.IsSyncPrincipal(in.PrincipalID) {
+		readOnly, err := c.mirrorSvc.IsReadOnly(ctx, repo.ID)
+		if err != nil {
+			return hook.Output{}, fmt.Errorf("failed to check if repository is a pull mirror: %w", err)
+		}
+		if readOnly {
+			output.Error = ptr.String("Repository is a pull mirror and can't be modified")
+			return output, nil
+		}
+	}
+
 	if err := c.limiter.RepoSize(ctx, in.RepoID); err != nil {
 		return hook.Output{}, fmt.Errorf(
 			"resource limit exceeded: %w",
@@ -92,7 +109,15 @@ func (c *Controller) PreReceive(
 
 		dummySession := &auth.Session{Principal: *principal, Metadata: nil}
 
-		err = c.checkProtectionRules(ctx, dummySession, repo, refUpdates, &output)
+		err = c.checkMaintainerEditAccess(ctx, dummySession, repo, refUpdates, &output)
+		if output.Error != nil {
+			return output, nil
+		}
+		if err != nil {
+			return hook.Output{}, fmt.Errorf("failed to check maintainer edit access: %w", err)
+		}
+
+		err = c.checkProtectionRules(ctx, rgit, dummySession, repo, in, refUpdates, &output)
 		if output.Error != nil {
 			return output, nil
 		}
@@ -143,10 +168,55 @@ func (c *Controller) blockPullReqRefUpdate(refUpdates changedRefs, state enum.Re
 		slices.ContainsFunc(refUpdates.other.forced, fn)
 }
 
+// checkMaintainerEditAccess verifies that principals pushing to a fork as maintainers of its upstream
+// repository, rather than with push access to the fork, update only source branches of pull requests
+// that allow maintainer edits.
+func (c *Controller) checkMaintainerEditAccess(
+	ctx context.Context,
+	session *auth.Session,
+	repo *types.Repository,
+	refUpdates changedRefs,
+	output *hook.Output,
+) error {
+	err := apiauth.CheckRepo(ctx, c.authorizer, session, repo, enum.PermissionRepoPush)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, apiauth.ErrNotAuthorized) {
+		return fmt.Errorf("failed to check repo access: %w", err)
+	}
+
+	if len(refUpdates.branches.created) > 0 || len(refUpdates.branches.deleted) > 0 ||
+		!refUpdates.tags.isEmpty() || !refUpdates.other.isEmpty() {
+		output.Error = ptr.String("Maintainers of the upstream repository can only update pull request branches")
+		return nil
+	}
+
+	branches := make([]string, 0, len(refUpdates.branches.updated)+len(refUpdates.branches.forced))
+	branches = append(branches, refUpdates.branches.updated...)
+	branches = append(branches, refUpdates.branches.forced...)
+
+	for _, branch := range branches {
+		err = apiauth.CheckRepoMaintainerEdit(ctx, c.authorizer, c.repoStore, c.pullreqStore, session, repo, branch)
+		if errors.Is(err, apiauth.ErrNotAuthorized) {
+			output.Error = ptr.String(fmt.Sprintf(
+				"Branch %q isn't a source branch of a pull request that allows maintainer edits", branch))
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check maintainer edit access for branch %q: %w", branch, err)
+		}
+	}
+
+	return nil
+}
+
 func (c *Controller) checkProtectionRules(
 	ctx context.Context,
+	rgit RestrictedGIT,
 	session *auth.Session,
 	repo *types.Repository,
+	in types.GithookPreReceiveInput,
 	refUpdates changedRefs,
 	output *hook.Output,
 ) error {
@@ -163,20 +233,22 @@ func (c *Controller) checkProtectionRules(
 	var ruleViolations []types.RuleViolations
 	var errCheckAction error
 
-	//nolint:unparam
 	checkAction := func(refAction protection.RefAction, refType protection.RefType, names []string) {
 		if errCheckAction != nil || len(names) == 0 {
 			return
 		}
 
 		violations, err := protectionRules.RefChangeVerify(ctx, protection.RefChangeVerifyInput{
-			Actor:       &session.Principal,
-			AllowBypass: true,
-			IsRepoOwner: isRepoOwner,
-			Repo:        repo,
-			RefAction:   refAction,
-			RefType:     refType,
-			RefNames:    names,
+			ResolveUnverifiedCommits: c.unverifiedCommitsResolver(rgit, repo, in),
+			ResolvePushedCommits:     c.pushedCommitsResolver(rgit, repo, in, refNamePrefix(refType)),
+			IsVerifiedEmail:          c.verifiedEmailResolver(),
+			Actor:                    &session.Principal,
+			AllowBypass:              true,
+			IsRepoOwner:              isRepoOwner,
+			Repo:                     repo,
+			RefAction:                refAction,
+			RefType:                  refType,
+			RefNames:                 names,
 		})
 		if err != nil {
 			errCheckAction = fmt.Errorf("failed to verify protection rules for git push: %w", err)
@@ -191,6 +263,16 @@ func (c *Controller) checkProtectionRules(
 	checkAction(protection.RefActionUpdate, protection.RefTypeBranch, refUpdates.branches.updated)
 	checkAction(protection.RefActionUpdateForce, protection.RefTypeBranch, refUpdates.branches.forced)
 
+	checkAction(protection.RefActionCreate, protection.RefTypeTag, refUpdates.tags.created)
+	checkAction(protection.RefActionDelete, protection.RefTypeTag, refUpdates.tags.deleted)
+	checkAction(protection.RefActionUpdate, protection.RefTypeTag, refUpdates.tags.updated)
+	checkAction(protection.RefActionUpdateForce, protection.RefTypeTag, refUpdates.tags.forced)
+
+	checkAction(protection.RefActionCreate, protection.RefTypeRaw, refUpdates.other.created)
+	checkAction(protection.RefActionDelete, protection.RefTypeRaw, refUpdates.other.deleted)
+	checkAction(protection.RefActionUpdate, protection.RefTypeRaw, refUpdates.other.updated)
+	checkAction(protection.RefActionUpdateForce, protection.RefTypeRaw, refUpdates.other.forced)
+
 	if errCheckAction != nil {
 		return errCheckAction
 	}
@@ -217,6 +299,162 @@ func (c *Controller) checkProtectionRules(
 	return nil
 }
 
+// unverifiedCommitsResolver returns a function that lists SHAs of the commits
+// pushed to the provided branches that don't have a verified signature.
+func (c *Controller) unverifiedCommitsResolver(
+	rgit RestrictedGIT,
+	repo *types.Repository,
+	in types.GithookPreReceiveInput,
+) func(ctx context.Context, branchNames []string) ([]string, error) {
+	return func(ctx context.Context, branchNames []string) ([]string, error) {
+		commits, err := listPushedCommits(ctx, rgit, repo, in, gitReferenceNamePrefixBranch, branchNames)
+		if err != nil {
+			return nil, err
+		}
+
+		return controller.UnverifiedCommitSHAs(ctx, c.publicKeySvc, commits)
+	}
+}
+
+// pushedCommitsResolver returns a function that lists the commits pushed to the provided references.
+// The reference names are provided without the prefix.
+func (c *Controller) pushedCommitsResolver(
+	rgit RestrictedGIT,
+	repo *types.Repository,
+	in types.GithookPreReceiveInput,
+	refPrefix string,
+) func(ctx context.Context, refNames []string, withPaths bool) ([]protection.PushedCommit, error) {
+	return func(ctx context.Context, refNames []string, withPaths bool) ([]protection.PushedCommit, error) {
+		commits, err := listPushedCommits(ctx, rgit, repo, in, refPrefix, refNames)
+		if err != nil {
+			return nil, err
+		}
+
+		pushed := make([]protection.PushedCommit, len(commits))
+		for i := range commits {
+			pushed[i] = protection.PushedCommit{
+				SHA:            commits[i].SHA.String(),
+				Message:        commits[i].Message,
+				AuthorEmail:    commits[i].Author.Identity.Email,
+				CommitterEmail: commits[i].Committer.Identity.Email,
+			}
+		}
+
+		if !withPaths || len(pushed) == 0 {
+			return pushed, nil
+		}
+
+		commitSHAs := make([]string, len(pushed))
+		for i := range pushed {
+			commitSHAs[i] = pushed[i].SHA
+		}
+
+		output, err := rgit.ListCommitPaths(ctx, &git.ListCommitPathsParams{
+			ReadParams: git.ReadParams{
+				RepoUID:             repo.GitUID,
+				AlternateObjectDirs: in.Environment.AlternateObjectDirs,
+			},
+			CommitSHAs: commitSHAs,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list paths of pushed commits: %w", err)
+		}
+
+		paths := make(map[string][]string, len(output.Commits))
+		for _, commit := range output.Commits {
+			paths[commit.SHA.String()] = commit.Paths
+		}
+
+		for i := range pushed {
+			pushed[i].Paths = paths[pushed[i].SHA]
+		}
+
+		return pushed, nil
+	}
+}
+
+// verifiedEmailResolver returns a function that checks if an email address
+// is the verified email address of an active user.
+func (c *Controller) verifiedEmailResolver() func(ctx context.Context, email string) (bool, error) {
+	return func(ctx context.Context, email string) (bool, error) {
+		user, err := c.principalStore.FindUserByEmail(ctx, email)
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to find user by email: %w", err)
+		}
+
+		return user.EmailVerified && !user.Blocked, nil
+	}
+}
+
+// listPushedCommits lists the new commits pushed to the provided references, whose names are provided
+// without the prefix. Commits pushed to more than one of the references are listed only once.
+func listPushedCommits(
+	ctx context.Context,
+	rgit RestrictedGIT,
+	repo *types.Repository,
+	in types.GithookPreReceiveInput,
+	refPrefix string,
+	refNames []string,
+) ([]git.Commit, error) {
+	var commits []git.Commit
+	seen := map[string]struct{}{}
+	for _, refUpdate := range in.RefUpdates {
+		refName, ok := strings.CutPrefix(refUpdate.Ref, refPrefix)
+		if !ok || refUpdate.New.IsNil() || !slices.Contains(refNames, refName) {
+			continue
+		}
+
+		// Only the commits that aren't reachable from any existing reference are listed,
+		// so commits that are already in the repository (e.g. merged from another branch) aren't checked again.
+		var baseRev string
+		if !refUpdate.Old.IsNil() {
+			baseRev = refUpdate.Old.String()
+		}
+
+		output, err := rgit.ListCommitSignatures(ctx, &git.ListCommitSignaturesParams{
+			ReadParams: git.ReadParams{
+				RepoUID:             repo.GitUID,
+				AlternateObjectDirs: in.Environment.AlternateObjectDirs,
+			},
+			Rev:               refUpdate.New.String(),
+			BaseRev:           baseRev,
+			ExcludeReferenced: true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list commits of %q: %w", refUpdate.Ref, err)
+		}
+
+		for _, commit := range output.Commits {
+			if _, ok := seen[commit.SHA.String()]; ok {
+				continue
+			}
+
+			seen[commit.SHA.String()] = struct{}{}
+			commits = append(commits, commit)
+		}
+	}
+
+	return commits, nil
+}
+
+// refNamePrefix returns the prefix of the names of the references of the type.
+// Names of other references are full reference names.
+func refNamePrefix(refType protection.RefType) string {
+	switch refType {
+	case protection.RefTypeBranch:
+		return gitReferenceNamePrefixBranch
+	case protection.RefTypeTag:
+		return gitReferenceNamePrefixTag
+	case protection.RefTypeRaw:
+		return ""
+	}
+
+	return ""
+}
+
 type changes struct {
 	created []string
 	deleted []string
@@ -224,6 +462,10 @@ type changes struct {
 	forced  []string
 }
 
+func (c *changes) isEmpty() bool {
+	return len(c.created)+len(c.deleted)+len(c.updated)+len(c.forced) == 0
+}
+
 func (c *changes) groupByAction(
 	refUpdate hook.ReferenceUpdate,
 	name string,
@@ -255,7 +497,7 @@ func groupRefsByAction(refUpdates []hook.ReferenceUpdate, forced []bool) (c chan
 			c.branches.groupByAction(refUpdate, branchName, forced[i])
 		case strings.HasPrefix(refUpdate.Ref, gitReferenceNamePrefixTag):
 			tagName := refUpdate.Ref[len(gitReferenceNamePrefixTag):]
-			c.tags.groupByAction(refUpdate, tagName, false)
+			c.tags.groupByAction(refUpdate, tagName, forced[i])
 		default:
 			c.other.groupByAction(refUpdate, refUpdate.Ref, false)
 		}
@@ -0,0 +1,391 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githook
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/git/hook"
+	"github.com/harness/gitness/git/sha"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/gotidy/ptr"
+)
+
+func TestCheckMaintainerEditAccess(t *testing.T) {
+	upstream := &types.Repository{ID: 1, Path: "space/upstream"}
+	fork := &types.Repository{ID: 2, Path: "other/fork", ForkID: upstream.ID}
+
+	const (
+		errOnlyPullReqBranches = "Maintainers of the upstream repository can only update pull request branches"
+		errNotEditable         = `Branch "other" isn't a source branch of a pull request that allows maintainer edits`
+	)
+
+	tests := []struct {
+		name      string
+		pushRepos []string
+		refs      changedRefs
+		expErr    *string
+	}{
+		{
+			name:      "push-access-to-fork",
+			pushRepos: []string{fork.Path},
+			refs: changedRefs{
+				branches: changes{created: []string{"new"}, deleted: []string{"old"}},
+				tags:     changes{created: []string{"v1"}},
+			},
+		},
+		{
+			name:      "maintainer-updates-pullreq-branch",
+			pushRepos: []string{upstream.Path},
+			refs:      changedRefs{branches: changes{updated: []string{"feature"}}},
+		},
+		{
+			name:      "maintainer-force-pushes-pullreq-branch",
+			pushRepos: []string{upstream.Path},
+			refs:      changedRefs{branches: changes{forced: []string{"feature"}}},
+		},
+		{
+			name:      "maintainer-updates-other-branch",
+			pushRepos: []string{upstream.Path},
+			refs:      changedRefs{branches: changes{updated: []string{"feature", "other"}}},
+			expErr:    ptr.String(errNotEditable),
+		},
+		{
+			name:      "maintainer-creates-branch",
+			pushRepos: []string{upstream.Path},
+			refs:      changedRefs{branches: changes{created: []string{"new"}}},
+			expErr:    ptr.String(errOnlyPullReqBranches),
+		},
+		{
+			name:      "maintainer-deletes-branch",
+			pushRepos: []string{upstream.Path},
+			refs:      changedRefs{branches: changes{deleted: []string{"feature"}}},
+			expErr:    ptr.String(errOnlyPullReqBranches),
+		},
+		{
+			name:      "maintainer-updates-tag",
+			pushRepos: []string{upstream.Path},
+			refs:      changedRefs{tags: changes{forced: []string{"v1"}}},
+			expErr:    ptr.String(errOnlyPullReqBranches),
+		},
+		{
+			name:   "no-access",
+			refs:   changedRefs{branches: changes{updated: []string{"other"}}},
+			expErr: ptr.String(errNotEditable),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &Controller{
+				authorizer: fakePushAuthorizer{pushRepos: test.pushRepos},
+				repoStore:  fakeRepoStore{repos: []*types.Repository{upstream, fork}},
+				pullreqStore: fakePullReqStore{prs: []*types.PullReq{{
+					SourceRepoID:        fork.ID,
+					SourceBranch:        "feature",
+					TargetRepoID:        upstream.ID,
+					State:               enum.PullReqStateOpen,
+					AllowMaintainerEdit: true,
+				}}},
+			}
+
+			output := hook.Output{}
+			session := &auth.Session{Principal: types.Principal{ID: 1}}
+
+			err := c.checkMaintainerEditAccess(context.Background(), session, fork, test.refs, &output)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if ptr.ToString(output.Error) != ptr.ToString(test.expErr) {
+				t.Errorf("expected output error %q, got %q", ptr.ToString(test.expErr), ptr.ToString(output.Error))
+			}
+		})
+	}
+}
+
+func TestCheckProtectionRules_Tags(t *testing.T) {
+	repo := &types.Repository{ID: 1, Path: "space/repo", DefaultBranch: "main"}
+
+	ruleStore := fakeRuleStore{rules: []types.RuleInfoInternal{{
+		RuleInfo: types.RuleInfo{
+			ID:         1,
+			Identifier: "release-tags",
+			Type:       protection.TypeTag,
+			State:      enum.RuleStateActive,
+		},
+		Pattern:    []byte(`{"include":["v*"]}`),
+		Definition: []byte(`{"lifecycle":{"update_forbidden":true}}`),
+	}}}
+
+	protectionManager, err := protection.ProvideManager(ruleStore)
+	if err != nil {
+		t.Fatalf("failed to create protection manager: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		refs     changedRefs
+		expError bool
+	}{
+		{
+			name: "create-tag",
+			refs: changedRefs{tags: changes{created: []string{"v1"}}},
+		},
+		{
+			name:     "move-tag",
+			refs:     changedRefs{tags: changes{updated: []string{"v1"}}},
+			expError: true,
+		},
+		{
+			name:     "force-move-tag",
+			refs:     changedRefs{tags: changes{forced: []string{"v1"}}},
+			expError: true,
+		},
+		{
+			name: "force-move-unprotected-tag",
+			refs: changedRefs{tags: changes{forced: []string{"nightly"}}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &Controller{
+				authorizer:        fakePushAuthorizer{},
+				protectionManager: protectionManager,
+			}
+
+			output := hook.Output{}
+			session := &auth.Session{Principal: types.Principal{ID: 1}}
+
+			err := c.checkProtectionRules(context.Background(), nil, session, repo,
+				types.GithookPreReceiveInput{}, test.refs, &output)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if blocked := output.Error != nil; blocked != test.expError {
+				t.Errorf("expected blocked=%t, got output error %q", test.expError, ptr.ToString(output.Error))
+			}
+		})
+	}
+}
+
+func TestGroupRefsByAction_ForcedTag(t *testing.T) {
+	oldSHA := sha.Must("1111111111111111111111111111111111111111")
+	newSHA := sha.Must("2222222222222222222222222222222222222222")
+
+	refs := groupRefsByAction([]hook.ReferenceUpdate{
+		{Ref: gitReferenceNamePrefixTag + "v1", Old: oldSHA, New: newSHA},
+		{Ref: gitReferenceNamePrefixTag + "v2", Old: oldSHA, New: newSHA},
+	}, []bool{true, false})
+
+	if !slices.Equal(refs.tags.forced, []string{"v1"}) || !slices.Equal(refs.tags.updated, []string{"v2"}) {
+		t.Errorf("unexpected tag changes: forced=%v updated=%v", refs.tags.forced, refs.tags.updated)
+	}
+}
+
+func TestCheckProtectionRules_PushPolicy(t *testing.T) {
+	repo := &types.Repository{ID: 1, Path: "space/repo", DefaultBranch: "main"}
+
+	var (
+		oldSHA   = sha.Must("1111111111111111111111111111111111111111")
+		mainSHA  = sha.Must("2222222222222222222222222222222222222222")
+		mergeSHA = sha.Must("3333333333333333333333333333333333333333")
+		badSHA   = sha.Must("4444444444444444444444444444444444444444")
+	)
+
+	// The commit of main was pushed before the push policy was added and doesn't follow it.
+	rgit := fakeCommitsGIT{
+		commits: map[sha.SHA][]git.Commit{
+			mergeSHA: {
+				{SHA: mergeSHA, Message: "PROJ-1: merge main"},
+				{SHA: mainSHA, Message: "old style message"},
+			},
+			badSHA: {{SHA: badSHA, Message: "no ticket"}},
+		},
+		referenced: map[sha.SHA]bool{mainSHA: true},
+	}
+
+	ruleStore := fakeRuleStore{rules: []types.RuleInfoInternal{{
+		RuleInfo: types.RuleInfo{
+			ID:         1,
+			Identifier: "ticket-in-message",
+			Type:       protection.TypePushPolicy,
+			State:      enum.RuleStateActive,
+		},
+		Pattern:    []byte(`{"include":["feature","main"]}`),
+		Definition: []byte(`{"policy":{"commit_message_pattern":"^PROJ-\\d+: "}}`),
+	}}}
+
+	protectionManager, err := protection.ProvideManager(ruleStore)
+	if err != nil {
+		t.Fatalf("failed to create protection manager: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		refUpdate hook.ReferenceUpdate
+		expError  bool
+	}{
+		{
+			name:      "merge-main-into-branch",
+			refUpdate: hook.ReferenceUpdate{Ref: gitReferenceNamePrefixBranch + "feature", Old: oldSHA, New: mergeSHA},
+		},
+		{
+			name:      "push-to-branch",
+			refUpdate: hook.ReferenceUpdate{Ref: gitReferenceNamePrefixBranch + "feature", Old: oldSHA, New: badSHA},
+			expError:  true,
+		},
+		{
+			name:      "push-to-unmatched-branch",
+			refUpdate: hook.ReferenceUpdate{Ref: gitReferenceNamePrefixBranch + "scratch", Old: oldSHA, New: badSHA},
+		},
+		{
+			name:      "push-tag",
+			refUpdate: hook.ReferenceUpdate{Ref: gitReferenceNamePrefixTag + "v1", Old: sha.Nil, New: badSHA},
+			expError:  true,
+		},
+		{
+			name:      "push-other-ref",
+			refUpdate: hook.ReferenceUpdate{Ref: "refs/notes/commits", Old: oldSHA, New: badSHA},
+			expError:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &Controller{
+				authorizer:        fakePushAuthorizer{},
+				protectionManager: protectionManager,
+			}
+
+			output := hook.Output{}
+			session := &auth.Session{Principal: types.Principal{ID: 1}}
+			in := types.GithookPreReceiveInput{
+				PreReceiveInput: hook.PreReceiveInput{RefUpdates: []hook.ReferenceUpdate{test.refUpdate}},
+			}
+			refs := groupRefsByAction(in.RefUpdates, []bool{false})
+
+			err := c.checkProtectionRules(context.Background(), rgit, session, repo, in, refs, &output)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if blocked := output.Error != nil; blocked != test.expError {
+				t.Errorf("expected blocked=%t, got output error %q, messages %v",
+					test.expError, ptr.ToString(output.Error), output.Messages)
+			}
+		})
+	}
+}
+
+// fakeCommitsGIT lists the commits of a revision. The referenced commits,
+// i.e. the commits already in the repository, are excluded if requested.
+type fakeCommitsGIT struct {
+	RestrictedGIT
+	commits    map[sha.SHA][]git.Commit
+	referenced map[sha.SHA]bool
+}
+
+func (f fakeCommitsGIT) ListCommitSignatures(
+	_ context.Context,
+	params *git.ListCommitSignaturesParams,
+) (*git.ListCommitSignaturesOutput, error) {
+	var commits []git.Commit
+	for _, commit := range f.commits[sha.Must(params.Rev)] {
+		if params.ExcludeReferenced && f.referenced[commit.SHA] {
+			continue
+		}
+		commits = append(commits, commit)
+	}
+
+	return &git.ListCommitSignaturesOutput{Commits: commits}, nil
+}
+
+type fakeRuleStore struct {
+	store.RuleStore
+	rules []types.RuleInfoInternal
+}
+
+func (f fakeRuleStore) ListAllRepoRules(context.Context, int64) ([]types.RuleInfoInternal, error) {
+	return f.rules, nil
+}
+
+// fakePushAuthorizer grants the push permission for the repositories with the provided paths.
+type fakePushAuthorizer struct {
+	pushRepos []string
+}
+
+func (f fakePushAuthorizer) Check(
+	_ context.Context,
+	_ *auth.Session,
+	scope *types.Scope,
+	resource *types.Resource,
+	permission enum.Permission,
+) (bool, error) {
+	if permission != enum.PermissionRepoPush || resource.Type != enum.ResourceTypeRepo {
+		return false, nil
+	}
+	for _, path := range f.pushRepos {
+		if path == scope.SpacePath+"/"+resource.Identifier {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f fakePushAuthorizer) CheckAll(context.Context, *auth.Session, ...types.PermissionCheck) (bool, error) {
+	return false, nil
+}
+
+type fakeRepoStore struct {
+	store.RepoStore
+	repos []*types.Repository
+}
+
+func (f fakeRepoStore) Find(_ context.Context, id int64) (*types.Repository, error) {
+	for _, repo := range f.repos {
+		if repo.ID == id {
+			return repo, nil
+		}
+	}
+	return nil, gitness_store.ErrResourceNotFound
+}
+
+type fakePullReqStore struct {
+	store.PullReqStore
+	prs []*types.PullReq
+}
+
+func (f fakePullReqStore) List(_ context.Context, filter *types.PullReqFilter) ([]*types.PullReq, error) {
+	var prs []*types.PullReq
+	for _, pr := range f.prs {
+		if pr.SourceRepoID == filter.SourceRepoID && pr.TargetRepoID == filter.TargetRepoID &&
+			(filter.SourceBranch == "" || pr.SourceBranch == filter.SourceBranch) {
+			prs = append(prs, pr)
+		}
+	}
+	return prs, nil
+}
@@ -19,7 +19,9 @@ import (
 	"github.com/harness/gitness/app/auth/authz"
 	eventsgit "github.com/harness/gitness/app/events/git"
 	eventsrepo "github.com/harness/gitness/app/events/repo"
+	"github.com/harness/gitness/app/services/mirror"
 	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/app/services/publickey"
 	"github.com/harness/gitness/app/services/settings"
 	"github.com/harness/gitness/app/sse"
 	"github.com/harness/gitness/app/store"
@@ -58,6 +60,8 @@ func ProvideController(
 	updateExtender UpdateExtender,
 	postReceiveExtender PostReceiveExtender,
 	sseStreamer sse.Streamer,
+	publicKeySvc publickey.Service,
+	mirrorSvc *mirror.Service,
 ) *Controller {
 	ctrl := NewController(
 		authorizer,
@@ -75,6 +79,8 @@ func ProvideController(
 		updateExtender,
 		postReceiveExtender,
 		sseStreamer,
+		publicKeySvc,
+		mirrorSvc,
 	)
 
 	// TODO: improve wiring if possible
@@ -0,0 +1,182 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lfs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+const (
+	OperationDownload = "download"
+	OperationUpload   = "upload"
+
+	TransferBasic  = "basic"
+	HashAlgoSHA256 = "sha256"
+)
+
+// BatchRequest is the request of the git lfs batch API.
+// See https://github.com/git-lfs/git-lfs/blob/main/docs/api/batch.md
+type BatchRequest struct {
+	Operation string    `json:"operation"`
+	Transfers []string  `json:"transfers,omitempty"`
+	Ref       *Ref      `json:"ref,omitempty"`
+	Objects   []Pointer `json:"objects"`
+	HashAlgo  string    `json:"hash_algo,omitempty"`
+}
+
+type Ref struct {
+	Name string `json:"name"`
+}
+
+type Pointer struct {
+	OID  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+// BatchResponse is the response of the git lfs batch API.
+type BatchResponse struct {
+	Transfer string           `json:"transfer"`
+	Objects  []ObjectResponse `json:"objects"`
+	HashAlgo string           `json:"hash_algo"`
+}
+
+type ObjectResponse struct {
+	Pointer
+	Actions map[string]Action `json:"actions,omitempty"`
+	Error   *ObjectError      `json:"error,omitempty"`
+}
+
+type Action struct {
+	Href   string            `json:"href"`
+	Header map[string]string `json:"header,omitempty"`
+}
+
+type ObjectError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (in *BatchRequest) sanitize() error {
+	if in.Operation != OperationDownload && in.Operation != OperationUpload {
+		return usererror.BadRequestf("Unsupported operation %q.", in.Operation)
+	}
+
+	if len(in.Transfers) > 0 && !slices.Contains(in.Transfers, TransferBasic) {
+		return usererror.BadRequestf("Only the %q transfer adapter is supported.", TransferBasic)
+	}
+
+	if in.HashAlgo != "" && in.HashAlgo != HashAlgoSHA256 {
+		return usererror.BadRequestf("Only the %q hash algorithm is supported.", HashAlgoSHA256)
+	}
+
+	return nil
+}
+
+// Batch handles the git lfs batch API request and returns the transfer actions for the requested objects.
+func (c *Controller) Batch(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	in *BatchRequest,
+) (*BatchResponse, error) {
+	if err := in.sanitize(); err != nil {
+		return nil, err
+	}
+
+	permission := enum.PermissionRepoView
+	if in.Operation == OperationUpload {
+		permission = enum.PermissionRepoPush
+	}
+
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, permission)
+	if err != nil {
+		return nil, err
+	}
+
+	oids := make([]string, 0, len(in.Objects))
+	for _, obj := range in.Objects {
+		if isValidOID(obj.OID) {
+			oids = append(oids, obj.OID)
+		}
+	}
+
+	existing := map[string]*types.LFSObject{}
+	if len(oids) > 0 {
+		objects, err := c.lfsStore.FindMany(ctx, repo.ID, oids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find lfs objects: %w", err)
+		}
+
+		for _, obj := range objects {
+			existing[obj.OID] = obj
+		}
+	}
+
+	objectsURL := c.urlProvider.GenerateGITCloneURL(ctx, repo.Path) + "/info/lfs/objects/"
+
+	objects := make([]ObjectResponse, len(in.Objects))
+	for i, obj := range in.Objects {
+		objects[i] = ObjectResponse{Pointer: obj}
+
+		if !isValidOID(obj.OID) || obj.Size < 0 {
+			objects[i].Error = &ObjectError{
+				Code:    http.StatusUnprocessableEntity,
+				Message: "Invalid object ID or size.",
+			}
+			continue
+		}
+
+		stored, ok := existing[obj.OID]
+
+		switch in.Operation {
+		case OperationUpload:
+			// objects that are already stored don't need to be uploaded again.
+			if ok {
+				objects[i].Size = stored.Size
+				continue
+			}
+
+			objects[i].Actions = map[string]Action{
+				OperationUpload: {Href: objectsURL + obj.OID},
+			}
+		case OperationDownload:
+			if !ok {
+				objects[i].Error = &ObjectError{
+					Code:    http.StatusNotFound,
+					Message: "Object doesn't exist.",
+				}
+				continue
+			}
+
+			objects[i].Size = stored.Size
+			objects[i].Actions = map[string]Action{
+				OperationDownload: {Href: objectsURL + obj.OID},
+			}
+		}
+	}
+
+	return &BatchResponse{
+		Transfer: TransferBasic,
+		Objects:  objects,
+		HashAlgo: HashAlgoSHA256,
+	}, nil
+}
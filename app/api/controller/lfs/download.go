@@ -0,0 +1,62 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/blob"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types/enum"
+)
+
+// Download returns the content and the size of an LFS object.
+func (c *Controller) Download(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	oid string,
+) (io.ReadCloser, int64, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if !isValidOID(oid) {
+		return nil, 0, usererror.BadRequest("Invalid object ID.")
+	}
+
+	obj, err := c.lfsStore.Find(ctx, repo.ID, oid)
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return nil, 0, usererror.NotFound("Object not found.")
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find lfs object: %w", err)
+	}
+
+	file, err := c.blobStore.Download(ctx, GetObjectPath(repo.ID, oid))
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, 0, usererror.NotFound("Object not found.")
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to download lfs object: %w", err)
+	}
+
+	return file, obj.Size, nil
+}
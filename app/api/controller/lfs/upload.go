@@ -0,0 +1,127 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lfs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/harness/gitness/app/api/controller/limiter"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Upload stores the content of an LFS object. The content is written to a temporary location
+// and moved to the object path only after it's verified against the object ID and the declared size.
+func (c *Controller) Upload(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	oid string,
+	size int64,
+	file io.Reader,
+) error {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	if err != nil {
+		return err
+	}
+
+	if !isValidOID(oid) {
+		return usererror.BadRequest("Invalid object ID.")
+	}
+
+	if size < 0 {
+		return usererror.BadRequest("The size of the object must be provided.")
+	}
+
+	if err := c.limiter.RepoSize(ctx, repo.ID); err != nil {
+		return fmt.Errorf("resource limit exceeded: %w", limiter.ErrMaxRepoSizeReached)
+	}
+
+	_, err = c.lfsStore.Find(ctx, repo.ID, oid)
+	if err == nil {
+		// the object is already stored, content addressing guarantees it's the same.
+		return nil
+	}
+	if !errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return fmt.Errorf("failed to find lfs object: %w", err)
+	}
+
+	hash := sha256.New()
+	// read one byte more than declared to detect content that is larger than the declared size.
+	counter := &countingReader{r: io.TeeReader(io.LimitReader(file, size+1), hash)}
+
+	tempPath := getTempObjectPath(repo.ID, oid)
+	if err := c.blobStore.Upload(ctx, counter, tempPath); err != nil {
+		return fmt.Errorf("failed to upload lfs object: %w", err)
+	}
+
+	moved := false
+	defer func() {
+		if moved {
+			return
+		}
+		if err := c.blobStore.Delete(ctx, tempPath); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("failed to delete temporary lfs object %q", tempPath)
+		}
+	}()
+
+	if counter.n != size {
+		return usererror.BadRequestf("Object size doesn't match the declared size of %d bytes.", size)
+	}
+
+	if hex.EncodeToString(hash.Sum(nil)) != oid {
+		return usererror.BadRequest("Object content doesn't match the object ID.")
+	}
+
+	if err := c.blobStore.Move(ctx, tempPath, GetObjectPath(repo.ID, oid)); err != nil {
+		return fmt.Errorf("failed to move lfs object: %w", err)
+	}
+
+	moved = true
+
+	err = c.lfsStore.Create(ctx, &types.LFSObject{
+		OID:       oid,
+		Size:      size,
+		Created:   time.Now().UnixMilli(),
+		CreatedBy: session.Principal.ID,
+		RepoID:    repo.ID,
+	})
+	if err != nil && !errors.Is(err, gitness_store.ErrDuplicate) {
+		return fmt.Errorf("failed to create lfs object: %w", err)
+	}
+
+	return nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,198 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lfs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/harness/gitness/app/api/controller/limiter"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/blob"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+func TestControllerUpload(t *testing.T) {
+	const content = "lfs object content"
+	sum := sha256.Sum256([]byte(content))
+	oid := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name      string
+		oid       string
+		size      int64
+		content   string
+		existing  bool
+		expErr    string
+		expStored bool
+	}{
+		{
+			name:      "valid",
+			oid:       oid,
+			size:      int64(len(content)),
+			content:   content,
+			expStored: true,
+		},
+		{
+			name:    "hash mismatch",
+			oid:     strings.Repeat("0", 64),
+			size:    int64(len(content)),
+			content: content,
+			expErr:  "Object content doesn't match the object ID.",
+		},
+		{
+			name:    "larger than declared",
+			oid:     oid,
+			size:    int64(len(content)) - 1,
+			content: content,
+			expErr:  "Object size doesn't match the declared size of 17 bytes.",
+		},
+		{
+			name:    "smaller than declared",
+			oid:     oid,
+			size:    int64(len(content)) + 1,
+			content: content,
+			expErr:  "Object size doesn't match the declared size of 19 bytes.",
+		},
+		{
+			name:    "size not declared",
+			oid:     oid,
+			size:    -1,
+			content: content,
+			expErr:  "The size of the object must be provided.",
+		},
+		{
+			name:     "already stored",
+			oid:      oid,
+			size:     int64(len(content)),
+			content:  "ignored",
+			existing: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			basePath := t.TempDir()
+
+			blobStore, err := blob.NewFileSystemStore(blob.Config{Bucket: basePath})
+			if err != nil {
+				t.Fatalf("failed to create blob store: %v", err)
+			}
+
+			lfsStore := &fakeLFSObjectStore{objects: map[string]*types.LFSObject{}}
+			if test.existing {
+				lfsStore.objects[test.oid] = &types.LFSObject{OID: test.oid, RepoID: 1}
+			}
+
+			c := NewController(
+				fakeAuthorizer{},
+				refcache.NewRepoFinder(fakeRepoStore{}, nil),
+				lfsStore,
+				blobStore,
+				nil,
+				limiter.Unlimited{},
+			)
+
+			session := &auth.Session{Principal: types.Principal{ID: 7}}
+
+			err = c.Upload(ctx, session, "1", test.oid, test.size, strings.NewReader(test.content))
+			if test.expErr != "" {
+				if err == nil || err.Error() != test.expErr {
+					t.Fatalf("expected error %q, got %v", test.expErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			obj, stored := lfsStore.objects[test.oid]
+			if test.expStored != (stored && obj.CreatedBy == session.Principal.ID) {
+				t.Errorf("expected object stored to be %t", test.expStored)
+			}
+			if test.expStored && obj.Size != test.size {
+				t.Errorf("expected object size %d, got %d", test.size, obj.Size)
+			}
+
+			rc, err := blobStore.Download(ctx, GetObjectPath(1, test.oid))
+			switch {
+			case test.expStored && err != nil:
+				t.Fatalf("failed to download the object: %v", err)
+			case test.expStored:
+				data, _ := io.ReadAll(rc)
+				_ = rc.Close()
+				if string(data) != test.content {
+					t.Errorf("expected object content %q, got %q", test.content, data)
+				}
+			case !errors.Is(err, blob.ErrNotFound):
+				t.Errorf("expected no object content to be stored, got %v", err)
+			}
+
+			// the temporary upload must never be left behind.
+			tempFiles, _ := os.ReadDir(filepath.Join(basePath, "lfs", "1", "tmp"))
+			if len(tempFiles) != 0 {
+				t.Errorf("expected no temporary files, got %d", len(tempFiles))
+			}
+		})
+	}
+}
+
+type fakeAuthorizer struct{}
+
+func (fakeAuthorizer) Check(
+	context.Context, *auth.Session, *types.Scope, *types.Resource, enum.Permission,
+) (bool, error) {
+	return true, nil
+}
+
+func (fakeAuthorizer) CheckAll(context.Context, *auth.Session, ...types.PermissionCheck) (bool, error) {
+	return true, nil
+}
+
+type fakeRepoStore struct {
+	store.RepoStore
+}
+
+func (fakeRepoStore) Find(_ context.Context, id int64) (*types.Repository, error) {
+	return &types.Repository{ID: id, Identifier: "repo", Path: "space/repo"}, nil
+}
+
+type fakeLFSObjectStore struct {
+	store.LFSObjectStore
+	objects map[string]*types.LFSObject
+}
+
+func (f *fakeLFSObjectStore) Find(_ context.Context, _ int64, oid string) (*types.LFSObject, error) {
+	obj, ok := f.objects[oid]
+	if !ok {
+		return nil, gitness_store.ErrResourceNotFound
+	}
+	return obj, nil
+}
+
+func (f *fakeLFSObjectStore) Create(_ context.Context, obj *types.LFSObject) error {
+	f.objects[obj.OID] = obj
+	return nil
+}
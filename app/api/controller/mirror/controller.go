@@ -0,0 +1,132 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mirror
+
+import (
+	"context"
+	"fmt"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/auth/authz"
+	"github.com/harness/gitness/app/services/mirror"
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/errors"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+type Controller struct {
+	authorizer     authz.Authorizer
+	repoFinder     refcache.RepoFinder
+	mirrorStore    store.MirrorStore
+	connectorStore store.ConnectorStore
+	secretStore    store.SecretStore
+	mirrorSvc      *mirror.Service
+}
+
+func NewController(
+	authorizer authz.Authorizer,
+	repoFinder refcache.RepoFinder,
+	mirrorStore store.MirrorStore,
+	connectorStore store.ConnectorStore,
+	secretStore store.SecretStore,
+	mirrorSvc *mirror.Service,
+) *Controller {
+	return &Controller{
+		authorizer:     authorizer,
+		repoFinder:     repoFinder,
+		mirrorStore:    mirrorStore,
+		connectorStore: connectorStore,
+		secretStore:    secretStore,
+		mirrorSvc:      mirrorSvc,
+	}
+}
+
+func (c *Controller) getRepoCheckAccess(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	permission enum.Permission,
+) (*types.Repository, error) {
+	if repoRef == "" {
+		return nil, usererror.BadRequest("A valid repository reference must be provided.")
+	}
+
+	repo, err := c.repoFinder.FindByRef(ctx, repoRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find repo: %w", err)
+	}
+
+	if err = apiauth.CheckRepo(ctx, c.authorizer, session, repo, permission); err != nil {
+		return nil, fmt.Errorf("failed to verify authorization: %w", err)
+	}
+
+	return repo, nil
+}
+
+func (c *Controller) getMirror(
+	ctx context.Context,
+	repoID int64,
+	identifier string,
+) (*types.Mirror, error) {
+	m, err := c.mirrorStore.FindByIdentifier(ctx, repoID, identifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find mirror %q: %w", identifier, err)
+	}
+
+	return m, nil
+}
+
+// checkCredentials verifies that the connector or the secret used by the mirror
+// exists in the parent space of the repository.
+func (c *Controller) checkCredentials(
+	ctx context.Context,
+	repo *types.Repository,
+	connectorIdentifier string,
+	secretIdentifier string,
+) error {
+	if connectorIdentifier != "" {
+		_, err := c.connectorStore.FindByIdentifier(ctx, repo.ParentID, connectorIdentifier)
+		if errors.Is(err, gitness_store.ErrResourceNotFound) {
+			return usererror.BadRequestf("Connector %q not found.", connectorIdentifier)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find connector: %w", err)
+		}
+	}
+
+	if secretIdentifier != "" {
+		_, err := c.secretStore.FindByIdentifier(ctx, repo.ParentID, secretIdentifier)
+		if errors.Is(err, gitness_store.ErrResourceNotFound) {
+			return usererror.BadRequestf("Secret %q not found.", secretIdentifier)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find secret: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func checkInterval(interval int64) error {
+	if interval < mirror.MinInterval {
+		return usererror.BadRequestf("Interval must be at least %d seconds.", mirror.MinInterval)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/services/mirror"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/check"
+	"github.com/harness/gitness/types/enum"
+)
+
+// defaultInterval is the default number of seconds between two synchronizations of a pull mirror.
+const defaultInterval = 60 * 60
+
+// Create creates a new repository mirror.
+func (c *Controller) Create(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	in *types.MirrorCreateInput,
+) (*types.Mirror, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.sanitizeCreateInput(ctx, in); err != nil {
+		return nil, err
+	}
+
+	if err := c.checkCredentials(ctx, repo, in.ConnectorIdentifier, in.SecretIdentifier); err != nil {
+		return nil, err
+	}
+
+	if in.Direction == enum.MirrorDirectionPull {
+		pullMirrors, err := c.mirrorStore.List(ctx, repo.ID, enum.MirrorDirectionPull)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull mirrors: %w", err)
+		}
+		if len(pullMirrors) > 0 {
+			return nil, usererror.BadRequest("Repository already has a pull mirror.")
+		}
+	}
+
+	now := time.Now()
+	m := &types.Mirror{
+		RepoID:              repo.ID,
+		Identifier:          in.Identifier,
+		Direction:           in.Direction,
+		RemoteURL:           in.RemoteURL,
+		Username:            in.Username,
+		SecretIdentifier:    in.SecretIdentifier,
+		ConnectorIdentifier: in.ConnectorIdentifier,
+		Interval:            in.Interval,
+		Enabled:             in.Enabled,
+		SyncState:           enum.MirrorSyncStateIdle,
+		CreatedBy:           session.Principal.ID,
+		Created:             now.UnixMilli(),
+		Updated:             now.UnixMilli(),
+	}
+	m.NextSync = mirror.NextSync(m, now)
+
+	err = c.mirrorStore.Create(ctx, m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mirror: %w", err)
+	}
+
+	if !m.Enabled {
+		return m, nil
+	}
+
+	// synchronize the new mirror right away instead of waiting for the first interval or push.
+	m, err = c.mirrorSvc.Sync(ctx, m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sync mirror: %w", err)
+	}
+
+	return m, nil
+}
+
+func (c *Controller) sanitizeCreateInput(ctx context.Context, in *types.MirrorCreateInput) error {
+	if err := check.Identifier(in.Identifier); err != nil {
+		return err
+	}
+
+	direction, ok := in.Direction.Sanitize()
+	if !ok || direction == "" {
+		return usererror.BadRequest("Mirror direction must be either pull or push.")
+	}
+	in.Direction = direction
+
+	if err := c.mirrorSvc.CheckRemoteURL(ctx, in.RemoteURL); err != nil {
+		return err
+	}
+
+	if in.ConnectorIdentifier != "" && in.SecretIdentifier != "" {
+		return usererror.BadRequest("Only one of connector or secret can be provided.")
+	}
+
+	if in.Direction == enum.MirrorDirectionPush {
+		in.Interval = 0
+		return nil
+	}
+
+	if in.Interval == 0 {
+		in.Interval = defaultInterval
+	}
+
+	return checkInterval(in.Interval)
+}
@@ -0,0 +1,134 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/services/mirror"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/check"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/gotidy/ptr"
+)
+
+// Update updates a repository mirror.
+func (c *Controller) Update(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	identifier string,
+	in *types.MirrorUpdateInput,
+) (*types.Mirror, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := c.getMirror(ctx, repo.ID, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.sanitizeUpdateInput(ctx, m, in); err != nil {
+		return nil, err
+	}
+
+	if in.ConnectorIdentifier != nil || in.SecretIdentifier != nil {
+		err = c.checkCredentials(ctx, repo, ptr.ToString(in.ConnectorIdentifier), ptr.ToString(in.SecretIdentifier))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	m, err = c.mirrorStore.UpdateOptLock(ctx, m, func(m *types.Mirror) error {
+		if in.Identifier != nil {
+			m.Identifier = *in.Identifier
+		}
+		if in.RemoteURL != nil {
+			m.RemoteURL = *in.RemoteURL
+		}
+		if in.Username != nil {
+			m.Username = *in.Username
+		}
+		if in.SecretIdentifier != nil {
+			m.SecretIdentifier = *in.SecretIdentifier
+		}
+		if in.ConnectorIdentifier != nil {
+			m.ConnectorIdentifier = *in.ConnectorIdentifier
+		}
+		if in.Interval != nil {
+			m.Interval = *in.Interval
+		}
+		if in.Enabled != nil {
+			m.Enabled = *in.Enabled
+		}
+
+		// the next synchronization of a pull mirror is recalculated from the updated interval.
+		if in.Interval != nil || in.Enabled != nil {
+			m.NextSync = mirror.NextSync(m, now)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update mirror: %w", err)
+	}
+
+	return m, nil
+}
+
+func (c *Controller) sanitizeUpdateInput(ctx context.Context, m *types.Mirror, in *types.MirrorUpdateInput) error {
+	if in.Identifier != nil {
+		if err := check.Identifier(*in.Identifier); err != nil {
+			return err
+		}
+	}
+
+	if in.RemoteURL != nil {
+		if err := c.mirrorSvc.CheckRemoteURL(ctx, *in.RemoteURL); err != nil {
+			return err
+		}
+	}
+
+	connectorIdentifier := m.ConnectorIdentifier
+	if in.ConnectorIdentifier != nil {
+		connectorIdentifier = *in.ConnectorIdentifier
+	}
+	secretIdentifier := m.SecretIdentifier
+	if in.SecretIdentifier != nil {
+		secretIdentifier = *in.SecretIdentifier
+	}
+	if connectorIdentifier != "" && secretIdentifier != "" {
+		return usererror.BadRequest("Only one of connector or secret can be provided.")
+	}
+
+	if in.Interval != nil {
+		if m.Direction != enum.MirrorDirectionPull {
+			return usererror.BadRequest("Interval can be set only for pull mirrors.")
+		}
+		if err := checkInterval(*in.Interval); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
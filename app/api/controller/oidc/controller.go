@@ -0,0 +1,87 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"fmt"
+
+	"github.com/harness/gitness/app/api/controller/user"
+	authoidc "github.com/harness/gitness/app/auth/oidc"
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+)
+
+type Controller struct {
+	tx                   dbtx.Transactor
+	provider             *authoidc.Provider
+	userCtrl             *user.Controller
+	principalStore       store.PrincipalStore
+	tokenStore           store.TokenStore
+	identityStore        store.OIDCIdentityStore
+	membershipStore      store.MembershipStore
+	userGroupStore       store.UserGroupStore
+	userGroupMemberStore store.UserGroupMemberStore
+	spaceCache           refcache.SpaceCache
+
+	enabled            bool
+	autoProvision      bool
+	matchVerifiedEmail bool
+	spaceRoleMappings  []spaceRoleMapping
+	userGroupMappings  []userGroupMapping
+}
+
+func NewController(
+	config *types.Config,
+	tx dbtx.Transactor,
+	provider *authoidc.Provider,
+	userCtrl *user.Controller,
+	principalStore store.PrincipalStore,
+	tokenStore store.TokenStore,
+	identityStore store.OIDCIdentityStore,
+	membershipStore store.MembershipStore,
+	userGroupStore store.UserGroupStore,
+	userGroupMemberStore store.UserGroupMemberStore,
+	spaceCache refcache.SpaceCache,
+) (*Controller, error) {
+	spaceRoleMappings, err := parseSpaceRoleMappings(config.OIDC.SpaceRoleMappings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OIDC space role mappings: %w", err)
+	}
+
+	userGroupMappings, err := parseUserGroupMappings(config.OIDC.UserGroupMappings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OIDC usergroup mappings: %w", err)
+	}
+
+	return &Controller{
+		tx:                   tx,
+		provider:             provider,
+		userCtrl:             userCtrl,
+		principalStore:       principalStore,
+		tokenStore:           tokenStore,
+		identityStore:        identityStore,
+		membershipStore:      membershipStore,
+		userGroupStore:       userGroupStore,
+		userGroupMemberStore: userGroupMemberStore,
+		spaceCache:           spaceCache,
+		enabled:              config.OIDC.Enabled,
+		autoProvision:        config.OIDC.AutoProvision,
+		matchVerifiedEmail:   config.OIDC.MatchVerifiedEmail,
+		spaceRoleMappings:    spaceRoleMappings,
+		userGroupMappings:    userGroupMappings,
+	}, nil
+}
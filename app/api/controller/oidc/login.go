@@ -0,0 +1,239 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/app/api/controller/user"
+	"github.com/harness/gitness/app/api/usererror"
+	authoidc "github.com/harness/gitness/app/auth/oidc"
+	"github.com/harness/gitness/app/token"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/check"
+
+	"github.com/dchest/uniuri"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// provisionedPasswordLength is the length of the random password of provisioned users.
+	// Nobody knows the password, so provisioned users can sign in only with single sign-on.
+	provisionedPasswordLength = 64
+
+	uidSuffixLength = 4
+)
+
+var illegalUIDChars = regexp.MustCompile(`[^a-zA-Z0-9-_.]+`)
+
+type CallbackInput struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+
+	// Error and ErrorDescription are returned by the identity provider if the authorization failed.
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Authorize starts the authorization code flow. It returns the authorization request,
+// which has to be kept until the callback, and the URL of the identity provider.
+func (c *Controller) Authorize(ctx context.Context) (*authoidc.AuthRequest, string, error) {
+	if !c.enabled {
+		return nil, "", usererror.Forbidden("Single sign-on is not enabled")
+	}
+
+	req, authURL, err := c.provider.NewAuthRequest(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create authorization request: %w", err)
+	}
+
+	return req, authURL, nil
+}
+
+// Callback completes the authorization code flow - it signs in the user
+// identified by the identity provider and returns the session token.
+func (c *Controller) Callback(
+	ctx context.Context,
+	req *authoidc.AuthRequest,
+	in *CallbackInput,
+) (*types.TokenResponse, error) {
+	if !c.enabled {
+		return nil, usererror.Forbidden("Single sign-on is not enabled")
+	}
+
+	if in.Error != "" {
+		log.Ctx(ctx).Info().Msgf("identity provider rejected the authorization: %s: %s", in.Error, in.ErrorDescription)
+		return nil, usererror.ErrUnauthorized
+	}
+
+	if req == nil || in.State == "" || subtle.ConstantTimeCompare([]byte(req.State), []byte(in.State)) != 1 {
+		return nil, usererror.BadRequest("Invalid or expired single sign-on state, please try again")
+	}
+
+	claims, err := c.provider.Exchange(ctx, req, in.Code)
+	if errors.Is(err, authoidc.ErrInvalidToken) {
+		log.Ctx(ctx).Warn().Err(err).Msg("received invalid ID token")
+		return nil, usererror.ErrUnauthorized
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	usr, err := c.findOrProvisionUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if usr.Blocked {
+		return nil, usererror.Forbidden("The user is blocked")
+	}
+
+	if err = c.syncGroups(ctx, usr, claims.Groups); err != nil {
+		return nil, fmt.Errorf("failed to sync groups of the user: %w", err)
+	}
+
+	err = c.identityStore.UpdateLastLogin(ctx, claims.Issuer, claims.Subject, time.Now().UnixMilli())
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to update last login of the identity")
+	}
+
+	// the session is created only after the second factor is verified.
+	if err = c.userCtrl.TwoFactorChallenge(ctx, usr); err != nil {
+		return nil, err
+	}
+
+	tokenIdentifier, err := user.GenerateSessionTokenIdentifier()
+	if err != nil {
+		return nil, err
+	}
+
+	tkn, jwtToken, err := token.CreateUserSession(ctx, c.tokenStore, usr, tokenIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.TokenResponse{Token: *tkn, AccessToken: jwtToken}, nil
+}
+
+// findOrProvisionUser returns the user linked to the identity. On the first sign-in, the identity is linked
+// to the user with the same verified email or, if there's none, to a newly provisioned user.
+func (c *Controller) findOrProvisionUser(ctx context.Context, claims *types.OIDCClaims) (*types.User, error) {
+	identity, err := c.identityStore.Find(ctx, claims.Issuer, claims.Subject)
+	if err == nil {
+		return c.principalStore.FindUser(ctx, identity.PrincipalID)
+	}
+	if !errors.Is(err, store.ErrResourceNotFound) {
+		return nil, fmt.Errorf("failed to find identity: %w", err)
+	}
+
+	var usr *types.User
+
+	if c.matchVerifiedEmail && claims.EmailVerified && claims.Email != "" {
+		usr, err = c.principalStore.FindUserByEmail(ctx, claims.Email)
+		if err != nil && !errors.Is(err, store.ErrResourceNotFound) {
+			return nil, fmt.Errorf("failed to find user by email: %w", err)
+		}
+	}
+
+	if usr == nil {
+		if !c.autoProvision {
+			return nil, usererror.Forbidden("The user is not registered")
+		}
+
+		usr, err = c.provisionUser(ctx, claims)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UnixMilli()
+	err = c.identityStore.Create(ctx, &types.OIDCIdentity{
+		Issuer:      claims.Issuer,
+		Subject:     claims.Subject,
+		PrincipalID: usr.ID,
+		Created:     now,
+		LastLogin:   now,
+	})
+	if errors.Is(err, store.ErrDuplicate) {
+		// the identity was linked by a concurrent sign-in.
+		return c.findOrProvisionUser(ctx, claims)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to link identity to user: %w", err)
+	}
+
+	log.Ctx(ctx).Info().Msgf("linked identity %q of issuer %q to user %q", claims.Subject, claims.Issuer, usr.UID)
+
+	return usr, nil
+}
+
+func (c *Controller) provisionUser(ctx context.Context, claims *types.OIDCClaims) (*types.User, error) {
+	if claims.Email == "" {
+		return nil, usererror.BadRequest("The identity provider didn't provide the email of the user")
+	}
+
+	uid := sanitizeUID(claims.PreferredUsername)
+	if uid == "" {
+		uid = sanitizeUID(strings.Split(claims.Email, "@")[0])
+	}
+
+	displayName := claims.Name
+	if displayName == "" {
+		displayName = uid
+	}
+
+	in := &user.CreateInput{
+		UID:         uid,
+		Email:       claims.Email,
+		DisplayName: displayName,
+		Password:    uniuri.NewLen(provisionedPasswordLength),
+	}
+
+	usr, err := c.userCtrl.CreateNoAuth(ctx, in, false)
+	if errors.Is(err, store.ErrDuplicate) {
+		if _, findErr := c.principalStore.FindUserByEmail(ctx, claims.Email); findErr == nil {
+			return nil, usererror.Conflict("A user with the same email already exists")
+		}
+
+		// the UID is taken, retry with a random suffix.
+		in.UID = uid[:min(len(uid), check.MaxIdentifierLength-uidSuffixLength-1)] + "-" +
+			strings.ToLower(uniuri.NewLen(uidSuffixLength))
+		in.Password = uniuri.NewLen(provisionedPasswordLength)
+		usr, err = c.userCtrl.CreateNoAuth(ctx, in, false)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to provision user: %w", err)
+	}
+
+	log.Ctx(ctx).Info().Msgf("provisioned user %q for identity %q of issuer %q", usr.UID, claims.Subject, claims.Issuer)
+
+	return usr, nil
+}
+
+func sanitizeUID(s string) string {
+	uid := strings.Trim(illegalUIDChars.ReplaceAllString(s, "-"), "-.")
+	if len(uid) > check.MaxIdentifierLength {
+		uid = uid[:check.MaxIdentifierLength]
+	}
+
+	return uid
+}
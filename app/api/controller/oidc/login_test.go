@@ -0,0 +1,292 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/api/controller/user"
+	"github.com/harness/gitness/app/api/usererror"
+	authoidc "github.com/harness/gitness/app/auth/oidc"
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/services/settings"
+	systemsvc "github.com/harness/gitness/app/services/system"
+	appstore "github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	gojwt "github.com/golang-jwt/jwt"
+)
+
+const (
+	testClientID = "gitness"
+	testNonce    = "nonce"
+	testState    = "state"
+)
+
+// newTestIdentityProvider starts an identity provider that issues ID tokens for the provided subject and email.
+func newTestIdentityProvider(t *testing.T, subject, email string) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+
+	var server *httptest.Server
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 server.URL,
+			"authorization_endpoint": server.URL + "/authorize",
+			"token_endpoint":         server.URL + "/token",
+			"jwks_uri":               server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, _ *http.Request) {
+		idToken, err := gojwt.NewWithClaims(gojwt.SigningMethodRS256, gojwt.MapClaims{
+			"iss":            server.URL,
+			"sub":            subject,
+			"aud":            testClientID,
+			"exp":            time.Now().Add(time.Hour).Unix(),
+			"nonce":          testNonce,
+			"email":          email,
+			"email_verified": true,
+		}).SignedString(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, map[string]any{"access_token": "access-token", "token_type": "Bearer", "id_token": idToken})
+	})
+
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+type fakeIdentityStore struct {
+	appstore.OIDCIdentityStore
+	identities map[string]*types.OIDCIdentity
+}
+
+func (s *fakeIdentityStore) Find(_ context.Context, _, subject string) (*types.OIDCIdentity, error) {
+	if identity, ok := s.identities[subject]; ok {
+		return identity, nil
+	}
+	return nil, store.ErrResourceNotFound
+}
+
+func (s *fakeIdentityStore) Create(_ context.Context, identity *types.OIDCIdentity) error {
+	s.identities[identity.Subject] = identity
+	return nil
+}
+
+func (s *fakeIdentityStore) UpdateLastLogin(context.Context, string, string, int64) error {
+	return nil
+}
+
+type fakePrincipalStore struct {
+	appstore.PrincipalStore
+	users []*types.User
+}
+
+func (s *fakePrincipalStore) FindUser(_ context.Context, id int64) (*types.User, error) {
+	for _, u := range s.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, store.ErrResourceNotFound
+}
+
+func (s *fakePrincipalStore) FindUserByEmail(_ context.Context, email string) (*types.User, error) {
+	for _, u := range s.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, store.ErrResourceNotFound
+}
+
+type fakeTokenStore struct {
+	appstore.TokenStore
+	created []*types.Token
+}
+
+func (s *fakeTokenStore) Create(_ context.Context, token *types.Token) error {
+	s.created = append(s.created, token)
+	return nil
+}
+
+type fakeTOTPStore struct {
+	appstore.UserTOTPStore
+	totps map[int64]*types.UserTOTP
+}
+
+func (s *fakeTOTPStore) Find(_ context.Context, principalID int64) (*types.UserTOTP, error) {
+	if otp, ok := s.totps[principalID]; ok {
+		return otp, nil
+	}
+	return nil, store.ErrResourceNotFound
+}
+
+type fakeSettingsStore struct {
+	appstore.SettingsStore
+	values map[string]json.RawMessage
+}
+
+func (s *fakeSettingsStore) FindMany(
+	_ context.Context,
+	_ enum.SettingsScope,
+	_ int64,
+	_ ...string,
+) (map[string]json.RawMessage, error) {
+	return s.values, nil
+}
+
+func TestCallback(t *testing.T) {
+	const (
+		subject = "subject"
+		email   = "user@example.com"
+	)
+
+	existingUser := &types.User{ID: 1, UID: "user", Email: email, Salt: "salt"}
+	linkedIdentity := map[string]*types.OIDCIdentity{subject: {Subject: subject, PrincipalID: existingUser.ID}}
+
+	tests := []struct {
+		name                 string
+		identities           map[string]*types.OIDCIdentity
+		totps                map[int64]*types.UserTOTP
+		twoFactorRequirement enum.TwoFactorRequirement
+		wantStatus           int
+		wantChallenge        bool
+		wantEnrollment       bool
+	}{
+		{
+			name:       "session",
+			identities: linkedIdentity,
+		},
+		{
+			name:          "two-factor-enabled",
+			identities:    linkedIdentity,
+			totps:         map[int64]*types.UserTOTP{existingUser.ID: {PrincipalID: existingUser.ID, Enabled: true}},
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: true,
+		},
+		{
+			name:                 "two-factor-required",
+			identities:           linkedIdentity,
+			twoFactorRequirement: enum.TwoFactorRequirementAll,
+			wantStatus:           http.StatusUnauthorized,
+			wantChallenge:        true,
+			wantEnrollment:       true,
+		},
+		{
+			// the identity isn't linked to the user with the same email unless explicitly configured.
+			name:       "email-not-matched-by-default",
+			identities: map[string]*types.OIDCIdentity{},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			config := &types.Config{}
+			config.URL.API = "http://localhost/api"
+			config.OIDC.Enabled = true
+			config.OIDC.Issuer = newTestIdentityProvider(t, subject, email)
+			config.OIDC.ClientID = testClientID
+
+			settingsValues := map[string]json.RawMessage{}
+			if test.twoFactorRequirement != "" {
+				settingsValues[string(settings.KeyTwoFactorRequirement)] =
+					json.RawMessage(`"` + test.twoFactorRequirement + `"`)
+			}
+
+			principalStore := &fakePrincipalStore{users: []*types.User{existingUser}}
+			tokenStore := &fakeTokenStore{}
+			identityStore := &fakeIdentityStore{identities: test.identities}
+			systemService := systemsvc.ProvideService(settings.NewService(&fakeSettingsStore{values: settingsValues}))
+
+			userCtrl := user.NewController(config, nil, nil, nil, principalStore, tokenStore, nil, nil, nil, nil,
+				&fakeTOTPStore{totps: test.totps}, nil, systemService, nil, refcache.RepoFinder{}, nil, nil, nil)
+
+			ctrl, err := NewController(config, nil, authoidc.NewProvider(config), userCtrl,
+				principalStore, tokenStore, identityStore, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to create controller: %s", err)
+			}
+
+			resp, err := ctrl.Callback(ctx,
+				&authoidc.AuthRequest{State: testState, Nonce: testNonce, CodeVerifier: "verifier"},
+				&CallbackInput{Code: "code", State: testState})
+
+			if test.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if resp.AccessToken == "" || len(tokenStore.created) != 1 {
+					t.Fatalf("expected a session to be created")
+				}
+				return
+			}
+
+			uErr := &usererror.Error{}
+			if !errors.As(err, &uErr) || uErr.Status != test.wantStatus {
+				t.Fatalf("expected error with status %d, got: %v", test.wantStatus, err)
+			}
+			if len(tokenStore.created) != 0 {
+				t.Errorf("session must not be created")
+			}
+
+			challenge, _ := uErr.Values["two_factor_challenge"].(string)
+			if (challenge != "") != test.wantChallenge {
+				t.Errorf("unexpected two-factor challenge: %q", challenge)
+			}
+			if enrollment, _ := uErr.Values["enrollment_required"].(bool); enrollment != test.wantEnrollment {
+				t.Errorf("unexpected enrollment requirement: %t", enrollment)
+			}
+		})
+	}
+}
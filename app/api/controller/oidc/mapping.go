@@ -0,0 +1,122 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/harness/gitness/types/enum"
+)
+
+// roleRank orders the space roles from the least to the most privileged one.
+// If the groups of a user map to multiple roles in the same space, the most privileged role is granted.
+var roleRank = []enum.MembershipRole{
+	enum.MembershipRoleReader,
+	enum.MembershipRoleExecutor,
+	enum.MembershipRoleContributor,
+	enum.MembershipRoleSpaceOwner,
+}
+
+// spaceRoleMapping grants a space role to the members of a group of the identity provider.
+type spaceRoleMapping struct {
+	group     string
+	spacePath string
+	role      enum.MembershipRole
+}
+
+// userGroupMapping adds the members of a group of the identity provider to a usergroup.
+type userGroupMapping struct {
+	group               string
+	spacePath           string
+	userGroupIdentifier string
+}
+
+// parseSpaceRoleMappings parses mappings in the format "<group>=<space path>:<role>".
+func parseSpaceRoleMappings(raw []string) ([]spaceRoleMapping, error) {
+	mappings := make([]spaceRoleMapping, 0, len(raw))
+	for _, s := range raw {
+		group, spacePath, value, err := parseMapping(s)
+		if err != nil {
+			return nil, err
+		}
+
+		role, ok := enum.MembershipRole(value).Sanitize()
+		if !ok {
+			return nil, fmt.Errorf("mapping %q has invalid role %q", s, value)
+		}
+
+		mappings = append(mappings, spaceRoleMapping{
+			group:     group,
+			spacePath: spacePath,
+			role:      role,
+		})
+	}
+
+	return mappings, nil
+}
+
+// parseUserGroupMappings parses mappings in the format "<group>=<space path>:<usergroup identifier>".
+func parseUserGroupMappings(raw []string) ([]userGroupMapping, error) {
+	mappings := make([]userGroupMapping, 0, len(raw))
+	for _, s := range raw {
+		group, spacePath, identifier, err := parseMapping(s)
+		if err != nil {
+			return nil, err
+		}
+
+		mappings = append(mappings, userGroupMapping{
+			group:               group,
+			spacePath:           spacePath,
+			userGroupIdentifier: identifier,
+		})
+	}
+
+	return mappings, nil
+}
+
+func parseMapping(s string) (string, string, string, error) {
+	group, target, ok := cutLast(strings.TrimSpace(s), "=")
+	if !ok || group == "" {
+		return "", "", "", fmt.Errorf("mapping %q must be in the format <group>=<space path>:<value>", s)
+	}
+
+	spacePath, value, ok := cutLast(target, ":")
+	if !ok || spacePath == "" || value == "" {
+		return "", "", "", fmt.Errorf("mapping %q must be in the format <group>=<space path>:<value>", s)
+	}
+
+	return group, strings.Trim(spacePath, "/"), value, nil
+}
+
+// cutLast slices s around the last instance of sep. Group names may contain the separators.
+func cutLast(s, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+
+	return s[:i], s[i+len(sep):], true
+}
+
+// higherRole returns the more privileged of the two roles.
+func higherRole(a, b enum.MembershipRole) enum.MembershipRole {
+	if slices.Index(roleRank, a) > slices.Index(roleRank, b) {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,184 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/harness/gitness/app/bootstrap"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+// syncGroups applies the group mappings to the user. Space memberships granted by the mappings are
+// created by the system principal and usergroup memberships have the OIDC source, which is how they are
+// told apart from the memberships managed otherwise. Only the former are revoked when the user leaves a group.
+func (c *Controller) syncGroups(ctx context.Context, usr *types.User, groups []string) error {
+	if len(c.spaceRoleMappings) == 0 && len(c.userGroupMappings) == 0 {
+		return nil
+	}
+
+	syncPrincipalID := bootstrap.NewSystemServiceSession().Principal.ID
+
+	return c.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := c.syncSpaceRoles(ctx, usr, groups, syncPrincipalID); err != nil {
+			return err
+		}
+
+		return c.syncUserGroups(ctx, usr, groups, syncPrincipalID)
+	})
+}
+
+func (c *Controller) syncSpaceRoles(
+	ctx context.Context,
+	usr *types.User,
+	groups []string,
+	syncPrincipalID int64,
+) error {
+	// the most privileged role of the user in every mapped space, an empty role means no membership.
+	roles := make(map[string]enum.MembershipRole)
+	for _, m := range c.spaceRoleMappings {
+		role := roles[m.spacePath]
+		if slices.Contains(groups, m.group) {
+			role = higherRole(role, m.role)
+		}
+		roles[m.spacePath] = role
+	}
+
+	for spacePath, role := range roles {
+		space, err := c.spaceCache.Get(ctx, spacePath)
+		if errors.Is(err, store.ErrResourceNotFound) {
+			log.Ctx(ctx).Warn().Msgf("space %q of the OIDC space role mapping doesn't exist", spacePath)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find space %q: %w", spacePath, err)
+		}
+
+		key := types.MembershipKey{SpaceID: space.ID, PrincipalID: usr.ID}
+
+		membership, err := c.membershipStore.Find(ctx, key)
+		if err != nil && !errors.Is(err, store.ErrResourceNotFound) {
+			return fmt.Errorf("failed to find membership in space %q: %w", spacePath, err)
+		}
+
+		now := time.Now().UnixMilli()
+
+		switch {
+		case membership == nil && role != "":
+			err = c.membershipStore.Create(ctx, &types.Membership{
+				MembershipKey: key,
+				CreatedBy:     syncPrincipalID,
+				Created:       now,
+				Updated:       now,
+				Role:          role,
+			})
+		case membership == nil || membership.CreatedBy != syncPrincipalID:
+			// no membership is needed or the membership is managed manually.
+			continue
+		case role == "":
+			err = c.membershipStore.Delete(ctx, key)
+		case membership.Role != role:
+			membership.Role = role
+			membership.Updated = now
+			err = c.membershipStore.Update(ctx, membership)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to sync membership in space %q: %w", spacePath, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Controller) syncUserGroups(
+	ctx context.Context,
+	usr *types.User,
+	groups []string,
+	syncPrincipalID int64,
+) error {
+	// membership of the user in every mapped usergroup.
+	wanted := make(map[int64]bool)
+	for _, m := range c.userGroupMappings {
+		userGroup, err := c.findUserGroup(ctx, m)
+		if errors.Is(err, store.ErrResourceNotFound) {
+			log.Ctx(ctx).Warn().Msgf("usergroup %q in space %q of the OIDC usergroup mapping doesn't exist",
+				m.userGroupIdentifier, m.spacePath)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		wanted[userGroup.ID] = wanted[userGroup.ID] || slices.Contains(groups, m.group)
+	}
+
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	current, err := c.userGroupMemberStore.ListByPrincipal(ctx, usr.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list usergroup memberships: %w", err)
+	}
+
+	currentByID := make(map[int64]*types.UserGroupMember, len(current))
+	for _, m := range current {
+		currentByID[m.UserGroupID] = m
+	}
+
+	for userGroupID, isWanted := range wanted {
+		member, isMember := currentByID[userGroupID]
+
+		switch {
+		case isWanted && !isMember:
+			err = c.userGroupMemberStore.Add(ctx, &types.UserGroupMember{
+				UserGroupID: userGroupID,
+				PrincipalID: usr.ID,
+				CreatedBy:   syncPrincipalID,
+				Created:     time.Now().UnixMilli(),
+				Source:      enum.UserGroupSourceOIDC,
+			})
+		case !isWanted && isMember && member.Source == enum.UserGroupSourceOIDC:
+			err = c.userGroupMemberStore.Remove(ctx, userGroupID, usr.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to sync membership of usergroup %d: %w", userGroupID, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Controller) findUserGroup(ctx context.Context, m userGroupMapping) (*types.UserGroup, error) {
+	space, err := c.spaceCache.Get(ctx, m.spacePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find space %q: %w", m.spacePath, err)
+	}
+
+	userGroup, err := c.userGroupStore.FindByIdentifier(ctx, space.ID, m.userGroupIdentifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find usergroup %q in space %q: %w", m.userGroupIdentifier, m.spacePath, err)
+	}
+
+	return userGroup, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"github.com/harness/gitness/app/api/controller/user"
+	authoidc "github.com/harness/gitness/app/auth/oidc"
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+
+	"github.com/google/wire"
+)
+
+// WireSet provides a wire set for this package.
+var WireSet = wire.NewSet(
+	ProvideController,
+)
+
+func ProvideController(
+	config *types.Config,
+	tx dbtx.Transactor,
+	provider *authoidc.Provider,
+	userCtrl *user.Controller,
+	principalStore store.PrincipalStore,
+	tokenStore store.TokenStore,
+	identityStore store.OIDCIdentityStore,
+	membershipStore store.MembershipStore,
+	userGroupStore store.UserGroupStore,
+	userGroupMemberStore store.UserGroupMemberStore,
+	spaceCache refcache.SpaceCache,
+) (*Controller, error) {
+	return NewController(
+		config,
+		tx,
+		provider,
+		userCtrl,
+		principalStore,
+		tokenStore,
+		identityStore,
+		membershipStore,
+		userGroupStore,
+		userGroupMemberStore,
+		spaceCache,
+	)
+}
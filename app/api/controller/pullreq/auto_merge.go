@@ -0,0 +1,310 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/errors"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+type AutoMergeInput struct {
+	Method  enum.MergeMethod `json:"method"`
+	Title   string           `json:"title"`
+	Message string           `json:"message"`
+}
+
+func (in *AutoMergeInput) sanitize() error {
+	method, ok := in.Method.Sanitize()
+	if !ok || method == "" {
+		return usererror.BadRequestf("unsupported merge method: %s", in.Method)
+	}
+
+	in.Method = method
+
+	// cleanup title / message (NOTE: git doesn't support white space only)
+	in.Title = strings.TrimSpace(in.Title)
+	in.Message = strings.TrimSpace(in.Message)
+
+	if (in.Method == enum.MergeMethodRebase || in.Method == enum.MergeMethodFastForward) &&
+		(in.Title != "" || in.Message != "") {
+		return usererror.BadRequestf(
+			"merge method %q doesn't support customizing commit title and message", in.Method)
+	}
+
+	return nil
+}
+
+// AutoMergeEnable enables auto-merge of a pull request. The pull request is merged on behalf of
+// the current principal with the provided merge method as soon as all merge requirements are satisfied.
+func (c *Controller) AutoMergeEnable(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pullreqNum int64,
+	in *AutoMergeInput,
+) (*types.PullReqAutoMerge, error) {
+	if err := in.sanitize(); err != nil {
+		return nil, err
+	}
+
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire access to repo: %w", err)
+	}
+
+	pr, err := c.pullreqStore.FindByNumber(ctx, repo.ID, pullreqNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request by number: %w", err)
+	}
+
+	if pr.State != enum.PullReqStateOpen {
+		return nil, usererror.BadRequest("Pull request must be open")
+	}
+
+	autoMerge := &types.PullReqAutoMerge{
+		PullReqID: pr.ID,
+		RepoID:    pr.TargetRepoID,
+		Method:    in.Method,
+		Title:     in.Title,
+		Message:   in.Message,
+		CreatedBy: session.Principal.ID,
+		Created:   time.Now().UnixMilli(),
+	}
+
+	err = c.autoMergeStore.Upsert(ctx, autoMerge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable auto-merge: %w", err)
+	}
+
+	c.writePullReqActivity(ctx, pr, session.Principal.ID,
+		&types.PullRequestActivityPayloadAutoMergeEnable{MergeMethod: in.Method})
+
+	// the merge requirements might already be satisfied
+	if err = c.AutoMerge(ctx, pr.ID); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to auto-merge pull request after enabling auto-merge")
+	}
+
+	return autoMerge, nil
+}
+
+// AutoMergeDisable disables auto-merge of a pull request.
+func (c *Controller) AutoMergeDisable(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pullreqNum int64,
+) error {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	if err != nil {
+		return fmt.Errorf("failed to acquire access to repo: %w", err)
+	}
+
+	pr, err := c.pullreqStore.FindByNumber(ctx, repo.ID, pullreqNum)
+	if err != nil {
+		return fmt.Errorf("failed to get pull request by number: %w", err)
+	}
+
+	_, err = c.autoMergeStore.Find(ctx, pr.ID)
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return usererror.NotFound("Auto-merge is not enabled for the pull request")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find auto-merge of pull request: %w", err)
+	}
+
+	err = c.autoMergeStore.Delete(ctx, pr.ID)
+	if err != nil {
+		return fmt.Errorf("failed to disable auto-merge: %w", err)
+	}
+
+	c.writePullReqActivity(ctx, pr, session.Principal.ID,
+		&types.PullRequestActivityPayloadAutoMergeDisable{Reason: enum.PullReqAutoMergeDisableReasonCancelled})
+
+	return nil
+}
+
+// AutoMerge merges the pull request if it has auto-merge enabled and all its merge requirements are satisfied.
+// The pull request is merged on behalf of the principal who enabled auto-merge. If the merge attempt fails,
+// auto-merge is disabled and the reason is posted to the pull request activity.
+func (c *Controller) AutoMerge(ctx context.Context, pullreqID int64) error {
+	autoMerge, err := c.autoMergeStore.Find(ctx, pullreqID)
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find auto-merge of pull request: %w", err)
+	}
+
+	pr, err := c.pullreqStore.Find(ctx, pullreqID)
+	if err != nil {
+		return fmt.Errorf("failed to find pull request: %w", err)
+	}
+
+	if pr.State != enum.PullReqStateOpen {
+		// the pull request got closed or merged in the meantime
+		return c.autoMergeStore.Delete(ctx, pr.ID)
+	}
+
+	if pr.IsDraft {
+		return nil
+	}
+
+	repo, err := c.repoStore.Find(ctx, pr.TargetRepoID)
+	if err != nil {
+		return fmt.Errorf("failed to find target repository: %w", err)
+	}
+
+	principal, err := c.principalStore.Find(ctx, autoMerge.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to find principal who enabled auto-merge: %w", err)
+	}
+
+	session := &auth.Session{Principal: *principal}
+
+	in := &MergeInput{
+		Method:      autoMerge.Method,
+		SourceSHA:   pr.SourceSHA,
+		Title:       autoMerge.Title,
+		Message:     autoMerge.Message,
+		DryRunRules: true,
+	}
+
+	out, _, err := c.Merge(ctx, session, repo.Path, pr.Number, in)
+	if err != nil {
+		return c.autoMergeFailed(ctx, pr, autoMerge, err)
+	}
+
+	if out.RequiresMergeQueue {
+		return c.autoMergeEnqueue(ctx, session, repo, pr, autoMerge)
+	}
+
+	if protection.IsCritical(out.RuleViolations) {
+		// the merge requirements aren't satisfied yet
+		return nil
+	}
+
+	in.DryRunRules = false
+
+	_, violations, err := c.Merge(ctx, session, repo.Path, pr.Number, in)
+	if err != nil {
+		return c.autoMergeFailed(ctx, pr, autoMerge, err)
+	}
+	if violations != nil {
+		return c.autoMergeFailed(ctx, pr, autoMerge, errors.New(violations.Message))
+	}
+
+	err = c.autoMergeStore.Delete(ctx, pr.ID)
+	if err != nil {
+		return fmt.Errorf("failed to remove auto-merge of merged pull request: %w", err)
+	}
+
+	return nil
+}
+
+// autoMergeEnqueue adds the pull request to the merge queue of the target branch
+// as soon as all merge requirements, apart from the merge queue itself, are satisfied.
+func (c *Controller) autoMergeEnqueue(
+	ctx context.Context,
+	session *auth.Session,
+	repo *types.Repository,
+	pr *types.PullReq,
+	autoMerge *types.PullReqAutoMerge,
+) error {
+	_, violations, err := c.mergeQueueAdd(ctx, session, repo, pr, &MergeQueueAddInput{
+		Method:  autoMerge.Method,
+		Title:   autoMerge.Title,
+		Message: autoMerge.Message,
+	})
+	if err != nil {
+		return c.autoMergeFailed(ctx, pr, autoMerge, err)
+	}
+	if violations != nil {
+		// the merge requirements aren't satisfied yet
+		return nil
+	}
+
+	// from now on the merge queue takes care of merging the pull request
+	err = c.autoMergeStore.Delete(ctx, pr.ID)
+	if err != nil {
+		return fmt.Errorf("failed to remove auto-merge of enqueued pull request: %w", err)
+	}
+
+	return c.MergeQueueProcess(ctx, repo.ID, pr.TargetBranch)
+}
+
+// autoMergeFailed disables auto-merge of the pull request if the error is caused by the pull request itself,
+// like merge conflicts or missing permissions of the principal who enabled auto-merge. Other errors are returned.
+func (c *Controller) autoMergeFailed(
+	ctx context.Context,
+	pr *types.PullReq,
+	autoMerge *types.PullReqAutoMerge,
+	errMerge error,
+) error {
+	var uErr *usererror.Error
+	switch {
+	case errors.As(errMerge, &uErr):
+		if errors.Is(errMerge, errNewerCommit) {
+			// the source branch got updated in the meantime, the branch update triggers another attempt
+			return nil
+		}
+	case errors.Is(errMerge, apiauth.ErrNotAuthorized):
+		errMerge = errors.New("The principal who enabled auto-merge isn't allowed to merge the pull request.")
+	default:
+		return errMerge
+	}
+
+	err := c.autoMergeStore.Delete(ctx, pr.ID)
+	if err != nil {
+		return fmt.Errorf("failed to disable auto-merge: %w", err)
+	}
+
+	c.writePullReqActivity(ctx, pr, autoMerge.CreatedBy, &types.PullRequestActivityPayloadAutoMergeDisable{
+		Reason: enum.PullReqAutoMergeDisableReasonFailed,
+		Error:  errMerge.Error(),
+	})
+
+	return nil
+}
+
+func (c *Controller) writePullReqActivity(
+	ctx context.Context,
+	pr *types.PullReq,
+	principalID int64,
+	payload types.PullReqActivityPayload,
+) {
+	var err error
+	if pr, err = c.pullreqStore.UpdateActivitySeq(ctx, pr); err != nil {
+		log.Ctx(ctx).Err(err).Msg("failed to update pull request activity sequence")
+		return
+	}
+
+	_, err = c.activityStore.CreateWithPayload(ctx, pr, principalID, payload, nil)
+	if err != nil {
+		log.Ctx(ctx).Err(err).Msgf("failed to write pull request activity %q", payload.ActivityType())
+	}
+}
@@ -0,0 +1,328 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAutoMergeInputSanitize(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   AutoMergeInput
+		wantErr bool
+	}{
+		{
+			name:  "squash-with-title",
+			input: AutoMergeInput{Method: enum.MergeMethodSquash, Title: " title ", Message: "message"},
+		},
+		{
+			name:  "rebase",
+			input: AutoMergeInput{Method: enum.MergeMethodRebase},
+		},
+		{
+			name:    "missing-method",
+			input:   AutoMergeInput{},
+			wantErr: true,
+		},
+		{
+			name:    "unknown-method",
+			input:   AutoMergeInput{Method: "octopus"},
+			wantErr: true,
+		},
+		{
+			name:    "fast-forward-with-title",
+			input:   AutoMergeInput{Method: enum.MergeMethodFastForward, Title: "title"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			in := test.input
+			err := in.sanitize()
+			if test.wantErr != (err != nil) {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if err == nil && in.Title != "" && in.Title != "title" {
+				t.Errorf("title not trimmed: %q", in.Title)
+			}
+		})
+	}
+}
+
+func TestControllerAutoMerge(t *testing.T) {
+	tests := []struct {
+		name       string
+		pr         types.PullReq
+		autoMerge  bool
+		expEnabled bool
+	}{
+		{
+			name: "not-enabled",
+			pr:   types.PullReq{ID: 1, State: enum.PullReqStateOpen},
+		},
+		{
+			name:      "closed",
+			pr:        types.PullReq{ID: 1, State: enum.PullReqStateClosed},
+			autoMerge: true,
+		},
+		{
+			name:      "merged",
+			pr:        types.PullReq{ID: 1, State: enum.PullReqStateMerged},
+			autoMerge: true,
+		},
+		{
+			name:       "draft",
+			pr:         types.PullReq{ID: 1, State: enum.PullReqStateOpen, IsDraft: true},
+			autoMerge:  true,
+			expEnabled: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			autoMergeStore := &fakeAutoMergeStore{autoMerges: map[int64]*types.PullReqAutoMerge{}}
+			if test.autoMerge {
+				autoMergeStore.autoMerges[test.pr.ID] = &types.PullReqAutoMerge{PullReqID: test.pr.ID}
+			}
+
+			pr := test.pr
+			c := &Controller{
+				autoMergeStore: autoMergeStore,
+				pullreqStore:   &fakeAutoMergePullReqStore{pr: &pr},
+			}
+
+			if err := c.AutoMerge(context.Background(), test.pr.ID); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if _, enabled := autoMergeStore.autoMerges[test.pr.ID]; enabled != test.expEnabled {
+				t.Errorf("expected auto-merge enabled to be %t", test.expEnabled)
+			}
+		})
+	}
+}
+
+func TestControllerAutoMergeFailed(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		expErr     bool
+		expEnabled bool
+		expReason  string
+	}{
+		{
+			name:      "merge-conflict",
+			err:       usererror.BadRequest("Pull request has merge conflicts."),
+			expReason: "Pull request has merge conflicts.",
+		},
+		{
+			name:      "not-authorized",
+			err:       fmt.Errorf("access check failed: %w", apiauth.ErrNotAuthorized),
+			expReason: "The principal who enabled auto-merge isn't allowed to merge the pull request.",
+		},
+		{
+			name:       "newer-commit",
+			err:        errNewerCommit,
+			expEnabled: true,
+		},
+		{
+			name:       "internal-error",
+			err:        errors.New("git is unavailable"),
+			expErr:     true,
+			expEnabled: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pr := &types.PullReq{ID: 1, State: enum.PullReqStateOpen}
+			autoMerge := &types.PullReqAutoMerge{PullReqID: pr.ID, CreatedBy: 5}
+			autoMergeStore := &fakeAutoMergeStore{
+				autoMerges: map[int64]*types.PullReqAutoMerge{pr.ID: autoMerge},
+			}
+			activityStore := &fakeAutoMergeActivityStore{}
+
+			c := &Controller{
+				autoMergeStore: autoMergeStore,
+				pullreqStore:   &fakeAutoMergePullReqStore{pr: pr},
+				activityStore:  activityStore,
+			}
+
+			err := c.autoMergeFailed(context.Background(), pr, autoMerge, test.err)
+			if test.expErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if _, enabled := autoMergeStore.autoMerges[pr.ID]; enabled != test.expEnabled {
+				t.Errorf("expected auto-merge enabled to be %t", test.expEnabled)
+			}
+
+			if test.expReason == "" {
+				if len(activityStore.payloads) != 0 {
+					t.Errorf("expected no activity, got %d", len(activityStore.payloads))
+				}
+				return
+			}
+
+			exp := []types.PullReqActivityPayload{
+				&types.PullRequestActivityPayloadAutoMergeDisable{
+					Reason: enum.PullReqAutoMergeDisableReasonFailed,
+					Error:  test.expReason,
+				},
+			}
+			if diff := cmp.Diff(exp, activityStore.payloads); diff != "" {
+				t.Errorf("activity mismatch (-want +got):\n%s", diff)
+			}
+			if activityStore.principalID != autoMerge.CreatedBy {
+				t.Errorf("expected the activity to be written by %d, got %d",
+					autoMerge.CreatedBy, activityStore.principalID)
+			}
+		})
+	}
+}
+
+type fakeAutoMergeStore struct {
+	store.PullReqAutoMergeStore
+	autoMerges map[int64]*types.PullReqAutoMerge
+}
+
+func (f *fakeAutoMergeStore) Find(_ context.Context, pullreqID int64) (*types.PullReqAutoMerge, error) {
+	autoMerge, ok := f.autoMerges[pullreqID]
+	if !ok {
+		return nil, gitness_store.ErrResourceNotFound
+	}
+	return autoMerge, nil
+}
+
+func (f *fakeAutoMergeStore) Delete(_ context.Context, pullreqID int64) error {
+	delete(f.autoMerges, pullreqID)
+	return nil
+}
+
+type fakeAutoMergePullReqStore struct {
+	store.PullReqStore
+	pr *types.PullReq
+}
+
+func (f *fakeAutoMergePullReqStore) Find(context.Context, int64) (*types.PullReq, error) {
+	return f.pr, nil
+}
+
+func (f *fakeAutoMergePullReqStore) FindByNumber(context.Context, int64, int64) (*types.PullReq, error) {
+	return f.pr, nil
+}
+
+func (f *fakeAutoMergePullReqStore) UpdateActivitySeq(_ context.Context, pr *types.PullReq) (*types.PullReq, error) {
+	pr.ActivitySeq++
+	return pr, nil
+}
+
+type fakeAutoMergeActivityStore struct {
+	store.PullReqActivityStore
+	principalID int64
+	payloads    []types.PullReqActivityPayload
+}
+
+func (f *fakeAutoMergeActivityStore) CreateWithPayload(
+	_ context.Context,
+	_ *types.PullReq,
+	principalID int64,
+	payload types.PullReqActivityPayload,
+	_ *types.PullReqActivityMetadata,
+) (*types.PullReqActivity, error) {
+	f.principalID = principalID
+	f.payloads = append(f.payloads, payload)
+	return &types.PullReqActivity{}, nil
+}
+
+func TestControllerAutoMergeDisable(t *testing.T) {
+	pr := &types.PullReq{ID: 1, Number: 3, State: enum.PullReqStateOpen}
+	autoMergeStore := &fakeAutoMergeStore{
+		autoMerges: map[int64]*types.PullReqAutoMerge{pr.ID: {PullReqID: pr.ID, CreatedBy: 5}},
+	}
+	activityStore := &fakeAutoMergeActivityStore{}
+
+	c := &Controller{
+		authorizer:     fakeAutoMergeAuthorizer{},
+		repoFinder:     refcache.NewRepoFinder(fakeAutoMergeRepoStore{}, nil),
+		autoMergeStore: autoMergeStore,
+		pullreqStore:   &fakeAutoMergePullReqStore{pr: pr},
+		activityStore:  activityStore,
+	}
+
+	session := &auth.Session{Principal: types.Principal{ID: 7}}
+
+	if err := c.AutoMergeDisable(context.Background(), session, "1", pr.Number); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, enabled := autoMergeStore.autoMerges[pr.ID]; enabled {
+		t.Error("expected auto-merge to be disabled")
+	}
+
+	exp := []types.PullReqActivityPayload{
+		&types.PullRequestActivityPayloadAutoMergeDisable{Reason: enum.PullReqAutoMergeDisableReasonCancelled},
+	}
+	if diff := cmp.Diff(exp, activityStore.payloads); diff != "" {
+		t.Errorf("activity mismatch (-want +got):\n%s", diff)
+	}
+	if activityStore.principalID != session.Principal.ID {
+		t.Errorf("expected the activity to be written by %d, got %d", session.Principal.ID, activityStore.principalID)
+	}
+
+	err := c.AutoMergeDisable(context.Background(), session, "1", pr.Number)
+	var uErr *usererror.Error
+	if !errors.As(err, &uErr) || uErr.Status != http.StatusNotFound {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+type fakeAutoMergeAuthorizer struct{}
+
+func (fakeAutoMergeAuthorizer) Check(
+	context.Context, *auth.Session, *types.Scope, *types.Resource, enum.Permission,
+) (bool, error) {
+	return true, nil
+}
+
+func (fakeAutoMergeAuthorizer) CheckAll(context.Context, *auth.Session, ...types.PermissionCheck) (bool, error) {
+	return true, nil
+}
+
+type fakeAutoMergeRepoStore struct {
+	store.RepoStore
+}
+
+func (fakeAutoMergeRepoStore) Find(_ context.Context, id int64) (*types.Repository, error) {
+	return &types.Repository{ID: id, Path: "space/repo", State: enum.RepoStateActive}, nil
+}
@@ -31,6 +31,7 @@ import (
 	locker "github.com/harness/gitness/app/services/locker"
 	"github.com/harness/gitness/app/services/migrate"
 	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/app/services/publickey"
 	"github.com/harness/gitness/app/services/pullreq"
 	"github.com/harness/gitness/app/services/refcache"
 	"github.com/harness/gitness/app/services/usergroup"
@@ -65,6 +66,8 @@ type Controller struct {
 	fileViewStore          store.PullReqFileViewStore
 	membershipStore        store.MembershipStore
 	checkStore             store.CheckStore
+	autoMergeStore         store.PullReqAutoMergeStore
+	mergeQueueStore        store.MergeQueueEntryStore
 	git                    git.Interface
 	repoFinder             refcache.RepoFinder
 	eventReporter          *pullreqevents.Reporter
@@ -79,6 +82,7 @@ type Controller struct {
 	labelSvc               *label.Service
 	instrumentation        instrument.Service
 	userGroupService       usergroup.SearchService
+	publicKeySvc           publickey.Service
 }
 
 func NewController(
@@ -99,6 +103,8 @@ func NewController(
 	fileViewStore store.PullReqFileViewStore,
 	membershipStore store.MembershipStore,
 	checkStore store.CheckStore,
+	autoMergeStore store.PullReqAutoMergeStore,
+	mergeQueueStore store.MergeQueueEntryStore,
 	git git.Interface,
 	repoFinder refcache.RepoFinder,
 	eventReporter *pullreqevents.Reporter,
@@ -113,6 +119,7 @@ func NewController(
 	labelSvc *label.Service,
 	instrumentation instrument.Service,
 	userGroupService usergroup.SearchService,
+	publicKeySvc publickey.Service,
 ) *Controller {
 	return &Controller{
 		tx:                     tx,
@@ -132,6 +139,8 @@ func NewController(
 		fileViewStore:          fileViewStore,
 		membershipStore:        membershipStore,
 		checkStore:             checkStore,
+		autoMergeStore:         autoMergeStore,
+		mergeQueueStore:        mergeQueueStore,
 		git:                    git,
 		repoFinder:             repoFinder,
 		codeCommentMigrator:    codeCommentMigrator,
@@ -146,6 +155,7 @@ func NewController(
 		labelSvc:               labelSvc,
 		instrumentation:        instrumentation,
 		userGroupService:       userGroupService,
+		publicKeySvc:           publicKeySvc,
 	}
 }
 
@@ -0,0 +1,73 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"context"
+	"fmt"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/controller"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/git/sha"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+// checkPullReqEditAccess checks if the principal is allowed to edit the pull request.
+// Apart from principals with push access to the target repository,
+// authors of pull requests opened from a fork are allowed to edit their pull requests.
+func (c *Controller) checkPullReqEditAccess(
+	ctx context.Context,
+	session *auth.Session,
+	targetRepo *types.Repository,
+	pr *types.PullReq,
+) error {
+	if pr.SourceRepoID != pr.TargetRepoID && pr.CreatedBy == session.Principal.ID {
+		return nil
+	}
+
+	return apiauth.CheckRepo(ctx, c.authorizer, session, targetRepo, enum.PermissionRepoPush)
+}
+
+// fetchSourceObjects copies the source commit of a pull request opened from a fork into the target repository.
+func (c *Controller) fetchSourceObjects(
+	ctx context.Context,
+	session *auth.Session,
+	sourceRepo *types.Repository,
+	targetRepo *types.Repository,
+	sourceSHA sha.SHA,
+) error {
+	if sourceRepo.ID == targetRepo.ID {
+		return nil
+	}
+
+	writeParams, err := controller.CreateRPCInternalWriteParams(ctx, c.urlProvider, session, targetRepo)
+	if err != nil {
+		return fmt.Errorf("failed to create RPC write params: %w", err)
+	}
+
+	err = c.git.FetchObjects(ctx, &git.FetchObjectsParams{
+		WriteParams: writeParams,
+		Source:      sourceRepo.GitUID,
+		ObjectSHAs:  []sha.SHA{sourceSHA},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch source commit from the fork: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,268 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/git/sha"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+const (
+	forkTestMainSHA    = "1111111111111111111111111111111111111111"
+	forkTestFeatureSHA = "2222222222222222222222222222222222222222"
+)
+
+var (
+	forkTestUpstream  = &types.Repository{ID: 1, Path: "space/upstream", GitUID: "upstream-uid"}
+	forkTestFork      = &types.Repository{ID: 2, Path: "other/fork", GitUID: "fork-uid", ForkID: 1}
+	forkTestUnrelated = &types.Repository{ID: 3, Path: "other/unrelated", GitUID: "unrelated-uid"}
+)
+
+func TestCheckPullReqEditAccess(t *testing.T) {
+	tests := []struct {
+		name        string
+		pr          *types.PullReq
+		permissions map[string][]enum.Permission
+		expErr      error
+	}{
+		{
+			name: "author-of-pullreq-from-fork",
+			pr:   &types.PullReq{SourceRepoID: forkTestFork.ID, TargetRepoID: forkTestUpstream.ID, CreatedBy: 7},
+		},
+		{
+			name:   "other-principal-pullreq-from-fork",
+			pr:     &types.PullReq{SourceRepoID: forkTestFork.ID, TargetRepoID: forkTestUpstream.ID, CreatedBy: 8},
+			expErr: apiauth.ErrNotAuthorized,
+		},
+		{
+			name: "maintainer-pullreq-from-fork",
+			pr:   &types.PullReq{SourceRepoID: forkTestFork.ID, TargetRepoID: forkTestUpstream.ID, CreatedBy: 8},
+			permissions: map[string][]enum.Permission{
+				forkTestUpstream.Path: {enum.PermissionRepoPush},
+			},
+		},
+		{
+			name:   "author-of-pullreq-within-repo",
+			pr:     &types.PullReq{SourceRepoID: forkTestUpstream.ID, TargetRepoID: forkTestUpstream.ID, CreatedBy: 7},
+			expErr: apiauth.ErrNotAuthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &Controller{authorizer: fakeRepoPermissionAuthorizer{permissions: test.permissions}}
+			session := &auth.Session{Principal: types.Principal{ID: 7}}
+
+			err := c.checkPullReqEditAccess(context.Background(), session, forkTestUpstream, test.pr)
+			if !errors.Is(err, test.expErr) {
+				t.Errorf("expected error %v, got %v", test.expErr, err)
+			}
+		})
+	}
+}
+
+//nolint:gocognit
+func TestCreateCrossRepo(t *testing.T) {
+	// the fake git reports the source branch as merged already, which stops the creation after the access checks.
+	const errNoNewCommits = "The source branch doesn't contain any new commits"
+
+	tests := []struct {
+		name          string
+		sourceRepoRef string
+		permissions   map[string][]enum.Permission
+		expErr        error
+		expUserErr    string
+		expFetched    bool
+	}{
+		{
+			name:          "from-fork-with-read-access-to-upstream",
+			sourceRepoRef: "2",
+			permissions: map[string][]enum.Permission{
+				forkTestUpstream.Path: {enum.PermissionRepoView},
+				forkTestFork.Path:     {enum.PermissionRepoView, enum.PermissionRepoPush},
+			},
+			expUserErr: errNoNewCommits,
+			expFetched: true,
+		},
+		{
+			name:          "from-fork-without-push-access-to-fork",
+			sourceRepoRef: "2",
+			permissions: map[string][]enum.Permission{
+				forkTestUpstream.Path: {enum.PermissionRepoView},
+				forkTestFork.Path:     {enum.PermissionRepoView},
+			},
+			expErr: apiauth.ErrNotAuthorized,
+		},
+		{
+			name:          "from-unrelated-repo",
+			sourceRepoRef: "3",
+			permissions: map[string][]enum.Permission{
+				forkTestUpstream.Path:  {enum.PermissionRepoView, enum.PermissionRepoPush},
+				forkTestUnrelated.Path: {enum.PermissionRepoView, enum.PermissionRepoPush},
+			},
+			expUserErr: "Pull requests can only be opened from a fork into its upstream repository",
+		},
+		{
+			name: "within-repo-without-push-access",
+			permissions: map[string][]enum.Permission{
+				forkTestUpstream.Path: {enum.PermissionRepoView},
+			},
+			expErr: apiauth.ErrNotAuthorized,
+		},
+		{
+			name: "within-repo-with-push-access",
+			permissions: map[string][]enum.Permission{
+				forkTestUpstream.Path: {enum.PermissionRepoView, enum.PermissionRepoPush},
+			},
+			expUserErr: errNoNewCommits,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gitService := &fakeForkGit{}
+			repoStore := fakeForkRepoStore{}
+
+			c := &Controller{
+				urlProvider:  newTestURLProvider(t),
+				authorizer:   fakeRepoPermissionAuthorizer{permissions: test.permissions},
+				repoFinder:   refcache.NewRepoFinder(repoStore, nil),
+				pullreqStore: fakeForkPullReqStore{},
+				git:          gitService,
+			}
+			session := &auth.Session{Principal: types.Principal{ID: 7}}
+
+			_, err := c.Create(context.Background(), session, "1", &CreateInput{
+				Title:         "title",
+				SourceRepoRef: test.sourceRepoRef,
+				SourceBranch:  "feature",
+				TargetBranch:  "main",
+			})
+
+			var uErr *usererror.Error
+			switch {
+			case test.expUserErr != "":
+				if !errors.As(err, &uErr) || uErr.Status != http.StatusBadRequest || uErr.Message != test.expUserErr {
+					t.Fatalf("expected bad request %q, got %v", test.expUserErr, err)
+				}
+			case !errors.Is(err, test.expErr):
+				t.Fatalf("expected error %v, got %v", test.expErr, err)
+			}
+
+			if test.expFetched != (gitService.fetched != nil) {
+				t.Fatalf("expected source objects fetched to be %t", test.expFetched)
+			}
+			if !test.expFetched {
+				return
+			}
+
+			if gitService.fetched.RepoUID != forkTestUpstream.GitUID || gitService.fetched.Source != forkTestFork.GitUID {
+				t.Errorf("expected objects to be fetched from %s into %s, got from %s into %s",
+					forkTestFork.GitUID, forkTestUpstream.GitUID, gitService.fetched.Source, gitService.fetched.RepoUID)
+			}
+			if len(gitService.fetched.ObjectSHAs) != 1 || gitService.fetched.ObjectSHAs[0].String() != forkTestFeatureSHA {
+				t.Errorf("expected the source commit to be fetched, got %v", gitService.fetched.ObjectSHAs)
+			}
+			if gitService.mergeBaseRepoUID != forkTestUpstream.GitUID {
+				t.Errorf("expected the merge base to be computed in the target repository, got %s",
+					gitService.mergeBaseRepoUID)
+			}
+		})
+	}
+}
+
+// fakeRepoPermissionAuthorizer grants the provided permissions on the repositories with the provided paths.
+type fakeRepoPermissionAuthorizer struct {
+	permissions map[string][]enum.Permission
+}
+
+func (f fakeRepoPermissionAuthorizer) Check(
+	_ context.Context,
+	_ *auth.Session,
+	scope *types.Scope,
+	resource *types.Resource,
+	permission enum.Permission,
+) (bool, error) {
+	for _, p := range f.permissions[scope.SpacePath+"/"+resource.Identifier] {
+		if p == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f fakeRepoPermissionAuthorizer) CheckAll(context.Context, *auth.Session, ...types.PermissionCheck) (bool, error) {
+	return false, nil
+}
+
+type fakeForkRepoStore struct {
+	store.RepoStore
+}
+
+func (fakeForkRepoStore) Find(_ context.Context, id int64) (*types.Repository, error) {
+	for _, repo := range []*types.Repository{forkTestUpstream, forkTestFork, forkTestUnrelated} {
+		if repo.ID == id {
+			r := *repo
+			r.State = enum.RepoStateActive
+			return &r, nil
+		}
+	}
+	return nil, gitness_store.ErrResourceNotFound
+}
+
+type fakeForkPullReqStore struct {
+	store.PullReqStore
+}
+
+func (fakeForkPullReqStore) List(context.Context, *types.PullReqFilter) ([]*types.PullReq, error) {
+	return nil, nil
+}
+
+type fakeForkGit struct {
+	git.Interface
+	fetched          *git.FetchObjectsParams
+	mergeBaseRepoUID string
+}
+
+func (f *fakeForkGit) GetRef(_ context.Context, params git.GetRefParams) (git.GetRefResponse, error) {
+	if params.Name == "feature" {
+		return git.GetRefResponse{SHA: sha.Must(forkTestFeatureSHA)}, nil
+	}
+	return git.GetRefResponse{SHA: sha.Must(forkTestMainSHA)}, nil
+}
+
+func (f *fakeForkGit) FetchObjects(_ context.Context, params *git.FetchObjectsParams) error {
+	f.fetched = params
+	return nil
+}
+
+func (f *fakeForkGit) MergeBase(_ context.Context, params git.MergeBaseParams) (git.MergeBaseOutput, error) {
+	f.mergeBaseRepoUID = params.RepoUID
+	// the source branch is already part of the target branch
+	return git.MergeBaseOutput{MergeBaseSHA: sha.Must(params.Ref1)}, nil
+}
@@ -42,6 +42,8 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+var errNewerCommit = usererror.BadRequest("A newer commit is available. Only the latest commit can be merged.")
+
 type MergeInput struct {
 	Method    enum.MergeMethod `json:"method"`
 	SourceSHA string           `json:"source_sha"`
@@ -51,6 +53,11 @@ type MergeInput struct {
 	BypassRules bool `json:"bypass_rules"`
 	DryRun      bool `json:"dry_run"`
 	DryRunRules bool `json:"dry_run_rules"`
+
+	// mergeQueue is set if the merge queue adds or merges the pull request.
+	mergeQueue bool
+	// mergeQueueSHA is the tested speculative merge commit of the merge queue the target branch is fast-forwarded to.
+	mergeQueueSHA string
 }
 
 func (in *MergeInput) sanitize() error {
@@ -156,8 +163,7 @@ func (c *Controller) Merge(
 	}
 
 	if pr.SourceSHA != in.SourceSHA {
-		return nil, nil,
-			usererror.BadRequest("A newer commit is available. Only the latest commit can be merged.")
+		return nil, nil, errNewerCommit
 	}
 
 	if pr.IsDraft && !in.DryRunRules && !in.DryRun {
@@ -179,14 +185,14 @@ func (c *Controller) Merge(
 	sourceRepo := targetRepo
 	sourceWriteParams := targetWriteParams
 	if pr.SourceRepoID != pr.TargetRepoID {
-		sourceWriteParams, err = controller.CreateRPCInternalWriteParams(ctx, c.urlProvider, session, sourceRepo)
+		sourceRepo, err = c.repoStore.Find(ctx, pr.SourceRepoID)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create RPC write params: %w", err)
+			return nil, nil, fmt.Errorf("failed to get source repository: %w", err)
 		}
 
-		sourceRepo, err = c.repoStore.Find(ctx, pr.SourceRepoID)
+		sourceWriteParams, err = controller.CreateRPCInternalWriteParams(ctx, c.urlProvider, session, sourceRepo)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get source repository: %w", err)
+			return nil, nil, fmt.Errorf("failed to create RPC write params: %w", err)
 		}
 	}
 
@@ -195,29 +201,38 @@ func (c *Controller) Merge(
 		return nil, nil, fmt.Errorf("failed to fetch rules: %w", err)
 	}
 
-	checkResults, err := c.checkStore.ListResults(ctx, targetRepo.ID, pr.SourceSHA)
+	// the merge queue requires the status checks to pass on its speculative merge commit
+	checkSHA := pr.SourceSHA
+	if in.mergeQueueSHA != "" {
+		checkSHA = in.mergeQueueSHA
+	}
+
+	checkResults, err := c.checkStore.ListResults(ctx, targetRepo.ID, checkSHA)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to list status checks: %w", err)
 	}
 
-	codeOwnerWithApproval, err := c.codeOwners.Evaluate(ctx, sourceRepo, pr, reviewers)
+	// code owners are always taken from the target repository, a fork must not be able to change them.
+	codeOwnerWithApproval, err := c.codeOwners.Evaluate(ctx, targetRepo, pr, reviewers)
 	// check for error and ignore if it is codeowners file not found else throw error
 	if err != nil && !errors.Is(err, codeowners.ErrNotFound) {
 		return nil, nil, fmt.Errorf("CODEOWNERS evaluation failed: %w", err)
 	}
 
 	ruleOut, violations, err := protectionRules.MergeVerify(ctx, protection.MergeVerifyInput{
-		ResolveUserGroupID: c.userGroupService.ListUserIDsByGroupIDs,
-		Actor:              &session.Principal,
-		AllowBypass:        in.BypassRules,
-		IsRepoOwner:        isRepoOwner,
-		TargetRepo:         targetRepo,
-		SourceRepo:         sourceRepo,
-		PullReq:            pr,
-		Reviewers:          reviewers,
-		Method:             in.Method, // the method can be empty for dry run or dry run rules
-		CheckResults:       checkResults,
-		CodeOwners:         codeOwnerWithApproval,
+		ResolveUserGroupID:       c.userGroupService.ListUserIDsByGroupIDs,
+		ResolveUnverifiedCommits: c.unverifiedCommitsResolver(targetRepo, pr),
+		Actor:                    &session.Principal,
+		AllowBypass:              in.BypassRules,
+		IsRepoOwner:              isRepoOwner,
+		TargetRepo:               targetRepo,
+		SourceRepo:               sourceRepo,
+		PullReq:                  pr,
+		Reviewers:                reviewers,
+		Method:                   in.Method, // the method can be empty for dry run or dry run rules
+		CheckResults:             checkResults,
+		CodeOwners:               codeOwnerWithApproval,
+		MergeQueue:               in.mergeQueue,
 	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to verify protection rules: %w", err)
@@ -234,6 +249,8 @@ func (c *Controller) Merge(
 			RequiresCodeOwnersApprovalLatest:    ruleOut.RequiresCodeOwnersApprovalLatest,
 			RequiresCommentResolution:           ruleOut.RequiresCommentResolution,
 			RequiresNoChangeRequests:            ruleOut.RequiresNoChangeRequests,
+			RequiresSignedCommits:               ruleOut.RequiresSignedCommits,
+			RequiresMergeQueue:                  ruleOut.RequiresMergeQueue,
 			MinimumRequiredApprovalsCount:       ruleOut.MinimumRequiredApprovalsCount,
 			MinimumRequiredApprovalsCountLatest: ruleOut.MinimumRequiredApprovalsCountLatest,
 		}, nil, nil
@@ -353,6 +370,8 @@ func (c *Controller) Merge(
 			RequiresCodeOwnersApprovalLatest:    ruleOut.RequiresCodeOwnersApprovalLatest,
 			RequiresCommentResolution:           ruleOut.RequiresCommentResolution,
 			RequiresNoChangeRequests:            ruleOut.RequiresNoChangeRequests,
+			RequiresSignedCommits:               ruleOut.RequiresSignedCommits,
+			RequiresMergeQueue:                  ruleOut.RequiresMergeQueue,
 			MinimumRequiredApprovalsCount:       ruleOut.MinimumRequiredApprovalsCount,
 			MinimumRequiredApprovalsCountLatest: ruleOut.MinimumRequiredApprovalsCountLatest,
 		}
@@ -387,46 +406,15 @@ func (c *Controller) Merge(
 
 	// commit details: author, committer and message
 
-	var author *git.Identity
-
-	switch in.Method {
-	case enum.MergeMethodMerge:
-		author = controller.IdentityFromPrincipalInfo(*session.Principal.ToPrincipalInfo())
-	case enum.MergeMethodSquash:
-		author = controller.IdentityFromPrincipalInfo(pr.Author)
-	case enum.MergeMethodRebase, enum.MergeMethodFastForward:
-		author = nil // Not important for these merge methods: the author info in the commits will be preserved.
-	}
-
-	var committer *git.Identity
-
-	switch in.Method {
-	case enum.MergeMethodMerge, enum.MergeMethodSquash:
-		committer = controller.SystemServicePrincipalInfo()
-	case enum.MergeMethodRebase:
-		committer = controller.IdentityFromPrincipalInfo(*session.Principal.ToPrincipalInfo())
-	case enum.MergeMethodFastForward:
-		committer = nil // Not important for fast-forward merge
-	}
-
-	// backfill commit title if none provided
-	if in.Title == "" {
-		switch in.Method {
-		case enum.MergeMethodMerge:
-			in.Title = fmt.Sprintf("Merge branch '%s' of %s (#%d)", pr.SourceBranch, sourceRepo.Path, pr.Number)
-		case enum.MergeMethodSquash:
-			in.Title = fmt.Sprintf("%s (#%d)", pr.Title, pr.Number)
-		case enum.MergeMethodRebase, enum.MergeMethodFastForward:
-			// Not used.
-		}
-	}
+	author, committer, title := mergeCommitDetails(in.Method, &session.Principal, pr, sourceRepo, in.Title)
+	in.Title = title
 
 	// create merge commit(s)
 
 	log.Ctx(ctx).Debug().Msgf("all pre-check passed, merge PR")
 
 	now := time.Now()
-	mergeOutput, err := c.git.Merge(ctx, &git.MergeParams{
+	mergeParams := &git.MergeParams{
 		WriteParams:     targetWriteParams,
 		BaseBranch:      pr.TargetBranch,
 		HeadRepoUID:     sourceRepo.GitUID,
@@ -440,10 +428,22 @@ func (c *Controller) Merge(
 		RefName:         pr.TargetBranch,
 		HeadExpectedSHA: sha.Must(in.SourceSHA),
 		Method:          gitenum.MergeMethod(in.Method),
-	})
+	}
+	if in.mergeQueueSHA != "" {
+		// the merge commit has already been created and tested by the merge queue
+		mergeParams.HeadRepoUID = targetRepo.GitUID
+		mergeParams.HeadBranch = in.mergeQueueSHA
+		mergeParams.HeadExpectedSHA = sha.Must(in.mergeQueueSHA)
+		mergeParams.Method = gitenum.MergeMethodFastForward
+	}
+
+	mergeOutput, err := c.git.Merge(ctx, mergeParams)
 	if err != nil {
 		return nil, nil, fmt.Errorf("merge execution failed: %w", err)
 	}
+	if in.mergeQueueSHA != "" {
+		mergeOutput.HeadSHA = sha.Must(in.SourceSHA)
+	}
 	//nolint:nestif
 	if mergeOutput.MergeSHA.String() == "" || len(mergeOutput.ConflictFiles) > 0 {
 		_, err = c.pullreqStore.UpdateOptLock(ctx, pr, func(pr *types.PullReq) error {
@@ -625,3 +625,49 @@ func (c *Controller) Merge(
 		RuleViolations: violations,
 	}, nil, nil
 }
+
+// mergeCommitDetails returns the author, the committer and the title of the commit(s)
+// created by merging the pull request with the provided merge method.
+func mergeCommitDetails(
+	method enum.MergeMethod,
+	merger *types.Principal,
+	pr *types.PullReq,
+	sourceRepo *types.Repository,
+	title string,
+) (*git.Identity, *git.Identity, string) {
+	var author *git.Identity
+
+	switch method {
+	case enum.MergeMethodMerge:
+		author = controller.IdentityFromPrincipalInfo(*merger.ToPrincipalInfo())
+	case enum.MergeMethodSquash:
+		author = controller.IdentityFromPrincipalInfo(pr.Author)
+	case enum.MergeMethodRebase, enum.MergeMethodFastForward:
+		author = nil // Not important for these merge methods: the author info in the commits will be preserved.
+	}
+
+	var committer *git.Identity
+
+	switch method {
+	case enum.MergeMethodMerge, enum.MergeMethodSquash:
+		committer = controller.SystemServicePrincipalInfo()
+	case enum.MergeMethodRebase:
+		committer = controller.IdentityFromPrincipalInfo(*merger.ToPrincipalInfo())
+	case enum.MergeMethodFastForward:
+		committer = nil // Not important for fast-forward merge
+	}
+
+	// backfill commit title if none provided
+	if title == "" {
+		switch method {
+		case enum.MergeMethodMerge:
+			title = fmt.Sprintf("Merge branch '%s' of %s (#%d)", pr.SourceBranch, sourceRepo.Path, pr.Number)
+		case enum.MergeMethodSquash:
+			title = fmt.Sprintf("%s (#%d)", pr.Title, pr.Number)
+		case enum.MergeMethodRebase, enum.MergeMethodFastForward:
+			// Not used.
+		}
+	}
+
+	return author, committer, title
+}
@@ -0,0 +1,606 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/controller"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	pullreqevents "github.com/harness/gitness/app/events/pullreq"
+	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git"
+	gitenum "github.com/harness/gitness/git/enum"
+	"github.com/harness/gitness/git/sha"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// mergeQueueRefPrefix is the prefix of the references holding the speculative merge commits of the merge queue.
+	mergeQueueRefPrefix = "refs/merge-queue/"
+
+	// mergeQueueLockTimeout is the max time processing of a merge queue is allowed to take.
+	mergeQueueLockTimeout = 5 * time.Minute
+
+	// mergeQueueTestingTimeout is the max time the required status checks are allowed to take
+	// on a speculative merge commit before the entry is removed from the merge queue.
+	mergeQueueTestingTimeout = 2 * time.Hour
+)
+
+func mergeQueueRef(entryID int64) string {
+	return mergeQueueRefPrefix + strconv.FormatInt(entryID, 10)
+}
+
+type MergeQueueAddInput AutoMergeInput
+
+func (in *MergeQueueAddInput) sanitize() error {
+	return (*AutoMergeInput)(in).sanitize()
+}
+
+// MergeQueueAdd adds a pull request to the merge queue of its target branch.
+// The pull request is merged on behalf of the current principal with the provided merge method
+// once the required status checks pass on its speculative merge commit.
+func (c *Controller) MergeQueueAdd(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pullreqNum int64,
+	in *MergeQueueAddInput,
+) (*types.MergeQueueEntry, *types.MergeViolations, error) {
+	if err := in.sanitize(); err != nil {
+		return nil, nil, err
+	}
+
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to acquire access to repo: %w", err)
+	}
+
+	pr, err := c.pullreqStore.FindByNumber(ctx, repo.ID, pullreqNum)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get pull request by number: %w", err)
+	}
+
+	entry, violations, err := c.mergeQueueAdd(ctx, session, repo, pr, in)
+	if err != nil || violations != nil {
+		return nil, violations, err
+	}
+
+	// the pull request might be the only one in the queue
+	if err = c.MergeQueueProcess(ctx, repo.ID, pr.TargetBranch); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to process merge queue after adding a pull request")
+	}
+
+	return entry, nil, nil
+}
+
+func (c *Controller) mergeQueueAdd(
+	ctx context.Context,
+	session *auth.Session,
+	repo *types.Repository,
+	pr *types.PullReq,
+	in *MergeQueueAddInput,
+) (*types.MergeQueueEntry, *types.MergeViolations, error) {
+	if pr.State != enum.PullReqStateOpen {
+		return nil, nil, usererror.BadRequest("Pull request must be open")
+	}
+
+	if pr.IsDraft {
+		return nil, nil, usererror.BadRequest(
+			"Draft pull requests can't be added to the merge queue. Clear the draft flag first.")
+	}
+
+	_, err := c.mergeQueueStore.FindByPullReq(ctx, pr.ID)
+	if err == nil {
+		return nil, nil, usererror.Conflict("Pull request is already in the merge queue")
+	}
+	if !errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return nil, nil, fmt.Errorf("failed to find merge queue entry of pull request: %w", err)
+	}
+
+	out, _, err := c.Merge(ctx, session, repo.Path, pr.Number, &MergeInput{
+		Method:      in.Method,
+		SourceSHA:   pr.SourceSHA,
+		Title:       in.Title,
+		Message:     in.Message,
+		DryRunRules: true,
+		mergeQueue:  true,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !out.RequiresMergeQueue {
+		return nil, nil, usererror.BadRequest("The merge queue isn't enabled for the target branch")
+	}
+
+	if protection.IsCritical(out.RuleViolations) {
+		return nil, &types.MergeViolations{
+			RuleViolations: out.RuleViolations,
+			Message:        protection.GenerateErrorMessageForBlockingViolations(out.RuleViolations),
+		}, nil
+	}
+
+	now := time.Now().UnixMilli()
+	entry := &types.MergeQueueEntry{
+		RepoID:        repo.ID,
+		Branch:        pr.TargetBranch,
+		PullReqID:     pr.ID,
+		PullReqNumber: pr.Number,
+		SourceSHA:     pr.SourceSHA,
+		Method:        in.Method,
+		Title:         in.Title,
+		Message:       in.Message,
+		State:         enum.MergeQueueEntryStateQueued,
+		CreatedBy:     session.Principal.ID,
+		Created:       now,
+		Updated:       now,
+	}
+
+	err = c.mergeQueueStore.Create(ctx, entry)
+	if errors.Is(err, gitness_store.ErrDuplicate) {
+		return nil, nil, usererror.Conflict("Pull request is already in the merge queue")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to add pull request to the merge queue: %w", err)
+	}
+
+	c.writePullReqActivity(ctx, pr, session.Principal.ID,
+		&types.PullRequestActivityPayloadMergeQueueAdd{MergeMethod: in.Method})
+
+	return entry, nil, nil
+}
+
+// MergeQueueRemove removes a pull request from the merge queue of its target branch.
+func (c *Controller) MergeQueueRemove(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pullreqNum int64,
+) error {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	if err != nil {
+		return fmt.Errorf("failed to acquire access to repo: %w", err)
+	}
+
+	pr, err := c.pullreqStore.FindByNumber(ctx, repo.ID, pullreqNum)
+	if err != nil {
+		return fmt.Errorf("failed to get pull request by number: %w", err)
+	}
+
+	err = func() error {
+		unlock, err := c.locker.LockMergeQueue(ctx, repo.ID, pr.TargetBranch, mergeQueueLockTimeout)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		entry, err := c.mergeQueueStore.FindByPullReq(ctx, pr.ID)
+		if errors.Is(err, gitness_store.ErrResourceNotFound) {
+			return usererror.NotFound("Pull request is not in the merge queue")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find merge queue entry of pull request: %w", err)
+		}
+
+		return c.mergeQueueEject(ctx, session, repo, pr, entry, enum.MergeQueueRemoveReasonCancelled, "")
+	}()
+	if err != nil {
+		return err
+	}
+
+	// the entries behind the removed one must be rebuilt
+	if err = c.MergeQueueProcess(ctx, repo.ID, pr.TargetBranch); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to process merge queue after removing a pull request")
+	}
+
+	return nil
+}
+
+// MergeQueueList returns the merge queue of a branch.
+func (c *Controller) MergeQueueList(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	branch string,
+) ([]*types.MergeQueueEntry, error) {
+	if branch == "" {
+		return nil, usererror.BadRequest("Branch name must be provided")
+	}
+
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire access to repo: %w", err)
+	}
+
+	entries, err := c.mergeQueueStore.List(ctx, repo.ID, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merge queue entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// MergeQueueProcess advances the merge queue of a branch. For every entry in the queue a speculative
+// merge commit is built on top of the target branch and the merge commits of the entries ahead of it.
+// The entry at the head of the queue is merged once the required status checks pass on its merge commit.
+// Entries that can't be merged are removed from the queue and the reason is posted to the pull request activity.
+//
+//nolint:gocognit
+func (c *Controller) MergeQueueProcess(ctx context.Context, repoID int64, branch string) error {
+	unlock, err := c.locker.LockMergeQueue(ctx, repoID, branch, mergeQueueLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := c.mergeQueueStore.List(ctx, repoID, branch)
+	if err != nil {
+		return fmt.Errorf("failed to list merge queue entries: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	repo, err := c.repoStore.Find(ctx, repoID)
+	if err != nil {
+		return fmt.Errorf("failed to find repository: %w", err)
+	}
+
+	protectionRules, err := c.protectionManager.ForRepository(ctx, repo.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch protection rules for the repository: %w", err)
+	}
+
+	branchRef, err := c.git.GetRef(ctx, git.GetRefParams{
+		ReadParams: git.CreateReadParams(repo),
+		Name:       branch,
+		Type:       gitenum.RefTypeBranch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get head of branch %q: %w", branch, err)
+	}
+
+	// base is the commit the next entry is built on top of.
+	base := branchRef.SHA.String()
+	// isHead is true as long as all entries ahead of the next entry got merged.
+	isHead := true
+
+	for _, entry := range entries {
+		pr, err := c.pullreqStore.Find(ctx, entry.PullReqID)
+		if err != nil {
+			return fmt.Errorf("failed to find pull request: %w", err)
+		}
+
+		principal, err := c.principalStore.Find(ctx, entry.CreatedBy)
+		if err != nil {
+			return fmt.Errorf("failed to find principal who added the pull request to the merge queue: %w", err)
+		}
+
+		session := &auth.Session{Principal: *principal}
+
+		if pr.State != enum.PullReqStateOpen {
+			// the pull request got closed or merged outside the merge queue
+			if err = c.mergeQueueDelete(ctx, session, repo, entry); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if pr.SourceSHA != entry.SourceSHA {
+			err = c.mergeQueueEject(ctx, session, repo, pr, entry, enum.MergeQueueRemoveReasonFailed,
+				"The source branch of the pull request got updated.")
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if entry.BaseSHA != base || entry.MergeSHA == "" {
+			var failure string
+			entry, failure, err = c.mergeQueueBuild(ctx, session, repo, pr, entry, base)
+			if err != nil {
+				return err
+			}
+			if failure != "" {
+				err = c.mergeQueueEject(ctx, session, repo, pr, entry, enum.MergeQueueRemoveReasonFailed, failure)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		status, err := c.mergeQueueCheckStatus(ctx, session, repo, protectionRules, pr, entry)
+		if err != nil {
+			return err
+		}
+
+		if status == enum.CheckStatusFailure {
+			err = c.mergeQueueEject(ctx, session, repo, pr, entry, enum.MergeQueueRemoveReasonFailed,
+				"Required status checks failed on the merge queue commit.")
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if status == enum.CheckStatusPending && time.Since(time.UnixMilli(entry.Updated)) > mergeQueueTestingTimeout {
+			err = c.mergeQueueEject(ctx, session, repo, pr, entry, enum.MergeQueueRemoveReasonFailed,
+				fmt.Sprintf("Required status checks didn't complete on the merge queue commit within %s.",
+					mergeQueueTestingTimeout))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if status != enum.CheckStatusSuccess || !isHead {
+			// the entries behind are built on top of this one
+			base = entry.MergeSHA
+			isHead = false
+			continue
+		}
+
+		failure, err := c.mergeQueueMerge(ctx, session, repo, pr, entry)
+		if err != nil {
+			return err
+		}
+		if failure != "" {
+			err = c.mergeQueueEject(ctx, session, repo, pr, entry, enum.MergeQueueRemoveReasonFailed, failure)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err = c.mergeQueueDelete(ctx, session, repo, entry); err != nil {
+			return err
+		}
+
+		base = entry.MergeSHA
+	}
+
+	return nil
+}
+
+// mergeQueueBuild creates the speculative merge commit of the merge queue entry on top of the provided base commit.
+// If the merge commit can't be created because of the pull request itself, the reason is returned as a string.
+func (c *Controller) mergeQueueBuild(
+	ctx context.Context,
+	session *auth.Session,
+	repo *types.Repository,
+	pr *types.PullReq,
+	entry *types.MergeQueueEntry,
+	base string,
+) (*types.MergeQueueEntry, string, error) {
+	writeParams, err := controller.CreateRPCInternalWriteParams(ctx, c.urlProvider, session, repo)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create RPC write params: %w", err)
+	}
+
+	sourceRepo := repo
+	if pr.SourceRepoID != pr.TargetRepoID {
+		sourceRepo, err = c.repoStore.Find(ctx, pr.SourceRepoID)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get source repository: %w", err)
+		}
+	}
+
+	author, committer, title := mergeCommitDetails(entry.Method, &session.Principal, pr, sourceRepo, entry.Title)
+
+	now := time.Now()
+	mergeOutput, err := c.git.Merge(ctx, &git.MergeParams{
+		WriteParams:     writeParams,
+		BaseSHA:         sha.Must(base),
+		BaseBranch:      pr.TargetBranch,
+		HeadRepoUID:     sourceRepo.GitUID,
+		HeadBranch:      entry.SourceSHA,
+		Title:           title,
+		Message:         entry.Message,
+		Committer:       committer,
+		CommitterDate:   &now,
+		Author:          author,
+		AuthorDate:      &now,
+		RefType:         gitenum.RefTypeRaw,
+		RefName:         mergeQueueRef(entry.ID),
+		HeadExpectedSHA: sha.Must(entry.SourceSHA),
+		Method:          gitenum.MergeMethod(entry.Method),
+	})
+	if errors.IsConflict(err) || errors.IsInvalidArgument(err) || errors.IsPreconditionFailed(err) {
+		return entry, "Failed to create the merge queue commit: " + errors.Message(err), nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create merge queue commit: %w", err)
+	}
+	if mergeOutput.MergeSHA.IsEmpty() || len(mergeOutput.ConflictFiles) > 0 {
+		return entry, "The pull request conflicts with the target branch or the pull requests ahead in the merge queue.",
+			nil
+	}
+
+	entry, err = c.mergeQueueStore.UpdateOptLock(ctx, entry, func(e *types.MergeQueueEntry) error {
+		e.State = enum.MergeQueueEntryStateTesting
+		e.BaseSHA = base
+		e.MergeSHA = mergeOutput.MergeSHA.String()
+		return nil
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to update merge queue entry: %w", err)
+	}
+
+	// the pipelines and webhooks of the repository are triggered for the speculative merge commit,
+	// the status checks they report are required for merging the entry.
+	c.eventReporter.MergeQueueBuilt(ctx, &pullreqevents.MergeQueueBuiltPayload{
+		Base:      eventBase(pr, &session.Principal),
+		EntryID:   entry.ID,
+		Ref:       mergeQueueRef(entry.ID),
+		SourceSHA: entry.SourceSHA,
+		BaseSHA:   entry.BaseSHA,
+		MergeSHA:  entry.MergeSHA,
+	})
+
+	return entry, "", nil
+}
+
+// mergeQueueCheckStatus returns the combined status of the required status checks
+// on the speculative merge commit of the merge queue entry.
+func (c *Controller) mergeQueueCheckStatus(
+	ctx context.Context,
+	session *auth.Session,
+	repo *types.Repository,
+	protectionRules protection.Protection,
+	pr *types.PullReq,
+	entry *types.MergeQueueEntry,
+) (enum.CheckStatus, error) {
+	isRepoOwner, err := apiauth.IsRepoOwner(ctx, c.authorizer, session, repo)
+	if err != nil {
+		return "", fmt.Errorf("failed to determine if user is repo owner: %w", err)
+	}
+
+	reqChecks, err := protectionRules.RequiredChecks(ctx, protection.RequiredChecksInput{
+		ResolveUserGroupID: c.userGroupService.ListUserIDsByGroupIDs,
+		Actor:              &session.Principal,
+		IsRepoOwner:        isRepoOwner,
+		Repo:               repo,
+		PullReq:            pr,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get identifiers of required checks: %w", err)
+	}
+
+	checkResults, err := c.checkStore.ListResults(ctx, repo.ID, entry.MergeSHA)
+	if err != nil {
+		return "", fmt.Errorf("failed to list status checks: %w", err)
+	}
+
+	statuses := make(map[string]enum.CheckStatus, len(checkResults))
+	for _, result := range checkResults {
+		statuses[result.Identifier] = result.Status
+	}
+
+	status := enum.CheckStatusSuccess
+	for identifier := range reqChecks.RequiredIdentifiers {
+		checkStatus, ok := statuses[identifier]
+		switch {
+		case !ok || !checkStatus.IsCompleted():
+			status = enum.CheckStatusPending
+		case checkStatus != enum.CheckStatusSuccess:
+			return enum.CheckStatusFailure, nil
+		}
+	}
+
+	return status, nil
+}
+
+// mergeQueueMerge merges the pull request by fast-forwarding the target branch to the tested merge commit.
+// If the pull request can't be merged because of the pull request itself, the reason is returned as a string.
+func (c *Controller) mergeQueueMerge(
+	ctx context.Context,
+	session *auth.Session,
+	repo *types.Repository,
+	pr *types.PullReq,
+	entry *types.MergeQueueEntry,
+) (string, error) {
+	_, violations, err := c.Merge(ctx, session, repo.Path, pr.Number, &MergeInput{
+		Method:        entry.Method,
+		SourceSHA:     entry.SourceSHA,
+		Title:         entry.Title,
+		Message:       entry.Message,
+		mergeQueue:    true,
+		mergeQueueSHA: entry.MergeSHA,
+	})
+
+	var uErr *usererror.Error
+	switch {
+	case errors.Is(err, apiauth.ErrNotAuthorized):
+		return "The principal who added the pull request to the merge queue isn't allowed to merge it.", nil
+	case errors.As(err, &uErr):
+		return uErr.Error(), nil
+	case err != nil:
+		return "", fmt.Errorf("failed to merge pull request from the merge queue: %w", err)
+	case violations != nil:
+		return violations.Message, nil
+	}
+
+	return "", nil
+}
+
+// mergeQueueEject removes the entry from the merge queue and posts the reason to the pull request activity.
+func (c *Controller) mergeQueueEject(
+	ctx context.Context,
+	session *auth.Session,
+	repo *types.Repository,
+	pr *types.PullReq,
+	entry *types.MergeQueueEntry,
+	reason enum.MergeQueueRemoveReason,
+	failure string,
+) error {
+	if err := c.mergeQueueDelete(ctx, session, repo, entry); err != nil {
+		return err
+	}
+
+	c.writePullReqActivity(ctx, pr, session.Principal.ID, &types.PullRequestActivityPayloadMergeQueueRemove{
+		Reason: reason,
+		Error:  failure,
+	})
+
+	return nil
+}
+
+// mergeQueueDelete deletes the merge queue entry along with the reference of its speculative merge commit.
+func (c *Controller) mergeQueueDelete(
+	ctx context.Context,
+	session *auth.Session,
+	repo *types.Repository,
+	entry *types.MergeQueueEntry,
+) error {
+	err := c.mergeQueueStore.Delete(ctx, entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete merge queue entry: %w", err)
+	}
+
+	if entry.MergeSHA == "" {
+		return nil
+	}
+
+	writeParams, err := controller.CreateRPCInternalWriteParams(ctx, c.urlProvider, session, repo)
+	if err != nil {
+		return fmt.Errorf("failed to create RPC write params: %w", err)
+	}
+
+	err = c.git.UpdateRef(ctx, git.UpdateRefParams{
+		WriteParams: writeParams,
+		Type:        gitenum.RefTypeRaw,
+		Name:        mergeQueueRef(entry.ID),
+		NewValue:    sha.None, // when NewValue is empty will delete the ref.
+	})
+	if err != nil {
+		// non-critical error
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to delete merge queue reference of entry %d", entry.ID)
+	}
+
+	return nil
+}
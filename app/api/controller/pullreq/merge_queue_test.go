@@ -0,0 +1,493 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/api/controller/service"
+	"github.com/harness/gitness/app/bootstrap"
+	pullreqevents "github.com/harness/gitness/app/events/pullreq"
+	"github.com/harness/gitness/app/services/locker"
+	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/services/usergroup"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/app/url"
+	"github.com/harness/gitness/cache"
+	"github.com/harness/gitness/events"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/git/sha"
+	"github.com/harness/gitness/lock"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	mqTestHeadSHA   = "1111111111111111111111111111111111111111"
+	mqTestSourceSHA = "2222222222222222222222222222222222222222"
+	mqTestMergeSHA  = "3333333333333333333333333333333333333333"
+	mqTestOldSHA    = "4444444444444444444444444444444444444444"
+)
+
+//nolint:gocognit,maintidx
+func TestMergeQueueProcess(t *testing.T) {
+	tests := []struct {
+		name        string
+		pr          types.PullReq
+		entry       types.MergeQueueEntry
+		mergeOutput git.MergeOutput
+		checks      []types.CheckResult
+		expBuilt    bool
+		expEntry    *types.MergeQueueEntry
+		expFailure  string
+	}{
+		{
+			name:  "build",
+			entry: types.MergeQueueEntry{State: enum.MergeQueueEntryStateQueued},
+			mergeOutput: git.MergeOutput{
+				MergeSHA: sha.Must(mqTestMergeSHA),
+			},
+			expBuilt: true,
+			expEntry: &types.MergeQueueEntry{
+				State:    enum.MergeQueueEntryStateTesting,
+				BaseSHA:  mqTestHeadSHA,
+				MergeSHA: mqTestMergeSHA,
+			},
+		},
+		{
+			name: "rebuild-after-target-branch-update",
+			entry: types.MergeQueueEntry{
+				State:    enum.MergeQueueEntryStateTesting,
+				BaseSHA:  mqTestOldSHA,
+				MergeSHA: mqTestOldSHA,
+			},
+			mergeOutput: git.MergeOutput{
+				MergeSHA: sha.Must(mqTestMergeSHA),
+			},
+			expBuilt: true,
+			expEntry: &types.MergeQueueEntry{
+				State:    enum.MergeQueueEntryStateTesting,
+				BaseSHA:  mqTestHeadSHA,
+				MergeSHA: mqTestMergeSHA,
+			},
+		},
+		{
+			name:  "build-conflict",
+			entry: types.MergeQueueEntry{State: enum.MergeQueueEntryStateQueued},
+			mergeOutput: git.MergeOutput{
+				ConflictFiles: []string{"README.md"},
+			},
+			expBuilt: true,
+			expFailure: "The pull request conflicts with the target branch " +
+				"or the pull requests ahead in the merge queue.",
+		},
+		{
+			name: "source-branch-updated",
+			pr:   types.PullReq{SourceSHA: mqTestOldSHA},
+			entry: types.MergeQueueEntry{
+				State:    enum.MergeQueueEntryStateTesting,
+				BaseSHA:  mqTestHeadSHA,
+				MergeSHA: mqTestMergeSHA,
+			},
+			expFailure: "The source branch of the pull request got updated.",
+		},
+		{
+			name: "check-pending",
+			entry: types.MergeQueueEntry{
+				State:    enum.MergeQueueEntryStateTesting,
+				BaseSHA:  mqTestHeadSHA,
+				MergeSHA: mqTestMergeSHA,
+			},
+			checks: []types.CheckResult{{Identifier: "ci", Status: enum.CheckStatusRunning}},
+			expEntry: &types.MergeQueueEntry{
+				State:    enum.MergeQueueEntryStateTesting,
+				BaseSHA:  mqTestHeadSHA,
+				MergeSHA: mqTestMergeSHA,
+			},
+		},
+		{
+			name: "check-timeout",
+			entry: types.MergeQueueEntry{
+				State:    enum.MergeQueueEntryStateTesting,
+				BaseSHA:  mqTestHeadSHA,
+				MergeSHA: mqTestMergeSHA,
+				Updated:  time.Now().Add(-mergeQueueTestingTimeout - time.Minute).UnixMilli(),
+			},
+			expFailure: "Required status checks didn't complete on the merge queue commit within 2h0m0s.",
+		},
+		{
+			name: "check-failure",
+			entry: types.MergeQueueEntry{
+				State:    enum.MergeQueueEntryStateTesting,
+				BaseSHA:  mqTestHeadSHA,
+				MergeSHA: mqTestMergeSHA,
+			},
+			checks:     []types.CheckResult{{Identifier: "ci", Status: enum.CheckStatusFailure}},
+			expFailure: "Required status checks failed on the merge queue commit.",
+		},
+		{
+			name: "merge-failure",
+			pr:   types.PullReq{IsDraft: true},
+			entry: types.MergeQueueEntry{
+				State:    enum.MergeQueueEntryStateTesting,
+				BaseSHA:  mqTestHeadSHA,
+				MergeSHA: mqTestMergeSHA,
+			},
+			checks:     []types.CheckResult{{Identifier: "ci", Status: enum.CheckStatusSuccess}},
+			expFailure: "Draft pull requests can't be merged. Clear the draft flag first.",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			pr := test.pr
+			pr.ID = 1
+			pr.Number = 3
+			pr.SourceRepoID = 1
+			pr.TargetRepoID = 1
+			pr.TargetBranch = "main"
+			pr.State = enum.PullReqStateOpen
+			if pr.SourceSHA == "" {
+				pr.SourceSHA = mqTestSourceSHA
+			}
+
+			entry := test.entry
+			entry.ID = 1
+			entry.RepoID = 1
+			entry.Branch = "main"
+			entry.PullReqID = pr.ID
+			entry.PullReqNumber = pr.Number
+			entry.SourceSHA = mqTestSourceSHA
+			entry.Method = enum.MergeMethodMerge
+			entry.CreatedBy = 5
+			if entry.Updated == 0 {
+				entry.Updated = time.Now().UnixMilli()
+			}
+
+			mergeQueueStore := &fakeMergeQueueStore{entries: []*types.MergeQueueEntry{&entry}}
+			gitService := &fakeMergeQueueGit{mergeOutput: test.mergeOutput}
+			activityStore := &fakeAutoMergeActivityStore{}
+			producer := &fakeMergeQueueProducer{}
+
+			c := newMergeQueueTestController(t, producer)
+			c.mergeQueueStore = mergeQueueStore
+			c.git = gitService
+			c.pullreqStore = &fakeAutoMergePullReqStore{pr: &pr}
+			c.activityStore = activityStore
+			c.checkStore = fakeMergeQueueCheckStore{results: test.checks}
+
+			if err := c.MergeQueueProcess(ctx, 1, "main"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if test.expBuilt != (gitService.merge != nil) {
+				t.Fatalf("expected merge queue commit built to be %t", test.expBuilt)
+			}
+			if test.expBuilt {
+				if gitService.merge.RefName != "refs/merge-queue/1" {
+					t.Errorf("expected the merge queue commit at refs/merge-queue/1, got %q",
+						gitService.merge.RefName)
+				}
+				if gitService.merge.BaseSHA.String() != mqTestHeadSHA {
+					t.Errorf("expected the merge queue commit on top of %s, got %s",
+						mqTestHeadSHA, gitService.merge.BaseSHA)
+				}
+			}
+
+			// the pipelines and webhooks are triggered only for successfully built merge queue commits
+			expEvents := 0
+			if test.expBuilt && test.expFailure == "" {
+				expEvents = 1
+			}
+			if got := producer.count(pullreqevents.MergeQueueBuiltEvent); got != expEvents {
+				t.Errorf("expected %d merge queue built events, got %d", expEvents, got)
+			}
+
+			if test.expEntry == nil {
+				if len(mergeQueueStore.entries) != 0 {
+					t.Fatalf("expected the entry to be removed from the merge queue")
+				}
+				if entry.MergeSHA != "" && gitService.deletedRef != "refs/merge-queue/1" {
+					t.Errorf("expected the merge queue reference to be deleted")
+				}
+			} else {
+				if len(mergeQueueStore.entries) != 1 {
+					t.Fatalf("expected the entry to stay in the merge queue")
+				}
+				got := mergeQueueStore.entries[0]
+				if got.State != test.expEntry.State ||
+					got.BaseSHA != test.expEntry.BaseSHA ||
+					got.MergeSHA != test.expEntry.MergeSHA {
+					t.Errorf("unexpected entry: state=%s base=%s merge=%s", got.State, got.BaseSHA, got.MergeSHA)
+				}
+			}
+
+			if test.expFailure == "" {
+				if len(activityStore.payloads) != 0 {
+					t.Errorf("expected no activity, got %d", len(activityStore.payloads))
+				}
+				return
+			}
+
+			exp := []types.PullReqActivityPayload{
+				&types.PullRequestActivityPayloadMergeQueueRemove{
+					Reason: enum.MergeQueueRemoveReasonFailed,
+					Error:  test.expFailure,
+				},
+			}
+			if diff := cmp.Diff(exp, activityStore.payloads); diff != "" {
+				t.Errorf("activity mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func newMergeQueueTestController(t *testing.T, producer *fakeMergeQueueProducer) *Controller {
+	t.Helper()
+
+	// the speculative merge commits are committed by the system service principal.
+	config := &types.Config{}
+	config.Principal.System.UID = "gitness"
+	serviceCtrl := service.NewController(nil, nil, fakeMergeQueuePrincipalStore{})
+	if err := bootstrap.SystemService(context.Background(), config, serviceCtrl); err != nil {
+		t.Fatalf("failed to setup system service: %v", err)
+	}
+
+	eventsSystem, err := events.NewSystem(
+		func(string, string) (events.StreamConsumer, error) { return nil, nil },
+		producer)
+	if err != nil {
+		t.Fatalf("failed to create events system: %v", err)
+	}
+
+	eventReporter, err := pullreqevents.NewReporter(eventsSystem)
+	if err != nil {
+		t.Fatalf("failed to create event reporter: %v", err)
+	}
+
+	protectionManager, err := protection.ProvideManager(fakeMergeQueueRuleStore{})
+	if err != nil {
+		t.Fatalf("failed to create protection manager: %v", err)
+	}
+
+	repoStore := fakeMergeQueueRepoStore{}
+
+	return &Controller{
+		urlProvider:       newTestURLProvider(t),
+		authorizer:        fakeAutoMergeAuthorizer{},
+		repoStore:         repoStore,
+		repoFinder:        refcache.NewRepoFinder(repoStore, fakeMergeQueueSpaceCache{}),
+		principalStore:    fakeMergeQueuePrincipalStore{},
+		reviewerStore:     fakeMergeQueueReviewerStore{},
+		eventReporter:     eventReporter,
+		protectionManager: protectionManager,
+		userGroupService:  fakeMergeQueueUserGroupService{},
+		locker: locker.NewLocker(lock.NewInMemory(lock.Config{
+			App:        "gitness",
+			Namespace:  "test",
+			Expiry:     time.Minute,
+			Tries:      1,
+			RetryDelay: time.Millisecond,
+		})),
+	}
+}
+
+func newTestURLProvider(t *testing.T) url.Provider {
+	t.Helper()
+
+	urlProvider, err := url.NewProvider("http://localhost:3000", "http://localhost:3000",
+		"http://localhost:3000", "http://localhost:3000", "ssh://localhost:3022", "git", false,
+		"http://localhost:3000", "http://localhost:3000")
+	if err != nil {
+		t.Fatalf("failed to create url provider: %v", err)
+	}
+
+	return urlProvider
+}
+
+type fakeMergeQueueStore struct {
+	store.MergeQueueEntryStore
+	entries []*types.MergeQueueEntry
+}
+
+func (f *fakeMergeQueueStore) List(context.Context, int64, string) ([]*types.MergeQueueEntry, error) {
+	return append([]*types.MergeQueueEntry(nil), f.entries...), nil
+}
+
+func (f *fakeMergeQueueStore) UpdateOptLock(
+	_ context.Context,
+	e *types.MergeQueueEntry,
+	mutateFn func(e *types.MergeQueueEntry) error,
+) (*types.MergeQueueEntry, error) {
+	updated := *e
+	if err := mutateFn(&updated); err != nil {
+		return nil, err
+	}
+	updated.Version++
+	updated.Updated = time.Now().UnixMilli()
+
+	for i := range f.entries {
+		if f.entries[i].ID == e.ID {
+			f.entries[i] = &updated
+		}
+	}
+
+	return &updated, nil
+}
+
+func (f *fakeMergeQueueStore) Delete(_ context.Context, id int64) error {
+	for i := range f.entries {
+		if f.entries[i].ID == id {
+			f.entries = append(f.entries[:i], f.entries[i+1:]...)
+			return nil
+		}
+	}
+	return gitness_store.ErrResourceNotFound
+}
+
+type fakeMergeQueueGit struct {
+	git.Interface
+	mergeOutput git.MergeOutput
+	merge       *git.MergeParams
+	deletedRef  string
+}
+
+func (f *fakeMergeQueueGit) GetRef(context.Context, git.GetRefParams) (git.GetRefResponse, error) {
+	return git.GetRefResponse{SHA: sha.Must(mqTestHeadSHA)}, nil
+}
+
+func (f *fakeMergeQueueGit) Merge(_ context.Context, in *git.MergeParams) (git.MergeOutput, error) {
+	f.merge = in
+	return f.mergeOutput, nil
+}
+
+func (f *fakeMergeQueueGit) UpdateRef(_ context.Context, params git.UpdateRefParams) error {
+	if params.NewValue.IsEmpty() {
+		f.deletedRef = params.Name
+	}
+	return nil
+}
+
+type fakeMergeQueueCheckStore struct {
+	store.CheckStore
+	results []types.CheckResult
+}
+
+func (f fakeMergeQueueCheckStore) ListResults(context.Context, int64, string) ([]types.CheckResult, error) {
+	return f.results, nil
+}
+
+type fakeMergeQueueRuleStore struct {
+	store.RuleStore
+}
+
+func (fakeMergeQueueRuleStore) ListAllRepoRules(context.Context, int64) ([]types.RuleInfoInternal, error) {
+	return []types.RuleInfoInternal{
+		{
+			RuleInfo: types.RuleInfo{
+				RepoPath:   "space/repo",
+				ID:         1,
+				Identifier: "merge-queue",
+				Type:       protection.TypeBranch,
+				State:      enum.RuleStateActive,
+			},
+			Pattern:    []byte(`{"default":true}`),
+			Definition: []byte(`{"pullreq":{"status_checks":{"require_identifiers":["ci"]}}}`),
+		},
+	}, nil
+}
+
+type fakeMergeQueueRepoStore struct {
+	store.RepoStore
+}
+
+func (fakeMergeQueueRepoStore) Find(_ context.Context, id int64) (*types.Repository, error) {
+	return &types.Repository{
+		ID:            id,
+		Path:          "space/repo",
+		Identifier:    "repo",
+		GitUID:        "repo-uid",
+		DefaultBranch: "main",
+		State:         enum.RepoStateActive,
+	}, nil
+}
+
+func (f fakeMergeQueueRepoStore) FindActiveByUID(ctx context.Context, _ int64, _ string) (*types.Repository, error) {
+	return f.Find(ctx, 1)
+}
+
+type fakeMergeQueueSpaceCache struct {
+	cache.Cache[string, *types.Space]
+}
+
+func (fakeMergeQueueSpaceCache) Get(context.Context, string) (*types.Space, error) {
+	return &types.Space{ID: 1, Path: "space"}, nil
+}
+
+type fakeMergeQueuePrincipalStore struct {
+	store.PrincipalStore
+}
+
+func (fakeMergeQueuePrincipalStore) Find(_ context.Context, id int64) (*types.Principal, error) {
+	return &types.Principal{ID: id, UID: "queuer", Type: enum.PrincipalTypeUser}, nil
+}
+
+func (fakeMergeQueuePrincipalStore) FindServiceByUID(_ context.Context, uid string) (*types.Service, error) {
+	return &types.Service{ID: 1, UID: uid, Admin: true}, nil
+}
+
+type fakeMergeQueueReviewerStore struct {
+	store.PullReqReviewerStore
+}
+
+func (fakeMergeQueueReviewerStore) List(context.Context, int64) ([]*types.PullReqReviewer, error) {
+	return nil, nil
+}
+
+type fakeMergeQueueUserGroupService struct {
+	usergroup.SearchService
+}
+
+func (fakeMergeQueueUserGroupService) ListUserIDsByGroupIDs(context.Context, []int64) ([]int64, error) {
+	return nil, nil
+}
+
+type fakeMergeQueueProducer struct {
+	streamIDs []string
+}
+
+func (f *fakeMergeQueueProducer) Send(_ context.Context, streamID string, _ map[string]interface{}) (string, error) {
+	f.streamIDs = append(f.streamIDs, streamID)
+	return "1", nil
+}
+
+func (f *fakeMergeQueueProducer) count(eventType events.EventType) int {
+	n := 0
+	for _, streamID := range f.streamIDs {
+		if strings.HasSuffix(streamID, string(eventType)) {
+			n++
+		}
+	}
+	return n
+}
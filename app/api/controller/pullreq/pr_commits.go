@@ -60,7 +60,7 @@ func (c *Controller) Commits(
 	commits := make([]types.Commit, len(output.Commits))
 	for i := range output.Commits {
 		var commit *types.Commit
-		commit, err = controller.MapCommit(&output.Commits[i])
+		commit, err = controller.MapCommitWithSignature(ctx, c.publicKeySvc, &output.Commits[i])
 		if err != nil {
 			return nil, fmt.Errorf("failed to map commit: %w", err)
 		}
@@ -69,3 +69,23 @@ func (c *Controller) Commits(
 
 	return commits, nil
 }
+
+// unverifiedCommitsResolver returns a function that lists SHAs of the pull request commits
+// that don't have a verified signature.
+func (c *Controller) unverifiedCommitsResolver(
+	repo *types.Repository,
+	pr *types.PullReq,
+) func(ctx context.Context) ([]string, error) {
+	return func(ctx context.Context) ([]string, error) {
+		output, err := c.git.ListCommitSignatures(ctx, &git.ListCommitSignaturesParams{
+			ReadParams: git.CreateReadParams(repo),
+			Rev:        pr.SourceSHA,
+			BaseRev:    pr.MergeBaseSHA,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull request commit signatures: %w", err)
+		}
+
+		return controller.UnverifiedCommitSHAs(ctx, c.publicKeySvc, output.Commits)
+	}
+}
@@ -45,6 +45,10 @@ type CreateInput struct {
 	SourceBranch  string `json:"source_branch"`
 	TargetBranch  string `json:"target_branch"`
 
+	// AllowMaintainerEdit allows principals with push access to the target repository
+	// to push to the source branch. Applies only to pull requests opened from a fork.
+	AllowMaintainerEdit bool `json:"allow_maintainer_edit"`
+
 	ReviewerIDs []int64 `json:"reviewer_ids"`
 }
 
@@ -74,7 +78,7 @@ func (c *Controller) Create(
 		return nil, err
 	}
 
-	targetRepo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	targetRepo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
 	if err != nil {
 		return nil, fmt.Errorf("failed to acquire access to target repo: %w", err)
 	}
@@ -87,6 +91,16 @@ func (c *Controller) Create(
 		}
 	}
 
+	// pull requests from a fork only require read access to the upstream repository.
+	if sourceRepo.ID == targetRepo.ID {
+		err = apiauth.CheckRepo(ctx, c.authorizer, session, targetRepo, enum.PermissionRepoPush)
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire access to target repo: %w", err)
+		}
+	} else if sourceRepo.ForkID != targetRepo.ID {
+		return nil, usererror.BadRequest("Pull requests can only be opened from a fork into its upstream repository")
+	}
+
 	if sourceRepo.ID == targetRepo.ID && in.TargetBranch == in.SourceBranch {
 		return nil, usererror.BadRequest("target and source branch can't be the same")
 	}
@@ -105,9 +119,13 @@ func (c *Controller) Create(
 		return nil, err
 	}
 
+	if err = c.fetchSourceObjects(ctx, session, sourceRepo, targetRepo, sourceSHA); err != nil {
+		return nil, err
+	}
+
 	mergeBaseResult, err := c.git.MergeBase(ctx, git.MergeBaseParams{
-		ReadParams: git.ReadParams{RepoUID: sourceRepo.GitUID},
-		Ref1:       in.SourceBranch,
+		ReadParams: git.ReadParams{RepoUID: targetRepo.GitUID},
+		Ref1:       sourceSHA.String(),
 		Ref2:       in.TargetBranch,
 	})
 	if err != nil {
@@ -267,30 +285,31 @@ func newPullReq(
 ) *types.PullReq {
 	now := time.Now().UnixMilli()
 	return &types.PullReq{
-		ID:                0, // the ID will be populated in the data layer
-		Version:           0,
-		Number:            number,
-		CreatedBy:         session.Principal.ID,
-		Created:           now,
-		Updated:           now,
-		Edited:            now,
-		State:             enum.PullReqStateOpen,
-		IsDraft:           in.IsDraft,
-		Title:             in.Title,
-		Description:       in.Description,
-		SourceRepoID:      sourceRepo.ID,
-		SourceBranch:      in.SourceBranch,
-		SourceSHA:         sourceSHA.String(),
-		TargetRepoID:      targetRepo.ID,
-		TargetBranch:      in.TargetBranch,
-		ActivitySeq:       0,
-		MergedBy:          nil,
-		Merged:            nil,
-		MergeMethod:       nil,
-		MergeBaseSHA:      mergeBaseSHA.String(),
-		MergeCheckStatus:  enum.MergeCheckStatusUnchecked,
-		RebaseCheckStatus: enum.MergeCheckStatusUnchecked,
-		Author:            *session.Principal.ToPrincipalInfo(),
-		Merger:            nil,
+		ID:                  0, // the ID will be populated in the data layer
+		Version:             0,
+		Number:              number,
+		CreatedBy:           session.Principal.ID,
+		Created:             now,
+		Updated:             now,
+		Edited:              now,
+		State:               enum.PullReqStateOpen,
+		IsDraft:             in.IsDraft,
+		Title:               in.Title,
+		Description:         in.Description,
+		SourceRepoID:        sourceRepo.ID,
+		SourceBranch:        in.SourceBranch,
+		SourceSHA:           sourceSHA.String(),
+		TargetRepoID:        targetRepo.ID,
+		TargetBranch:        in.TargetBranch,
+		AllowMaintainerEdit: in.AllowMaintainerEdit && sourceRepo.ID != targetRepo.ID,
+		ActivitySeq:         0,
+		MergedBy:            nil,
+		Merged:              nil,
+		MergeMethod:         nil,
+		MergeBaseSHA:        mergeBaseSHA.String(),
+		MergeCheckStatus:    enum.MergeCheckStatusUnchecked,
+		RebaseCheckStatus:   enum.MergeCheckStatusUnchecked,
+		Author:              *session.Principal.ToPrincipalInfo(),
+		Merger:              nil,
 	}
 }
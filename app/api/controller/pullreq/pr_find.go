@@ -20,6 +20,8 @@ import (
 
 	"github.com/harness/gitness/app/api/usererror"
 	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/errors"
+	gitness_store "github.com/harness/gitness/store"
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
 
@@ -61,6 +63,11 @@ func (c *Controller) Find(
 		log.Ctx(ctx).Warn().Err(err).Msg("failed to backfill PR stats")
 	}
 
+	pr.AutoMerge, err = c.autoMergeStore.Find(ctx, pr.ID)
+	if err != nil && !errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return nil, fmt.Errorf("failed to find auto-merge of pull request: %w", err)
+	}
+
 	return pr, nil
 }
 
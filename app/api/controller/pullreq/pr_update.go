@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/usererror"
 	"github.com/harness/gitness/app/auth"
 	pullreqevents "github.com/harness/gitness/app/events/pullreq"
 	"github.com/harness/gitness/types"
@@ -31,6 +32,9 @@ import (
 type UpdateInput struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
+
+	// AllowMaintainerEdit can be changed only by the author of a pull request opened from a fork.
+	AllowMaintainerEdit *bool `json:"allow_maintainer_edit"`
 }
 
 func (in *UpdateInput) Sanitize() error {
@@ -56,7 +60,7 @@ func (c *Controller) Update(ctx context.Context,
 		return nil, err
 	}
 
-	targetRepo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	targetRepo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
 	if err != nil {
 		return nil, fmt.Errorf("failed to acquire access to target repo: %w", err)
 	}
@@ -66,6 +70,20 @@ func (c *Controller) Update(ctx context.Context,
 		return nil, fmt.Errorf("failed to get pull request by number: %w", err)
 	}
 
+	if err = c.checkPullReqEditAccess(ctx, session, targetRepo, pr); err != nil {
+		return nil, fmt.Errorf("failed to acquire access to target repo: %w", err)
+	}
+
+	allowMaintainerEditChanged := in.AllowMaintainerEdit != nil && *in.AllowMaintainerEdit != pr.AllowMaintainerEdit
+	if allowMaintainerEditChanged {
+		if pr.SourceRepoID == pr.TargetRepoID {
+			return nil, usererror.BadRequest("Maintainer edits apply only to pull requests opened from a fork")
+		}
+		if pr.CreatedBy != session.Principal.ID {
+			return nil, usererror.Forbidden("Only the author of the pull request can allow maintainer edits")
+		}
+	}
+
 	if pr.SourceRepoID != pr.TargetRepoID {
 		var sourceRepo *types.Repository
 
@@ -86,7 +104,7 @@ func (c *Controller) Update(ctx context.Context,
 	titleChanged := titleOld != in.Title
 	descriptionChanged := descriptionOld != in.Description
 
-	if !titleChanged && !descriptionChanged {
+	if !titleChanged && !descriptionChanged && !allowMaintainerEditChanged {
 		return pr, nil
 	}
 
@@ -95,6 +113,9 @@ func (c *Controller) Update(ctx context.Context,
 	pr, err = c.pullreqStore.UpdateOptLock(ctx, pr, func(pr *types.PullReq) error {
 		pr.Title = in.Title
 		pr.Description = in.Description
+		if in.AllowMaintainerEdit != nil {
+			pr.AllowMaintainerEdit = *in.AllowMaintainerEdit
+		}
 		if needToWriteActivity {
 			pr.ActivitySeq++
 		}
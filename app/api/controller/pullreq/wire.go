@@ -24,6 +24,7 @@ import (
 	"github.com/harness/gitness/app/services/locker"
 	"github.com/harness/gitness/app/services/migrate"
 	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/app/services/publickey"
 	"github.com/harness/gitness/app/services/pullreq"
 	"github.com/harness/gitness/app/services/refcache"
 	"github.com/harness/gitness/app/services/usergroup"
@@ -58,6 +59,8 @@ func ProvideController(
 	fileViewStore store.PullReqFileViewStore,
 	membershipStore store.MembershipStore,
 	checkStore store.CheckStore,
+	autoMergeStore store.PullReqAutoMergeStore,
+	mergeQueueStore store.MergeQueueEntryStore,
 	rpcClient git.Interface,
 	repoFinder refcache.RepoFinder,
 	eventReporter *pullreqevents.Reporter, codeCommentMigrator *codecomments.Migrator,
@@ -67,6 +70,7 @@ func ProvideController(
 	labelSvc *label.Service,
 	instrumentation instrument.Service,
 	userGroupService usergroup.SearchService,
+	publicKeySvc publickey.Service,
 ) *Controller {
 	return NewController(tx,
 		urlProvider,
@@ -85,6 +89,8 @@ func ProvideController(
 		fileViewStore,
 		membershipStore,
 		checkStore,
+		autoMergeStore,
+		mergeQueueStore,
 		rpcClient,
 		repoFinder,
 		eventReporter,
@@ -99,5 +105,6 @@ func ProvideController(
 		labelSvc,
 		instrumentation,
 		userGroupService,
+		publicKeySvc,
 	)
 }
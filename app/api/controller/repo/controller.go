@@ -35,6 +35,7 @@ import (
 	"github.com/harness/gitness/app/services/locker"
 	"github.com/harness/gitness/app/services/protection"
 	"github.com/harness/gitness/app/services/publicaccess"
+	"github.com/harness/gitness/app/services/publickey"
 	"github.com/harness/gitness/app/services/refcache"
 	"github.com/harness/gitness/app/services/rules"
 	"github.com/harness/gitness/app/services/settings"
@@ -43,6 +44,7 @@ import (
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/app/url"
 	"github.com/harness/gitness/audit"
+	"github.com/harness/gitness/blob"
 	"github.com/harness/gitness/git"
 	"github.com/harness/gitness/lock"
 	"github.com/harness/gitness/store/database/dbtx"
@@ -109,6 +111,9 @@ type Controller struct {
 	instrumentation    instrument.Service
 	rulesSvc           *rules.Service
 	sseStreamer        sse.Streamer
+	publicKeySvc       publickey.Service
+	lfsStore           store.LFSObjectStore
+	blobStore          blob.Store
 }
 
 func NewController(
@@ -147,6 +152,9 @@ func NewController(
 	userGroupService usergroup.SearchService,
 	rulesSvc *rules.Service,
 	sseStreamer sse.Streamer,
+	publicKeySvc publickey.Service,
+	lfsStore store.LFSObjectStore,
+	blobStore blob.Store,
 ) *Controller {
 	return &Controller{
 		defaultBranch:      config.Git.DefaultBranch,
@@ -184,6 +192,9 @@ func NewController(
 		userGroupService:   userGroupService,
 		rulesSvc:           rulesSvc,
 		sseStreamer:        sseStreamer,
+		publicKeySvc:       publicKeySvc,
+		lfsStore:           lfsStore,
+		blobStore:          blobStore,
 	}
 }
 
@@ -238,6 +249,26 @@ func (c *Controller) getRepoCheckAccessForGit(
 	)
 }
 
+// getRepoCheckMaintainerEditAccess fetches a fork and checks if the current user
+// is allowed to push to it as a maintainer of its upstream repository.
+func (c *Controller) getRepoCheckMaintainerEditAccess(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+) (*types.Repository, error) {
+	repo, err := GetRepo(ctx, c.repoFinder, repoRef, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find repo: %w", err)
+	}
+
+	err = apiauth.CheckRepoMaintainerEdit(ctx, c.authorizer, c.repoStore, c.pullReqStore, session, repo, "")
+	if err != nil {
+		return nil, fmt.Errorf("access check failed: %w", err)
+	}
+
+	return repo, nil
+}
+
 func (c *Controller) getSpaceCheckAuthRepoCreation(
 	ctx context.Context,
 	session *auth.Session,
@@ -53,7 +53,6 @@ type CreateInput struct {
 	DefaultBranch string `json:"default_branch"`
 	Description   string `json:"description"`
 	IsPublic      bool   `json:"is_public"`
-	ForkID        int64  `json:"fork_id"`
 	Readme        bool   `json:"readme"`
 	License       string `json:"license"`
 	GitIgnore     string `json:"git_ignore"`
@@ -116,7 +115,6 @@ func (c *Controller) Create(ctx context.Context, session *auth.Session, in *Crea
 			CreatedBy:     session.Principal.ID,
 			Created:       now,
 			Updated:       now,
-			ForkID:        in.ForkID,
 			DefaultBranch: in.DefaultBranch,
 			IsEmpty:       isEmpty,
 		}
@@ -21,8 +21,10 @@ import (
 
 	"github.com/harness/gitness/app/api/controller"
 	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/paths"
 	"github.com/harness/gitness/app/services/instrument"
 	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/audit"
 	"github.com/harness/gitness/git"
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
@@ -99,11 +101,45 @@ func (c *Controller) CreateCommitTag(ctx context.Context,
 		return nil, nil, err
 	}
 
-	commitTag, err := mapCommitTag(rpcOut.CommitTag)
+	commitTag, err := c.mapCommitTag(ctx, rpcOut.CommitTag)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to map tag received from service output: %w", err)
 	}
 
+	if protection.IsBypassed(violations) {
+		err = c.auditService.Log(ctx,
+			session.Principal,
+			audit.NewResource(
+				audit.ResourceTypeRepository,
+				repo.Identifier,
+				audit.RepoPath,
+				repo.Path,
+				audit.BypassedResourceType,
+				audit.BypassedResourceTypeTag,
+				audit.BypassedResourceName,
+				in.Name,
+				audit.BypassAction,
+				audit.BypassActionCreated,
+				audit.ResourceName,
+				fmt.Sprintf(
+					audit.BypassSHALabelFormat,
+					repo.Identifier,
+					in.Name,
+				),
+			),
+			audit.ActionBypassed,
+			paths.Parent(repo.Path),
+			audit.WithNewObject(audit.TagObject{
+				TagName:        in.Name,
+				RepoPath:       repo.Path,
+				RuleViolations: violations,
+			}),
+		)
+		if err != nil {
+			log.Ctx(ctx).Warn().Msgf("failed to insert audit log for create tag operation: %s", err)
+		}
+	}
+
 	err = c.instrumentation.Track(ctx, instrument.Event{
 		Type:      instrument.EventTypeCreateTag,
 		Principal: session.Principal.ToPrincipalInfo(),
@@ -45,7 +45,7 @@ func (c *Controller) GetCommit(ctx context.Context,
 	}
 
 	rpcCommit := rpcOut.Commit
-	commit, err := controller.MapCommit(&rpcCommit)
+	commit, err := controller.MapCommitWithSignature(ctx, c.publicKeySvc, &rpcCommit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to map commit: %w", err)
 	}
@@ -18,8 +18,10 @@ import (
 	"context"
 	"fmt"
 
+	apiauth "github.com/harness/gitness/app/api/auth"
 	"github.com/harness/gitness/app/api/controller"
 	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/errors"
 	"github.com/harness/gitness/git"
 	"github.com/harness/gitness/git/api"
 	"github.com/harness/gitness/types/enum"
@@ -41,6 +43,11 @@ func (c *Controller) GitServicePack(
 	}
 
 	repo, err := c.getRepoCheckAccessForGit(ctx, session, repoRef, permission)
+	if isWriteOperation && errors.Is(err, apiauth.ErrNotAuthorized) {
+		// maintainers of the upstream repository can push to source branches of pull requests opened from a fork.
+		// The updated branches are verified by the pre-receive hook.
+		repo, err = c.getRepoCheckMaintainerEditAccess(ctx, session, repoRef)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to verify repo access: %w", err)
 	}
@@ -33,6 +33,8 @@ type CommitTag struct {
 	Message     string           `json:"message,omitempty"`
 	Tagger      *types.Signature `json:"tagger,omitempty"`
 	Commit      *types.Commit    `json:"commit,omitempty"`
+
+	Signature *types.GitSignature `json:"signature,omitempty"`
 }
 
 // ListCommitTags lists the commit tags of a repo.
@@ -62,7 +64,7 @@ func (c *Controller) ListCommitTags(ctx context.Context,
 
 	tags := make([]CommitTag, len(rpcOut.Tags))
 	for i := range rpcOut.Tags {
-		tags[i], err = mapCommitTag(rpcOut.Tags[i])
+		tags[i], err = c.mapCommitTag(ctx, rpcOut.Tags[i])
 		if err != nil {
 			return nil, fmt.Errorf("failed to map CommitTag: %w", err)
 		}
@@ -85,11 +87,11 @@ func mapToRPCTagSortOption(o enum.TagSortOption) git.TagSortOption {
 	}
 }
 
-func mapCommitTag(t git.CommitTag) (CommitTag, error) {
+func (c *Controller) mapCommitTag(ctx context.Context, t git.CommitTag) (CommitTag, error) {
 	var commit *types.Commit
 	if t.Commit != nil {
 		var err error
-		commit, err = controller.MapCommit(t.Commit)
+		commit, err = controller.MapCommitWithSignature(ctx, c.publicKeySvc, t.Commit)
 		if err != nil {
 			return CommitTag{}, err
 		}
@@ -104,6 +106,16 @@ func mapCommitTag(t git.CommitTag) (CommitTag, error) {
 		}
 	}
 
+	var signature *types.GitSignature
+	if t.Signature != nil && t.Tagger != nil {
+		var err error
+		signature, err = c.publicKeySvc.VerifySignature(ctx,
+			t.Signature.Signature, t.Signature.Payload, t.Tagger.Identity.Email)
+		if err != nil {
+			return CommitTag{}, fmt.Errorf("failed to verify tag signature: %w", err)
+		}
+	}
+
 	return CommitTag{
 		Name:        t.Name,
 		SHA:         t.SHA.String(),
@@ -112,5 +124,6 @@ func mapCommitTag(t git.CommitTag) (CommitTag, error) {
 		Message:     t.Message,
 		Tagger:      tagger,
 		Commit:      commit,
+		Signature:   signature,
 	}, nil
 }
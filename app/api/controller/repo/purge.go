@@ -19,11 +19,16 @@ import (
 	"fmt"
 
 	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/controller"
+	"github.com/harness/gitness/app/api/controller/lfs"
 	"github.com/harness/gitness/app/api/usererror"
 	"github.com/harness/gitness/app/auth"
 	repoevents "github.com/harness/gitness/app/events/repo"
 	"github.com/harness/gitness/app/githook"
+	"github.com/harness/gitness/app/services/backup/pause"
+	"github.com/harness/gitness/errors"
 	"github.com/harness/gitness/git"
+	gitness_store "github.com/harness/gitness/store"
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
 
@@ -63,6 +68,10 @@ func (c *Controller) PurgeNoAuth(
 	session *auth.Session,
 	repo *types.Repository,
 ) error {
+	if err := c.CheckDeletionsAllowed(ctx); err != nil {
+		return err
+	}
+
 	if repo.State == enum.RepoStateGitImport {
 		log.Ctx(ctx).Info().Msg("repository is importing. cancelling the import job.")
 		err := c.importer.Cancel(ctx, repo)
@@ -71,10 +80,24 @@ func (c *Controller) PurgeNoAuth(
 		}
 	}
 
+	// LFS objects are removed from the db together with the repo, so their blobs have to be deleted first.
+	if err := c.deleteLFSObjects(ctx, repo.ID); err != nil {
+		return fmt.Errorf("failed to delete lfs objects: %w", err)
+	}
+
+	// forks borrow git objects of the repository, so they need their own copy before the repository is gone.
+	if err := c.detachForks(ctx, session, repo); err != nil {
+		return fmt.Errorf("failed to detach forks: %w", err)
+	}
+
 	if err := c.repoStore.Purge(ctx, repo.ID, repo.Deleted); err != nil {
 		return fmt.Errorf("failed to delete repo from db: %w", err)
 	}
 
+	if repo.ForkID != 0 {
+		c.decrementNumForks(ctx, repo.ForkID)
+	}
+
 	if err := c.DeleteGitRepository(ctx, session, repo.GitUID); err != nil {
 		log.Ctx(ctx).Err(err).Msg("failed to remove git repository")
 	}
@@ -89,6 +112,16 @@ func (c *Controller) PurgeNoAuth(
 	return nil
 }
 
+// CheckDeletionsAllowed returns an error if the permanent deletion of repository data is paused by a backup.
+func (c *Controller) CheckDeletionsAllowed(ctx context.Context) error {
+	err := pause.Check(ctx, c.settings)
+	if errors.Is(err, pause.ErrDeletionsPaused) {
+		return usererror.Conflict("Permanent deletions are paused while a backup is being created. Try again later.")
+	}
+
+	return err
+}
+
 func (c *Controller) DeleteGitRepository(
 	ctx context.Context,
 	session *auth.Session,
@@ -125,3 +158,64 @@ func (c *Controller) DeleteGitRepository(
 
 	return nil
 }
+
+func (c *Controller) detachForks(
+	ctx context.Context,
+	session *auth.Session,
+	repo *types.Repository,
+) error {
+	forks, err := c.repoStore.ListForks(ctx, repo.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list forks: %w", err)
+	}
+
+	for _, fork := range forks {
+		writeParams, err := controller.CreateRPCInternalWriteParams(ctx, c.urlProvider, session, fork)
+		if err != nil {
+			return fmt.Errorf("failed to create RPC write params: %w", err)
+		}
+
+		err = c.git.DetachFork(ctx, &git.DetachForkParams{WriteParams: writeParams})
+		if err != nil {
+			return fmt.Errorf("failed to detach fork %d: %w", fork.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Controller) decrementNumForks(ctx context.Context, upstreamID int64) {
+	upstream, err := c.repoStore.Find(ctx, upstreamID)
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return // the upstream repository is deleted
+	}
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to find upstream repository")
+		return
+	}
+
+	_, err = c.repoStore.UpdateOptLock(ctx, upstream, func(r *types.Repository) error {
+		if r.NumForks > 0 {
+			r.NumForks--
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, gitness_store.ErrResourceNotFound) {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to update number of forks of the upstream repository")
+	}
+}
+
+func (c *Controller) deleteLFSObjects(ctx context.Context, repoID int64) error {
+	oids, err := c.lfsStore.ListOIDs(ctx, repoID)
+	if err != nil {
+		return fmt.Errorf("failed to list lfs objects: %w", err)
+	}
+
+	for _, oid := range oids {
+		if err := c.blobStore.Delete(ctx, lfs.GetObjectPath(repoID, oid)); err != nil {
+			return fmt.Errorf("failed to delete lfs object %q: %w", oid, err)
+		}
+	}
+
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/harness/gitness/app/services/locker"
 	"github.com/harness/gitness/app/services/protection"
 	"github.com/harness/gitness/app/services/publicaccess"
+	"github.com/harness/gitness/app/services/publickey"
 	"github.com/harness/gitness/app/services/refcache"
 	"github.com/harness/gitness/app/services/rules"
 	"github.com/harness/gitness/app/services/settings"
@@ -34,6 +35,7 @@ import (
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/app/url"
 	"github.com/harness/gitness/audit"
+	"github.com/harness/gitness/blob"
 	"github.com/harness/gitness/git"
 	"github.com/harness/gitness/lock"
 	"github.com/harness/gitness/store/database/dbtx"
@@ -46,6 +48,7 @@ import (
 // WireSet provides a wire set for this package.
 var WireSet = wire.NewSet(
 	ProvideController,
+	ProvideRepoDeleter,
 )
 
 func ProvideController(
@@ -84,6 +87,9 @@ func ProvideController(
 	userGroupService usergroup.SearchService,
 	rulesSvc *rules.Service,
 	sseStreamer sse.Streamer,
+	publicKeySvc publickey.Service,
+	lfsStore store.LFSObjectStore,
+	blobStore blob.Store,
 ) *Controller {
 	return NewController(config, tx, urlProvider,
 		authorizer,
@@ -92,10 +98,15 @@ func ProvideController(
 		principalInfoCache, protectionManager, rpcClient, spaceCache, repoFinder, importer,
 		codeOwners, repoReporter, indexer, limiter, locker, auditService, mtxManager, identifierCheck,
 		repoChecks, publicAccess, labelSvc, instrumentation, userGroupStore, userGroupService,
-		rulesSvc, sseStreamer,
+		rulesSvc, sseStreamer, publicKeySvc, lfsStore, blobStore,
 	)
 }
 
+// ProvideRepoDeleter provides the controller as the repository deleter of the space archive importer.
+func ProvideRepoDeleter(ctrl *Controller) importer.RepoDeleter {
+	return ctrl
+}
+
 func ProvideRepoCheck() Check {
 	return NewNoOpRepoChecks()
 }
@@ -0,0 +1,74 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/app/api/usererror"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Authenticate returns the runner that authenticates with the given token.
+// It also records the time the runner was last seen, at most once per heartbeat interval.
+func (c *Controller) Authenticate(ctx context.Context, token string) (*types.Runner, error) {
+	if token == "" {
+		return nil, usererror.ErrUnauthorized
+	}
+
+	runner, err := c.runnerStore.FindByTokenHash(ctx, hashToken(token))
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return nil, usererror.ErrUnauthorized
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find runner: %w", err)
+	}
+
+	now := time.Now().UnixMilli()
+	if now-runner.LastSeen < heartbeatInterval.Milliseconds() {
+		return runner, nil
+	}
+
+	c.heartbeat(ctx, runner, &types.RunnerHeartbeat{
+		OS:     runner.OS,
+		Arch:   runner.Arch,
+		Labels: runner.Labels,
+		Time:   now,
+	})
+
+	return runner, nil
+}
+
+// heartbeat records the heartbeat of the runner. A failure isn't fatal for the request of the runner.
+func (c *Controller) heartbeat(ctx context.Context, runner *types.Runner, heartbeat *types.RunnerHeartbeat) {
+	err := c.runnerStore.UpdateHeartbeat(ctx, runner.ID, heartbeat)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).
+			Str("runner", runner.Identifier).
+			Msg("failed to update runner heartbeat")
+		return
+	}
+
+	runner.OS = heartbeat.OS
+	runner.Arch = heartbeat.Arch
+	runner.Labels = heartbeat.Labels
+	runner.LastSeen = heartbeat.Time
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/drone/runner-go/client"
+)
+
+const (
+	// heartbeatInterval is the minimum time between two updates of the time a runner was last seen.
+	heartbeatInterval = 15 * time.Second
+
+	// offerLifetime is the time a runner has to accept a stage it got from Request.
+	offerLifetime = time.Minute
+)
+
+// Controller manages the registered remote pipeline runners and serves the protocol they use to
+// execute stages. The protocol is the one of drone runners, so standalone drone runners can be used.
+type Controller struct {
+	config      *types.Config
+	runnerStore store.RunnerStore
+	stageStore  store.StageStore
+	stepStore   store.StepStore
+	// client forwards the protocol requests of the runners to the execution manager.
+	client client.Client
+}
+
+func NewController(
+	config *types.Config,
+	runnerStore store.RunnerStore,
+	stageStore store.StageStore,
+	stepStore store.StepStore,
+	client client.Client,
+) *Controller {
+	return &Controller{
+		config:      config,
+		runnerStore: runnerStore,
+		stageStore:  stageStore,
+		stepStore:   stepStore,
+		client:      client,
+	}
+}
+
+// machineName returns the machine the stages executed by the runner are assigned to.
+// It's derived from the id of the runner, as the identifier is chosen freely on registration
+// and could match the machine name of the embedded runner.
+func machineName(runner *types.Runner) string {
+	return "runner-" + strconv.FormatInt(runner.ID, 10)
+}
+
+// isAssigned returns true if the stage is assigned to the runner.
+func isAssigned(stage *types.Stage, runner *types.Runner) bool {
+	return stage.RunnerID != nil && *stage.RunnerID == runner.ID && stage.Machine == machineName(runner)
+}
+
+// setStatus sets the status of the runner based on the time it was last seen.
+func (c *Controller) setStatus(runner *types.Runner) {
+	runner.Status = enum.RunnerStatusOffline
+	if time.Since(time.UnixMilli(runner.LastSeen)) < c.config.CI.RemoteRunnerOfflineAfter {
+		runner.Status = enum.RunnerStatusOnline
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"fmt"
+)
+
+// Delete deletes the remote runner with the given identifier, which revokes its token.
+// The stages the runner is executing at the time can't be completed anymore.
+func (c *Controller) Delete(ctx context.Context, identifier string) error {
+	runner, err := c.runnerStore.FindByIdentifier(ctx, identifier)
+	if err != nil {
+		return fmt.Errorf("failed to find runner: %w", err)
+	}
+
+	err = c.runnerStore.Delete(ctx, runner.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete runner: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/check"
+
+	"github.com/dchest/uniuri"
+)
+
+const (
+	tokenPrefix = "gnr_"
+	tokenLength = 40
+)
+
+type RegisterInput struct {
+	Identifier  string `json:"identifier"`
+	Description string `json:"description"`
+}
+
+// RegisterOutput holds the registered runner and its token, which is only ever returned once.
+type RegisterOutput struct {
+	Runner *types.Runner `json:"runner"`
+	Token  string        `json:"token"`
+}
+
+// Register registers a new remote runner and returns the token it authenticates with.
+func (c *Controller) Register(
+	ctx context.Context,
+	session *auth.Session,
+	in *RegisterInput,
+) (*RegisterOutput, error) {
+	if err := in.sanitize(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	runner := &types.Runner{
+		Identifier:  in.Identifier,
+		Description: in.Description,
+		CreatedBy:   session.Principal.ID,
+		Created:     now,
+		Updated:     now,
+	}
+
+	token := tokenPrefix + uniuri.NewLen(tokenLength)
+
+	err := c.runnerStore.Create(ctx, runner, hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runner: %w", err)
+	}
+
+	c.setStatus(runner)
+
+	return &RegisterOutput{
+		Runner: runner,
+		Token:  token,
+	}, nil
+}
+
+func (in *RegisterInput) sanitize() error {
+	in.Identifier = strings.TrimSpace(in.Identifier)
+	in.Description = strings.TrimSpace(in.Description)
+
+	if err := check.Identifier(in.Identifier); err != nil {
+		return err
+	}
+
+	return check.Description(in.Description)
+}
+
+// hashToken returns the hash of the runner token. The tokens are random and long enough,
+// so unlike passwords they don't require a slow hash function.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
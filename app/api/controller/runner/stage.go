@@ -0,0 +1,170 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/app/api/usererror"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/drone/runner-go/client"
+)
+
+// Request waits for the next stage the runner can execute. It returns nil if no stage
+// became available before the poll timeout, in which case the runner is expected to poll again.
+func (c *Controller) Request(
+	ctx context.Context,
+	runner *types.Runner,
+	filter *client.Filter,
+) (*drone.Stage, error) {
+	c.heartbeat(ctx, runner, &types.RunnerHeartbeat{
+		OS:     filter.OS,
+		Arch:   filter.Arch,
+		Labels: filter.Labels,
+		Time:   time.Now().UnixMilli(),
+	})
+
+	pollCtx, cancel := context.WithTimeout(ctx, c.config.CI.RemoteRunnerPollTimeout)
+	defer cancel()
+
+	stage, err := c.client.Request(pollCtx, filter)
+	if errors.Is(pollCtx.Err(), context.DeadlineExceeded) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to request stage: %w", err)
+	}
+
+	if stage == nil {
+		return nil, nil
+	}
+
+	// the offer is stored with the stage, so the runner can accept it through any instance.
+	err = c.stageStore.Offer(ctx, stage.ID, runner.ID, time.Now().Add(offerLifetime).UnixMilli())
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		// the stage got assigned in the meantime, the runner polls again.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to offer stage: %w", err)
+	}
+
+	return stage, nil
+}
+
+// Accept assigns the stage to the runner. Only a pending stage handed out to the runner by Request
+// can be accepted. It fails with a conflict if the stage is already assigned or isn't pending anymore.
+func (c *Controller) Accept(ctx context.Context, runner *types.Runner, stageID int64) (*drone.Stage, error) {
+	stage, err := c.stageStore.Find(ctx, stageID)
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return nil, usererror.Forbidden("Stage wasn't handed out to the runner")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stage: %w", err)
+	}
+
+	if stage.RunnerID == nil || *stage.RunnerID != runner.ID ||
+		time.Now().UnixMilli() >= stage.RunnerOfferExpires {
+		return nil, usererror.Forbidden("Stage wasn't handed out to the runner")
+	}
+
+	if stage.Machine != "" {
+		return nil, usererror.Conflict("Stage is already assigned")
+	}
+
+	if stage.Status != enum.CIStatusPending {
+		return nil, usererror.Conflict("Stage isn't pending")
+	}
+
+	droneStage := &drone.Stage{
+		ID:      stage.ID,
+		Machine: machineName(runner),
+	}
+
+	// the execution manager assigns the stage only if no other runner accepted it in the meantime.
+	err = c.client.Accept(ctx, droneStage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept stage: %w", err)
+	}
+
+	return droneStage, nil
+}
+
+// Detail returns everything the runner needs to execute the stage.
+func (c *Controller) Detail(ctx context.Context, runner *types.Runner, stageID int64) (*client.Context, error) {
+	stage, err := c.findOwnStage(ctx, runner, stageID)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := c.client.Detail(ctx, &drone.Stage{ID: stage.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stage details: %w", err)
+	}
+
+	return details, nil
+}
+
+// UpdateStage updates the stage the runner is executing. The returned stage includes the steps
+// along with the ids they were created with.
+func (c *Controller) UpdateStage(
+	ctx context.Context,
+	runner *types.Runner,
+	stageID int64,
+	in *drone.Stage,
+) (*drone.Stage, error) {
+	stage, err := c.findOwnStage(ctx, runner, stageID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the identity of the stage isn't up to the runner
+	in.ID = stage.ID
+	in.BuildID = stage.ExecutionID
+	in.Number = int(stage.Number)
+	in.Name = stage.Name
+	in.Machine = stage.Machine
+	for _, step := range in.Steps {
+		step.StageID = stage.ID
+	}
+
+	err = c.client.Update(ctx, in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update stage: %w", err)
+	}
+
+	return in, nil
+}
+
+// findOwnStage returns the stage with the given id if it's assigned to the runner.
+func (c *Controller) findOwnStage(ctx context.Context, runner *types.Runner, stageID int64) (*types.Stage, error) {
+	stage, err := c.stageStore.Find(ctx, stageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stage: %w", err)
+	}
+
+	if !isAssigned(stage, runner) {
+		return nil, usererror.ErrForbidden
+	}
+
+	return stage, nil
+}
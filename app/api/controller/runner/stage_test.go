@@ -0,0 +1,264 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/drone/runner-go/client"
+)
+
+type fakeRunnerStore struct {
+	store.RunnerStore
+}
+
+func (fakeRunnerStore) UpdateHeartbeat(context.Context, int64, *types.RunnerHeartbeat) error {
+	return nil
+}
+
+type fakeStageStore struct {
+	store.StageStore
+	stages map[int64]*types.Stage
+}
+
+func (s *fakeStageStore) Find(_ context.Context, id int64) (*types.Stage, error) {
+	stage, ok := s.stages[id]
+	if !ok {
+		return nil, gitness_store.ErrResourceNotFound
+	}
+	dup := *stage
+	return &dup, nil
+}
+
+func (s *fakeStageStore) Offer(_ context.Context, stageID, runnerID, expires int64) error {
+	stage, ok := s.stages[stageID]
+	if !ok || stage.Machine != "" {
+		return gitness_store.ErrResourceNotFound
+	}
+	stage.RunnerID = &runnerID
+	stage.RunnerOfferExpires = expires
+	return nil
+}
+
+// fakeClient hands out the queued stages and assigns the accepted stages like the execution manager.
+type fakeClient struct {
+	client.Client
+	stageStore *fakeStageStore
+	queue      []int64
+}
+
+func (c *fakeClient) Request(context.Context, *client.Filter) (*drone.Stage, error) {
+	if len(c.queue) == 0 {
+		return nil, errors.New("no stages queued")
+	}
+	id := c.queue[0]
+	c.queue = c.queue[1:]
+	return &drone.Stage{ID: id}, nil
+}
+
+func (c *fakeClient) Accept(_ context.Context, stage *drone.Stage) error {
+	c.stageStore.stages[stage.ID].Machine = stage.Machine
+	return nil
+}
+
+func (c *fakeClient) Detail(context.Context, *drone.Stage) (*client.Context, error) {
+	return &client.Context{}, nil
+}
+
+func setupStageTest() (*Controller, *fakeStageStore, *fakeClient) {
+	stageStore := &fakeStageStore{stages: map[int64]*types.Stage{
+		1: {ID: 1, Status: enum.CIStatusPending},
+		2: {ID: 2, Status: enum.CIStatusPending},
+		3: {ID: 3, Status: enum.CIStatusBlocked},
+		4: {ID: 4, Status: enum.CIStatusSuccess},
+	}}
+	fakeClient := &fakeClient{stageStore: stageStore}
+
+	config := &types.Config{}
+	config.CI.RemoteRunnerPollTimeout = time.Second
+
+	return NewController(config, fakeRunnerStore{}, stageStore, nil, fakeClient), stageStore, fakeClient
+}
+
+func requireErrorStatus(t *testing.T, err error, status int) {
+	t.Helper()
+
+	uErr := &usererror.Error{}
+	if !errors.As(err, &uErr) || uErr.Status != status {
+		t.Fatalf("want error with status %d, got %v", status, err)
+	}
+}
+
+func TestAccept(t *testing.T) {
+	ctx := context.Background()
+	runner := &types.Runner{ID: 1, Identifier: "runner"}
+	otherRunner := &types.Runner{ID: 2, Identifier: "other"}
+
+	tests := []struct {
+		name string
+		// queue are the stages handed out by Request before the stage is accepted.
+		queue      []int64
+		stageID    int64
+		runner     *types.Runner
+		modify     func(stageStore *fakeStageStore)
+		wantStatus int
+	}{
+		{
+			name:    "offered",
+			queue:   []int64{1},
+			stageID: 1,
+			runner:  runner,
+		},
+		{
+			name:       "not-offered",
+			queue:      []int64{1},
+			stageID:    2,
+			runner:     runner,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "offered-to-other-runner",
+			queue:      []int64{1},
+			stageID:    1,
+			runner:     otherRunner,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "blocked",
+			queue:      []int64{3},
+			stageID:    3,
+			runner:     runner,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "completed",
+			queue:      []int64{4},
+			stageID:    4,
+			runner:     runner,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:    "already-assigned",
+			queue:   []int64{1},
+			stageID: 1,
+			runner:  runner,
+			modify: func(stageStore *fakeStageStore) {
+				stageStore.stages[1].Machine = "other"
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:    "offer-expired",
+			queue:   []int64{1},
+			stageID: 1,
+			runner:  runner,
+			modify: func(stageStore *fakeStageStore) {
+				stageStore.stages[1].RunnerOfferExpires = time.Now().Add(-time.Second).UnixMilli()
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl, stageStore, fakeClient := setupStageTest()
+			fakeClient.queue = test.queue
+
+			for range test.queue {
+				if _, err := ctrl.Request(ctx, runner, &client.Filter{}); err != nil {
+					t.Fatalf("failed to request stage: %s", err)
+				}
+			}
+
+			if test.modify != nil {
+				test.modify(stageStore)
+			}
+
+			stage, err := ctrl.Accept(ctx, test.runner, test.stageID)
+			if test.wantStatus != 0 {
+				requireErrorStatus(t, err, test.wantStatus)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			want := machineName(test.runner)
+			if stage.Machine != want || stageStore.stages[test.stageID].Machine != want {
+				t.Errorf("want stage assigned to %s, got %s", want, stageStore.stages[test.stageID].Machine)
+			}
+
+			if _, err := ctrl.Detail(ctx, test.runner, test.stageID); err != nil {
+				t.Errorf("want details of the accepted stage, got %v", err)
+			}
+
+			// the offer is used up.
+			_, err = ctrl.Accept(ctx, test.runner, test.stageID)
+			requireErrorStatus(t, err, http.StatusConflict)
+		})
+	}
+}
+
+func TestDetail_NotAssigned(t *testing.T) {
+	ctx := context.Background()
+	runnerID := int64(1)
+
+	tests := []struct {
+		name   string
+		runner *types.Runner
+		modify func(stage *types.Stage)
+	}{
+		{
+			name:   "other-runner",
+			runner: &types.Runner{ID: 1, Identifier: "runner"},
+			modify: func(stage *types.Stage) {
+				stage.Machine = "runner-2"
+			},
+		},
+		{
+			// a remote runner named like the instance doesn't get the stages of the embedded runner,
+			// even if the stage was offered to it before.
+			name:   "embedded-runner",
+			runner: &types.Runner{ID: runnerID, Identifier: "gitness"},
+			modify: func(stage *types.Stage) {
+				stage.Machine = "gitness"
+				stage.RunnerID = &runnerID
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl, stageStore, _ := setupStageTest()
+			test.modify(stageStore.stages[1])
+
+			_, err := ctrl.Detail(ctx, test.runner, 1)
+			if !errors.Is(err, usererror.ErrForbidden) {
+				t.Fatalf("want error %v, got %v", usererror.ErrForbidden, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/types"
+
+	"github.com/drone/drone-go/drone"
+)
+
+// UpdateStep updates a step of a stage the runner is executing.
+func (c *Controller) UpdateStep(
+	ctx context.Context,
+	runner *types.Runner,
+	stepID int64,
+	in *drone.Step,
+) (*drone.Step, error) {
+	step, err := c.findOwnStep(ctx, runner, stepID)
+	if err != nil {
+		return nil, err
+	}
+
+	in.ID = step.ID
+	in.StageID = step.StageID
+	in.Number = int(step.Number)
+
+	err = c.client.UpdateStep(ctx, in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update step: %w", err)
+	}
+
+	return in, nil
+}
+
+// WriteLogs streams the log lines of a step the runner is executing.
+func (c *Controller) WriteLogs(
+	ctx context.Context,
+	runner *types.Runner,
+	stepID int64,
+	lines []*drone.Line,
+) error {
+	step, err := c.findOwnStep(ctx, runner, stepID)
+	if err != nil {
+		return err
+	}
+
+	err = c.client.Batch(ctx, step.ID, lines)
+	if err != nil {
+		return fmt.Errorf("failed to write logs: %w", err)
+	}
+
+	return nil
+}
+
+// UploadLogs stores the complete logs of a step the runner has finished executing.
+func (c *Controller) UploadLogs(
+	ctx context.Context,
+	runner *types.Runner,
+	stepID int64,
+	lines []*drone.Line,
+) error {
+	step, err := c.findOwnStep(ctx, runner, stepID)
+	if err != nil {
+		return err
+	}
+
+	err = c.client.Upload(ctx, step.ID, lines)
+	if err != nil {
+		return fmt.Errorf("failed to upload logs: %w", err)
+	}
+
+	return nil
+}
+
+// findOwnStep returns the step with the given id if its stage is assigned to the runner.
+func (c *Controller) findOwnStep(ctx context.Context, runner *types.Runner, stepID int64) (*types.Step, error) {
+	step, err := c.stepStore.Find(ctx, stepID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find step: %w", err)
+	}
+
+	_, err = c.findOwnStage(ctx, runner, step.StageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return step, nil
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/harness/gitness/app/auth/authz"
+	"github.com/harness/gitness/app/services/refcache"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/check"
@@ -30,11 +31,12 @@ type Controller struct {
 	spaceStore        store.SpaceStore
 	repoStore         store.RepoStore
 	tokenStore        store.TokenStore
+	repoFinder        refcache.RepoFinder
 }
 
 func NewController(principalUIDCheck check.PrincipalUID, authorizer authz.Authorizer,
 	principalStore store.PrincipalStore, spaceStore store.SpaceStore, repoStore store.RepoStore,
-	tokenStore store.TokenStore) *Controller {
+	tokenStore store.TokenStore, repoFinder refcache.RepoFinder) *Controller {
 	return &Controller{
 		principalUIDCheck: principalUIDCheck,
 		authorizer:        authorizer,
@@ -42,6 +44,7 @@ func NewController(principalUIDCheck check.PrincipalUID, authorizer authz.Author
 		spaceStore:        spaceStore,
 		repoStore:         repoStore,
 		tokenStore:        tokenStore,
+		repoFinder:        repoFinder,
 	}
 }
 
@@ -32,6 +32,8 @@ type CreateTokenInput struct {
 	UID        string         `json:"uid" deprecated:"true"`
 	Identifier string         `json:"identifier"`
 	Lifetime   *time.Duration `json:"lifetime"`
+	// Scope optionally restricts the token to a set of permissions, spaces and repositories.
+	Scope *types.TokenScopeInput `json:"scope"`
 }
 
 // CreateToken creates a new service account access token.
@@ -56,6 +58,11 @@ func (c *Controller) CreateToken(
 		return nil, err
 	}
 
+	scope, err := apiauth.ResolveTokenScope(ctx, c.authorizer, session, c.spaceStore, c.repoFinder, in.Scope)
+	if err != nil {
+		return nil, err
+	}
+
 	token, jwtToken, err := token.CreateSAT(
 		ctx,
 		c.tokenStore,
@@ -63,6 +70,7 @@ func (c *Controller) CreateToken(
 		sa,
 		in.Identifier,
 		in.Lifetime,
+		scope,
 	)
 	if err != nil {
 		return nil, err
@@ -16,9 +16,11 @@ package serviceaccount
 
 import (
 	"context"
+	"fmt"
 
 	apiauth "github.com/harness/gitness/app/api/auth"
 	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/token"
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
 )
@@ -37,5 +39,14 @@ func (c *Controller) ListTokens(ctx context.Context, session *auth.Session,
 		return nil, err
 	}
 
-	return c.tokenStore.List(ctx, sa.ID, enum.TokenTypeSAT)
+	tokens, err := c.tokenStore.List(ctx, sa.ID, enum.TokenTypeSAT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tokens: %w", err)
+	}
+
+	if err = token.PopulateScopePaths(ctx, c.spaceStore, c.repoFinder, tokens); err != nil {
+		return nil, fmt.Errorf("failed to populate token scopes: %w", err)
+	}
+
+	return tokens, nil
 }
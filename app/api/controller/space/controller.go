@@ -93,6 +93,8 @@ type Controller struct {
 	spaceCache       refcache.SpaceCache
 	importer         *importer.Repository
 	exporter         *exporter.Repository
+	archiveImporter  *importer.Archive
+	archiveExporter  *exporter.Archive
 	resourceLimiter  limiter.ResourceLimiter
 	publicAccess     publicaccess.Service
 	auditService     audit.Service
@@ -112,6 +114,7 @@ func NewController(config *types.Config, tx dbtx.Transactor, urlProvider url.Pro
 	membershipStore store.MembershipStore, prListService *pullreq.ListService,
 	spaceCache refcache.SpaceCache,
 	importer *importer.Repository, exporter *exporter.Repository,
+	archiveExporter *exporter.Archive, archiveImporter *importer.Archive,
 	limiter limiter.ResourceLimiter, publicAccess publicaccess.Service, auditService audit.Service,
 	gitspaceSvc *gitspace.Service, labelSvc *label.Service,
 	instrumentation instrument.Service, executionStore store.ExecutionStore,
@@ -138,6 +141,8 @@ func NewController(config *types.Config, tx dbtx.Transactor, urlProvider url.Pro
 		spaceCache:          spaceCache,
 		importer:            importer,
 		exporter:            exporter,
+		archiveImporter:     archiveImporter,
+		archiveExporter:     archiveExporter,
 		resourceLimiter:     limiter,
 		publicAccess:        publicAccess,
 		auditService:        auditService,
@@ -0,0 +1,140 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package space
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/harness/gitness/app/api/controller/limiter"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/services/importer"
+	"github.com/harness/gitness/job"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+type ImportArchiveInput struct {
+	CreateInput
+}
+
+// ImportArchive creates a new space and starts a background job that imports the space archive into it.
+// The archive is extracted before the space is created, so an invalid archive doesn't leave a space behind.
+// If the import job fails, all imported data is removed from the space, the failure is reported by
+// ImportArchiveProgress.
+func (c *Controller) ImportArchive(
+	ctx context.Context,
+	session *auth.Session,
+	in *ImportArchiveInput,
+	r io.Reader,
+) (*SpaceOutput, error) {
+	parentSpace, err := c.getSpaceCheckAuthSpaceCreation(ctx, session, in.ParentRef)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := c.archiveImporter.Open(ctx, r)
+	if err != nil {
+		return nil, translateArchiveError(err)
+	}
+
+	// the import job works on the copy of the archive that is staged by Run.
+	defer func() {
+		if errClose := archive.Close(); errClose != nil {
+			log.Ctx(ctx).Warn().Err(errClose).Msg("failed to remove extracted space archive")
+		}
+	}()
+
+	if in.Identifier == "" && in.UID == "" {
+		in.Identifier = archive.Space.Identifier
+	}
+	if in.Description == "" {
+		in.Description = archive.Space.Description
+	}
+
+	err = c.sanitizeCreateInput(&in.CreateInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sanitize input: %w", err)
+	}
+
+	var space *types.Space
+	err = c.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := c.resourceLimiter.RepoCount(
+			ctx, parentSpace.ID, len(archive.Space.Repositories)); err != nil {
+			return fmt.Errorf("resource limit exceeded: %w", limiter.ErrMaxNumReposReached)
+		}
+
+		space, err = c.createSpaceInnerInTX(ctx, session, parentSpace.ID, &in.CreateInput)
+		if err != nil {
+			return err
+		}
+
+		// the job is started in the transaction, so the space isn't created without it.
+		return c.archiveImporter.Run(ctx, &session.Principal, space, archive)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.publicAccess.Set(ctx, enum.PublicResourceTypeSpace, space.Path, in.IsPublic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set space public access: %w", err)
+	}
+
+	return GetSpaceOutput(ctx, c.publicAccess, space)
+}
+
+// ImportArchiveProgress returns the progress of the space archive import into the space.
+func (c *Controller) ImportArchiveProgress(
+	ctx context.Context,
+	session *auth.Session,
+	spaceRef string,
+) (job.Progress, error) {
+	space, err := c.getSpaceCheckAuth(ctx, session, spaceRef, enum.PermissionSpaceView)
+	if err != nil {
+		return job.Progress{}, fmt.Errorf("failed to acquire access to space: %w", err)
+	}
+
+	progress, err := c.archiveImporter.GetProgress(ctx, space)
+	if errors.Is(err, importer.ErrNotFound) {
+		return job.Progress{}, usererror.NotFound("No recent or ongoing archive import found for space.")
+	}
+	if err != nil {
+		return job.Progress{}, fmt.Errorf("failed to retrieve archive import progress: %w", err)
+	}
+
+	return progress, nil
+}
+
+func translateArchiveError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		// translated to request too large.
+		return err
+	case errors.Is(err, importer.ErrArchiveTooLarge):
+		return usererror.RequestTooLargef("%s", err.Error())
+	case errors.Is(err, importer.ErrInvalidArchive):
+		return usererror.BadRequest(err.Error())
+	default:
+		return fmt.Errorf("failed to open space archive: %w", err)
+	}
+}
@@ -58,6 +58,10 @@ func (c *Controller) PurgeNoAuth(
 	session *auth.Session,
 	space *types.Space,
 ) error {
+	if err := c.repoCtrl.CheckDeletionsAllowed(ctx); err != nil {
+		return err
+	}
+
 	// the max time we give a purge space to succeed
 	const timeout = 15 * time.Minute
 	// create new, time-restricted context to guarantee space purge completion, even if request is canceled.
@@ -51,6 +51,7 @@ func ProvideController(config *types.Config, tx dbtx.Transactor, urlProvider url
 	repoCtrl *repo.Controller, membershipStore store.MembershipStore, prListService *pullreq.ListService,
 	spaceCache refcache.SpaceCache,
 	importer *importer.Repository, exporter *exporter.Repository,
+	archiveExporter *exporter.Archive, archiveImporter *importer.Archive,
 	limiter limiter.ResourceLimiter, publicAccess publicaccess.Service,
 	auditService audit.Service, gitspaceService *gitspace.Service,
 	labelSvc *label.Service, instrumentation instrument.Service, executionStore store.ExecutionStore,
@@ -63,7 +64,7 @@ func ProvideController(config *types.Config, tx dbtx.Transactor, urlProvider url
 		spaceStore, repoStore, principalStore,
 		repoCtrl, membershipStore, prListService,
 		spaceCache,
-		importer, exporter, limiter, publicAccess,
+		importer, exporter, archiveExporter, archiveImporter, limiter, publicAccess,
 		auditService, gitspaceService,
 		labelSvc, instrumentation, executionStore,
 		rulesSvc, usageMetricStore,
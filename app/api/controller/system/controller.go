@@ -17,23 +17,39 @@ package system
 import (
 	"context"
 
+	systemsvc "github.com/harness/gitness/app/services/system"
 	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/registry/gc"
 	"github.com/harness/gitness/types"
 )
 
 type Controller struct {
 	principalStore store.PrincipalStore
+	systemService  *systemsvc.Service
+	gcService      gc.Service
 	config         *types.Config
 }
 
-func NewController(principalStore store.PrincipalStore, config *types.Config) *Controller {
+func NewController(
+	principalStore store.PrincipalStore,
+	systemService *systemsvc.Service,
+	gcService gc.Service,
+	config *types.Config,
+) *Controller {
 	return &Controller{
 		principalStore: principalStore,
+		systemService:  systemService,
+		gcService:      gcService,
 		config:         config,
 	}
 }
 
 func (c *Controller) IsUserSignupAllowed(ctx context.Context) (bool, error) {
+	// with single sign-on the users are provisioned by the identity provider.
+	if !c.IsPasswordLoginAllowed() {
+		return false, nil
+	}
+
 	usrCount, err := c.principalStore.CountUsers(ctx, &types.UserFilter{})
 	if err != nil {
 		return false, err
@@ -41,3 +57,8 @@ func (c *Controller) IsUserSignupAllowed(ctx context.Context) (bool, error) {
 
 	return usrCount == 0 || c.config.UserSignupEnabled, nil
 }
+
+// IsPasswordLoginAllowed returns true if users are allowed to sign in with local passwords.
+func (c *Controller) IsPasswordLoginAllowed() bool {
+	return !c.config.OIDC.PasswordLoginDisabled
+}
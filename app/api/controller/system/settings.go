@@ -0,0 +1,91 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package system
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	systemsvc "github.com/harness/gitness/app/services/system"
+	"github.com/harness/gitness/types/enum"
+)
+
+// SettingsOutput holds the system settings that can be managed by admins.
+type SettingsOutput struct {
+	TwoFactorRequirement enum.TwoFactorRequirement `json:"two_factor_requirement"`
+}
+
+type UpdateSettingsInput struct {
+	TwoFactorRequirement *enum.TwoFactorRequirement `json:"two_factor_requirement"`
+}
+
+// FindSettings returns the system settings.
+func (c *Controller) FindSettings(ctx context.Context, session *auth.Session) (*SettingsOutput, error) {
+	if !session.Principal.Admin {
+		return nil, usererror.ErrForbidden
+	}
+
+	return c.findSettings(ctx)
+}
+
+// UpdateSettings updates the system settings.
+func (c *Controller) UpdateSettings(
+	ctx context.Context,
+	session *auth.Session,
+	in *UpdateSettingsInput,
+) (*SettingsOutput, error) {
+	if !session.Principal.Admin {
+		return nil, usererror.ErrForbidden
+	}
+
+	if err := in.sanitize(); err != nil {
+		return nil, err
+	}
+
+	settings := &systemsvc.Settings{
+		TwoFactorRequirement: in.TwoFactorRequirement,
+	}
+
+	if _, err := c.systemService.Update(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to update system settings: %w", err)
+	}
+
+	return c.findSettings(ctx)
+}
+
+func (c *Controller) findSettings(ctx context.Context) (*SettingsOutput, error) {
+	settings, err := c.systemService.Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find system settings: %w", err)
+	}
+
+	return &SettingsOutput{
+		TwoFactorRequirement: *settings.TwoFactorRequirement,
+	}, nil
+}
+
+func (in *UpdateSettingsInput) sanitize() error {
+	if in.TwoFactorRequirement != nil {
+		requirement, ok := in.TwoFactorRequirement.Sanitize()
+		if !ok {
+			return usererror.BadRequestf("Invalid two-factor requirement %q", *in.TwoFactorRequirement)
+		}
+		in.TwoFactorRequirement = &requirement
+	}
+
+	return nil
+}
@@ -15,7 +15,9 @@
 package system
 
 import (
+	systemsvc "github.com/harness/gitness/app/services/system"
 	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/registry/gc"
 	"github.com/harness/gitness/types"
 
 	"github.com/google/wire"
@@ -26,6 +28,11 @@ var WireSet = wire.NewSet(
 	NewController,
 )
 
-func ProvideController(principalStore store.PrincipalStore, config *types.Config) *Controller {
-	return NewController(principalStore, config)
+func ProvideController(
+	principalStore store.PrincipalStore,
+	systemService *systemsvc.Service,
+	gcService gc.Service,
+	config *types.Config,
+) *Controller {
+	return NewController(principalStore, systemService, gcService, config)
 }
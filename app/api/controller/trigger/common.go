@@ -15,6 +15,11 @@
 package trigger
 
 import (
+	"time"
+
+	triggersvc "github.com/harness/gitness/app/services/trigger"
+	gitcheck "github.com/harness/gitness/git/check"
+	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/check"
 	"github.com/harness/gitness/types/enum"
 )
@@ -48,6 +53,53 @@ func checkActions(actions []enum.TriggerAction) error {
 	return nil
 }
 
+// checkCron validates the cron schedule of a trigger.
+func checkCron(cron, branch, timezone string, actions []enum.TriggerAction) error {
+	if cron == "" {
+		if branch != "" || timezone != "" {
+			return check.NewValidationError("Branch and timezone can only be set for cron triggers.")
+		}
+		return nil
+	}
+
+	if len(actions) > 0 {
+		return check.NewValidationError("A cron trigger can't have any actions.")
+	}
+
+	if branch != "" {
+		if err := gitcheck.BranchName(branch); err != nil {
+			return check.NewValidationErrorf("The provided branch is invalid: %s", err)
+		}
+	}
+
+	if _, err := triggersvc.NextCronRun(cron, timezone, time.Now()); err != nil {
+		return check.NewValidationErrorf("The provided cron schedule is invalid: %s", err)
+	}
+
+	return nil
+}
+
+// setCronSchedule updates the type and the next cron run of the trigger.
+func setCronSchedule(trigger *types.Trigger) error {
+	if trigger.Cron == "" {
+		if trigger.Type == enum.TriggerCron {
+			trigger.Type = ""
+		}
+		trigger.CronNext = 0
+		return nil
+	}
+
+	next, err := triggersvc.NextCronRun(trigger.Cron, trigger.Timezone, time.Now())
+	if err != nil {
+		return check.NewValidationErrorf("The provided cron schedule is invalid: %s", err)
+	}
+
+	trigger.Type = enum.TriggerCron
+	trigger.CronNext = next.UnixMilli()
+
+	return nil
+}
+
 // deduplicateActions de-duplicates the actions provided by in the trigger.
 func deduplicateActions(in []enum.TriggerAction) []enum.TriggerAction {
 	if len(in) == 0 {
@@ -17,6 +17,7 @@ package trigger
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	apiauth "github.com/harness/gitness/app/api/auth"
@@ -35,6 +36,9 @@ type CreateInput struct {
 	Secret     string               `json:"secret"`
 	Disabled   bool                 `json:"disabled"`
 	Actions    []enum.TriggerAction `json:"actions"`
+	Cron       string               `json:"cron"`
+	Branch     string               `json:"branch"`
+	Timezone   string               `json:"timezone"`
 }
 
 func (c *Controller) Create(
@@ -75,10 +79,17 @@ func (c *Controller) Create(
 		Actions:     deduplicateActions(in.Actions),
 		Identifier:  in.Identifier,
 		PipelineID:  pipeline.ID,
+		Cron:        in.Cron,
+		Branch:      in.Branch,
+		Timezone:    in.Timezone,
 		Created:     now,
 		Updated:     now,
 		Version:     0,
 	}
+	if err = setCronSchedule(trigger); err != nil {
+		return nil, err
+	}
+
 	err = c.triggerStore.Create(ctx, trigger)
 	if err != nil {
 		return nil, fmt.Errorf("trigger creation failed: %w", err)
@@ -102,6 +113,12 @@ func (c *Controller) sanitizeCreateInput(in *CreateInput) error {
 	if err := checkActions(in.Actions); err != nil {
 		return err
 	}
+	in.Cron = strings.TrimSpace(in.Cron)
+	in.Branch = strings.TrimSpace(in.Branch)
+	in.Timezone = strings.TrimSpace(in.Timezone)
+	if err := checkCron(in.Cron, in.Branch, in.Timezone, in.Actions); err != nil {
+		return err
+	}
 	if err := check.Identifier(in.Identifier); err != nil { //nolint:revive
 		return err
 	}
@@ -35,6 +35,9 @@ type UpdateInput struct {
 	Actions    []enum.TriggerAction `json:"actions"`
 	Secret     *string              `json:"secret"`
 	Disabled   *bool                `json:"disabled"` // can be nil, so keeping it a pointer
+	Cron       *string              `json:"cron"`
+	Branch     *string              `json:"branch"`
+	Timezone   *string              `json:"timezone"`
 }
 
 func (c *Controller) Update(
@@ -88,8 +91,21 @@ func (c *Controller) Update(
 			if in.Disabled != nil {
 				original.Disabled = *in.Disabled
 			}
+			if in.Cron != nil {
+				original.Cron = *in.Cron
+			}
+			if in.Branch != nil {
+				original.Branch = *in.Branch
+			}
+			if in.Timezone != nil {
+				original.Timezone = *in.Timezone
+			}
+
+			if err := checkCron(original.Cron, original.Branch, original.Timezone, original.Actions); err != nil {
+				return err
+			}
 
-			return nil
+			return setCronSchedule(original)
 		})
 }
 
@@ -124,5 +140,15 @@ func (c *Controller) sanitizeUpdateInput(in *UpdateInput) error {
 		}
 	}
 
+	if in.Cron != nil {
+		*in.Cron = strings.TrimSpace(*in.Cron)
+	}
+	if in.Branch != nil {
+		*in.Branch = strings.TrimSpace(*in.Branch)
+	}
+	if in.Timezone != nil {
+		*in.Timezone = strings.TrimSpace(*in.Timezone)
+	}
+
 	return nil
 }
@@ -0,0 +1,303 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/dchest/uniuri"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	maxChatExternalIDLength     = 256
+	chatConfirmationCodeLength  = 8
+	chatConfirmationLifetime    = 15 * time.Minute
+	chatConfirmationMaxAttempts = 5
+)
+
+type LinkChatIdentityInput struct {
+	ExternalID string `json:"external_id"`
+}
+
+type ConfirmChatIdentityInput struct {
+	Code string `json:"code"`
+}
+
+// ListChatIdentities returns the chat identities linked by the user.
+func (c *Controller) ListChatIdentities(
+	ctx context.Context,
+	session *auth.Session,
+	userUID string,
+) ([]*types.UserChatIdentity, error) {
+	user, err := c.principalStore.FindUserByUID(ctx, userUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user by uid: %w", err)
+	}
+
+	if err = apiauth.CheckUser(ctx, c.authorizer, session, user, enum.PermissionUserView); err != nil {
+		return nil, err
+	}
+
+	identities, err := c.chatIdentityStore.List(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chat identities: %w", err)
+	}
+
+	return identities, nil
+}
+
+// LinkChatIdentity links the user to their account on the chat service,
+// replacing the account linked before for the same service. The identity stays unverified
+// and receives no notifications until the user confirms it with the code sent to the account
+// as direct message. Chat services the messages can't be sent on require an administrator to link the identity.
+func (c *Controller) LinkChatIdentity(
+	ctx context.Context,
+	session *auth.Session,
+	userUID string,
+	provider enum.ChatProvider,
+	in *LinkChatIdentityInput,
+) (*types.UserChatIdentity, error) {
+	user, err := c.principalStore.FindUserByUID(ctx, userUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user by uid: %w", err)
+	}
+
+	if err = apiauth.CheckUser(ctx, c.authorizer, session, user, enum.PermissionUserEdit); err != nil {
+		return nil, err
+	}
+
+	provider, err = sanitizeChatProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = in.sanitize(); err != nil {
+		return nil, err
+	}
+
+	if !c.chatMessenger.CanSendDirectMessages(provider) {
+		return nil, usererror.BadRequestf(
+			"The ownership of %s accounts can't be confirmed, they have to be linked by an administrator.", provider)
+	}
+
+	code := uniuri.NewLenChars(chatConfirmationCodeLength, recoveryCodeChars)
+
+	now := time.Now()
+	identity := &types.UserChatIdentity{
+		PrincipalID:          user.ID,
+		Provider:             provider,
+		ExternalID:           in.ExternalID,
+		Verified:             false,
+		Created:              now.UnixMilli(),
+		Updated:              now.UnixMilli(),
+		ConfirmationHash:     hashChatConfirmationCode(code),
+		ConfirmationExpires:  now.Add(chatConfirmationLifetime).UnixMilli(),
+		ConfirmationAttempts: 0,
+	}
+
+	err = c.chatIdentityStore.Upsert(ctx, identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to link chat identity: %w", err)
+	}
+
+	text := fmt.Sprintf("Your code to confirm the chat identity of %s is %s. It expires in %d minutes.",
+		user.UID, code, int(chatConfirmationLifetime.Minutes()))
+
+	err = c.chatMessenger.SendDirectMessage(ctx, provider, identity.ExternalID, text)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to send chat identity confirmation to %s", identity.ExternalID)
+		return nil, usererror.BadRequestf("Failed to send the confirmation code to the %s account.", provider)
+	}
+
+	return identity, nil
+}
+
+// ConfirmChatIdentity verifies the chat identity linked by the user with the code sent to the account.
+func (c *Controller) ConfirmChatIdentity(
+	ctx context.Context,
+	session *auth.Session,
+	userUID string,
+	provider enum.ChatProvider,
+	in *ConfirmChatIdentityInput,
+) (*types.UserChatIdentity, error) {
+	user, err := c.principalStore.FindUserByUID(ctx, userUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user by uid: %w", err)
+	}
+
+	if err = apiauth.CheckUser(ctx, c.authorizer, session, user, enum.PermissionUserEdit); err != nil {
+		return nil, err
+	}
+
+	provider, err = sanitizeChatProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := c.chatIdentityStore.Find(ctx, user.ID, provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find chat identity: %w", err)
+	}
+
+	if identity.Verified {
+		return identity, nil
+	}
+
+	if identity.ConfirmationHash == "" ||
+		identity.ConfirmationAttempts >= chatConfirmationMaxAttempts ||
+		time.Now().UnixMilli() > identity.ConfirmationExpires {
+		return nil, usererror.BadRequest("The confirmation code expired, link the chat identity again.")
+	}
+
+	hash := hashChatConfirmationCode(strings.TrimSpace(in.Code))
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(identity.ConfirmationHash)) != 1 {
+		identity.ConfirmationAttempts++
+		identity.Updated = time.Now().UnixMilli()
+		if err = c.chatIdentityStore.Upsert(ctx, identity); err != nil {
+			return nil, fmt.Errorf("failed to record failed chat identity confirmation: %w", err)
+		}
+
+		return nil, usererror.BadRequest("The confirmation code is invalid.")
+	}
+
+	identity.Verified = true
+	identity.Updated = time.Now().UnixMilli()
+	identity.ConfirmationHash = ""
+	identity.ConfirmationExpires = 0
+	identity.ConfirmationAttempts = 0
+
+	err = c.chatIdentityStore.Upsert(ctx, identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to confirm chat identity: %w", err)
+	}
+
+	return identity, nil
+}
+
+// LinkChatIdentityAdmin links the user to their account on the chat service on behalf of the user.
+// The administrator vouches for the ownership of the account, so the identity is verified right away.
+func (c *Controller) LinkChatIdentityAdmin(
+	ctx context.Context,
+	session *auth.Session,
+	userUID string,
+	provider enum.ChatProvider,
+	in *LinkChatIdentityInput,
+) (*types.UserChatIdentity, error) {
+	user, err := c.principalStore.FindUserByUID(ctx, userUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user by uid: %w", err)
+	}
+
+	if err = apiauth.CheckUser(ctx, c.authorizer, session, user, enum.PermissionUserEditAdmin); err != nil {
+		return nil, err
+	}
+
+	provider, err = sanitizeChatProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = in.sanitize(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	identity := &types.UserChatIdentity{
+		PrincipalID: user.ID,
+		Provider:    provider,
+		ExternalID:  in.ExternalID,
+		Verified:    true,
+		Created:     now,
+		Updated:     now,
+	}
+
+	err = c.chatIdentityStore.Upsert(ctx, identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to link chat identity: %w", err)
+	}
+
+	return identity, nil
+}
+
+// UnlinkChatIdentity unlinks the account of the user on the chat service.
+func (c *Controller) UnlinkChatIdentity(
+	ctx context.Context,
+	session *auth.Session,
+	userUID string,
+	provider enum.ChatProvider,
+) error {
+	user, err := c.principalStore.FindUserByUID(ctx, userUID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch user by uid: %w", err)
+	}
+
+	if err = apiauth.CheckUser(ctx, c.authorizer, session, user, enum.PermissionUserEdit); err != nil {
+		return err
+	}
+
+	provider, err = sanitizeChatProvider(provider)
+	if err != nil {
+		return err
+	}
+
+	err = c.chatIdentityStore.Delete(ctx, user.ID, provider)
+	if err != nil {
+		return fmt.Errorf("failed to unlink chat identity: %w", err)
+	}
+
+	return nil
+}
+
+func sanitizeChatProvider(provider enum.ChatProvider) (enum.ChatProvider, error) {
+	provider, ok := provider.Sanitize()
+	if !ok {
+		return "", usererror.BadRequestf("Unknown chat provider %q.", provider)
+	}
+
+	return provider, nil
+}
+
+func hashChatConfirmationCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+func (in *LinkChatIdentityInput) sanitize() error {
+	in.ExternalID = strings.TrimSpace(in.ExternalID)
+
+	if in.ExternalID == "" {
+		return usererror.BadRequest("The external ID of the chat identity is required.")
+	}
+
+	if len(in.ExternalID) > maxChatExternalIDLength {
+		return usererror.BadRequestf("The external ID of the chat identity can have at most %d characters.",
+			maxChatExternalIDLength)
+	}
+
+	return nil
+}
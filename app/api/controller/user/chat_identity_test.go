@@ -0,0 +1,250 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/services/refcache"
+	appstore "github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+// fakeChatIdentityAuthorizer grants the admin permissions only to admins.
+type fakeChatIdentityAuthorizer struct{}
+
+func (fakeChatIdentityAuthorizer) Check(
+	_ context.Context,
+	session *auth.Session,
+	_ *types.Scope,
+	_ *types.Resource,
+	permission enum.Permission,
+) (bool, error) {
+	return permission != enum.PermissionUserEditAdmin || session.Principal.Admin, nil
+}
+
+func (a fakeChatIdentityAuthorizer) CheckAll(
+	ctx context.Context,
+	session *auth.Session,
+	permissionChecks ...types.PermissionCheck,
+) (bool, error) {
+	for _, p := range permissionChecks {
+		if ok, err := a.Check(ctx, session, &p.Scope, &p.Resource, p.Permission); err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+type fakeChatIdentityStore struct {
+	appstore.UserChatIdentityStore
+	identities map[enum.ChatProvider]*types.UserChatIdentity
+}
+
+func (s *fakeChatIdentityStore) Find(
+	_ context.Context,
+	_ int64,
+	provider enum.ChatProvider,
+) (*types.UserChatIdentity, error) {
+	identity, ok := s.identities[provider]
+	if !ok {
+		return nil, store.ErrResourceNotFound
+	}
+	dup := *identity
+	return &dup, nil
+}
+
+func (s *fakeChatIdentityStore) Upsert(_ context.Context, identity *types.UserChatIdentity) error {
+	dup := *identity
+	s.identities[identity.Provider] = &dup
+	return nil
+}
+
+type fakeChatMessenger struct {
+	messages []string
+}
+
+func (*fakeChatMessenger) CanSendDirectMessages(provider enum.ChatProvider) bool {
+	return provider == enum.ChatProviderSlack
+}
+
+func (m *fakeChatMessenger) SendDirectMessage(_ context.Context, _ enum.ChatProvider, _, text string) error {
+	m.messages = append(m.messages, text)
+	return nil
+}
+
+var chatConfirmationCodeRegexp = regexp.MustCompile(`is ([a-z0-9]{8})\.`)
+
+func setupChatIdentityTest(t *testing.T) (*Controller, *auth.Session, *fakeChatIdentityStore, *fakeChatMessenger) {
+	t.Helper()
+
+	user := &types.User{ID: 1, UID: "alice"}
+	identityStore := &fakeChatIdentityStore{identities: map[enum.ChatProvider]*types.UserChatIdentity{}}
+	messenger := &fakeChatMessenger{}
+
+	ctrl := NewController(&types.Config{}, nil, nil, fakeChatIdentityAuthorizer{},
+		&fakeLoginPrincipalStore{users: []*types.User{user}},
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, refcache.RepoFinder{}, nil,
+		identityStore, messenger)
+
+	return ctrl, &auth.Session{Principal: *user.ToPrincipal()}, identityStore, messenger
+}
+
+func linkChatIdentity(t *testing.T, ctrl *Controller, session *auth.Session, messenger *fakeChatMessenger) string {
+	t.Helper()
+
+	identity, err := ctrl.LinkChatIdentity(context.Background(), session, "alice", enum.ChatProviderSlack,
+		&LinkChatIdentityInput{ExternalID: " U001 "})
+	if err != nil {
+		t.Fatalf("failed to link chat identity: %s", err)
+	}
+	if identity.Verified {
+		t.Fatalf("chat identity is verified before it was confirmed")
+	}
+
+	if len(messenger.messages) == 0 {
+		t.Fatalf("no confirmation code has been sent")
+	}
+
+	match := chatConfirmationCodeRegexp.FindStringSubmatch(messenger.messages[len(messenger.messages)-1])
+	if match == nil {
+		t.Fatalf("no confirmation code in message %q", messenger.messages[len(messenger.messages)-1])
+	}
+
+	return match[1]
+}
+
+func requireBadRequest(t *testing.T, err error) {
+	t.Helper()
+
+	var uErr *usererror.Error
+	if !errors.As(err, &uErr) || uErr.Status != 400 {
+		t.Fatalf("expected bad request, got: %v", err)
+	}
+}
+
+func TestConfirmChatIdentity(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("valid code verifies identity", func(t *testing.T) {
+		ctrl, session, identityStore, messenger := setupChatIdentityTest(t)
+		code := linkChatIdentity(t, ctrl, session, messenger)
+
+		identity, err := ctrl.ConfirmChatIdentity(ctx, session, "alice", enum.ChatProviderSlack,
+			&ConfirmChatIdentityInput{Code: code})
+		if err != nil {
+			t.Fatalf("failed to confirm chat identity: %s", err)
+		}
+
+		stored := identityStore.identities[enum.ChatProviderSlack]
+		if !identity.Verified || !stored.Verified || stored.ExternalID != "U001" || stored.ConfirmationHash != "" {
+			t.Fatalf("chat identity hasn't been verified: %+v", stored)
+		}
+	})
+
+	t.Run("invalid code is rejected", func(t *testing.T) {
+		ctrl, session, identityStore, messenger := setupChatIdentityTest(t)
+		linkChatIdentity(t, ctrl, session, messenger)
+
+		_, err := ctrl.ConfirmChatIdentity(ctx, session, "alice", enum.ChatProviderSlack,
+			&ConfirmChatIdentityInput{Code: "wrong"})
+		requireBadRequest(t, err)
+
+		stored := identityStore.identities[enum.ChatProviderSlack]
+		if stored.Verified || stored.ConfirmationAttempts != 1 {
+			t.Fatalf("failed attempt hasn't been recorded: %+v", stored)
+		}
+	})
+
+	t.Run("code is unusable after too many attempts", func(t *testing.T) {
+		ctrl, session, _, messenger := setupChatIdentityTest(t)
+		code := linkChatIdentity(t, ctrl, session, messenger)
+
+		for range chatConfirmationMaxAttempts {
+			_, err := ctrl.ConfirmChatIdentity(ctx, session, "alice", enum.ChatProviderSlack,
+				&ConfirmChatIdentityInput{Code: "wrong"})
+			requireBadRequest(t, err)
+		}
+
+		_, err := ctrl.ConfirmChatIdentity(ctx, session, "alice", enum.ChatProviderSlack,
+			&ConfirmChatIdentityInput{Code: code})
+		requireBadRequest(t, err)
+	})
+
+	t.Run("expired code is rejected", func(t *testing.T) {
+		ctrl, session, identityStore, messenger := setupChatIdentityTest(t)
+		code := linkChatIdentity(t, ctrl, session, messenger)
+
+		identityStore.identities[enum.ChatProviderSlack].ConfirmationExpires = time.Now().Add(-time.Minute).UnixMilli()
+
+		_, err := ctrl.ConfirmChatIdentity(ctx, session, "alice", enum.ChatProviderSlack,
+			&ConfirmChatIdentityInput{Code: code})
+		requireBadRequest(t, err)
+	})
+
+	t.Run("relinking replaces the code", func(t *testing.T) {
+		ctrl, session, _, messenger := setupChatIdentityTest(t)
+		first := linkChatIdentity(t, ctrl, session, messenger)
+		second := linkChatIdentity(t, ctrl, session, messenger)
+		if first == second {
+			t.Skip("generated the same code twice")
+		}
+
+		_, err := ctrl.ConfirmChatIdentity(ctx, session, "alice", enum.ChatProviderSlack,
+			&ConfirmChatIdentityInput{Code: first})
+		requireBadRequest(t, err)
+	})
+}
+
+func TestLinkChatIdentity_WithoutDirectMessages(t *testing.T) {
+	ctrl, session, identityStore, _ := setupChatIdentityTest(t)
+
+	_, err := ctrl.LinkChatIdentity(context.Background(), session, "alice", enum.ChatProviderTeams,
+		&LinkChatIdentityInput{ExternalID: "alice@example.com"})
+	requireBadRequest(t, err)
+
+	if len(identityStore.identities) != 0 {
+		t.Fatalf("unconfirmable chat identity has been linked")
+	}
+}
+
+func TestLinkChatIdentityAdmin(t *testing.T) {
+	ctrl, session, identityStore, _ := setupChatIdentityTest(t)
+
+	_, err := ctrl.LinkChatIdentityAdmin(context.Background(), session, "alice", enum.ChatProviderTeams,
+		&LinkChatIdentityInput{ExternalID: "alice@example.com"})
+	if err == nil {
+		t.Fatalf("non-admin linked a verified chat identity")
+	}
+
+	admin := &auth.Session{Principal: types.Principal{ID: 2, UID: "admin", Admin: true}}
+	identity, err := ctrl.LinkChatIdentityAdmin(context.Background(), admin, "alice", enum.ChatProviderTeams,
+		&LinkChatIdentityInput{ExternalID: "alice@example.com"})
+	if err != nil {
+		t.Fatalf("failed to link chat identity: %s", err)
+	}
+
+	if !identity.Verified || !identityStore.identities[enum.ChatProviderTeams].Verified {
+		t.Fatalf("chat identity linked by an admin isn't verified")
+	}
+}
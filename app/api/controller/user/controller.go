@@ -18,7 +18,13 @@ import (
 	"context"
 
 	"github.com/harness/gitness/app/auth/authz"
+	"github.com/harness/gitness/app/auth/ldap"
+	"github.com/harness/gitness/app/services/accountmail"
+	"github.com/harness/gitness/app/services/notification"
+	"github.com/harness/gitness/app/services/refcache"
+	systemsvc "github.com/harness/gitness/app/services/system"
 	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/encrypt"
 	"github.com/harness/gitness/store/database/dbtx"
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/check"
@@ -35,9 +41,22 @@ type Controller struct {
 	tokenStore        store.TokenStore
 	membershipStore   store.MembershipStore
 	publicKeyStore    store.PublicKeyStore
+	ldapDirectory     *ldap.Directory
+	ldapIdentityStore store.LDAPIdentityStore
+	totpStore         store.UserTOTPStore
+	encrypter         encrypt.Encrypter
+	systemService     *systemsvc.Service
+	spaceStore        store.SpaceStore
+	repoFinder        refcache.RepoFinder
+	accountMail       *accountmail.Service
+	chatIdentityStore store.UserChatIdentityStore
+	chatMessenger     notification.DirectMessenger
+
+	passwordLoginAllowed bool
 }
 
 func NewController(
+	config *types.Config,
 	tx dbtx.Transactor,
 	principalUIDCheck check.PrincipalUID,
 	authorizer authz.Authorizer,
@@ -45,6 +64,16 @@ func NewController(
 	tokenStore store.TokenStore,
 	membershipStore store.MembershipStore,
 	publicKeyStore store.PublicKeyStore,
+	ldapDirectory *ldap.Directory,
+	ldapIdentityStore store.LDAPIdentityStore,
+	totpStore store.UserTOTPStore,
+	encrypter encrypt.Encrypter,
+	systemService *systemsvc.Service,
+	spaceStore store.SpaceStore,
+	repoFinder refcache.RepoFinder,
+	accountMail *accountmail.Service,
+	chatIdentityStore store.UserChatIdentityStore,
+	chatMessenger notification.DirectMessenger,
 ) *Controller {
 	return &Controller{
 		tx:                tx,
@@ -54,6 +83,19 @@ func NewController(
 		tokenStore:        tokenStore,
 		membershipStore:   membershipStore,
 		publicKeyStore:    publicKeyStore,
+		ldapDirectory:     ldapDirectory,
+		ldapIdentityStore: ldapIdentityStore,
+		totpStore:         totpStore,
+		encrypter:         encrypter,
+		systemService:     systemService,
+		spaceStore:        spaceStore,
+		repoFinder:        repoFinder,
+		accountMail:       accountMail,
+		chatIdentityStore: chatIdentityStore,
+		chatMessenger:     chatMessenger,
+
+		// with single sign-on the sign-in with local passwords can be disabled.
+		passwordLoginAllowed: !config.OIDC.PasswordLoginDisabled,
 	}
 }
 
@@ -60,6 +60,17 @@ func (c *Controller) Create(ctx context.Context, session *auth.Session, in *Crea
  * Note: take admin separately to avoid potential vulnerabilities for user calls.
  */
 func (c *Controller) CreateNoAuth(ctx context.Context, in *CreateInput, admin bool) (*types.User, error) {
+	return c.createNoAuth(ctx, in, admin, true)
+}
+
+// createNoAuth creates a new user without auth checks.
+// Users created by admins, the system or via an identity provider are treated as verified.
+func (c *Controller) createNoAuth(
+	ctx context.Context,
+	in *CreateInput,
+	admin bool,
+	emailVerified bool,
+) (*types.User, error) {
 	if err := c.sanitizeCreateInput(in); err != nil {
 		return nil, fmt.Errorf("invalid input: %w", err)
 	}
@@ -70,14 +81,15 @@ func (c *Controller) CreateNoAuth(ctx context.Context, in *CreateInput, admin bo
 	}
 
 	user := &types.User{
-		UID:         in.UID,
-		DisplayName: in.DisplayName,
-		Email:       in.Email,
-		Password:    string(hash),
-		Salt:        uniuri.NewLen(uniuri.UUIDLen),
-		Created:     time.Now().UnixMilli(),
-		Updated:     time.Now().UnixMilli(),
-		Admin:       admin,
+		UID:           in.UID,
+		DisplayName:   in.DisplayName,
+		Email:         in.Email,
+		Password:      string(hash),
+		Salt:          uniuri.NewLen(uniuri.UUIDLen),
+		Created:       time.Now().UnixMilli(),
+		Updated:       time.Now().UnixMilli(),
+		Admin:         admin,
+		EmailVerified: emailVerified,
 	}
 
 	err = c.principalStore.CreateUser(ctx, user)
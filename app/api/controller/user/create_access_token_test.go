@@ -0,0 +1,231 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/paths"
+	"github.com/harness/gitness/app/services/refcache"
+	appstore "github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/check"
+	"github.com/harness/gitness/types/enum"
+)
+
+var (
+	tokenScopeTestSpaces = []*types.Space{
+		{ID: 1, Identifier: "acme", Path: "acme"},
+		{ID: 2, Identifier: "secret", Path: "secret"},
+	}
+	tokenScopeTestRepos = []*types.Repository{
+		{ID: 10, ParentID: 1, Identifier: "repo", Path: "acme/repo"},
+		{ID: 11, ParentID: 2, Identifier: "repo", Path: "secret/repo"},
+	}
+)
+
+// fakeTokenScopeAuthorizer denies access to everything in the space "secret".
+type fakeTokenScopeAuthorizer struct{}
+
+func (fakeTokenScopeAuthorizer) Check(
+	_ context.Context,
+	_ *auth.Session,
+	scope *types.Scope,
+	resource *types.Resource,
+	_ enum.Permission,
+) (bool, error) {
+	return !paths.IsAncesterOf("secret", paths.Concatenate(scope.SpacePath, resource.Identifier)), nil
+}
+
+func (a fakeTokenScopeAuthorizer) CheckAll(
+	ctx context.Context,
+	session *auth.Session,
+	permissionChecks ...types.PermissionCheck,
+) (bool, error) {
+	for _, p := range permissionChecks {
+		if ok, err := a.Check(ctx, session, &p.Scope, &p.Resource, p.Permission); err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+type fakeTokenScopeSpaceStore struct {
+	appstore.SpaceStore
+}
+
+func (fakeTokenScopeSpaceStore) FindByRef(_ context.Context, spaceRef string) (*types.Space, error) {
+	for _, space := range tokenScopeTestSpaces {
+		if space.Path == spaceRef {
+			return space, nil
+		}
+	}
+	return nil, store.ErrResourceNotFound
+}
+
+type fakeTokenScopeSpaceCache struct{}
+
+func (fakeTokenScopeSpaceCache) Stats() (int64, int64) {
+	return 0, 0
+}
+
+func (fakeTokenScopeSpaceCache) Get(ctx context.Context, spacePath string) (*types.Space, error) {
+	return fakeTokenScopeSpaceStore{}.FindByRef(ctx, spacePath)
+}
+
+type fakeTokenScopeRepoStore struct {
+	appstore.RepoStore
+}
+
+func (fakeTokenScopeRepoStore) FindActiveByUID(
+	_ context.Context,
+	parentID int64,
+	identifier string,
+) (*types.Repository, error) {
+	for _, repo := range tokenScopeTestRepos {
+		if repo.ParentID == parentID && repo.Identifier == identifier {
+			dup := *repo
+			return &dup, nil
+		}
+	}
+	return nil, store.ErrResourceNotFound
+}
+
+type fakeTokenScopeTokenStore struct {
+	appstore.TokenStore
+	created []*types.Token
+}
+
+func (s *fakeTokenScopeTokenStore) Create(_ context.Context, token *types.Token) error {
+	s.created = append(s.created, token)
+	return nil
+}
+
+func TestCreateAccessToken_Scope(t *testing.T) {
+	user := &types.User{ID: 1, UID: "user", Salt: "salt"}
+	lifetime := 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		metadata  auth.Metadata
+		scope     *types.TokenScopeInput
+		wantErr   error
+		wantScope *types.TokenScope
+	}{
+		{
+			name: "unscoped",
+		},
+		{
+			name: "scoped",
+			scope: &types.TokenScopeInput{
+				Permissions: []enum.Permission{enum.PermissionRepoView, enum.PermissionRepoView},
+				Spaces:      []string{"acme", "acme"},
+				Repos:       []string{"acme/repo"},
+			},
+			wantScope: &types.TokenScope{
+				Permissions: []enum.Permission{enum.PermissionRepoView},
+				SpaceIDs:    []int64{1},
+				Spaces:      []string{"acme"},
+				RepoIDs:     []int64{10},
+				Repos:       []string{"acme/repo"},
+			},
+		},
+		{
+			name:    "empty-scope",
+			scope:   &types.TokenScopeInput{},
+			wantErr: check.ErrTokenScopeEmpty,
+		},
+		{
+			name:    "space-not-accessible",
+			scope:   &types.TokenScopeInput{Spaces: []string{"secret"}},
+			wantErr: apiauth.ErrNotAuthorized,
+		},
+		{
+			name:    "repo-not-accessible",
+			scope:   &types.TokenScopeInput{Repos: []string{"secret/repo"}},
+			wantErr: apiauth.ErrNotAuthorized,
+		},
+		{
+			name:    "space-not-found",
+			scope:   &types.TokenScopeInput{Spaces: []string{"missing"}},
+			wantErr: store.ErrResourceNotFound,
+		},
+		{
+			// a scoped token can't create a token, not even a token with a narrower scope.
+			name: "scoped-session",
+			metadata: &auth.TokenMetadata{
+				TokenType: enum.TokenTypePAT,
+				Scope:     &types.TokenScope{Permissions: []enum.Permission{enum.PermissionUserEdit}},
+			},
+			scope:   &types.TokenScopeInput{Spaces: []string{"acme"}},
+			wantErr: apiauth.ErrNotAuthorized,
+		},
+		{
+			name:     "membership-session",
+			metadata: &auth.MembershipMetadata{SpaceID: 1, Role: enum.MembershipRoleSpaceOwner},
+			wantErr:  apiauth.ErrNotAuthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tokenStore := &fakeTokenScopeTokenStore{}
+
+			ctrl := NewController(&types.Config{}, nil, nil, fakeTokenScopeAuthorizer{},
+				&fakeLoginPrincipalStore{users: []*types.User{user}},
+				tokenStore, nil, nil, nil, nil, nil, nil, nil,
+				fakeTokenScopeSpaceStore{},
+				refcache.NewRepoFinder(fakeTokenScopeRepoStore{}, fakeTokenScopeSpaceCache{}),
+				nil, nil, nil)
+
+			session := &auth.Session{Principal: *user.ToPrincipal(), Metadata: test.metadata}
+
+			resp, err := ctrl.CreateAccessToken(context.Background(), session, user.UID, &CreateTokenInput{
+				Identifier: "token",
+				Lifetime:   &lifetime,
+				Scope:      test.scope,
+			})
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("want error %v, got %v", test.wantErr, err)
+				}
+				if len(tokenStore.created) > 0 {
+					t.Errorf("expected no token to be created")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(tokenStore.created) != 1 {
+				t.Fatalf("want 1 token created, got %d", len(tokenStore.created))
+			}
+			if !reflect.DeepEqual(tokenStore.created[0].Scope, test.wantScope) {
+				t.Errorf("want stored scope %+v, got %+v", test.wantScope, tokenStore.created[0].Scope)
+			}
+			if !reflect.DeepEqual(resp.Token.Scope, test.wantScope) {
+				t.Errorf("want returned scope %+v, got %+v", test.wantScope, resp.Token.Scope)
+			}
+		})
+	}
+}
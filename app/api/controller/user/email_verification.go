@@ -0,0 +1,123 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/services/accountmail"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+type RequestEmailVerificationInput struct {
+	Email string `json:"email"`
+}
+
+type VerifyEmailInput struct {
+	Token string `json:"token"`
+}
+
+// emailVerificationCheck returns an error if the user has to verify the email address before signing in.
+func (c *Controller) emailVerificationCheck(user *types.User) error {
+	if user.EmailVerified || !c.accountMail.EmailVerificationRequired() {
+		return nil
+	}
+
+	return usererror.NewWithPayload(http.StatusForbidden,
+		"The email address has to be verified before signing in, please check your inbox",
+		map[string]any{
+			"email_verification_required": true,
+		})
+}
+
+// RequestEmailVerification sends a new verification link to the email address of an unverified user.
+// No auth is required. To not disclose which email addresses are registered, it never fails for unknown users.
+func (c *Controller) RequestEmailVerification(ctx context.Context, in *RequestEmailVerificationInput) error {
+	if !c.accountMail.EmailVerificationRequired() {
+		return usererror.Forbidden("Email verification is disabled")
+	}
+
+	user, err := findUserFromEmail(ctx, c.principalStore, strings.TrimSpace(in.Email))
+	if errors.Is(err, store.ErrResourceNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find user by email: %w", err)
+	}
+
+	if user.EmailVerified || user.Blocked {
+		return nil
+	}
+
+	c.sendAccountMail(ctx, user, enum.UserMailTypeEmailVerification)
+
+	return nil
+}
+
+// VerifyEmail marks the email address of the user the verification link was sent to as verified.
+func (c *Controller) VerifyEmail(ctx context.Context, in *VerifyEmailInput) error {
+	user, err := c.accountMail.ParseLink(ctx, in.Token, enum.UserMailTypeEmailVerification)
+	if errors.Is(err, accountmail.ErrInvalidLink) {
+		return usererror.BadRequest("The verification link is invalid or expired")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse verification link: %w", err)
+	}
+
+	if user.EmailVerified {
+		return nil
+	}
+
+	user.EmailVerified = true
+	user.Updated = time.Now().UnixMilli()
+
+	if err = c.principalStore.UpdateUser(ctx, user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return nil
+}
+
+// sendAccountMail sends the account mail of the type to the user.
+// Errors are only logged, so the response doesn't disclose whether a mail was sent.
+func (c *Controller) sendAccountMail(ctx context.Context, user *types.User, mailType enum.UserMailType) {
+	var err error
+
+	//nolint:exhaustive
+	switch mailType {
+	case enum.UserMailTypePasswordReset:
+		err = c.accountMail.SendPasswordReset(ctx, user)
+	case enum.UserMailTypeEmailVerification:
+		err = c.accountMail.SendEmailVerification(ctx, user)
+	}
+
+	if errors.Is(err, accountmail.ErrRateLimited) {
+		log.Ctx(ctx).Debug().Str("user_uid", user.UID).Msgf("rate limit of %s mails exceeded", mailType)
+		return
+	}
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("user_uid", user.UID).Msgf("failed to send %s mail", mailType)
+	}
+}
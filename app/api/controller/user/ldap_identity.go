@@ -0,0 +1,96 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/auth/ldap"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+type LinkLDAPIdentityInput struct {
+	DN string `json:"dn"`
+}
+
+// LinkLDAPIdentity links the user to a directory user, after that the user signs in with the directory password.
+// Existing users are never linked implicitly on sign-in, so this is the only way to move them to the directory.
+func (c *Controller) LinkLDAPIdentity(
+	ctx context.Context,
+	session *auth.Session,
+	userUID string,
+	in *LinkLDAPIdentityInput,
+) (*types.LDAPIdentity, error) {
+	user, err := findUserFromUID(ctx, c.principalStore, userUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = apiauth.CheckUser(ctx, c.authorizer, session, user, enum.PermissionUserEditAdmin); err != nil {
+		return nil, err
+	}
+
+	in.DN = strings.TrimSpace(in.DN)
+	if !strings.Contains(in.DN, "=") {
+		return nil, usererror.BadRequest("The DN of the directory user is invalid")
+	}
+
+	identity := &types.LDAPIdentity{
+		DN:          ldap.NormalizeDN(in.DN),
+		PrincipalID: user.ID,
+		Created:     time.Now().UnixMilli(),
+	}
+
+	err = c.ldapIdentityStore.Create(ctx, identity)
+	if errors.Is(err, store.ErrDuplicate) {
+		return nil, usererror.Conflict("The user or the directory user is already linked")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to link directory user: %w", err)
+	}
+
+	return identity, nil
+}
+
+// UnlinkLDAPIdentity removes the link of the user to the directory user.
+func (c *Controller) UnlinkLDAPIdentity(
+	ctx context.Context,
+	session *auth.Session,
+	userUID string,
+) error {
+	user, err := findUserFromUID(ctx, c.principalStore, userUID)
+	if err != nil {
+		return err
+	}
+
+	if err = apiauth.CheckUser(ctx, c.authorizer, session, user, enum.PermissionUserEditAdmin); err != nil {
+		return err
+	}
+
+	if err = c.ldapIdentityStore.DeleteByPrincipalID(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to unlink directory user: %w", err)
+	}
+
+	return nil
+}
@@ -16,10 +16,12 @@ package user
 
 import (
 	"context"
+	"fmt"
 
 	apiauth "github.com/harness/gitness/app/api/auth"
 	"github.com/harness/gitness/app/api/usererror"
 	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/token"
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
 )
@@ -43,5 +45,14 @@ func (c *Controller) ListTokens(ctx context.Context, session *auth.Session,
 		return nil, usererror.ErrBadRequest
 	}
 
-	return c.tokenStore.List(ctx, user.ID, tokenType)
+	tokens, err := c.tokenStore.List(ctx, user.ID, tokenType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tokens: %w", err)
+	}
+
+	if err = token.PopulateScopePaths(ctx, c.spaceStore, c.repoFinder, tokens); err != nil {
+		return nil, fmt.Errorf("failed to populate token scopes: %w", err)
+	}
+
+	return tokens, nil
 }
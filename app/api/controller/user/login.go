@@ -43,18 +43,80 @@ func (c *Controller) Login(
 	ctx context.Context,
 	in *LoginInput,
 ) (*types.TokenResponse, error) {
+	if !c.passwordLoginAllowed && !c.ldapDirectory.Enabled() {
+		return nil, usererror.Forbidden("Login with password is disabled, use single sign-on instead")
+	}
+
 	// no auth check required, password is used for it.
 
+	user, err := c.authenticate(ctx, in)
+
+	// always return not found for security reasons.
+	if user == nil || err != nil {
+		return nil, usererror.ErrNotFound
+	}
+
+	if err = c.emailVerificationCheck(user); err != nil {
+		return nil, err
+	}
+
+	// the session is created only after the second factor is verified.
+	if err = c.TwoFactorChallenge(ctx, user); err != nil {
+		return nil, err
+	}
+
+	tokenIdentifier, err := GenerateSessionTokenIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	token, jwtToken, err := token.CreateUserSession(ctx, c.tokenStore, user, tokenIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.TokenResponse{Token: *token, AccessToken: jwtToken}, nil
+}
+
+// authenticate verifies the credentials with a single method. Users linked to a directory user
+// are verified against the directory and the other users with their local password.
+// The directory is asked only for the users linked to it and for unknown login identifiers.
+func (c *Controller) authenticate(ctx context.Context, in *LoginInput) (*types.User, error) {
 	user, err := findUserFromUID(ctx, c.principalStore, in.LoginIdentifier)
 	if errors.Is(err, store.ErrResourceNotFound) {
 		user, err = findUserFromEmail(ctx, c.principalStore, in.LoginIdentifier)
 	}
-
-	// always return not found for security reasons.
+	if errors.Is(err, store.ErrResourceNotFound) && c.ldapDirectory.Enabled() {
+		return c.loginLDAP(ctx, in)
+	}
 	if err != nil {
 		log.Ctx(ctx).Debug().Err(err).
 			Msgf("failed to retrieve user %q during login (returning ErrNotFound).", in.LoginIdentifier)
-		return nil, usererror.ErrNotFound
+		return nil, err
+	}
+
+	_, err = c.ldapIdentityStore.FindByPrincipalID(ctx, user.ID)
+	if err != nil && !errors.Is(err, store.ErrResourceNotFound) {
+		return nil, fmt.Errorf("failed to find directory identity of user: %w", err)
+	}
+
+	if err == nil {
+		if !c.ldapDirectory.Enabled() {
+			return nil, errors.New("user is linked to a directory user, but LDAP is disabled")
+		}
+
+		dirLinkedUser, err := c.loginLDAP(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		if dirLinkedUser.ID != user.ID {
+			return nil, fmt.Errorf("directory user of %q is linked to another user", in.LoginIdentifier)
+		}
+
+		return dirLinkedUser, nil
+	}
+
+	if !c.passwordLoginAllowed {
+		return nil, errors.New("login with local password is disabled")
 	}
 
 	err = bcrypt.CompareHashAndPassword(
@@ -66,19 +128,10 @@ func (c *Controller) Login(
 			Str("user_uid", user.UID).
 			Msg("invalid password")
 
-		return nil, usererror.ErrNotFound
-	}
-
-	tokenIdentifier, err := GenerateSessionTokenIdentifier()
-	if err != nil {
-		return nil, err
-	}
-	token, jwtToken, err := token.CreateUserSession(ctx, c.tokenStore, user, tokenIdentifier)
-	if err != nil {
 		return nil, err
 	}
 
-	return &types.TokenResponse{Token: *token, AccessToken: jwtToken}, nil
+	return user, nil
 }
 
 func GenerateSessionTokenIdentifier() (string, error) {
@@ -0,0 +1,106 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/app/auth/ldap"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+
+	"github.com/dchest/uniuri"
+	"github.com/rs/zerolog/log"
+)
+
+// ldapProvisionedPasswordLength is the length of the random local password of users provisioned from the directory.
+// Nobody knows the password, so such users can sign in only with their directory password.
+const ldapProvisionedPasswordLength = 64
+
+// loginLDAP verifies the credentials against the directory and returns the user linked to the directory user.
+// If the directory user isn't linked yet, a new user is provisioned and linked to it. Existing local users
+// are never linked implicitly, a directory user with the UID or the email of a local user can't sign in.
+func (c *Controller) loginLDAP(ctx context.Context, in *LoginInput) (*types.User, error) {
+	dirUser, err := c.ldapDirectory.Authenticate(ctx, in.LoginIdentifier, in.Password)
+	if errors.Is(err, ldap.ErrInvalidCredentials) {
+		log.Ctx(ctx).Debug().Msgf("invalid directory credentials of %q", in.LoginIdentifier)
+		return nil, err
+	}
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to authenticate %q against the directory", in.LoginIdentifier)
+		return nil, err
+	}
+
+	dn := ldap.NormalizeDN(dirUser.DN)
+	now := time.Now().UnixMilli()
+
+	identity, err := c.ldapIdentityStore.Find(ctx, dn)
+	if err == nil {
+		if err = c.ldapIdentityStore.UpdateLastLogin(ctx, dn, now); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("failed to update last login of the directory identity")
+		}
+
+		return c.principalStore.FindUser(ctx, identity.PrincipalID)
+	}
+	if !errors.Is(err, store.ErrResourceNotFound) {
+		return nil, fmt.Errorf("failed to find directory identity: %w", err)
+	}
+
+	if !c.ldapDirectory.AutoProvision() {
+		log.Ctx(ctx).Info().Msgf("directory user %q isn't registered and auto provisioning is disabled", dirUser.DN)
+		return nil, err
+	}
+
+	displayName := dirUser.DisplayName
+	if displayName == "" {
+		displayName = dirUser.UID
+	}
+
+	var user *types.User
+	err = c.tx.WithTx(ctx, func(ctx context.Context) error {
+		user, err = c.CreateNoAuth(ctx, &CreateInput{
+			UID:         dirUser.UID,
+			Email:       dirUser.Email,
+			DisplayName: displayName,
+			Password:    uniuri.NewLen(ldapProvisionedPasswordLength),
+		}, false)
+		if err != nil {
+			return fmt.Errorf("failed to provision directory user: %w", err)
+		}
+
+		return c.ldapIdentityStore.Create(ctx, &types.LDAPIdentity{
+			DN:          dn,
+			PrincipalID: user.ID,
+			Created:     now,
+			LastLogin:   now,
+		})
+	})
+	if errors.Is(err, store.ErrDuplicate) {
+		log.Ctx(ctx).Warn().Msgf("directory user %q conflicts with an existing user, an admin has to link it explicitly",
+			dirUser.DN)
+		return nil, err
+	}
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to provision directory user %q", dirUser.DN)
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Msgf("provisioned user %q for directory user %q", user.UID, dirUser.DN)
+
+	return user, nil
+}
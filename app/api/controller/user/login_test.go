@@ -0,0 +1,243 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth/ldap"
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/services/settings"
+	systemsvc "github.com/harness/gitness/app/services/system"
+	appstore "github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type fakeLoginPrincipalStore struct {
+	appstore.PrincipalStore
+	users []*types.User
+}
+
+func (s *fakeLoginPrincipalStore) FindUserByUID(_ context.Context, uid string) (*types.User, error) {
+	for _, u := range s.users {
+		if u.UID == uid {
+			return u, nil
+		}
+	}
+	return nil, store.ErrResourceNotFound
+}
+
+func (s *fakeLoginPrincipalStore) FindUserByEmail(_ context.Context, email string) (*types.User, error) {
+	for _, u := range s.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, store.ErrResourceNotFound
+}
+
+type fakeLDAPIdentityStore struct {
+	appstore.LDAPIdentityStore
+	identities []*types.LDAPIdentity
+}
+
+func (s *fakeLDAPIdentityStore) FindByPrincipalID(_ context.Context, principalID int64) (*types.LDAPIdentity, error) {
+	for _, identity := range s.identities {
+		if identity.PrincipalID == principalID {
+			return identity, nil
+		}
+	}
+	return nil, store.ErrResourceNotFound
+}
+
+type fakeLoginTokenStore struct {
+	appstore.TokenStore
+}
+
+func (s *fakeLoginTokenStore) Create(context.Context, *types.Token) error {
+	return nil
+}
+
+type fakeLoginTOTPStore struct {
+	appstore.UserTOTPStore
+}
+
+func (s *fakeLoginTOTPStore) Find(context.Context, int64) (*types.UserTOTP, error) {
+	return nil, store.ErrResourceNotFound
+}
+
+type fakeLoginSettingsStore struct {
+	appstore.SettingsStore
+}
+
+func (s *fakeLoginSettingsStore) FindMany(
+	context.Context,
+	enum.SettingsScope,
+	int64,
+	...string,
+) (map[string]json.RawMessage, error) {
+	return map[string]json.RawMessage{}, nil
+}
+
+// newCountingDirectoryURL returns the URL of a server that counts and drops all connections,
+// so the tests can tell whether the directory was asked without running a directory server.
+func newCountingDirectoryURL(t *testing.T) (string, *atomic.Int32) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	count := &atomic.Int32{}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			count.Add(1)
+			_ = conn.Close()
+		}
+	}()
+
+	return "ldap://" + l.Addr().String(), count
+}
+
+func TestLogin(t *testing.T) {
+	const password = "password"
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash password: %s", err)
+	}
+
+	localUser := &types.User{ID: 1, UID: "local", Email: "local@example.org", Password: string(hash),
+		Salt: "salt", EmailVerified: true}
+	linkedUser := &types.User{ID: 2, UID: "linked", Email: "linked@example.org", Password: string(hash),
+		Salt: "salt", EmailVerified: true}
+
+	tests := []struct {
+		name                  string
+		login                 string
+		password              string
+		ldapDisabled          bool
+		passwordLoginDisabled bool
+		wantSuccess           bool
+		wantDirectoryAsked    bool
+	}{
+		{
+			name:        "local-user",
+			login:       localUser.UID,
+			password:    password,
+			wantSuccess: true,
+		},
+		{
+			name:        "local-user-by-email",
+			login:       localUser.Email,
+			password:    password,
+			wantSuccess: true,
+		},
+		{
+			// a wrong local password must not fall back to the directory.
+			name:     "local-user-wrong-password",
+			login:    localUser.UID,
+			password: "wrong",
+		},
+		{
+			name:                  "local-user-password-login-disabled",
+			login:                 localUser.UID,
+			password:              password,
+			passwordLoginDisabled: true,
+		},
+		{
+			// the local password of a linked user is never accepted.
+			name:               "linked-user",
+			login:              linkedUser.UID,
+			password:           password,
+			wantDirectoryAsked: true,
+		},
+		{
+			name:         "linked-user-ldap-disabled",
+			login:        linkedUser.UID,
+			password:     password,
+			ldapDisabled: true,
+		},
+		{
+			name:               "unknown-user",
+			login:              "unknown",
+			password:           password,
+			wantDirectoryAsked: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			url, connections := newCountingDirectoryURL(t)
+
+			config := &types.Config{}
+			config.OIDC.PasswordLoginDisabled = test.passwordLoginDisabled
+			config.LDAP.Enabled = !test.ldapDisabled
+			config.LDAP.URL = url
+			config.LDAP.Timeout = time.Second
+
+			ctrl := NewController(config, nil, nil, nil,
+				&fakeLoginPrincipalStore{users: []*types.User{localUser, linkedUser}},
+				&fakeLoginTokenStore{}, nil, nil,
+				ldap.NewDirectory(config),
+				&fakeLDAPIdentityStore{identities: []*types.LDAPIdentity{
+					{DN: "uid=linked,ou=users,dc=example,dc=org", PrincipalID: linkedUser.ID},
+				}},
+				&fakeLoginTOTPStore{}, nil,
+				systemsvc.ProvideService(settings.NewService(&fakeLoginSettingsStore{})),
+				nil, refcache.RepoFinder{}, nil, nil, nil)
+
+			resp, err := ctrl.Login(ctx, &LoginInput{LoginIdentifier: test.login, Password: test.password})
+			if test.wantSuccess {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if resp.AccessToken == "" {
+					t.Errorf("expected an access token")
+				}
+			} else if !errors.Is(err, usererror.ErrNotFound) {
+				t.Fatalf("want error %v, got %v", usererror.ErrNotFound, err)
+			}
+
+			// the connections are accepted asynchronously.
+			deadline := time.Now().Add(200 * time.Millisecond)
+			for connections.Load() == 0 && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			if asked := connections.Load() > 0; asked != test.wantDirectoryAsked {
+				t.Errorf("want directory asked=%t, got %t", test.wantDirectoryAsked, asked)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/token"
+	"github.com/harness/gitness/types"
+)
+
+type LoginTwoFactorInput struct {
+	TwoFactorChallenge string `json:"two_factor_challenge"`
+	Code               string `json:"code"`
+}
+
+type LoginTwoFactorEnrollInput struct {
+	TwoFactorChallenge string `json:"two_factor_challenge"`
+}
+
+// LoginTwoFactor completes the login with the two-factor challenge returned by Login.
+// The code is either a code of the authenticator app or a recovery code. If the challenge
+// required the enrollment, the code confirms it and the new recovery codes are returned.
+// A challenge can be used only once.
+func (c *Controller) LoginTwoFactor(
+	ctx context.Context,
+	in *LoginTwoFactorInput,
+) (*types.TwoFactorLoginResponse, error) {
+	user, challenge, err := c.parseTwoFactorChallenge(ctx, in.TwoFactorChallenge)
+	if err != nil {
+		return nil, err
+	}
+
+	otp, err := c.findTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if otp == nil {
+		return nil, usererror.BadRequest("Two-factor authentication is not set up")
+	}
+	if err = checkTwoFactorChallengeRevoked(otp, challenge); err != nil {
+		return nil, err
+	}
+
+	var recoveryCodes []string
+
+	switch {
+	case otp.Enabled:
+		if err = c.verifyTOTPCode(ctx, otp, in.Code, true); err != nil {
+			return nil, err
+		}
+	case challenge.Enrollment:
+		recoveryCodes, err = c.enableTOTP(ctx, otp, in.Code)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, usererror.BadRequest("Two-factor authentication is not set up")
+	}
+
+	if err = c.revokeTwoFactorChallenges(ctx, otp); err != nil {
+		return nil, err
+	}
+
+	tokenIdentifier, err := GenerateSessionTokenIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	tkn, jwtToken, err := token.CreateUserSession(ctx, c.tokenStore, user, tokenIdentifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return &types.TwoFactorLoginResponse{
+		TokenResponse: types.TokenResponse{Token: *tkn, AccessToken: jwtToken},
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// LoginTwoFactorEnroll starts the enrollment required by the two-factor challenge.
+func (c *Controller) LoginTwoFactorEnroll(
+	ctx context.Context,
+	in *LoginTwoFactorEnrollInput,
+) (*types.TOTPEnrollment, error) {
+	user, challenge, err := c.parseTwoFactorChallenge(ctx, in.TwoFactorChallenge)
+	if err != nil {
+		return nil, err
+	}
+
+	if !challenge.Enrollment {
+		return nil, usererror.BadRequest("Two-factor authentication is already set up")
+	}
+
+	otp, err := c.findTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkTwoFactorChallengeRevoked(otp, challenge); err != nil {
+		return nil, err
+	}
+
+	return c.enrollTOTP(ctx, user)
+}
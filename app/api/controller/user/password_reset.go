@@ -0,0 +1,128 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/services/accountmail"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types/check"
+	"github.com/harness/gitness/types/enum"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type RequestPasswordResetInput struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// RequestPasswordReset sends a password reset link to the email address of the user.
+// No auth is required. To not disclose which email addresses are registered, it never fails for unknown users.
+func (c *Controller) RequestPasswordReset(
+	ctx context.Context,
+	in *RequestPasswordResetInput,
+) error {
+	if err := c.checkPasswordResetAllowed(); err != nil {
+		return err
+	}
+
+	user, err := findUserFromEmail(ctx, c.principalStore, strings.TrimSpace(in.Email))
+	if errors.Is(err, store.ErrResourceNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find user by email: %w", err)
+	}
+
+	if user.Blocked {
+		return nil
+	}
+
+	c.sendAccountMail(ctx, user, enum.UserMailTypePasswordReset)
+
+	return nil
+}
+
+// ResetPassword sets the new password of the user the password reset link was sent to.
+// All session tokens of the user are deleted, the access tokens stay valid.
+func (c *Controller) ResetPassword(ctx context.Context, in *ResetPasswordInput) error {
+	if err := c.checkPasswordResetAllowed(); err != nil {
+		return err
+	}
+
+	if err := check.Password(in.Password); err != nil {
+		return err
+	}
+
+	user, err := c.accountMail.ParseLink(ctx, in.Token, enum.UserMailTypePasswordReset)
+	if errors.Is(err, accountmail.ErrInvalidLink) {
+		return usererror.BadRequest("The password reset link is invalid, expired or was already used")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse password reset link: %w", err)
+	}
+
+	if user.Blocked {
+		return usererror.Forbidden("The user is blocked")
+	}
+
+	hash, err := hashPassword([]byte(in.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user.Password = string(hash)
+	// the link was received via email, which proves ownership of the email address.
+	user.EmailVerified = true
+	user.Updated = time.Now().UnixMilli()
+
+	return c.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := c.principalStore.UpdateUser(ctx, user); err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+
+		sessions, err := c.tokenStore.List(ctx, user.ID, enum.TokenTypeSession)
+		if err != nil {
+			return fmt.Errorf("failed to list session tokens: %w", err)
+		}
+
+		for _, session := range sessions {
+			if err = c.tokenStore.Delete(ctx, session.ID); err != nil {
+				return fmt.Errorf("failed to delete session token: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (c *Controller) checkPasswordResetAllowed() error {
+	if !c.accountMail.PasswordResetEnabled() || !c.passwordLoginAllowed {
+		return usererror.Forbidden("Password reset is disabled")
+	}
+
+	return nil
+}
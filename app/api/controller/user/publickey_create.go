@@ -54,11 +54,6 @@ func (c *Controller) CreatePublicKey(
 		return nil, err
 	}
 
-	key, comment, err := publickey.ParseString(in.Content)
-	if err != nil {
-		return nil, errors.InvalidArgument("could not parse public key")
-	}
-
 	now := time.Now().UnixMilli()
 
 	k := &types.PublicKey{
@@ -67,10 +62,36 @@ func (c *Controller) CreatePublicKey(
 		Verified:    nil, // the key is created as unverified
 		Identifier:  in.Identifier,
 		Usage:       in.Usage,
-		Fingerprint: key.Fingerprint(),
 		Content:     in.Content,
-		Comment:     comment,
-		Type:        key.Type(),
+	}
+
+	// matches reports whether the content of an existing key is the same key as the new one.
+	var matches func(content string) bool
+
+	if publickey.IsPGP(in.Content) {
+		if in.Usage != enum.PublicKeyUsageSign {
+			return nil, errors.InvalidArgument("PGP keys can only be used for signing")
+		}
+
+		key, err := publickey.ParsePGP(in.Content)
+		if err != nil {
+			return nil, errors.InvalidArgument("could not parse PGP public key")
+		}
+
+		k.Fingerprint = key.Fingerprint()
+		k.Comment = key.Comment()
+		k.Type = publickey.KeyTypePGP
+		matches = func(string) bool { return true } // fingerprints of PGP keys are unique
+	} else {
+		key, comment, err := publickey.ParseString(in.Content)
+		if err != nil {
+			return nil, errors.InvalidArgument("could not parse public key")
+		}
+
+		k.Fingerprint = key.Fingerprint()
+		k.Comment = comment
+		k.Type = key.Type()
+		matches = key.Matches
 	}
 
 	err = c.tx.WithTx(ctx, func(ctx context.Context) error {
@@ -79,8 +100,12 @@ func (c *Controller) CreatePublicKey(
 			return fmt.Errorf("failed to read keys by fingerprint: %w", err)
 		}
 
+		// the same user can register a key once for authentication and once for signing.
 		for _, existingKey := range existingKeys {
-			if key.Matches(existingKey.Content) {
+			if !matches(existingKey.Content) {
+				continue
+			}
+			if existingKey.Usage == k.Usage || existingKey.PrincipalID != k.PrincipalID {
 				return errors.InvalidArgument("Key is already in use")
 			}
 		}
@@ -22,6 +22,7 @@ import (
 	"github.com/harness/gitness/app/api/usererror"
 	"github.com/harness/gitness/app/token"
 	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
 )
 
 type RegisterInput struct {
@@ -44,16 +45,26 @@ func (c *Controller) Register(ctx context.Context, sysCtrl *system.Controller,
 		return nil, usererror.Forbidden("User sign-up is disabled")
 	}
 
-	user, err := c.CreateNoAuth(ctx, &CreateInput{
+	user, err := c.createNoAuth(ctx, &CreateInput{
 		UID:         in.UID,
 		Email:       in.Email,
 		DisplayName: in.DisplayName,
 		Password:    in.Password,
-	}, false)
+	}, false, !c.accountMail.EmailVerificationRequired())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
+	// the session is created only after the email address is verified.
+	if !user.EmailVerified {
+		c.sendAccountMail(ctx, user, enum.UserMailTypeEmailVerification)
+		return nil, c.emailVerificationCheck(user)
+	}
+
+	if err = c.TwoFactorChallenge(ctx, user); err != nil {
+		return nil, err
+	}
+
 	// TODO: how should we name session tokens?
 	token, jwtToken, err := token.CreateUserSession(ctx, c.tokenStore, user, "register")
 	if err != nil {
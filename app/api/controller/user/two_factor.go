@@ -0,0 +1,342 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth/totp"
+	"github.com/harness/gitness/app/jwt"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/dchest/uniuri"
+	gojwt "github.com/golang-jwt/jwt"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// totpIssuer is the issuer shown in the authenticator apps.
+	totpIssuer = "Gitness"
+
+	twoFactorChallengeLifetime = 5 * time.Minute
+
+	// twoFactorMaxFailedAttempts is the number of invalid codes after which the second factor is locked
+	// and all outstanding two-factor challenges of the user are revoked.
+	twoFactorMaxFailedAttempts = 5
+	twoFactorLockoutDuration   = 15 * time.Minute
+
+	// twoFactorChallengeSecretSuffix is appended to the principal's salt to sign two-factor challenges,
+	// so a challenge can't be used as a session token.
+	twoFactorChallengeSecretSuffix = ":two-factor-challenge"
+
+	recoveryCodeCount     = 10
+	recoveryCodeLength    = 10
+	recoveryCodeSeparator = "-"
+)
+
+var recoveryCodeChars = []byte("abcdefghijkmnpqrstuvwxyz23456789")
+
+// isTwoFactorRequired returns true if the user is required to use two-factor authentication.
+func (c *Controller) isTwoFactorRequired(ctx context.Context, user *types.User) (bool, error) {
+	settings, err := c.systemService.Find(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to find system settings: %w", err)
+	}
+
+	switch *settings.TwoFactorRequirement {
+	case enum.TwoFactorRequirementAll:
+		return true, nil
+	case enum.TwoFactorRequirementSpaceOwners:
+		if user.Admin {
+			return true, nil
+		}
+
+		count, err := c.membershipStore.CountSpaces(ctx, user.ID, types.MembershipSpaceFilter{
+			Role: enum.MembershipRoleSpaceOwner,
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to count owned spaces: %w", err)
+		}
+
+		return count > 0, nil
+	case enum.TwoFactorRequirementNone:
+		return false, nil
+	default:
+		return false, nil
+	}
+}
+
+// findTOTP returns the TOTP second factor of the user or nil if the user doesn't have one.
+func (c *Controller) findTOTP(ctx context.Context, principalID int64) (*types.UserTOTP, error) {
+	otp, err := c.totpStore.Find(ctx, principalID)
+	if errors.Is(err, store.ErrResourceNotFound) {
+		return nil, nil //nolint:nilnil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find totp of user: %w", err)
+	}
+
+	return otp, nil
+}
+
+// TwoFactorChallenge returns an error with a two-factor challenge if the user has to pass
+// the second factor before the session is created, otherwise it returns nil.
+// Every sign-in method must call it before it creates the session of the user.
+func (c *Controller) TwoFactorChallenge(ctx context.Context, user *types.User) error {
+	otp, err := c.findTOTP(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	enrollment := false
+	if otp == nil || !otp.Enabled {
+		required, err := c.isTwoFactorRequired(ctx, user)
+		if err != nil {
+			return err
+		}
+		if !required {
+			return nil
+		}
+
+		enrollment = true
+	}
+
+	challenge, err := jwt.GenerateForTwoFactorChallenge(user.ID, enrollment, twoFactorChallengeLifetime,
+		user.Salt+twoFactorChallengeSecretSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to generate two-factor challenge: %w", err)
+	}
+
+	message := "Two-factor authentication code is required"
+	if enrollment {
+		message = "Two-factor authentication has to be set up"
+	}
+
+	return usererror.NewWithPayload(http.StatusUnauthorized, message, map[string]any{
+		"two_factor_challenge": challenge,
+		"enrollment_required":  enrollment,
+		"expires_at":           time.Now().Add(twoFactorChallengeLifetime).UnixMilli(),
+	})
+}
+
+// parseTwoFactorChallenge verifies the two-factor challenge and returns the user it was issued for.
+func (c *Controller) parseTwoFactorChallenge(
+	ctx context.Context,
+	challenge string,
+) (*types.User, *jwt.SubClaimsTwoFactor, error) {
+	var user *types.User
+	claims := &jwt.Claims{}
+
+	parsed, err := gojwt.ParseWithClaims(challenge, claims, func(token *gojwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*gojwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("invalid signing method of two-factor challenge")
+		}
+
+		var err error
+		user, err = c.principalStore.FindUser(ctx, claims.PrincipalID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find user of two-factor challenge: %w", err)
+		}
+
+		return []byte(user.Salt + twoFactorChallengeSecretSuffix), nil
+	})
+	if err != nil || !parsed.Valid || claims.TwoFactor == nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("invalid two-factor challenge")
+		return nil, nil, usererror.New(http.StatusUnauthorized,
+			"Invalid or expired two-factor challenge, please sign in again")
+	}
+
+	if user.Blocked {
+		return nil, nil, usererror.Forbidden("The user is blocked")
+	}
+
+	return user, claims.TwoFactor, nil
+}
+
+// checkTwoFactorChallengeRevoked returns an error if the challenge was issued before the challenges
+// of the user were revoked, either because a challenge was used or because of too many failed attempts.
+func checkTwoFactorChallengeRevoked(otp *types.UserTOTP, challenge *jwt.SubClaimsTwoFactor) error {
+	if otp == nil || otp.ChallengesRevoked == 0 || challenge.IssuedAt > otp.ChallengesRevoked {
+		return nil
+	}
+
+	return usererror.New(http.StatusUnauthorized, "Invalid or expired two-factor challenge, please sign in again")
+}
+
+// revokeTwoFactorChallenges revokes all two-factor challenges of the user issued so far,
+// which makes the challenge used to sign in single-use.
+func (c *Controller) revokeTwoFactorChallenges(ctx context.Context, otp *types.UserTOTP) error {
+	otp.ChallengesRevoked = time.Now().UnixMilli()
+
+	err := c.totpStore.Update(ctx, otp)
+	if errors.Is(err, store.ErrVersionConflict) {
+		// the challenge was used concurrently.
+		return usererror.New(http.StatusUnauthorized, "Invalid or expired two-factor challenge, please sign in again")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke two-factor challenges: %w", err)
+	}
+
+	return nil
+}
+
+// verifyTOTPCode verifies the code of the authenticator app and, if allowed, the recovery codes.
+// A used code is consumed, so it can't be used again. After too many invalid codes
+// no codes are accepted for a while.
+func (c *Controller) verifyTOTPCode(
+	ctx context.Context,
+	otp *types.UserTOTP,
+	code string,
+	allowRecoveryCode bool,
+) error {
+	now := time.Now()
+	if otp.LockedUntil > now.UnixMilli() {
+		return errTwoFactorLocked
+	}
+
+	code = strings.TrimSpace(code)
+
+	secret, err := c.encrypter.Decrypt(otp.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt totp secret: %w", err)
+	}
+
+	if step, ok := totp.Validate(secret, strings.ReplaceAll(code, " ", ""), now, otp.LastUsedStep); ok {
+		otp.LastUsedStep = step
+		otp.FailedAttempts = 0
+		return c.updateTOTP(ctx, otp)
+	}
+
+	if !allowRecoveryCode {
+		return c.recordFailedTOTPAttempt(ctx, otp, usererror.BadRequest("Invalid two-factor authentication code"))
+	}
+
+	idx := findRecoveryCode(otp.RecoveryCodes, code)
+	if idx < 0 {
+		return c.recordFailedTOTPAttempt(ctx, otp,
+			usererror.BadRequest("Invalid two-factor authentication or recovery code"))
+	}
+
+	otp.RecoveryCodes = append(otp.RecoveryCodes[:idx:idx], otp.RecoveryCodes[idx+1:]...)
+	otp.FailedAttempts = 0
+	if err = c.updateTOTP(ctx, otp); err != nil {
+		return err
+	}
+
+	log.Ctx(ctx).Info().Msgf("user %d used a recovery code, %d remaining", otp.PrincipalID, len(otp.RecoveryCodes))
+
+	return nil
+}
+
+var errTwoFactorLocked = usererror.New(http.StatusTooManyRequests,
+	"Too many invalid two-factor authentication codes, please try again later")
+
+// recordFailedTOTPAttempt counts the invalid code and returns errInvalid, or, if there were too many
+// invalid codes, locks the second factor, revokes all two-factor challenges of the user and returns errTwoFactorLocked.
+func (c *Controller) recordFailedTOTPAttempt(ctx context.Context, otp *types.UserTOTP, errInvalid error) error {
+	now := time.Now()
+	locked := false
+
+	_, err := c.totpStore.UpdateOptLock(ctx, otp, func(otp *types.UserTOTP) error {
+		if otp.LockedUntil > now.UnixMilli() {
+			// locked concurrently.
+			return errTwoFactorLocked
+		}
+
+		otp.FailedAttempts++
+		locked = otp.FailedAttempts >= twoFactorMaxFailedAttempts
+		if locked {
+			otp.FailedAttempts = 0
+			otp.LockedUntil = now.Add(twoFactorLockoutDuration).UnixMilli()
+			otp.ChallengesRevoked = now.UnixMilli()
+		}
+
+		return nil
+	})
+	if errors.Is(err, errTwoFactorLocked) {
+		return errTwoFactorLocked
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record failed two-factor authentication attempt: %w", err)
+	}
+
+	if locked {
+		log.Ctx(ctx).Warn().Msgf("two-factor authentication of user %d locked after %d invalid codes",
+			otp.PrincipalID, twoFactorMaxFailedAttempts)
+		return errTwoFactorLocked
+	}
+
+	return errInvalid
+}
+
+func (c *Controller) updateTOTP(ctx context.Context, otp *types.UserTOTP) error {
+	err := c.totpStore.Update(ctx, otp)
+	if errors.Is(err, store.ErrVersionConflict) {
+		// the same code was used concurrently.
+		return usererror.BadRequest("Invalid two-factor authentication code")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update totp of user: %w", err)
+	}
+
+	return nil
+}
+
+// generateRecoveryCodes returns new recovery codes and their hashes.
+func generateRecoveryCodes() ([]string, []string) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		code := uniuri.NewLenChars(recoveryCodeLength, recoveryCodeChars)
+		codes[i] = code[:recoveryCodeLength/2] + recoveryCodeSeparator + code[recoveryCodeLength/2:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	return codes, hashes
+}
+
+// hashRecoveryCode returns the hash of the recovery code. The codes are random and long enough,
+// so unlike passwords they don't require a slow hash function.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer(recoveryCodeSeparator, "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// findRecoveryCode returns the index of the hash of the recovery code or -1 if there's none.
+func findRecoveryCode(hashes []string, code string) int {
+	hash := []byte(hashRecoveryCode(code))
+
+	idx := -1
+	for i, h := range hashes {
+		if subtle.ConstantTimeCompare([]byte(h), hash) == 1 {
+			idx = i
+		}
+	}
+
+	return idx
+}
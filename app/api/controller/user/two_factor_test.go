@@ -0,0 +1,256 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth/totp"
+	"github.com/harness/gitness/app/jwt"
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/services/settings"
+	systemsvc "github.com/harness/gitness/app/services/system"
+	appstore "github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/encrypt"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+)
+
+type fakeTwoFactorPrincipalStore struct {
+	appstore.PrincipalStore
+	user *types.User
+}
+
+func (s *fakeTwoFactorPrincipalStore) FindUser(_ context.Context, id int64) (*types.User, error) {
+	if id != s.user.ID {
+		return nil, store.ErrResourceNotFound
+	}
+	return s.user, nil
+}
+
+// fakeTwoFactorTOTPStore keeps a single TOTP second factor and checks its version like the database store.
+type fakeTwoFactorTOTPStore struct {
+	appstore.UserTOTPStore
+	otp types.UserTOTP
+}
+
+func (s *fakeTwoFactorTOTPStore) Find(_ context.Context, principalID int64) (*types.UserTOTP, error) {
+	if principalID != s.otp.PrincipalID {
+		return nil, store.ErrResourceNotFound
+	}
+	otp := s.otp
+	return &otp, nil
+}
+
+func (s *fakeTwoFactorTOTPStore) Update(_ context.Context, otp *types.UserTOTP) error {
+	if otp.Version != s.otp.Version {
+		return store.ErrVersionConflict
+	}
+	otp.Version++
+	s.otp = *otp
+	return nil
+}
+
+func (s *fakeTwoFactorTOTPStore) UpdateOptLock(
+	ctx context.Context,
+	otp *types.UserTOTP,
+	mutateFn func(otp *types.UserTOTP) error,
+) (*types.UserTOTP, error) {
+	for {
+		dup := *otp
+		if err := mutateFn(&dup); err != nil {
+			return nil, err
+		}
+
+		err := s.Update(ctx, &dup)
+		if err == nil {
+			return &dup, nil
+		}
+		if !errors.Is(err, store.ErrVersionConflict) {
+			return nil, err
+		}
+
+		otp, _ = s.Find(ctx, otp.PrincipalID)
+	}
+}
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXP"
+
+func setupTwoFactorTest(t *testing.T) (*Controller, *types.User, *fakeTwoFactorTOTPStore) {
+	t.Helper()
+
+	encrypter, err := encrypt.New("0123456789abcdef0123456789abcdef", false)
+	if err != nil {
+		t.Fatalf("failed to create encrypter: %s", err)
+	}
+
+	secret, err := encrypter.Encrypt(testTOTPSecret)
+	if err != nil {
+		t.Fatalf("failed to encrypt secret: %s", err)
+	}
+
+	user := &types.User{ID: 1, UID: "user", Salt: "salt"}
+	totpStore := &fakeTwoFactorTOTPStore{otp: types.UserTOTP{
+		PrincipalID:   user.ID,
+		Secret:        secret,
+		Enabled:       true,
+		RecoveryCodes: []string{hashRecoveryCode("abcde-fghij")},
+	}}
+
+	ctrl := NewController(&types.Config{}, nil, nil, nil,
+		&fakeTwoFactorPrincipalStore{user: user},
+		&fakeLoginTokenStore{}, nil, nil, nil, nil,
+		totpStore, encrypter,
+		systemsvc.ProvideService(settings.NewService(&fakeLoginSettingsStore{})),
+		nil, refcache.RepoFinder{}, nil, nil, nil)
+
+	return ctrl, user, totpStore
+}
+
+func generateTwoFactorChallenge(t *testing.T, user *types.User) string {
+	t.Helper()
+
+	// challenges are revoked with millisecond precision.
+	time.Sleep(2 * time.Millisecond)
+
+	challenge, err := jwt.GenerateForTwoFactorChallenge(user.ID, false, twoFactorChallengeLifetime,
+		user.Salt+twoFactorChallengeSecretSuffix)
+	if err != nil {
+		t.Fatalf("failed to generate challenge: %s", err)
+	}
+
+	return challenge
+}
+
+func currentTOTPCode(t *testing.T) string {
+	t.Helper()
+
+	code, err := totp.Code(testTOTPSecret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatalf("failed to generate code: %s", err)
+	}
+
+	return code
+}
+
+func requireUserErrorStatus(t *testing.T, err error, status int) {
+	t.Helper()
+
+	uErr := &usererror.Error{}
+	if !errors.As(err, &uErr) || uErr.Status != status {
+		t.Fatalf("want error with status %d, got %v", status, err)
+	}
+}
+
+func TestLoginTwoFactor_ChallengeSingleUse(t *testing.T) {
+	ctx := context.Background()
+	ctrl, user, _ := setupTwoFactorTest(t)
+
+	challenge := generateTwoFactorChallenge(t, user)
+
+	resp, err := ctrl.LoginTwoFactor(ctx, &LoginTwoFactorInput{TwoFactorChallenge: challenge, Code: currentTOTPCode(t)})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if resp.AccessToken == "" {
+		t.Errorf("expected an access token")
+	}
+
+	// the recovery code is valid, but the challenge was already used.
+	_, err = ctrl.LoginTwoFactor(ctx, &LoginTwoFactorInput{TwoFactorChallenge: challenge, Code: "abcde-fghij"})
+	requireUserErrorStatus(t, err, http.StatusUnauthorized)
+
+	// a new challenge works.
+	_, err = ctrl.LoginTwoFactor(ctx, &LoginTwoFactorInput{
+		TwoFactorChallenge: generateTwoFactorChallenge(t, user),
+		Code:               "abcde-fghij",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestLoginTwoFactor_Lockout(t *testing.T) {
+	ctx := context.Background()
+	ctrl, user, totpStore := setupTwoFactorTest(t)
+
+	challenge := generateTwoFactorChallenge(t, user)
+
+	for i := 1; i < twoFactorMaxFailedAttempts; i++ {
+		_, err := ctrl.LoginTwoFactor(ctx, &LoginTwoFactorInput{TwoFactorChallenge: challenge, Code: "000000"})
+		requireUserErrorStatus(t, err, http.StatusBadRequest)
+	}
+
+	if totpStore.otp.FailedAttempts != twoFactorMaxFailedAttempts-1 {
+		t.Fatalf("want %d failed attempts, got %d", twoFactorMaxFailedAttempts-1, totpStore.otp.FailedAttempts)
+	}
+
+	_, err := ctrl.LoginTwoFactor(ctx, &LoginTwoFactorInput{TwoFactorChallenge: challenge, Code: "000000"})
+	requireUserErrorStatus(t, err, http.StatusTooManyRequests)
+
+	// the challenge used for the failed attempts is revoked.
+	_, err = ctrl.LoginTwoFactor(ctx, &LoginTwoFactorInput{TwoFactorChallenge: challenge, Code: currentTOTPCode(t)})
+	requireUserErrorStatus(t, err, http.StatusUnauthorized)
+
+	// while locked even a valid code with a new challenge is rejected.
+	_, err = ctrl.LoginTwoFactor(ctx, &LoginTwoFactorInput{
+		TwoFactorChallenge: generateTwoFactorChallenge(t, user),
+		Code:               currentTOTPCode(t),
+	})
+	requireUserErrorStatus(t, err, http.StatusTooManyRequests)
+
+	// once the lockout expires a valid code is accepted again.
+	totpStore.otp.LockedUntil = time.Now().Add(-time.Second).UnixMilli()
+
+	_, err = ctrl.LoginTwoFactor(ctx, &LoginTwoFactorInput{
+		TwoFactorChallenge: generateTwoFactorChallenge(t, user),
+		Code:               currentTOTPCode(t),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestLoginTwoFactor_SuccessResetsFailedAttempts(t *testing.T) {
+	ctx := context.Background()
+	ctrl, user, totpStore := setupTwoFactorTest(t)
+
+	_, err := ctrl.LoginTwoFactor(ctx, &LoginTwoFactorInput{
+		TwoFactorChallenge: generateTwoFactorChallenge(t, user),
+		Code:               "000000",
+	})
+	requireUserErrorStatus(t, err, http.StatusBadRequest)
+
+	if totpStore.otp.FailedAttempts != 1 {
+		t.Fatalf("want 1 failed attempt, got %d", totpStore.otp.FailedAttempts)
+	}
+
+	_, err = ctrl.LoginTwoFactor(ctx, &LoginTwoFactorInput{
+		TwoFactorChallenge: generateTwoFactorChallenge(t, user),
+		Code:               currentTOTPCode(t),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if totpStore.otp.FailedAttempts != 0 {
+		t.Errorf("want failed attempts reset, got %d", totpStore.otp.FailedAttempts)
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/auth/totp"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+type TOTPCodeInput struct {
+	Code string `json:"code"`
+}
+
+// TwoFactorStatus returns the two-factor authentication status of the user.
+func (c *Controller) TwoFactorStatus(
+	ctx context.Context,
+	session *auth.Session,
+	userUID string,
+) (*types.TwoFactorStatus, error) {
+	user, err := findUserFromUID(ctx, c.principalStore, userUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = apiauth.CheckUser(ctx, c.authorizer, session, user, enum.PermissionUserView); err != nil {
+		return nil, err
+	}
+
+	required, err := c.isTwoFactorRequired(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	otp, err := c.findTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &types.TwoFactorStatus{Required: required}
+	if otp != nil && otp.Enabled {
+		status.Enabled = true
+		status.RecoveryCodesRemaining = len(otp.RecoveryCodes)
+		status.Created = otp.Created
+	}
+
+	return status, nil
+}
+
+// EnrollTOTP starts the enrollment of an authenticator app. The enrollment has to be confirmed with EnableTOTP.
+func (c *Controller) EnrollTOTP(
+	ctx context.Context,
+	session *auth.Session,
+) (*types.TOTPEnrollment, error) {
+	user, err := c.findSessionUser(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.enrollTOTP(ctx, user)
+}
+
+// EnableTOTP confirms the enrollment of the authenticator app with a code and returns the recovery codes.
+func (c *Controller) EnableTOTP(
+	ctx context.Context,
+	session *auth.Session,
+	in *TOTPCodeInput,
+) (*types.TwoFactorRecoveryCodes, error) {
+	user, err := c.findSessionUser(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	otp, err := c.findTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if otp == nil {
+		return nil, usererror.BadRequest("Two-factor authentication enrollment was not started")
+	}
+	if otp.Enabled {
+		return nil, usererror.Conflict("Two-factor authentication is already enabled")
+	}
+
+	recoveryCodes, err := c.enableTOTP(ctx, otp, in.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.TwoFactorRecoveryCodes{RecoveryCodes: recoveryCodes}, nil
+}
+
+// DisableTOTP disables two-factor authentication of the user, unless it's required.
+func (c *Controller) DisableTOTP(
+	ctx context.Context,
+	session *auth.Session,
+	in *TOTPCodeInput,
+) error {
+	user, err := c.findSessionUser(ctx, session)
+	if err != nil {
+		return err
+	}
+
+	otp, err := c.findTOTP(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if otp == nil || !otp.Enabled {
+		return usererror.BadRequest("Two-factor authentication is not enabled")
+	}
+
+	required, err := c.isTwoFactorRequired(ctx, user)
+	if err != nil {
+		return err
+	}
+	if required {
+		return usererror.Forbidden("Two-factor authentication is required and can't be disabled")
+	}
+
+	if err = c.verifyTOTPCode(ctx, otp, in.Code, true); err != nil {
+		return err
+	}
+
+	if err = c.totpStore.Delete(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to delete totp of user: %w", err)
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user with new ones.
+func (c *Controller) RegenerateRecoveryCodes(
+	ctx context.Context,
+	session *auth.Session,
+	in *TOTPCodeInput,
+) (*types.TwoFactorRecoveryCodes, error) {
+	user, err := c.findSessionUser(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	otp, err := c.findTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if otp == nil || !otp.Enabled {
+		return nil, usererror.BadRequest("Two-factor authentication is not enabled")
+	}
+
+	if err = c.verifyTOTPCode(ctx, otp, in.Code, false); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, hashes := generateRecoveryCodes()
+	otp.RecoveryCodes = hashes
+
+	if err = c.updateTOTP(ctx, otp); err != nil {
+		return nil, err
+	}
+
+	return &types.TwoFactorRecoveryCodes{RecoveryCodes: recoveryCodes}, nil
+}
+
+// ResetTwoFactor removes the two-factor authentication of a user who lost the authenticator and
+// the recovery codes. If two-factor authentication is required, the user has to set it up on the next login.
+func (c *Controller) ResetTwoFactor(
+	ctx context.Context,
+	session *auth.Session,
+	userUID string,
+) error {
+	user, err := findUserFromUID(ctx, c.principalStore, userUID)
+	if err != nil {
+		return err
+	}
+
+	if err = apiauth.CheckUser(ctx, c.authorizer, session, user, enum.PermissionUserEditAdmin); err != nil {
+		return err
+	}
+
+	if err = c.totpStore.Delete(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to delete totp of user: %w", err)
+	}
+
+	return nil
+}
+
+// findSessionUser returns the user of the session. The second factor can be managed only by the user itself.
+func (c *Controller) findSessionUser(ctx context.Context, session *auth.Session) (*types.User, error) {
+	if session.Principal.Type != enum.PrincipalTypeUser {
+		return nil, usererror.Forbidden("Two-factor authentication can be managed only by users")
+	}
+
+	user, err := c.principalStore.FindUser(ctx, session.Principal.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	return user, nil
+}
+
+// enrollTOTP generates a new secret for the user, replacing any unconfirmed enrollment.
+func (c *Controller) enrollTOTP(ctx context.Context, user *types.User) (*types.TOTPEnrollment, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := c.encrypter.Encrypt(secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt totp secret: %w", err)
+	}
+
+	err = c.tx.WithTx(ctx, func(ctx context.Context) error {
+		otp, err := c.findTOTP(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		if otp != nil && otp.Enabled {
+			return usererror.Conflict("Two-factor authentication is already enabled")
+		}
+
+		if err = c.totpStore.Delete(ctx, user.ID); err != nil {
+			return fmt.Errorf("failed to delete previous totp enrollment: %w", err)
+		}
+
+		now := time.Now().UnixMilli()
+		enrollment := &types.UserTOTP{
+			PrincipalID: user.ID,
+			Secret:      encrypted,
+			Created:     now,
+			Updated:     now,
+		}
+		if otp != nil {
+			// a new enrollment must not reset the lockout or unrevoke challenges.
+			enrollment.FailedAttempts = otp.FailedAttempts
+			enrollment.LockedUntil = otp.LockedUntil
+			enrollment.ChallengesRevoked = otp.ChallengesRevoked
+		}
+
+		err = c.totpStore.Create(ctx, enrollment)
+		if err != nil {
+			return fmt.Errorf("failed to create totp enrollment: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	account := user.Email
+	if account == "" {
+		account = user.UID
+	}
+
+	return &types.TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(totpIssuer, account, secret),
+	}, nil
+}
+
+// enableTOTP confirms the enrollment with the code and returns the new recovery codes.
+func (c *Controller) enableTOTP(ctx context.Context, otp *types.UserTOTP, code string) ([]string, error) {
+	if err := c.verifyTOTPCode(ctx, otp, code, false); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, hashes := generateRecoveryCodes()
+	otp.Enabled = true
+	otp.RecoveryCodes = hashes
+
+	if err := c.updateTOTP(ctx, otp); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
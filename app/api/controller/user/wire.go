@@ -16,8 +16,15 @@ package user
 
 import (
 	"github.com/harness/gitness/app/auth/authz"
+	"github.com/harness/gitness/app/auth/ldap"
+	"github.com/harness/gitness/app/services/accountmail"
+	"github.com/harness/gitness/app/services/notification"
+	"github.com/harness/gitness/app/services/refcache"
+	systemsvc "github.com/harness/gitness/app/services/system"
 	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/encrypt"
 	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/check"
 
 	"github.com/google/wire"
@@ -29,6 +36,7 @@ var WireSet = wire.NewSet(
 )
 
 func ProvideController(
+	config *types.Config,
 	tx dbtx.Transactor,
 	principalUIDCheck check.PrincipalUID,
 	authorizer authz.Authorizer,
@@ -36,13 +44,34 @@ func ProvideController(
 	tokenStore store.TokenStore,
 	membershipStore store.MembershipStore,
 	publicKeyStore store.PublicKeyStore,
+	ldapDirectory *ldap.Directory,
+	ldapIdentityStore store.LDAPIdentityStore,
+	totpStore store.UserTOTPStore,
+	encrypter encrypt.Encrypter,
+	systemService *systemsvc.Service,
+	spaceStore store.SpaceStore,
+	repoFinder refcache.RepoFinder,
+	accountMail *accountmail.Service,
+	chatIdentityStore store.UserChatIdentityStore,
+	chatMessenger notification.DirectMessenger,
 ) *Controller {
 	return NewController(
+		config,
 		tx,
 		principalUIDCheck,
 		authorizer,
 		principalStore,
 		tokenStore,
 		membershipStore,
-		publicKeyStore)
+		publicKeyStore,
+		ldapDirectory,
+		ldapIdentityStore,
+		totpStore,
+		encrypter,
+		systemService,
+		spaceStore,
+		repoFinder,
+		accountMail,
+		chatIdentityStore,
+		chatMessenger)
 }
@@ -21,9 +21,11 @@ import (
 	"github.com/harness/gitness/app/auth"
 	"github.com/harness/gitness/app/bootstrap"
 	"github.com/harness/gitness/app/githook"
+	"github.com/harness/gitness/app/services/publickey"
 	"github.com/harness/gitness/app/url"
 	"github.com/harness/gitness/git"
 	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
 )
 
 // createRPCWriteParams creates base write parameters for git write operations.
@@ -128,6 +130,74 @@ func MapCommit(c *git.Commit) (*types.Commit, error) {
 		nil
 }
 
+// MapCommitWithSignature maps the commit and, if the commit is signed,
+// verifies its signature against the signing keys registered in the system.
+func MapCommitWithSignature(
+	ctx context.Context,
+	publicKeySvc publickey.Service,
+	c *git.Commit,
+) (*types.Commit, error) {
+	commit, err := MapCommit(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Signature == nil {
+		return commit, nil
+	}
+
+	commit.Signature, err = publicKeySvc.VerifySignature(ctx,
+		c.Signature.Signature, c.Signature.Payload, c.Committer.Identity.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify commit signature: %w", err)
+	}
+
+	return commit, nil
+}
+
+// UnverifiedCommitSHAs returns SHAs of the provided commits that don't have a verified signature.
+func UnverifiedCommitSHAs(
+	ctx context.Context,
+	publicKeySvc publickey.Service,
+	commits []git.Commit,
+) ([]string, error) {
+	var unverified []string
+	for i := range commits {
+		commit, err := MapCommitWithSignature(ctx, publicKeySvc, &commits[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if commit.Signature == nil || commit.Signature.Status != enum.GitSignatureStatusVerified {
+			unverified = append(unverified, commit.SHA)
+		}
+	}
+
+	return unverified, nil
+}
+
+// UnreferencedCommitsResolver returns a function that lists SHAs of the commits reachable from rev,
+// but not from any existing reference, that don't have a verified signature.
+// It's used to verify new references to commits that are already stored in the repository.
+func UnreferencedCommitsResolver(
+	gitService git.Interface,
+	publicKeySvc publickey.Service,
+	repo *types.Repository,
+	rev string,
+) func(ctx context.Context, refNames []string) ([]string, error) {
+	return func(ctx context.Context, _ []string) ([]string, error) {
+		output, err := gitService.ListCommitSignatures(ctx, &git.ListCommitSignaturesParams{
+			ReadParams: git.CreateReadParams(repo),
+			Rev:        rev,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list unreferenced commits: %w", err)
+		}
+
+		return UnverifiedCommitSHAs(ctx, publicKeySvc, output.Commits)
+	}
+}
+
 func mapStats(c *git.Commit) *types.CommitStats {
 	if len(c.FileStats) == 0 {
 		return nil
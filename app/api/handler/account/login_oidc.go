@@ -0,0 +1,154 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package account
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/app/api/controller/oidc"
+	"github.com/harness/gitness/app/api/render"
+	"github.com/harness/gitness/app/api/usererror"
+	authoidc "github.com/harness/gitness/app/auth/oidc"
+)
+
+const (
+	// oidcCookieSuffix is appended to the token cookie name to get the name of the cookie
+	// that keeps the authorization request until the identity provider redirects back.
+	oidcCookieSuffix = "_oidc"
+	oidcCookieMaxAge = 10 * time.Minute
+
+	// oidcSignInPath is the path of the UI page that completes the sign-in with the second factor.
+	oidcSignInPath = "/signin"
+)
+
+// HandleOIDCAuthorize returns an http.HandlerFunc that starts the single sign-on
+// by redirecting the user to the OpenID Connect identity provider.
+func HandleOIDCAuthorize(oidcCtrl *oidc.Controller, cookieName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		authReq, authURL, err := oidcCtrl.Authorize(ctx)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		raw, err := json.Marshal(authReq)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		cookie := newOIDCCookie(r, cookieName)
+		cookie.Value = base64.RawURLEncoding.EncodeToString(raw)
+		cookie.MaxAge = int(oidcCookieMaxAge.Seconds())
+		http.SetCookie(w, cookie)
+
+		http.Redirect(w, r, authURL, http.StatusFound)
+	}
+}
+
+// HandleOIDCCallback returns an http.HandlerFunc that completes the single sign-on
+// when the identity provider redirects the user back, and redirects the user to the UI.
+func HandleOIDCCallback(oidcCtrl *oidc.Controller, cookieName string, uiURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		query := r.URL.Query()
+		in := &oidc.CallbackInput{
+			Code:             query.Get("code"),
+			State:            query.Get("state"),
+			Error:            query.Get("error"),
+			ErrorDescription: query.Get("error_description"),
+		}
+
+		authReq := readOIDCCookie(r, cookieName)
+
+		// the authorization request can be used only once.
+		cookie := newOIDCCookie(r, cookieName)
+		cookie.MaxAge = -1
+		http.SetCookie(w, cookie)
+
+		tokenResponse, err := oidcCtrl.Callback(ctx, authReq, in)
+		if redirectURL, ok := twoFactorRedirectURL(err, uiURL); ok {
+			http.Redirect(w, r, redirectURL, http.StatusFound)
+			return
+		}
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		includeTokenCookie(r, w, tokenResponse, cookieName)
+
+		http.Redirect(w, r, uiURL, http.StatusFound)
+	}
+}
+
+// twoFactorRedirectURL returns the URL of the UI page that continues the sign-in
+// if the error holds a two-factor challenge. The challenge is passed in the fragment,
+// so it isn't sent to any server or written to access logs.
+func twoFactorRedirectURL(err error, uiURL string) (string, bool) {
+	uErr := &usererror.Error{}
+	if !errors.As(err, &uErr) {
+		return "", false
+	}
+
+	challenge, ok := uErr.Values["two_factor_challenge"].(string)
+	if !ok || challenge == "" {
+		return "", false
+	}
+
+	enrollment, _ := uErr.Values["enrollment_required"].(bool)
+
+	fragment := url.Values{}
+	fragment.Set("two_factor_challenge", challenge)
+	fragment.Set("enrollment_required", fmt.Sprint(enrollment))
+
+	return strings.TrimRight(uiURL, "/") + oidcSignInPath + "#" + fragment.Encode(), true
+}
+
+func readOIDCCookie(r *http.Request, cookieName string) *authoidc.AuthRequest {
+	cookie, err := r.Cookie(cookieName + oidcCookieSuffix)
+	if errors.Is(err, http.ErrNoCookie) {
+		return nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	if err != nil {
+		return nil
+	}
+
+	authReq := &authoidc.AuthRequest{}
+	if err = json.Unmarshal(raw, authReq); err != nil {
+		return nil
+	}
+
+	return authReq
+}
+
+func newOIDCCookie(r *http.Request, cookieName string) *http.Cookie {
+	cookie := newEmptyTokenCookie(r, cookieName+oidcCookieSuffix)
+	// the identity provider redirects back with a cross-site navigation, a strict cookie wouldn't be sent.
+	cookie.SameSite = http.SameSiteLaxMode
+	return cookie
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package account
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/harness/gitness/app/api/usererror"
+)
+
+func TestTwoFactorRedirectURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		wantOK bool
+		want   string
+	}{
+		{
+			name: "challenge",
+			err: usererror.NewWithPayload(http.StatusUnauthorized, "code required", map[string]any{
+				"two_factor_challenge": "abc",
+				"enrollment_required":  true,
+			}),
+			wantOK: true,
+			want:   "http://localhost/signin#enrollment_required=true&two_factor_challenge=abc",
+		},
+		{
+			name: "no-challenge",
+			err:  usererror.ErrUnauthorized,
+		},
+		{
+			name: "other-error",
+			err:  errors.New("failure"),
+		},
+		{
+			name: "no-error",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := twoFactorRedirectURL(test.err, "http://localhost/")
+			if ok != test.wantOK || got != test.want {
+				t.Errorf("want %q %t, got %q %t", test.want, test.wantOK, got, ok)
+			}
+		})
+	}
+}
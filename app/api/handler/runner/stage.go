@@ -0,0 +1,129 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/harness/gitness/app/api/controller/runner"
+	"github.com/harness/gitness/app/api/render"
+	"github.com/harness/gitness/app/api/request"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/drone/runner-go/client"
+)
+
+// HandleRequestStage returns an http.HandlerFunc that waits for the next stage the remote runner
+// can execute. No content is returned if no stage became available before the poll timeout.
+func HandleRequestStage(runnerCtrl *runner.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		rnr, _ := request.RunnerFrom(ctx)
+
+		in := new(client.Filter)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequestf(ctx, w, "Invalid request body: %s.", err)
+			return
+		}
+
+		stage, err := runnerCtrl.Request(ctx, rnr, in)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+		if stage == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		render.JSON(w, http.StatusOK, stage)
+	}
+}
+
+// HandleAcceptStage returns an http.HandlerFunc that assigns the stage to the remote runner.
+func HandleAcceptStage(runnerCtrl *runner.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		rnr, _ := request.RunnerFrom(ctx)
+
+		stageID, err := request.GetRunnerStageIDFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		stage, err := runnerCtrl.Accept(ctx, rnr, stageID)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		render.JSON(w, http.StatusOK, stage)
+	}
+}
+
+// HandleDetailStage returns an http.HandlerFunc that writes everything the remote runner
+// needs to execute the stage.
+func HandleDetailStage(runnerCtrl *runner.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		rnr, _ := request.RunnerFrom(ctx)
+
+		stageID, err := request.GetRunnerStageIDFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		details, err := runnerCtrl.Detail(ctx, rnr, stageID)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		render.JSON(w, http.StatusOK, details)
+	}
+}
+
+// HandleUpdateStage returns an http.HandlerFunc that updates the stage the remote runner is executing.
+func HandleUpdateStage(runnerCtrl *runner.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		rnr, _ := request.RunnerFrom(ctx)
+
+		stageID, err := request.GetRunnerStageIDFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		in := new(drone.Stage)
+		err = json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequestf(ctx, w, "Invalid request body: %s.", err)
+			return
+		}
+
+		stage, err := runnerCtrl.UpdateStage(ctx, rnr, stageID, in)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		render.JSON(w, http.StatusOK, stage)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package space
+
+import (
+	"net/http"
+
+	"github.com/harness/gitness/app/api/controller/space"
+	"github.com/harness/gitness/app/api/render"
+	"github.com/harness/gitness/app/api/request"
+)
+
+// HandleImportArchive creates a new space and starts the import of the space archive in the request body.
+func HandleImportArchive(spaceCtrl *space.Controller, maxArchiveSize int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		session, _ := request.AuthSessionFrom(ctx)
+
+		isPublic, err := request.GetIsPublicFromQuery(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		// the request body holds the archive, so the parameters of the new space are passed in the url.
+		in := &space.ImportArchiveInput{
+			CreateInput: space.CreateInput{
+				ParentRef:   request.QueryParamOrDefault(r, request.QueryParamParentRef, ""),
+				Identifier:  request.QueryParamOrDefault(r, request.QueryParamIdentifier, ""),
+				Description: request.QueryParamOrDefault(r, request.QueryParamDescription, ""),
+				IsPublic:    isPublic,
+			},
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxArchiveSize)
+
+		space, err := spaceCtrl.ImportArchive(ctx, session, in, r.Body)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		render.JSON(w, http.StatusCreated, space)
+	}
+}
+
+// HandleImportArchiveProgress returns the progress of the space archive import.
+func HandleImportArchiveProgress(spaceCtrl *space.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		session, _ := request.AuthSessionFrom(ctx)
+		spaceRef, err := request.GetSpaceRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		progress, err := spaceCtrl.ImportArchiveProgress(ctx, session, spaceRef)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		render.JSON(w, http.StatusOK, progress)
+	}
+}
@@ -26,13 +26,22 @@ type UI struct {
 	ShowPlugin bool `json:"show_plugin"`
 }
 
+type OIDC struct {
+	Enabled               bool   `json:"enabled"`
+	ProviderName          string `json:"provider_name"`
+	PasswordLoginDisabled bool   `json:"password_login_disabled"`
+}
+
 type ConfigOutput struct {
 	UserSignupAllowed             bool `json:"user_signup_allowed"`
 	PublicResourceCreationEnabled bool `json:"public_resource_creation_enabled"`
 	SSHEnabled                    bool `json:"ssh_enabled"`
 	GitspaceEnabled               bool `json:"gitspace_enabled"`
 	ArtifactRegistryEnabled       bool `json:"artifact_registry_enabled"`
+	PasswordResetEnabled          bool `json:"password_reset_enabled"`
+	EmailVerificationRequired     bool `json:"email_verification_required"`
 	UI                            UI   `json:"ui"`
+	OIDC                          OIDC `json:"oidc"`
 }
 
 // HandleGetConfig returns an http.HandlerFunc that processes an http.Request
@@ -53,7 +62,14 @@ func HandleGetConfig(config *types.Config, sysCtrl *system.Controller) http.Hand
 			PublicResourceCreationEnabled: config.PublicResourceCreationEnabled,
 			GitspaceEnabled:               config.Gitspace.Enable,
 			ArtifactRegistryEnabled:       config.Registry.Enable,
+			PasswordResetEnabled:          config.AccountMail.PasswordResetEnabled && sysCtrl.IsPasswordLoginAllowed(),
+			EmailVerificationRequired:     config.AccountMail.EmailVerificationRequired,
 			UI:                            UI{ShowPlugin: config.UI.ShowPlugin},
+			OIDC: OIDC{
+				Enabled:               config.OIDC.Enabled,
+				ProviderName:          config.OIDC.ProviderName,
+				PasswordLoginDisabled: !sysCtrl.IsPasswordLoginAllowed(),
+			},
 		})
 	}
 }
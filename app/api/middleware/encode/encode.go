@@ -62,6 +62,7 @@ func processGitRequest(r *http.Request) (bool, error) {
 	const receivePack = "git-receive-pack"
 	const receivePackPath = "/" + receivePack
 	const serviceParam = "service"
+	const lfsPath = "/info/lfs/"
 
 	allowedServices := []string{
 		uploadPack,
@@ -73,6 +74,11 @@ func processGitRequest(r *http.Request) (bool, error) {
 		urlPath = r.URL.RawPath
 	}
 
+	// git lfs requests (e.g. "/space1/repo1/info/lfs/objects/batch")
+	if strings.Contains(urlPath, lfsPath) {
+		return pathTerminatedWithMarkerAndURL(r, "", lfsPath, lfsPath, urlPath)
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		// check if request is coming from git client
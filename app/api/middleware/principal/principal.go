@@ -64,7 +64,7 @@ func RestrictTo(pType enum.PrincipalType) func(http.Handler) http.Handler {
 /*
  * RestrictToAdmin returns an http.HandlerFunc middleware that ensures the principal
  * is an admin. In case there is no authenticated principal,
- * or the principal isn't an admin, an error is rendered.
+ * the principal isn't an admin, or the session is restricted, an error is rendered.
  */
 func RestrictToAdmin() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -79,6 +79,15 @@ func RestrictToAdmin() func(http.Handler) http.Handler {
 				return
 			}
 
+			// restricted sessions (e.g. scoped tokens) can't be used for admin operations.
+			if session, ok := request.AuthSessionFrom(ctx); ok &&
+				session.Metadata != nil && session.Metadata.ImpactsAuthorization() {
+				log.Ctx(ctx).Debug().Msg("The session of the admin is restricted")
+
+				render.Forbidden(ctx, w)
+				return
+			}
+
 			next.ServeHTTP(w, r)
 		})
 	}
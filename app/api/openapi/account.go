@@ -46,11 +46,49 @@ type loginRequest struct {
 	user.LoginInput
 }
 
+// request to complete the two-factor challenge of a login.
+type loginTwoFactorRequest struct {
+	user.LoginTwoFactorInput
+}
+
+// request to start the enrollment required by the two-factor challenge of a login.
+type loginTwoFactorEnrollRequest struct {
+	user.LoginTwoFactorEnrollInput
+}
+
 // request to register an account.
 type registerRequest struct {
 	user.RegisterInput
 }
 
+// request to send a password reset link.
+type requestPasswordResetRequest struct {
+	user.RequestPasswordResetInput
+}
+
+// request to reset the password using a password reset link.
+type resetPasswordRequest struct {
+	user.ResetPasswordInput
+}
+
+// request to verify the email address using a verification link.
+type verifyEmailRequest struct {
+	user.VerifyEmailInput
+}
+
+// request to send a new email verification link.
+type requestEmailVerificationRequest struct {
+	user.RequestEmailVerificationInput
+}
+
+// request to complete an OpenID Connect login.
+type oidcCallbackRequest struct {
+	Code             string `query:"code"`
+	State            string `query:"state"`
+	Error            string `query:"error"`
+	ErrorDescription string `query:"error_description"`
+}
+
 // helper function that constructs the openapi specification
 // for the account registration and login endpoints.
 func buildAccount(reflector *openapi3.Reflector) {
@@ -61,10 +99,35 @@ func buildAccount(reflector *openapi3.Reflector) {
 	_ = reflector.SetRequest(&onLogin, new(loginRequest), http.MethodPost)
 	_ = reflector.SetJSONResponse(&onLogin, new(types.TokenResponse), http.StatusOK)
 	_ = reflector.SetJSONResponse(&onLogin, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&onLogin, new(usererror.Error), http.StatusUnauthorized)
 	_ = reflector.SetJSONResponse(&onLogin, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&onLogin, new(usererror.Error), http.StatusForbidden)
 	_ = reflector.SetJSONResponse(&onLogin, new(usererror.Error), http.StatusNotFound)
 	_ = reflector.Spec.AddOperation(http.MethodPost, "/login", onLogin)
 
+	onLoginTwoFactor := openapi3.Operation{}
+	onLoginTwoFactor.WithTags("account")
+	onLoginTwoFactor.WithParameters(queryParameterIncludeCookie)
+	onLoginTwoFactor.WithMapOfAnything(map[string]interface{}{"operationId": "onLoginTwoFactor"})
+	_ = reflector.SetRequest(&onLoginTwoFactor, new(loginTwoFactorRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&onLoginTwoFactor, new(types.TwoFactorLoginResponse), http.StatusOK)
+	_ = reflector.SetJSONResponse(&onLoginTwoFactor, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&onLoginTwoFactor, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&onLoginTwoFactor, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&onLoginTwoFactor, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/login/2fa", onLoginTwoFactor)
+
+	onLoginTwoFactorEnroll := openapi3.Operation{}
+	onLoginTwoFactorEnroll.WithTags("account")
+	onLoginTwoFactorEnroll.WithMapOfAnything(map[string]interface{}{"operationId": "onLoginTwoFactorEnroll"})
+	_ = reflector.SetRequest(&onLoginTwoFactorEnroll, new(loginTwoFactorEnrollRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&onLoginTwoFactorEnroll, new(types.TOTPEnrollment), http.StatusOK)
+	_ = reflector.SetJSONResponse(&onLoginTwoFactorEnroll, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&onLoginTwoFactorEnroll, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&onLoginTwoFactorEnroll, new(usererror.Error), http.StatusConflict)
+	_ = reflector.SetJSONResponse(&onLoginTwoFactorEnroll, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/login/2fa/enroll", onLoginTwoFactorEnroll)
+
 	opLogout := openapi3.Operation{}
 	opLogout.WithTags("account")
 	opLogout.WithMapOfAnything(map[string]interface{}{"operationId": "opLogout"})
@@ -83,5 +146,66 @@ func buildAccount(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&onRegister, new(types.TokenResponse), http.StatusOK)
 	_ = reflector.SetJSONResponse(&onRegister, new(usererror.Error), http.StatusInternalServerError)
 	_ = reflector.SetJSONResponse(&onRegister, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&onRegister, new(usererror.Error), http.StatusForbidden)
 	_ = reflector.Spec.AddOperation(http.MethodPost, "/register", onRegister)
+
+	onRequestPasswordReset := openapi3.Operation{}
+	onRequestPasswordReset.WithTags("account")
+	onRequestPasswordReset.WithMapOfAnything(map[string]interface{}{"operationId": "onRequestPasswordReset"})
+	_ = reflector.SetRequest(&onRequestPasswordReset, new(requestPasswordResetRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&onRequestPasswordReset, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&onRequestPasswordReset, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&onRequestPasswordReset, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&onRequestPasswordReset, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/password-reset", onRequestPasswordReset)
+
+	onResetPassword := openapi3.Operation{}
+	onResetPassword.WithTags("account")
+	onResetPassword.WithMapOfAnything(map[string]interface{}{"operationId": "onResetPassword"})
+	_ = reflector.SetRequest(&onResetPassword, new(resetPasswordRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&onResetPassword, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&onResetPassword, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&onResetPassword, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&onResetPassword, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/password-reset/confirm", onResetPassword)
+
+	onVerifyEmail := openapi3.Operation{}
+	onVerifyEmail.WithTags("account")
+	onVerifyEmail.WithMapOfAnything(map[string]interface{}{"operationId": "onVerifyEmail"})
+	_ = reflector.SetRequest(&onVerifyEmail, new(verifyEmailRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&onVerifyEmail, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&onVerifyEmail, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&onVerifyEmail, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/verify-email", onVerifyEmail)
+
+	onRequestEmailVerification := openapi3.Operation{}
+	onRequestEmailVerification.WithTags("account")
+	onRequestEmailVerification.WithMapOfAnything(
+		map[string]interface{}{"operationId": "onRequestEmailVerification"})
+	_ = reflector.SetRequest(&onRequestEmailVerification, new(requestEmailVerificationRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&onRequestEmailVerification, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&onRequestEmailVerification, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&onRequestEmailVerification, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&onRequestEmailVerification, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/verify-email/resend", onRequestEmailVerification)
+
+	onLoginOIDC := openapi3.Operation{}
+	onLoginOIDC.WithTags("account")
+	onLoginOIDC.WithMapOfAnything(map[string]interface{}{"operationId": "onLoginOIDC"})
+	_ = reflector.SetRequest(&onLoginOIDC, nil, http.MethodGet)
+	_ = reflector.SetJSONResponse(&onLoginOIDC, nil, http.StatusFound)
+	_ = reflector.SetJSONResponse(&onLoginOIDC, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&onLoginOIDC, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/login/oidc", onLoginOIDC)
+
+	onLoginOIDCCallback := openapi3.Operation{}
+	onLoginOIDCCallback.WithTags("account")
+	onLoginOIDCCallback.WithMapOfAnything(map[string]interface{}{"operationId": "onLoginOIDCCallback"})
+	_ = reflector.SetRequest(&onLoginOIDCCallback, new(oidcCallbackRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&onLoginOIDCCallback, nil, http.StatusFound)
+	_ = reflector.SetJSONResponse(&onLoginOIDCCallback, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&onLoginOIDCCallback, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&onLoginOIDCCallback, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&onLoginOIDCCallback, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/login/oidc/callback", onLoginOIDCCallback)
 }
@@ -0,0 +1,70 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/audit"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+type (
+	// auditEventFilterRequest holds the query parameters for listing audit events.
+	auditEventFilterRequest struct {
+		ResourceTypes []string `query:"resource_type"`
+		Actions       []string `query:"action"        enum:"created,updated,deleted,bypassed"`
+		PrincipalIDs  []int64  `query:"principal_id"`
+		StartTime     int64    `query:"start_time"    description:"Only return events at or after this time (unix millis)."`
+		EndTime       int64    `query:"end_time"      description:"Only return events at or before this time (unix millis)."`
+
+		// include pagination request
+		paginationRequest
+	}
+
+	// listSpaceAuditEventsRequest is the request for listing audit events of a space.
+	listSpaceAuditEventsRequest struct {
+		spaceRequest
+		auditEventFilterRequest
+	}
+)
+
+// auditOperations constructs the openapi specification for audit event operations.
+func auditOperations(reflector *openapi3.Reflector) {
+	opListSpace := openapi3.Operation{}
+	opListSpace.WithTags("space")
+	opListSpace.WithMapOfAnything(map[string]interface{}{"operationId": "listSpaceAuditEvents"})
+	_ = reflector.SetRequest(&opListSpace, new(listSpaceAuditEventsRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&opListSpace, []audit.Event{}, http.StatusOK)
+	_ = reflector.SetJSONResponse(&opListSpace, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opListSpace, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opListSpace, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opListSpace, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opListSpace, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/spaces/{space_ref}/audit-events", opListSpace)
+
+	opList := openapi3.Operation{}
+	opList.WithTags("admin")
+	opList.WithMapOfAnything(map[string]interface{}{"operationId": "adminListAuditEvents"})
+	_ = reflector.SetRequest(&opList, new(auditEventFilterRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&opList, []audit.Event{}, http.StatusOK)
+	_ = reflector.SetJSONResponse(&opList, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opList, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opList, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opList, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/admin/audit-events", opList)
+}
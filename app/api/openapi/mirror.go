@@ -0,0 +1,112 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/types"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+type createMirrorRequest struct {
+	repoRequest
+	types.MirrorCreateInput
+}
+
+type mirrorRequest struct {
+	repoRequest
+	Identifier string `path:"mirror_identifier"`
+}
+
+type updateMirrorRequest struct {
+	mirrorRequest
+	types.MirrorUpdateInput
+}
+
+// mirrorOperations constructs the openapi specification for repository mirror operations.
+func mirrorOperations(reflector *openapi3.Reflector) {
+	createMirror := openapi3.Operation{}
+	createMirror.WithTags("mirror")
+	createMirror.WithMapOfAnything(map[string]interface{}{"operationId": "createMirror"})
+	_ = reflector.SetRequest(&createMirror, new(createMirrorRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&createMirror, new(types.Mirror), http.StatusCreated)
+	_ = reflector.SetJSONResponse(&createMirror, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&createMirror, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&createMirror, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&createMirror, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/repos/{repo_ref}/mirrors", createMirror)
+
+	listMirrors := openapi3.Operation{}
+	listMirrors.WithTags("mirror")
+	listMirrors.WithMapOfAnything(map[string]interface{}{"operationId": "listMirrors"})
+	_ = reflector.SetRequest(&listMirrors, new(repoRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&listMirrors, new([]types.Mirror), http.StatusOK)
+	_ = reflector.SetJSONResponse(&listMirrors, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&listMirrors, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&listMirrors, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&listMirrors, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/mirrors", listMirrors)
+
+	getMirror := openapi3.Operation{}
+	getMirror.WithTags("mirror")
+	getMirror.WithMapOfAnything(map[string]interface{}{"operationId": "getMirror"})
+	_ = reflector.SetRequest(&getMirror, new(mirrorRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&getMirror, new(types.Mirror), http.StatusOK)
+	_ = reflector.SetJSONResponse(&getMirror, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&getMirror, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&getMirror, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&getMirror, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&getMirror, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/mirrors/{mirror_identifier}", getMirror)
+
+	updateMirror := openapi3.Operation{}
+	updateMirror.WithTags("mirror")
+	updateMirror.WithMapOfAnything(map[string]interface{}{"operationId": "updateMirror"})
+	_ = reflector.SetRequest(&updateMirror, new(updateMirrorRequest), http.MethodPatch)
+	_ = reflector.SetJSONResponse(&updateMirror, new(types.Mirror), http.StatusOK)
+	_ = reflector.SetJSONResponse(&updateMirror, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&updateMirror, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&updateMirror, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&updateMirror, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&updateMirror, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPatch, "/repos/{repo_ref}/mirrors/{mirror_identifier}", updateMirror)
+
+	deleteMirror := openapi3.Operation{}
+	deleteMirror.WithTags("mirror")
+	deleteMirror.WithMapOfAnything(map[string]interface{}{"operationId": "deleteMirror"})
+	_ = reflector.SetRequest(&deleteMirror, new(mirrorRequest), http.MethodDelete)
+	_ = reflector.SetJSONResponse(&deleteMirror, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&deleteMirror, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&deleteMirror, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&deleteMirror, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&deleteMirror, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&deleteMirror, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodDelete, "/repos/{repo_ref}/mirrors/{mirror_identifier}", deleteMirror)
+
+	syncMirror := openapi3.Operation{}
+	syncMirror.WithTags("mirror")
+	syncMirror.WithMapOfAnything(map[string]interface{}{"operationId": "syncMirror"})
+	_ = reflector.SetRequest(&syncMirror, new(mirrorRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&syncMirror, new(types.Mirror), http.StatusOK)
+	_ = reflector.SetJSONResponse(&syncMirror, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&syncMirror, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&syncMirror, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&syncMirror, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&syncMirror, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/repos/{repo_ref}/mirrors/{mirror_identifier}/sync", syncMirror)
+}
@@ -69,10 +69,13 @@ func (*OpenAPI) Generate() *openapi3.Spec {
 	resourceOperations(&reflector)
 	pullReqOperations(&reflector)
 	webhookOperations(&reflector)
+	mirrorOperations(&reflector)
 	checkOperations(&reflector)
 	uploadOperations(&reflector)
 	gitspaceOperations(&reflector)
 	infraProviderOperations(&reflector)
+	auditOperations(&reflector)
+	runnerOperations(&reflector)
 
 	//
 	// define security scheme
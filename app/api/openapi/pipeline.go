@@ -49,6 +49,11 @@ type logRequest struct {
 	StepNum  string `path:"step_number"`
 }
 
+type stageRequest struct {
+	executionRequest
+	StageNum string `path:"stage_number"`
+}
+
 type createExecutionRequest struct {
 	pipelineRequest
 }
@@ -241,6 +246,60 @@ func pipelineOperations(reflector *openapi3.Reflector) {
 	_ = reflector.Spec.AddOperation(http.MethodPost,
 		"/repos/{repo_ref}/pipelines/{pipeline_identifier}/executions/{execution_number}/cancel", executionCancel)
 
+	executionRerun := openapi3.Operation{}
+	executionRerun.WithTags("pipeline")
+	executionRerun.WithMapOfAnything(map[string]interface{}{"operationId": "rerunExecution"})
+	_ = reflector.SetRequest(&executionRerun, new(getExecutionRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&executionRerun, new(types.Execution), http.StatusCreated)
+	_ = reflector.SetJSONResponse(&executionRerun, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&executionRerun, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&executionRerun, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&executionRerun, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&executionRerun, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPost,
+		"/repos/{repo_ref}/pipelines/{pipeline_identifier}/executions/{execution_number}/rerun", executionRerun)
+
+	executionRerunFailed := openapi3.Operation{}
+	executionRerunFailed.WithTags("pipeline")
+	executionRerunFailed.WithMapOfAnything(map[string]interface{}{"operationId": "rerunFailedExecutionStages"})
+	_ = reflector.SetRequest(&executionRerunFailed, new(getExecutionRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&executionRerunFailed, new(types.Execution), http.StatusCreated)
+	_ = reflector.SetJSONResponse(&executionRerunFailed, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&executionRerunFailed, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&executionRerunFailed, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&executionRerunFailed, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&executionRerunFailed, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPost,
+		"/repos/{repo_ref}/pipelines/{pipeline_identifier}/executions/{execution_number}/rerun-failed", executionRerunFailed)
+
+	stageApprove := openapi3.Operation{}
+	stageApprove.WithTags("pipeline")
+	stageApprove.WithMapOfAnything(map[string]interface{}{"operationId": "approveStage"})
+	_ = reflector.SetRequest(&stageApprove, new(stageRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&stageApprove, new(types.Stage), http.StatusOK)
+	_ = reflector.SetJSONResponse(&stageApprove, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&stageApprove, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&stageApprove, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&stageApprove, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&stageApprove, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPost,
+		"/repos/{repo_ref}/pipelines/{pipeline_identifier}/executions/{execution_number}/stages/{stage_number}/approve",
+		stageApprove)
+
+	stageDecline := openapi3.Operation{}
+	stageDecline.WithTags("pipeline")
+	stageDecline.WithMapOfAnything(map[string]interface{}{"operationId": "declineStage"})
+	_ = reflector.SetRequest(&stageDecline, new(stageRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&stageDecline, new(types.Stage), http.StatusOK)
+	_ = reflector.SetJSONResponse(&stageDecline, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&stageDecline, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&stageDecline, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&stageDecline, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&stageDecline, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPost,
+		"/repos/{repo_ref}/pipelines/{pipeline_identifier}/executions/{execution_number}/stages/{stage_number}/decline",
+		stageDecline)
+
 	executionDelete := openapi3.Operation{}
 	executionDelete.WithTags("pipeline")
 	executionDelete.WithMapOfAnything(map[string]interface{}{"operationId": "deleteExecution"})
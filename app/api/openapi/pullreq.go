@@ -72,6 +72,16 @@ type mergePullReq struct {
 	pullreq.MergeInput
 }
 
+type autoMergePullReq struct {
+	pullReqRequest
+	pullreq.AutoMergeInput
+}
+
+type mergeQueueAddPullReq struct {
+	pullReqRequest
+	pullreq.MergeQueueAddInput
+}
+
 type commentCreatePullReqRequest struct {
 	pullReqRequest
 	pullreq.CommentCreateInput
@@ -702,6 +712,71 @@ func pullReqOperations(reflector *openapi3.Reflector) {
 	_ = reflector.Spec.AddOperation(http.MethodPost,
 		"/repos/{repo_ref}/pullreq/{pullreq_number}/merge", mergePullReqOp)
 
+	autoMergeEnableOp := openapi3.Operation{}
+	autoMergeEnableOp.WithTags("pullreq")
+	autoMergeEnableOp.WithMapOfAnything(map[string]interface{}{"operationId": "autoMergeEnablePullReq"})
+	_ = reflector.SetRequest(&autoMergeEnableOp, new(autoMergePullReq), http.MethodPost)
+	_ = reflector.SetJSONResponse(&autoMergeEnableOp, new(types.PullReqAutoMerge), http.StatusOK)
+	_ = reflector.SetJSONResponse(&autoMergeEnableOp, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&autoMergeEnableOp, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&autoMergeEnableOp, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&autoMergeEnableOp, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&autoMergeEnableOp, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPost,
+		"/repos/{repo_ref}/pullreq/{pullreq_number}/auto-merge", autoMergeEnableOp)
+
+	autoMergeDisableOp := openapi3.Operation{}
+	autoMergeDisableOp.WithTags("pullreq")
+	autoMergeDisableOp.WithMapOfAnything(map[string]interface{}{"operationId": "autoMergeDisablePullReq"})
+	_ = reflector.SetRequest(&autoMergeDisableOp, new(pullReqRequest), http.MethodDelete)
+	_ = reflector.SetJSONResponse(&autoMergeDisableOp, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&autoMergeDisableOp, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&autoMergeDisableOp, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&autoMergeDisableOp, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&autoMergeDisableOp, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodDelete,
+		"/repos/{repo_ref}/pullreq/{pullreq_number}/auto-merge", autoMergeDisableOp)
+
+	mergeQueueAddOp := openapi3.Operation{}
+	mergeQueueAddOp.WithTags("pullreq")
+	mergeQueueAddOp.WithMapOfAnything(map[string]interface{}{"operationId": "mergeQueueAddPullReq"})
+	_ = reflector.SetRequest(&mergeQueueAddOp, new(mergeQueueAddPullReq), http.MethodPost)
+	_ = reflector.SetJSONResponse(&mergeQueueAddOp, new(types.MergeQueueEntry), http.StatusOK)
+	_ = reflector.SetJSONResponse(&mergeQueueAddOp, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&mergeQueueAddOp, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&mergeQueueAddOp, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&mergeQueueAddOp, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&mergeQueueAddOp, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.SetJSONResponse(&mergeQueueAddOp, new(usererror.Error), http.StatusConflict)
+	_ = reflector.SetJSONResponse(&mergeQueueAddOp, new(types.MergeViolations), http.StatusUnprocessableEntity)
+	_ = reflector.Spec.AddOperation(http.MethodPost,
+		"/repos/{repo_ref}/pullreq/{pullreq_number}/merge-queue", mergeQueueAddOp)
+
+	mergeQueueRemoveOp := openapi3.Operation{}
+	mergeQueueRemoveOp.WithTags("pullreq")
+	mergeQueueRemoveOp.WithMapOfAnything(map[string]interface{}{"operationId": "mergeQueueRemovePullReq"})
+	_ = reflector.SetRequest(&mergeQueueRemoveOp, new(pullReqRequest), http.MethodDelete)
+	_ = reflector.SetJSONResponse(&mergeQueueRemoveOp, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&mergeQueueRemoveOp, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&mergeQueueRemoveOp, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&mergeQueueRemoveOp, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&mergeQueueRemoveOp, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodDelete,
+		"/repos/{repo_ref}/pullreq/{pullreq_number}/merge-queue", mergeQueueRemoveOp)
+
+	mergeQueueListOp := openapi3.Operation{}
+	mergeQueueListOp.WithTags("pullreq")
+	mergeQueueListOp.WithMapOfAnything(map[string]interface{}{"operationId": "mergeQueueList"})
+	mergeQueueListOp.WithParameters(queryParameterBranch)
+	_ = reflector.SetRequest(&mergeQueueListOp, new(repoRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&mergeQueueListOp, []types.MergeQueueEntry{}, http.StatusOK)
+	_ = reflector.SetJSONResponse(&mergeQueueListOp, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&mergeQueueListOp, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&mergeQueueListOp, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&mergeQueueListOp, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&mergeQueueListOp, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/merge-queue", mergeQueueListOp)
+
 	opListCommits := openapi3.Operation{}
 	opListCommits.WithTags("pullreq")
 	opListCommits.WithMapOfAnything(map[string]interface{}{"operationId": "listPullReqCommits"})
@@ -59,6 +59,11 @@ type moveRepoRequest struct {
 	repo.MoveInput
 }
 
+type forkRepoRequest struct {
+	repoRequest
+	repo.ForkInput
+}
+
 type getContentRequest struct {
 	repoRequest
 	Path string `path:"path"`
@@ -825,6 +830,17 @@ func repoOperations(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&opMove, new(usererror.Error), http.StatusForbidden)
 	_ = reflector.Spec.AddOperation(http.MethodPost, "/repos/{repo_ref}/move", opMove)
 
+	opFork := openapi3.Operation{}
+	opFork.WithTags("repository")
+	opFork.WithMapOfAnything(map[string]interface{}{"operationId": "forkRepository"})
+	_ = reflector.SetRequest(&opFork, new(forkRepoRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&opFork, new(repo.RepositoryOutput), http.StatusCreated)
+	_ = reflector.SetJSONResponse(&opFork, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opFork, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opFork, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opFork, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/repos/{repo_ref}/fork", opFork)
+
 	opUpdatePublicAccess := openapi3.Operation{}
 	opUpdatePublicAccess.WithTags("repository")
 	opUpdatePublicAccess.WithMapOfAnything(
@@ -0,0 +1,85 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/harness/gitness/app/api/controller/runner"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/types"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+type (
+	// registerRunnerRequest is the request for registering a remote runner.
+	registerRunnerRequest struct {
+		runner.RegisterInput
+	}
+
+	// runnerRequest is the request for runner specific operations.
+	runnerRequest struct {
+		Identifier string `path:"runner_identifier"`
+	}
+)
+
+// runnerOperations registers the operations of the remote pipeline runners.
+// The protocol the runners themselves use is the one of drone runners and isn't documented here.
+func runnerOperations(reflector *openapi3.Reflector) {
+	opRegister := openapi3.Operation{}
+	opRegister.WithTags("admin")
+	opRegister.WithMapOfAnything(map[string]interface{}{"operationId": "adminRegisterRunner"})
+	_ = reflector.SetRequest(&opRegister, new(registerRunnerRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&opRegister, new(runner.RegisterOutput), http.StatusCreated)
+	_ = reflector.SetJSONResponse(&opRegister, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opRegister, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opRegister, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opRegister, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opRegister, new(usererror.Error), http.StatusConflict)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/admin/runners", opRegister)
+
+	opList := openapi3.Operation{}
+	opList.WithTags("admin")
+	opList.WithMapOfAnything(map[string]interface{}{"operationId": "adminListRunners"})
+	_ = reflector.SetRequest(&opList, nil, http.MethodGet)
+	_ = reflector.SetJSONResponse(&opList, new([]*types.Runner), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opList, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opList, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opList, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/admin/runners", opList)
+
+	opFind := openapi3.Operation{}
+	opFind.WithTags("admin")
+	opFind.WithMapOfAnything(map[string]interface{}{"operationId": "adminGetRunner"})
+	_ = reflector.SetRequest(&opFind, new(runnerRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&opFind, new(types.Runner), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opFind, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opFind, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opFind, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opFind, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/admin/runners/{runner_identifier}", opFind)
+
+	opDelete := openapi3.Operation{}
+	opDelete.WithTags("admin")
+	opDelete.WithMapOfAnything(map[string]interface{}{"operationId": "adminDeleteRunner"})
+	_ = reflector.SetRequest(&opDelete, new(runnerRequest), http.MethodDelete)
+	_ = reflector.SetJSONResponse(&opDelete, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&opDelete, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opDelete, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opDelete, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opDelete, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodDelete, "/admin/runners/{runner_identifier}", opDelete)
+}
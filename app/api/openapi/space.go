@@ -21,6 +21,7 @@ import (
 	"github.com/harness/gitness/app/api/controller/space"
 	"github.com/harness/gitness/app/api/request"
 	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/job"
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
 
@@ -55,6 +56,13 @@ type exportSpaceRequest struct {
 	space.ExportInput
 }
 
+type importArchiveSpaceRequest struct {
+	ParentRef   string `query:"parent_ref"`
+	Identifier  string `query:"identifier"`
+	Description string `query:"description"`
+	IsPublic    bool   `query:"is_public"`
+}
+
 type restoreSpaceRequest struct {
 	spaceRequest
 	space.RestoreInput
@@ -226,6 +234,21 @@ func spaceOperations(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&opImport, new(usererror.Error), http.StatusForbidden)
 	_ = reflector.Spec.AddOperation(http.MethodPost, "/spaces/import", opImport)
 
+	opImportArchive := openapi3.Operation{}
+	opImportArchive.WithTags("space")
+	opImportArchive.WithMapOfAnything(map[string]interface{}{"operationId": "importArchiveSpace"})
+	opImportArchive.WithDescription("Creates a new space and imports the space archive into it in the background. " +
+		"The progress of the import is returned by import-archive-progress of the new space. " +
+		"If the import fails, the imported data is removed and the space is left empty.")
+	_ = reflector.SetRequest(&opImportArchive, new(importArchiveSpaceRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&opImportArchive, new(space.SpaceOutput), http.StatusCreated)
+	_ = reflector.SetJSONResponse(&opImportArchive, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opImportArchive, new(usererror.Error), http.StatusRequestEntityTooLarge)
+	_ = reflector.SetJSONResponse(&opImportArchive, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opImportArchive, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opImportArchive, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/spaces/import-archive", opImportArchive)
+
 	opImportRepositories := openapi3.Operation{}
 	opImportRepositories.WithTags("space")
 	opImportRepositories.WithMapOfAnything(map[string]interface{}{"operationId": "importSpaceRepositories"})
@@ -259,6 +282,32 @@ func spaceOperations(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&opExportProgress, new(usererror.Error), http.StatusForbidden)
 	_ = reflector.Spec.AddOperation(http.MethodGet, "/spaces/{space_ref}/export-progress", opExportProgress)
 
+	opExportArchive := openapi3.Operation{}
+	opExportArchive.WithTags("space")
+	opExportArchive.WithMapOfAnything(map[string]interface{}{"operationId": "exportArchiveSpace"})
+	opExportArchive.WithDescription("Returns an archive of the space and its repositories. " +
+		"Rules other than repository branch rules, space webhooks, webhook secrets and rule bypass user groups " +
+		"aren't exported, they're listed in the skipped field of space.json in the archive.")
+	_ = reflector.SetRequest(&opExportArchive, new(spaceRequest), http.MethodGet)
+	_ = reflector.SetStringResponse(&opExportArchive, http.StatusOK, "application/gzip")
+	_ = reflector.SetJSONResponse(&opExportArchive, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opExportArchive, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opExportArchive, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opExportArchive, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/spaces/{space_ref}/export-archive", opExportArchive)
+
+	opImportArchiveProgress := openapi3.Operation{}
+	opImportArchiveProgress.WithTags("space")
+	opImportArchiveProgress.WithMapOfAnything(map[string]interface{}{"operationId": "importArchiveProgressSpace"})
+	_ = reflector.SetRequest(&opImportArchiveProgress, new(spaceRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&opImportArchiveProgress, new(job.Progress), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opImportArchiveProgress, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.SetJSONResponse(&opImportArchiveProgress, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opImportArchiveProgress, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opImportArchiveProgress, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/spaces/{space_ref}/import-archive-progress",
+		opImportArchiveProgress)
+
 	opGet := openapi3.Operation{}
 	opGet.WithTags("space")
 	opGet.WithMapOfAnything(map[string]interface{}{"operationId": "getSpace"})
@@ -17,6 +17,7 @@ package openapi
 import (
 	"net/http"
 
+	controllersystem "github.com/harness/gitness/app/api/controller/system"
 	"github.com/harness/gitness/app/api/handler/system"
 	"github.com/harness/gitness/app/api/usererror"
 
@@ -34,4 +35,23 @@ func buildSystem(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&opGetConfig, new(usererror.Error), http.StatusInternalServerError)
 	_ = reflector.SetJSONResponse(&opGetConfig, new(usererror.Error), http.StatusBadRequest)
 	_ = reflector.Spec.AddOperation(http.MethodGet, "/system/config", opGetConfig)
+
+	opGetSettings := openapi3.Operation{}
+	opGetSettings.WithTags("admin")
+	opGetSettings.WithMapOfAnything(map[string]interface{}{"operationId": "adminGetSystemSettings"})
+	_ = reflector.SetRequest(&opGetSettings, nil, http.MethodGet)
+	_ = reflector.SetJSONResponse(&opGetSettings, new(controllersystem.SettingsOutput), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opGetSettings, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opGetSettings, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/admin/settings", opGetSettings)
+
+	opUpdateSettings := openapi3.Operation{}
+	opUpdateSettings.WithTags("admin")
+	opUpdateSettings.WithMapOfAnything(map[string]interface{}{"operationId": "adminUpdateSystemSettings"})
+	_ = reflector.SetRequest(&opUpdateSettings, new(controllersystem.UpdateSettingsInput), http.MethodPatch)
+	_ = reflector.SetJSONResponse(&opUpdateSettings, new(controllersystem.SettingsOutput), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opUpdateSettings, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opUpdateSettings, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opUpdateSettings, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodPatch, "/admin/settings", opUpdateSettings)
 }
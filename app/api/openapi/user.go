@@ -31,6 +31,20 @@ type tokensRequest struct {
 	Identifier string `path:"token_identifier"`
 }
 
+type chatIdentityRequest struct {
+	Provider enum.ChatProvider `path:"chat_provider"`
+}
+
+type linkChatIdentityRequest struct {
+	chatIdentityRequest
+	user.LinkChatIdentityInput
+}
+
+type confirmChatIdentityRequest struct {
+	chatIdentityRequest
+	user.ConfirmChatIdentityInput
+}
+
 var queryParameterMembershipSpaces = openapi3.ParameterOrRef{
 	Parameter: &openapi3.Parameter{
 		Name:        request.QueryParamQuery,
@@ -183,4 +197,96 @@ func buildUser(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&opDeleteToken, new(usererror.Error), http.StatusForbidden)
 	_ = reflector.SetJSONResponse(&opDeleteToken, new(usererror.Error), http.StatusInternalServerError)
 	_ = reflector.Spec.AddOperation(http.MethodDelete, "/user/tokens/{token_identifier}", opDeleteToken)
+
+	opTwoFactorStatus := openapi3.Operation{}
+	opTwoFactorStatus.WithTags("user")
+	opTwoFactorStatus.WithMapOfAnything(map[string]interface{}{"operationId": "getTwoFactorStatus"})
+	_ = reflector.SetRequest(&opTwoFactorStatus, nil, http.MethodGet)
+	_ = reflector.SetJSONResponse(&opTwoFactorStatus, new(types.TwoFactorStatus), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opTwoFactorStatus, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opTwoFactorStatus, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/user/2fa", opTwoFactorStatus)
+
+	opEnrollTOTP := openapi3.Operation{}
+	opEnrollTOTP.WithTags("user")
+	opEnrollTOTP.WithMapOfAnything(map[string]interface{}{"operationId": "enrollTOTP"})
+	_ = reflector.SetRequest(&opEnrollTOTP, nil, http.MethodPost)
+	_ = reflector.SetJSONResponse(&opEnrollTOTP, new(types.TOTPEnrollment), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opEnrollTOTP, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opEnrollTOTP, new(usererror.Error), http.StatusConflict)
+	_ = reflector.SetJSONResponse(&opEnrollTOTP, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/user/2fa/totp", opEnrollTOTP)
+
+	opEnableTOTP := openapi3.Operation{}
+	opEnableTOTP.WithTags("user")
+	opEnableTOTP.WithMapOfAnything(map[string]interface{}{"operationId": "enableTOTP"})
+	_ = reflector.SetRequest(&opEnableTOTP, new(user.TOTPCodeInput), http.MethodPost)
+	_ = reflector.SetJSONResponse(&opEnableTOTP, new(types.TwoFactorRecoveryCodes), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opEnableTOTP, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opEnableTOTP, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opEnableTOTP, new(usererror.Error), http.StatusConflict)
+	_ = reflector.SetJSONResponse(&opEnableTOTP, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/user/2fa/totp/enable", opEnableTOTP)
+
+	opDisableTOTP := openapi3.Operation{}
+	opDisableTOTP.WithTags("user")
+	opDisableTOTP.WithMapOfAnything(map[string]interface{}{"operationId": "disableTOTP"})
+	_ = reflector.SetRequest(&opDisableTOTP, new(user.TOTPCodeInput), http.MethodPost)
+	_ = reflector.SetJSONResponse(&opDisableTOTP, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&opDisableTOTP, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opDisableTOTP, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opDisableTOTP, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opDisableTOTP, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/user/2fa/totp/disable", opDisableTOTP)
+
+	opRegenerateRecoveryCodes := openapi3.Operation{}
+	opRegenerateRecoveryCodes.WithTags("user")
+	opRegenerateRecoveryCodes.WithMapOfAnything(map[string]interface{}{"operationId": "regenerateRecoveryCodes"})
+	_ = reflector.SetRequest(&opRegenerateRecoveryCodes, new(user.TOTPCodeInput), http.MethodPost)
+	_ = reflector.SetJSONResponse(&opRegenerateRecoveryCodes, new(types.TwoFactorRecoveryCodes), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opRegenerateRecoveryCodes, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opRegenerateRecoveryCodes, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opRegenerateRecoveryCodes, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/user/2fa/recovery-codes", opRegenerateRecoveryCodes)
+
+	opListChatIdentities := openapi3.Operation{}
+	opListChatIdentities.WithTags("user")
+	opListChatIdentities.WithMapOfAnything(map[string]interface{}{"operationId": "listChatIdentities"})
+	_ = reflector.SetRequest(&opListChatIdentities, nil, http.MethodGet)
+	_ = reflector.SetJSONResponse(&opListChatIdentities, new([]types.UserChatIdentity), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opListChatIdentities, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opListChatIdentities, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/user/chat-identities", opListChatIdentities)
+
+	opLinkChatIdentity := openapi3.Operation{}
+	opLinkChatIdentity.WithTags("user")
+	opLinkChatIdentity.WithMapOfAnything(map[string]interface{}{"operationId": "linkChatIdentity"})
+	_ = reflector.SetRequest(&opLinkChatIdentity, new(linkChatIdentityRequest), http.MethodPut)
+	_ = reflector.SetJSONResponse(&opLinkChatIdentity, new(types.UserChatIdentity), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opLinkChatIdentity, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opLinkChatIdentity, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opLinkChatIdentity, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodPut, "/user/chat-identities/{chat_provider}", opLinkChatIdentity)
+
+	opConfirmChatIdentity := openapi3.Operation{}
+	opConfirmChatIdentity.WithTags("user")
+	opConfirmChatIdentity.WithMapOfAnything(map[string]interface{}{"operationId": "confirmChatIdentity"})
+	_ = reflector.SetRequest(&opConfirmChatIdentity, new(confirmChatIdentityRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&opConfirmChatIdentity, new(types.UserChatIdentity), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opConfirmChatIdentity, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opConfirmChatIdentity, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opConfirmChatIdentity, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.SetJSONResponse(&opConfirmChatIdentity, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodPost,
+		"/user/chat-identities/{chat_provider}/confirm", opConfirmChatIdentity)
+
+	opUnlinkChatIdentity := openapi3.Operation{}
+	opUnlinkChatIdentity.WithTags("user")
+	opUnlinkChatIdentity.WithMapOfAnything(map[string]interface{}{"operationId": "unlinkChatIdentity"})
+	_ = reflector.SetRequest(&opUnlinkChatIdentity, new(chatIdentityRequest), http.MethodDelete)
+	_ = reflector.SetJSONResponse(&opUnlinkChatIdentity, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&opUnlinkChatIdentity, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opUnlinkChatIdentity, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opUnlinkChatIdentity, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodDelete, "/user/chat-identities/{chat_provider}", opUnlinkChatIdentity)
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/harness/gitness/app/api/controller/user"
 	"github.com/harness/gitness/app/api/usererror"
 	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
 
 	"github.com/swaggest/openapi-go/openapi3"
 )
@@ -55,6 +56,24 @@ type (
 		adminUsersRequest
 		user.UpdateAdminInput
 	}
+
+	// linkLDAPIdentityRequest is the request for linking the user to a directory user.
+	linkLDAPIdentityRequest struct {
+		adminUsersRequest
+		user.LinkLDAPIdentityInput
+	}
+
+	// adminChatIdentityRequest is the request for user specific admin chat identity operations.
+	adminChatIdentityRequest struct {
+		adminUsersRequest
+		Provider enum.ChatProvider `path:"chat_provider"`
+	}
+
+	// adminLinkChatIdentityRequest is the request for linking the user to an account on a chat service.
+	adminLinkChatIdentityRequest struct {
+		adminChatIdentityRequest
+		user.LinkChatIdentityInput
+	}
 )
 
 // helper function that constructs the openapi specification
@@ -117,4 +136,55 @@ func buildAdmin(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&opDelete, new(usererror.Error), http.StatusInternalServerError)
 	_ = reflector.SetJSONResponse(&opDelete, new(usererror.Error), http.StatusNotFound)
 	_ = reflector.Spec.AddOperation(http.MethodDelete, "/admin/users/{user_uid}", opDelete)
+
+	opResetTwoFactor := openapi3.Operation{}
+	opResetTwoFactor.WithTags("admin")
+	opResetTwoFactor.WithMapOfAnything(map[string]interface{}{"operationId": "adminResetUserTwoFactor"})
+	_ = reflector.SetRequest(&opResetTwoFactor, new(adminUsersRequest), http.MethodDelete)
+	_ = reflector.SetJSONResponse(&opResetTwoFactor, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&opResetTwoFactor, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opResetTwoFactor, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodDelete, "/admin/users/{user_uid}/2fa", opResetTwoFactor)
+
+	opLinkLDAPIdentity := openapi3.Operation{}
+	opLinkLDAPIdentity.WithTags("admin")
+	opLinkLDAPIdentity.WithMapOfAnything(map[string]interface{}{"operationId": "adminLinkUserLDAPIdentity"})
+	_ = reflector.SetRequest(&opLinkLDAPIdentity, new(linkLDAPIdentityRequest), http.MethodPut)
+	_ = reflector.SetJSONResponse(&opLinkLDAPIdentity, new(types.LDAPIdentity), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opLinkLDAPIdentity, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opLinkLDAPIdentity, new(usererror.Error), http.StatusConflict)
+	_ = reflector.SetJSONResponse(&opLinkLDAPIdentity, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opLinkLDAPIdentity, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPut, "/admin/users/{user_uid}/ldap-identity", opLinkLDAPIdentity)
+
+	opUnlinkLDAPIdentity := openapi3.Operation{}
+	opUnlinkLDAPIdentity.WithTags("admin")
+	opUnlinkLDAPIdentity.WithMapOfAnything(map[string]interface{}{"operationId": "adminUnlinkUserLDAPIdentity"})
+	_ = reflector.SetRequest(&opUnlinkLDAPIdentity, new(adminUsersRequest), http.MethodDelete)
+	_ = reflector.SetJSONResponse(&opUnlinkLDAPIdentity, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&opUnlinkLDAPIdentity, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opUnlinkLDAPIdentity, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodDelete, "/admin/users/{user_uid}/ldap-identity", opUnlinkLDAPIdentity)
+
+	opLinkChatIdentity := openapi3.Operation{}
+	opLinkChatIdentity.WithTags("admin")
+	opLinkChatIdentity.WithMapOfAnything(map[string]interface{}{"operationId": "adminLinkUserChatIdentity"})
+	_ = reflector.SetRequest(&opLinkChatIdentity, new(adminLinkChatIdentityRequest), http.MethodPut)
+	_ = reflector.SetJSONResponse(&opLinkChatIdentity, new(types.UserChatIdentity), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opLinkChatIdentity, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opLinkChatIdentity, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opLinkChatIdentity, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPut,
+		"/admin/users/{user_uid}/chat-identities/{chat_provider}", opLinkChatIdentity)
+
+	opUnlinkChatIdentity := openapi3.Operation{}
+	opUnlinkChatIdentity.WithTags("admin")
+	opUnlinkChatIdentity.WithMapOfAnything(map[string]interface{}{"operationId": "adminUnlinkUserChatIdentity"})
+	_ = reflector.SetRequest(&opUnlinkChatIdentity, new(adminChatIdentityRequest), http.MethodDelete)
+	_ = reflector.SetJSONResponse(&opUnlinkChatIdentity, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&opUnlinkChatIdentity, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opUnlinkChatIdentity, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opUnlinkChatIdentity, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodDelete,
+		"/admin/users/{user_uid}/chat-identities/{chat_provider}", opUnlinkChatIdentity)
 }
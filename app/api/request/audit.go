@@ -0,0 +1,109 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package request
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/audit"
+)
+
+const (
+	QueryParamResourceType = "resource_type"
+	QueryParamAction       = "action"
+	QueryParamPrincipalID  = "principal_id"
+)
+
+// ParseAuditEventFilter extracts the audit event filter from the url.
+func ParseAuditEventFilter(r *http.Request) (*audit.EventFilter, error) {
+	principalIDs, err := QueryParamListAsPositiveInt64(r, QueryParamPrincipalID)
+	if err != nil {
+		return nil, fmt.Errorf("encountered error parsing principal ID filter: %w", err)
+	}
+
+	from, _, err := QueryParamAsPositiveInt64(r, QueryParamStartTime)
+	if err != nil {
+		return nil, fmt.Errorf("encountered error parsing start time filter: %w", err)
+	}
+
+	to, _, err := QueryParamAsPositiveInt64(r, QueryParamEndTime)
+	if err != nil {
+		return nil, fmt.Errorf("encountered error parsing end time filter: %w", err)
+	}
+
+	resourceTypes, err := parseAuditResourceTypes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	actions, err := parseAuditActions(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &audit.EventFilter{
+		Page:          ParsePage(r),
+		Size:          ParseLimit(r),
+		ResourceTypes: resourceTypes,
+		Actions:       actions,
+		PrincipalIDs:  principalIDs,
+		From:          from,
+		To:            to,
+	}, nil
+}
+
+// parseAuditResourceTypes extracts the audit resource types from the url.
+// Unknown resource types are rejected as they would otherwise silently widen the filter.
+func parseAuditResourceTypes(r *http.Request) ([]audit.ResourceType, error) {
+	strTypes, _ := QueryParamList(r, QueryParamResourceType)
+	m := make(map[audit.ResourceType]struct{}) // use map to eliminate duplicates
+	for _, s := range strTypes {
+		t := audit.ResourceType(s)
+		if err := t.Validate(); err != nil {
+			return nil, usererror.BadRequestf("Invalid value for the %s query parameter: %q.", QueryParamResourceType, s)
+		}
+		m[t] = struct{}{}
+	}
+
+	resourceTypes := make([]audit.ResourceType, 0, len(m))
+	for t := range m {
+		resourceTypes = append(resourceTypes, t)
+	}
+
+	return resourceTypes, nil
+}
+
+// parseAuditActions extracts the audit actions from the url.
+// Unknown actions are rejected as they would otherwise silently widen the filter.
+func parseAuditActions(r *http.Request) ([]audit.Action, error) {
+	strActions, _ := QueryParamList(r, QueryParamAction)
+	m := make(map[audit.Action]struct{}) // use map to eliminate duplicates
+	for _, s := range strActions {
+		a := audit.Action(s)
+		if err := a.Validate(); err != nil {
+			return nil, usererror.BadRequestf("Invalid value for the %s query parameter: %q.", QueryParamAction, s)
+		}
+		m[a] = struct{}{}
+	}
+
+	actions := make([]audit.Action, 0, len(m))
+	for a := range m {
+		actions = append(actions, a)
+	}
+
+	return actions, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package request
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"testing"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/audit"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseAuditEventFilter(t *testing.T) {
+	tests := []struct {
+		name              string
+		query             string
+		wantResourceTypes []audit.ResourceType
+		wantActions       []audit.Action
+		wantErr           bool
+	}{
+		{
+			name:              "no filter",
+			query:             "",
+			wantResourceTypes: []audit.ResourceType{},
+			wantActions:       []audit.Action{},
+		},
+		{
+			name:              "valid values with duplicates",
+			query:             "resource_type=repository&resource_type=repository&action=created&action=deleted",
+			wantResourceTypes: []audit.ResourceType{audit.ResourceTypeRepository},
+			wantActions:       []audit.Action{audit.ActionCreated, audit.ActionDeleted},
+		},
+		{
+			name:    "unknown resource type",
+			query:   "resource_type=repository&resource_type=unknown",
+			wantErr: true,
+		},
+		{
+			name:    "unknown action",
+			query:   "action=created&action=unknown",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &http.Request{URL: &url.URL{Path: "/audit-events", RawQuery: test.query}}
+
+			filter, err := ParseAuditEventFilter(r)
+			if test.wantErr {
+				var userErr *usererror.Error
+				if !errors.As(err, &userErr) || userErr.Status != http.StatusBadRequest {
+					t.Fatalf("expected bad request error, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			sort.Slice(filter.Actions, func(i, j int) bool { return filter.Actions[i] < filter.Actions[j] })
+
+			if diff := cmp.Diff(test.wantResourceTypes, filter.ResourceTypes); diff != "" {
+				t.Errorf("resource types mismatch: %s", diff)
+			}
+			if diff := cmp.Diff(test.wantActions, filter.Actions); diff != "" {
+				t.Errorf("actions mismatch: %s", diff)
+			}
+		})
+	}
+}
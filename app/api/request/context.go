@@ -33,6 +33,7 @@ const (
 	spaceKey
 	repoKey
 	requestIDKey
+	runnerKey
 )
 
 // WithAuthSession returns a copy of parent in which the principal
@@ -119,3 +120,15 @@ func RequestIDFrom(ctx context.Context) (string, bool) {
 	v, ok := ctx.Value(requestIDKey).(string)
 	return v, ok && v != ""
 }
+
+// WithRunner returns a copy of parent in which the remote runner value is set.
+func WithRunner(parent context.Context, v *types.Runner) context.Context {
+	return context.WithValue(parent, runnerKey, v)
+}
+
+// RunnerFrom returns the value of the remote runner key on the
+// context - ok is true iff a non-nil value existed.
+func RunnerFrom(ctx context.Context) (*types.Runner, bool) {
+	v, ok := ctx.Value(runnerKey).(*types.Runner)
+	return v, ok && v != nil
+}
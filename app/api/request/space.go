@@ -26,6 +26,11 @@ const (
 	PathParamSpaceRef = "space_ref"
 
 	QueryParamIncludeSubspaces = "include_subspaces"
+
+	QueryParamParentRef   = "parent_ref"
+	QueryParamIdentifier  = "identifier"
+	QueryParamDescription = "description"
+	QueryParamIsPublic    = "is_public"
 )
 
 func GetSpaceRefFromPath(r *http.Request) (string, error) {
@@ -87,3 +92,12 @@ func GetIncludeSubspacesFromQuery(r *http.Request) (bool, error) {
 
 	return v, nil
 }
+
+func GetIsPublicFromQuery(r *http.Request) (bool, error) {
+	v, err := QueryParamAsBoolOrDefault(r, QueryParamIsPublic, false)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse is public parameter: %w", err)
+	}
+
+	return v, nil
+}
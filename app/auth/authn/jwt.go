@@ -85,6 +85,10 @@ func (a *JWTAuthenticator) Authenticate(r *http.Request) (*auth.Session, error)
 
 	var metadata auth.Metadata
 	switch {
+	case claims.TwoFactor != nil:
+		return nil, errors.New("jwt of a two-factor challenge can't be used for authentication")
+	case claims.AccountLink != nil:
+		return nil, errors.New("jwt of an account link can't be used for authentication")
 	case claims.Token != nil:
 		metadata, err = a.metadataFromTokenClaims(ctx, principal, claims.Token)
 		if err != nil {
@@ -126,6 +130,7 @@ func (a *JWTAuthenticator) metadataFromTokenClaims(
 	return &auth.TokenMetadata{
 		TokenType: tkn.Type,
 		TokenID:   tkn.ID,
+		Scope:     tkn.Scope,
 	}, nil
 }
 
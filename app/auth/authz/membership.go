@@ -21,6 +21,7 @@ import (
 	"github.com/harness/gitness/app/auth"
 	"github.com/harness/gitness/app/paths"
 	"github.com/harness/gitness/app/services/publicaccess"
+	"github.com/harness/gitness/app/services/refcache"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
@@ -34,17 +35,20 @@ var _ Authorizer = (*MembershipAuthorizer)(nil)
 type MembershipAuthorizer struct {
 	permissionCache PermissionCache
 	spaceStore      store.SpaceStore
+	repoFinder      refcache.RepoFinder
 	publicAccess    publicaccess.Service
 }
 
 func NewMembershipAuthorizer(
 	permissionCache PermissionCache,
 	spaceStore store.SpaceStore,
+	repoFinder refcache.RepoFinder,
 	publicAccess publicaccess.Service,
 ) *MembershipAuthorizer {
 	return &MembershipAuthorizer{
 		permissionCache: permissionCache,
 		spaceStore:      spaceStore,
+		repoFinder:      repoFinder,
 		publicAccess:    publicAccess,
 	}
 }
@@ -76,6 +80,20 @@ func (a *MembershipAuthorizer) Check(
 		session.Metadata,
 	)
 
+	// the scope of a token restricts access of any principal, including system admins.
+	tokenMetadata, isScopedToken := session.Metadata.(*auth.TokenMetadata)
+	isScopedToken = isScopedToken && tokenMetadata.Scope != nil
+	if isScopedToken {
+		inScope, err := a.checkTokenScope(ctx, tokenMetadata, scope, resource, permission)
+		if err != nil {
+			return false, fmt.Errorf("failed to check token scope: %w", err)
+		}
+
+		if !inScope {
+			return false, nil
+		}
+	}
+
 	if session.Principal.Admin {
 		return true, nil // system admin can call any API
 	}
@@ -148,7 +166,7 @@ func (a *MembershipAuthorizer) Check(
 	}
 
 	// ensure we aren't bypassing unknown metadata with impact on authorization
-	if session.Metadata != nil && session.Metadata.ImpactsAuthorization() {
+	if !isScopedToken && session.Metadata != nil && session.Metadata.ImpactsAuthorization() {
 		return false, fmt.Errorf("session contains unknown metadata that impacts authorization: %T", session.Metadata)
 	}
 
@@ -0,0 +1,113 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/paths"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"golang.org/x/exp/slices"
+)
+
+// checkTokenScope checks whether the requested permission is within the scope of the token used by the session.
+// The scope only restricts access further, access still has to be granted by the principal's memberships.
+func (a *MembershipAuthorizer) checkTokenScope(
+	ctx context.Context,
+	tokenMetadata *auth.TokenMetadata,
+	scope *types.Scope,
+	resource *types.Resource,
+	permission enum.Permission,
+) (bool, error) {
+	tokenScope := tokenMetadata.Scope
+
+	if len(tokenScope.Permissions) > 0 && !slices.Contains(tokenScope.Permissions, permission) {
+		return false, nil
+	}
+
+	// operations on users aren't bound to spaces or repositories, they have to be granted explicitly.
+	if resource.Type == enum.ResourceTypeUser {
+		return slices.Contains(tokenScope.Permissions, permission), nil
+	}
+
+	if len(tokenScope.SpaceIDs) == 0 && len(tokenScope.RepoIDs) == 0 {
+		return true, nil
+	}
+
+	requestedSpacePath := scope.SpacePath
+	if resource.Type == enum.ResourceTypeSpace {
+		requestedSpacePath = paths.Concatenate(scope.SpacePath, resource.Identifier)
+	}
+
+	for _, spaceID := range tokenScope.SpaceIDs {
+		space, err := a.spaceStore.Find(ctx, spaceID)
+		if errors.Is(err, gitness_store.ErrResourceNotFound) {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to find space of token scope: %w", err)
+		}
+
+		if isSpaceOrSubspace(space.Path, requestedSpacePath) {
+			return true, nil
+		}
+	}
+
+	if len(tokenScope.RepoIDs) == 0 {
+		return false, nil
+	}
+
+	var repoIdentifier string
+	switch {
+	case resource.Type == enum.ResourceTypeRepo:
+		repoIdentifier = resource.Identifier
+	case scope.Repo != "":
+		repoIdentifier = scope.Repo
+	}
+
+	// the request isn't targeting a repository (e.g. creation of a repository in a space).
+	if repoIdentifier == "" {
+		return false, nil
+	}
+
+	repo, err := a.repoFinder.FindByRef(ctx, paths.Concatenate(scope.SpacePath, repoIdentifier))
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to find repository: %w", err)
+	}
+
+	return slices.Contains(tokenScope.RepoIDs, repo.ID), nil
+}
+
+// isSpaceOrSubspace returns true if the path is the space path or a path of one of its subspaces.
+// Unlike paths.IsAncesterOf it doesn't match spaces with the same identifier in other spaces.
+func isSpaceOrSubspace(spacePath string, path string) bool {
+	spacePath = strings.Trim(spacePath, types.PathSeparatorAsString)
+	path = strings.Trim(path, types.PathSeparatorAsString)
+
+	return strings.HasPrefix(
+		strings.ToLower(path)+types.PathSeparatorAsString,
+		strings.ToLower(spacePath)+types.PathSeparatorAsString,
+	)
+}
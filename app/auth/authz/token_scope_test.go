@@ -0,0 +1,262 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/paths"
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+var (
+	testSpaces = []*types.Space{
+		{ID: 1, Path: "acme"},
+		{ID: 2, Path: "acme/sub"},
+		{ID: 3, Path: "other"},
+		{ID: 4, Path: "sub"},
+	}
+	testRepos = []*types.Repository{
+		{ID: 10, ParentID: 2, Identifier: "repo", Path: "acme/sub/repo"},
+		{ID: 11, ParentID: 3, Identifier: "repo", Path: "other/repo"},
+	}
+)
+
+type fakeSpaceStore struct {
+	store.SpaceStore
+}
+
+func (fakeSpaceStore) Find(_ context.Context, id int64) (*types.Space, error) {
+	for _, space := range testSpaces {
+		if space.ID == id {
+			return space, nil
+		}
+	}
+	return nil, gitness_store.ErrResourceNotFound
+}
+
+type fakeSpaceCache struct{}
+
+func (fakeSpaceCache) Stats() (int64, int64) {
+	return 0, 0
+}
+
+func (fakeSpaceCache) Get(_ context.Context, path string) (*types.Space, error) {
+	for _, space := range testSpaces {
+		if space.Path == path {
+			return space, nil
+		}
+	}
+	return nil, gitness_store.ErrResourceNotFound
+}
+
+type fakeRepoStore struct {
+	store.RepoStore
+}
+
+func (fakeRepoStore) FindActiveByUID(_ context.Context, parentID int64, identifier string) (*types.Repository, error) {
+	for _, repo := range testRepos {
+		if repo.ParentID == parentID && repo.Identifier == identifier {
+			dup := *repo
+			return &dup, nil
+		}
+	}
+	return nil, gitness_store.ErrResourceNotFound
+}
+
+type fakePublicAccess struct{}
+
+func (fakePublicAccess) Get(context.Context, enum.PublicResourceType, string) (bool, error) {
+	return false, nil
+}
+
+func (fakePublicAccess) Set(context.Context, enum.PublicResourceType, string, bool) error {
+	return nil
+}
+
+func (fakePublicAccess) Delete(context.Context, enum.PublicResourceType, string) error {
+	return nil
+}
+
+func (fakePublicAccess) IsPublicAccessSupported(context.Context, string) (bool, error) {
+	return true, nil
+}
+
+func TestMembershipAuthorizer_TokenScope(t *testing.T) {
+	repoResource := func(repoPath string) (*types.Scope, *types.Resource) {
+		spacePath, identifier, _ := paths.DisectLeaf(repoPath)
+		return &types.Scope{SpacePath: spacePath}, &types.Resource{Type: enum.ResourceTypeRepo, Identifier: identifier}
+	}
+	spaceResource := func(spacePath string) (*types.Scope, *types.Resource) {
+		parentPath, identifier, _ := paths.DisectLeaf(spacePath)
+		return &types.Scope{SpacePath: parentPath}, &types.Resource{Type: enum.ResourceTypeSpace, Identifier: identifier}
+	}
+	pipelineResource := func(repoPath string) (*types.Scope, *types.Resource) {
+		spacePath, identifier, _ := paths.DisectLeaf(repoPath)
+		return &types.Scope{SpacePath: spacePath, Repo: identifier},
+			&types.Resource{Type: enum.ResourceTypePipeline, Identifier: "pipeline"}
+	}
+	userResource := func() (*types.Scope, *types.Resource) {
+		return &types.Scope{}, &types.Resource{Type: enum.ResourceTypeUser, Identifier: "someone"}
+	}
+
+	tests := []struct {
+		name       string
+		tokenScope *types.TokenScope
+		resource   func() (*types.Scope, *types.Resource)
+		permission enum.Permission
+		want       bool
+	}{
+		{
+			name:       "unscoped",
+			resource:   func() (*types.Scope, *types.Resource) { return repoResource("other/repo") },
+			permission: enum.PermissionRepoPush,
+			want:       true,
+		},
+		{
+			name:       "permission-granted",
+			tokenScope: &types.TokenScope{Permissions: []enum.Permission{enum.PermissionRepoView}},
+			resource:   func() (*types.Scope, *types.Resource) { return repoResource("other/repo") },
+			permission: enum.PermissionRepoView,
+			want:       true,
+		},
+		{
+			name:       "permission-not-granted",
+			tokenScope: &types.TokenScope{Permissions: []enum.Permission{enum.PermissionRepoView}},
+			resource:   func() (*types.Scope, *types.Resource) { return repoResource("other/repo") },
+			permission: enum.PermissionRepoPush,
+		},
+		{
+			name:       "space-scope-repo-in-subspace",
+			tokenScope: &types.TokenScope{SpaceIDs: []int64{1}},
+			resource:   func() (*types.Scope, *types.Resource) { return repoResource("acme/sub/repo") },
+			permission: enum.PermissionRepoPush,
+			want:       true,
+		},
+		{
+			name:       "space-scope-subspace",
+			tokenScope: &types.TokenScope{SpaceIDs: []int64{1}},
+			resource:   func() (*types.Scope, *types.Resource) { return spaceResource("acme/sub") },
+			permission: enum.PermissionSpaceView,
+			want:       true,
+		},
+		{
+			name:       "space-scope-repo-outside",
+			tokenScope: &types.TokenScope{SpaceIDs: []int64{1}},
+			resource:   func() (*types.Scope, *types.Resource) { return repoResource("other/repo") },
+			permission: enum.PermissionRepoView,
+		},
+		{
+			// a top-level space with the same identifier as a subspace mustn't match the subspace.
+			name:       "space-scope-same-identifier-elsewhere",
+			tokenScope: &types.TokenScope{SpaceIDs: []int64{4}},
+			resource:   func() (*types.Scope, *types.Resource) { return repoResource("acme/sub/repo") },
+			permission: enum.PermissionRepoView,
+		},
+		{
+			name:       "space-scope-parent-space",
+			tokenScope: &types.TokenScope{SpaceIDs: []int64{2}},
+			resource:   func() (*types.Scope, *types.Resource) { return spaceResource("acme") },
+			permission: enum.PermissionSpaceView,
+		},
+		{
+			name:       "space-scope-deleted-space",
+			tokenScope: &types.TokenScope{SpaceIDs: []int64{99}},
+			resource:   func() (*types.Scope, *types.Resource) { return repoResource("acme/sub/repo") },
+			permission: enum.PermissionRepoView,
+		},
+		{
+			name:       "repo-scope-repo",
+			tokenScope: &types.TokenScope{RepoIDs: []int64{11}},
+			resource:   func() (*types.Scope, *types.Resource) { return repoResource("other/repo") },
+			permission: enum.PermissionRepoPush,
+			want:       true,
+		},
+		{
+			name:       "repo-scope-resource-in-repo",
+			tokenScope: &types.TokenScope{RepoIDs: []int64{11}},
+			resource:   func() (*types.Scope, *types.Resource) { return pipelineResource("other/repo") },
+			permission: enum.PermissionPipelineView,
+			want:       true,
+		},
+		{
+			name:       "repo-scope-other-repo",
+			tokenScope: &types.TokenScope{RepoIDs: []int64{11}},
+			resource:   func() (*types.Scope, *types.Resource) { return repoResource("acme/sub/repo") },
+			permission: enum.PermissionRepoView,
+		},
+		{
+			name:       "repo-scope-parent-space",
+			tokenScope: &types.TokenScope{RepoIDs: []int64{11}},
+			resource:   func() (*types.Scope, *types.Resource) { return spaceResource("other") },
+			permission: enum.PermissionSpaceView,
+		},
+		{
+			name:       "repo-scope-repo-creation",
+			tokenScope: &types.TokenScope{RepoIDs: []int64{11}},
+			resource: func() (*types.Scope, *types.Resource) {
+				return &types.Scope{SpacePath: "other"}, &types.Resource{Type: enum.ResourceTypeRepo}
+			},
+			permission: enum.PermissionRepoEdit,
+		},
+		{
+			name:       "user-permission-granted",
+			tokenScope: &types.TokenScope{Permissions: []enum.Permission{enum.PermissionUserView}},
+			resource:   userResource,
+			permission: enum.PermissionUserView,
+			want:       true,
+		},
+		{
+			name:       "user-permission-not-granted-explicitly",
+			tokenScope: &types.TokenScope{SpaceIDs: []int64{1}},
+			resource:   userResource,
+			permission: enum.PermissionUserView,
+		},
+	}
+
+	authorizer := NewMembershipAuthorizer(
+		nil,
+		fakeSpaceStore{},
+		refcache.NewRepoFinder(fakeRepoStore{}, fakeSpaceCache{}),
+		fakePublicAccess{},
+	)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// the scope has to restrict even system admins, whose memberships aren't checked.
+			session := &auth.Session{
+				Principal: types.Principal{ID: 1, UID: "admin", Type: enum.PrincipalTypeUser, Admin: true},
+				Metadata:  &auth.TokenMetadata{TokenType: enum.TokenTypePAT, TokenID: 1, Scope: test.tokenScope},
+			}
+
+			scope, resource := test.resource()
+
+			got, err := authorizer.Check(context.Background(), session, scope, resource, test.permission)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got != test.want {
+				t.Errorf("want %t, got %t", test.want, got)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/harness/gitness/app/services/publicaccess"
+	"github.com/harness/gitness/app/services/refcache"
 	"github.com/harness/gitness/app/store"
 
 	"github.com/google/wire"
@@ -32,9 +33,10 @@ var WireSet = wire.NewSet(
 func ProvideAuthorizer(
 	pCache PermissionCache,
 	spaceStore store.SpaceStore,
+	repoFinder refcache.RepoFinder,
 	publicAccess publicaccess.Service,
 ) Authorizer {
-	return NewMembershipAuthorizer(pCache, spaceStore, publicAccess)
+	return NewMembershipAuthorizer(pCache, spaceStore, repoFinder, publicAccess)
 }
 
 func ProvidePermissionCache(
@@ -0,0 +1,297 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/harness/gitness/types"
+
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+const (
+	// userFilterPlaceholder is replaced with the login identifier in the configured user filter.
+	userFilterPlaceholder = "%s"
+
+	// searchPageSize is the page size of the searches that list all users or groups.
+	searchPageSize = 500
+)
+
+var (
+	ErrNotConfigured      = errors.New("LDAP is not configured")
+	ErrInvalidCredentials = errors.New("invalid LDAP credentials")
+)
+
+// User is a user of the directory.
+type User struct {
+	DN          string
+	UID         string
+	Email       string
+	DisplayName string
+}
+
+// Group is a group of the directory.
+type Group struct {
+	DN          string
+	Identifier  string
+	Description string
+
+	// Members holds the raw values of the member attribute, DNs or UIDs of the members.
+	Members []string
+}
+
+// Directory authenticates users against an LDAP directory and lists its users and groups.
+// Every operation uses a new connection.
+type Directory struct {
+	config *types.Config
+}
+
+func NewDirectory(config *types.Config) *Directory {
+	return &Directory{
+		config: config,
+	}
+}
+
+// Enabled returns true if the LDAP authentication is enabled.
+func (d *Directory) Enabled() bool {
+	return d.config.LDAP.Enabled && d.config.LDAP.URL != ""
+}
+
+// AutoProvision returns true if the users of the directory should be created on their first sign-in.
+func (d *Directory) AutoProvision() bool {
+	return d.config.LDAP.AutoProvision
+}
+
+// GroupSyncEnabled returns true if the groups of the directory should be synced as usergroups.
+func (d *Directory) GroupSyncEnabled() bool {
+	return d.Enabled() && d.config.LDAP.GroupSpace != ""
+}
+
+// Authenticate verifies the password of the directory user identified by the login identifier.
+// ErrInvalidCredentials is returned if the user doesn't exist or the password is wrong.
+func (d *Directory) Authenticate(ctx context.Context, login, password string) (*User, error) {
+	if !d.Enabled() {
+		return nil, ErrNotConfigured
+	}
+
+	// a simple bind with an empty password is an anonymous bind, which would always succeed.
+	if login == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, closeConn, err := d.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+
+	result, err := conn.Search(d.searchRequest(
+		d.config.LDAP.UserBaseDN,
+		strings.ReplaceAll(d.config.LDAP.UserFilter, userFilterPlaceholder, goldap.EscapeFilter(login)),
+		d.userAttributes(),
+		2,
+	))
+	if goldap.IsErrorWithCode(err, goldap.LDAPResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("login identifier %q matches multiple directory users", login)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to search user: %w", err)
+	}
+
+	if len(result.Entries) == 0 {
+		return nil, ErrInvalidCredentials
+	}
+	if len(result.Entries) > 1 {
+		return nil, fmt.Errorf("login identifier %q matches multiple directory users", login)
+	}
+
+	entry := result.Entries[0]
+
+	err = conn.Bind(entry.DN, password)
+	if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind as user: %w", err)
+	}
+
+	return d.mapUser(entry), nil
+}
+
+// ListUsers returns all users matched by the configured user filter.
+func (d *Directory) ListUsers(ctx context.Context) ([]*User, error) {
+	if !d.Enabled() {
+		return nil, ErrNotConfigured
+	}
+
+	conn, closeConn, err := d.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+
+	result, err := conn.SearchWithPaging(d.searchRequest(
+		d.config.LDAP.UserBaseDN,
+		strings.ReplaceAll(d.config.LDAP.UserFilter, userFilterPlaceholder, "*"),
+		d.userAttributes(),
+		0,
+	), searchPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	users := make([]*User, len(result.Entries))
+	for i, entry := range result.Entries {
+		users[i] = d.mapUser(entry)
+	}
+
+	return users, nil
+}
+
+// ListGroups returns all groups matched by the configured group filter.
+func (d *Directory) ListGroups(ctx context.Context) ([]*Group, error) {
+	if !d.Enabled() {
+		return nil, ErrNotConfigured
+	}
+
+	conn, closeConn, err := d.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+
+	cfg := d.config.LDAP
+	result, err := conn.SearchWithPaging(d.searchRequest(
+		cfg.GroupBaseDN,
+		cfg.GroupFilter,
+		[]string{
+			cfg.GroupAttributeIdentifier,
+			cfg.GroupAttributeDescription,
+			cfg.GroupAttributeMember,
+		},
+		0,
+	), searchPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search groups: %w", err)
+	}
+
+	groups := make([]*Group, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		identifier := entry.GetEqualFoldAttributeValue(cfg.GroupAttributeIdentifier)
+		if identifier == "" {
+			continue
+		}
+
+		groups = append(groups, &Group{
+			DN:          entry.DN,
+			Identifier:  identifier,
+			Description: entry.GetEqualFoldAttributeValue(cfg.GroupAttributeDescription),
+			Members:     entry.GetEqualFoldAttributeValues(cfg.GroupAttributeMember),
+		})
+	}
+
+	return groups, nil
+}
+
+// connect opens a new connection and binds it with the configured service account.
+// The connection is closed by the returned function or when the context is canceled.
+func (d *Directory) connect(ctx context.Context) (*goldap.Conn, func(), error) {
+	cfg := d.config.LDAP
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // explicitly enabled by the config
+	}
+
+	conn, err := goldap.DialURL(cfg.URL,
+		goldap.DialWithDialer(&net.Dialer{Timeout: cfg.Timeout}),
+		goldap.DialWithTLSConfig(tlsConfig),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to the directory: %w", err)
+	}
+
+	conn.SetTimeout(cfg.Timeout)
+
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	closeConn := func() {
+		stop()
+		_ = conn.Close()
+	}
+
+	if cfg.StartTLS {
+		if err = conn.StartTLS(tlsConfig); err != nil {
+			closeConn()
+			return nil, nil, fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+
+	if cfg.BindDN != "" {
+		if err = conn.Bind(cfg.BindDN, cfg.BindPassword); err != nil {
+			closeConn()
+			return nil, nil, fmt.Errorf("failed to bind with the service account: %w", err)
+		}
+	}
+
+	return conn, closeConn, nil
+}
+
+func (d *Directory) searchRequest(baseDN, filter string, attributes []string, sizeLimit int) *goldap.SearchRequest {
+	return goldap.NewSearchRequest(
+		baseDN,
+		goldap.ScopeWholeSubtree,
+		goldap.NeverDerefAliases,
+		sizeLimit,
+		int(d.config.LDAP.Timeout.Seconds()),
+		false,
+		filter,
+		attributes,
+		nil,
+	)
+}
+
+func (d *Directory) userAttributes() []string {
+	return []string{
+		d.config.LDAP.UserAttributeUID,
+		d.config.LDAP.UserAttributeEmail,
+		d.config.LDAP.UserAttributeName,
+	}
+}
+
+func (d *Directory) mapUser(entry *goldap.Entry) *User {
+	return &User{
+		DN:          entry.DN,
+		UID:         entry.GetEqualFoldAttributeValue(d.config.LDAP.UserAttributeUID),
+		Email:       entry.GetEqualFoldAttributeValue(d.config.LDAP.UserAttributeEmail),
+		DisplayName: entry.GetEqualFoldAttributeValue(d.config.LDAP.UserAttributeName),
+	}
+}
+
+// NormalizeDN returns the DN in a form that can be used for comparison of DNs,
+// it ignores the case and the spaces around the separators.
+func NormalizeDN(dn string) string {
+	rdns := strings.Split(dn, ",")
+	for i, rdn := range rdns {
+		attr, value, _ := strings.Cut(rdn, "=")
+		rdns[i] = strings.TrimSpace(attr) + "=" + strings.TrimSpace(value)
+	}
+	return strings.ToLower(strings.Join(rdns, ","))
+}
@@ -0,0 +1,250 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/types"
+
+	"github.com/dchest/uniuri"
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+// The directory tests run against a real LDAP server and are skipped unless it's configured, e.g.:
+//
+//	docker run --rm -p 389:389 -e LDAP_ORGANISATION=example -e LDAP_DOMAIN=example.org \
+//	    -e LDAP_ADMIN_PASSWORD=admin osixia/openldap:1.5.0
+//
+//	GITNESS_TEST_LDAP_URL=ldap://localhost:389 \
+//	GITNESS_TEST_LDAP_BIND_DN=cn=admin,dc=example,dc=org \
+//	GITNESS_TEST_LDAP_BIND_PASSWORD=admin \
+//	GITNESS_TEST_LDAP_BASE_DN=dc=example,dc=org \
+//	go test ./app/auth/ldap/...
+//
+// The bind DN has to be allowed to add entries, the tests create their entries
+// in a new organizational unit below the base DN and remove it afterwards.
+const (
+	envTestURL          = "GITNESS_TEST_LDAP_URL"
+	envTestBindDN       = "GITNESS_TEST_LDAP_BIND_DN"
+	envTestBindPassword = "GITNESS_TEST_LDAP_BIND_PASSWORD"
+	envTestBaseDN       = "GITNESS_TEST_LDAP_BASE_DN"
+)
+
+type testUser struct {
+	uid      string
+	name     string
+	password string
+}
+
+var testUsers = []testUser{
+	{uid: "jdoe", name: "John Doe", password: "secret"},
+	{uid: "asmith", name: "Alice Smith", password: "password"},
+}
+
+// newTestDirectory seeds the directory of the configured LDAP server with the test users
+// and a group with all of them, and returns a directory that uses the seeded entries.
+func newTestDirectory(t *testing.T) *Directory {
+	t.Helper()
+
+	url := os.Getenv(envTestURL)
+	if url == "" {
+		t.Skipf("%s isn't set, skipping the tests against an LDAP server", envTestURL)
+	}
+
+	config := &types.Config{}
+	config.LDAP.Enabled = true
+	config.LDAP.URL = url
+	config.LDAP.Timeout = 10 * time.Second
+	config.LDAP.BindDN = os.Getenv(envTestBindDN)
+	config.LDAP.BindPassword = os.Getenv(envTestBindPassword)
+
+	conn, err := goldap.DialURL(url)
+	if err != nil {
+		t.Fatalf("failed to connect to the LDAP server: %s", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	if err = conn.Bind(config.LDAP.BindDN, config.LDAP.BindPassword); err != nil {
+		t.Fatalf("failed to bind: %s", err)
+	}
+
+	baseDN := fmt.Sprintf("ou=gitness-test-%s,%s", uniuri.NewLen(8), os.Getenv(envTestBaseDN))
+	usersDN := "ou=users," + baseDN
+	groupsDN := "ou=groups," + baseDN
+
+	var created []string
+	add := func(dn string, attrs map[string][]string) {
+		req := goldap.NewAddRequest(dn, nil)
+		for name, values := range attrs {
+			req.Attribute(name, values)
+		}
+		if err := conn.Add(req); err != nil {
+			t.Fatalf("failed to add %q: %s", dn, err)
+		}
+		created = append(created, dn)
+	}
+
+	t.Cleanup(func() {
+		for i := len(created) - 1; i >= 0; i-- {
+			if err := conn.Del(goldap.NewDelRequest(created[i], nil)); err != nil {
+				t.Logf("failed to delete %q: %s", created[i], err)
+			}
+		}
+	})
+
+	add(baseDN, map[string][]string{"objectClass": {"organizationalUnit"}})
+	add(usersDN, map[string][]string{"objectClass": {"organizationalUnit"}})
+	add(groupsDN, map[string][]string{"objectClass": {"organizationalUnit"}})
+
+	members := make([]string, len(testUsers))
+	for i, u := range testUsers {
+		members[i] = "uid=" + u.uid + "," + usersDN
+		add(members[i], map[string][]string{
+			"objectClass":  {"inetOrgPerson"},
+			"uid":          {u.uid},
+			"cn":           {u.name},
+			"sn":           {u.name},
+			"mail":         {u.uid + "@example.org"},
+			"userPassword": {u.password},
+		})
+	}
+
+	add("cn=developers,"+groupsDN, map[string][]string{
+		"objectClass": {"groupOfNames"},
+		"cn":          {"developers"},
+		"description": {"All developers"},
+		"member":      members,
+	})
+
+	config.LDAP.UserBaseDN = usersDN
+	config.LDAP.UserFilter = "(&(objectClass=inetOrgPerson)(uid=%s))"
+	config.LDAP.UserAttributeUID = "uid"
+	config.LDAP.UserAttributeEmail = "mail"
+	config.LDAP.UserAttributeName = "cn"
+	config.LDAP.GroupBaseDN = groupsDN
+	config.LDAP.GroupFilter = "(objectClass=groupOfNames)"
+	config.LDAP.GroupAttributeIdentifier = "cn"
+	config.LDAP.GroupAttributeDescription = "description"
+	config.LDAP.GroupAttributeMember = "member"
+
+	return NewDirectory(config)
+}
+
+func TestDirectory_Authenticate(t *testing.T) {
+	d := newTestDirectory(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		login    string
+		password string
+		wantErr  error
+		wantUID  string
+	}{
+		{name: "valid", login: "jdoe", password: "secret", wantUID: "jdoe"},
+		{name: "wrong-password", login: "jdoe", password: "wrong", wantErr: ErrInvalidCredentials},
+		{name: "empty-password", login: "jdoe", password: "", wantErr: ErrInvalidCredentials},
+		{name: "unknown-user", login: "nobody", password: "secret", wantErr: ErrInvalidCredentials},
+		{name: "filter-injection", login: "*", password: "secret", wantErr: ErrInvalidCredentials},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			user, err := d.Authenticate(ctx, test.login, test.password)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("want error %v, got %v", test.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if user.UID != test.wantUID || user.Email != "jdoe@example.org" || user.DisplayName != "John Doe" {
+				t.Errorf("unexpected user: %+v", user)
+			}
+		})
+	}
+}
+
+func TestDirectory_ListUsersAndGroups(t *testing.T) {
+	d := newTestDirectory(t)
+	ctx := context.Background()
+
+	users, err := d.ListUsers(ctx)
+	if err != nil {
+		t.Fatalf("failed to list users: %s", err)
+	}
+	if len(users) != len(testUsers) {
+		t.Fatalf("want %d users, got %d", len(testUsers), len(users))
+	}
+
+	groups, err := d.ListGroups(ctx)
+	if err != nil {
+		t.Fatalf("failed to list groups: %s", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("want 1 group, got %d", len(groups))
+	}
+
+	g := groups[0]
+	if g.Identifier != "developers" || g.Description != "All developers" || len(g.Members) != len(users) {
+		t.Fatalf("unexpected group: %+v", g)
+	}
+
+	userDNs := make(map[string]struct{}, len(users))
+	for _, u := range users {
+		userDNs[NormalizeDN(u.DN)] = struct{}{}
+	}
+	for _, member := range g.Members {
+		if _, ok := userDNs[NormalizeDN(member)]; !ok {
+			t.Errorf("member %q doesn't match any user", member)
+		}
+	}
+}
+
+func TestDirectory_NotConfigured(t *testing.T) {
+	d := NewDirectory(&types.Config{})
+
+	if _, err := d.Authenticate(context.Background(), "jdoe", "secret"); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("want error %v, got %v", ErrNotConfigured, err)
+	}
+}
+
+func TestNormalizeDN(t *testing.T) {
+	tests := []struct {
+		dn   string
+		want string
+	}{
+		{dn: "uid=jdoe,ou=users,dc=example,dc=org", want: "uid=jdoe,ou=users,dc=example,dc=org"},
+		{dn: "UID=JDoe, OU=Users, DC=Example, DC=org", want: "uid=jdoe,ou=users,dc=example,dc=org"},
+		{dn: " uid = jdoe ,ou=users", want: "uid=jdoe,ou=users"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.dn, func(t *testing.T) {
+			if got := NormalizeDN(test.dn); got != test.want {
+				t.Errorf("want=%q got=%q", test.want, got)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ package auth
 
 import (
 	"github.com/harness/gitness/app/jwt"
+	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
 )
 
@@ -34,10 +35,12 @@ func (m *EmptyMetadata) ImpactsAuthorization() bool {
 type TokenMetadata struct {
 	TokenType enum.TokenType
 	TokenID   int64
+	// Scope restricts the permissions and resources the token can be used for (nil if unrestricted).
+	Scope *types.TokenScope
 }
 
 func (m *TokenMetadata) ImpactsAuthorization() bool {
-	return false
+	return m.Scope != nil
 }
 
 // MembershipMetadata contains information about an ephemeral membership grant.
@@ -0,0 +1,308 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/harness/gitness/types"
+
+	gojwt "github.com/golang-jwt/jwt"
+	"golang.org/x/oauth2"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+
+	// callbackPath is the path of the callback endpoint relative to the API URL.
+	callbackPath = "/v1/login/oidc/callback"
+
+	httpTimeout = 30 * time.Second
+)
+
+var (
+	ErrNotConfigured = errors.New("OpenID Connect is not configured")
+	ErrInvalidToken  = errors.New("invalid ID token")
+)
+
+// AuthRequest holds the values of an authorization request that have to be kept
+// until the identity provider redirects the user back to the callback endpoint.
+type AuthRequest struct {
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+// Provider is a client of an OpenID Connect identity provider
+// that implements the authorization code flow with PKCE.
+type Provider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	redirectURL  string
+	groupsClaim  string
+
+	httpClient *http.Client
+
+	mx       sync.Mutex
+	metadata *metadata
+	keys     *keySet
+}
+
+// metadata holds the fields of the provider's discovery document.
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func NewProvider(config *types.Config) *Provider {
+	redirectURL := config.OIDC.RedirectURL
+	if redirectURL == "" {
+		redirectURL = strings.TrimRight(config.URL.API, "/") + callbackPath
+	}
+
+	return &Provider{
+		issuer:       strings.TrimRight(config.OIDC.Issuer, "/"),
+		clientID:     config.OIDC.ClientID,
+		clientSecret: config.OIDC.ClientSecret,
+		scopes:       config.OIDC.Scopes,
+		redirectURL:  redirectURL,
+		groupsClaim:  config.OIDC.GroupsClaim,
+		httpClient:   &http.Client{Timeout: httpTimeout},
+	}
+}
+
+// NewAuthRequest generates a new authorization request and returns it along with
+// the URL of the identity provider the user should be redirected to.
+func (p *Provider) NewAuthRequest(ctx context.Context) (*AuthRequest, string, error) {
+	oauthConfig, err := p.oauthConfig(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	state, err := randomString()
+	if err != nil {
+		return nil, "", err
+	}
+
+	nonce, err := randomString()
+	if err != nil {
+		return nil, "", err
+	}
+
+	req := &AuthRequest{
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: oauth2.GenerateVerifier(),
+	}
+
+	authURL := oauthConfig.AuthCodeURL(req.State,
+		oauth2.S256ChallengeOption(req.CodeVerifier),
+		oauth2.SetAuthURLParam("nonce", req.Nonce),
+	)
+
+	return req, authURL, nil
+}
+
+// Exchange exchanges the authorization code for the tokens of the user
+// and returns the claims of the verified ID token.
+func (p *Provider) Exchange(ctx context.Context, req *AuthRequest, code string) (*types.OIDCClaims, error) {
+	oauthConfig, err := p.oauthConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.httpClient)
+
+	token, err := oauthConfig.Exchange(ctx, code, oauth2.VerifierOption(req.CodeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, fmt.Errorf("%w: token response doesn't contain an ID token", ErrInvalidToken)
+	}
+
+	return p.verify(ctx, rawIDToken, req.Nonce)
+}
+
+// verify verifies the signature and the standard claims of the ID token.
+func (p *Provider) verify(ctx context.Context, rawIDToken string, nonce string) (*types.OIDCClaims, error) {
+	claims := gojwt.MapClaims{}
+
+	parser := &gojwt.Parser{ValidMethods: []string{
+		gojwt.SigningMethodRS256.Name,
+		gojwt.SigningMethodRS384.Name,
+		gojwt.SigningMethodRS512.Name,
+		gojwt.SigningMethodES256.Name,
+		gojwt.SigningMethodES384.Name,
+		gojwt.SigningMethodES512.Name,
+	}}
+
+	parsed, err := parser.ParseWithClaims(rawIDToken, claims, func(token *gojwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	if !parsed.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	md, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the issuer claim must match the issuer of the discovery document exactly,
+	// the configured issuer might differ from it in the trailing slash.
+	if !claims.VerifyIssuer(md.Issuer, true) {
+		return nil, fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if !claims.VerifyAudience(p.clientID, true) {
+		return nil, fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, fmt.Errorf("%w: token is expired", ErrInvalidToken)
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidToken)
+	}
+
+	return p.mapClaims(claims)
+}
+
+func (p *Provider) mapClaims(claims gojwt.MapClaims) (*types.OIDCClaims, error) {
+	// round-trip through JSON to decode the standard claims into the struct.
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ID token claims: %w", err)
+	}
+
+	result := &types.OIDCClaims{}
+	if err = json.Unmarshal(raw, result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ID token claims: %w", err)
+	}
+
+	if result.Subject == "" {
+		return nil, fmt.Errorf("%w: subject is missing", ErrInvalidToken)
+	}
+
+	switch groups := claims[p.groupsClaim].(type) {
+	case string:
+		result.Groups = []string{groups}
+	case []interface{}:
+		for _, group := range groups {
+			if s, ok := group.(string); ok {
+				result.Groups = append(result.Groups, s)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (p *Provider) oauthConfig(ctx context.Context) (*oauth2.Config, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.clientID,
+		ClientSecret: p.clientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  md.AuthorizationEndpoint,
+			TokenURL: md.TokenEndpoint,
+		},
+		RedirectURL: p.redirectURL,
+		Scopes:      p.scopes,
+	}, nil
+}
+
+// discover fetches the discovery document of the identity provider.
+// The document is fetched lazily, so the server can start while the identity provider isn't reachable.
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	if p.issuer == "" || p.clientID == "" {
+		return nil, ErrNotConfigured
+	}
+
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	md := &metadata{}
+	if err := p.getJSON(ctx, p.issuer+discoveryPath, md); err != nil {
+		return nil, fmt.Errorf("failed to get discovery document: %w", err)
+	}
+
+	if strings.TrimRight(md.Issuer, "/") != p.issuer {
+		return nil, fmt.Errorf("issuer of the discovery document %q doesn't match the configured issuer", md.Issuer)
+	}
+
+	p.metadata = md
+
+	return md, nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, url string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status code: %d", resp.StatusCode)
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random string: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
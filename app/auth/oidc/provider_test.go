@@ -0,0 +1,206 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/types"
+
+	gojwt "github.com/golang-jwt/jwt"
+)
+
+const (
+	testClientID = "gitness"
+	testKeyID    = "key-1"
+)
+
+// testIdentityProvider is an identity provider that issues the ID token with the claims returned by claims.
+type testIdentityProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims func(issuer string) gojwt.MapClaims
+}
+
+func newTestIdentityProvider(t *testing.T, claims func(issuer string) gojwt.MapClaims) *testIdentityProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+
+	idp := &testIdentityProvider{key: key, claims: claims}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, _ *http.Request) {
+		// the issuer of the discovery document has a trailing slash, like some providers have.
+		writeJSON(w, map[string]string{
+			"issuer":                 idp.issuer(),
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{"keys": []map[string]string{{
+			"kid": testKeyID,
+			"kty": "RSA",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, _ *http.Request) {
+		token := gojwt.NewWithClaims(gojwt.SigningMethodRS256, idp.claims(idp.issuer()))
+		token.Header["kid"] = testKeyID
+
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, map[string]any{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     idToken,
+		})
+	})
+
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+func (idp *testIdentityProvider) issuer() string {
+	return idp.server.URL + "/"
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestProviderExchange(t *testing.T) {
+	const nonce = "nonce"
+
+	validClaims := func(issuer string) gojwt.MapClaims {
+		return gojwt.MapClaims{
+			"iss":            issuer,
+			"sub":            "subject",
+			"aud":            testClientID,
+			"exp":            time.Now().Add(time.Hour).Unix(),
+			"nonce":          nonce,
+			"email":          "user@example.com",
+			"email_verified": true,
+			"groups":         []string{"dev", "ops"},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(claims gojwt.MapClaims)
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			modify: func(gojwt.MapClaims) {},
+		},
+		{
+			name: "issuer-without-trailing-slash",
+			modify: func(claims gojwt.MapClaims) {
+				claims["iss"] = claims["iss"].(string)[:len(claims["iss"].(string))-1]
+			},
+			wantErr: true,
+		},
+		{
+			name:    "other-issuer",
+			modify:  func(claims gojwt.MapClaims) { claims["iss"] = "https://evil.example.com/" },
+			wantErr: true,
+		},
+		{
+			name:    "other-audience",
+			modify:  func(claims gojwt.MapClaims) { claims["aud"] = "other" },
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			modify:  func(claims gojwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Minute).Unix() },
+			wantErr: true,
+		},
+		{
+			name:    "nonce-mismatch",
+			modify:  func(claims gojwt.MapClaims) { claims["nonce"] = "other" },
+			wantErr: true,
+		},
+		{
+			name:    "missing-subject",
+			modify:  func(claims gojwt.MapClaims) { delete(claims, "sub") },
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			idp := newTestIdentityProvider(t, func(issuer string) gojwt.MapClaims {
+				claims := validClaims(issuer)
+				test.modify(claims)
+				return claims
+			})
+
+			config := &types.Config{}
+			config.URL.API = "http://localhost/api"
+			config.OIDC.Issuer = idp.issuer()
+			config.OIDC.ClientID = testClientID
+			config.OIDC.GroupsClaim = "groups"
+
+			provider := NewProvider(config)
+
+			claims, err := provider.Exchange(context.Background(), &AuthRequest{Nonce: nonce}, "code")
+			if test.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("expected invalid token error, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if claims.Issuer != idp.issuer() || claims.Subject != "subject" {
+				t.Errorf("unexpected identity: %s %s", claims.Issuer, claims.Subject)
+			}
+			if !claims.EmailVerified || claims.Email != "user@example.com" {
+				t.Errorf("unexpected email: %s verified=%t", claims.Email, claims.EmailVerified)
+			}
+			if len(claims.Groups) != 2 || claims.Groups[0] != "dev" || claims.Groups[1] != "ops" {
+				t.Errorf("unexpected groups: %v", claims.Groups)
+			}
+		})
+	}
+}
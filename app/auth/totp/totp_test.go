@@ -0,0 +1,103 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 secret of the test vectors of RFC 6238 appendix B.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode_RFC6238(t *testing.T) {
+	// RFC 6238 lists 8 digit codes, the 6 digit codes are their last 6 digits.
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+
+	for _, test := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(test.unix, 0)))
+		if err != nil {
+			t.Fatalf("failed to generate code: %s", err)
+		}
+		if got != test.want {
+			t.Errorf("time=%d: want=%s got=%s", test.unix, test.want, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	step := Step(now)
+
+	current, _ := Code(rfcSecret, step)
+	previous, _ := Code(rfcSecret, step-1)
+	tooOld, _ := Code(rfcSecret, step-2)
+
+	tests := []struct {
+		name         string
+		code         string
+		lastUsedStep int64
+		wantStep     int64
+		wantOK       bool
+	}{
+		{name: "current", code: current, wantStep: step, wantOK: true},
+		{name: "previous", code: previous, wantStep: step - 1, wantOK: true},
+		{name: "too-old", code: tooOld},
+		{name: "reused", code: current, lastUsedStep: step},
+		{name: "older-than-last-used", code: previous, lastUsedStep: step - 1},
+		{name: "wrong-length", code: current[1:]},
+		{name: "wrong", code: "000000"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotStep, ok := Validate(rfcSecret, test.code, now, test.lastUsedStep)
+			if ok != test.wantOK || gotStep != test.wantStep {
+				t.Errorf("want=(%d, %t) got=(%d, %t)", test.wantStep, test.wantOK, gotStep, ok)
+			}
+		})
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("failed to generate secret: %s", err)
+	}
+
+	u, err := url.Parse(ProvisioningURI("Gitness", "jdoe@example.org", secret))
+	if err != nil {
+		t.Fatalf("invalid uri: %s", err)
+	}
+
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/Gitness:jdoe@example.org" {
+		t.Errorf("unexpected uri: %s", u)
+	}
+	if u.Query().Get("secret") != secret || u.Query().Get("issuer") != "Gitness" {
+		t.Errorf("unexpected uri parameters: %s", u.RawQuery)
+	}
+}
@@ -45,6 +45,8 @@ type Claims struct {
 	Token             *SubClaimsToken             `json:"tkn,omitempty"`
 	Membership        *SubClaimsMembership        `json:"ms,omitempty"`
 	AccessPermissions *SubClaimsAccessPermissions `json:"ap,omitempty"`
+	TwoFactor         *SubClaimsTwoFactor         `json:"tfa,omitempty"`
+	AccountLink       *SubClaimsAccountLink       `json:"al,omitempty"`
 }
 
 // SubClaimsToken contains information about the token the JWT was created for.
@@ -65,6 +67,21 @@ type SubClaimsAccessPermissions struct {
 	Permissions []AccessPermissions `json:"permissions,omitempty"`
 }
 
+// SubClaimsTwoFactor marks a two-factor authentication challenge issued after a successful password login.
+type SubClaimsTwoFactor struct {
+	// Enrollment is true if the user has to set up two-factor authentication to complete the login.
+	Enrollment bool `json:"enr,omitempty"`
+	// IssuedAt is the time the challenge was issued in milliseconds, used to revoke challenges once they are used.
+	IssuedAt int64 `json:"iat,omitempty"`
+}
+
+// SubClaimsAccountLink contains the purpose of a link sent to the user by email (e.g. password reset).
+type SubClaimsAccountLink struct {
+	Type enum.UserMailType `json:"typ,omitempty"`
+	// Fingerprint is derived from the user data the link modifies, so the link can only be used once.
+	Fingerprint string `json:"fp,omitempty"`
+}
+
 // AccessPermissions stores allowed actions on a resource.
 type AccessPermissions struct {
 	SpaceID     int64             `json:"sid,omitempty"`
@@ -162,3 +179,68 @@ func GenerateForTokenWithAccessPermissions(
 
 	return res, nil
 }
+
+// GenerateForTwoFactorChallenge generates a jwt for a two-factor authentication challenge.
+// The secret has to differ from the principal's salt, so the challenge can't be used for authentication.
+func GenerateForTwoFactorChallenge(
+	principalID int64,
+	enrollment bool,
+	lifetime time.Duration,
+	secret string,
+) (string, error) {
+	issuedAt := time.Now()
+	expiresAt := issuedAt.Add(lifetime)
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    issuer,
+			IssuedAt:  issuedAt.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+		PrincipalID: principalID,
+		TwoFactor: &SubClaimsTwoFactor{
+			Enrollment: enrollment,
+			IssuedAt:   issuedAt.UnixMilli(),
+		},
+	})
+
+	res, err := jwtToken.SignedString([]byte(secret))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return res, nil
+}
+
+// GenerateForAccountLink generates a jwt for a link sent to the user by email.
+// The secret has to differ from the principal's salt, so the link can't be used for authentication.
+func GenerateForAccountLink(
+	principalID int64,
+	mailType enum.UserMailType,
+	fingerprint string,
+	lifetime time.Duration,
+	secret string,
+) (string, error) {
+	issuedAt := time.Now()
+	expiresAt := issuedAt.Add(lifetime)
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    issuer,
+			IssuedAt:  issuedAt.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+		PrincipalID: principalID,
+		AccountLink: &SubClaimsAccountLink{
+			Type:        mailType,
+			Fingerprint: fingerprint,
+		},
+	})
+
+	res, err := jwtToken.SignedString([]byte(secret))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return res, nil
+}
@@ -152,6 +152,10 @@ func (t *teardown) do(ctx context.Context, stage *types.Stage) error {
 			execution.Status = enum.CIStatusError
 			break
 		}
+		if sibling.Status == enum.CIStatusDeclined {
+			execution.Status = enum.CIStatusDeclined
+			break
+		}
 	}
 	if execution.Started == 0 {
 		execution.Started = execution.Finished
@@ -201,8 +205,8 @@ func (t *teardown) cancelDownstream(
 ) error {
 	failed := false
 	for _, s := range stages {
-		// check pipeline state
-		if s.Status.IsFailed() {
+		// check pipeline state, a declined stage fails its downstream stages too.
+		if s.Status.IsFailed() || s.Status == enum.CIStatusDeclined {
 			failed = true
 		}
 	}
@@ -263,7 +267,6 @@ func isexecutionComplete(stages []*types.Stage) bool {
 		if stage.Status == enum.CIStatusPending ||
 			stage.Status == enum.CIStatusRunning ||
 			stage.Status == enum.CIStatusWaitingOnDeps ||
-			stage.Status == enum.CIStatusDeclined ||
 			stage.Status == enum.CIStatusBlocked {
 			return false
 		}
@@ -322,6 +325,27 @@ func (t *teardown) scheduleDownstream(
 			Str("stage.depends_on", strings.Join(sibling.DependsOn, ",")).
 			Logger()
 
+		// stages requiring a manual approval are blocked until approved or declined.
+		if sibling.ApprovalRequired {
+			log.Debug().Msg("manager: block next stage until approved")
+
+			sibling.Status = enum.CIStatusBlocked
+			err := t.Stages.Update(noContext, sibling)
+			if errors.Is(err, gitness_store.ErrVersionConflict) {
+				rErr := t.resync(ctx, sibling)
+				if rErr != nil {
+					log.Warn().Err(rErr).Msg("failed to resync after version conflict")
+				}
+				continue
+			}
+			if err != nil {
+				log.Error().Err(err).
+					Msg("manager: cannot update stage status")
+				errs = multierror.Append(errs, err)
+			}
+			continue
+		}
+
 		log.Debug().Msg("manager: schedule next stage")
 
 		sibling.Status = enum.CIStatusPending
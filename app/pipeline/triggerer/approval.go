@@ -0,0 +1,88 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggerer
+
+import (
+	"fmt"
+
+	"github.com/drone/drone-yaml/yaml"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// approval is the manual approval requirement a stage can declare in the pipeline YAML:
+//
+//	approval:
+//	  required: true
+//
+// Neither the drone nor the v1 YAML parsers know about it, so it's read from the raw YAML.
+type approval struct {
+	Required bool `yaml:"required"`
+}
+
+// parseDroneApprovals returns the names of the drone YAML pipelines requiring a manual approval.
+func parseDroneApprovals(data []byte) (map[string]bool, error) {
+	resources, err := yaml.ParseRawBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse raw yaml: %w", err)
+	}
+
+	approvals := map[string]bool{}
+	for _, resource := range resources {
+		if resource.Kind != yaml.KindPipeline {
+			continue
+		}
+
+		in := struct {
+			Name     string   `yaml:"name"`
+			Approval approval `yaml:"approval"`
+		}{}
+		if err = yamlv3.Unmarshal(resource.Data, &in); err != nil {
+			return nil, fmt.Errorf("failed to parse pipeline approval: %w", err)
+		}
+
+		name := in.Name
+		if name == "" {
+			name = "default"
+		}
+		if in.Approval.Required {
+			approvals[name] = true
+		}
+	}
+
+	return approvals, nil
+}
+
+// parseV1Approvals returns the indexes of the v1 YAML stages requiring a manual approval.
+func parseV1Approvals(data []byte) (map[int]bool, error) {
+	in := struct {
+		Spec struct {
+			Stages []struct {
+				Approval approval `yaml:"approval"`
+			} `yaml:"stages"`
+		} `yaml:"spec"`
+	}{}
+	if err := yamlv3.Unmarshal(data, &in); err != nil {
+		return nil, fmt.Errorf("failed to parse stage approvals: %w", err)
+	}
+
+	approvals := map[int]bool{}
+	for idx, stage := range in.Spec.Stages {
+		if stage.Approval.Required {
+			approvals[idx] = true
+		}
+	}
+
+	return approvals, nil
+}
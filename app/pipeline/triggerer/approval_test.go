@@ -0,0 +1,189 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggerer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDroneApprovals(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    map[string]bool
+		wantErr bool
+	}{
+		{
+			name: "no-approvals",
+			yaml: `kind: pipeline
+name: build
+steps:
+- name: test
+  image: alpine
+`,
+			want: map[string]bool{},
+		},
+		{
+			name: "multiple-pipelines",
+			yaml: `kind: pipeline
+name: build
+steps:
+- name: test
+  image: alpine
+---
+kind: pipeline
+name: deploy
+approval:
+  required: true
+depends_on:
+- build
+steps:
+- name: deploy
+  image: alpine
+---
+kind: pipeline
+name: notify
+approval:
+  required: false
+steps:
+- name: notify
+  image: alpine
+`,
+			want: map[string]bool{"deploy": true},
+		},
+		{
+			name: "default-name",
+			yaml: `kind: pipeline
+approval:
+  required: true
+steps:
+- name: test
+  image: alpine
+`,
+			want: map[string]bool{"default": true},
+		},
+		{
+			// only pipelines can require an approval.
+			name: "non-pipeline-resource",
+			yaml: `kind: secret
+name: token
+approval:
+  required: true
+---
+kind: pipeline
+name: build
+steps:
+- name: test
+  image: alpine
+`,
+			want: map[string]bool{},
+		},
+		{
+			name: "invalid-approval",
+			yaml: `kind: pipeline
+name: build
+approval: yes please
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseDroneApprovals([]byte(test.yaml))
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestParseV1Approvals(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    map[int]bool
+		wantErr bool
+	}{
+		{
+			name: "no-stages",
+			yaml: `kind: pipeline
+spec: {}
+`,
+			want: map[int]bool{},
+		},
+		{
+			name: "stages",
+			yaml: `kind: pipeline
+spec:
+  stages:
+  - name: build
+    type: ci
+  - name: deploy
+    type: ci
+    approval:
+      required: true
+  - name: notify
+    type: ci
+    approval:
+      required: false
+  - type: ci
+    approval:
+      required: true
+`,
+			want: map[int]bool{1: true, 3: true},
+		},
+		{
+			name: "invalid-approval",
+			yaml: `kind: pipeline
+spec:
+  stages:
+  - name: build
+    approval: [required]
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseV1Approvals([]byte(test.yaml))
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
@@ -63,6 +63,27 @@ func (d *Dag) Ancestors(name string) []*Vertex {
 	return d.ancestors(vertex)
 }
 
+// Dependents returns the names of all vertices that directly or
+// transitively depend on the vertex.
+func (d *Dag) Dependents(name string) []string {
+	visited := map[string]bool{name: true}
+	var combined []string
+	queue := []string{name}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, vertex := range d.graph {
+			if visited[vertex.Name] || !vertex.dependsOn(current) {
+				continue
+			}
+			visited[vertex.Name] = true
+			combined = append(combined, vertex.Name)
+			queue = append(queue, vertex.Name)
+		}
+	}
+	return combined
+}
+
 // DetectCycles returns true if cycles are detected in the graph.
 func (d *Dag) DetectCycles() bool {
 	visited := make(map[string]bool)
@@ -120,6 +141,16 @@ func (d *Dag) dependencies(parent *Vertex) []string {
 	return combined
 }
 
+// helper function returns true if the vertex directly depends on the named vertex.
+func (v *Vertex) dependsOn(name string) bool {
+	for _, dep := range v.graph {
+		if dep == name {
+			return true
+		}
+	}
+	return false
+}
+
 // helper function returns true if the vertex is cyclical.
 func (d *Dag) detectCycles(name string, visited, recStack map[string]bool) bool {
 	visited[name] = true
@@ -16,6 +16,7 @@ package dag
 
 import (
 	"reflect"
+	"sort"
 	"testing"
 )
 
@@ -217,3 +218,28 @@ func TestDependencies_Complex(t *testing.T) {
 		t.Errorf("Unexpected dependencies for notify, got %v", got)
 	}
 }
+
+func TestDependents(t *testing.T) {
+	dag := New()
+	dag.Add("clone")
+	dag.Add("backend", "clone")
+	dag.Add("frontend", "clone")
+	dag.Add("publish", "backend")
+	dag.Add("notify", "publish", "frontend")
+
+	got := dag.Dependents("backend")
+	sort.Strings(got)
+	if want := []string{"notify", "publish"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected dependents for backend, got %v", got)
+	}
+
+	got = dag.Dependents("clone")
+	sort.Strings(got)
+	if want := []string{"backend", "frontend", "notify", "publish"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected dependents for clone, got %v", got)
+	}
+
+	if deps := dag.Dependents("notify"); len(deps) != 0 {
+		t.Errorf("Expect zero dependents for notify")
+	}
+}
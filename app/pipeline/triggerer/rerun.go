@@ -0,0 +1,178 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggerer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/app/pipeline/triggerer/dag"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+// ErrNoFailedStages is returned when rerunning the failed stages of an execution without any.
+var ErrNoFailedStages = errors.New("execution has no failed stages")
+
+// RerunFailed creates an execution for the hook that reuses the results of the successful
+// stages of the parent execution and only runs its failed, incomplete and skipped stages and their dependents.
+// The logs of the reused stages remain with the parent execution.
+func (t *triggerer) RerunFailed(
+	ctx context.Context,
+	pipeline *types.Pipeline,
+	base *Hook,
+	parentStages []*types.Stage,
+) (*types.Execution, error) {
+	repo, err := t.repoStore.Find(ctx, pipeline.RepoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find repo: %w", err)
+	}
+
+	now := time.Now().UnixMilli()
+
+	stages, err := rerunStages(repo.ID, parentStages, now)
+	if err != nil {
+		return nil, err
+	}
+
+	execution := newExecution(repo, pipeline, base, base.event(), now)
+
+	return t.createAndSchedule(ctx, repo, pipeline, execution, stages)
+}
+
+// rerunStages returns the stages of the rerun of the parent stages. The failed and declined stages,
+// the stages that never completed (e.g. because the execution was canceled) and the stages depending
+// on them are rerun. The stages skipped because of the failure of the parent execution are rerun too.
+// The results of all other stages are reused.
+func rerunStages(repoID int64, parentStages []*types.Stage, now int64) ([]*types.Stage, error) {
+	graph := dag.New()
+	for _, stage := range parentStages {
+		graph.Add(stage.Name, stage.DependsOn...)
+	}
+
+	rerun := map[string]bool{}
+	addRerun := func(stage *types.Stage) {
+		rerun[stage.Name] = true
+		for _, name := range graph.Dependents(stage.Name) {
+			rerun[name] = true
+		}
+	}
+
+	for _, stage := range parentStages {
+		if stage.Status.IsFailed() || stage.Status == enum.CIStatusDeclined || !stage.Status.IsDone() {
+			addRerun(stage)
+		}
+	}
+
+	if len(rerun) == 0 {
+		return nil, ErrNoFailedStages
+	}
+
+	// the skipped stages are decided again once the rerun stages complete.
+	for _, stage := range parentStages {
+		if stage.Status == enum.CIStatusSkipped {
+			addRerun(stage)
+		}
+	}
+
+	stages := make([]*types.Stage, len(parentStages))
+	for i, parent := range parentStages {
+		stage := &types.Stage{
+			RepoID:           repoID,
+			Number:           parent.Number,
+			Name:             parent.Name,
+			Kind:             parent.Kind,
+			Type:             parent.Type,
+			OS:               parent.OS,
+			Arch:             parent.Arch,
+			Variant:          parent.Variant,
+			Kernel:           parent.Kernel,
+			Limit:            parent.Limit,
+			LimitRepo:        parent.LimitRepo,
+			OnSuccess:        parent.OnSuccess,
+			OnFailure:        parent.OnFailure,
+			DependsOn:        parent.DependsOn,
+			Labels:           parent.Labels,
+			ApprovalRequired: parent.ApprovalRequired,
+			Created:          now,
+			Updated:          now,
+		}
+
+		if rerun[parent.Name] {
+			stage.Status = rerunStatus(stage, parent, rerun)
+		} else {
+			reuseStageResult(stage, parent)
+		}
+
+		stages[i] = stage
+	}
+
+	return stages, nil
+}
+
+// rerunStatus returns the initial status of a stage that's rerun. The dependencies
+// that aren't rerun are already complete as their results are reused.
+func rerunStatus(stage *types.Stage, parent *types.Stage, rerun map[string]bool) enum.CIStatus {
+	for _, dep := range stage.DependsOn {
+		if rerun[dep] {
+			return enum.CIStatusWaitingOnDeps
+		}
+	}
+
+	// a skipped stage waits for the other rerun stages, which decide whether it runs or is skipped again.
+	// Only stages with dependencies get skipped, so the teardown of the execution picks it up.
+	if parent.Status == enum.CIStatusSkipped {
+		return enum.CIStatusWaitingOnDeps
+	}
+
+	if stage.ApprovalRequired {
+		return enum.CIStatusBlocked
+	}
+
+	return enum.CIStatusPending
+}
+
+// reuseStageResult copies the result of the parent stage, including its steps, to the stage.
+func reuseStageResult(stage *types.Stage, parent *types.Stage) {
+	stage.Status = parent.Status
+	stage.Error = parent.Error
+	stage.ErrIgnore = parent.ErrIgnore
+	stage.ExitCode = parent.ExitCode
+	stage.Machine = parent.Machine
+	stage.Started = parent.Started
+	stage.Stopped = parent.Stopped
+	stage.ApprovalBy = parent.ApprovalBy
+	stage.ApprovalDecided = parent.ApprovalDecided
+
+	stage.Steps = make([]*types.Step, len(parent.Steps))
+	for i, step := range parent.Steps {
+		stage.Steps[i] = &types.Step{
+			Number:    step.Number,
+			Name:      step.Name,
+			Status:    step.Status,
+			Error:     step.Error,
+			ErrIgnore: step.ErrIgnore,
+			ExitCode:  step.ExitCode,
+			Started:   step.Started,
+			Stopped:   step.Stopped,
+			DependsOn: step.DependsOn,
+			Image:     step.Image,
+			Detached:  step.Detached,
+			Schema:    step.Schema,
+		}
+	}
+}
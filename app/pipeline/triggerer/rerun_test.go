@@ -0,0 +1,157 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggerer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+func TestRerunStages(t *testing.T) {
+	approvedBy := int64(7)
+
+	tests := []struct {
+		name    string
+		parents []*types.Stage
+		// want are the statuses of the rerun stages by name, the other stages are reused.
+		want    map[string]enum.CIStatus
+		wantErr error
+	}{
+		{
+			name: "no-failed-stages",
+			parents: []*types.Stage{
+				{Name: "build", Status: enum.CIStatusSuccess, OnSuccess: true},
+				{Name: "notify", Status: enum.CIStatusSkipped, OnFailure: true, DependsOn: []string{"build"}},
+			},
+			wantErr: ErrNoFailedStages,
+		},
+		{
+			name: "failed-stage-and-dependents",
+			parents: []*types.Stage{
+				{Name: "build", Status: enum.CIStatusSuccess, OnSuccess: true},
+				{Name: "test", Status: enum.CIStatusFailure, OnSuccess: true, DependsOn: []string{"build"}},
+				{Name: "deploy", Status: enum.CIStatusSkipped, OnSuccess: true, DependsOn: []string{"test"}},
+				{Name: "report", Status: enum.CIStatusSkipped, OnSuccess: true, DependsOn: []string{"deploy"}},
+			},
+			want: map[string]enum.CIStatus{
+				"test":   enum.CIStatusPending,
+				"deploy": enum.CIStatusWaitingOnDeps,
+				"report": enum.CIStatusWaitingOnDeps,
+			},
+		},
+		{
+			// lint was skipped because docs failed, it has to be decided again after docs is rerun.
+			name: "skipped-stage-of-failed-execution",
+			parents: []*types.Stage{
+				{Name: "build", Status: enum.CIStatusSuccess, OnSuccess: true},
+				{Name: "docs", Status: enum.CIStatusError, OnSuccess: true},
+				{Name: "lint", Status: enum.CIStatusSkipped, OnSuccess: true, DependsOn: []string{"build"}},
+			},
+			want: map[string]enum.CIStatus{
+				"docs": enum.CIStatusPending,
+				"lint": enum.CIStatusWaitingOnDeps,
+			},
+		},
+		{
+			name: "declined-stage",
+			parents: []*types.Stage{
+				{Name: "build", Status: enum.CIStatusSuccess, OnSuccess: true},
+				{Name: "deploy", Status: enum.CIStatusDeclined, OnSuccess: true, ApprovalRequired: true,
+					ApprovalBy: &approvedBy, DependsOn: []string{"build"}},
+			},
+			want: map[string]enum.CIStatus{
+				"deploy": enum.CIStatusBlocked,
+			},
+		},
+		{
+			// the execution was canceled while stages were still waiting.
+			name: "incomplete-stages",
+			parents: []*types.Stage{
+				{Name: "build", Status: enum.CIStatusSuccess, OnSuccess: true},
+				{Name: "test", Status: enum.CIStatusPending, OnSuccess: true},
+				{Name: "approve", Status: enum.CIStatusBlocked, OnSuccess: true, ApprovalRequired: true,
+					DependsOn: []string{"build"}},
+				{Name: "deploy", Status: enum.CIStatusWaitingOnDeps, OnSuccess: true, DependsOn: []string{"approve"}},
+			},
+			want: map[string]enum.CIStatus{
+				"test":    enum.CIStatusPending,
+				"approve": enum.CIStatusBlocked,
+				"deploy":  enum.CIStatusWaitingOnDeps,
+			},
+		},
+		{
+			name: "killed-stage",
+			parents: []*types.Stage{
+				{Name: "build", Status: enum.CIStatusKilled, OnSuccess: true},
+				{Name: "test", Status: enum.CIStatusSuccess, OnSuccess: true},
+			},
+			want: map[string]enum.CIStatus{
+				"build": enum.CIStatusPending,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i, parent := range test.parents {
+				parent.Number = int64(i + 1)
+				parent.Started = 1
+				parent.Stopped = 2
+				parent.Steps = []*types.Step{{Number: 1, Name: "step", Status: parent.Status}}
+			}
+
+			stages, err := rerunStages(1, test.parents, 3)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("want error %v, got %v", test.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(stages) != len(test.parents) {
+				t.Fatalf("want %d stages, got %d", len(test.parents), len(stages))
+			}
+
+			for i, stage := range stages {
+				parent := test.parents[i]
+				if stage.Name != parent.Name || stage.Number != parent.Number {
+					t.Fatalf("want stage %d %q, got %d %q", parent.Number, parent.Name, stage.Number, stage.Name)
+				}
+
+				wantStatus, rerun := test.want[stage.Name]
+				if !rerun {
+					// the result of the parent stage is reused.
+					if stage.Status != parent.Status || stage.Started != parent.Started || len(stage.Steps) != 1 {
+						t.Errorf("stage %q: expected the result of the parent stage to be reused", stage.Name)
+					}
+					continue
+				}
+
+				if stage.Status != wantStatus {
+					t.Errorf("stage %q: want status %s, got %s", stage.Name, wantStatus, stage.Status)
+				}
+				if stage.Started != 0 || stage.Stopped != 0 || len(stage.Steps) != 0 || stage.ApprovalBy != nil {
+					t.Errorf("stage %q: expected a rerun stage without results", stage.Name)
+				}
+			}
+		})
+	}
+}
@@ -73,17 +73,35 @@ type Hook struct {
 	Params       map[string]string  `json:"params"`
 }
 
+// event returns the trigger event of the hook.
+func (h *Hook) event() enum.TriggerEvent {
+	if h.Trigger == enum.TriggerCron {
+		return enum.TriggerEventCron
+	}
+	return h.Action.GetTriggerEvent()
+}
+
 // Triggerer is responsible for triggering a Execution from an
 // incoming hook (could be manual or webhook). If an execution is skipped a nil value is
 // returned.
 type Triggerer interface {
 	Trigger(ctx context.Context, pipeline *types.Pipeline, hook *Hook) (*types.Execution, error)
+
+	// RerunFailed creates an execution for the hook that reuses the results of the successful
+	// stages of the parent execution and only runs its failed, incomplete and skipped stages and their dependents.
+	RerunFailed(
+		ctx context.Context,
+		pipeline *types.Pipeline,
+		hook *Hook,
+		parentStages []*types.Stage,
+	) (*types.Execution, error)
 }
 
 type triggerer struct {
 	executionStore   store.ExecutionStore
 	checkStore       store.CheckStore
 	stageStore       store.StageStore
+	stepStore        store.StepStore
 	tx               dbtx.Transactor
 	pipelineStore    store.PipelineStore
 	fileService      file.Service
@@ -100,6 +118,7 @@ func New(
 	executionStore store.ExecutionStore,
 	checkStore store.CheckStore,
 	stageStore store.StageStore,
+	stepStore store.StepStore,
 	pipelineStore store.PipelineStore,
 	tx dbtx.Transactor,
 	repoStore store.RepoStore,
@@ -115,6 +134,7 @@ func New(
 		executionStore:   executionStore,
 		checkStore:       checkStore,
 		stageStore:       stageStore,
+		stepStore:        stepStore,
 		scheduler:        scheduler,
 		urlProvider:      urlProvider,
 		tx:               tx,
@@ -150,7 +170,7 @@ func (t *triggerer) Trigger(
 		}
 	}()
 
-	event := base.Action.GetTriggerEvent()
+	event := base.event()
 
 	repo, err := t.repoStore.Find(ctx, pipeline.RepoID)
 	if err != nil {
@@ -170,36 +190,7 @@ func (t *triggerer) Trigger(
 	}
 
 	now := time.Now().UnixMilli()
-	execution := &types.Execution{
-		RepoID:     repo.ID,
-		PipelineID: pipeline.ID,
-		Trigger:    base.Trigger,
-		CreatedBy:  base.TriggeredBy,
-		Parent:     base.Parent,
-		Status:     enum.CIStatusPending,
-		Event:      event,
-		Action:     base.Action,
-		Link:       base.Link,
-		// Timestamp:    base.Timestamp,
-		Title:        trunc(base.Title, 2000),
-		Message:      trunc(base.Message, 2000),
-		Before:       base.Before,
-		After:        base.After,
-		Ref:          base.Ref,
-		Fork:         base.Fork,
-		Source:       base.Source,
-		Target:       base.Target,
-		Author:       base.AuthorLogin,
-		AuthorName:   base.AuthorName,
-		AuthorEmail:  base.AuthorEmail,
-		AuthorAvatar: base.AuthorAvatar,
-		Params:       base.Params,
-		Debug:        base.Debug,
-		Sender:       base.Sender,
-		Cron:         base.Cron,
-		Created:      now,
-		Updated:      now,
-	}
+	execution := newExecution(repo, pipeline, base, event, now)
 
 	// For drone, follow the existing path of calculating dependencies, creating a DAG,
 	// and creating stages accordingly. For V1 YAML - for now we can just parse the stages
@@ -233,6 +224,12 @@ func (t *triggerer) Trigger(
 			return t.createExecutionWithError(ctx, pipeline, base, err.Error())
 		}
 
+		approvals, err := parseDroneApprovals(file.Data)
+		if err != nil {
+			log.Warn().Err(err).Msg("trigger: cannot parse yaml approvals")
+			return t.createExecutionWithError(ctx, pipeline, base, err.Error())
+		}
+
 		var matched []*yaml.Pipeline
 		var dag = dag.New()
 		for _, document := range manifest.Resources {
@@ -319,6 +316,7 @@ func (t *triggerer) Trigger(
 			if stage.Name == "" {
 				stage.Name = "default"
 			}
+			stage.ApprovalRequired = approvals[stage.Name]
 			if len(stage.DependsOn) == 0 {
 				stage.Status = enum.CIStatusPending
 			}
@@ -338,6 +336,11 @@ func (t *triggerer) Trigger(
 				len(stage.DependsOn) == 0 {
 				stage.Status = enum.CIStatusPending
 			}
+
+			// stages requiring a manual approval are blocked instead of being picked up for execution.
+			if stage.Status == enum.CIStatusPending && stage.ApprovalRequired {
+				stage.Status = enum.CIStatusBlocked
+			}
 		}
 	} else {
 		stages, err = parseV1Stages(
@@ -347,8 +350,26 @@ func (t *triggerer) Trigger(
 		}
 	}
 
+	return t.createAndSchedule(ctx, repo, pipeline, execution, stages)
+}
+
+// createAndSchedule assigns the next execution number of the pipeline to the execution,
+// creates it along with its stages and schedules the stages that are ready to run.
+func (t *triggerer) createAndSchedule(
+	ctx context.Context,
+	repo *types.Repository,
+	pipeline *types.Pipeline,
+	execution *types.Execution,
+	stages []*types.Stage,
+) (*types.Execution, error) {
+	log := log.With().
+		Int64("pipeline.id", pipeline.ID).
+		Str("trigger.ref", execution.Ref).
+		Str("trigger.commit", execution.After).
+		Logger()
+
 	// Increment pipeline number using optimistic locking.
-	pipeline, err = t.pipelineStore.IncrementSeqNum(ctx, pipeline)
+	pipeline, err := t.pipelineStore.IncrementSeqNum(ctx, pipeline)
 	if err != nil {
 		log.Error().Err(err).Msg("trigger: cannot increment execution sequence number")
 		return nil, err
@@ -384,6 +405,46 @@ func (t *triggerer) Trigger(
 	return execution, nil
 }
 
+// newExecution returns a new pending execution of the pipeline for the hook.
+func newExecution(
+	repo *types.Repository,
+	pipeline *types.Pipeline,
+	base *Hook,
+	event enum.TriggerEvent,
+	now int64,
+) *types.Execution {
+	return &types.Execution{
+		RepoID:     repo.ID,
+		PipelineID: pipeline.ID,
+		Trigger:    base.Trigger,
+		CreatedBy:  base.TriggeredBy,
+		Parent:     base.Parent,
+		Status:     enum.CIStatusPending,
+		Event:      event,
+		Action:     base.Action,
+		Link:       base.Link,
+		// Timestamp:    base.Timestamp,
+		Title:        trunc(base.Title, 2000),
+		Message:      trunc(base.Message, 2000),
+		Before:       base.Before,
+		After:        base.After,
+		Ref:          base.Ref,
+		Fork:         base.Fork,
+		Source:       base.Source,
+		Target:       base.Target,
+		Author:       base.AuthorLogin,
+		AuthorName:   base.AuthorName,
+		AuthorEmail:  base.AuthorEmail,
+		AuthorAvatar: base.AuthorAvatar,
+		Params:       base.Params,
+		Debug:        base.Debug,
+		Sender:       base.Sender,
+		Cron:         base.Cron,
+		Created:      now,
+		Updated:      now,
+	}
+}
+
 func trunc(s string, i int) string {
 	runes := []rune(s)
 	if len(runes) > i {
@@ -414,6 +475,11 @@ func parseV1Stages(
 		return nil, fmt.Errorf("could not parse v1 yaml: %w", err)
 	}
 
+	approvals, err := parseV1Approvals(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse v1 yaml approvals: %w", err)
+	}
+
 	// Normalize the config to make sure stage names and step names are unique
 	err = normalize.Normalize(config)
 	if err != nil {
@@ -476,6 +542,9 @@ func parseV1Stages(
 				// If the stage has no dependencies, it can be picked up for execution.
 				if len(dependsOn) == 0 {
 					status = enum.CIStatusPending
+					if approvals[idx] {
+						status = enum.CIStatusBlocked
+					}
 				}
 				temp := &types.Stage{
 					RepoID:    repo.ID,
@@ -487,6 +556,8 @@ func parseV1Stages(
 					OnSuccess: onSuccess,
 					OnFailure: onFailure,
 					DependsOn: dependsOn,
+
+					ApprovalRequired: approvals[idx],
 				}
 				prevStage = temp.Name
 				stages = append(stages, temp)
@@ -525,6 +596,15 @@ func (t *triggerer) createExecutionWithStages(
 			if err != nil {
 				return err
 			}
+
+			// only the stages reused by a rerun come with steps.
+			for _, step := range stage.Steps {
+				step.StageID = stage.ID
+				err = t.stepStore.Create(ctx, step)
+				if err != nil {
+					return err
+				}
+			}
 		}
 		return nil
 	})
@@ -557,7 +637,7 @@ func (t *triggerer) createExecutionWithError(
 		Parent:       base.Parent,
 		Status:       enum.CIStatusError,
 		Error:        message,
-		Event:        base.Action.GetTriggerEvent(),
+		Event:        base.event(),
 		Action:       base.Action,
 		Link:         base.Link,
 		Title:        base.Title,
@@ -36,6 +36,7 @@ func ProvideTriggerer(
 	executionStore store.ExecutionStore,
 	checkStore store.CheckStore,
 	stageStore store.StageStore,
+	stepStore store.StepStore,
 	tx dbtx.Transactor,
 	pipelineStore store.PipelineStore,
 	fileService file.Service,
@@ -47,7 +48,7 @@ func ProvideTriggerer(
 	pluginStore store.PluginStore,
 	publicAccess publicaccess.Service,
 ) Triggerer {
-	return New(executionStore, checkStore, stageStore, pipelineStore,
+	return New(executionStore, checkStore, stageStore, stepStore, pipelineStore,
 		tx, repoStore, urlProvider, scheduler, fileService, converterService,
 		templateStore, pluginStore, publicAccess)
 }
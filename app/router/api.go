@@ -20,6 +20,7 @@ import (
 	"net/http"
 
 	"github.com/harness/gitness/app/api/controller/aiagent"
+	controlleraudit "github.com/harness/gitness/app/api/controller/audit"
 	"github.com/harness/gitness/app/api/controller/capabilities"
 	"github.com/harness/gitness/app/api/controller/check"
 	"github.com/harness/gitness/app/api/controller/connector"
@@ -30,12 +31,15 @@ import (
 	"github.com/harness/gitness/app/api/controller/keywordsearch"
 	"github.com/harness/gitness/app/api/controller/logs"
 	"github.com/harness/gitness/app/api/controller/migrate"
+	"github.com/harness/gitness/app/api/controller/mirror"
+	"github.com/harness/gitness/app/api/controller/oidc"
 	"github.com/harness/gitness/app/api/controller/pipeline"
 	"github.com/harness/gitness/app/api/controller/plugin"
 	"github.com/harness/gitness/app/api/controller/principal"
 	"github.com/harness/gitness/app/api/controller/pullreq"
 	"github.com/harness/gitness/app/api/controller/repo"
 	"github.com/harness/gitness/app/api/controller/reposettings"
+	"github.com/harness/gitness/app/api/controller/runner"
 	"github.com/harness/gitness/app/api/controller/secret"
 	"github.com/harness/gitness/app/api/controller/serviceaccount"
 	"github.com/harness/gitness/app/api/controller/space"
@@ -48,6 +52,7 @@ import (
 	"github.com/harness/gitness/app/api/controller/webhook"
 	"github.com/harness/gitness/app/api/handler/account"
 	handleraiagent "github.com/harness/gitness/app/api/handler/aiagent"
+	handleraudit "github.com/harness/gitness/app/api/handler/audit"
 	handlercapabilities "github.com/harness/gitness/app/api/handler/capabilities"
 	handlercheck "github.com/harness/gitness/app/api/handler/check"
 	handlerconnector "github.com/harness/gitness/app/api/handler/connector"
@@ -58,6 +63,7 @@ import (
 	handlerkeywordsearch "github.com/harness/gitness/app/api/handler/keywordsearch"
 	handlerlogs "github.com/harness/gitness/app/api/handler/logs"
 	handlermigrate "github.com/harness/gitness/app/api/handler/migrate"
+	handlermirror "github.com/harness/gitness/app/api/handler/mirror"
 	handlerpipeline "github.com/harness/gitness/app/api/handler/pipeline"
 	handlerplugin "github.com/harness/gitness/app/api/handler/plugin"
 	handlerprincipal "github.com/harness/gitness/app/api/handler/principal"
@@ -65,6 +71,7 @@ import (
 	handlerrepo "github.com/harness/gitness/app/api/handler/repo"
 	handlerreposettings "github.com/harness/gitness/app/api/handler/reposettings"
 	"github.com/harness/gitness/app/api/handler/resource"
+	handlerrunner "github.com/harness/gitness/app/api/handler/runner"
 	handlersecret "github.com/harness/gitness/app/api/handler/secret"
 	handlerserviceaccount "github.com/harness/gitness/app/api/handler/serviceaccount"
 	handlerspace "github.com/harness/gitness/app/api/handler/space"
@@ -82,6 +89,7 @@ import (
 	"github.com/harness/gitness/app/api/middleware/logging"
 	"github.com/harness/gitness/app/api/middleware/nocache"
 	middlewareprincipal "github.com/harness/gitness/app/api/middleware/principal"
+	middlewarerunner "github.com/harness/gitness/app/api/middleware/runner"
 	"github.com/harness/gitness/app/api/request"
 	"github.com/harness/gitness/app/auth/authn"
 	"github.com/harness/gitness/app/githook"
@@ -122,6 +130,7 @@ func NewAPIHandler(
 	pluginCtrl *plugin.Controller,
 	pullreqCtrl *pullreq.Controller,
 	webhookCtrl *webhook.Controller,
+	mirrorCtrl *mirror.Controller,
 	githookCtrl *controllergithook.Controller,
 	git git.Interface,
 	saCtrl *serviceaccount.Controller,
@@ -137,6 +146,9 @@ func NewAPIHandler(
 	gitspaceCtrl *gitspace.Controller,
 	aiagentCtrl *aiagent.Controller,
 	capabilitiesCtrl *capabilities.Controller,
+	auditCtrl *controlleraudit.Controller,
+	oidcCtrl *oidc.Controller,
+	runnerCtrl *runner.Controller,
 	usageSender usage.Sender,
 ) http.Handler {
 	// Use go-chi router for inner routing.
@@ -160,17 +172,19 @@ func NewAPIHandler(
 
 	r.Route("/v1", func(r chi.Router) {
 		// special methods that don't require authentication
-		setupAccountWithoutAuth(r, userCtrl, sysCtrl, config)
+		setupAccountWithoutAuth(r, userCtrl, sysCtrl, oidcCtrl, config)
 		setupSystem(r, config, sysCtrl)
 		setupResources(r)
+		setupRunnerRPC(r, runnerCtrl)
 
 		r.Group(func(r chi.Router) {
 			r.Use(middlewareauthn.Attempt(authenticator))
 
 			setupRoutesV1WithAuth(r, appCtx, config, repoCtrl, repoSettingsCtrl, executionCtrl, triggerCtrl, logCtrl,
 				pipelineCtrl, connectorCtrl, templateCtrl, pluginCtrl, secretCtrl, spaceCtrl, pullreqCtrl,
-				webhookCtrl, githookCtrl, git, saCtrl, userCtrl, principalCtrl, userGroupCtrl, checkCtrl, uploadCtrl,
-				searchCtrl, gitspaceCtrl, infraProviderCtrl, migrateCtrl, aiagentCtrl, capabilitiesCtrl, usageSender)
+				webhookCtrl, mirrorCtrl, githookCtrl, git, saCtrl, userCtrl, principalCtrl, userGroupCtrl, checkCtrl, uploadCtrl,
+				searchCtrl, gitspaceCtrl, infraProviderCtrl, migrateCtrl, aiagentCtrl, capabilitiesCtrl, auditCtrl,
+				sysCtrl, runnerCtrl, usageSender)
 		})
 	})
 
@@ -208,6 +222,7 @@ func setupRoutesV1WithAuth(r chi.Router,
 	spaceCtrl *space.Controller,
 	pullreqCtrl *pullreq.Controller,
 	webhookCtrl *webhook.Controller,
+	mirrorCtrl *mirror.Controller,
 	githookCtrl *controllergithook.Controller,
 	git git.Interface,
 	saCtrl *serviceaccount.Controller,
@@ -222,12 +237,15 @@ func setupRoutesV1WithAuth(r chi.Router,
 	migrateCtrl *migrate.Controller,
 	aiagentCtrl *aiagent.Controller,
 	capabilitiesCtrl *capabilities.Controller,
+	auditCtrl *controlleraudit.Controller,
+	sysCtrl *system.Controller,
+	runnerCtrl *runner.Controller,
 	usageSender usage.Sender,
 ) {
 	setupAccountWithAuth(r, userCtrl, config)
-	setupSpaces(r, appCtx, spaceCtrl, userGroupCtrl, webhookCtrl, checkCtrl)
+	setupSpaces(r, appCtx, config, spaceCtrl, userGroupCtrl, webhookCtrl, checkCtrl, auditCtrl)
 	setupRepos(r, repoCtrl, repoSettingsCtrl, pipelineCtrl, executionCtrl, triggerCtrl,
-		logCtrl, pullreqCtrl, webhookCtrl, checkCtrl, uploadCtrl, usageSender)
+		logCtrl, pullreqCtrl, webhookCtrl, mirrorCtrl, checkCtrl, uploadCtrl, usageSender)
 	setupConnectors(r, connectorCtrl)
 	setupTemplates(r, templateCtrl)
 	setupSecrets(r, secretCtrl)
@@ -236,7 +254,7 @@ func setupRoutesV1WithAuth(r chi.Router,
 	setupServiceAccounts(r, saCtrl)
 	setupPrincipals(r, principalCtrl)
 	setupInternal(r, githookCtrl, git)
-	setupAdmin(r, userCtrl)
+	setupAdmin(r, userCtrl, auditCtrl, sysCtrl, runnerCtrl)
 	setupPlugins(r, pluginCtrl)
 	setupKeywordSearch(r, searchCtrl)
 	setupInfraProviders(r, infraProviderCtrl)
@@ -248,15 +266,18 @@ func setupRoutesV1WithAuth(r chi.Router,
 func setupSpaces(
 	r chi.Router,
 	appCtx context.Context,
+	config *types.Config,
 	spaceCtrl *space.Controller,
 	userGroupCtrl *usergroup.Controller,
 	webhookCtrl *webhook.Controller,
 	checkCtrl *check.Controller,
+	auditCtrl *controlleraudit.Controller,
 ) {
 	r.Route("/spaces", func(r chi.Router) {
 		// Create takes path and parentId via body, not uri
 		r.Post("/", handlerspace.HandleCreate(spaceCtrl))
 		r.Post("/import", handlerspace.HandleImport(spaceCtrl))
+		r.Post("/import-archive", handlerspace.HandleImportArchive(spaceCtrl, config.SpaceArchive.MaxSize))
 
 		r.Route(fmt.Sprintf("/{%s}", request.PathParamSpaceRef), func(r chi.Router) {
 			// space operations
@@ -282,8 +303,11 @@ func setupSpaces(
 			r.Get("/gitspaces", handlerspace.HandleListGitspaces(spaceCtrl))
 			r.Post("/export", handlerspace.HandleExport(spaceCtrl))
 			r.Get("/export-progress", handlerspace.HandleExportProgress(spaceCtrl))
+			r.Get("/export-archive", handlerspace.HandleExportArchive(spaceCtrl))
+			r.Get("/import-archive-progress", handlerspace.HandleImportArchiveProgress(spaceCtrl))
 			r.Post("/public-access", handlerspace.HandleUpdatePublicAccess(spaceCtrl))
 			r.Get("/pullreq", handlerspace.HandleListPullReqs(spaceCtrl))
+			r.Get("/audit-events", handleraudit.HandleListSpace(auditCtrl))
 
 			r.Route("/members", func(r chi.Router) {
 				r.Get("/", handlerspace.HandleMembershipList(spaceCtrl))
@@ -372,6 +396,7 @@ func setupRepos(r chi.Router,
 	logCtrl *logs.Controller,
 	pullreqCtrl *pullreq.Controller,
 	webhookCtrl *webhook.Controller,
+	mirrorCtrl *mirror.Controller,
 	checkCtrl *check.Controller,
 	uploadCtrl *upload.Controller,
 	usageSender usage.Sender,
@@ -399,6 +424,7 @@ func setupRepos(r chi.Router,
 			r.Get("/summary", handlerrepo.HandleSummary(repoCtrl))
 
 			r.Post("/move", handlerrepo.HandleMove(repoCtrl))
+			r.Post("/fork", handlerrepo.HandleFork(repoCtrl))
 			r.Get("/service-accounts", handlerrepo.HandleListServiceAccounts(repoCtrl))
 
 			r.Get("/import-progress", handlerrepo.HandleImportProgress(repoCtrl))
@@ -479,8 +505,12 @@ func setupRepos(r chi.Router,
 
 			SetupPullReq(r, pullreqCtrl)
 
+			r.Get("/merge-queue", handlerpullreq.HandleMergeQueueList(pullreqCtrl))
+
 			SetupWebhookRepo(r, webhookCtrl)
 
+			SetupMirrors(r, mirrorCtrl)
+
 			setupPipelines(r, repoCtrl, pipelineCtrl, executionCtrl, triggerCtrl, logCtrl)
 
 			SetupChecks(r, checkCtrl)
@@ -618,6 +648,12 @@ func setupExecutions(
 		r.Route(fmt.Sprintf("/{%s}", request.PathParamExecutionNumber), func(r chi.Router) {
 			r.Get("/", handlerexecution.HandleFind(executionCtrl))
 			r.Post("/cancel", handlerexecution.HandleCancel(executionCtrl))
+			r.Post("/rerun", handlerexecution.HandleRerun(executionCtrl))
+			r.Post("/rerun-failed", handlerexecution.HandleRerunFailed(executionCtrl))
+			r.Route(fmt.Sprintf("/stages/{%s}", request.PathParamStageNumber), func(r chi.Router) {
+				r.Post("/approve", handlerexecution.HandleApprove(executionCtrl))
+				r.Post("/decline", handlerexecution.HandleDecline(executionCtrl))
+			})
 			r.Delete("/", handlerexecution.HandleDelete(executionCtrl))
 			r.Get(
 				fmt.Sprintf("/logs/{%s}/{%s}",
@@ -706,6 +742,14 @@ func SetupPullReq(r chi.Router, pullreqCtrl *pullreq.Controller) {
 				r.Post("/", handlerpullreq.HandleReviewSubmit(pullreqCtrl))
 			})
 			r.Post("/merge", handlerpullreq.HandleMerge(pullreqCtrl))
+			r.Route("/auto-merge", func(r chi.Router) {
+				r.Post("/", handlerpullreq.HandleAutoMergeEnable(pullreqCtrl))
+				r.Delete("/", handlerpullreq.HandleAutoMergeDisable(pullreqCtrl))
+			})
+			r.Route("/merge-queue", func(r chi.Router) {
+				r.Post("/", handlerpullreq.HandleMergeQueueAdd(pullreqCtrl))
+				r.Delete("/", handlerpullreq.HandleMergeQueueRemove(pullreqCtrl))
+			})
 			r.Get("/commits", handlerpullreq.HandleCommits(pullreqCtrl))
 			r.Get("/metadata", handlerpullreq.HandleMetadata(pullreqCtrl))
 			r.Route("/branch", func(r chi.Router) {
@@ -760,6 +804,19 @@ func SetupWebhookRepo(r chi.Router, webhookCtrl *webhook.Controller) {
 	})
 }
 
+func SetupMirrors(r chi.Router, mirrorCtrl *mirror.Controller) {
+	r.Route("/mirrors", func(r chi.Router) {
+		r.Post("/", handlermirror.HandleCreate(mirrorCtrl))
+		r.Get("/", handlermirror.HandleList(mirrorCtrl))
+		r.Route(fmt.Sprintf("/{%s}", request.PathParamMirrorIdentifier), func(r chi.Router) {
+			r.Get("/", handlermirror.HandleFind(mirrorCtrl))
+			r.Patch("/", handlermirror.HandleUpdate(mirrorCtrl))
+			r.Delete("/", handlermirror.HandleDelete(mirrorCtrl))
+			r.Post("/sync", handlermirror.HandleSync(mirrorCtrl))
+		})
+	})
+}
+
 func SetupChecks(r chi.Router, checkCtrl *check.Controller) {
 	r.Route("/checks", func(r chi.Router) {
 		r.Get("/recent", handlercheck.HandleCheckListRecent(checkCtrl))
@@ -819,6 +876,25 @@ func setupUser(r chi.Router, userCtrl *user.Controller) {
 			r.Delete(fmt.Sprintf("/{%s}", request.PathParamPublicKeyIdentifier),
 				handleruser.HandleDeletePublicKey(userCtrl))
 		})
+
+		// Chat identities used for chat notifications
+		r.Route("/chat-identities", func(r chi.Router) {
+			r.Get("/", handleruser.HandleListChatIdentities(userCtrl))
+			r.Route(fmt.Sprintf("/{%s}", request.PathParamChatProvider), func(r chi.Router) {
+				r.Put("/", handleruser.HandleLinkChatIdentity(userCtrl))
+				r.Delete("/", handleruser.HandleUnlinkChatIdentity(userCtrl))
+				r.Post("/confirm", handleruser.HandleConfirmChatIdentity(userCtrl))
+			})
+		})
+
+		// Two-factor authentication
+		r.Route("/2fa", func(r chi.Router) {
+			r.Get("/", handleruser.HandleTwoFactorStatus(userCtrl))
+			r.Post("/totp", handleruser.HandleEnrollTOTP(userCtrl))
+			r.Post("/totp/enable", handleruser.HandleEnableTOTP(userCtrl))
+			r.Post("/totp/disable", handleruser.HandleDisableTOTP(userCtrl))
+			r.Post("/recovery-codes", handleruser.HandleRegenerateRecoveryCodes(userCtrl))
+		})
 	})
 }
 
@@ -897,7 +973,13 @@ func setupInfraProviders(r chi.Router, infraProviderCtrl *infraprovider.Controll
 	})
 }
 
-func setupAdmin(r chi.Router, userCtrl *user.Controller) {
+func setupAdmin(
+	r chi.Router,
+	userCtrl *user.Controller,
+	auditCtrl *controlleraudit.Controller,
+	sysCtrl *system.Controller,
+	runnerCtrl *runner.Controller,
+) {
 	r.Route("/admin", func(r chi.Router) {
 		r.Use(middlewareprincipal.RestrictToAdmin())
 		r.Route("/users", func(r chi.Router) {
@@ -909,8 +991,57 @@ func setupAdmin(r chi.Router, userCtrl *user.Controller) {
 				r.Patch("/", users.HandleUpdate(userCtrl))
 				r.Delete("/", users.HandleDelete(userCtrl))
 				r.Patch("/admin", handleruser.HandleUpdateAdmin(userCtrl))
+				r.Delete("/2fa", users.HandleResetTwoFactor(userCtrl))
+				r.Put("/ldap-identity", users.HandleLinkLDAPIdentity(userCtrl))
+				r.Delete("/ldap-identity", users.HandleUnlinkLDAPIdentity(userCtrl))
+				r.Route(fmt.Sprintf("/chat-identities/{%s}", request.PathParamChatProvider), func(r chi.Router) {
+					r.Put("/", users.HandleLinkChatIdentity(userCtrl))
+					r.Delete("/", users.HandleUnlinkChatIdentity(userCtrl))
+				})
 			})
 		})
+
+		r.Route("/settings", func(r chi.Router) {
+			r.Get("/", handlersystem.HandleFindSettings(sysCtrl))
+			r.Patch("/", handlersystem.HandleUpdateSettings(sysCtrl))
+		})
+
+		r.Get("/registry/gc", handlersystem.HandleRegistryGCStats(sysCtrl))
+
+		r.Get("/audit-events", handleraudit.HandleList(auditCtrl))
+
+		r.Route("/runners", func(r chi.Router) {
+			r.Get("/", handlerrunner.HandleList(runnerCtrl))
+			r.Post("/", handlerrunner.HandleRegister(runnerCtrl))
+
+			r.Route(fmt.Sprintf("/{%s}", request.PathParamRunnerIdentifier), func(r chi.Router) {
+				r.Get("/", handlerrunner.HandleFind(runnerCtrl))
+				r.Delete("/", handlerrunner.HandleDelete(runnerCtrl))
+			})
+		})
+	})
+}
+
+// setupRunnerRPC sets up the protocol used by remote runners to execute stages.
+// The runners authenticate with their own token instead of a principal.
+func setupRunnerRPC(r chi.Router, runnerCtrl *runner.Controller) {
+	r.Route("/rpc/v2", func(r chi.Router) {
+		r.Use(middlewarerunner.Authenticate(runnerCtrl))
+
+		r.Post("/ping", handlerrunner.HandlePing())
+		r.Post("/stage", handlerrunner.HandleRequestStage(runnerCtrl))
+		r.Route(fmt.Sprintf("/stage/{%s}", request.PathParamRunnerStageID), func(r chi.Router) {
+			r.Post("/", handlerrunner.HandleAcceptStage(runnerCtrl))
+			r.Get("/", handlerrunner.HandleDetailStage(runnerCtrl))
+			r.Put("/", handlerrunner.HandleUpdateStage(runnerCtrl))
+		})
+		r.Route(fmt.Sprintf("/step/{%s}", request.PathParamRunnerStepID), func(r chi.Router) {
+			r.Put("/", handlerrunner.HandleUpdateStep(runnerCtrl))
+			r.Post("/logs/batch", handlerrunner.HandleWriteLogs(runnerCtrl))
+			r.Post("/logs/upload", handlerrunner.HandleUploadLogs(runnerCtrl))
+			r.Post("/card", handlerrunner.HandleUploadCard())
+		})
+		r.Post(fmt.Sprintf("/build/{%s}/watch", request.PathParamRunnerBuildID), handlerrunner.HandleWatch(runnerCtrl))
 	})
 }
 
@@ -918,11 +1049,20 @@ func setupAccountWithoutAuth(
 	r chi.Router,
 	userCtrl *user.Controller,
 	sysCtrl *system.Controller,
+	oidcCtrl *oidc.Controller,
 	config *types.Config,
 ) {
 	cookieName := config.Token.CookieName
 	r.Post("/login", account.HandleLogin(userCtrl, cookieName))
+	r.Post("/login/2fa", account.HandleLoginTwoFactor(userCtrl, cookieName))
+	r.Post("/login/2fa/enroll", account.HandleLoginTwoFactorEnroll(userCtrl))
 	r.Post("/register", account.HandleRegister(userCtrl, sysCtrl, cookieName))
+	r.Post("/password-reset", account.HandleRequestPasswordReset(userCtrl))
+	r.Post("/password-reset/confirm", account.HandleResetPassword(userCtrl))
+	r.Post("/verify-email", account.HandleVerifyEmail(userCtrl))
+	r.Post("/verify-email/resend", account.HandleRequestEmailVerification(userCtrl))
+	r.Get("/login/oidc", account.HandleOIDCAuthorize(oidcCtrl, cookieName))
+	r.Get("/login/oidc/callback", account.HandleOIDCCallback(oidcCtrl, cookieName, config.URL.UI))
 }
 
 func setupAccountWithAuth(r chi.Router, userCtrl *user.Controller, config *types.Config) {
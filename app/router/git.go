@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/harness/gitness/app/api/controller/lfs"
 	"github.com/harness/gitness/app/api/controller/repo"
 	handlerrepo "github.com/harness/gitness/app/api/handler/repo"
 	middlewareauthn "github.com/harness/gitness/app/api/middleware/authn"
@@ -44,6 +45,7 @@ func NewGitHandler(
 	urlProvider url.Provider,
 	authenticator authn.Authenticator,
 	repoCtrl *repo.Controller,
+	lfsCtrl *lfs.Controller,
 	usageSender usage.Sender,
 ) http.Handler {
 	// maxRepoDepth depends on config
@@ -89,6 +91,17 @@ func NewGitHandler(
 				enum.GitServiceTypeReceivePack, repoCtrl, urlProvider))
 			r.Get("/info/refs", handlerrepo.HandleGitInfoRefs(repoCtrl, urlProvider))
 
+			// git lfs (batch API and basic transfer adapter)
+			r.Route("/info/lfs/objects", func(r chi.Router) {
+				r.Post("/batch", handlerrepo.HandleGitLFSBatch(lfsCtrl, urlProvider))
+				r.Put(fmt.Sprintf("/{%s}", request.PathParamLFSObjectID),
+					handlerrepo.HandleGitLFSUpload(lfsCtrl, urlProvider))
+				r.With(
+					usage.Middleware(usageSender, false),
+				).Get(fmt.Sprintf("/{%s}", request.PathParamLFSObjectID),
+					handlerrepo.HandleGitLFSDownload(lfsCtrl, urlProvider))
+			})
+
 			// dumb protocol
 			r.Get("/HEAD", stubGitHandler())
 			r.Get("/objects/info/alternates", stubGitHandler())
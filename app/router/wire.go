@@ -19,6 +19,7 @@ import (
 	"strings"
 
 	"github.com/harness/gitness/app/api/controller/aiagent"
+	"github.com/harness/gitness/app/api/controller/audit"
 	"github.com/harness/gitness/app/api/controller/capabilities"
 	"github.com/harness/gitness/app/api/controller/check"
 	"github.com/harness/gitness/app/api/controller/connector"
@@ -27,14 +28,18 @@ import (
 	"github.com/harness/gitness/app/api/controller/gitspace"
 	"github.com/harness/gitness/app/api/controller/infraprovider"
 	"github.com/harness/gitness/app/api/controller/keywordsearch"
+	"github.com/harness/gitness/app/api/controller/lfs"
 	"github.com/harness/gitness/app/api/controller/logs"
 	"github.com/harness/gitness/app/api/controller/migrate"
+	"github.com/harness/gitness/app/api/controller/mirror"
+	"github.com/harness/gitness/app/api/controller/oidc"
 	"github.com/harness/gitness/app/api/controller/pipeline"
 	"github.com/harness/gitness/app/api/controller/plugin"
 	"github.com/harness/gitness/app/api/controller/principal"
 	"github.com/harness/gitness/app/api/controller/pullreq"
 	"github.com/harness/gitness/app/api/controller/repo"
 	"github.com/harness/gitness/app/api/controller/reposettings"
+	"github.com/harness/gitness/app/api/controller/runner"
 	"github.com/harness/gitness/app/api/controller/secret"
 	"github.com/harness/gitness/app/api/controller/serviceaccount"
 	"github.com/harness/gitness/app/api/controller/space"
@@ -110,10 +115,15 @@ func ProvideRouter(
 	migrateCtrl *migrate.Controller,
 	aiagentCtrl *aiagent.Controller,
 	capabilitiesCtrl *capabilities.Controller,
+	auditCtrl *audit.Controller,
 	urlProvider url.Provider,
 	openapi openapi.Service,
 	registryRouter router.AppRouter,
 	usageSender usage.Sender,
+	lfsCtrl *lfs.Controller,
+	mirrorCtrl *mirror.Controller,
+	oidcCtrl *oidc.Controller,
+	runnerCtrl *runner.Controller,
 ) *Router {
 	routers := make([]Interface, 4)
 
@@ -123,6 +133,7 @@ func ProvideRouter(
 		urlProvider,
 		authenticator,
 		repoCtrl,
+		lfsCtrl,
 		usageSender,
 	)
 	routers[0] = NewGitRouter(gitHandler, gitRoutingHost)
@@ -132,8 +143,9 @@ func ProvideRouter(
 		appCtx, config,
 		authenticator, repoCtrl, repoSettingsCtrl, executionCtrl, logCtrl, spaceCtrl, pipelineCtrl,
 		secretCtrl, triggerCtrl, connectorCtrl, templateCtrl, pluginCtrl, pullreqCtrl, webhookCtrl,
-		githookCtrl, git, saCtrl, userCtrl, principalCtrl, userGroupCtrl, checkCtrl, sysCtrl, blobCtrl, searchCtrl,
-		infraProviderCtrl, migrateCtrl, gitspaceCtrl, aiagentCtrl, capabilitiesCtrl, usageSender)
+		mirrorCtrl, githookCtrl, git, saCtrl, userCtrl, principalCtrl, userGroupCtrl, checkCtrl, sysCtrl, blobCtrl,
+		searchCtrl, infraProviderCtrl, migrateCtrl, gitspaceCtrl, aiagentCtrl, capabilitiesCtrl, auditCtrl, oidcCtrl,
+		runnerCtrl, usageSender)
 	routers[2] = NewAPIRouter(apiHandler)
 
 	webHandler := NewWebHandler(config, authenticator, openapi)
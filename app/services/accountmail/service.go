@@ -0,0 +1,219 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountmail
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/app/jwt"
+	"github.com/harness/gitness/app/services/notification/mailer"
+	"github.com/harness/gitness/app/store"
+	urlprovider "github.com/harness/gitness/app/url"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	gojwt "github.com/golang-jwt/jwt"
+)
+
+const (
+	templatePasswordReset     = "password_reset.html"
+	templateEmailVerification = "email_verification.html"
+
+	subjectPasswordReset     = "Reset your password"
+	subjectEmailVerification = "Verify your email address"
+
+	// linkSecretSuffix is appended to the principal's salt to sign the links,
+	// so a link can't be used as a session token.
+	linkSecretSuffix = ":account-link"
+)
+
+var (
+	// ErrRateLimited is returned if too many mails of the same type were sent to the user recently.
+	ErrRateLimited = errors.New("too many account mails sent to the user")
+
+	// ErrInvalidLink is returned if the token of a link is invalid, expired or was already used.
+	ErrInvalidLink = errors.New("invalid or expired link")
+)
+
+var (
+	//go:embed templates/*
+	files     embed.FS
+	templates = template.Must(template.ParseFS(files, "templates/*.html"))
+)
+
+type mailData struct {
+	User     *types.User
+	Link     string
+	Lifetime time.Duration
+}
+
+// Service sends the links of the self-service account flows (password reset, email verification)
+// to the users and verifies the links once they are used.
+type Service struct {
+	config         *types.Config
+	mailer         mailer.Mailer
+	urlProvider    urlprovider.Provider
+	principalStore store.PrincipalStore
+	limitStore     store.UserMailLimitStore
+}
+
+func NewService(
+	config *types.Config,
+	mailer mailer.Mailer,
+	urlProvider urlprovider.Provider,
+	principalStore store.PrincipalStore,
+	limitStore store.UserMailLimitStore,
+) *Service {
+	return &Service{
+		config:         config,
+		mailer:         mailer,
+		urlProvider:    urlProvider,
+		principalStore: principalStore,
+		limitStore:     limitStore,
+	}
+}
+
+// PasswordResetEnabled returns true if users can reset their password by email.
+func (s *Service) PasswordResetEnabled() bool {
+	return s.config.AccountMail.PasswordResetEnabled
+}
+
+// EmailVerificationRequired returns true if new users have to verify their email address before they can log in.
+func (s *Service) EmailVerificationRequired() bool {
+	return s.config.AccountMail.EmailVerificationRequired
+}
+
+// SendPasswordReset sends the user a link to reset the password.
+func (s *Service) SendPasswordReset(ctx context.Context, user *types.User) error {
+	return s.send(ctx, user, enum.UserMailTypePasswordReset, s.config.AccountMail.PasswordResetLinkLifetime,
+		"reset-password", subjectPasswordReset, templatePasswordReset)
+}
+
+// SendEmailVerification sends the user a link to verify the email address.
+func (s *Service) SendEmailVerification(ctx context.Context, user *types.User) error {
+	return s.send(ctx, user, enum.UserMailTypeEmailVerification, s.config.AccountMail.EmailVerificationLinkLifetime,
+		"verify-email", subjectEmailVerification, templateEmailVerification)
+}
+
+func (s *Service) send(
+	ctx context.Context,
+	user *types.User,
+	mailType enum.UserMailType,
+	lifetime time.Duration,
+	uiPath string,
+	subject string,
+	templateName string,
+) error {
+	now := time.Now()
+	windowStart := now.Add(-s.config.AccountMail.RateLimitWindow)
+
+	count, err := s.limitStore.Increment(ctx, user.ID, mailType, now.UnixMilli(), windowStart.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("failed to increment the mail count of the user: %w", err)
+	}
+
+	if count > s.config.AccountMail.RateLimit {
+		return ErrRateLimited
+	}
+
+	token, err := jwt.GenerateForAccountLink(user.ID, mailType, fingerprint(user, mailType), lifetime,
+		user.Salt+linkSecretSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to generate link token: %w", err)
+	}
+
+	link, err := url.JoinPath(s.urlProvider.GetUIBaseURL(ctx), uiPath)
+	if err != nil {
+		return fmt.Errorf("failed to build link: %w", err)
+	}
+
+	link += "?" + url.Values{"token": []string{token}}.Encode()
+
+	body := bytes.Buffer{}
+	err = templates.ExecuteTemplate(&body, templateName, mailData{
+		User:     user,
+		Link:     link,
+		Lifetime: lifetime,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute template %s: %w", templateName, err)
+	}
+
+	err = s.mailer.Send(ctx, mailer.Payload{
+		ToRecipients: []string{user.Email},
+		Subject:      subject,
+		Body:         body.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	return nil
+}
+
+// ParseLink verifies the token of a link of the provided type and returns the user the link was sent to.
+// The link is invalid once the data it modifies changed (e.g. the password got reset).
+func (s *Service) ParseLink(ctx context.Context, token string, mailType enum.UserMailType) (*types.User, error) {
+	var user *types.User
+	claims := &jwt.Claims{}
+
+	parsed, err := gojwt.ParseWithClaims(token, claims, func(token *gojwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*gojwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("invalid signing method of link")
+		}
+
+		var err error
+		user, err = s.principalStore.FindUser(ctx, claims.PrincipalID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find user of link: %w", err)
+		}
+
+		return []byte(user.Salt + linkSecretSuffix), nil
+	})
+	if err != nil || !parsed.Valid || claims.AccountLink == nil {
+		return nil, ErrInvalidLink
+	}
+
+	if claims.AccountLink.Type != mailType || claims.AccountLink.Fingerprint != fingerprint(user, mailType) {
+		return nil, ErrInvalidLink
+	}
+
+	return user, nil
+}
+
+// fingerprint returns a hash of the user data the link of the provided type modifies.
+func fingerprint(user *types.User, mailType enum.UserMailType) string {
+	var data string
+	switch mailType {
+	case enum.UserMailTypePasswordReset:
+		data = user.Password
+	case enum.UserMailTypeEmailVerification:
+		data = strings.ToLower(user.Email)
+	}
+
+	sum := sha256.Sum256([]byte(string(mailType) + ":" + data))
+
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,319 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountmail
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/jwt"
+	"github.com/harness/gitness/app/services/notification/mailer"
+	"github.com/harness/gitness/app/store"
+	urlprovider "github.com/harness/gitness/app/url"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+type fakePrincipalStore struct {
+	store.PrincipalStore
+	user *types.User
+}
+
+func (s *fakePrincipalStore) FindUser(_ context.Context, id int64) (*types.User, error) {
+	if s.user == nil || s.user.ID != id {
+		return nil, gitness_store.ErrResourceNotFound
+	}
+	dup := *s.user
+	return &dup, nil
+}
+
+type fakeLimitStore struct {
+	store.UserMailLimitStore
+	count int
+}
+
+func (s *fakeLimitStore) Increment(context.Context, int64, enum.UserMailType, int64, int64) (int, error) {
+	s.count++
+	return s.count, nil
+}
+
+type fakeMailer struct {
+	sent []mailer.Payload
+}
+
+func (m *fakeMailer) Send(_ context.Context, payload mailer.Payload) error {
+	m.sent = append(m.sent, payload)
+	return nil
+}
+
+type fakeURLProvider struct {
+	urlprovider.Provider
+}
+
+func (fakeURLProvider) GetUIBaseURL(context.Context, ...string) string {
+	return "https://gitness.example.org"
+}
+
+var linkTokenRegexp = regexp.MustCompile(`token=([A-Za-z0-9_\-.]+)`)
+
+func setupService(t *testing.T) (*Service, *types.User, *fakePrincipalStore, *fakeMailer) {
+	t.Helper()
+
+	config := &types.Config{}
+	config.AccountMail.PasswordResetEnabled = true
+	config.AccountMail.PasswordResetLinkLifetime = time.Hour
+	config.AccountMail.EmailVerificationLinkLifetime = time.Hour
+	config.AccountMail.RateLimit = 3
+	config.AccountMail.RateLimitWindow = time.Hour
+
+	user := &types.User{ID: 1, UID: "user", Email: "user@example.org", Password: "hash", Salt: "salt"}
+	principalStore := &fakePrincipalStore{user: user}
+	mail := &fakeMailer{}
+
+	return NewService(config, mail, fakeURLProvider{}, principalStore, &fakeLimitStore{}), user, principalStore, mail
+}
+
+// sentLinkToken returns the token of the link in the last sent mail.
+func sentLinkToken(t *testing.T, mail *fakeMailer) string {
+	t.Helper()
+
+	if len(mail.sent) == 0 {
+		t.Fatalf("expected a mail to be sent")
+	}
+
+	match := linkTokenRegexp.FindStringSubmatch(mail.sent[len(mail.sent)-1].Body)
+	if match == nil {
+		t.Fatalf("expected a link with a token in the mail")
+	}
+
+	return match[1]
+}
+
+func TestParseLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    func(t *testing.T, user *types.User) string
+		mailType enum.UserMailType
+		// modify changes the user after the link was sent.
+		modify  func(user *types.User)
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			token: func(t *testing.T, user *types.User) string {
+				return generateLink(t, user, enum.UserMailTypePasswordReset, time.Hour, user.Salt+linkSecretSuffix)
+			},
+			mailType: enum.UserMailTypePasswordReset,
+		},
+		{
+			name: "bad-signature",
+			token: func(t *testing.T, user *types.User) string {
+				return generateLink(t, user, enum.UserMailTypePasswordReset, time.Hour, "other"+linkSecretSuffix)
+			},
+			mailType: enum.UserMailTypePasswordReset,
+			wantErr:  true,
+		},
+		{
+			// a link mustn't be signed with the secret of the session tokens.
+			name: "session-secret",
+			token: func(t *testing.T, user *types.User) string {
+				return generateLink(t, user, enum.UserMailTypePasswordReset, time.Hour, user.Salt)
+			},
+			mailType: enum.UserMailTypePasswordReset,
+			wantErr:  true,
+		},
+		{
+			name: "session-token",
+			token: func(t *testing.T, user *types.User) string {
+				token, err := jwt.GenerateForToken(&types.Token{
+					Type:        enum.TokenTypeSession,
+					PrincipalID: user.ID,
+					IssuedAt:    time.Now().UnixMilli(),
+				}, user.Salt+linkSecretSuffix)
+				if err != nil {
+					t.Fatalf("failed to generate token: %s", err)
+				}
+				return token
+			},
+			mailType: enum.UserMailTypePasswordReset,
+			wantErr:  true,
+		},
+		{
+			name: "expired",
+			token: func(t *testing.T, user *types.User) string {
+				return generateLink(t, user, enum.UserMailTypePasswordReset, -time.Minute, user.Salt+linkSecretSuffix)
+			},
+			mailType: enum.UserMailTypePasswordReset,
+			wantErr:  true,
+		},
+		{
+			name: "purpose-mismatch",
+			token: func(t *testing.T, user *types.User) string {
+				return generateLink(t, user, enum.UserMailTypeEmailVerification, time.Hour, user.Salt+linkSecretSuffix)
+			},
+			mailType: enum.UserMailTypePasswordReset,
+			wantErr:  true,
+		},
+		{
+			// the password reset changes the password, so the link can be used only once.
+			name: "password-changed",
+			token: func(t *testing.T, user *types.User) string {
+				return generateLink(t, user, enum.UserMailTypePasswordReset, time.Hour, user.Salt+linkSecretSuffix)
+			},
+			mailType: enum.UserMailTypePasswordReset,
+			modify:   func(user *types.User) { user.Password = "new-hash" },
+			wantErr:  true,
+		},
+		{
+			name: "email-changed",
+			token: func(t *testing.T, user *types.User) string {
+				return generateLink(t, user, enum.UserMailTypeEmailVerification, time.Hour, user.Salt+linkSecretSuffix)
+			},
+			mailType: enum.UserMailTypeEmailVerification,
+			modify:   func(user *types.User) { user.Email = "other@example.org" },
+			wantErr:  true,
+		},
+		{
+			name: "email-case-changed",
+			token: func(t *testing.T, user *types.User) string {
+				return generateLink(t, user, enum.UserMailTypeEmailVerification, time.Hour, user.Salt+linkSecretSuffix)
+			},
+			mailType: enum.UserMailTypeEmailVerification,
+			modify:   func(user *types.User) { user.Email = "User@Example.org" },
+		},
+		{
+			name: "salt-changed",
+			token: func(t *testing.T, user *types.User) string {
+				return generateLink(t, user, enum.UserMailTypePasswordReset, time.Hour, user.Salt+linkSecretSuffix)
+			},
+			mailType: enum.UserMailTypePasswordReset,
+			modify:   func(user *types.User) { user.Salt = "new-salt" },
+			wantErr:  true,
+		},
+		{
+			name:     "malformed",
+			token:    func(*testing.T, *types.User) string { return "not-a-token" },
+			mailType: enum.UserMailTypePasswordReset,
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, user, principalStore, _ := setupService(t)
+
+			token := test.token(t, user)
+			if test.modify != nil {
+				test.modify(principalStore.user)
+			}
+
+			got, err := service.ParseLink(context.Background(), token, test.mailType)
+			if test.wantErr {
+				if !errors.Is(err, ErrInvalidLink) {
+					t.Fatalf("want error %v, got %v", ErrInvalidLink, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got.ID != user.ID {
+				t.Errorf("want user %d, got %d", user.ID, got.ID)
+			}
+		})
+	}
+}
+
+func TestParseLink_UserDeleted(t *testing.T) {
+	service, user, principalStore, _ := setupService(t)
+
+	token := generateLink(t, user, enum.UserMailTypePasswordReset, time.Hour, user.Salt+linkSecretSuffix)
+	principalStore.user = nil
+
+	if _, err := service.ParseLink(context.Background(), token, enum.UserMailTypePasswordReset); !errors.Is(err,
+		ErrInvalidLink) {
+		t.Fatalf("want error %v, got %v", ErrInvalidLink, err)
+	}
+}
+
+func TestSendPasswordReset(t *testing.T) {
+	ctx := context.Background()
+	service, user, principalStore, mail := setupService(t)
+
+	if err := service.SendPasswordReset(ctx, user); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := mail.sent[0].ToRecipients; len(got) != 1 || got[0] != user.Email {
+		t.Errorf("want mail sent to %s, got %v", user.Email, got)
+	}
+
+	token := sentLinkToken(t, mail)
+
+	if _, err := service.ParseLink(ctx, token, enum.UserMailTypePasswordReset); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// the link of a password reset can't be used to verify the email address.
+	if _, err := service.ParseLink(ctx, token, enum.UserMailTypeEmailVerification); !errors.Is(err, ErrInvalidLink) {
+		t.Fatalf("want error %v, got %v", ErrInvalidLink, err)
+	}
+
+	// once the password was reset, the link is used up.
+	principalStore.user.Password = "new-hash"
+	if _, err := service.ParseLink(ctx, token, enum.UserMailTypePasswordReset); !errors.Is(err, ErrInvalidLink) {
+		t.Fatalf("want error %v, got %v", ErrInvalidLink, err)
+	}
+}
+
+func TestSendPasswordReset_RateLimited(t *testing.T) {
+	ctx := context.Background()
+	service, user, _, mail := setupService(t)
+
+	for i := 0; i < service.config.AccountMail.RateLimit; i++ {
+		if err := service.SendPasswordReset(ctx, user); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if err := service.SendPasswordReset(ctx, user); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("want error %v, got %v", ErrRateLimited, err)
+	}
+
+	if len(mail.sent) != service.config.AccountMail.RateLimit {
+		t.Errorf("want %d mails sent, got %d", service.config.AccountMail.RateLimit, len(mail.sent))
+	}
+}
+
+func generateLink(
+	t *testing.T,
+	user *types.User,
+	mailType enum.UserMailType,
+	lifetime time.Duration,
+	secret string,
+) string {
+	t.Helper()
+
+	token, err := jwt.GenerateForAccountLink(user.ID, mailType, fingerprint(user, mailType), lifetime, secret)
+	if err != nil {
+		t.Fatalf("failed to generate link: %s", err)
+	}
+	return token
+}
@@ -0,0 +1,187 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package automerge
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/app/api/controller/pullreq"
+	checkevents "github.com/harness/gitness/app/events/check"
+	pipelineevents "github.com/harness/gitness/app/events/pipeline"
+	pullreqevents "github.com/harness/gitness/app/events/pullreq"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/events"
+	"github.com/harness/gitness/stream"
+	"github.com/harness/gitness/types"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const eventsReaderGroupName = "gitness:automerge"
+
+// merger merges a pull request if it has auto-merge enabled and all its merge requirements are satisfied.
+type merger interface {
+	AutoMerge(ctx context.Context, pullreqID int64) error
+}
+
+// Service merges pull requests with enabled auto-merge once their merge requirements are satisfied.
+// The requirements are re-evaluated whenever a pull request gets reviewed, its source branch gets updated
+// or a status check of its latest commit gets reported.
+type Service struct {
+	pullreqCtrl    merger
+	pullreqStore   store.PullReqStore
+	autoMergeStore store.PullReqAutoMergeStore
+}
+
+func New(
+	ctx context.Context,
+	config *types.Config,
+	pullreqCtrl *pullreq.Controller,
+	pullreqStore store.PullReqStore,
+	autoMergeStore store.PullReqAutoMergeStore,
+	pullreqEvReaderFactory *events.ReaderFactory[*pullreqevents.Reader],
+	checkEvReaderFactory *events.ReaderFactory[*checkevents.Reader],
+	pipelineEvReaderFactory *events.ReaderFactory[*pipelineevents.Reader],
+) (*Service, error) {
+	service := &Service{
+		pullreqCtrl:    pullreqCtrl,
+		pullreqStore:   pullreqStore,
+		autoMergeStore: autoMergeStore,
+	}
+
+	const idleTimeout = 5 * time.Minute // gives enough time for the merge
+
+	_, err := pullreqEvReaderFactory.Launch(ctx, eventsReaderGroupName, config.InstanceID,
+		func(r *pullreqevents.Reader) error {
+			r.Configure(
+				stream.WithConcurrency(1),
+				stream.WithHandlerOptions(
+					stream.WithIdleTimeout(idleTimeout),
+					stream.WithMaxRetries(2),
+				))
+
+			_ = r.RegisterReviewSubmitted(service.handleEventReviewSubmitted)
+			_ = r.RegisterBranchUpdated(service.handleEventBranchUpdated)
+
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch pull request events reader: %w", err)
+	}
+
+	_, err = checkEvReaderFactory.Launch(ctx, eventsReaderGroupName, config.InstanceID,
+		func(r *checkevents.Reader) error {
+			r.Configure(
+				stream.WithConcurrency(1),
+				stream.WithHandlerOptions(
+					stream.WithIdleTimeout(idleTimeout),
+					stream.WithMaxRetries(2),
+				))
+
+			_ = r.RegisterReported(service.handleEventCheckReported)
+
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch check events reader: %w", err)
+	}
+
+	_, err = pipelineEvReaderFactory.Launch(ctx, eventsReaderGroupName, config.InstanceID,
+		func(r *pipelineevents.Reader) error {
+			r.Configure(
+				stream.WithConcurrency(1),
+				stream.WithHandlerOptions(
+					stream.WithIdleTimeout(idleTimeout),
+					stream.WithMaxRetries(2),
+				))
+
+			_ = r.RegisterExecuted(service.handleEventPipelineExecuted)
+
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch pipeline events reader: %w", err)
+	}
+
+	return service, nil
+}
+
+func (s *Service) handleEventReviewSubmitted(
+	ctx context.Context,
+	event *events.Event[*pullreqevents.ReviewSubmittedPayload],
+) error {
+	return s.pullreqCtrl.AutoMerge(ctx, event.Payload.PullReqID)
+}
+
+func (s *Service) handleEventBranchUpdated(
+	ctx context.Context,
+	event *events.Event[*pullreqevents.BranchUpdatedPayload],
+) error {
+	return s.pullreqCtrl.AutoMerge(ctx, event.Payload.PullReqID)
+}
+
+// handleEventCheckReported attempts to merge the pull requests of the repository
+// with auto-merge enabled whose latest commit is the one the status check got reported for.
+func (s *Service) handleEventCheckReported(
+	ctx context.Context,
+	event *events.Event[*checkevents.ReportedPayload],
+) error {
+	if !event.Payload.Status.IsCompleted() {
+		return nil
+	}
+
+	return s.autoMergeRepo(ctx, event.Payload.RepoID, func(pr *types.PullReq) bool {
+		return pr.SourceSHA == event.Payload.CommitSHA
+	})
+}
+
+// handleEventPipelineExecuted attempts to merge the pull requests of the repository with auto-merge enabled,
+// as the status check of the executed pipeline might have been the last missing merge requirement.
+func (s *Service) handleEventPipelineExecuted(
+	ctx context.Context,
+	event *events.Event[*pipelineevents.ExecutedPayload],
+) error {
+	return s.autoMergeRepo(ctx, event.Payload.RepoID, func(*types.PullReq) bool {
+		return true
+	})
+}
+
+func (s *Service) autoMergeRepo(ctx context.Context, repoID int64, filter func(*types.PullReq) bool) error {
+	autoMerges, err := s.autoMergeStore.ListByRepo(ctx, repoID)
+	if err != nil {
+		return fmt.Errorf("failed to list pull requests with auto-merge enabled: %w", err)
+	}
+
+	var errs error
+	for _, autoMerge := range autoMerges {
+		pr, err := s.pullreqStore.Find(ctx, autoMerge.PullReqID)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("failed to find pull request: %w", err))
+			continue
+		}
+
+		if !filter(pr) {
+			continue
+		}
+
+		if err = s.pullreqCtrl.AutoMerge(ctx, pr.ID); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("failed to auto-merge pull request #%d: %w", pr.Number, err))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,144 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package automerge
+
+import (
+	"context"
+	"testing"
+
+	checkevents "github.com/harness/gitness/app/events/check"
+	pipelineevents "github.com/harness/gitness/app/events/pipeline"
+	pullreqevents "github.com/harness/gitness/app/events/pullreq"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/events"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestService_Events(t *testing.T) {
+	const repoID = 10
+
+	tests := []struct {
+		name   string
+		handle func(ctx context.Context, s *Service) error
+		exp    []int64
+	}{
+		{
+			name: "review-submitted",
+			handle: func(ctx context.Context, s *Service) error {
+				return s.handleEventReviewSubmitted(ctx, &events.Event[*pullreqevents.ReviewSubmittedPayload]{
+					Payload: &pullreqevents.ReviewSubmittedPayload{Base: pullreqevents.Base{PullReqID: 2}},
+				})
+			},
+			exp: []int64{2},
+		},
+		{
+			name: "branch-updated",
+			handle: func(ctx context.Context, s *Service) error {
+				return s.handleEventBranchUpdated(ctx, &events.Event[*pullreqevents.BranchUpdatedPayload]{
+					Payload: &pullreqevents.BranchUpdatedPayload{Base: pullreqevents.Base{PullReqID: 3}},
+				})
+			},
+			exp: []int64{3},
+		},
+		{
+			name: "check-reported-running",
+			handle: func(ctx context.Context, s *Service) error {
+				return s.handleEventCheckReported(ctx, &events.Event[*checkevents.ReportedPayload]{
+					Payload: &checkevents.ReportedPayload{
+						RepoID:    repoID,
+						CommitSHA: "sha-1",
+						Status:    enum.CheckStatusRunning,
+					},
+				})
+			},
+		},
+		{
+			name: "check-reported-completed",
+			handle: func(ctx context.Context, s *Service) error {
+				return s.handleEventCheckReported(ctx, &events.Event[*checkevents.ReportedPayload]{
+					Payload: &checkevents.ReportedPayload{
+						RepoID:    repoID,
+						CommitSHA: "sha-1",
+						Status:    enum.CheckStatusSuccess,
+					},
+				})
+			},
+			exp: []int64{1},
+		},
+		{
+			name: "pipeline-executed",
+			handle: func(ctx context.Context, s *Service) error {
+				return s.handleEventPipelineExecuted(ctx, &events.Event[*pipelineevents.ExecutedPayload]{
+					Payload: &pipelineevents.ExecutedPayload{RepoID: repoID},
+				})
+			},
+			exp: []int64{1, 2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := &fakeMerger{}
+			s := &Service{
+				pullreqCtrl: m,
+				pullreqStore: fakePullReqStore{prs: map[int64]*types.PullReq{
+					1: {ID: 1, SourceSHA: "sha-1"},
+					2: {ID: 2, SourceSHA: "sha-2"},
+				}},
+				autoMergeStore: fakeAutoMergeStore{autoMerges: map[int64][]*types.PullReqAutoMerge{
+					repoID: {{PullReqID: 1}, {PullReqID: 2}},
+				}},
+			}
+
+			if err := test.handle(context.Background(), s); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(test.exp, m.pullreqIDs); diff != "" {
+				t.Errorf("auto-merged pull requests mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type fakeMerger struct {
+	pullreqIDs []int64
+}
+
+func (f *fakeMerger) AutoMerge(_ context.Context, pullreqID int64) error {
+	f.pullreqIDs = append(f.pullreqIDs, pullreqID)
+	return nil
+}
+
+type fakePullReqStore struct {
+	store.PullReqStore
+	prs map[int64]*types.PullReq
+}
+
+func (f fakePullReqStore) Find(_ context.Context, id int64) (*types.PullReq, error) {
+	return f.prs[id], nil
+}
+
+type fakeAutoMergeStore struct {
+	store.PullReqAutoMergeStore
+	autoMerges map[int64][]*types.PullReqAutoMerge
+}
+
+func (f fakeAutoMergeStore) ListByRepo(_ context.Context, repoID int64) ([]*types.PullReqAutoMerge, error) {
+	return f.autoMerges[repoID], nil
+}
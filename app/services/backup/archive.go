@@ -0,0 +1,299 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const gitRepoSuffix = ".git"
+
+// addFile adds a single regular file to the archive under the provided name.
+func addFile(tw *tar.Writer, filePath, name string) error {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to stat %q: %w", filePath, err)
+	}
+
+	return addEntry(tw, filePath, name, info)
+}
+
+// addDir adds the content of the directory to the archive, with all names prefixed with the provided prefix.
+// If include is provided, only the entries for which it returns true are added.
+// Files that are removed while the directory is being archived are silently skipped.
+func addDir(
+	ctx context.Context,
+	tw *tar.Writer,
+	root string,
+	prefix string,
+	include func(rel string) bool,
+) error {
+	return filepath.WalkDir(root, func(filePath string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		rel = filepath.ToSlash(rel)
+		if include != nil && !include(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return addEntry(tw, filePath, path.Join(prefix, rel), info)
+	})
+}
+
+// addRepos adds all git repositories found in the root directory to the archive.
+// For each repository, its references are archived before the rest of the repository,
+// so that every archived reference points to an archived object.
+func addRepos(ctx context.Context, tw *tar.Writer, root string) error {
+	return filepath.WalkDir(root, func(filePath string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() || !strings.HasSuffix(d.Name(), gitRepoSuffix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+
+		prefix := path.Join(dirRepos, filepath.ToSlash(rel))
+
+		err = addDir(ctx, tw, filePath, prefix, func(rel string) bool {
+			return isRepoRef(rel) && !isLockFile(rel)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to archive references of repository %q: %w", rel, err)
+		}
+
+		err = addDir(ctx, tw, filePath, prefix, func(rel string) bool {
+			return !isRepoRef(rel) && !isLockFile(rel)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to archive repository %q: %w", rel, err)
+		}
+
+		return filepath.SkipDir
+	})
+}
+
+func isRepoRef(rel string) bool {
+	return rel == "HEAD" || rel == "packed-refs" || rel == "refs" || strings.HasPrefix(rel, "refs/")
+}
+
+func isLockFile(rel string) bool {
+	return strings.HasSuffix(rel, ".lock")
+}
+
+func addEntry(tw *tar.Writer, filePath, name string, info fs.FileInfo) error {
+	var link string
+	if info.Mode()&fs.ModeSymlink != 0 {
+		var err error
+		if link, err = os.Readlink(filePath); err != nil {
+			return fmt.Errorf("failed to read link %q: %w", filePath, err)
+		}
+	}
+
+	hdr, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return fmt.Errorf("failed to create archive header for %q: %w", filePath, err)
+	}
+
+	hdr.Name = name
+	hdr.Uname = ""
+	hdr.Gname = ""
+
+	switch hdr.Typeflag {
+	case tar.TypeDir, tar.TypeSymlink:
+		return tw.WriteHeader(hdr)
+	case tar.TypeReg:
+	default:
+		// sockets, devices and other special files are never part of the instance data.
+		return nil
+	}
+
+	f, err := os.Open(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %q: %w", filePath, err)
+	}
+	defer f.Close()
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write archive header for %q: %w", filePath, err)
+	}
+
+	if _, err := io.CopyN(tw, f, hdr.Size); err != nil {
+		return fmt.Errorf("failed to archive %q: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// extractEntry extracts a single archive entry into the root directory.
+// The function mapLink is used to map the targets of symbolic links.
+func extractEntry(
+	root, rel string,
+	hdr *tar.Header,
+	r io.Reader,
+	mapLink func(string) (string, bool),
+) error {
+	// cleaning the path as an absolute one makes sure that the entry can't escape the root directory.
+	rel = strings.TrimPrefix(path.Clean("/"+rel), "/")
+	if rel == "" {
+		return nil
+	}
+
+	target := filepath.Join(root, filepath.FromSlash(rel))
+	mode := fs.FileMode(hdr.Mode).Perm()
+
+	// an earlier entry could have placed a symbolic link on the path, writing through it would escape the root.
+	if err := checkNoSymlinkParent(root, rel); err != nil {
+		return err
+	}
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		if err := os.MkdirAll(target, mode|0o700); err != nil {
+			return fmt.Errorf("failed to create directory %q: %w", target, err)
+		}
+		return nil
+
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+			return fmt.Errorf("failed to create directory for %q: %w", target, err)
+		}
+		return writeFile(target, r, mode)
+
+	case tar.TypeSymlink:
+		link, trusted := mapLink(hdr.Linkname)
+		if !trusted && !linkWithinRoot(root, target, link) {
+			return fmt.Errorf("%w: symbolic link %q points outside of the target directory",
+				ErrInvalidArchive, hdr.Name)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+			return fmt.Errorf("failed to create directory for %q: %w", target, err)
+		}
+		if err := os.Symlink(link, target); err != nil {
+			return fmt.Errorf("failed to create symbolic link %q: %w", target, err)
+		}
+		return nil
+
+	default:
+		return nil
+	}
+}
+
+// checkNoSymlinkParent returns an error if any of the existing parent directories of rel is a symbolic link.
+func checkNoSymlinkParent(root, rel string) error {
+	current := root
+	for _, name := range strings.Split(path.Dir(rel), "/") {
+		if name == "." {
+			break
+		}
+
+		current = filepath.Join(current, name)
+
+		info, err := os.Lstat(current)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check %q: %w", current, err)
+		}
+
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%w: path %q leads through a symbolic link", ErrInvalidArchive, rel)
+		}
+	}
+
+	return nil
+}
+
+// linkWithinRoot returns true if the symbolic link at target pointing to link resolves inside the root directory.
+func linkWithinRoot(root, target, link string) bool {
+	resolved := link
+	if !filepath.IsAbs(link) {
+		resolved = filepath.Join(filepath.Dir(target), link)
+	}
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+func writeFile(filePath string, r io.Reader, mode fs.FileMode) error {
+	f, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return fmt.Errorf("failed to create file %q: %w", filePath, err)
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write file %q: %w", filePath, err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close file %q: %w", filePath, err)
+	}
+
+	return nil
+}
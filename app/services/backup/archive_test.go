@@ -0,0 +1,169 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/tar"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractEntry_Symlinks(t *testing.T) {
+	mapLink := func(link string) (string, bool) {
+		if link == oldHookPath {
+			return newHookPath, true
+		}
+		return link, false
+	}
+
+	tests := []struct {
+		name     string
+		linkName string
+		link     string
+		wantLink string
+		wantErr  error
+	}{
+		{
+			name:     "relative-inside",
+			linkName: "repo.git/hooks/pre-receive",
+			link:     "../objects/hook",
+			wantLink: "../objects/hook",
+		},
+		{
+			name:     "absolute-inside",
+			linkName: "repo.git/hooks/pre-receive",
+			wantLink: "", // set to a path inside the root
+		},
+		{
+			name:     "mapped-hook",
+			linkName: "repo.git/hooks/pre-receive",
+			link:     oldHookPath,
+			wantLink: newHookPath,
+		},
+		{
+			name:     "relative-outside",
+			linkName: "repo.git/hooks/pre-receive",
+			link:     "../../../etc/passwd",
+			wantErr:  ErrInvalidArchive,
+		},
+		{
+			name:     "absolute-outside",
+			linkName: "repo.git/hooks/pre-receive",
+			link:     "/etc/passwd",
+			wantErr:  ErrInvalidArchive,
+		},
+		{
+			name:     "root-parent",
+			linkName: "escape",
+			link:     "..",
+			wantErr:  ErrInvalidArchive,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := t.TempDir()
+
+			link, wantLink := test.link, test.wantLink
+			if link == "" {
+				link = filepath.Join(root, "repo.git", "objects")
+				wantLink = link
+			}
+
+			hdr := &tar.Header{Typeflag: tar.TypeSymlink, Name: test.linkName, Linkname: link}
+
+			err := extractEntry(root, test.linkName, hdr, nil, mapLink)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("error mismatch: want=%v got=%v", test.wantErr, err)
+			}
+			if test.wantErr != nil {
+				if _, err := os.Lstat(filepath.Join(root, test.linkName)); !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("expected the link not to be created, got: %v", err)
+				}
+				return
+			}
+
+			got, err := os.Readlink(filepath.Join(root, test.linkName))
+			if err != nil {
+				t.Fatalf("failed to read link: %s", err)
+			}
+			if got != wantLink {
+				t.Errorf("link mismatch: want=%s got=%s", wantLink, got)
+			}
+		})
+	}
+}
+
+func TestExtractEntry_SymlinkParent(t *testing.T) {
+	noMap := func(link string) (string, bool) { return link, false }
+
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	// a link that points inside the root passes the link check, but later entries mustn't be written through it.
+	if err := os.Symlink(filepath.Join(root, "repo.git"), filepath.Join(root, "link")); err != nil {
+		t.Fatalf("failed to create link: %s", err)
+	}
+	// a link placed by something else than the archive.
+	if err := os.Symlink(outside, filepath.Join(root, "outside")); err != nil {
+		t.Fatalf("failed to create link: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		hdr  *tar.Header
+	}{
+		{
+			name: "file",
+			hdr:  &tar.Header{Typeflag: tar.TypeReg, Name: "outside/file", Mode: 0o600, Size: 4},
+		},
+		{
+			name: "nested-file",
+			hdr:  &tar.Header{Typeflag: tar.TypeReg, Name: "link/objects/file", Mode: 0o600, Size: 4},
+		},
+		{
+			name: "dir",
+			hdr:  &tar.Header{Typeflag: tar.TypeDir, Name: "outside/dir", Mode: 0o700},
+		},
+		{
+			name: "symlink",
+			hdr:  &tar.Header{Typeflag: tar.TypeSymlink, Name: "outside/link", Linkname: "file"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := extractEntry(root, test.hdr.Name, test.hdr, strings.NewReader("data"), noMap)
+			if !errors.Is(err, ErrInvalidArchive) {
+				t.Errorf("expected ErrInvalidArchive, got: %v", err)
+			}
+		})
+	}
+
+	entries, err := os.ReadDir(outside)
+	if err != nil {
+		t.Fatalf("failed to read directory: %s", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected nothing to be written outside of the root, got %d entries", len(entries))
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "repo.git")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected nothing to be written through the link, got: %v", err)
+	}
+}
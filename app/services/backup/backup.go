@@ -0,0 +1,315 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/harness/gitness/app/services/backup/pause"
+	"github.com/harness/gitness/app/services/settings"
+	"github.com/harness/gitness/app/store/database"
+	"github.com/harness/gitness/app/store/database/migrate"
+	"github.com/harness/gitness/version"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// FormatVersion is the version of the archive format produced by Create.
+	FormatVersion = 1
+
+	manifestName = "manifest.json"
+
+	dirDatabase = "database"
+	dirRepos    = "repos"
+	dirBlob     = "blob"
+	dirRegistry = "registry"
+
+	// deletionPauseLease is how long a single extension of the deletion pause lasts.
+	deletionPauseLease = 5 * time.Minute
+)
+
+// DeletionSettleTime is how long Create waits after pausing the deletions before taking the database snapshot.
+// It's longer than the interval in which the background jobs check the pause.
+var DeletionSettleTime = 30 * time.Second
+
+// Component is a part of the instance data stored in a backup archive.
+type Component string
+
+const (
+	ComponentDatabase Component = dirDatabase
+	ComponentRepos    Component = dirRepos
+	ComponentBlob     Component = dirBlob
+	ComponentRegistry Component = dirRegistry
+)
+
+var (
+	ErrNotFresh           = errors.New("the target instance already contains data")
+	ErrInvalidArchive     = errors.New("invalid backup archive")
+	ErrUnsupportedDriver  = errors.New("unsupported database driver")
+	ErrComponentNoStorage = errors.New("no local storage configured for the backed up component")
+)
+
+// Config holds the locations of the instance data.
+type Config struct {
+	DatabaseDriver     string
+	DatabaseDatasource string
+
+	// ReposRoot is the directory containing the git repositories.
+	ReposRoot string
+
+	// HookPath is the path of the git server hook binary the repository hooks point to.
+	HookPath string
+
+	// BlobStoreRoot is the directory of the blob store.
+	// It's empty if the blob store doesn't use the local filesystem.
+	BlobStoreRoot string
+
+	// RegistryRoot is the root directory of the artifact registry storage.
+	// It's empty if the registry doesn't use the local filesystem.
+	RegistryRoot string
+}
+
+// Manifest describes the content of a backup archive. It's always the first entry of the archive.
+type Manifest struct {
+	FormatVersion    int         `json:"format_version"`
+	GitnessVersion   string      `json:"gitness_version"`
+	Created          int64       `json:"created"`
+	DatabaseDriver   string      `json:"database_driver"`
+	MigrationVersion string      `json:"migration_version"`
+	HookPath         string      `json:"hook_path"`
+	Components       []Component `json:"components"`
+}
+
+func (m *Manifest) has(component Component) bool {
+	for _, c := range m.Components {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
+// Create writes a gzip compressed tar archive with the instance data to w.
+//
+// The database is archived first from a consistent snapshot (VACUUM INTO for sqlite, pg_dump for postgres),
+// so the server doesn't have to be stopped. The data on the filesystem is archived after the snapshot,
+// so the deletion of data (repository purge, fork detach, registry garbage collection) is paused
+// for the duration of the backup, which makes sure that the data referenced by the snapshot is still present.
+// Deletions that were already running are given DeletionSettleTime to finish before the snapshot is taken.
+// References of each git repository are archived before its objects, which makes sure that a push running
+// concurrently with the backup can't produce references that point to missing objects.
+func Create(ctx context.Context, config Config, db *sqlx.DB, w io.Writer) (*Manifest, error) {
+	settingsService := settings.NewService(database.NewSettingsStore(db))
+
+	stopPause, err := pauseDeletions(ctx, settingsService)
+	if err != nil {
+		return nil, err
+	}
+	defer stopPause()
+
+	migrationVersion, err := migrate.Current(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the current migration version: %w", err)
+	}
+
+	manifest := &Manifest{
+		FormatVersion:    FormatVersion,
+		GitnessVersion:   version.Version.String(),
+		Created:          time.Now().UnixMilli(),
+		DatabaseDriver:   config.DatabaseDriver,
+		MigrationVersion: migrationVersion,
+		HookPath:         config.HookPath,
+		Components:       []Component{ComponentDatabase, ComponentRepos},
+	}
+	if config.BlobStoreRoot != "" {
+		manifest.Components = append(manifest.Components, ComponentBlob)
+	}
+	if config.RegistryRoot != "" {
+		manifest.Components = append(manifest.Components, ComponentRegistry)
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	if err := writeManifest(tw, manifest); err != nil {
+		return nil, err
+	}
+
+	dumpPath, cleanup, err := dumpDatabase(ctx, config, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump the database: %w", err)
+	}
+	defer cleanup()
+
+	if err := addFile(tw, dumpPath, databaseEntryName(config.DatabaseDriver)); err != nil {
+		return nil, fmt.Errorf("failed to archive the database dump: %w", err)
+	}
+
+	log.Ctx(ctx).Info().Msg("database archived")
+
+	if err := addRepos(ctx, tw, config.ReposRoot); err != nil {
+		return nil, fmt.Errorf("failed to archive git repositories: %w", err)
+	}
+
+	log.Ctx(ctx).Info().Msg("git repositories archived")
+
+	if config.BlobStoreRoot != "" {
+		if err := addDir(ctx, tw, config.BlobStoreRoot, dirBlob, nil); err != nil {
+			return nil, fmt.Errorf("failed to archive the blob store: %w", err)
+		}
+
+		log.Ctx(ctx).Info().Msg("blob store archived")
+	}
+
+	if config.RegistryRoot != "" {
+		if err := addDir(ctx, tw, config.RegistryRoot, dirRegistry, nil); err != nil {
+			return nil, fmt.Errorf("failed to archive the registry storage: %w", err)
+		}
+
+		log.Ctx(ctx).Info().Msg("registry storage archived")
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize the archive: %w", err)
+	}
+
+	if err := gw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize the archive compression: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// pauseDeletions pauses the deletion of data and keeps extending the pause until the returned function is called.
+// The pause is limited in time, so it expires on its own if the backup process is terminated.
+func pauseDeletions(ctx context.Context, settingsService *settings.Service) (func(), error) {
+	if err := pause.Pause(ctx, settingsService, time.Now().Add(deletionPauseLease)); err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Msg("deletions paused, waiting for running deletions to finish")
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(DeletionSettleTime):
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(deletionPauseLease / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				err := pause.Pause(ctx, settingsService, time.Now().Add(deletionPauseLease))
+				if err != nil {
+					log.Ctx(ctx).Warn().Err(err).Msg("failed to extend the deletion pause")
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+
+		// the context could be already canceled, but the pause should be ended anyway.
+		if err := pause.Resume(context.WithoutCancel(ctx), settingsService); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("failed to resume deletions, they resume when the pause expires")
+		}
+	}, nil
+}
+
+func writeManifest(tw *tar.Writer, manifest *Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the manifest: %w", err)
+	}
+
+	err = tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     manifestName,
+		Size:     int64(len(data)),
+		Mode:     0o600,
+		ModTime:  time.UnixMilli(manifest.Created),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write the manifest header: %w", err)
+	}
+
+	if _, err = tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write the manifest: %w", err)
+	}
+
+	return nil
+}
+
+func readManifest(tr *tar.Reader) (*Manifest, error) {
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read the first entry: %w", ErrInvalidArchive, err)
+	}
+	if hdr.Name != manifestName {
+		return nil, fmt.Errorf("%w: the first entry isn't the manifest", ErrInvalidArchive)
+	}
+
+	manifest := &Manifest{}
+	if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode the manifest: %w", ErrInvalidArchive, err)
+	}
+
+	if manifest.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidArchive, manifest.FormatVersion)
+	}
+
+	return manifest, nil
+}
+
+// isEmptyDir returns true if the directory doesn't exist or is empty.
+func isEmptyDir(dir string) (bool, error) {
+	f, err := os.Open(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	_, err = f.Readdirnames(1)
+	if errors.Is(err, io.EOF) {
+		return true, nil
+	}
+
+	return false, err
+}
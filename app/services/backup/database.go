@@ -0,0 +1,212 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+
+	"github.com/harness/gitness/store/database"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	driverSqlite   = "sqlite3"
+	driverPostgres = "postgres"
+
+	databaseFileSqlite   = "gitness.sqlite3"
+	databaseFilePostgres = "gitness.pgdump"
+)
+
+func databaseEntryName(driver string) string {
+	if driver == driverPostgres {
+		return path.Join(dirDatabase, databaseFilePostgres)
+	}
+	return path.Join(dirDatabase, databaseFileSqlite)
+}
+
+// dumpDatabase writes a consistent snapshot of the database to a temporary file.
+// The returned cleanup function removes the file.
+func dumpDatabase(ctx context.Context, config Config, db *sqlx.DB) (string, func(), error) {
+	tmpDir, err := os.MkdirTemp("", "gitness-backup-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create a temporary directory: %w", err)
+	}
+
+	cleanup := func() { _ = os.RemoveAll(tmpDir) }
+
+	switch config.DatabaseDriver {
+	case driverSqlite:
+		dumpPath := filepath.Join(tmpDir, databaseFileSqlite)
+
+		// VACUUM INTO creates a transactionally consistent copy of the database without blocking writers.
+		if _, err = db.ExecContext(ctx, "VACUUM INTO ?", dumpPath); err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("failed to create a copy of the sqlite database: %w", err)
+		}
+
+		return dumpPath, cleanup, nil
+
+	case driverPostgres:
+		dumpPath := filepath.Join(tmpDir, databaseFilePostgres)
+
+		// pg_dump reads all data from a single snapshot, so the dump is consistent without locking writes.
+		//nolint:gosec // the datasource comes from the server configuration.
+		cmd := exec.CommandContext(ctx, "pg_dump",
+			"--format=custom",
+			"--no-owner",
+			"--no-privileges",
+			"--file="+dumpPath,
+			"--dbname="+config.DatabaseDatasource,
+		)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("pg_dump failed: %w: %s", err, output)
+		}
+
+		return dumpPath, cleanup, nil
+
+	default:
+		cleanup()
+		return "", nil, fmt.Errorf("%w: %s", ErrUnsupportedDriver, config.DatabaseDriver)
+	}
+}
+
+// checkDatabaseEmpty returns ErrNotFresh if the target database already contains data.
+func checkDatabaseEmpty(ctx context.Context, config Config) error {
+	switch config.DatabaseDriver {
+	case driverSqlite:
+		filePath, err := sqliteFilePath(config.DatabaseDatasource)
+		if err != nil {
+			return err
+		}
+
+		info, err := os.Stat(filePath)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check the sqlite database file: %w", err)
+		}
+		if info.Size() > 0 {
+			return fmt.Errorf("%w: sqlite database file %q already exists", ErrNotFresh, filePath)
+		}
+
+		return nil
+
+	case driverPostgres:
+		db, err := database.Connect(ctx, config.DatabaseDriver, config.DatabaseDatasource)
+		if err != nil {
+			return fmt.Errorf("failed to connect to the database: %w", err)
+		}
+		defer db.Close()
+
+		var count int
+		err = db.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema()").
+			Scan(&count)
+		if err != nil {
+			return fmt.Errorf("failed to count database tables: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: the postgres database isn't empty", ErrNotFresh)
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedDriver, config.DatabaseDriver)
+	}
+}
+
+// restoreDatabase restores the database from the dump read from r.
+func restoreDatabase(ctx context.Context, config Config, r io.Reader) error {
+	switch config.DatabaseDriver {
+	case driverSqlite:
+		filePath, err := sqliteFilePath(config.DatabaseDatasource)
+		if err != nil {
+			return err
+		}
+
+		if dir := filepath.Dir(filePath); dir != "" {
+			if err := os.MkdirAll(dir, 0o700); err != nil {
+				return fmt.Errorf("failed to create the database directory: %w", err)
+			}
+		}
+
+		// an empty database file might have been created by the sqlite driver, it's safe to replace it.
+		if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove the empty database file: %w", err)
+		}
+
+		return writeFile(filePath, r, 0o600)
+
+	case driverPostgres:
+		tmpDir, err := os.MkdirTemp("", "gitness-restore-")
+		if err != nil {
+			return fmt.Errorf("failed to create a temporary directory: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
+
+		dumpPath := filepath.Join(tmpDir, databaseFilePostgres)
+		if err := writeFile(dumpPath, r, 0o600); err != nil {
+			return err
+		}
+
+		//nolint:gosec // the datasource comes from the server configuration.
+		cmd := exec.CommandContext(ctx, "pg_restore",
+			"--no-owner",
+			"--no-privileges",
+			"--exit-on-error",
+			"--dbname="+config.DatabaseDatasource,
+			dumpPath,
+		)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("pg_restore failed: %w: %s", err, output)
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedDriver, config.DatabaseDriver)
+	}
+}
+
+// sqliteFilePath extracts the path of the database file from the sqlite datasource.
+func sqliteFilePath(datasource string) (string, error) {
+	u, err := url.Parse(datasource)
+	if err != nil {
+		return "", fmt.Errorf("datasource is of invalid format for driver sqlite3: %w", err)
+	}
+
+	filePath := u.Path
+	if u.Opaque != "" {
+		filePath = u.Opaque
+	}
+
+	if filePath == "" || filePath == ":memory:" {
+		return "", fmt.Errorf("sqlite datasource %q doesn't point to a database file", datasource)
+	}
+
+	return filePath, nil
+}
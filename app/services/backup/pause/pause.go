@@ -0,0 +1,67 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pause pauses the deletion of instance data while a backup is being created.
+// The backup is created by a separate process, so the pause is stored as a system setting in the database,
+// which makes it visible to all server instances.
+package pause
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/app/services/settings"
+)
+
+// settingKeyDeletionsPausedUntil is the system setting that holds the time (unix ms)
+// until which the deletions are paused.
+const settingKeyDeletionsPausedUntil settings.Key = "backup_deletions_paused_until"
+
+// ErrDeletionsPaused is returned by Check while the deletions are paused.
+var ErrDeletionsPaused = errors.New("deletions are paused while a backup is being created")
+
+// Pause pauses the deletions until the provided time. Calling it again extends the pause.
+func Pause(ctx context.Context, settingsService *settings.Service, until time.Time) error {
+	if err := settingsService.SystemSet(ctx, settingKeyDeletionsPausedUntil, until.UnixMilli()); err != nil {
+		return fmt.Errorf("failed to pause deletions: %w", err)
+	}
+
+	return nil
+}
+
+// Resume ends the pause of the deletions.
+func Resume(ctx context.Context, settingsService *settings.Service) error {
+	if err := settingsService.SystemSet(ctx, settingKeyDeletionsPausedUntil, 0); err != nil {
+		return fmt.Errorf("failed to resume deletions: %w", err)
+	}
+
+	return nil
+}
+
+// Check returns ErrDeletionsPaused if the deletions are paused.
+// A pause that wasn't ended, e.g. because the backup process crashed, expires at its end time.
+func Check(ctx context.Context, settingsService *settings.Service) error {
+	var until int64
+	if _, err := settingsService.SystemGet(ctx, settingKeyDeletionsPausedUntil, &until); err != nil {
+		return fmt.Errorf("failed to check if deletions are paused: %w", err)
+	}
+
+	if time.Now().UnixMilli() < until {
+		return ErrDeletionsPaused
+	}
+
+	return nil
+}
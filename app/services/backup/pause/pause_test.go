@@ -0,0 +1,103 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pause
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/services/settings"
+	appstore "github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types/enum"
+)
+
+type settingsStore struct {
+	appstore.SettingsStore
+	values map[string]json.RawMessage
+}
+
+func (s *settingsStore) Find(_ context.Context, _ enum.SettingsScope, _ int64, key string) (json.RawMessage, error) {
+	value, ok := s.values[key]
+	if !ok {
+		return nil, store.ErrResourceNotFound
+	}
+	return value, nil
+}
+
+func (s *settingsStore) Upsert(
+	_ context.Context,
+	_ enum.SettingsScope,
+	_ int64,
+	key string,
+	value json.RawMessage,
+) error {
+	s.values[key] = value
+	return nil
+}
+
+func TestPause(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		setup   func(svc *settings.Service) error
+		wantErr error
+	}{
+		{
+			name:  "never-paused",
+			setup: func(*settings.Service) error { return nil },
+		},
+		{
+			name: "paused",
+			setup: func(svc *settings.Service) error {
+				return Pause(ctx, svc, time.Now().Add(time.Minute))
+			},
+			wantErr: ErrDeletionsPaused,
+		},
+		{
+			name: "pause-expired",
+			setup: func(svc *settings.Service) error {
+				return Pause(ctx, svc, time.Now().Add(-time.Second))
+			},
+		},
+		{
+			name: "resumed",
+			setup: func(svc *settings.Service) error {
+				if err := Pause(ctx, svc, time.Now().Add(time.Minute)); err != nil {
+					return err
+				}
+				return Resume(ctx, svc)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := settings.NewService(&settingsStore{values: map[string]json.RawMessage{}})
+
+			if err := test.setup(svc); err != nil {
+				t.Fatalf("failed to setup: %s", err)
+			}
+
+			if err := Check(ctx, svc); !errors.Is(err, test.wantErr) {
+				t.Errorf("error mismatch: want=%v got=%v", test.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Restore restores the instance data from the archive read from r. The target instance must be fresh:
+// The database and all the storage directories of the components in the archive must be empty.
+// It's expected that the server isn't running while the data is restored.
+func Restore(ctx context.Context, config Config, r io.Reader) (*Manifest, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+
+	manifest, err := readManifest(tr)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.DatabaseDriver != config.DatabaseDriver {
+		return nil, fmt.Errorf("%w: the archive contains a %s database, but the server is configured to use %s",
+			ErrUnsupportedDriver, manifest.DatabaseDriver, config.DatabaseDriver)
+	}
+
+	roots, err := componentRoots(config, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkFresh(ctx, config, roots); err != nil {
+		return nil, err
+	}
+
+	// the hooks of the restored repositories must point to the hook binary of the new server.
+	// It's the only link target outside of the restored directories that is accepted.
+	mapLink := func(link string) (string, bool) {
+		if manifest.HookPath != "" && config.HookPath != "" && link == manifest.HookPath {
+			return config.HookPath, true
+		}
+		return link, false
+	}
+
+	var databaseRestored bool
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to read the next entry: %w", ErrInvalidArchive, err)
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		component, rel, _ := strings.Cut(hdr.Name, "/")
+
+		if Component(component) == ComponentDatabase {
+			if hdr.Name != databaseEntryName(manifest.DatabaseDriver) {
+				return nil, fmt.Errorf("%w: unexpected database entry %q", ErrInvalidArchive, hdr.Name)
+			}
+
+			if err := restoreDatabase(ctx, config, tr); err != nil {
+				return nil, fmt.Errorf("failed to restore the database: %w", err)
+			}
+
+			databaseRestored = true
+
+			log.Ctx(ctx).Info().Msg("database restored")
+
+			continue
+		}
+
+		root, ok := roots[Component(component)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unexpected entry %q", ErrInvalidArchive, hdr.Name)
+		}
+
+		if err := extractEntry(root, rel, hdr, tr, mapLink); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", component, err)
+		}
+	}
+
+	if !databaseRestored {
+		return nil, fmt.Errorf("%w: the archive doesn't contain the database", ErrInvalidArchive)
+	}
+
+	return manifest, nil
+}
+
+// componentRoots returns the target directories of the file based components in the archive.
+func componentRoots(config Config, manifest *Manifest) (map[Component]string, error) {
+	roots := map[Component]string{}
+
+	for _, c := range []struct {
+		component Component
+		root      string
+	}{
+		{component: ComponentRepos, root: config.ReposRoot},
+		{component: ComponentBlob, root: config.BlobStoreRoot},
+		{component: ComponentRegistry, root: config.RegistryRoot},
+	} {
+		if !manifest.has(c.component) {
+			continue
+		}
+
+		if c.root == "" {
+			return nil, fmt.Errorf("%w: %s", ErrComponentNoStorage, c.component)
+		}
+
+		roots[c.component] = c.root
+	}
+
+	return roots, nil
+}
+
+// checkFresh returns ErrNotFresh if the database or any of the component directories already contain data.
+func checkFresh(ctx context.Context, config Config, roots map[Component]string) error {
+	if err := checkDatabaseEmpty(ctx, config); err != nil {
+		return err
+	}
+
+	for component, root := range roots {
+		empty, err := isEmptyDir(root)
+		if err != nil {
+			return fmt.Errorf("failed to check the %s directory: %w", component, err)
+		}
+		if !empty {
+			return fmt.Errorf("%w: the %s directory %q isn't empty", ErrNotFresh, component, root)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/harness/gitness/app/store/database/migrate"
+	"github.com/harness/gitness/store/database"
+)
+
+const (
+	oldHookPath = "/old/gitness"
+	newHookPath = "/new/gitness"
+)
+
+func TestCreateAndRestore(t *testing.T) {
+	ctx := context.Background()
+
+	src := newTestConfig(t, oldHookPath)
+
+	db, err := database.ConnectAndMigrate(ctx, src.DatabaseDriver, src.DatabaseDatasource, migrate.Migrate)
+	if err != nil {
+		t.Fatalf("failed to setup the source database: %s", err)
+	}
+	defer db.Close()
+
+	if _, err = db.ExecContext(ctx, `INSERT INTO settings (setting_space_id, setting_repo_id, setting_key,
+		setting_value) VALUES (NULL, NULL, 'backup_test', '"ok"')`); err != nil {
+		t.Fatalf("failed to insert test data: %s", err)
+	}
+
+	repoDir := filepath.Join(src.ReposRoot, "ab", "cd", "efgh.git")
+	writeTestFile(t, filepath.Join(repoDir, "HEAD"), "ref: refs/heads/main\n")
+	writeTestFile(t, filepath.Join(repoDir, "refs", "heads", "main"), "0123456789abcdef\n")
+	writeTestFile(t, filepath.Join(repoDir, "objects", "01", "23456789abcdef"), "object")
+	writeTestFile(t, filepath.Join(repoDir, "refs", "heads", "other.lock"), "lock")
+	if err := os.MkdirAll(filepath.Join(repoDir, "hooks"), 0o700); err != nil {
+		t.Fatalf("failed to create hooks directory: %s", err)
+	}
+	if err := os.Symlink(oldHookPath, filepath.Join(repoDir, "hooks", "pre-receive")); err != nil {
+		t.Fatalf("failed to create hook: %s", err)
+	}
+
+	writeTestFile(t, filepath.Join(src.BlobStoreRoot, "lfs", "object"), "blob")
+	writeTestFile(t, filepath.Join(src.RegistryRoot, "docker", "registry", "blob"), "layer")
+
+	archive := &bytes.Buffer{}
+
+	created, err := Create(ctx, src, db, archive)
+	if err != nil {
+		t.Fatalf("failed to create backup: %s", err)
+	}
+
+	dst := newTestConfig(t, newHookPath)
+
+	// a freshly started server creates an empty repos directory, which mustn't prevent the restore.
+	if err := os.MkdirAll(dst.ReposRoot, 0o700); err != nil {
+		t.Fatalf("failed to create repos directory: %s", err)
+	}
+
+	restored, err := Restore(ctx, dst, bytes.NewReader(archive.Bytes()))
+	if err != nil {
+		t.Fatalf("failed to restore backup: %s", err)
+	}
+
+	if want, got := created.MigrationVersion, restored.MigrationVersion; want != got {
+		t.Errorf("migration version mismatch: want=%s got=%s", want, got)
+	}
+
+	dstRepoDir := filepath.Join(dst.ReposRoot, "ab", "cd", "efgh.git")
+	expectTestFile(t, filepath.Join(dstRepoDir, "HEAD"), "ref: refs/heads/main\n")
+	expectTestFile(t, filepath.Join(dstRepoDir, "refs", "heads", "main"), "0123456789abcdef\n")
+	expectTestFile(t, filepath.Join(dstRepoDir, "objects", "01", "23456789abcdef"), "object")
+	expectTestFile(t, filepath.Join(dst.BlobStoreRoot, "lfs", "object"), "blob")
+	expectTestFile(t, filepath.Join(dst.RegistryRoot, "docker", "registry", "blob"), "layer")
+
+	if _, err := os.Lstat(filepath.Join(dstRepoDir, "refs", "heads", "other.lock")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected lock file not to be restored, got: %v", err)
+	}
+
+	link, err := os.Readlink(filepath.Join(dstRepoDir, "hooks", "pre-receive"))
+	if err != nil {
+		t.Fatalf("failed to read restored hook: %s", err)
+	}
+	if link != newHookPath {
+		t.Errorf("hook link mismatch: want=%s got=%s", newHookPath, link)
+	}
+
+	dstDB, err := database.Connect(ctx, dst.DatabaseDriver, dst.DatabaseDatasource)
+	if err != nil {
+		t.Fatalf("failed to open the restored database: %s", err)
+	}
+	defer dstDB.Close()
+
+	var value string
+	err = dstDB.QueryRowContext(ctx, `SELECT setting_value FROM settings WHERE setting_key = 'backup_test'`).
+		Scan(&value)
+	if err != nil {
+		t.Fatalf("failed to read restored data: %s", err)
+	}
+	if value != `"ok"` {
+		t.Errorf("restored setting mismatch: want=%q got=%q", `"ok"`, value)
+	}
+
+	// restoring onto an instance that already has data must fail.
+	_, err = Restore(ctx, dst, bytes.NewReader(archive.Bytes()))
+	if !errors.Is(err, ErrNotFresh) {
+		t.Errorf("expected ErrNotFresh, got: %v", err)
+	}
+}
+
+func TestRestore_Validation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid-archive", func(t *testing.T) {
+		_, err := Restore(ctx, newTestConfig(t, newHookPath), bytes.NewReader([]byte("not an archive")))
+		if !errors.Is(err, ErrInvalidArchive) {
+			t.Errorf("expected ErrInvalidArchive, got: %v", err)
+		}
+	})
+
+	t.Run("missing-storage", func(t *testing.T) {
+		src := newTestConfig(t, oldHookPath)
+
+		db, err := database.ConnectAndMigrate(ctx, src.DatabaseDriver, src.DatabaseDatasource, migrate.Migrate)
+		if err != nil {
+			t.Fatalf("failed to setup the source database: %s", err)
+		}
+		defer db.Close()
+
+		archive := &bytes.Buffer{}
+		if _, err := Create(ctx, src, db, archive); err != nil {
+			t.Fatalf("failed to create backup: %s", err)
+		}
+
+		dst := newTestConfig(t, newHookPath)
+		dst.RegistryRoot = ""
+
+		_, err = Restore(ctx, dst, archive)
+		if !errors.Is(err, ErrComponentNoStorage) {
+			t.Errorf("expected ErrComponentNoStorage, got: %v", err)
+		}
+
+		if _, err := os.Stat(dst.DatabaseDatasource); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected no database to be restored, got: %v", err)
+		}
+	})
+}
+
+func newTestConfig(t *testing.T, hookPath string) Config {
+	root := t.TempDir()
+	return Config{
+		DatabaseDriver:     driverSqlite,
+		DatabaseDatasource: filepath.Join(root, "database.sqlite3"),
+		ReposRoot:          filepath.Join(root, "git", "repos"),
+		HookPath:           hookPath,
+		BlobStoreRoot:      filepath.Join(root, "blob"),
+		RegistryRoot:       filepath.Join(root, "registry"),
+	}
+}
+
+func writeTestFile(t *testing.T, filePath, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o700); err != nil {
+		t.Fatalf("failed to create directory: %s", err)
+	}
+
+	if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write file: %s", err)
+	}
+}
+
+func expectTestFile(t *testing.T, filePath, content string) {
+	t.Helper()
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Errorf("failed to read %s: %s", filePath, err)
+		return
+	}
+
+	if string(data) != content {
+		t.Errorf("content mismatch of %s: want=%q got=%q", filePath, content, string(data))
+	}
+}
@@ -22,12 +22,14 @@ import (
 
 	"github.com/harness/gitness/app/api/controller/repo"
 	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/audit"
 	"github.com/harness/gitness/job"
 )
 
 type Config struct {
 	WebhookExecutionsRetentionTime   time.Duration
 	DeletedRepositoriesRetentionTime time.Duration
+	AuditEventsRetentionTime         time.Duration
 }
 
 func (c *Config) Prepare() error {
@@ -41,6 +43,10 @@ func (c *Config) Prepare() error {
 	if c.DeletedRepositoriesRetentionTime <= 0 {
 		return errors.New("config.DeletedRepositoriesRetentionTime has to be provided")
 	}
+
+	if c.AuditEventsRetentionTime <= 0 {
+		return errors.New("config.AuditEventsRetentionTime has to be provided")
+	}
 	return nil
 }
 
@@ -53,6 +59,7 @@ type Service struct {
 	tokenStore            store.TokenStore
 	repoStore             store.RepoStore
 	repoCtrl              *repo.Controller
+	auditStore            audit.Store
 }
 
 func NewService(
@@ -63,6 +70,7 @@ func NewService(
 	tokenStore store.TokenStore,
 	repoStore store.RepoStore,
 	repoCtrl *repo.Controller,
+	auditStore audit.Store,
 ) (*Service, error) {
 	if err := config.Prepare(); err != nil {
 		return nil, fmt.Errorf("provided cleanup config is invalid: %w", err)
@@ -77,6 +85,7 @@ func NewService(
 		tokenStore:            tokenStore,
 		repoStore:             repoStore,
 		repoCtrl:              repoCtrl,
+		auditStore:            auditStore,
 	}, nil
 }
 
@@ -126,6 +135,17 @@ func (s *Service) scheduleRecurringCleanupJobs(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to schedule deleted repo cleanup job: %w", err)
 	}
+
+	err = s.scheduler.AddRecurring(
+		ctx,
+		jobTypeAuditEvents,
+		jobTypeAuditEvents,
+		jobCronAuditEvents,
+		jobMaxDurationAuditEvents,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to schedule audit events cleanup job: %w", err)
+	}
 	return nil
 }
 
@@ -160,5 +180,15 @@ func (s *Service) registerJobHandlers() error {
 	); err != nil {
 		return fmt.Errorf("failed to register job handler for deleted repos cleanup: %w", err)
 	}
+
+	if err := s.executor.Register(
+		jobTypeAuditEvents,
+		newAuditEventsCleanupJob(
+			s.config.AuditEventsRetentionTime,
+			s.auditStore,
+		),
+	); err != nil {
+		return fmt.Errorf("failed to register job handler for audit events cleanup: %w", err)
+	}
 	return nil
 }
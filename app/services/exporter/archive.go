@@ -0,0 +1,848 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/harness/gitness/app/services/migrate"
+	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/app/services/publicaccess"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	migratetypes "github.com/harness/harness-migrate/types"
+	"github.com/rs/zerolog/log"
+)
+
+const archivePageSize = 100
+
+// Archive writes a space into a self-contained archive that can be imported into another instance.
+// Repositories are stored as git bundles and all other data is stored in the formats of the migrate package.
+type Archive struct {
+	git                  git.Interface
+	repoStore            store.RepoStore
+	principalInfoCache   store.PrincipalInfoCache
+	pullReqStore         store.PullReqStore
+	activityStore        store.PullReqActivityStore
+	reviewerStore        store.PullReqReviewerStore
+	labelStore           store.LabelStore
+	labelValueStore      store.LabelValueStore
+	labelAssignmentStore store.PullReqLabelAssignmentStore
+	ruleStore            store.RuleStore
+	webhookStore         store.WebhookStore
+	pipelineStore        store.PipelineStore
+	triggerStore         store.TriggerStore
+	publicAccess         publicaccess.Service
+}
+
+// Export writes the archive of the space as a gzip compressed tarball to the writer.
+func (a *Archive) Export(ctx context.Context, space *types.Space, w io.Writer) error {
+	repos, err := listAll(func(page int) ([]*types.Repository, error) {
+		return a.repoStore.List(ctx, space.ID, &types.RepoFilter{
+			Page:  page,
+			Size:  archivePageSize,
+			Sort:  enum.RepoAttrIdentifier,
+			Order: enum.OrderAsc,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "space-export-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() {
+		if errRemove := os.RemoveAll(tmpDir); errRemove != nil {
+			log.Ctx(ctx).Warn().Err(errRemove).Msg("failed to remove temporary export directory")
+		}
+	}()
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	extSpace := migrate.ExternalSpace{
+		Version:     migrate.ArchiveVersion,
+		Identifier:  space.Identifier,
+		Description: space.Description,
+		Created:     time.UnixMilli(space.Created),
+	}
+
+	for _, repo := range repos {
+		if repo.State != enum.RepoStateActive {
+			log.Ctx(ctx).Warn().Msgf("skipping repository '%s' in state %s", repo.Identifier, repo.State)
+			continue
+		}
+
+		skipped, err := a.exportRepository(ctx, tw, tmpDir, repo)
+		if err != nil {
+			return fmt.Errorf("failed to export repository '%s': %w", repo.Identifier, err)
+		}
+
+		extSpace.Repositories = append(extSpace.Repositories, repo.Identifier)
+		extSpace.Skipped = append(extSpace.Skipped, skipped...)
+	}
+
+	skipped, err := a.listSpaceSkipped(ctx, space)
+	if err != nil {
+		return err
+	}
+
+	extSpace.Skipped = append(extSpace.Skipped, skipped...)
+
+	labels, err := a.exportLabels(ctx, &space.ID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to export space labels: %w", err)
+	}
+
+	if err := writeArchiveJSON(tw, migrate.ArchiveLabelsFileName, labels); err != nil {
+		return err
+	}
+
+	if err := writeArchiveJSON(tw, migrate.ArchiveSpaceFileName, extSpace); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+
+	if err := gw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive compression: %w", err)
+	}
+
+	return nil
+}
+
+func (a *Archive) exportRepository(
+	ctx context.Context,
+	tw *tar.Writer,
+	tmpDir string,
+	repo *types.Repository,
+) ([]migrate.ExternalSkipped, error) {
+	prefix := path.Join(migrate.ArchiveReposDir, repo.Identifier)
+
+	isPublic, err := a.publicAccess.Get(ctx, enum.PublicResourceTypeRepo, repo.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check repo public access: %w", err)
+	}
+
+	visibility := migratetypes.VisibilityPrivate
+	if isPublic {
+		visibility = migratetypes.VisibilityPublic
+	}
+
+	info := migrate.ExternalRepositoryInfo{
+		ExternalRepository: migrate.ExternalRepository{
+			Slug:       repo.Path,
+			ID:         strconv.FormatInt(repo.ID, 10),
+			Name:       repo.Identifier,
+			Branch:     repo.DefaultBranch,
+			Private:    !isPublic,
+			Visibility: visibility,
+			Created:    time.UnixMilli(repo.Created),
+			Updated:    time.UnixMilli(repo.Updated),
+			IsEmpty:    repo.IsEmpty,
+		},
+		Description: repo.Description,
+	}
+
+	if !repo.IsEmpty {
+		bundlePath := filepath.Join(tmpDir, repo.GitUID+".bundle")
+
+		err = a.git.CreateBundle(ctx, &git.CreateBundleParams{
+			ReadParams: git.ReadParams{RepoUID: repo.GitUID},
+			Path:       bundlePath,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create git bundle: %w", err)
+		}
+
+		err = writeArchiveFile(tw, path.Join(prefix, migrate.ArchiveBundleFileName), bundlePath)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := os.Remove(bundlePath); err != nil {
+			return nil, fmt.Errorf("failed to remove git bundle: %w", err)
+		}
+	}
+
+	if err := writeArchiveJSON(tw, path.Join(prefix, migrate.ArchiveInfoFileName), info); err != nil {
+		return nil, err
+	}
+
+	if err := a.exportPullRequests(ctx, tw, prefix, repo); err != nil {
+		return nil, fmt.Errorf("failed to export pull requests: %w", err)
+	}
+
+	labels, err := a.exportLabels(ctx, nil, &repo.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export labels: %w", err)
+	}
+
+	if err := writeArchiveJSON(tw, path.Join(prefix, migrate.ArchiveLabelsFileName), labels); err != nil {
+		return nil, err
+	}
+
+	rules, skippedRules, err := a.exportRules(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export rules: %w", err)
+	}
+
+	if err := writeArchiveJSON(tw, path.Join(prefix, migrate.ArchiveBranchRulesFileName), rules); err != nil {
+		return nil, err
+	}
+
+	webhooks, skippedWebhooks, err := a.exportWebhooks(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export webhooks: %w", err)
+	}
+
+	if err := writeArchiveJSON(tw, path.Join(prefix, migrate.ArchiveWebhookFileName), webhooks); err != nil {
+		return nil, err
+	}
+
+	pipelines, err := a.exportPipelines(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export pipelines: %w", err)
+	}
+
+	if err := writeArchiveJSON(tw, path.Join(prefix, migrate.ArchivePipelinesFileName), pipelines); err != nil {
+		return nil, err
+	}
+
+	return append(skippedRules, skippedWebhooks...), nil
+}
+
+func (a *Archive) exportPullRequests(
+	ctx context.Context,
+	tw *tar.Writer,
+	prefix string,
+	repo *types.Repository,
+) error {
+	pullReqs, err := listAll(func(page int) ([]*types.PullReq, error) {
+		return a.pullReqStore.List(ctx, &types.PullReqFilter{
+			Page:         page,
+			Size:         archivePageSize,
+			TargetRepoID: repo.ID,
+			Sort:         enum.PullReqSortNumber,
+			Order:        enum.OrderAsc,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	reviews := make([]migrate.ExternalReview, 0)
+
+	for _, pullReq := range pullReqs {
+		// The source branch of an open pull request from a fork doesn't exist in the target repository,
+		// so it's impossible to import it.
+		if pullReq.SourceRepoID != pullReq.TargetRepoID && pullReq.State == enum.PullReqStateOpen {
+			log.Ctx(ctx).Warn().Msgf("skipping open pull request #%d of repository '%s' from a fork",
+				pullReq.Number, repo.Identifier)
+			continue
+		}
+
+		extPullReq, err := a.convertPullReq(ctx, pullReq)
+		if err != nil {
+			return fmt.Errorf("failed to convert pull request #%d: %w", pullReq.Number, err)
+		}
+
+		name := path.Join(prefix, migrate.ArchivePullRequestDir, strconv.FormatInt(pullReq.Number, 10)+".json")
+		if err := writeArchiveJSON(tw, name, extPullReq); err != nil {
+			return err
+		}
+
+		reviewers, err := a.reviewerStore.List(ctx, pullReq.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list reviewers of pull request #%d: %w", pullReq.Number, err)
+		}
+
+		for _, reviewer := range reviewers {
+			reviews = append(reviews, migrate.ExternalReview{
+				PullRequest: int(pullReq.Number),
+				Reviewer:    convertPrincipalInfo(&reviewer.Reviewer),
+				Type:        string(reviewer.Type),
+				Decision:    string(reviewer.ReviewDecision),
+				SHA:         reviewer.SHA,
+				Created:     time.UnixMilli(reviewer.Created),
+				Updated:     time.UnixMilli(reviewer.Updated),
+			})
+		}
+	}
+
+	return writeArchiveJSON(tw, path.Join(prefix, migrate.ArchiveReviewsFileName), reviews)
+}
+
+func (a *Archive) convertPullReq(
+	ctx context.Context,
+	pullReq *types.PullReq,
+) (*migrate.ExternalPullRequest, error) {
+	author, err := a.findUser(ctx, pullReq.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pull request author: %w", err)
+	}
+
+	assigned, err := a.labelAssignmentStore.ListAssigned(ctx, pullReq.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assigned labels: %w", err)
+	}
+
+	labels := make([]migrate.ExternalLabel, 0, len(assigned))
+	for _, assignment := range assigned {
+		label := migrate.ExternalLabel{
+			Name:  assignment.Key,
+			Color: migrate.LabelColorHex(assignment.Color),
+		}
+		if assignment.AssignedValue != nil && assignment.AssignedValue.Value != nil {
+			label.Value = *assignment.AssignedValue.Value
+		}
+		labels = append(labels, label)
+	}
+
+	comments, err := a.convertComments(ctx, pullReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert comments: %w", err)
+	}
+
+	return &migrate.ExternalPullRequest{
+		PullRequest: migratetypes.PullRequest{
+			Number: int(pullReq.Number),
+			Title:  pullReq.Title,
+			Body:   pullReq.Description,
+			SHA:    pullReq.SourceSHA,
+			Source: pullReq.SourceBranch,
+			Target: pullReq.TargetBranch,
+			Draft:  pullReq.IsDraft,
+			Closed: pullReq.State != enum.PullReqStateOpen,
+			Merged: pullReq.State == enum.PullReqStateMerged,
+			Base: migrate.ExternalReference{
+				Name: pullReq.TargetBranch,
+				SHA:  pullReq.MergeBaseSHA,
+			},
+			Head: migrate.ExternalReference{
+				Name: pullReq.SourceBranch,
+				SHA:  pullReq.SourceSHA,
+			},
+			Author:  author,
+			Created: time.UnixMilli(pullReq.Created),
+			Updated: time.UnixMilli(pullReq.Updated),
+			Labels:  labels,
+		},
+		Comments: comments,
+	}, nil
+}
+
+func (a *Archive) convertComments(
+	ctx context.Context,
+	pullReq *types.PullReq,
+) ([]migrate.ExternalComment, error) {
+	activities, err := a.activityStore.List(ctx, pullReq.ID, &types.PullReqActivityFilter{
+		Kinds: []enum.PullReqActivityKind{
+			enum.PullReqActivityKindComment,
+			enum.PullReqActivityKindChangeComment,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull request activities: %w", err)
+	}
+
+	comments := make([]migrate.ExternalComment, 0, len(activities))
+	for _, activity := range activities {
+		if activity.Deleted != nil {
+			continue
+		}
+
+		author, err := a.findUser(ctx, activity.CreatedBy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find comment author: %w", err)
+		}
+
+		comment := migrate.ExternalComment{
+			ID:      int(activity.ID),
+			Body:    activity.Text,
+			Author:  author,
+			Created: time.UnixMilli(activity.Created),
+			Updated: time.UnixMilli(activity.Updated),
+		}
+
+		if activity.ParentID != nil {
+			comment.ParentID = int(*activity.ParentID)
+		}
+
+		if activity.IsValidCodeComment() {
+			comment.CodeComment = convertCodeComment(activity)
+		}
+
+		comments = append(comments, comment)
+	}
+
+	return comments, nil
+}
+
+func convertCodeComment(activity *types.PullReqActivity) *migrate.ExternalCodeComment {
+	cc := activity.CodeComment
+
+	codeComment := &migrate.ExternalCodeComment{
+		Path: cc.Path,
+		Side: "NEW",
+		HunkHeader: fmt.Sprintf("@@ -%d,%d +%d,%d @@",
+			cc.LineOld, cc.SpanOld, cc.LineNew, cc.SpanNew),
+		SourceSHA:    cc.SourceSHA,
+		MergeBaseSHA: cc.MergeBaseSHA,
+		Outdated:     cc.Outdated,
+	}
+
+	payload := &types.PullRequestActivityPayloadCodeComment{}
+	if err := json.Unmarshal(activity.PayloadRaw, payload); err == nil {
+		codeComment.CodeSnippet = migrate.ExternalHunk{
+			Header: payload.Title,
+			Lines:  payload.Lines,
+		}
+		if !payload.LineStartNew {
+			codeComment.Side = "OLD"
+		}
+	}
+
+	return codeComment
+}
+
+// exportLabels returns the labels defined either in the space or in the repository.
+// A label with multiple values results in one external label per value.
+func (a *Archive) exportLabels(
+	ctx context.Context,
+	spaceID, repoID *int64,
+) ([]*migrate.ExternalLabel, error) {
+	labels, err := listAll(func(page int) ([]*types.Label, error) {
+		return a.labelStore.List(ctx, spaceID, repoID, &types.LabelFilter{
+			ListQueryFilter: types.ListQueryFilter{
+				Pagination: types.Pagination{Page: page, Size: archivePageSize},
+			},
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+
+	extLabels := make([]*migrate.ExternalLabel, 0, len(labels))
+	for _, label := range labels {
+		values, err := listAll(func(page int) ([]*types.LabelValue, error) {
+			return a.labelValueStore.List(ctx, label.ID, &types.ListQueryFilter{
+				Pagination: types.Pagination{Page: page, Size: archivePageSize},
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list values of label '%s': %w", label.Key, err)
+		}
+
+		extLabel := migrate.ExternalLabel{
+			Name:        label.Key,
+			Description: label.Description,
+			Color:       migrate.LabelColorHex(label.Color),
+		}
+
+		if len(values) == 0 {
+			extLabels = append(extLabels, &extLabel)
+			continue
+		}
+
+		for _, value := range values {
+			extValue := extLabel
+			extValue.Value = value.Value
+			extLabels = append(extLabels, &extValue)
+		}
+	}
+
+	return extLabels, nil
+}
+
+// exportRules returns the branch rules of the repository.
+// Rules of other types can't be expressed in the migrate format and are reported as skipped.
+func (a *Archive) exportRules(
+	ctx context.Context,
+	repo *types.Repository,
+) ([]*migrate.ExternalRule, []migrate.ExternalSkipped, error) {
+	rules, err := listAll(func(page int) ([]types.Rule, error) {
+		return a.ruleStore.List(ctx,
+			[]types.RuleParentInfo{{Type: enum.RuleParentRepo, ID: repo.ID}},
+			&types.RuleFilter{
+				ListQueryFilter: types.ListQueryFilter{
+					Pagination: types.Pagination{Page: page, Size: archivePageSize},
+				},
+			})
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list rules: %w", err)
+	}
+
+	var skipped []migrate.ExternalSkipped
+
+	extRules := make([]*migrate.ExternalRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Type != protection.TypeBranch {
+			skipped = append(skipped, skip(ctx, repo.Identifier, migrate.ArchiveSkippedRule, rule.Identifier,
+				fmt.Sprintf("rules of type %s aren't supported by the archive format", rule.Type)))
+			continue
+		}
+
+		var def protection.Branch
+		if err := json.Unmarshal(rule.Definition, &def); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal definition of rule '%s': %w", rule.Identifier, err)
+		}
+
+		var pattern protection.Pattern
+		if err := json.Unmarshal(rule.Pattern, &pattern); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal pattern of rule '%s': %w", rule.Identifier, err)
+		}
+
+		if len(def.Bypass.UserGroupIDs) > 0 {
+			skipped = append(skipped, skip(ctx, repo.Identifier, migrate.ArchiveSkippedRuleBypass, rule.Identifier,
+				"user groups allowed to bypass the rule aren't exported"))
+		}
+
+		extDef, err := a.convertBranchRule(ctx, &def)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to convert rule '%s': %w", rule.Identifier, err)
+		}
+
+		extPattern := migrate.ExternalBranchPattern{
+			Default: pattern.Default,
+			Include: pattern.Include,
+			Exclude: pattern.Exclude,
+		}
+
+		extRules = append(extRules, &migrate.ExternalRule{
+			ID:         int(rule.ID),
+			Identifier: rule.Identifier,
+			State:      string(rule.State),
+			Definition: extDef.JSON(),
+			Pattern:    extPattern.JSON(),
+			Created:    time.UnixMilli(rule.Created),
+			Updated:    time.UnixMilli(rule.Updated),
+		})
+	}
+
+	return extRules, skipped, nil
+}
+
+func (a *Archive) convertBranchRule(
+	ctx context.Context,
+	def *protection.Branch,
+) (*migrate.ExternalDefinition, error) {
+	emails := make([]string, 0, len(def.Bypass.UserIDs))
+	for _, userID := range def.Bypass.UserIDs {
+		user, err := a.findUser(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find bypass user: %w", err)
+		}
+		emails = append(emails, user.Email)
+	}
+
+	strategies := make([]string, len(def.PullReq.Merge.StrategiesAllowed))
+	for i, strategy := range def.PullReq.Merge.StrategiesAllowed {
+		strategies[i] = string(strategy)
+	}
+
+	return &migrate.ExternalDefinition{
+		Bypass: migratetypes.Bypass{
+			UserEmails: emails,
+			RepoOwners: def.Bypass.RepoOwners,
+		},
+		PullReq: migratetypes.PullReq{
+			Approvals: migratetypes.Approvals(def.PullReq.Approvals),
+			Comments:  migratetypes.Comments(def.PullReq.Comments),
+			Merge: migratetypes.Merge{
+				StrategiesAllowed: strategies,
+				DeleteBranch:      def.PullReq.Merge.DeleteBranch,
+				Block:             def.PullReq.Merge.Block,
+			},
+		},
+		Lifecycle: migratetypes.Lifecycle{
+			CreateForbidden:      def.Lifecycle.CreateForbidden,
+			DeleteForbidden:      def.Lifecycle.DeleteForbidden,
+			UpdateForbidden:      def.Lifecycle.UpdateForbidden,
+			UpdateForceForbidden: def.Lifecycle.UpdateForceForbidden,
+		},
+	}, nil
+}
+
+// exportWebhooks returns the webhooks of the repository.
+// Webhook secrets aren't exported, webhooks that have one are reported as skipped.
+func (a *Archive) exportWebhooks(
+	ctx context.Context,
+	repo *types.Repository,
+) (*migrate.ExternalWebhookData, []migrate.ExternalSkipped, error) {
+	webhooks, err := listAll(func(page int) ([]*types.Webhook, error) {
+		return a.webhookStore.List(ctx,
+			[]types.WebhookParentInfo{{Type: enum.WebhookParentRepo, ID: repo.ID}},
+			&types.WebhookFilter{
+				Page:         page,
+				Size:         archivePageSize,
+				SkipInternal: true,
+			})
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	var skipped []migrate.ExternalSkipped
+
+	data := &migrate.ExternalWebhookData{
+		Hooks: make([]*migrate.ExternalWebhook, len(webhooks)),
+	}
+	for i, webhook := range webhooks {
+		events := make([]string, len(webhook.Triggers))
+		for j, trigger := range webhook.Triggers {
+			events[j] = string(trigger)
+		}
+
+		if webhook.Secret != "" {
+			skipped = append(skipped, skip(ctx, repo.Identifier, migrate.ArchiveSkippedWebhookSecret,
+				webhook.Identifier, "webhook secrets aren't exported"))
+		}
+
+		data.Hooks[i] = &migrate.ExternalWebhook{
+			ID:         strconv.FormatInt(webhook.ID, 10),
+			Identifier: webhook.Identifier,
+			Target:     webhook.URL,
+			Events:     events,
+			Active:     webhook.Enabled,
+			SkipVerify: webhook.Insecure,
+		}
+	}
+
+	return data, skipped, nil
+}
+
+// listSpaceSkipped returns the rules and the webhooks of the space.
+// The archive format only supports them on the repository level, so none of them are exported.
+func (a *Archive) listSpaceSkipped(ctx context.Context, space *types.Space) ([]migrate.ExternalSkipped, error) {
+	rules, err := listAll(func(page int) ([]types.Rule, error) {
+		return a.ruleStore.List(ctx,
+			[]types.RuleParentInfo{{Type: enum.RuleParentSpace, ID: space.ID}},
+			&types.RuleFilter{
+				ListQueryFilter: types.ListQueryFilter{
+					Pagination: types.Pagination{Page: page, Size: archivePageSize},
+				},
+			})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list space rules: %w", err)
+	}
+
+	webhooks, err := listAll(func(page int) ([]*types.Webhook, error) {
+		return a.webhookStore.List(ctx,
+			[]types.WebhookParentInfo{{Type: enum.WebhookParentSpace, ID: space.ID}},
+			&types.WebhookFilter{
+				Page:         page,
+				Size:         archivePageSize,
+				SkipInternal: true,
+			})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list space webhooks: %w", err)
+	}
+
+	skipped := make([]migrate.ExternalSkipped, 0, len(rules)+len(webhooks))
+	for _, rule := range rules {
+		skipped = append(skipped, skip(ctx, "", migrate.ArchiveSkippedRule, rule.Identifier,
+			"space rules aren't supported by the archive format"))
+	}
+
+	for _, webhook := range webhooks {
+		skipped = append(skipped, skip(ctx, "", migrate.ArchiveSkippedWebhook, webhook.Identifier,
+			"space webhooks aren't supported by the archive format"))
+	}
+
+	return skipped, nil
+}
+
+// skip logs that the data isn't exported and returns the entry that reports it in the archive.
+func skip(ctx context.Context, repoIdentifier, resource, identifier, reason string) migrate.ExternalSkipped {
+	log.Ctx(ctx).Warn().
+		Str("repository", repoIdentifier).
+		Str("resource", resource).
+		Str("identifier", identifier).
+		Msgf("skipping export: %s", reason)
+
+	return migrate.ExternalSkipped{
+		Repository: repoIdentifier,
+		Resource:   resource,
+		Identifier: identifier,
+		Reason:     reason,
+	}
+}
+
+func (a *Archive) exportPipelines(ctx context.Context, repo *types.Repository) ([]migrate.ExternalPipeline, error) {
+	pipelines, err := listAll(func(page int) ([]*types.Pipeline, error) {
+		return a.pipelineStore.List(ctx, repo.ID, &types.ListPipelinesFilter{
+			ListQueryFilter: types.ListQueryFilter{
+				Pagination: types.Pagination{Page: page, Size: archivePageSize},
+			},
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipelines: %w", err)
+	}
+
+	extPipelines := make([]migrate.ExternalPipeline, len(pipelines))
+	for i, pipeline := range pipelines {
+		triggers, err := listAll(func(page int) ([]*types.Trigger, error) {
+			return a.triggerStore.List(ctx, pipeline.ID, types.ListQueryFilter{
+				Pagination: types.Pagination{Page: page, Size: archivePageSize},
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list triggers of pipeline '%s': %w", pipeline.Identifier, err)
+		}
+
+		extTriggers := make([]migrate.ExternalTrigger, len(triggers))
+		for j, trigger := range triggers {
+			actions := make([]string, len(trigger.Actions))
+			for k, action := range trigger.Actions {
+				actions[k] = string(action)
+			}
+
+			extTriggers[j] = migrate.ExternalTrigger{
+				Identifier:  trigger.Identifier,
+				Description: trigger.Description,
+				Type:        trigger.Type,
+				Disabled:    trigger.Disabled,
+				Actions:     actions,
+				Cron:        trigger.Cron,
+				Branch:      trigger.Branch,
+				Timezone:    trigger.Timezone,
+			}
+		}
+
+		extPipelines[i] = migrate.ExternalPipeline{
+			Identifier:    pipeline.Identifier,
+			Description:   pipeline.Description,
+			Disabled:      pipeline.Disabled,
+			DefaultBranch: pipeline.DefaultBranch,
+			ConfigPath:    pipeline.ConfigPath,
+			Triggers:      extTriggers,
+		}
+	}
+
+	return extPipelines, nil
+}
+
+func (a *Archive) findUser(ctx context.Context, principalID int64) (migrate.ExternalUser, error) {
+	principal, err := a.principalInfoCache.Get(ctx, principalID)
+	if err != nil {
+		return migrate.ExternalUser{}, fmt.Errorf("failed to find principal %d: %w", principalID, err)
+	}
+
+	return convertPrincipalInfo(principal), nil
+}
+
+func convertPrincipalInfo(principal *types.PrincipalInfo) migrate.ExternalUser {
+	return migrate.ExternalUser{
+		ID:      strconv.FormatInt(principal.ID, 10),
+		Login:   principal.UID,
+		Name:    principal.DisplayName,
+		Email:   principal.Email,
+		Created: time.UnixMilli(principal.Created),
+		Updated: time.UnixMilli(principal.Updated),
+	}
+}
+
+// listAll calls the list function page by page until it returns an incomplete page.
+func listAll[T any](list func(page int) ([]T, error)) ([]T, error) {
+	var all []T
+	for page := 1; ; page++ {
+		items, err := list(page)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, items...)
+
+		if len(items) < archivePageSize {
+			return all, nil
+		}
+	}
+}
+
+func writeArchiveJSON(tw *tar.Writer, name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %q: %w", name, err)
+	}
+
+	err = tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     int64(len(data)),
+		Mode:     0o644,
+		ModTime:  time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write archive header for %q: %w", name, err)
+	}
+
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %q to archive: %w", name, err)
+	}
+
+	return nil
+}
+
+func writeArchiveFile(tw *tar.Writer, name string, filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open %q: %w", filePath, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %q: %w", filePath, err)
+	}
+
+	err = tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     info.Size(),
+		Mode:     0o644,
+		ModTime:  info.ModTime(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write archive header for %q: %w", name, err)
+	}
+
+	if _, err := io.CopyN(tw, f, info.Size()); err != nil {
+		return fmt.Errorf("failed to write %q to archive: %w", name, err)
+	}
+
+	return nil
+}
@@ -15,6 +15,7 @@
 package exporter
 
 import (
+	"github.com/harness/gitness/app/services/publicaccess"
 	"github.com/harness/gitness/app/sse"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/app/url"
@@ -27,6 +28,7 @@ import (
 
 var WireSet = wire.NewSet(
 	ProvideSpaceExporter,
+	ProvideArchiveExporter,
 )
 
 func ProvideSpaceExporter(
@@ -54,3 +56,37 @@ func ProvideSpaceExporter(
 
 	return exporter, nil
 }
+
+func ProvideArchiveExporter(
+	git git.Interface,
+	repoStore store.RepoStore,
+	principalInfoCache store.PrincipalInfoCache,
+	pullReqStore store.PullReqStore,
+	activityStore store.PullReqActivityStore,
+	reviewerStore store.PullReqReviewerStore,
+	labelStore store.LabelStore,
+	labelValueStore store.LabelValueStore,
+	labelAssignmentStore store.PullReqLabelAssignmentStore,
+	ruleStore store.RuleStore,
+	webhookStore store.WebhookStore,
+	pipelineStore store.PipelineStore,
+	triggerStore store.TriggerStore,
+	publicAccess publicaccess.Service,
+) *Archive {
+	return &Archive{
+		git:                  git,
+		repoStore:            repoStore,
+		principalInfoCache:   principalInfoCache,
+		pullReqStore:         pullReqStore,
+		activityStore:        activityStore,
+		reviewerStore:        reviewerStore,
+		labelStore:           labelStore,
+		labelValueStore:      labelValueStore,
+		labelAssignmentStore: labelAssignmentStore,
+		ruleStore:            ruleStore,
+		webhookStore:         webhookStore,
+		pipelineStore:        pipelineStore,
+		triggerStore:         triggerStore,
+		publicAccess:         publicAccess,
+	}
+}
@@ -0,0 +1,1012 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/githook"
+	"github.com/harness/gitness/app/paths"
+	"github.com/harness/gitness/app/services/instrument"
+	"github.com/harness/gitness/app/services/keywordsearch"
+	"github.com/harness/gitness/app/services/migrate"
+	"github.com/harness/gitness/app/services/publicaccess"
+	triggersvc "github.com/harness/gitness/app/services/trigger"
+	"github.com/harness/gitness/app/sse"
+	"github.com/harness/gitness/app/store"
+	gitnessurl "github.com/harness/gitness/app/url"
+	"github.com/harness/gitness/audit"
+	"github.com/harness/gitness/blob"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/job"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/check"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	archiveJobType        = "space_archive_import"
+	archiveJobMaxRetries  = 0
+	archiveJobMaxDuration = 3 * time.Hour
+
+	archiveBlobPathFmt = "space-archives/%d.tar.gz"
+)
+
+var (
+	// ErrInvalidArchive is returned if the provided space archive can't be imported.
+	ErrInvalidArchive = errors.New("invalid space archive")
+
+	// ErrArchiveTooLarge is returned if the content of the space archive exceeds the configured limits.
+	ErrArchiveTooLarge = errors.New("space archive is too large")
+)
+
+// Archive imports space archives written by the exporter.
+type Archive struct {
+	defaultBranch    string
+	maxExtractedSize int64
+	maxEntries       int
+	urlProvider      gitnessurl.Provider
+	git              git.Interface
+	tx               dbtx.Transactor
+	identifierCheck  check.RepoIdentifier
+	spaceStore       store.SpaceStore
+	repoStore        store.RepoStore
+	principalStore   store.PrincipalStore
+	reviewerStore    store.PullReqReviewerStore
+	pipelineStore    store.PipelineStore
+	triggerStore     store.TriggerStore
+	labelStore       store.LabelStore
+	blobStore        blob.Store
+	repoDeleter      RepoDeleter
+	publicAccess     publicaccess.Service
+	indexer          keywordsearch.Indexer
+	scheduler        *job.Scheduler
+	sseStreamer      sse.Streamer
+	auditService     audit.Service
+	instrumentation  instrument.Service
+	pullReqImporter  *migrate.PullReq
+	ruleImporter     *migrate.Rule
+	webhookImporter  *migrate.Webhook
+	labelImporter    *migrate.Label
+}
+
+var _ job.Handler = (*Archive)(nil)
+
+// RepoDeleter deletes repositories together with their public access and git data, and reports their deletion.
+// It's implemented by the repository controller.
+type RepoDeleter interface {
+	SoftDeleteNoAuth(ctx context.Context, session *auth.Session, repo *types.Repository, deletedAt int64) error
+	PurgeNoAuth(ctx context.Context, session *auth.Session, repo *types.Repository) error
+}
+
+// SpaceArchive is a space archive extracted to a temporary directory.
+type SpaceArchive struct {
+	// file is the copy of the archive that is staged for the import job, it's empty in the job itself.
+	file  string
+	dir   string
+	Space migrate.ExternalSpace
+}
+
+// Close removes the copy of the archive and its extracted content.
+func (a *SpaceArchive) Close() error {
+	return errors.Join(os.RemoveAll(a.file), os.RemoveAll(a.dir))
+}
+
+// ArchiveImportResult is the result of a completed space archive import.
+type ArchiveImportResult struct {
+	Repositories []string `json:"repositories"`
+	// Skipped lists the data of the exported space that isn't part of the archive.
+	Skipped []migrate.ExternalSkipped `json:"skipped,omitempty"`
+}
+
+type archiveJobInput struct {
+	SpaceID     int64  `json:"space_id"`
+	PrincipalID int64  `json:"principal_id"`
+	BlobPath    string `json:"blob_path"`
+}
+
+// Open extracts the space archive and reads the description of the space.
+// A copy of the archive is kept, so Run can stage it for the import job.
+func (a *Archive) Open(ctx context.Context, r io.Reader) (*SpaceArchive, error) {
+	archive := &SpaceArchive{}
+
+	if err := a.open(ctx, archive, r); err != nil {
+		if errRemove := archive.Close(); errRemove != nil {
+			log.Ctx(ctx).Warn().Err(errRemove).Msg("failed to remove extracted space archive")
+		}
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+func (a *Archive) open(ctx context.Context, archive *SpaceArchive, r io.Reader) error {
+	file, err := os.CreateTemp("", "space-import-*.tar.gz")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer file.Close()
+
+	archive.file = file.Name()
+
+	if _, err = io.Copy(file, r); err != nil {
+		return fmt.Errorf("failed to copy space archive: %w", err)
+	}
+
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind space archive copy: %w", err)
+	}
+
+	if archive.dir, err = a.extract(ctx, file); err != nil {
+		return err
+	}
+
+	if err = readArchiveSpace(archive.dir, &archive.Space); err != nil {
+		return err
+	}
+
+	for _, identifier := range archive.Space.Repositories {
+		if err = a.identifierCheck(identifier); err != nil {
+			return fmt.Errorf("%w: repository identifier %q: %w", ErrInvalidArchive, identifier, err)
+		}
+	}
+
+	return nil
+}
+
+// Run stages the opened archive in the blob store and starts a background job that imports it into the space.
+// The job can run on any instance, the caller still has to close the archive.
+func (a *Archive) Run(
+	ctx context.Context,
+	principal *types.Principal,
+	space *types.Space,
+	archive *SpaceArchive,
+) error {
+	blobPath := fmt.Sprintf(archiveBlobPathFmt, space.ID)
+
+	data, err := json.Marshal(archiveJobInput{
+		SpaceID:     space.ID,
+		PrincipalID: principal.ID,
+		BlobPath:    blobPath,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal job input json: %w", err)
+	}
+
+	file, err := os.Open(archive.file)
+	if err != nil {
+		return fmt.Errorf("failed to open space archive copy: %w", err)
+	}
+	defer file.Close()
+
+	if err = a.blobStore.Upload(ctx, file, blobPath); err != nil {
+		return fmt.Errorf("failed to upload space archive to blob store: %w", err)
+	}
+
+	err = a.scheduler.RunJob(ctx, job.Definition{
+		UID:        ArchiveJobIDFromSpaceID(space.ID),
+		Type:       archiveJobType,
+		MaxRetries: archiveJobMaxRetries,
+		Timeout:    archiveJobMaxDuration,
+		Data:       string(data),
+	})
+	if err != nil {
+		if errDelete := a.blobStore.Delete(context.WithoutCancel(ctx), blobPath); errDelete != nil {
+			log.Ctx(ctx).Warn().Err(errDelete).Msg("failed to delete space archive from blob store")
+		}
+		return fmt.Errorf("failed to run space archive import job: %w", err)
+	}
+
+	return nil
+}
+
+// GetProgress returns the progress of the archive import into the space.
+func (a *Archive) GetProgress(ctx context.Context, space *types.Space) (job.Progress, error) {
+	progress, err := a.scheduler.GetJobProgress(ctx, ArchiveJobIDFromSpaceID(space.ID))
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return job.Progress{}, ErrNotFound
+	}
+	if err != nil {
+		return job.Progress{}, fmt.Errorf("failed to get job progress: %w", err)
+	}
+
+	return progress, nil
+}
+
+// Handle is the space archive import background job handler.
+// If the import fails, all imported data is removed from the space.
+func (a *Archive) Handle(ctx context.Context, data string, reportProgress job.ProgressReporter) (string, error) {
+	var input archiveJobInput
+	if err := json.Unmarshal([]byte(data), &input); err != nil {
+		return "", fmt.Errorf("failed to unmarshal job input json: %w", err)
+	}
+
+	// the job doesn't retry, so the staged archive isn't needed anymore once the job is done.
+	defer func() {
+		if errDelete := a.blobStore.Delete(context.WithoutCancel(ctx), input.BlobPath); errDelete != nil {
+			log.Ctx(ctx).Warn().Err(errDelete).Msg("failed to delete space archive from blob store")
+		}
+	}()
+
+	archive := &SpaceArchive{}
+	defer func() {
+		if errClose := archive.Close(); errClose != nil {
+			log.Ctx(ctx).Warn().Err(errClose).Msg("failed to remove extracted space archive")
+		}
+	}()
+
+	if err := a.download(ctx, archive, input.BlobPath); err != nil {
+		return "", err
+	}
+
+	if err := readArchiveSpace(archive.dir, &archive.Space); err != nil {
+		return "", err
+	}
+
+	space, err := a.spaceStore.Find(ctx, input.SpaceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find space: %w", err)
+	}
+
+	principal, err := a.principalStore.Find(ctx, input.PrincipalID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find principal: %w", err)
+	}
+
+	repos, err := a.importArchive(ctx, principal, space, archive, reportProgress)
+	if err != nil {
+		a.rollback(context.WithoutCancel(ctx), principal, space, repos)
+		return "", err
+	}
+
+	result := ArchiveImportResult{
+		Repositories: make([]string, len(repos)),
+		Skipped:      archive.Space.Skipped,
+	}
+
+	for i, repo := range repos {
+		result.Repositories[i] = repo.Identifier
+		a.completeRepository(ctx, principal, repo)
+	}
+
+	for _, skipped := range archive.Space.Skipped {
+		log.Ctx(ctx).Warn().
+			Str("space.path", space.Path).
+			Str("repo.identifier", skipped.Repository).
+			Msgf("%s '%s' wasn't exported: %s", skipped.Resource, skipped.Identifier, skipped.Reason)
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal job result: %w", err)
+	}
+
+	return string(resultJSON), nil
+}
+
+// importArchive imports the labels and all repositories of the archive into the space.
+// The repositories remain in the migrate data import state until all their data is imported.
+// The created repositories are returned even if the import fails, so they can be removed.
+func (a *Archive) importArchive(
+	ctx context.Context,
+	principal *types.Principal,
+	space *types.Space,
+	archive *SpaceArchive,
+	reportProgress job.ProgressReporter,
+) ([]*types.Repository, error) {
+	var labels []*migrate.ExternalLabel
+	if err := readArchiveJSON(archive.dir, migrate.ArchiveLabelsFileName, &labels); err != nil {
+		return nil, err
+	}
+
+	if len(labels) > 0 {
+		if _, err := a.labelImporter.Import(ctx, *principal, space, labels); err != nil {
+			return nil, fmt.Errorf("failed to import space labels: %w", err)
+		}
+	}
+
+	repos := make([]*types.Repository, 0, len(archive.Space.Repositories))
+	for i, identifier := range archive.Space.Repositories {
+		dir := filepath.Join(archive.dir, migrate.ArchiveReposDir, identifier)
+
+		var info migrate.ExternalRepositoryInfo
+		if err := readArchiveJSON(dir, migrate.ArchiveInfoFileName, &info); err != nil {
+			return repos, err
+		}
+
+		defaultBranch := info.Branch
+		if defaultBranch == "" {
+			defaultBranch = a.defaultBranch
+		}
+
+		repo, err := a.createRepository(ctx, principal, space, identifier, info.Description, defaultBranch)
+		if err != nil {
+			return repos, fmt.Errorf("failed to create repository '%s': %w", identifier, err)
+		}
+
+		repos = append(repos, repo)
+
+		repo, err = a.importRepository(ctx, principal, space, repo, !info.Private, dir)
+		if err != nil {
+			return repos, fmt.Errorf("failed to import repository '%s': %w", identifier, err)
+		}
+
+		repos[i] = repo
+
+		progress := job.ProgressMin + (i+1)*(job.ProgressMax-job.ProgressMin)/len(archive.Space.Repositories)
+		if err := reportProgress(progress, ""); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("failed to report space archive import progress")
+		}
+	}
+
+	return repos, nil
+}
+
+// importRepository imports the git data and all other data of the repository from the archive directory
+// into the created repository and activates it.
+//
+//nolint:gocognit // the steps of the import are easier to follow in one place.
+func (a *Archive) importRepository(
+	ctx context.Context,
+	principal *types.Principal,
+	space *types.Space,
+	repo *types.Repository,
+	isPublic bool,
+	dir string,
+) (*types.Repository, error) {
+	log := log.Ctx(ctx).With().
+		Str("space.path", space.Path).
+		Str("repo.identifier", repo.Identifier).
+		Logger()
+
+	writeParams, err := a.createRPCWriteParams(ctx, principal, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	bundlePath := filepath.Join(dir, migrate.ArchiveBundleFileName)
+	if _, err = os.Stat(bundlePath); err == nil {
+		log.Info().Msg("sync repository from bundle")
+
+		_, err = a.git.SyncRepository(ctx, &git.SyncRepositoryParams{
+			WriteParams:   writeParams,
+			Source:        bundlePath,
+			DefaultBranch: repo.DefaultBranch,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to sync repository from bundle: %w", err)
+		}
+
+		repo, err = a.repoStore.UpdateOptLock(ctx, repo, func(r *types.Repository) error {
+			r.IsEmpty = false
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to update repository after sync: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to stat repository bundle: %w", err)
+	}
+
+	if err = a.setPublicAccess(ctx, repo, isPublic); err != nil {
+		return nil, err
+	}
+
+	// The migrate label importer only knows space labels, so repository labels become labels of the space.
+	var labels []*migrate.ExternalLabel
+	if err = readArchiveJSON(dir, migrate.ArchiveLabelsFileName, &labels); err != nil {
+		return nil, err
+	}
+	if len(labels) > 0 {
+		if _, err = a.labelImporter.Import(ctx, *principal, space, labels); err != nil {
+			return nil, fmt.Errorf("failed to import labels: %w", err)
+		}
+	}
+
+	var rules []*migrate.ExternalRule
+	if err = readArchiveJSON(dir, migrate.ArchiveBranchRulesFileName, &rules); err != nil {
+		return nil, err
+	}
+	if len(rules) > 0 {
+		_, err = a.ruleImporter.Import(ctx, *principal, repo, migrate.ExternalRuleTypeBranch, rules)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import branch rules: %w", err)
+		}
+	}
+
+	var webhooks migrate.ExternalWebhookData
+	if err = readArchiveJSON(dir, migrate.ArchiveWebhookFileName, &webhooks); err != nil {
+		return nil, err
+	}
+	if len(webhooks.Hooks) > 0 {
+		if _, err = a.webhookImporter.Import(ctx, *principal, repo, webhooks.Hooks); err != nil {
+			return nil, fmt.Errorf("failed to import webhooks: %w", err)
+		}
+	}
+
+	log.Info().Msg("import pull requests")
+
+	if err = a.importPullRequests(ctx, principal, repo, dir); err != nil {
+		return nil, err
+	}
+
+	var pipelines []migrate.ExternalPipeline
+	if err = readArchiveJSON(dir, migrate.ArchivePipelinesFileName, &pipelines); err != nil {
+		return nil, err
+	}
+	if err = a.importPipelines(ctx, principal, repo, pipelines); err != nil {
+		return nil, fmt.Errorf("failed to import pipelines: %w", err)
+	}
+
+	repo, err = a.repoStore.UpdateOptLock(ctx, repo, func(r *types.Repository) error {
+		r.State = enum.RepoStateActive
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to activate repository: %w", err)
+	}
+
+	log.Info().Msg("completed repository import")
+
+	return repo, nil
+}
+
+// completeRepository does the steps that follow the successful import of all repositories of the archive.
+func (a *Archive) completeRepository(ctx context.Context, principal *types.Principal, repo *types.Repository) {
+	if err := a.indexer.Index(ctx, repo); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to index repository '%s'", repo.Identifier)
+	}
+
+	isPublic, err := a.publicAccess.Get(ctx, enum.PublicResourceTypeRepo, repo.Path)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to get public access of repository '%s'", repo.Identifier)
+	}
+
+	err = a.auditService.Log(ctx,
+		*principal,
+		audit.NewResource(audit.ResourceTypeRepository, repo.Identifier),
+		audit.ActionCreated,
+		paths.Parent(repo.Path),
+		audit.WithNewObject(audit.RepositoryObject{
+			Repository: *repo,
+			IsPublic:   isPublic,
+		}),
+	)
+	if err != nil {
+		log.Ctx(ctx).Warn().Msgf("failed to insert audit log for import repository operation: %s", err)
+	}
+
+	err = a.instrumentation.Track(ctx, instrument.Event{
+		Type:      instrument.EventTypeRepositoryCreate,
+		Principal: principal.ToPrincipalInfo(),
+		Path:      paths.Parent(repo.Path),
+		Properties: map[instrument.Property]any{
+			instrument.PropertyRepositoryID:           repo.ID,
+			instrument.PropertyRepositoryName:         repo.Identifier,
+			instrument.PropertyRepositoryCreationType: instrument.CreationTypeImport,
+		},
+	})
+	if err != nil {
+		log.Ctx(ctx).Warn().Msgf("failed to insert instrumentation record for import repository operation: %s", err)
+	}
+
+	a.sseStreamer.Publish(ctx, repo.ParentID, enum.SSETypeRepositoryImportCompleted, repo)
+}
+
+// rollback removes the imported data after a failed import: the created repositories with all their data
+// and the labels of the space. The space itself is kept, so the failure can be checked with GetProgress.
+func (a *Archive) rollback(
+	ctx context.Context,
+	principal *types.Principal,
+	space *types.Space,
+	repos []*types.Repository,
+) {
+	log := log.Ctx(ctx).With().Str("space.path", space.Path).Logger()
+
+	log.Warn().Msg("space archive import failed, removing the imported data")
+
+	session := &auth.Session{Principal: *principal}
+	deletedAt := time.Now().UnixMilli()
+
+	// the repositories are deleted the same way as by the user, so their public access is removed
+	// and their deletion is reported. The database cascades the purge to their pull requests, rules,
+	// webhooks and pipelines.
+	for _, repo := range repos {
+		err := a.repoDeleter.SoftDeleteNoAuth(ctx, session, repo, deletedAt)
+		if err == nil && repo.State == enum.RepoStateActive {
+			// activated repositories are only soft deleted, but they shouldn't stay behind.
+			deleted := *repo
+			deleted.Deleted = &deletedAt
+			err = a.repoDeleter.PurgeNoAuth(ctx, session, &deleted)
+		}
+		if err != nil {
+			log.Warn().Err(err).Msgf("failed to delete imported repository '%s'", repo.Identifier)
+		}
+	}
+
+	// the space is created for the import, so all its labels come from the archive.
+	// The deleted labels disappear from the list, so the first page is listed until it's empty.
+	for {
+		labels, err := a.labelStore.List(ctx, &space.ID, nil, &types.LabelFilter{})
+		if err != nil {
+			log.Warn().Err(err).Msg("failed to list imported labels")
+			return
+		}
+
+		if len(labels) == 0 {
+			return
+		}
+
+		for _, label := range labels {
+			if err := a.labelStore.Delete(ctx, &space.ID, nil, label.Key); err != nil {
+				log.Warn().Err(err).Msgf("failed to delete imported label '%s'", label.Key)
+				return
+			}
+		}
+	}
+}
+
+func (a *Archive) createRepository(
+	ctx context.Context,
+	principal *types.Principal,
+	space *types.Space,
+	identifier string,
+	description string,
+	defaultBranch string,
+) (*types.Repository, error) {
+	envVars, err := githook.GenerateEnvironmentVariables(
+		ctx,
+		a.urlProvider.GetInternalAPIURL(ctx),
+		0,
+		principal.ID,
+		true,
+		true,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate git hook environment variables: %w", err)
+	}
+
+	identity := &git.Identity{
+		Name:  principal.DisplayName,
+		Email: principal.Email,
+	}
+	now := time.Now()
+
+	gitResp, err := a.git.CreateRepository(ctx, &git.CreateRepositoryParams{
+		Actor:         *identity,
+		EnvVars:       envVars,
+		DefaultBranch: defaultBranch,
+		Author:        identity,
+		AuthorDate:    &now,
+		Committer:     identity,
+		CommitterDate: &now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create git repository: %w", err)
+	}
+
+	repo := &types.Repository{
+		ParentID:      space.ID,
+		Identifier:    identifier,
+		GitUID:        gitResp.UID,
+		Description:   description,
+		CreatedBy:     principal.ID,
+		Created:       now.UnixMilli(),
+		Updated:       now.UnixMilli(),
+		DefaultBranch: defaultBranch,
+		IsEmpty:       true,
+		State:         enum.RepoStateMigrateDataImport,
+	}
+
+	if err = a.repoStore.Create(ctx, repo); err != nil {
+		errDelete := a.git.DeleteRepository(context.WithoutCancel(ctx), &git.DeleteRepositoryParams{
+			WriteParams: git.WriteParams{
+				Actor:   *identity,
+				RepoUID: gitResp.UID,
+				EnvVars: envVars,
+			},
+		})
+		if errDelete != nil {
+			log.Ctx(ctx).Warn().Err(errDelete).Msg("failed to delete git repository after failed import")
+		}
+
+		return nil, fmt.Errorf("failed to create repository in storage: %w", err)
+	}
+
+	return repo, nil
+}
+
+func (a *Archive) setPublicAccess(ctx context.Context, repo *types.Repository, isPublic bool) error {
+	isPublicAccessSupported, err := a.publicAccess.IsPublicAccessSupported(ctx, paths.Parent(repo.Path))
+	if err != nil {
+		return fmt.Errorf("failed to check if public access is supported: %w", err)
+	}
+
+	if !isPublicAccessSupported {
+		isPublic = false
+	}
+
+	if err = a.publicAccess.Set(ctx, enum.PublicResourceTypeRepo, repo.Path, isPublic); err != nil {
+		return fmt.Errorf("failed to set repo access mode: %w", err)
+	}
+
+	return nil
+}
+
+func (a *Archive) importPullRequests(
+	ctx context.Context,
+	principal *types.Principal,
+	repo *types.Repository,
+	dir string,
+) error {
+	entries, err := os.ReadDir(filepath.Join(dir, migrate.ArchivePullRequestDir))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read pull requests: %w", err)
+	}
+
+	extPullReqs := make([]*migrate.ExternalPullRequest, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		extPullReq := &migrate.ExternalPullRequest{}
+		name := path.Join(migrate.ArchivePullRequestDir, entry.Name())
+		if err = readArchiveJSON(dir, name, extPullReq); err != nil {
+			return err
+		}
+
+		extPullReqs = append(extPullReqs, extPullReq)
+	}
+
+	sort.Slice(extPullReqs, func(i, j int) bool {
+		return extPullReqs[i].PullRequest.Number < extPullReqs[j].PullRequest.Number
+	})
+
+	pullReqs, err := a.pullReqImporter.Import(ctx, *principal, repo, extPullReqs)
+	if err != nil {
+		return fmt.Errorf("failed to import pull requests: %w", err)
+	}
+
+	var reviews []migrate.ExternalReview
+	if err = readArchiveJSON(dir, migrate.ArchiveReviewsFileName, &reviews); err != nil {
+		return err
+	}
+
+	pullReqIDs := make(map[int]int64, len(pullReqs))
+	for _, pullReq := range pullReqs {
+		pullReqIDs[int(pullReq.Number)] = pullReq.ID
+	}
+
+	return a.tx.WithTx(ctx, func(ctx context.Context) error {
+		for _, review := range reviews {
+			if err := a.importReview(ctx, principal, repo, pullReqIDs, review); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// importReview stores a review of a pull request. Reviews of unknown users are skipped.
+func (a *Archive) importReview(
+	ctx context.Context,
+	principal *types.Principal,
+	repo *types.Repository,
+	pullReqIDs map[int]int64,
+	review migrate.ExternalReview,
+) error {
+	pullReqID, ok := pullReqIDs[review.PullRequest]
+	if !ok {
+		return nil
+	}
+
+	reviewer, err := a.principalStore.FindByEmail(ctx, review.Reviewer.Email)
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		log.Ctx(ctx).Warn().Msgf("skipping review of pull request #%d by unknown user '%s'",
+			review.PullRequest, review.Reviewer.Email)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find reviewer: %w", err)
+	}
+
+	reviewerType, ok := enum.PullReqReviewerType(review.Type).Sanitize()
+	if !ok {
+		reviewerType = enum.PullReqReviewerTypeRequested
+	}
+
+	decision, ok := enum.PullReqReviewDecision(review.Decision).Sanitize()
+	if !ok {
+		decision = enum.PullReqReviewDecisionPending
+	}
+
+	now := time.Now().UnixMilli()
+
+	err = a.reviewerStore.Create(ctx, &types.PullReqReviewer{
+		PullReqID:      pullReqID,
+		PrincipalID:    reviewer.ID,
+		CreatedBy:      principal.ID,
+		Created:        timestampMillis(review.Created, now),
+		Updated:        timestampMillis(review.Updated, now),
+		RepoID:         repo.ID,
+		Type:           reviewerType,
+		ReviewDecision: decision,
+		SHA:            review.SHA,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store review of pull request #%d: %w", review.PullRequest, err)
+	}
+
+	return nil
+}
+
+func (a *Archive) importPipelines(
+	ctx context.Context,
+	principal *types.Principal,
+	repo *types.Repository,
+	extPipelines []migrate.ExternalPipeline,
+) error {
+	if len(extPipelines) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	nowMilli := now.UnixMilli()
+
+	return a.tx.WithTx(ctx, func(ctx context.Context) error {
+		for _, extPipeline := range extPipelines {
+			pipeline := &types.Pipeline{
+				Description:   extPipeline.Description,
+				Identifier:    extPipeline.Identifier,
+				Disabled:      extPipeline.Disabled,
+				CreatedBy:     principal.ID,
+				RepoID:        repo.ID,
+				DefaultBranch: extPipeline.DefaultBranch,
+				ConfigPath:    extPipeline.ConfigPath,
+				Created:       nowMilli,
+				Updated:       nowMilli,
+			}
+
+			if err := a.pipelineStore.Create(ctx, pipeline); err != nil {
+				return fmt.Errorf("failed to create pipeline '%s': %w", extPipeline.Identifier, err)
+			}
+
+			for _, extTrigger := range extPipeline.Triggers {
+				actions := make([]enum.TriggerAction, 0, len(extTrigger.Actions))
+				for _, action := range extTrigger.Actions {
+					if sanitized, ok := enum.TriggerAction(action).Sanitize(); ok {
+						actions = append(actions, sanitized)
+					}
+				}
+
+				trigger := &types.Trigger{
+					Description: extTrigger.Description,
+					Type:        extTrigger.Type,
+					PipelineID:  pipeline.ID,
+					RepoID:      repo.ID,
+					CreatedBy:   principal.ID,
+					Disabled:    extTrigger.Disabled,
+					Actions:     actions,
+					Identifier:  extTrigger.Identifier,
+					Cron:        extTrigger.Cron,
+					Branch:      extTrigger.Branch,
+					Timezone:    extTrigger.Timezone,
+					Created:     nowMilli,
+					Updated:     nowMilli,
+				}
+
+				if trigger.Cron != "" {
+					next, err := triggersvc.NextCronRun(trigger.Cron, trigger.Timezone, now)
+					if err != nil {
+						return fmt.Errorf("invalid cron schedule of trigger '%s': %w", extTrigger.Identifier, err)
+					}
+					trigger.CronNext = next.UnixMilli()
+				}
+
+				if err := a.triggerStore.Create(ctx, trigger); err != nil {
+					return fmt.Errorf("failed to create trigger '%s': %w", extTrigger.Identifier, err)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func (a *Archive) createRPCWriteParams(
+	ctx context.Context,
+	principal *types.Principal,
+	repo *types.Repository,
+) (git.WriteParams, error) {
+	envVars, err := githook.GenerateEnvironmentVariables(
+		ctx,
+		a.urlProvider.GetInternalAPIURL(ctx),
+		repo.ID,
+		principal.ID,
+		false,
+		true,
+	)
+	if err != nil {
+		return git.WriteParams{}, fmt.Errorf("failed to generate git hook environment variables: %w", err)
+	}
+
+	return git.WriteParams{
+		Actor: git.Identity{
+			Name:  principal.DisplayName,
+			Email: principal.Email,
+		},
+		RepoUID: repo.GitUID,
+		EnvVars: envVars,
+	}, nil
+}
+
+// download extracts the space archive staged in the blob store.
+func (a *Archive) download(ctx context.Context, archive *SpaceArchive, blobPath string) error {
+	r, err := a.blobStore.Download(ctx, blobPath)
+	if err != nil {
+		return fmt.Errorf("failed to download space archive from blob store: %w", err)
+	}
+	defer r.Close()
+
+	archive.dir, err = a.extract(ctx, r)
+
+	return err
+}
+
+// extract extracts the space archive to a new temporary directory, which is removed if the extraction fails.
+func (a *Archive) extract(ctx context.Context, r io.Reader) (string, error) {
+	dir, err := os.MkdirTemp("", "space-import-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+
+	if err = extractArchive(ctx, r, dir, a.maxExtractedSize, a.maxEntries); err != nil {
+		if errRemove := os.RemoveAll(dir); errRemove != nil {
+			log.Ctx(ctx).Warn().Err(errRemove).Msg("failed to remove extracted space archive")
+		}
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// extractArchive extracts the regular files of a gzip compressed tarball into the directory.
+// The total size of the extracted files and the number of entries are limited by maxSize and maxEntries.
+func extractArchive(ctx context.Context, r io.Reader, dir string, maxSize int64, maxEntries int) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	defer gr.Close()
+
+	var size int64
+	var entries int
+
+	tr := tar.NewReader(gr)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+		}
+
+		entries++
+		if entries > maxEntries {
+			return fmt.Errorf("%w: the archive has more than %d entries", ErrArchiveTooLarge, maxEntries)
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// the tar reader doesn't return more data than the header declares.
+		size += hdr.Size
+		if size > maxSize {
+			return fmt.Errorf("%w: the extracted archive exceeds %d bytes", ErrArchiveTooLarge, maxSize)
+		}
+
+		// cleaning the path as an absolute one makes sure that the entry can't escape the directory.
+		rel := strings.TrimPrefix(path.Clean("/"+hdr.Name), "/")
+		if rel == "" {
+			continue
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory for %q: %w", rel, err)
+		}
+
+		if err := extractFile(target, tr); err != nil {
+			return fmt.Errorf("failed to extract %q: %w", rel, err)
+		}
+	}
+}
+
+func extractFile(target string, r io.Reader) error {
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// readArchiveSpace reads the description of the space from the extracted archive.
+func readArchiveSpace(dir string, space *migrate.ExternalSpace) error {
+	if err := readArchiveJSON(dir, migrate.ArchiveSpaceFileName, space); err != nil {
+		return err
+	}
+
+	if space.Version != migrate.ArchiveVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidArchive, space.Version)
+	}
+
+	return nil
+}
+
+// readArchiveJSON reads a JSON file of the archive. A missing file leaves the value unchanged.
+func readArchiveJSON(dir, name string, v any) error {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %q: %w", name, err)
+	}
+
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: failed to parse %q: %w", ErrInvalidArchive, name, err)
+	}
+
+	return nil
+}
+
+func timestampMillis(t time.Time, def int64) int64 {
+	if t.IsZero() {
+		return def
+	}
+
+	return t.UnixMilli()
+}
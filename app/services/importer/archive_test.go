@@ -0,0 +1,223 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/harness/gitness/app/services/migrate"
+	"github.com/harness/gitness/blob"
+	"github.com/harness/gitness/types/check"
+)
+
+type testArchiveEntry struct {
+	name     string
+	typeflag byte
+	content  string
+}
+
+func writeTestArchive(t *testing.T, entries []testArchiveEntry) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Typeflag: e.typeflag, Mode: 0o644, Size: int64(len(e.content))}
+		if e.typeflag == tar.TypeSymlink {
+			hdr.Linkname = "/etc/passwd"
+			hdr.Size = 0
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("failed to write header: %s", err)
+		}
+		if _, err := tw.Write([]byte(e.content)); err != nil {
+			t.Fatalf("failed to write content: %s", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar writer: %s", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("failed to close gzip writer: %s", err)
+	}
+
+	return &buf
+}
+
+func TestExtractArchive(t *testing.T) {
+	buf := writeTestArchive(t, []testArchiveEntry{
+		{name: "space.json", typeflag: tar.TypeReg, content: `{"version":1,"identifier":"team"}`},
+		{name: "repos/repo1/info.json", typeflag: tar.TypeReg, content: `{}`},
+		{name: "../../escaped.txt", typeflag: tar.TypeReg, content: "escaped"},
+		{name: "/absolute.txt", typeflag: tar.TypeReg, content: "absolute"},
+		{name: "link", typeflag: tar.TypeSymlink},
+	})
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "archive")
+
+	if err := extractArchive(context.Background(), buf, dir, 1024, 10); err != nil {
+		t.Fatalf("failed to extract archive: %s", err)
+	}
+
+	var space migrate.ExternalSpace
+	if err := readArchiveSpace(dir, &space); err != nil {
+		t.Fatalf("failed to read space: %s", err)
+	}
+	if space.Version != 1 || space.Identifier != "team" {
+		t.Errorf("unexpected space: %+v", space)
+	}
+
+	for _, name := range []string{"repos/repo1/info.json", "escaped.txt", "absolute.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			t.Errorf("expected %q to be extracted: %s", name, err)
+		}
+	}
+
+	if _, err := os.Lstat(filepath.Join(dir, "link")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected symlink to be skipped, got: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "escaped.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected entry to stay within the directory, got: %v", err)
+	}
+
+	// missing files leave the value unchanged.
+	var labels []*migrate.ExternalLabel
+	if err := readArchiveJSON(dir, migrate.ArchiveLabelsFileName, &labels); err != nil || labels != nil {
+		t.Errorf("expected missing file to be ignored, got %v, %v", labels, err)
+	}
+}
+
+func TestOpenAndDownloadArchive(t *testing.T) {
+	ctx := context.Background()
+
+	blobStore, err := blob.NewFileSystemStore(blob.Config{Bucket: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create blob store: %s", err)
+	}
+
+	a := &Archive{
+		maxExtractedSize: 1024,
+		maxEntries:       10,
+		identifierCheck:  check.RepoIdentifierDefault,
+		blobStore:        blobStore,
+	}
+
+	buf := writeTestArchive(t, []testArchiveEntry{
+		{name: "space.json", typeflag: tar.TypeReg, content: `{"version":1,"identifier":"team","repositories":["repo1"]}`},
+		{name: "repos/repo1/info.json", typeflag: tar.TypeReg, content: `{}`},
+	})
+
+	opened, err := a.Open(ctx, buf)
+	if err != nil {
+		t.Fatalf("failed to open archive: %s", err)
+	}
+
+	// the copy of the archive is what Run stages for the import job.
+	file, err := os.Open(opened.file)
+	if err != nil {
+		t.Fatalf("failed to open archive copy: %s", err)
+	}
+	defer file.Close()
+
+	if err = blobStore.Upload(ctx, file, "space-archives/1.tar.gz"); err != nil {
+		t.Fatalf("failed to upload archive: %s", err)
+	}
+
+	if err = opened.Close(); err != nil {
+		t.Fatalf("failed to close archive: %s", err)
+	}
+	for _, name := range []string{opened.file, opened.dir} {
+		if _, err = os.Stat(name); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected %q to be removed, got: %v", name, err)
+		}
+	}
+
+	downloaded := &SpaceArchive{}
+	defer downloaded.Close()
+
+	if err = a.download(ctx, downloaded, "space-archives/1.tar.gz"); err != nil {
+		t.Fatalf("failed to download archive: %s", err)
+	}
+	if err = readArchiveSpace(downloaded.dir, &downloaded.Space); err != nil {
+		t.Fatalf("failed to read space: %s", err)
+	}
+	if len(downloaded.Space.Repositories) != 1 || downloaded.Space.Repositories[0] != "repo1" {
+		t.Errorf("unexpected space: %+v", downloaded.Space)
+	}
+}
+
+func TestExtractArchiveInvalid(t *testing.T) {
+	err := extractArchive(context.Background(), bytes.NewBufferString("not an archive"), t.TempDir(), 1024, 10)
+	if !errors.Is(err, ErrInvalidArchive) {
+		t.Errorf("expected ErrInvalidArchive, got: %v", err)
+	}
+}
+
+func TestExtractArchiveLimits(t *testing.T) {
+	entries := []testArchiveEntry{
+		{name: "a.txt", typeflag: tar.TypeReg, content: "0123456789"},
+		{name: "b.txt", typeflag: tar.TypeReg, content: "0123456789"},
+		{name: "link", typeflag: tar.TypeSymlink},
+	}
+
+	tests := []struct {
+		name       string
+		maxSize    int64
+		maxEntries int
+		expectErr  error
+	}{
+		{name: "within limits", maxSize: 20, maxEntries: 3},
+		{name: "too large", maxSize: 19, maxEntries: 3, expectErr: ErrArchiveTooLarge},
+		// skipped entries count as well.
+		{name: "too many entries", maxSize: 20, maxEntries: 2, expectErr: ErrArchiveTooLarge},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := writeTestArchive(t, entries)
+
+			err := extractArchive(context.Background(), buf, t.TempDir(), test.maxSize, test.maxEntries)
+			if test.expectErr == nil && err != nil {
+				t.Errorf("expected no error, got: %s", err)
+			}
+			if test.expectErr != nil && !errors.Is(err, test.expectErr) {
+				t.Errorf("expected %v, got: %v", test.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestReadArchiveSpaceVersion(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, migrate.ArchiveSpaceFileName), []byte(`{"version":99}`), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write space: %s", err)
+	}
+
+	var space migrate.ExternalSpace
+	if err := readArchiveSpace(dir, &space); !errors.Is(err, ErrInvalidArchive) {
+		t.Errorf("expected ErrInvalidArchive, got: %v", err)
+	}
+}
@@ -19,7 +19,10 @@ import (
 	"strings"
 )
 
-const jobIDPrefix = "import-repo-"
+const (
+	jobIDPrefix        = "import-repo-"
+	archiveJobIDPrefix = "import-space-archive-"
+)
 
 func JobIDFromRepoID(repoID int64) string {
 	return jobIDPrefix + strconv.FormatInt(repoID, 10)
@@ -32,3 +35,7 @@ func RepoIDFromJobID(jobID string) int64 {
 	repoID, _ := strconv.ParseInt(jobID[len(jobIDPrefix):], 10, 64)
 	return repoID
 }
+
+func ArchiveJobIDFromSpaceID(spaceID int64) string {
+	return archiveJobIDPrefix + strconv.FormatInt(spaceID, 10)
+}
@@ -15,23 +15,28 @@
 package importer
 
 import (
+	"github.com/harness/gitness/app/services/instrument"
 	"github.com/harness/gitness/app/services/keywordsearch"
+	"github.com/harness/gitness/app/services/migrate"
 	"github.com/harness/gitness/app/services/publicaccess"
 	"github.com/harness/gitness/app/sse"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/app/url"
 	"github.com/harness/gitness/audit"
+	"github.com/harness/gitness/blob"
 	"github.com/harness/gitness/encrypt"
 	"github.com/harness/gitness/git"
 	"github.com/harness/gitness/job"
 	"github.com/harness/gitness/store/database/dbtx"
 	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/check"
 
 	"github.com/google/wire"
 )
 
 var WireSet = wire.NewSet(
 	ProvideRepoImporter,
+	ProvideArchiveImporter,
 )
 
 func ProvideRepoImporter(
@@ -73,3 +78,67 @@ func ProvideRepoImporter(
 
 	return importer, nil
 }
+
+func ProvideArchiveImporter(
+	config *types.Config,
+	urlProvider url.Provider,
+	git git.Interface,
+	tx dbtx.Transactor,
+	identifierCheck check.RepoIdentifier,
+	spaceStore store.SpaceStore,
+	repoStore store.RepoStore,
+	principalStore store.PrincipalStore,
+	reviewerStore store.PullReqReviewerStore,
+	pipelineStore store.PipelineStore,
+	triggerStore store.TriggerStore,
+	labelStore store.LabelStore,
+	blobStore blob.Store,
+	repoDeleter RepoDeleter,
+	publicAccess publicaccess.Service,
+	indexer keywordsearch.Indexer,
+	scheduler *job.Scheduler,
+	executor *job.Executor,
+	sseStreamer sse.Streamer,
+	auditService audit.Service,
+	instrumentation instrument.Service,
+	pullReqImporter *migrate.PullReq,
+	ruleImporter *migrate.Rule,
+	webhookImporter *migrate.Webhook,
+	labelImporter *migrate.Label,
+) (*Archive, error) {
+	importer := &Archive{
+		defaultBranch:    config.Git.DefaultBranch,
+		maxExtractedSize: config.SpaceArchive.MaxExtractedSize,
+		maxEntries:       config.SpaceArchive.MaxEntries,
+		urlProvider:      urlProvider,
+		git:              git,
+		tx:               tx,
+		identifierCheck:  identifierCheck,
+		spaceStore:       spaceStore,
+		repoStore:        repoStore,
+		principalStore:   principalStore,
+		reviewerStore:    reviewerStore,
+		pipelineStore:    pipelineStore,
+		triggerStore:     triggerStore,
+		labelStore:       labelStore,
+		blobStore:        blobStore,
+		repoDeleter:      repoDeleter,
+		publicAccess:     publicAccess,
+		indexer:          indexer,
+		scheduler:        scheduler,
+		sseStreamer:      sseStreamer,
+		auditService:     auditService,
+		instrumentation:  instrumentation,
+		pullReqImporter:  pullReqImporter,
+		ruleImporter:     ruleImporter,
+		webhookImporter:  webhookImporter,
+		labelImporter:    labelImporter,
+	}
+
+	err := executor.Register(archiveJobType, importer)
+	if err != nil {
+		return nil, err
+	}
+
+	return importer, nil
+}
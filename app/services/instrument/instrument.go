@@ -26,6 +26,7 @@ type CreationType string
 const (
 	CreationTypeCreate CreationType = "CREATE"
 	CreationTypeImport CreationType = "IMPORT"
+	CreationTypeFork   CreationType = "FORK"
 )
 
 type Property string
@@ -0,0 +1,295 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keywordsearch
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+const (
+	// maxIndexedFileSize is the size limit of a single file that gets indexed, larger files are skipped.
+	maxIndexedFileSize = 1 << 20 // 1 MiB
+
+	// binarySniffLen is the number of leading bytes inspected to decide if a file is binary.
+	binarySniffLen = 8000
+
+	indexFileExtension = ".idx"
+)
+
+// trigram is a sequence of three case folded bytes packed into a single integer.
+type trigram uint32
+
+// localIndex is the trigram index of the content of a single repository branch.
+type localIndex struct {
+	RepoID    int64
+	Branch    string
+	CommitSHA string
+	Files     []indexedFile
+
+	// Trigrams holds for each trigram the sorted list of indexes of the files containing it.
+	Trigrams map[trigram][]uint32
+
+	// memSize is the approximate memory used by the index in bytes. It isn't stored with the index.
+	memSize int64
+}
+
+type indexedFile struct {
+	Path    string
+	Content []byte
+}
+
+func newLocalIndex(repoID int64, branch, commitSHA string) *localIndex {
+	return &localIndex{
+		RepoID:    repoID,
+		Branch:    branch,
+		CommitSHA: commitSHA,
+		Files:     make([]indexedFile, 0),
+		Trigrams:  make(map[trigram][]uint32),
+	}
+}
+
+// buildIndex builds an index from a tar archive of the repository content.
+func buildIndex(repoID int64, branch, commitSHA string, archive io.Reader) (*localIndex, error) {
+	idx := newLocalIndex(repoID, branch, commitSHA)
+
+	tr := tar.NewReader(archive)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg || header.Size > maxIndexedFileSize {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %q from archive: %w", header.Name, err)
+		}
+
+		if isBinary(content) {
+			continue
+		}
+
+		idx.add(header.Name, content)
+	}
+
+	return idx, nil
+}
+
+// approximate memory used by the parts of an index besides the file content and paths.
+const (
+	indexedFileOverhead = 64
+	trigramOverhead     = 48
+	postingSize         = 4
+)
+
+func (idx *localIndex) add(path string, content []byte) {
+	fileIdx := uint32(len(idx.Files))
+	idx.Files = append(idx.Files, indexedFile{
+		Path:    path,
+		Content: content,
+	})
+	idx.memSize += int64(len(path) + len(content) + indexedFileOverhead)
+
+	seen := make(map[trigram]struct{})
+	for i := 0; i+3 <= len(content); i++ {
+		t := newTrigram(content[i], content[i+1], content[i+2])
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		if _, ok := idx.Trigrams[t]; !ok {
+			idx.memSize += trigramOverhead
+		}
+		idx.Trigrams[t] = append(idx.Trigrams[t], fileIdx)
+		idx.memSize += postingSize
+	}
+}
+
+// size returns the approximate memory used by the index in bytes.
+func (idx *localIndex) size() int64 {
+	return idx.memSize
+}
+
+// computeSize sets the approximate memory used by an index that was loaded from the disk.
+func (idx *localIndex) computeSize() {
+	idx.memSize = 0
+	for _, file := range idx.Files {
+		idx.memSize += int64(len(file.Path) + len(file.Content) + indexedFileOverhead)
+	}
+	for _, postings := range idx.Trigrams {
+		idx.memSize += trigramOverhead + int64(len(postings))*postingSize
+	}
+}
+
+// candidates returns the indexes of the files that contain all provided trigrams.
+// If no trigrams are provided, all files are candidates.
+func (idx *localIndex) candidates(trigrams []trigram) []uint32 {
+	if len(trigrams) == 0 {
+		all := make([]uint32, len(idx.Files))
+		for i := range all {
+			all[i] = uint32(i)
+		}
+		return all
+	}
+
+	lists := make([][]uint32, len(trigrams))
+	for i, t := range trigrams {
+		lists[i] = idx.Trigrams[t]
+		if len(lists[i]) == 0 {
+			return nil
+		}
+	}
+
+	// intersect starting with the shortest posting list
+	sort.Slice(lists, func(i, j int) bool { return len(lists[i]) < len(lists[j]) })
+
+	result := lists[0]
+	for _, list := range lists[1:] {
+		result = intersect(result, list)
+		if len(result) == 0 {
+			return nil
+		}
+	}
+
+	return result
+}
+
+func intersect(a, b []uint32) []uint32 {
+	result := make([]uint32, 0, min(len(a), len(b)))
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+func newTrigram(a, b, c byte) trigram {
+	return trigram(uint32(toLowerASCII(a))<<16 | uint32(toLowerASCII(b))<<8 | uint32(toLowerASCII(c)))
+}
+
+// trigramsOf returns the distinct trigrams of the provided string.
+func trigramsOf(s string) []trigram {
+	seen := make(map[trigram]struct{})
+	result := make([]trigram, 0)
+	for i := 0; i+3 <= len(s); i++ {
+		t := newTrigram(s[i], s[i+1], s[i+2])
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		result = append(result, t)
+	}
+	return result
+}
+
+func toLowerASCII(b byte) byte {
+	if 'A' <= b && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
+
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content[:min(len(content), binarySniffLen)], 0) >= 0
+}
+
+func indexFilePath(root string, repoID int64) string {
+	return filepath.Join(root, strconv.FormatInt(repoID, 10)+indexFileExtension)
+}
+
+// saveIndex writes the index to the disk. The index file is replaced atomically.
+func saveIndex(root string, idx *localIndex) error {
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+
+	f, err := os.CreateTemp(root, "tmp-*"+indexFileExtension)
+	if err != nil {
+		return fmt.Errorf("failed to create temporary index file: %w", err)
+	}
+	tmpPath := f.Name()
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+	}()
+
+	zw := gzip.NewWriter(f)
+	if err = gob.NewEncoder(zw).Encode(idx); err != nil {
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+	if err = zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress index: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("failed to close index file: %w", err)
+	}
+
+	if err = os.Rename(tmpPath, indexFilePath(root, idx.RepoID)); err != nil {
+		return fmt.Errorf("failed to move index file into place: %w", err)
+	}
+
+	return nil
+}
+
+// loadIndex reads the index of the repository from the disk.
+// Returns nil and no error if the repository doesn't have an index yet.
+func loadIndex(root string, repoID int64) (*localIndex, error) {
+	f, err := os.Open(indexFilePath(root, repoID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil //nolint:nilnil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open index file: %w", err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress index: %w", err)
+	}
+
+	idx := &localIndex{}
+	if err = gob.NewDecoder(zr).Decode(idx); err != nil {
+		return nil, fmt.Errorf("failed to decode index: %w", err)
+	}
+
+	idx.computeSize()
+
+	return idx, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keywordsearch
+
+import (
+	"container/list"
+	"sync"
+)
+
+// indexCache keeps the most recently used indexes in memory,
+// which spares searches from decoding the index files from the disk every time.
+// The cache is bounded by the total size of the indexes, indexes larger than the cache aren't kept.
+// Indexes are never modified once built, so cached indexes can be shared between concurrent searches.
+type indexCache struct {
+	mu      sync.Mutex
+	maxSize int64
+	size    int64
+	entries map[int64]*list.Element
+	order   *list.List // front is the most recently used index
+}
+
+func newIndexCache(maxSize int64) *indexCache {
+	return &indexCache{
+		maxSize: maxSize,
+		entries: make(map[int64]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *indexCache) get(repoID int64) *localIndex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[repoID]
+	if !ok {
+		return nil
+	}
+
+	c.order.MoveToFront(e)
+
+	return e.Value.(*localIndex) //nolint:errcheck
+}
+
+func (c *indexCache) put(idx *localIndex) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removeLocked(idx.RepoID)
+
+	if idx.size() > c.maxSize {
+		return
+	}
+
+	c.entries[idx.RepoID] = c.order.PushFront(idx)
+	c.size += idx.size()
+
+	for c.size > c.maxSize {
+		c.removeLocked(c.order.Back().Value.(*localIndex).RepoID) //nolint:errcheck
+	}
+}
+
+func (c *indexCache) remove(repoID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removeLocked(repoID)
+}
+
+func (c *indexCache) removeLocked(repoID int64) {
+	e, ok := c.entries[repoID]
+	if !ok {
+		return
+	}
+
+	c.order.Remove(e)
+	delete(c.entries, repoID)
+	c.size -= e.Value.(*localIndex).size() //nolint:errcheck
+}
@@ -0,0 +1,185 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keywordsearch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+)
+
+func newTestIndex(repoID int64, commitSHA string) *localIndex {
+	idx := newLocalIndex(repoID, "main", commitSHA)
+	idx.add("main.go", bytes.Repeat([]byte("a"), 100))
+	return idx
+}
+
+func TestIndexCache(t *testing.T) {
+	// the cache fits two indexes.
+	c := newIndexCache(2 * newTestIndex(0, "").size())
+
+	c.put(newTestIndex(1, "a"))
+	c.put(newTestIndex(2, "b"))
+
+	// touch the first index so that the second one is the least recently used
+	if idx := c.get(1); idx == nil || idx.CommitSHA != "a" {
+		t.Fatalf("expected index of repo 1, got %+v", idx)
+	}
+
+	c.put(newTestIndex(3, "c"))
+
+	if c.get(2) != nil {
+		t.Error("expected least recently used index to be evicted")
+	}
+	if c.get(1) == nil || c.get(3) == nil {
+		t.Error("expected recently used indexes to stay cached")
+	}
+
+	c.put(newTestIndex(1, "d"))
+	if idx := c.get(1); idx == nil || idx.CommitSHA != "d" {
+		t.Errorf("expected replaced index of repo 1, got %+v", idx)
+	}
+	if c.get(3) == nil {
+		t.Error("expected replacing an index to keep the other indexes cached")
+	}
+
+	c.remove(1)
+	if c.get(1) != nil {
+		t.Error("expected removed index to be gone")
+	}
+}
+
+func TestIndexCacheDisabled(t *testing.T) {
+	c := newIndexCache(0)
+	c.put(newTestIndex(1, "a"))
+	if c.get(1) != nil {
+		t.Error("expected disabled cache to stay empty")
+	}
+}
+
+func TestIndexCacheTooLarge(t *testing.T) {
+	idx := newTestIndex(1, "a")
+	c := newIndexCache(idx.size() - 1)
+	c.put(idx)
+	if c.get(1) != nil || c.size != 0 {
+		t.Error("expected index larger than the cache not to be cached")
+	}
+}
+
+func TestLoadIndexSize(t *testing.T) {
+	root := t.TempDir()
+	idx := newTestIndex(7, "abc")
+	if err := saveIndex(root, idx); err != nil {
+		t.Fatalf("failed to save index: %s", err)
+	}
+
+	loaded, err := loadIndex(root, 7)
+	if err != nil {
+		t.Fatalf("failed to load index: %s", err)
+	}
+	if loaded.size() != idx.size() {
+		t.Errorf("expected size %d of loaded index, got %d", idx.size(), loaded.size())
+	}
+}
+
+func TestLocalIndexSearcherDelete(t *testing.T) {
+	root := t.TempDir()
+	s := NewLocalIndexSearcher(root, 1<<20, nil, nil)
+
+	idx := newLocalIndex(7, "main", "abc")
+	idx.add("main.go", []byte("package main"))
+	if err := saveIndex(root, idx); err != nil {
+		t.Fatalf("failed to save index: %s", err)
+	}
+
+	// the index is served from the disk first and from the cache afterwards
+	for range 2 {
+		got := s.findIndex(context.Background(), 7)
+		if got == nil || got.CommitSHA != "abc" || len(got.Files) != 1 {
+			t.Fatalf("unexpected index: %+v", got)
+		}
+	}
+
+	if err := s.Delete(context.Background(), 7); err != nil {
+		t.Fatalf("failed to delete index: %s", err)
+	}
+
+	if _, err := os.Stat(indexFilePath(root, 7)); !os.IsNotExist(err) {
+		t.Errorf("expected index file to be removed, got: %v", err)
+	}
+	if s.cache.get(7) != nil {
+		t.Error("expected index to be removed from the cache")
+	}
+
+	// deleting a missing index is not an error
+	if err := s.Delete(context.Background(), 7); err != nil {
+		t.Errorf("failed to delete missing index: %s", err)
+	}
+
+	// an indexing that waited for the deletion doesn't recreate the index.
+	if _, err := s.index(context.Background(), &types.Repository{ID: 7}); !errors.Is(err, errIndexDeleted) {
+		t.Errorf("expected errIndexDeleted, got: %v", err)
+	}
+	if err := s.Index(context.Background(), &types.Repository{ID: 7}); err != nil {
+		t.Errorf("expected indexing of deleted repo to be a no-op, got: %s", err)
+	}
+}
+
+type testRepoStore struct {
+	store.RepoStore
+	found chan int64
+}
+
+func (s *testRepoStore) Find(_ context.Context, id int64) (*types.Repository, error) {
+	s.found <- id
+	return nil, gitness_store.ErrResourceNotFound
+}
+
+func TestLocalIndexSearcherSearch_SkipsMissingIndexes(t *testing.T) {
+	root := t.TempDir()
+	repoStore := &testRepoStore{found: make(chan int64, 1)}
+	s := NewLocalIndexSearcher(root, 1<<20, nil, repoStore)
+
+	idx := newLocalIndex(1, "main", "abc")
+	idx.add("main.go", []byte("package main"))
+	if err := saveIndex(root, idx); err != nil {
+		t.Fatalf("failed to save index: %s", err)
+	}
+
+	result, err := s.Search(context.Background(), []int64{1, 2}, "package", false, 0)
+	if err != nil {
+		t.Fatalf("failed to search: %s", err)
+	}
+	if len(result.FileMatches) != 1 || result.FileMatches[0].RepoID != 1 {
+		t.Errorf("expected a match in repo 1, got %+v", result.FileMatches)
+	}
+
+	// the repository without an index is indexed in the background.
+	select {
+	case id := <-repoStore.found:
+		if id != 2 {
+			t.Errorf("expected repo 2 to be indexed, got %d", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("expected repo 2 to be indexed in the background")
+	}
+}
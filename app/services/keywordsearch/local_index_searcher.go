@@ -16,27 +16,307 @@ package keywordsearch
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
 
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/git/api"
 	"github.com/harness/gitness/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultMaxResultCount is the maximum number of matched lines returned if the caller didn't provide a limit.
+	defaultMaxResultCount = 100
+
+	// maxBackgroundIndexing is the maximum number of repositories indexed concurrently for searches.
+	maxBackgroundIndexing = 2
 )
 
+// errIndexDeleted is returned when indexing a repository whose index was deleted.
+var errIndexDeleted = errors.New("index of the repository was deleted")
+
+// LocalIndexSearcher is a built-in keyword search engine.
+// It maintains a trigram index of the default branch of every repository on the local disk.
 type LocalIndexSearcher struct {
+	indexRoot string
+	git       git.Interface
+	repoStore store.RepoStore
+
+	// repoLocks holds a *repoLock per repository. The entries are never removed, so that an indexing
+	// waiting for the lock of a deleted repository sees that the repository was deleted.
+	repoLocks sync.Map
+
+	// pending holds the repositories that are indexed in the background for searches.
+	pending  sync.Map
+	indexSem chan struct{}
+
+	cache *indexCache
 }
 
-func NewLocalIndexSearcher() *LocalIndexSearcher {
-	return &LocalIndexSearcher{}
+// repoLock serializes indexing and deletion of the index of a repository.
+type repoLock struct {
+	sync.Mutex
+	deleted bool
 }
 
+func NewLocalIndexSearcher(
+	indexRoot string,
+	cacheSize int64,
+	git git.Interface,
+	repoStore store.RepoStore,
+) *LocalIndexSearcher {
+	return &LocalIndexSearcher{
+		indexRoot: indexRoot,
+		git:       git,
+		repoStore: repoStore,
+		indexSem:  make(chan struct{}, maxBackgroundIndexing),
+		cache:     newIndexCache(cacheSize),
+	}
+}
+
+// Search searches the indexes of the provided repositories for the query.
+// Repositories that weren't indexed yet are skipped and indexed in the background.
 func (s *LocalIndexSearcher) Search(
-	_ context.Context,
-	_ []int64,
-	_ string,
-	_ bool,
-	_ int,
+	ctx context.Context,
+	repoIDs []int64,
+	query string,
+	enableRegex bool,
+	maxResultCount int,
 ) (types.SearchResult, error) {
-	return types.SearchResult{}, nil
+	m, err := newMatcher(query, enableRegex)
+	if err != nil {
+		return types.SearchResult{}, err
+	}
+
+	if maxResultCount <= 0 {
+		maxResultCount = defaultMaxResultCount
+	}
+
+	repoIDs = slices.Clone(repoIDs)
+	slices.Sort(repoIDs)
+
+	result := types.SearchResult{
+		FileMatches: make([]types.FileMatch, 0),
+	}
+
+	for _, repoID := range repoIDs {
+		if result.Stats.TotalMatches >= maxResultCount {
+			break
+		}
+
+		idx := s.findIndex(ctx, repoID)
+		if idx == nil {
+			s.indexInBackground(ctx, repoID)
+			continue
+		}
+
+		for _, fileIdx := range idx.candidates(m.trigrams()) {
+			remaining := maxResultCount - result.Stats.TotalMatches
+			if remaining <= 0 {
+				break
+			}
+
+			file := idx.Files[fileIdx]
+
+			lines := matchFile(m, file.Content, remaining)
+			if len(lines) == 0 {
+				continue
+			}
+
+			matches := make([]types.Match, len(lines))
+			for i, line := range lines {
+				matches[i] = line.toMatch()
+			}
+
+			result.FileMatches = append(result.FileMatches, types.FileMatch{
+				FileName:   file.Path,
+				RepoID:     repoID,
+				RepoBranch: idx.Branch,
+				Language:   detectLanguage(file.Path),
+				Matches:    matches,
+			})
+			result.Stats.TotalFiles++
+			result.Stats.TotalMatches += len(matches)
+		}
+	}
+
+	return result, nil
+}
+
+// Index updates the index of the default branch of the repository.
+// The index isn't rebuilt if the default branch hasn't changed since it was last indexed.
+func (s *LocalIndexSearcher) Index(ctx context.Context, repo *types.Repository) error {
+	_, err := s.index(ctx, repo)
+	if errors.Is(err, errIndexDeleted) {
+		return nil
+	}
+	return err
 }
 
-func (s *LocalIndexSearcher) Index(_ context.Context, _ *types.Repository) error {
+func (s *LocalIndexSearcher) lockRepo(repoID int64) *repoLock {
+	l, _ := s.repoLocks.LoadOrStore(repoID, &repoLock{})
+	lock := l.(*repoLock) //nolint:errcheck
+	lock.Lock()
+	return lock
+}
+
+func (s *LocalIndexSearcher) index(ctx context.Context, repo *types.Repository) (*localIndex, error) {
+	lock := s.lockRepo(repo.ID)
+	defer lock.Unlock()
+
+	if lock.deleted {
+		return nil, errIndexDeleted
+	}
+
+	existing := s.cache.get(repo.ID)
+	if existing == nil {
+		var err error
+		existing, err = loadIndex(s.indexRoot, repo.ID)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("failed to load existing index of repo %d, rebuilding it", repo.ID)
+			existing = nil
+		}
+	}
+
+	commitSHA := ""
+	branchOut, err := s.git.GetBranch(ctx, &git.GetBranchParams{
+		ReadParams: git.CreateReadParams(repo),
+		BranchName: repo.DefaultBranch,
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get default branch: %w", err)
+	}
+	if err == nil {
+		commitSHA = branchOut.Branch.SHA.String()
+	}
+
+	if existing != nil && existing.Branch == repo.DefaultBranch && existing.CommitSHA == commitSHA {
+		s.cache.put(existing)
+		return existing, nil
+	}
+
+	var idx *localIndex
+	if commitSHA == "" {
+		// the repository is empty or the default branch doesn't exist yet
+		idx = newLocalIndex(repo.ID, repo.DefaultBranch, commitSHA)
+	} else {
+		idx, err = s.buildIndexFromGit(ctx, repo, commitSHA)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = saveIndex(s.indexRoot, idx); err != nil {
+		return nil, fmt.Errorf("failed to save index: %w", err)
+	}
+
+	s.cache.put(idx)
+
+	log.Ctx(ctx).Debug().Msgf("indexed %d files of repo %d at commit %s",
+		len(idx.Files), repo.ID, commitSHA)
+
+	return idx, nil
+}
+
+func (s *LocalIndexSearcher) buildIndexFromGit(
+	ctx context.Context,
+	repo *types.Repository,
+	commitSHA string,
+) (*localIndex, error) {
+	pr, pw := io.Pipe()
+
+	go func() {
+		err := s.git.Archive(ctx, git.ArchiveParams{
+			ReadParams: git.CreateReadParams(repo),
+			ArchiveParams: api.ArchiveParams{
+				Format:  api.ArchiveFormatTar,
+				Treeish: commitSHA,
+			},
+		}, pw)
+		_ = pw.CloseWithError(err)
+	}()
+
+	idx, err := buildIndex(repo.ID, repo.DefaultBranch, commitSHA, pr)
+
+	// unblock the archive writer in case the index build stopped early
+	_ = pr.CloseWithError(io.ErrClosedPipe)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build index: %w", err)
+	}
+
+	return idx, nil
+}
+
+// Delete removes the index of the repository from the disk and from the cache.
+// The repository can't be indexed anymore afterwards, as the index is only deleted for purged repositories.
+func (s *LocalIndexSearcher) Delete(ctx context.Context, repoID int64) error {
+	lock := s.lockRepo(repoID)
+	defer lock.Unlock()
+
+	lock.deleted = true
+
+	s.cache.remove(repoID)
+
+	err := os.Remove(indexFilePath(s.indexRoot, repoID))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove index file: %w", err)
+	}
+
+	log.Ctx(ctx).Debug().Msgf("deleted index of repo %d", repoID)
+
 	return nil
 }
+
+// findIndex returns the index of the repository from the cache or from the disk.
+// It returns nil if the repository doesn't have a usable index.
+func (s *LocalIndexSearcher) findIndex(ctx context.Context, repoID int64) *localIndex {
+	if idx := s.cache.get(repoID); idx != nil {
+		return idx
+	}
+
+	idx, err := loadIndex(s.indexRoot, repoID)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to load index of repo %d, rebuilding it", repoID)
+		return nil
+	}
+	if idx != nil {
+		s.cache.put(idx)
+	}
+
+	return idx
+}
+
+// indexInBackground indexes the repository without blocking the caller,
+// unless the repository is already being indexed in the background.
+func (s *LocalIndexSearcher) indexInBackground(ctx context.Context, repoID int64) {
+	if _, pending := s.pending.LoadOrStore(repoID, struct{}{}); pending {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		defer s.pending.Delete(repoID)
+
+		s.indexSem <- struct{}{}
+		defer func() { <-s.indexSem }()
+
+		repo, err := s.repoStore.Find(ctx, repoID)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("failed to find repo %d for indexing", repoID)
+			return
+		}
+
+		if err = s.Index(ctx, repo); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("failed to index repo %d", repoID)
+		}
+	}()
+}
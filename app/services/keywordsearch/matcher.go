@@ -0,0 +1,297 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keywordsearch
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/types"
+)
+
+// matcher finds all matches of a search query within a single line.
+type matcher interface {
+	// findAll returns the start and end offsets of all matches within the line.
+	findAll(line []byte) [][]int
+
+	// trigrams returns the trigrams that every file containing a match must contain.
+	trigrams() []trigram
+}
+
+func newMatcher(query string, enableRegex bool) (matcher, error) {
+	if !enableRegex {
+		return newLiteralMatcher(query), nil
+	}
+
+	return newRegexMatcher(query)
+}
+
+// literalMatcher performs case-insensitive (ASCII only) search of a literal string.
+type literalMatcher struct {
+	query []byte
+}
+
+func newLiteralMatcher(query string) *literalMatcher {
+	return &literalMatcher{
+		query: lowerASCII([]byte(query)),
+	}
+}
+
+func (m *literalMatcher) findAll(line []byte) [][]int {
+	lower := lowerASCII(line)
+
+	var result [][]int
+	for offset := 0; offset < len(lower); {
+		i := bytes.Index(lower[offset:], m.query)
+		if i < 0 {
+			break
+		}
+		start := offset + i
+		end := start + len(m.query)
+		result = append(result, []int{start, end})
+		offset = end
+	}
+
+	return result
+}
+
+func (m *literalMatcher) trigrams() []trigram {
+	return trigramsOf(string(m.query))
+}
+
+// regexMatcher performs case-insensitive search using a regular expression.
+type regexMatcher struct {
+	re       *regexp.Regexp
+	required []trigram
+}
+
+func newRegexMatcher(query string) (*regexMatcher, error) {
+	re, err := regexp.Compile("(?i)" + query)
+	if err != nil {
+		return nil, errors.InvalidArgument("invalid regular expression: %s", err)
+	}
+
+	parsed, err := syntax.Parse(query, syntax.Perl)
+	if err != nil {
+		return nil, errors.InvalidArgument("invalid regular expression: %s", err)
+	}
+
+	var required []trigram
+	for _, literal := range requiredLiterals(parsed.Simplify()) {
+		required = append(required, trigramsOf(literal)...)
+	}
+
+	return &regexMatcher{
+		re:       re,
+		required: required,
+	}, nil
+}
+
+func (m *regexMatcher) findAll(line []byte) [][]int {
+	var result [][]int
+	for _, loc := range m.re.FindAllIndex(line, -1) {
+		// empty matches aren't useful for the search results
+		if loc[0] == loc[1] {
+			continue
+		}
+		result = append(result, loc)
+	}
+
+	return result
+}
+
+func (m *regexMatcher) trigrams() []trigram {
+	return m.required
+}
+
+// requiredLiterals returns the ASCII literal strings that must be part of any match of the regular expression.
+// The list is not exhaustive, it is used only to narrow down the files that need to be scanned.
+func requiredLiterals(re *syntax.Regexp) []string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		if s := string(re.Rune); isASCII(s) {
+			return []string{s}
+		}
+		return nil
+
+	case syntax.OpCapture, syntax.OpPlus:
+		return requiredLiterals(re.Sub[0])
+
+	case syntax.OpRepeat:
+		if re.Min < 1 {
+			return nil
+		}
+		return requiredLiterals(re.Sub[0])
+
+	case syntax.OpConcat:
+		var result []string
+		var current strings.Builder
+		flush := func() {
+			if current.Len() > 0 {
+				result = append(result, current.String())
+				current.Reset()
+			}
+		}
+
+		for _, sub := range re.Sub {
+			if sub.Op == syntax.OpLiteral && isASCII(string(sub.Rune)) {
+				current.WriteString(string(sub.Rune))
+				continue
+			}
+			flush()
+			result = append(result, requiredLiterals(sub)...)
+		}
+		flush()
+
+		return result
+
+	default:
+		return nil
+	}
+}
+
+// matchFile returns all matching lines of the file content.
+// At most maxMatches lines are returned.
+func matchFile(m matcher, content []byte, maxMatches int) []matchedLine {
+	var result []matchedLine
+
+	lines := bytes.Split(content, []byte{'\n'})
+	for i, line := range lines {
+		if len(result) >= maxMatches {
+			break
+		}
+
+		locs := m.findAll(line)
+		if len(locs) == 0 {
+			continue
+		}
+
+		var before, after []byte
+		if i > 0 {
+			before = lines[i-1]
+		}
+		if i+1 < len(lines) {
+			after = lines[i+1]
+		}
+
+		result = append(result, matchedLine{
+			lineNum: i + 1,
+			line:    line,
+			locs:    locs,
+			before:  before,
+			after:   after,
+		})
+	}
+
+	return result
+}
+
+type matchedLine struct {
+	lineNum int
+	line    []byte
+	locs    [][]int
+	before  []byte
+	after   []byte
+}
+
+func (l matchedLine) toMatch() types.Match {
+	fragments := make([]types.Fragment, len(l.locs))
+	prev := 0
+	for i, loc := range l.locs {
+		fragments[i] = types.Fragment{
+			Pre:   toValidString(l.line[prev:loc[0]]),
+			Match: toValidString(l.line[loc[0]:loc[1]]),
+		}
+		prev = loc[1]
+	}
+	fragments[len(fragments)-1].Post = toValidString(l.line[prev:])
+
+	return types.Match{
+		LineNum:   l.lineNum,
+		Fragments: fragments,
+		Before:    toValidString(l.before),
+		After:     toValidString(l.after),
+	}
+}
+
+var languages = map[string]string{
+	".c":     "C",
+	".cc":    "C++",
+	".cpp":   "C++",
+	".cs":    "C#",
+	".css":   "CSS",
+	".go":    "Go",
+	".h":     "C",
+	".hpp":   "C++",
+	".html":  "HTML",
+	".java":  "Java",
+	".js":    "JavaScript",
+	".json":  "JSON",
+	".jsx":   "JavaScript",
+	".kt":    "Kotlin",
+	".md":    "Markdown",
+	".php":   "PHP",
+	".py":    "Python",
+	".rb":    "Ruby",
+	".rs":    "Rust",
+	".scala": "Scala",
+	".scss":  "SCSS",
+	".sh":    "Shell",
+	".sql":   "SQL",
+	".swift": "Swift",
+	".toml":  "TOML",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".xml":   "XML",
+	".yaml":  "YAML",
+	".yml":   "YAML",
+}
+
+// detectLanguage returns the programming language of the file based on the file extension.
+func detectLanguage(fileName string) string {
+	if path.Base(fileName) == "Dockerfile" {
+		return "Dockerfile"
+	}
+	if path.Base(fileName) == "Makefile" {
+		return "Makefile"
+	}
+	return languages[strings.ToLower(path.Ext(fileName))]
+}
+
+func lowerASCII(b []byte) []byte {
+	result := make([]byte, len(b))
+	for i, c := range b {
+		result[i] = toLowerASCII(c)
+	}
+	return result
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+func toValidString(b []byte) string {
+	return strings.ToValidUTF8(string(b), string(utf8.RuneError))
+}
@@ -0,0 +1,199 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keywordsearch
+
+import (
+	"archive/tar"
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/harness/gitness/types"
+)
+
+func TestMatchFile(t *testing.T) {
+	const content = "package main\n\nfunc Foo() {\n\treturn foo(fOO)\n}\n"
+
+	tests := []struct {
+		name        string
+		query       string
+		enableRegex bool
+		want        []types.Match
+	}{
+		{
+			name:  "literal-case-insensitive",
+			query: "foo",
+			want: []types.Match{
+				{
+					LineNum:   3,
+					Fragments: []types.Fragment{{Pre: "func ", Match: "Foo", Post: "() {"}},
+					Before:    "",
+					After:     "\treturn foo(fOO)",
+				},
+				{
+					LineNum: 4,
+					Fragments: []types.Fragment{
+						{Pre: "\treturn ", Match: "foo"},
+						{Pre: "(", Match: "fOO", Post: ")"},
+					},
+					Before: "func Foo() {",
+					After:  "}",
+				},
+			},
+		},
+		{
+			name:        "regex",
+			query:       `func \w+\(`,
+			enableRegex: true,
+			want: []types.Match{
+				{
+					LineNum:   3,
+					Fragments: []types.Fragment{{Pre: "", Match: "func Foo(", Post: ") {"}},
+					Before:    "",
+					After:     "\treturn foo(fOO)",
+				},
+			},
+		},
+		{
+			name:  "no-match",
+			query: "bar",
+			want:  nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := newMatcher(test.query, test.enableRegex)
+			if err != nil {
+				t.Fatalf("failed to create matcher: %s", err)
+			}
+
+			var got []types.Match
+			for _, line := range matchFile(m, []byte(content), 10) {
+				got = append(got, line.toMatch())
+			}
+
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("want=%+v got=%+v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestRequiredLiterals(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: `hello`, want: []string{"hello"}},
+		{query: `hello\s+world`, want: []string{"hello", "world"}},
+		{query: `(abc)+x?yz`, want: []string{"abc", "yz"}},
+		{query: `abc|xyz`, want: nil},
+		{query: `a*`, want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			m, err := newRegexMatcher(test.query)
+			if err != nil {
+				t.Fatalf("failed to create matcher: %s", err)
+			}
+
+			var want []trigram
+			for _, literal := range test.want {
+				want = append(want, trigramsOf(literal)...)
+			}
+
+			if !reflect.DeepEqual(want, m.trigrams()) {
+				t.Errorf("want=%v got=%v", test.want, m.trigrams())
+			}
+		})
+	}
+}
+
+func TestIndex_Candidates(t *testing.T) {
+	files := map[string]string{
+		"a.go":    "package a // Hello World",
+		"b.go":    "package b // hello there",
+		"c.bin":   "binary\x00content hello",
+		"d/e.txt": "nothing to see",
+	}
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, name := range []string{"a.go", "b.go", "c.bin", "d/e.txt"} {
+		content := files[name]
+		if err := tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+		}); err != nil {
+			t.Fatalf("failed to write tar header: %s", err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write tar content: %s", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar writer: %s", err)
+	}
+
+	idx, err := buildIndex(1, "main", "sha", buf)
+	if err != nil {
+		t.Fatalf("failed to build index: %s", err)
+	}
+
+	if len(idx.Files) != 3 {
+		t.Fatalf("expected binary file to be skipped, got %d files", len(idx.Files))
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "HELLO", want: []string{"a.go", "b.go"}},
+		{query: "world", want: []string{"a.go"}},
+		{query: "see", want: []string{"d/e.txt"}},
+		{query: "missing", want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			var got []string
+			for _, fileIdx := range idx.candidates(trigramsOf(test.query)) {
+				got = append(got, idx.Files[fileIdx].Path)
+			}
+
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("want=%v got=%v", test.want, got)
+			}
+		})
+	}
+
+	root := t.TempDir()
+	if err = saveIndex(root, idx); err != nil {
+		t.Fatalf("failed to save index: %s", err)
+	}
+
+	loaded, err := loadIndex(root, 1)
+	if err != nil {
+		t.Fatalf("failed to load index: %s", err)
+	}
+
+	if !reflect.DeepEqual(idx, loaded) {
+		t.Errorf("loaded index doesn't match the saved one")
+	}
+}
@@ -33,6 +33,12 @@ type Config struct {
 	EventReaderName string
 	Concurrency     int
 	MaxRetries      int
+
+	// IndexRoot is the directory where the local search indexes are stored.
+	IndexRoot string
+
+	// IndexCacheSize is the max total size (in bytes) of the indexes kept in memory, zero disables the cache.
+	IndexCacheSize int64
 }
 
 func (c *Config) Prepare() error {
@@ -48,6 +54,12 @@ func (c *Config) Prepare() error {
 	if c.MaxRetries < 0 {
 		return errors.New("config.MaxRetries can't be negative")
 	}
+	if c.IndexRoot == "" {
+		return errors.New("config.IndexRoot is required")
+	}
+	if c.IndexCacheSize < 0 {
+		return errors.New("config.IndexCacheSize can't be negative")
+	}
 	return nil
 }
 
@@ -106,6 +118,7 @@ func NewService(
 				))
 
 			_ = r.RegisterDefaultBranchUpdated((service.handleUpdateDefaultBranch))
+			_ = r.RegisterRepoDeleted(service.handleRepoDeleted)
 			return nil
 		})
 	if err != nil {
@@ -0,0 +1,350 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldapsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/app/auth/ldap"
+	"github.com/harness/gitness/app/bootstrap"
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/job"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/check"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	jobTypeSync        = "gitness:ldap:group-sync"
+	jobMaxDurationSync = 15 * time.Minute
+)
+
+var illegalIdentifierChars = regexp.MustCompile(`[^a-zA-Z0-9-_.]+`)
+
+// errNotManaged is returned if the usergroup of a directory group isn't managed by the sync.
+var errNotManaged = errors.New("usergroup isn't managed by the LDAP sync")
+
+// Service periodically syncs the groups of the LDAP directory as usergroups of the configured space.
+// The sync only changes the usergroups it created and only removes the memberships it added,
+// so usergroups and members managed manually or by the OIDC mappings are kept.
+type Service struct {
+	config         *types.Config
+	directory      *ldap.Directory
+	tx             dbtx.Transactor
+	spaceCache     refcache.SpaceCache
+	identityStore  store.LDAPIdentityStore
+	userGroupStore store.UserGroupStore
+	memberStore    store.UserGroupMemberStore
+	scheduler      *job.Scheduler
+	executor       *job.Executor
+}
+
+func NewService(
+	config *types.Config,
+	directory *ldap.Directory,
+	tx dbtx.Transactor,
+	spaceCache refcache.SpaceCache,
+	identityStore store.LDAPIdentityStore,
+	userGroupStore store.UserGroupStore,
+	memberStore store.UserGroupMemberStore,
+	scheduler *job.Scheduler,
+	executor *job.Executor,
+) *Service {
+	return &Service{
+		config:         config,
+		directory:      directory,
+		tx:             tx,
+		spaceCache:     spaceCache,
+		identityStore:  identityStore,
+		userGroupStore: userGroupStore,
+		memberStore:    memberStore,
+		scheduler:      scheduler,
+		executor:       executor,
+	}
+}
+
+// Register registers the recurring group sync job. It's a no-op if the group sync isn't enabled.
+func (s *Service) Register(ctx context.Context) error {
+	if !s.directory.GroupSyncEnabled() {
+		return nil
+	}
+
+	err := s.executor.Register(jobTypeSync, s)
+	if err != nil {
+		return fmt.Errorf("failed to register job handler for LDAP group sync: %w", err)
+	}
+
+	err = s.scheduler.AddRecurring(ctx, jobTypeSync, jobTypeSync, s.config.LDAP.GroupSyncCron, jobMaxDurationSync)
+	if err != nil {
+		return fmt.Errorf("failed to schedule LDAP group sync job: %w", err)
+	}
+
+	return nil
+}
+
+// Handle is the handler of the group sync background job.
+func (s *Service) Handle(ctx context.Context, _ string, _ job.ProgressReporter) (string, error) {
+	if !s.directory.GroupSyncEnabled() {
+		return "", nil
+	}
+
+	synced, err := s.Sync(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("synced %d LDAP groups", synced), nil
+}
+
+// Sync syncs all directory groups and their members and returns the number of synced groups.
+func (s *Service) Sync(ctx context.Context) (int, error) {
+	space, err := s.spaceCache.Get(ctx, s.config.LDAP.GroupSpace)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find space %q for the directory groups: %w", s.config.LDAP.GroupSpace, err)
+	}
+
+	dirUsers, err := s.directory.ListUsers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list directory users: %w", err)
+	}
+
+	dirGroups, err := s.directory.ListGroups(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list directory groups: %w", err)
+	}
+
+	identities, err := s.identityStore.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list directory identities: %w", err)
+	}
+
+	resolver := newMemberResolver(dirUsers, identities)
+	syncPrincipalID := bootstrap.NewSystemServiceSession().Principal.ID
+	synced := make(map[string]struct{}, len(dirGroups))
+
+	for _, dirGroup := range dirGroups {
+		identifier := sanitizeIdentifier(dirGroup.Identifier)
+		if identifier == "" {
+			log.Ctx(ctx).Warn().Msgf("skipping directory group %q with invalid identifier", dirGroup.DN)
+			continue
+		}
+
+		if _, ok := synced[strings.ToLower(identifier)]; ok {
+			log.Ctx(ctx).Warn().Msgf("skipping directory group %q with duplicate identifier %q",
+				dirGroup.DN, identifier)
+			continue
+		}
+
+		principalIDs := resolver.resolve(dirGroup.Members)
+
+		err = s.syncGroup(ctx, syncPrincipalID, space.ID, &types.UserGroup{
+			Identifier:  identifier,
+			Name:        dirGroup.Identifier,
+			Description: dirGroup.Description,
+			ManagedBy:   enum.UserGroupSourceLDAP,
+		}, principalIDs)
+		if errors.Is(err, errNotManaged) {
+			log.Ctx(ctx).Warn().Msgf("skipping directory group %q, usergroup %q isn't managed by the LDAP sync",
+				dirGroup.DN, identifier)
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to sync directory group %q: %w", dirGroup.DN, err)
+		}
+
+		synced[strings.ToLower(identifier)] = struct{}{}
+	}
+
+	// remove the synced members of the usergroups whose directory groups got deleted.
+	userGroups, err := s.userGroupStore.List(ctx, space.ID, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list usergroups: %w", err)
+	}
+
+	for _, userGroup := range userGroups {
+		if userGroup.ManagedBy != enum.UserGroupSourceLDAP {
+			continue
+		}
+
+		if _, ok := synced[strings.ToLower(userGroup.Identifier)]; ok {
+			continue
+		}
+
+		if err = s.syncMembers(ctx, syncPrincipalID, userGroup.ID, nil); err != nil {
+			return 0, fmt.Errorf("failed to remove synced members of usergroup %q: %w", userGroup.Identifier, err)
+		}
+	}
+
+	return len(synced), nil
+}
+
+// syncGroup creates the usergroup of the directory group or updates it if it was created by the sync.
+// It returns errNotManaged if a usergroup with the same identifier was created otherwise.
+func (s *Service) syncGroup(
+	ctx context.Context,
+	syncPrincipalID int64,
+	spaceID int64,
+	in *types.UserGroup,
+	principalIDs map[int64]struct{},
+) error {
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		now := time.Now().UnixMilli()
+
+		userGroup, err := s.userGroupStore.FindByIdentifier(ctx, spaceID, in.Identifier)
+		switch {
+		case errors.Is(err, gitness_store.ErrResourceNotFound):
+			in.Created = now
+			in.Updated = now
+			if err = s.userGroupStore.Create(ctx, spaceID, in); err != nil {
+				return fmt.Errorf("failed to create usergroup: %w", err)
+			}
+			userGroup = in
+		case err != nil:
+			return fmt.Errorf("failed to find usergroup: %w", err)
+		case userGroup.ManagedBy != enum.UserGroupSourceLDAP:
+			return errNotManaged
+		case userGroup.Name != in.Name || userGroup.Description != in.Description:
+			userGroup.Name = in.Name
+			userGroup.Description = in.Description
+			userGroup.Updated = now
+			if err = s.userGroupStore.Update(ctx, userGroup); err != nil {
+				return fmt.Errorf("failed to update usergroup: %w", err)
+			}
+		}
+
+		return s.syncMembers(ctx, syncPrincipalID, userGroup.ID, principalIDs)
+	})
+}
+
+// syncMembers adds the missing members to the usergroup and removes the members
+// that were added by the sync but aren't members of the directory group anymore.
+func (s *Service) syncMembers(
+	ctx context.Context,
+	syncPrincipalID int64,
+	userGroupID int64,
+	principalIDs map[int64]struct{},
+) error {
+	members, err := s.memberStore.ListByUserGroup(ctx, userGroupID)
+	if err != nil {
+		return fmt.Errorf("failed to list usergroup members: %w", err)
+	}
+
+	existing := make(map[int64]struct{}, len(members))
+	for _, m := range members {
+		existing[m.PrincipalID] = struct{}{}
+
+		if _, ok := principalIDs[m.PrincipalID]; ok || m.Source != enum.UserGroupSourceLDAP {
+			continue
+		}
+
+		if err = s.memberStore.Remove(ctx, userGroupID, m.PrincipalID); err != nil {
+			return fmt.Errorf("failed to remove usergroup member: %w", err)
+		}
+	}
+
+	now := time.Now().UnixMilli()
+	for principalID := range principalIDs {
+		if _, ok := existing[principalID]; ok {
+			continue
+		}
+
+		err = s.memberStore.Add(ctx, &types.UserGroupMember{
+			UserGroupID: userGroupID,
+			PrincipalID: principalID,
+			CreatedBy:   syncPrincipalID,
+			Created:     now,
+			Source:      enum.UserGroupSourceLDAP,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add usergroup member: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// memberResolver maps the values of the member attribute of directory groups to local users.
+// Only the users linked to the directory users are resolved, users that never signed in are skipped.
+type memberResolver struct {
+	byDN  map[string]*ldap.User
+	byUID map[string]*ldap.User
+
+	// principalIDs holds the IDs of the linked local users by normalized DN.
+	principalIDs map[string]int64
+}
+
+func newMemberResolver(dirUsers []*ldap.User, identities []*types.LDAPIdentity) *memberResolver {
+	r := &memberResolver{
+		byDN:         make(map[string]*ldap.User, len(dirUsers)),
+		byUID:        make(map[string]*ldap.User, len(dirUsers)),
+		principalIDs: make(map[string]int64, len(identities)),
+	}
+
+	for _, u := range dirUsers {
+		r.byDN[ldap.NormalizeDN(u.DN)] = u
+		if u.UID != "" {
+			r.byUID[strings.ToLower(u.UID)] = u
+		}
+	}
+
+	for _, identity := range identities {
+		r.principalIDs[identity.DN] = identity.PrincipalID
+	}
+
+	return r
+}
+
+func (r *memberResolver) resolve(members []string) map[int64]struct{} {
+	principalIDs := make(map[int64]struct{}, len(members))
+
+	for _, member := range members {
+		dirUser, ok := r.byDN[ldap.NormalizeDN(member)]
+		if !ok {
+			dirUser, ok = r.byUID[strings.ToLower(member)]
+		}
+		if !ok {
+			continue
+		}
+
+		if principalID, ok := r.principalIDs[ldap.NormalizeDN(dirUser.DN)]; ok {
+			principalIDs[principalID] = struct{}{}
+		}
+	}
+
+	return principalIDs
+}
+
+func sanitizeIdentifier(s string) string {
+	identifier := strings.Trim(illegalIdentifierChars.ReplaceAllString(s, "-"), "-.")
+	if len(identifier) > check.MaxIdentifierLength {
+		identifier = identifier[:check.MaxIdentifierLength]
+	}
+
+	if check.Identifier(identifier) != nil {
+		return ""
+	}
+
+	return identifier
+}
@@ -0,0 +1,193 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldapsync
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+const (
+	testSpaceID         = 1
+	testSyncPrincipalID = 100
+)
+
+type testTx struct{}
+
+func (testTx) WithTx(ctx context.Context, txFn func(ctx context.Context) error, _ ...interface{}) error {
+	return txFn(ctx)
+}
+
+type testUserGroupStore struct {
+	store.UserGroupStore
+	userGroups []*types.UserGroup
+}
+
+func (s *testUserGroupStore) FindByIdentifier(
+	_ context.Context,
+	_ int64,
+	identifier string,
+) (*types.UserGroup, error) {
+	for _, userGroup := range s.userGroups {
+		if strings.EqualFold(userGroup.Identifier, identifier) {
+			dup := *userGroup
+			return &dup, nil
+		}
+	}
+	return nil, gitness_store.ErrResourceNotFound
+}
+
+func (s *testUserGroupStore) Create(_ context.Context, spaceID int64, userGroup *types.UserGroup) error {
+	userGroup.ID = int64(len(s.userGroups) + 1)
+	userGroup.SpaceID = spaceID
+	dup := *userGroup
+	s.userGroups = append(s.userGroups, &dup)
+	return nil
+}
+
+func (s *testUserGroupStore) Update(_ context.Context, userGroup *types.UserGroup) error {
+	for i := range s.userGroups {
+		if s.userGroups[i].ID == userGroup.ID {
+			dup := *userGroup
+			s.userGroups[i] = &dup
+			return nil
+		}
+	}
+	return gitness_store.ErrResourceNotFound
+}
+
+type testMemberStore struct {
+	store.UserGroupMemberStore
+	members map[int64]*types.UserGroupMember
+}
+
+func (s *testMemberStore) ListByUserGroup(_ context.Context, _ int64) ([]*types.UserGroupMember, error) {
+	members := make([]*types.UserGroupMember, 0, len(s.members))
+	for _, m := range s.members {
+		members = append(members, m)
+	}
+	return members, nil
+}
+
+func (s *testMemberStore) Add(_ context.Context, member *types.UserGroupMember) error {
+	s.members[member.PrincipalID] = member
+	return nil
+}
+
+func (s *testMemberStore) Remove(_ context.Context, _, principalID int64) error {
+	delete(s.members, principalID)
+	return nil
+}
+
+func TestSyncGroup(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		existing    *types.UserGroup
+		wantErr     error
+		wantName    string
+		wantMembers map[int64]enum.UserGroupSource
+	}{
+		{
+			name:     "new",
+			wantName: "Developers",
+			wantMembers: map[int64]enum.UserGroupSource{
+				// only the members the sync added itself are removed.
+				2: enum.UserGroupSourceOIDC,
+				3: enum.UserGroupSourceManual,
+				4: enum.UserGroupSourceLDAP,
+			},
+		},
+		{
+			name: "managed",
+			existing: &types.UserGroup{
+				ID:         1,
+				Identifier: "developers",
+				Name:       "Old",
+				ManagedBy:  enum.UserGroupSourceLDAP,
+			},
+			wantName: "Developers",
+			wantMembers: map[int64]enum.UserGroupSource{
+				2: enum.UserGroupSourceOIDC,
+				3: enum.UserGroupSourceManual,
+				4: enum.UserGroupSourceLDAP,
+			},
+		},
+		{
+			name: "created-manually",
+			existing: &types.UserGroup{
+				ID:         1,
+				Identifier: "Developers",
+				Name:       "Manual",
+				ManagedBy:  enum.UserGroupSourceManual,
+			},
+			wantErr:  errNotManaged,
+			wantName: "Manual",
+			wantMembers: map[int64]enum.UserGroupSource{
+				1: enum.UserGroupSourceLDAP,
+				2: enum.UserGroupSourceOIDC,
+				3: enum.UserGroupSourceManual,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			userGroupStore := &testUserGroupStore{}
+			if test.existing != nil {
+				userGroupStore.userGroups = append(userGroupStore.userGroups, test.existing)
+			}
+
+			memberStore := &testMemberStore{members: map[int64]*types.UserGroupMember{
+				1: {PrincipalID: 1, Source: enum.UserGroupSourceLDAP},
+				2: {PrincipalID: 2, Source: enum.UserGroupSourceOIDC},
+				3: {PrincipalID: 3, Source: enum.UserGroupSourceManual},
+			}}
+
+			s := &Service{tx: testTx{}, userGroupStore: userGroupStore, memberStore: memberStore}
+
+			err := s.syncGroup(ctx, testSyncPrincipalID, testSpaceID, &types.UserGroup{
+				Identifier: "developers",
+				Name:       "Developers",
+				ManagedBy:  enum.UserGroupSourceLDAP,
+			}, map[int64]struct{}{2: {}, 4: {}})
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("want error %v, got %v", test.wantErr, err)
+			}
+
+			if len(userGroupStore.userGroups) != 1 || userGroupStore.userGroups[0].Name != test.wantName {
+				t.Errorf("want one usergroup named %q, got %+v", test.wantName, userGroupStore.userGroups)
+			}
+
+			if len(memberStore.members) != len(test.wantMembers) {
+				t.Errorf("want %d members, got %d", len(test.wantMembers), len(memberStore.members))
+			}
+			for principalID, source := range test.wantMembers {
+				member, ok := memberStore.members[principalID]
+				if !ok || member.Source != source {
+					t.Errorf("want member %d with source %s, got %+v", principalID, source, member)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mergequeue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/app/api/controller/pullreq"
+	checkevents "github.com/harness/gitness/app/events/check"
+	gitevents "github.com/harness/gitness/app/events/git"
+	pipelineevents "github.com/harness/gitness/app/events/pipeline"
+	pullreqevents "github.com/harness/gitness/app/events/pullreq"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/events"
+	"github.com/harness/gitness/job"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/stream"
+	"github.com/harness/gitness/types"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	eventsReaderGroupName = "gitness:mergequeue"
+
+	jobTypeProcess        = "gitness:mergequeue:process"
+	jobCronProcess        = "* * * * *" // Every minute.
+	jobMaxDurationProcess = 5 * time.Minute
+
+	refPrefixBranch = "refs/heads/"
+)
+
+// Service advances the merge queues of protected branches. A merge queue is processed whenever
+// something that might affect its entries happens: a status check gets reported, a pipeline completes,
+// the target branch or the source branch of a queued pull request gets updated, or a queued pull request
+// gets closed. Additionally, all merge queues are processed periodically in a background job.
+type Service struct {
+	pullreqCtrl     *pullreq.Controller
+	mergeQueueStore store.MergeQueueEntryStore
+	scheduler       *job.Scheduler
+	executor        *job.Executor
+}
+
+//nolint:funlen
+func New(
+	ctx context.Context,
+	config *types.Config,
+	pullreqCtrl *pullreq.Controller,
+	mergeQueueStore store.MergeQueueEntryStore,
+	scheduler *job.Scheduler,
+	executor *job.Executor,
+	pullreqEvReaderFactory *events.ReaderFactory[*pullreqevents.Reader],
+	gitEvReaderFactory *events.ReaderFactory[*gitevents.Reader],
+	checkEvReaderFactory *events.ReaderFactory[*checkevents.Reader],
+	pipelineEvReaderFactory *events.ReaderFactory[*pipelineevents.Reader],
+) (*Service, error) {
+	service := &Service{
+		pullreqCtrl:     pullreqCtrl,
+		mergeQueueStore: mergeQueueStore,
+		scheduler:       scheduler,
+		executor:        executor,
+	}
+
+	const idleTimeout = 5 * time.Minute // gives enough time for building merge commits and merging
+
+	_, err := pullreqEvReaderFactory.Launch(ctx, eventsReaderGroupName, config.InstanceID,
+		func(r *pullreqevents.Reader) error {
+			r.Configure(
+				stream.WithConcurrency(1),
+				stream.WithHandlerOptions(
+					stream.WithIdleTimeout(idleTimeout),
+					stream.WithMaxRetries(2),
+				))
+
+			_ = r.RegisterBranchUpdated(service.handleEventPullReqBranchUpdated)
+			_ = r.RegisterClosed(service.handleEventPullReqClosed)
+
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch pull request events reader: %w", err)
+	}
+
+	_, err = gitEvReaderFactory.Launch(ctx, eventsReaderGroupName, config.InstanceID,
+		func(r *gitevents.Reader) error {
+			r.Configure(
+				stream.WithConcurrency(1),
+				stream.WithHandlerOptions(
+					stream.WithIdleTimeout(idleTimeout),
+					stream.WithMaxRetries(2),
+				))
+
+			_ = r.RegisterBranchUpdated(service.handleEventBranchUpdated)
+
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch git events reader: %w", err)
+	}
+
+	_, err = checkEvReaderFactory.Launch(ctx, eventsReaderGroupName, config.InstanceID,
+		func(r *checkevents.Reader) error {
+			r.Configure(
+				stream.WithConcurrency(1),
+				stream.WithHandlerOptions(
+					stream.WithIdleTimeout(idleTimeout),
+					stream.WithMaxRetries(2),
+				))
+
+			_ = r.RegisterReported(service.handleEventCheckReported)
+
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch check events reader: %w", err)
+	}
+
+	_, err = pipelineEvReaderFactory.Launch(ctx, eventsReaderGroupName, config.InstanceID,
+		func(r *pipelineevents.Reader) error {
+			r.Configure(
+				stream.WithConcurrency(1),
+				stream.WithHandlerOptions(
+					stream.WithIdleTimeout(idleTimeout),
+					stream.WithMaxRetries(2),
+				))
+
+			_ = r.RegisterExecuted(service.handleEventPipelineExecuted)
+
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch pipeline events reader: %w", err)
+	}
+
+	return service, nil
+}
+
+// Register registers the recurring job that processes all merge queues.
+func (s *Service) Register(ctx context.Context) error {
+	err := s.executor.Register(jobTypeProcess, s)
+	if err != nil {
+		return fmt.Errorf("failed to register job handler for merge queue processing: %w", err)
+	}
+
+	err = s.scheduler.AddRecurring(ctx, jobTypeProcess, jobTypeProcess, jobCronProcess, jobMaxDurationProcess)
+	if err != nil {
+		return fmt.Errorf("failed to schedule merge queue processing job: %w", err)
+	}
+
+	return nil
+}
+
+// Handle processes all non-empty merge queues. It's a safety net for events that got lost.
+func (s *Service) Handle(ctx context.Context, _ string, _ job.ProgressReporter) (string, error) {
+	branches, err := s.mergeQueueStore.ListBranches(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list merge queue branches: %w", err)
+	}
+
+	for _, b := range branches {
+		if err = s.pullreqCtrl.MergeQueueProcess(ctx, b.RepoID, b.Branch); err != nil {
+			log.Ctx(ctx).Warn().Err(err).
+				Int64("repo.id", b.RepoID).
+				Str("branch", b.Branch).
+				Msg("failed to process merge queue")
+		}
+	}
+
+	if len(branches) == 0 {
+		return "", nil
+	}
+
+	return fmt.Sprintf("processed %d merge queues", len(branches)), nil
+}
+
+func (s *Service) handleEventPullReqBranchUpdated(
+	ctx context.Context,
+	event *events.Event[*pullreqevents.BranchUpdatedPayload],
+) error {
+	return s.processPullReqQueue(ctx, event.Payload.PullReqID)
+}
+
+func (s *Service) handleEventPullReqClosed(
+	ctx context.Context,
+	event *events.Event[*pullreqevents.ClosedPayload],
+) error {
+	return s.processPullReqQueue(ctx, event.Payload.PullReqID)
+}
+
+// handleEventBranchUpdated processes the merge queue of the updated branch,
+// because the speculative merge commits of its entries are outdated.
+func (s *Service) handleEventBranchUpdated(
+	ctx context.Context,
+	event *events.Event[*gitevents.BranchUpdatedPayload],
+) error {
+	branch := strings.TrimPrefix(event.Payload.Ref, refPrefixBranch)
+
+	return s.processRepoQueues(ctx, event.Payload.RepoID, func(e *types.MergeQueueEntry) bool {
+		return e.Branch == branch
+	})
+}
+
+// handleEventCheckReported processes the merge queues containing the
+// speculative merge commit the status check got reported for.
+func (s *Service) handleEventCheckReported(
+	ctx context.Context,
+	event *events.Event[*checkevents.ReportedPayload],
+) error {
+	if !event.Payload.Status.IsCompleted() {
+		return nil
+	}
+
+	return s.processRepoQueues(ctx, event.Payload.RepoID, func(e *types.MergeQueueEntry) bool {
+		return e.MergeSHA == event.Payload.CommitSHA
+	})
+}
+
+// handleEventPipelineExecuted processes all merge queues of the repository,
+// as the status check of the executed pipeline might have been the last missing one.
+func (s *Service) handleEventPipelineExecuted(
+	ctx context.Context,
+	event *events.Event[*pipelineevents.ExecutedPayload],
+) error {
+	return s.processRepoQueues(ctx, event.Payload.RepoID, func(*types.MergeQueueEntry) bool {
+		return true
+	})
+}
+
+// processPullReqQueue processes the merge queue of the target branch of the pull request.
+func (s *Service) processPullReqQueue(ctx context.Context, pullreqID int64) error {
+	entry, err := s.mergeQueueStore.FindByPullReq(ctx, pullreqID)
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find merge queue entry of pull request: %w", err)
+	}
+
+	return s.pullreqCtrl.MergeQueueProcess(ctx, entry.RepoID, entry.Branch)
+}
+
+// processRepoQueues processes the merge queues of the repository containing an entry matching the filter.
+func (s *Service) processRepoQueues(
+	ctx context.Context,
+	repoID int64,
+	filter func(*types.MergeQueueEntry) bool,
+) error {
+	entries, err := s.mergeQueueStore.ListByRepo(ctx, repoID)
+	if err != nil {
+		return fmt.Errorf("failed to list merge queue entries: %w", err)
+	}
+
+	processed := map[string]struct{}{}
+
+	var errs error
+	for _, e := range entries {
+		if _, ok := processed[e.Branch]; ok || !filter(e) {
+			continue
+		}
+
+		processed[e.Branch] = struct{}{}
+
+		if err = s.pullreqCtrl.MergeQueueProcess(ctx, repoID, e.Branch); err != nil {
+			errs = multierror.Append(errs,
+				fmt.Errorf("failed to process merge queue of branch %q: %w", e.Branch, err))
+		}
+	}
+
+	return errs
+}
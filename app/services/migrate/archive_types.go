@@ -0,0 +1,118 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"time"
+
+	migratetypes "github.com/harness/harness-migrate/types"
+)
+
+// ArchiveVersion is the version of the space archive layout.
+const ArchiveVersion = 1
+
+// Layout of a space archive. Every repository is stored in its own directory under ArchiveReposDir,
+// using the same file names the harness-migrate tool uses for its exports.
+const (
+	ArchiveSpaceFileName = "space.json"
+	ArchiveReposDir      = "repos"
+
+	ArchiveInfoFileName        = migratetypes.InfoFileName
+	ArchivePullRequestDir      = migratetypes.PullRequestDir
+	ArchiveWebhookFileName     = migratetypes.WebhookFileName
+	ArchiveBranchRulesFileName = migratetypes.BranchRulesFileName
+	ArchiveLabelsFileName      = migratetypes.LabelsFileName
+	ArchiveBundleFileName      = "repo.bundle"
+	ArchiveReviewsFileName     = "reviews.json"
+	ArchivePipelinesFileName   = "pipelines.json"
+)
+
+// Kinds of the data of an exported space that isn't part of the space archive.
+const (
+	ArchiveSkippedRule          = "rule"
+	ArchiveSkippedRuleBypass    = "rule_bypass"
+	ArchiveSkippedWebhook       = "webhook"
+	ArchiveSkippedWebhookSecret = "webhook_secret"
+)
+
+type (
+	ExternalRepository  = migratetypes.Repository
+	ExternalUser        = migratetypes.User
+	ExternalReference   = migratetypes.Reference
+	ExternalCodeComment = migratetypes.CodeComment
+	ExternalHunk        = migratetypes.Hunk
+	ExternalWebhookData = migratetypes.WebhookData
+
+	// ExternalRepositoryInfo is the repository description stored in the archive.
+	// It extends the harness-migrate repository with the fields it doesn't have.
+	ExternalRepositoryInfo struct {
+		ExternalRepository
+		Description string `json:"description"`
+	}
+
+	// ExternalSpace describes the content of a space archive.
+	ExternalSpace struct {
+		Version      int       `json:"version"`
+		Identifier   string    `json:"identifier"`
+		Description  string    `json:"description"`
+		Created      time.Time `json:"created"`
+		Repositories []string  `json:"repositories"`
+
+		// Skipped lists the data of the space that couldn't be exported into the archive.
+		Skipped []ExternalSkipped `json:"skipped,omitempty"`
+	}
+
+	// ExternalSkipped describes data of the exported space that isn't part of the archive.
+	ExternalSkipped struct {
+		// Repository is the identifier of the repository the data belongs to, empty for data of the space.
+		Repository string `json:"repository,omitempty"`
+		Resource   string `json:"resource"`
+		Identifier string `json:"identifier"`
+		Reason     string `json:"reason"`
+	}
+
+	// ExternalReview is a review decision of a pull request reviewer.
+	ExternalReview struct {
+		PullRequest int          `json:"pull_request"`
+		Reviewer    ExternalUser `json:"reviewer"`
+		Type        string       `json:"type"`
+		Decision    string       `json:"decision"`
+		SHA         string       `json:"sha"`
+		Created     time.Time    `json:"created"`
+		Updated     time.Time    `json:"updated"`
+	}
+
+	// ExternalPipeline is a pipeline of a repository. The pipeline configuration is part of the git repository.
+	ExternalPipeline struct {
+		Identifier    string            `json:"identifier"`
+		Description   string            `json:"description"`
+		Disabled      bool              `json:"disabled"`
+		DefaultBranch string            `json:"default_branch"`
+		ConfigPath    string            `json:"config_path"`
+		Triggers      []ExternalTrigger `json:"triggers"`
+	}
+
+	// ExternalTrigger is a trigger of a pipeline.
+	ExternalTrigger struct {
+		Identifier  string   `json:"identifier"`
+		Description string   `json:"description"`
+		Type        string   `json:"type"`
+		Disabled    bool     `json:"disabled"`
+		Actions     []string `json:"actions"`
+		Cron        string   `json:"cron,omitempty"`
+		Branch      string   `json:"branch,omitempty"`
+		Timezone    string   `json:"timezone,omitempty"`
+	}
+)
@@ -225,6 +225,11 @@ func findClosestColor(ctx context.Context, extColor string) enum.LabelColor {
 	return closestColor
 }
 
+// LabelColorHex returns the hex value of a Gitness label color, as expected in external labels.
+func LabelColorHex(color enum.LabelColor) string {
+	return convertToColorful(color).Hex()
+}
+
 // convertToColorful converts Gitness supported label colors to the hex (text value) using web/src/utils:ColorDetails.
 func convertToColorful(color enum.LabelColor) colorful.Color {
 	var hexColor colorful.Color
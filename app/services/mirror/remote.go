@@ -0,0 +1,234 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mirror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/harness/gitness/app/paths"
+	gitnesserrors "github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+// tokenUsername is used as the username for connectors authenticating with a bearer token.
+const tokenUsername = "oauth2"
+
+// remote holds the URL of the remote repository with the credentials embedded.
+type remote struct {
+	url      string
+	password string
+	// resolve pins the host of the URL to the checked addresses, it's empty if the host is an IP address.
+	resolve string
+}
+
+// access restricts git to the checked addresses of the remote, and prevents it from following
+// redirects or switching to other protocols, which would bypass the checks of the remote URL.
+func (r remote) access() *git.RemoteAccess {
+	access := &git.RemoteAccess{
+		NoRedirects: true,
+		Protocols:   []string{"http", "https"},
+	}
+	if r.resolve != "" {
+		access.Resolve = []string{r.resolve}
+	}
+
+	return access
+}
+
+// redact removes the credentials from the error message,
+// as errors of the git operations could contain the remote URL.
+func (r remote) redact(err error) error {
+	if r.password == "" {
+		return err
+	}
+
+	escaped := strings.TrimPrefix(url.UserPassword("", r.password).String(), ":")
+
+	msg := strings.ReplaceAll(err.Error(), escaped, "xxxxx")
+	msg = strings.ReplaceAll(msg, r.password, "xxxxx")
+
+	return errors.New(msg)
+}
+
+// CheckRemoteURL verifies that the remote URL is a valid http or https URL without credentials,
+// and that its host doesn't resolve to a loopback, private or link-local address unless allowed by the config.
+func (s *Service) CheckRemoteURL(ctx context.Context, remoteURL string) error {
+	_, err := s.checkRemoteURL(ctx, remoteURL)
+	return err
+}
+
+// checkRemoteURL checks the remote URL and returns the curl resolve entry that pins its host to the checked
+// addresses, so git connects to the same addresses instead of resolving the host again.
+func (s *Service) checkRemoteURL(ctx context.Context, remoteURL string) (string, error) {
+	u, err := url.Parse(remoteURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return "", gitnesserrors.InvalidArgument("Remote URL must be a valid http or https URL.")
+	}
+	if u.User != nil {
+		return "", gitnesserrors.InvalidArgument(
+			"Remote URL must not contain credentials, use a secret or a connector instead.")
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return "", gitnesserrors.InvalidArgument("Failed to resolve the host of the remote URL: %s", err)
+	}
+
+	ips := make([]string, len(addrs))
+	for i, addr := range addrs {
+		if err := s.checkRemoteIP(addr.IP); err != nil {
+			return "", err
+		}
+
+		ips[i] = addr.IP.String()
+		if addr.IP.To4() == nil {
+			ips[i] = "[" + ips[i] + "]"
+		}
+	}
+
+	// IP addresses aren't resolved, so there's nothing to pin.
+	if net.ParseIP(u.Hostname()) != nil {
+		return "", nil
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	return u.Hostname() + ":" + port + ":" + strings.Join(ips, ","), nil
+}
+
+func (s *Service) checkRemoteIP(ip net.IP) error {
+	if !s.config.AllowLoopback && (ip.IsLoopback() || ip.IsUnspecified()) {
+		return gitnesserrors.InvalidArgument("Remote URL must not resolve to a loopback address.")
+	}
+
+	if !s.config.AllowPrivateNetwork &&
+		(ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast()) {
+		return gitnesserrors.InvalidArgument("Remote URL must not resolve to a private or link-local address.")
+	}
+
+	return nil
+}
+
+func (s *Service) newRemote(ctx context.Context, repo *types.Repository, m *types.Mirror) (remote, error) {
+	// the host is checked again before every synchronization, because the addresses it resolves to can change.
+	resolve, err := s.checkRemoteURL(ctx, m.RemoteURL)
+	if err != nil {
+		return remote{}, err
+	}
+
+	remoteURL, err := url.Parse(m.RemoteURL)
+	if err != nil {
+		return remote{}, fmt.Errorf("failed to parse remote URL: %w", err)
+	}
+
+	username, password, err := s.resolveCredentials(ctx, repo, m)
+	if err != nil {
+		return remote{}, err
+	}
+
+	if password != "" {
+		remoteURL.User = url.UserPassword(username, password)
+	}
+
+	return remote{
+		url:      remoteURL.String(),
+		password: password,
+		resolve:  resolve,
+	}, nil
+}
+
+// resolveCredentials returns the credentials of the mirror. The connector or secret of the mirror
+// are looked up in the parent space of the repository.
+func (s *Service) resolveCredentials(
+	ctx context.Context,
+	repo *types.Repository,
+	m *types.Mirror,
+) (string, string, error) {
+	spacePath := paths.Parent(repo.Path)
+
+	if m.ConnectorIdentifier != "" {
+		connector, err := s.connectorStore.FindByIdentifier(ctx, repo.ParentID, m.ConnectorIdentifier)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to find connector %q: %w", m.ConnectorIdentifier, err)
+		}
+
+		auth, err := connectorAuth(connector)
+		if err != nil {
+			return "", "", err
+		}
+
+		var username string
+		var ref types.SecretRef
+		switch auth.AuthType {
+		case enum.ConnectorAuthTypeBasic:
+			username, ref = auth.Basic.Username, auth.Basic.Password
+		case enum.ConnectorAuthTypeBearer:
+			username, ref = tokenUsername, auth.Bearer.Token
+		default:
+			return "", "", fmt.Errorf("unsupported connector auth type %q", auth.AuthType)
+		}
+
+		password, err := s.secretService.DecryptSecret(ctx, spacePath, ref.Identifier)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to resolve connector secret: %w", err)
+		}
+
+		return username, password, nil
+	}
+
+	if m.SecretIdentifier != "" {
+		password, err := s.secretService.DecryptSecret(ctx, spacePath, m.SecretIdentifier)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to resolve secret %q: %w", m.SecretIdentifier, err)
+		}
+
+		username := m.Username
+		if username == "" {
+			username = tokenUsername
+		}
+
+		return username, password, nil
+	}
+
+	return "", "", nil
+}
+
+func connectorAuth(connector *types.Connector) (*types.ConnectorAuth, error) {
+	switch connector.Type {
+	case enum.ConnectorTypeGithub:
+		if connector.Github == nil || connector.Github.Auth == nil {
+			return nil, fmt.Errorf("github connector %q has no credentials", connector.Identifier)
+		}
+		if err := connector.Github.Auth.Validate(); err != nil {
+			return nil, fmt.Errorf("github connector %q has invalid credentials: %w", connector.Identifier, err)
+		}
+		return connector.Github.Auth, nil
+	default:
+		return nil, fmt.Errorf("connector type %q is not supported for mirroring", connector.Type)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/job"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+type scheduleJob struct {
+	service *Service
+}
+
+// Handle schedules synchronization of all pull mirrors that are due. The job scheduler runs
+// a recurring job on a single instance at a time, and the next synchronization of each mirror
+// is moved forward with a compare-and-swap so a tick never schedules the same mirror twice.
+func (j *scheduleJob) Handle(ctx context.Context, _ string, _ job.ProgressReporter) (string, error) {
+	now := time.Now()
+
+	mirrors, err := j.service.mirrorStore.ListPullDue(ctx, now.UnixMilli())
+	if err != nil {
+		return "", fmt.Errorf("failed to list due pull mirrors: %w", err)
+	}
+
+	scheduled := 0
+	for _, m := range mirrors {
+		ok, err := j.service.schedulePull(ctx, m, now)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).
+				Int64("repo.id", m.RepoID).
+				Str("mirror.identifier", m.Identifier).
+				Msg("failed to schedule pull mirror sync")
+			continue
+		}
+		if ok {
+			scheduled++
+		}
+	}
+
+	if scheduled == 0 {
+		return "", nil
+	}
+
+	return fmt.Sprintf("scheduled %d pull mirrors", scheduled), nil
+}
+
+// schedulePull moves the next synchronization of the pull mirror forward and schedules the sync job.
+// Returns false if the mirror was claimed by someone else.
+func (s *Service) schedulePull(ctx context.Context, m *types.Mirror, now time.Time) (bool, error) {
+	ok, err := s.mirrorStore.UpdateNextSync(ctx, m.ID, m.NextSync, NextSync(m, now))
+	if err != nil {
+		return false, fmt.Errorf("failed to update next sync: %w", err)
+	}
+	if !ok {
+		return false, nil
+	}
+
+	m, err = s.mirrorStore.Find(ctx, m.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find mirror: %w", err)
+	}
+
+	if _, err = s.Sync(ctx, m); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// NextSync returns the time of the next synchronization of a pull mirror in unix milliseconds.
+// Push mirrors are synchronized on change only, so zero is returned for them.
+func NextSync(m *types.Mirror, now time.Time) int64 {
+	if m.Direction != enum.MirrorDirectionPull || !m.Enabled {
+		return 0
+	}
+
+	interval := max(m.Interval, MinInterval)
+
+	return now.Add(time.Duration(interval) * time.Second).UnixMilli()
+}
@@ -0,0 +1,341 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/harness/gitness/app/bootstrap"
+	"github.com/harness/gitness/app/githook"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/app/url"
+	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/job"
+	"github.com/harness/gitness/secret"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	jobTypeSync        = "gitness:mirror:sync"
+	jobMaxRetriesSync  = 0
+	jobMaxDurationSync = 45 * time.Minute
+
+	jobTypeSchedule        = "gitness:mirror:schedule"
+	jobCronSchedule        = "* * * * *" // Every minute.
+	jobMaxDurationSchedule = 5 * time.Minute
+
+	// MinInterval is the minimum number of seconds between two synchronizations of a pull mirror.
+	MinInterval = 60
+)
+
+type Config struct {
+	// AllowPrivateNetwork allows remote hosts that resolve to private or link-local addresses.
+	AllowPrivateNetwork bool
+	// AllowLoopback allows remote hosts that resolve to loopback addresses.
+	AllowLoopback bool
+}
+
+// Service synchronizes repository mirrors in background jobs.
+// Pull mirrors are fetched from the remote repository periodically,
+// push mirrors are pushed to the remote repository after every change of the repository.
+type Service struct {
+	config         Config
+	mirrorStore    store.MirrorStore
+	repoStore      store.RepoStore
+	connectorStore store.ConnectorStore
+	secretService  secret.Service
+	urlProvider    url.Provider
+	git            git.Interface
+	scheduler      *job.Scheduler
+	executor       *job.Executor
+}
+
+func NewService(
+	config Config,
+	mirrorStore store.MirrorStore,
+	repoStore store.RepoStore,
+	connectorStore store.ConnectorStore,
+	secretService secret.Service,
+	urlProvider url.Provider,
+	git git.Interface,
+	scheduler *job.Scheduler,
+	executor *job.Executor,
+) *Service {
+	return &Service{
+		config:         config,
+		mirrorStore:    mirrorStore,
+		repoStore:      repoStore,
+		connectorStore: connectorStore,
+		secretService:  secretService,
+		urlProvider:    urlProvider,
+		git:            git,
+		scheduler:      scheduler,
+		executor:       executor,
+	}
+}
+
+// Register registers the recurring job that schedules synchronization of the pull mirrors.
+func (s *Service) Register(ctx context.Context) error {
+	err := s.executor.Register(jobTypeSchedule, &scheduleJob{service: s})
+	if err != nil {
+		return fmt.Errorf("failed to register job handler for mirror scheduling: %w", err)
+	}
+
+	err = s.scheduler.AddRecurring(ctx, jobTypeSchedule, jobTypeSchedule, jobCronSchedule, jobMaxDurationSchedule)
+	if err != nil {
+		return fmt.Errorf("failed to schedule mirror scheduling job: %w", err)
+	}
+
+	return nil
+}
+
+// IsReadOnly returns true if the repository has an enabled pull mirror.
+// Only the mirror synchronization is allowed to update references of such a repository.
+func (s *Service) IsReadOnly(ctx context.Context, repoID int64) (bool, error) {
+	mirrors, err := s.mirrorStore.List(ctx, repoID, enum.MirrorDirectionPull)
+	if err != nil {
+		return false, fmt.Errorf("failed to list pull mirrors: %w", err)
+	}
+
+	for _, m := range mirrors {
+		if m.Enabled {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// IsSyncPrincipal returns true if the principal is the one that is used for mirror synchronization.
+func IsSyncPrincipal(principalID int64) bool {
+	return principalID == bootstrap.NewSystemServiceSession().Principal.ID
+}
+
+// Sync schedules a background job that synchronizes the mirror.
+// If the synchronization of the mirror is already scheduled no new job is created.
+func (s *Service) Sync(ctx context.Context, m *types.Mirror) (*types.Mirror, error) {
+	scheduled := false
+	m, err := s.mirrorStore.UpdateOptLock(ctx, m, func(m *types.Mirror) error {
+		// a scheduled state older than the max job duration is stale, e.g. after the job got lost
+		scheduled = m.SyncState == enum.MirrorSyncStateScheduled &&
+			time.Since(time.UnixMilli(m.Updated)) < jobMaxDurationSync
+		m.SyncState = enum.MirrorSyncStateScheduled
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update mirror sync state: %w", err)
+	}
+
+	if scheduled {
+		return m, nil
+	}
+
+	err = s.scheduler.RunJob(ctx, job.Definition{
+		UID:        fmt.Sprintf("gitness-mirror-%d-%d", m.ID, time.Now().UnixNano()),
+		Type:       jobTypeSync,
+		MaxRetries: jobMaxRetriesSync,
+		Timeout:    jobMaxDurationSync,
+		Data:       strconv.FormatInt(m.ID, 10),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to run mirror sync job: %w", err)
+	}
+
+	return m, nil
+}
+
+// SyncPushMirrors schedules synchronization of all enabled push mirrors of the repository.
+func (s *Service) SyncPushMirrors(ctx context.Context, repoID int64) error {
+	mirrors, err := s.mirrorStore.List(ctx, repoID, enum.MirrorDirectionPush)
+	if err != nil {
+		return fmt.Errorf("failed to list push mirrors: %w", err)
+	}
+
+	for _, m := range mirrors {
+		if !m.Enabled {
+			continue
+		}
+
+		if _, err := s.Sync(ctx, m); err != nil {
+			return fmt.Errorf("failed to sync push mirror %q: %w", m.Identifier, err)
+		}
+	}
+
+	return nil
+}
+
+// Handle is the handler of the mirror synchronization background job.
+func (s *Service) Handle(ctx context.Context, data string, _ job.ProgressReporter) (string, error) {
+	mirrorID, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid mirror id %q: %w", data, err)
+	}
+
+	m, err := s.mirrorStore.Find(ctx, mirrorID)
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		// the mirror got deleted in the meantime
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find mirror: %w", err)
+	}
+
+	repo, err := s.repoStore.Find(ctx, m.RepoID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find repository: %w", err)
+	}
+
+	m, err = s.mirrorStore.UpdateOptLock(ctx, m, func(m *types.Mirror) error {
+		m.SyncState = enum.MirrorSyncStateRunning
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to update mirror sync state: %w", err)
+	}
+
+	log := log.Ctx(ctx).With().
+		Int64("repo.id", repo.ID).
+		Str("mirror.identifier", m.Identifier).
+		Str("mirror.direction", string(m.Direction)).
+		Logger()
+
+	errSync := s.sync(ctx, repo, m)
+	if errSync != nil {
+		log.Warn().Err(errSync).Msg("failed to synchronize mirror")
+	}
+
+	_, err = s.mirrorStore.UpdateOptLock(ctx, m, func(m *types.Mirror) error {
+		// a new synchronization could have been requested while this one was running
+		if m.SyncState == enum.MirrorSyncStateRunning {
+			m.SyncState = enum.MirrorSyncStateSuccess
+			if errSync != nil {
+				m.SyncState = enum.MirrorSyncStateFailed
+			}
+		}
+
+		m.LastSync = time.Now().UnixMilli()
+		m.LastError = ""
+		if errSync != nil {
+			m.LastError = errSync.Error()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to update mirror sync status: %w", err)
+	}
+
+	if errSync != nil {
+		return "", errSync
+	}
+
+	return "", nil
+}
+
+func (s *Service) sync(ctx context.Context, repo *types.Repository, m *types.Mirror) error {
+	if repo.State != enum.RepoStateActive {
+		return errors.New("repository is not active")
+	}
+
+	remote, err := s.newRemote(ctx, repo, m)
+	if err != nil {
+		return err
+	}
+
+	switch m.Direction {
+	case enum.MirrorDirectionPull:
+		err = s.pull(ctx, repo, remote)
+	case enum.MirrorDirectionPush:
+		err = s.push(ctx, repo, remote)
+	default:
+		return fmt.Errorf("unsupported mirror direction %q", m.Direction)
+	}
+	if err != nil {
+		return remote.redact(err)
+	}
+
+	return nil
+}
+
+func (s *Service) pull(ctx context.Context, repo *types.Repository, remote remote) error {
+	writeParams, err := s.createRPCWriteParams(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.git.SyncRepository(ctx, &git.SyncRepositoryParams{
+		WriteParams:       writeParams,
+		Source:            remote.url,
+		CreateIfNotExists: false,
+		RefSpecs:          []string{"refs/heads/*:refs/heads/*", "refs/tags/*:refs/tags/*"},
+		DefaultBranch:     repo.DefaultBranch,
+		RemoteAccess:      remote.access(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch from the remote repository: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) push(ctx context.Context, repo *types.Repository, remote remote) error {
+	if repo.IsEmpty {
+		return nil
+	}
+
+	err := s.git.PushRemote(ctx, &git.PushRemoteParams{
+		ReadParams:   git.ReadParams{RepoUID: repo.GitUID},
+		RemoteURL:    remote.url,
+		RemoteAccess: remote.access(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to push to the remote repository: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) createRPCWriteParams(ctx context.Context, repo *types.Repository) (git.WriteParams, error) {
+	principal := bootstrap.NewSystemServiceSession().Principal
+
+	envVars, err := githook.GenerateEnvironmentVariables(
+		ctx,
+		s.urlProvider.GetInternalAPIURL(ctx),
+		repo.ID,
+		principal.ID,
+		false,
+		true,
+	)
+	if err != nil {
+		return git.WriteParams{}, fmt.Errorf("failed to generate git hook environment variables: %w", err)
+	}
+
+	return git.WriteParams{
+		Actor: git.Identity{
+			Name:  principal.DisplayName,
+			Email: principal.Email,
+		},
+		RepoUID: repo.GitUID,
+		EnvVars: envVars,
+	}, nil
+}
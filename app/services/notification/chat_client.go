@@ -0,0 +1,237 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+
+	"github.com/harness/gitness/app/services/publicaccess"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+const (
+	chatEventCommentPRAuthor      = "comment_pr_author"
+	chatEventCommentMentions      = "comment_mentions"
+	chatEventCommentParticipants  = "comment_participants"
+	chatEventReviewerAdded        = "reviewer_added"
+	chatEventPullReqBranchUpdated = "pullreq_branch_updated"
+	chatEventReviewSubmitted      = "review_submitted"
+	chatEventPullReqStateChanged  = "pullreq_state_changed"
+)
+
+// chatTemplates are the plain text templates of the chat messages, keyed by event.
+var chatTemplates = map[string]*template.Template{
+	chatEventCommentPRAuthor: newChatTemplate(chatEventCommentPRAuthor,
+		`{{.Commenter.DisplayName}} commented on your pull request: {{.Text}}`),
+	chatEventCommentMentions: newChatTemplate(chatEventCommentMentions,
+		`{{.Commenter.DisplayName}} mentioned you in a comment: {{.Text}}`),
+	chatEventCommentParticipants: newChatTemplate(chatEventCommentParticipants,
+		`{{.Commenter.DisplayName}} commented on a pull request you participate in: {{.Text}}`),
+	chatEventReviewerAdded: newChatTemplate(chatEventReviewerAdded,
+		`{{.Reviewer.DisplayName}} has been added as a reviewer.`),
+	chatEventPullReqBranchUpdated: newChatTemplate(chatEventPullReqBranchUpdated,
+		`{{.Committer.DisplayName}} pushed new commits, the head is now {{printf "%.8s" .NewSHA}}.`),
+	chatEventReviewSubmitted: newChatTemplate(chatEventReviewSubmitted,
+		`{{.Reviewer.DisplayName}} {{if eq .Decision "approved"}}approved{{else if eq .Decision "changereq"}}`+
+			`requested changes to{{else}}reviewed{{end}} the pull request.`),
+	chatEventPullReqStateChanged: newChatTemplate(chatEventPullReqStateChanged,
+		`The pull request has been {{.State}} by {{.ChangedBy.DisplayName}}.`),
+}
+
+func newChatTemplate(name, text string) *template.Template {
+	return template.Must(template.New(name).Option("missingkey=error").Parse(text))
+}
+
+// ChatMessage is a pull request notification rendered for a chat service.
+type ChatMessage struct {
+	Event string `json:"event"`
+	// Title identifies the pull request, like the subject of the notification mails.
+	Title string `json:"title"`
+	Text  string `json:"text"`
+	URL   string `json:"url"`
+	// Mentions are the external IDs of the recipients on the chat service.
+	Mentions []string `json:"mentions"`
+}
+
+// ChatSender delivers chat messages to a chat service.
+type ChatSender interface {
+	Provider() enum.ChatProvider
+	// Direct returns true if the messages are delivered privately to each recipient,
+	// rather than posted to a channel shared by all recipients.
+	Direct() bool
+	Send(ctx context.Context, message *ChatMessage) error
+}
+
+// ChatClient implements Client for a chat service. The notifications are only delivered
+// to the recipients that verified their identity on the chat service.
+// Senders posting to a shared channel only receive notifications of public repositories,
+// as the members of the channel aren't necessarily allowed to read the repository.
+type ChatClient struct {
+	sender            ChatSender
+	chatIdentityStore store.UserChatIdentityStore
+	publicAccess      publicaccess.Service
+}
+
+func NewChatClient(
+	sender ChatSender,
+	chatIdentityStore store.UserChatIdentityStore,
+	publicAccess publicaccess.Service,
+) ChatClient {
+	return ChatClient{
+		sender:            sender,
+		chatIdentityStore: chatIdentityStore,
+		publicAccess:      publicAccess,
+	}
+}
+
+func (c ChatClient) SendCommentPRAuthor(
+	ctx context.Context,
+	recipients []*types.PrincipalInfo,
+	payload *CommentPayload,
+) error {
+	return c.send(ctx, chatEventCommentPRAuthor, recipients, payload.Base, payload)
+}
+
+func (c ChatClient) SendCommentMentions(
+	ctx context.Context,
+	recipients []*types.PrincipalInfo,
+	payload *CommentPayload,
+) error {
+	return c.send(ctx, chatEventCommentMentions, recipients, payload.Base, payload)
+}
+
+func (c ChatClient) SendCommentParticipants(
+	ctx context.Context,
+	recipients []*types.PrincipalInfo,
+	payload *CommentPayload,
+) error {
+	return c.send(ctx, chatEventCommentParticipants, recipients, payload.Base, payload)
+}
+
+func (c ChatClient) SendReviewerAdded(
+	ctx context.Context,
+	recipients []*types.PrincipalInfo,
+	payload *ReviewerAddedPayload,
+) error {
+	return c.send(ctx, chatEventReviewerAdded, recipients, payload.Base, payload)
+}
+
+func (c ChatClient) SendPullReqBranchUpdated(
+	ctx context.Context,
+	recipients []*types.PrincipalInfo,
+	payload *PullReqBranchUpdatedPayload,
+) error {
+	return c.send(ctx, chatEventPullReqBranchUpdated, recipients, payload.Base, payload)
+}
+
+func (c ChatClient) SendReviewSubmitted(
+	ctx context.Context,
+	recipients []*types.PrincipalInfo,
+	payload *ReviewSubmittedPayload,
+) error {
+	return c.send(ctx, chatEventReviewSubmitted, recipients, payload.Base, payload)
+}
+
+func (c ChatClient) SendPullReqStateChanged(
+	ctx context.Context,
+	recipients []*types.PrincipalInfo,
+	payload *PullReqStateChangedPayload,
+) error {
+	return c.send(ctx, chatEventPullReqStateChanged, recipients, payload.Base, payload)
+}
+
+func (c ChatClient) send(
+	ctx context.Context,
+	event string,
+	recipients []*types.PrincipalInfo,
+	base *BasePullReqPayload,
+	payload interface{},
+) error {
+	if !c.sender.Direct() {
+		isPublic, err := c.publicAccess.Get(ctx, enum.PublicResourceTypeRepo, base.Repo.Path)
+		if err != nil {
+			return fmt.Errorf("failed to check public access of the repo: %w", err)
+		}
+
+		if !isPublic {
+			return nil
+		}
+	}
+
+	mentions, err := c.getMentions(ctx, recipients)
+	if err != nil {
+		return err
+	}
+
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	text := bytes.Buffer{}
+	err = chatTemplates[event].Execute(&text, payload)
+	if err != nil {
+		return fmt.Errorf("failed to execute chat template %s: %w", event, err)
+	}
+
+	message := &ChatMessage{
+		Event:    event,
+		Title:    GetSubjectPullRequest(base.Repo.Identifier, base.PullReq.Number, base.PullReq.Title),
+		Text:     text.String(),
+		URL:      base.PullReqURL,
+		Mentions: mentions,
+	}
+
+	err = c.sender.Send(ctx, message)
+	if err != nil {
+		return fmt.Errorf("failed to send %s chat message for event %s: %w", c.sender.Provider(), event, err)
+	}
+
+	return nil
+}
+
+// getMentions returns the external IDs of the recipients that verified their identity on the chat service.
+func (c ChatClient) getMentions(ctx context.Context, recipients []*types.PrincipalInfo) ([]string, error) {
+	principalIDs := make([]int64, len(recipients))
+	for i, recipient := range recipients {
+		principalIDs[i] = recipient.ID
+	}
+
+	externalIDs, err := c.chatIdentityStore.ListExternalIDs(ctx, c.sender.Provider(), principalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chat identities of the recipients: %w", err)
+	}
+
+	// keep the order of the recipients and drop duplicates
+	mentions := make([]string, 0, len(externalIDs))
+	seen := make(map[string]struct{}, len(externalIDs))
+	for _, principalID := range principalIDs {
+		externalID, ok := externalIDs[principalID]
+		if !ok {
+			continue
+		}
+		if _, ok := seen[externalID]; ok {
+			continue
+		}
+		seen[externalID] = struct{}{}
+		mentions = append(mentions, externalID)
+	}
+
+	return mentions, nil
+}
@@ -0,0 +1,185 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/harness/gitness/app/services/publicaccess"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeChatIdentityStore struct {
+	store.UserChatIdentityStore
+	externalIDs map[int64]string
+}
+
+func (s fakeChatIdentityStore) ListExternalIDs(
+	_ context.Context,
+	_ enum.ChatProvider,
+	principalIDs []int64,
+) (map[int64]string, error) {
+	result := map[int64]string{}
+	for _, id := range principalIDs {
+		if externalID, ok := s.externalIDs[id]; ok {
+			result[id] = externalID
+		}
+	}
+	return result, nil
+}
+
+type fakePublicAccess struct {
+	publicaccess.Service
+	public map[string]bool
+}
+
+func (p fakePublicAccess) Get(_ context.Context, _ enum.PublicResourceType, path string) (bool, error) {
+	return p.public[path], nil
+}
+
+type fakeChatSender struct {
+	direct   bool
+	messages []*ChatMessage
+}
+
+func (*fakeChatSender) Provider() enum.ChatProvider {
+	return enum.ChatProviderSlack
+}
+
+func (s *fakeChatSender) Direct() bool {
+	return s.direct
+}
+
+func (s *fakeChatSender) Send(_ context.Context, message *ChatMessage) error {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func TestChatClient_Webhook(t *testing.T) {
+	var bodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		body := map[string]any{}
+		require.NoError(t, json.Unmarshal(data, &body))
+		bodies = append(bodies, body)
+	}))
+	defer server.Close()
+
+	sender, err := NewWebhookSender(server.URL, "application/json", "")
+	require.NoError(t, err)
+
+	client := NewChatClient(sender,
+		fakeChatIdentityStore{externalIDs: map[int64]string{1: "alice", 3: "carol"}},
+		fakePublicAccess{public: map[string]bool{"space/repo": true}})
+
+	payload := &ReviewSubmittedPayload{
+		Base: &BasePullReqPayload{
+			Repo:       &types.Repository{Identifier: "repo", Path: "space/repo"},
+			PullReq:    &types.PullReq{Number: 7, Title: "Fix it"},
+			PullReqURL: "http://localhost/pr/7",
+		},
+		Reviewer: &types.PrincipalInfo{ID: 2, DisplayName: "Bob"},
+		Decision: enum.PullReqReviewDecisionApproved,
+	}
+
+	err = client.SendReviewSubmitted(context.Background(), []*types.PrincipalInfo{{ID: 1}, {ID: 2}, {ID: 1}}, payload)
+	require.NoError(t, err)
+
+	// no recipient linked an identity, so nothing is posted
+	err = client.SendReviewSubmitted(context.Background(), []*types.PrincipalInfo{{ID: 2}}, payload)
+	require.NoError(t, err)
+
+	require.Len(t, bodies, 1)
+	require.Equal(t, "[repo] Fix it (PR #7)\nBob approved the pull request.\nhttp://localhost/pr/7", bodies[0]["text"])
+	require.Equal(t, []any{"alice"}, bodies[0]["mentions"])
+}
+
+func TestChatClient_PrivateRepo(t *testing.T) {
+	payload := &PullReqStateChangedPayload{
+		Base: &BasePullReqPayload{
+			Repo:       &types.Repository{Identifier: "secret", Path: "space/secret"},
+			PullReq:    &types.PullReq{Number: 1, Title: "Internal"},
+			PullReqURL: "http://localhost/pr/1",
+		},
+		ChangedBy: &types.PrincipalInfo{ID: 2, DisplayName: "Bob"},
+		State:     PullReqStateMerged,
+	}
+	recipients := []*types.PrincipalInfo{{ID: 1}}
+	identities := fakeChatIdentityStore{externalIDs: map[int64]string{1: "alice"}}
+	publicAccess := fakePublicAccess{public: map[string]bool{}}
+
+	tests := []struct {
+		name   string
+		direct bool
+		sent   int
+	}{
+		{name: "shared channel skips private repo", direct: false, sent: 0},
+		{name: "direct message includes private repo", direct: true, sent: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sender := &fakeChatSender{direct: test.direct}
+			client := NewChatClient(sender, identities, publicAccess)
+
+			err := client.SendPullReqStateChanged(context.Background(), recipients, payload)
+			require.NoError(t, err)
+			require.Len(t, sender.messages, test.sent)
+		})
+	}
+}
+
+func TestNewWebhookSender_InvalidTemplate(t *testing.T) {
+	_, err := NewWebhookSender("http://localhost", "application/json", "{{.Text")
+	require.Error(t, err)
+}
+
+type fakeClient struct {
+	Client
+	err   error
+	calls int
+}
+
+func (c *fakeClient) SendReviewerAdded(context.Context, []*types.PrincipalInfo, *ReviewerAddedPayload) error {
+	c.calls++
+	return c.err
+}
+
+func TestMultiClient(t *testing.T) {
+	failing := &fakeClient{err: errors.New("unavailable")}
+	working := &fakeClient{}
+
+	err := NewMultiClient(failing, working).SendReviewerAdded(context.Background(), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, failing.calls)
+	require.Equal(t, 1, working.calls)
+
+	err = NewMultiClient(failing, &fakeClient{err: errors.New("unavailable")}).
+		SendReviewerAdded(context.Background(), nil, nil)
+	require.Error(t, err)
+}
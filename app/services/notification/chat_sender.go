@@ -0,0 +1,261 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/slack-go/slack"
+)
+
+const (
+	chatRequestTimeout = 10 * time.Second
+
+	// defaultChatWebhookTemplate renders a JSON object with a text field, which is understood
+	// by most chat services that accept incoming webhooks.
+	defaultChatWebhookTemplate = `{"text": {{json (printf "%s\n%s\n%s" .Title .Text .URL)}}, ` +
+		`"mentions": {{json .Mentions}}}`
+)
+
+var chatHTTPClient = &http.Client{Timeout: chatRequestTimeout}
+
+// SlackWebhookSender posts the chat messages to a Slack incoming webhook, mentioning the recipients.
+type SlackWebhookSender struct {
+	url string
+}
+
+func NewSlackWebhookSender(url string) *SlackWebhookSender {
+	return &SlackWebhookSender{url: url}
+}
+
+func (*SlackWebhookSender) Provider() enum.ChatProvider {
+	return enum.ChatProviderSlack
+}
+
+func (*SlackWebhookSender) Direct() bool {
+	return false
+}
+
+func (s *SlackWebhookSender) Send(ctx context.Context, message *ChatMessage) error {
+	mentions := make([]string, len(message.Mentions))
+	for i, mention := range message.Mentions {
+		mentions[i] = "<@" + mention + ">"
+	}
+
+	return slack.PostWebhookContext(ctx, s.url, &slack.WebhookMessage{
+		Text: strings.Join(mentions, " ") + " " + formatSlackMessage(message),
+	})
+}
+
+// SlackBotSender sends the chat messages as direct messages of a Slack bot to each recipient.
+type SlackBotSender struct {
+	client *slack.Client
+}
+
+func NewSlackBotSender(token string) *SlackBotSender {
+	return &SlackBotSender{client: slack.New(token)}
+}
+
+func (*SlackBotSender) Provider() enum.ChatProvider {
+	return enum.ChatProviderSlack
+}
+
+func (*SlackBotSender) Direct() bool {
+	return true
+}
+
+func (s *SlackBotSender) Send(ctx context.Context, message *ChatMessage) error {
+	text := formatSlackMessage(message)
+	for _, mention := range message.Mentions {
+		// posting to the member ID opens the direct message channel of the bot with the member.
+		_, _, err := s.client.PostMessageContext(ctx, mention,
+			slack.MsgOptionText(text, false),
+			slack.MsgOptionDisableLinkUnfurl())
+		if err != nil {
+			return fmt.Errorf("failed to post slack message to %s: %w", mention, err)
+		}
+	}
+
+	return nil
+}
+
+// SendDirectMessage sends the text as direct message of the Slack bot to the member.
+func (s *SlackBotSender) SendDirectMessage(ctx context.Context, memberID, text string) error {
+	_, _, err := s.client.PostMessageContext(ctx, memberID,
+		slack.MsgOptionText(escapeSlack(text), false),
+		slack.MsgOptionDisableLinkUnfurl())
+	if err != nil {
+		return fmt.Errorf("failed to post slack message to %s: %w", memberID, err)
+	}
+
+	return nil
+}
+
+func formatSlackMessage(message *ChatMessage) string {
+	return fmt.Sprintf("<%s|%s>\n%s", message.URL, escapeSlack(message.Title), escapeSlack(message.Text))
+}
+
+// escapeSlack escapes the characters Slack uses for its control sequences.
+func escapeSlack(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// TeamsSender posts the chat messages as adaptive cards to a Microsoft Teams incoming webhook.
+type TeamsSender struct {
+	url string
+}
+
+func NewTeamsSender(url string) *TeamsSender {
+	return &TeamsSender{url: url}
+}
+
+func (*TeamsSender) Provider() enum.ChatProvider {
+	return enum.ChatProviderTeams
+}
+
+func (*TeamsSender) Direct() bool {
+	return false
+}
+
+func (s *TeamsSender) Send(ctx context.Context, message *ChatMessage) error {
+	mentionTexts := make([]string, len(message.Mentions))
+	entities := make([]map[string]any, len(message.Mentions))
+	for i, mention := range message.Mentions {
+		mentionTexts[i] = "<at>" + mention + "</at>"
+		entities[i] = map[string]any{
+			"type": "mention",
+			"text": mentionTexts[i],
+			"mentioned": map[string]any{
+				"id":   mention,
+				"name": mention,
+			},
+		}
+	}
+
+	card := map[string]any{
+		"type":    "AdaptiveCard",
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"version": "1.4",
+		"body": []map[string]any{
+			{"type": "TextBlock", "text": message.Title, "weight": "bolder", "wrap": true},
+			{"type": "TextBlock", "text": strings.Join(mentionTexts, " ") + " " + message.Text, "wrap": true},
+		},
+		"actions": []map[string]any{
+			{"type": "Action.OpenUrl", "title": "View pull request", "url": message.URL},
+		},
+		"msteams": map[string]any{
+			"entities": entities,
+		},
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{
+			{"contentType": "application/vnd.microsoft.card.adaptive", "content": card},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal teams message: %w", err)
+	}
+
+	return postChatWebhook(ctx, s.url, "application/json", body)
+}
+
+// WebhookSender posts the chat messages to a generic chat webhook, rendering the request body
+// with a configurable template.
+type WebhookSender struct {
+	url         string
+	contentType string
+	template    *template.Template
+}
+
+func NewWebhookSender(url, contentType, bodyTemplate string) (*WebhookSender, error) {
+	if bodyTemplate == "" {
+		bodyTemplate = defaultChatWebhookTemplate
+	}
+
+	tmpl, err := template.New("chat_webhook").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"json": toJSON}).
+		Parse(bodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse chat webhook template: %w", err)
+	}
+
+	return &WebhookSender{
+		url:         url,
+		contentType: contentType,
+		template:    tmpl,
+	}, nil
+}
+
+func (*WebhookSender) Provider() enum.ChatProvider {
+	return enum.ChatProviderWebhook
+}
+
+func (*WebhookSender) Direct() bool {
+	return false
+}
+
+func (s *WebhookSender) Send(ctx context.Context, message *ChatMessage) error {
+	body := bytes.Buffer{}
+	err := s.template.Execute(&body, message)
+	if err != nil {
+		return fmt.Errorf("failed to execute chat webhook template: %w", err)
+	}
+
+	return postChatWebhook(ctx, s.url, s.contentType, body.Bytes())
+}
+
+func toJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func postChatWebhook(ctx context.Context, url, contentType string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create chat webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := chatHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post chat webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("chat webhook responded with status %d: %s", resp.StatusCode, msg)
+	}
+
+	return nil
+}
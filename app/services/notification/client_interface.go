@@ -21,7 +21,7 @@ import (
 )
 
 // Client is an interface for sending notifications, such as emails, Slack messages etc.
-// It is implemented by MailClient and in future we can have other implementations for other channels like Slack etc.
+// It is implemented by MailClient and ChatClient, MultiClient combines them.
 type Client interface {
 	SendCommentPRAuthor(
 		ctx context.Context,
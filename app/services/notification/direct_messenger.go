@@ -0,0 +1,65 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"context"
+	"errors"
+
+	"github.com/harness/gitness/types/enum"
+)
+
+// ErrDirectMessagesNotSupported is returned if direct messages can't be sent on the chat service.
+var ErrDirectMessagesNotSupported = errors.New("direct messages aren't supported by the chat service")
+
+// DirectMessenger sends private messages to a single user of a chat service,
+// e.g. to let the user confirm ownership of the chat identity.
+type DirectMessenger interface {
+	// CanSendDirectMessages returns true if direct messages can be sent on the chat service.
+	CanSendDirectMessages(provider enum.ChatProvider) bool
+
+	// SendDirectMessage sends the text privately to the user with the external ID on the chat service.
+	SendDirectMessage(ctx context.Context, provider enum.ChatProvider, externalID, text string) error
+}
+
+type directMessenger struct {
+	slackBot *SlackBotSender
+}
+
+func NewDirectMessenger(slackBotToken string) DirectMessenger {
+	messenger := directMessenger{}
+	if slackBotToken != "" {
+		messenger.slackBot = NewSlackBotSender(slackBotToken)
+	}
+
+	return messenger
+}
+
+func (m directMessenger) CanSendDirectMessages(provider enum.ChatProvider) bool {
+	return provider == enum.ChatProviderSlack && m.slackBot != nil
+}
+
+func (m directMessenger) SendDirectMessage(
+	ctx context.Context,
+	provider enum.ChatProvider,
+	externalID string,
+	text string,
+) error {
+	if !m.CanSendDirectMessages(provider) {
+		return ErrDirectMessagesNotSupported
+	}
+
+	return m.slackBot.SendDirectMessage(ctx, externalID, text)
+}
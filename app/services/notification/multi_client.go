@@ -0,0 +1,123 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"context"
+	"errors"
+
+	"github.com/harness/gitness/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// MultiClient implements Client by delivering the notifications through several clients,
+// e.g. mails and chat services. The failures of single clients are only logged, a notification
+// only fails if it couldn't be delivered by any client, so a retry doesn't duplicate the deliveries.
+type MultiClient struct {
+	clients []Client
+}
+
+func NewMultiClient(clients ...Client) MultiClient {
+	return MultiClient{
+		clients: clients,
+	}
+}
+
+func (m MultiClient) SendCommentPRAuthor(
+	ctx context.Context,
+	recipients []*types.PrincipalInfo,
+	payload *CommentPayload,
+) error {
+	return m.send(ctx, func(client Client) error {
+		return client.SendCommentPRAuthor(ctx, recipients, payload)
+	})
+}
+
+func (m MultiClient) SendCommentMentions(
+	ctx context.Context,
+	recipients []*types.PrincipalInfo,
+	payload *CommentPayload,
+) error {
+	return m.send(ctx, func(client Client) error {
+		return client.SendCommentMentions(ctx, recipients, payload)
+	})
+}
+
+func (m MultiClient) SendCommentParticipants(
+	ctx context.Context,
+	recipients []*types.PrincipalInfo,
+	payload *CommentPayload,
+) error {
+	return m.send(ctx, func(client Client) error {
+		return client.SendCommentParticipants(ctx, recipients, payload)
+	})
+}
+
+func (m MultiClient) SendReviewerAdded(
+	ctx context.Context,
+	recipients []*types.PrincipalInfo,
+	payload *ReviewerAddedPayload,
+) error {
+	return m.send(ctx, func(client Client) error {
+		return client.SendReviewerAdded(ctx, recipients, payload)
+	})
+}
+
+func (m MultiClient) SendPullReqBranchUpdated(
+	ctx context.Context,
+	recipients []*types.PrincipalInfo,
+	payload *PullReqBranchUpdatedPayload,
+) error {
+	return m.send(ctx, func(client Client) error {
+		return client.SendPullReqBranchUpdated(ctx, recipients, payload)
+	})
+}
+
+func (m MultiClient) SendReviewSubmitted(
+	ctx context.Context,
+	recipients []*types.PrincipalInfo,
+	payload *ReviewSubmittedPayload,
+) error {
+	return m.send(ctx, func(client Client) error {
+		return client.SendReviewSubmitted(ctx, recipients, payload)
+	})
+}
+
+func (m MultiClient) SendPullReqStateChanged(
+	ctx context.Context,
+	recipients []*types.PrincipalInfo,
+	payload *PullReqStateChangedPayload,
+) error {
+	return m.send(ctx, func(client Client) error {
+		return client.SendPullReqStateChanged(ctx, recipients, payload)
+	})
+}
+
+func (m MultiClient) send(ctx context.Context, fn func(client Client) error) error {
+	var errs []error
+	for _, client := range m.clients {
+		if err := fn(client); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("failed to deliver notification")
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 && len(errs) == len(m.clients) {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
@@ -82,6 +82,14 @@ type Config struct {
 	EventReaderName string
 	Concurrency     int
 	MaxRetries      int
+
+	// The chat channels are only used when configured.
+	SlackWebhookURL        string
+	SlackBotToken          string
+	TeamsWebhookURL        string
+	ChatWebhookURL         string
+	ChatWebhookTemplate    string
+	ChatWebhookContentType string
 }
 
 type Service struct {
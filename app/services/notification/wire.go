@@ -19,6 +19,7 @@ import (
 
 	pullreqevents "github.com/harness/gitness/app/events/pullreq"
 	"github.com/harness/gitness/app/services/notification/mailer"
+	"github.com/harness/gitness/app/services/publicaccess"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/app/url"
 	"github.com/harness/gitness/events"
@@ -27,8 +28,9 @@ import (
 )
 
 var WireSet = wire.NewSet(
-	ProvideMailClient,
+	ProvideNotificationClient,
 	ProvideNotificationService,
+	ProvideDirectMessenger,
 )
 
 func ProvideNotificationService(
@@ -61,6 +63,48 @@ func ProvideNotificationService(
 	)
 }
 
-func ProvideMailClient(mailer mailer.Mailer) Client {
-	return NewMailClient(mailer)
+// ProvideNotificationClient provides the client delivering the notifications as mails
+// and through the configured chat services.
+func ProvideNotificationClient(
+	config Config,
+	mailer mailer.Mailer,
+	chatIdentityStore store.UserChatIdentityStore,
+	publicAccess publicaccess.Service,
+) (Client, error) {
+	clients := []Client{NewMailClient(mailer)}
+
+	if config.SlackWebhookURL != "" {
+		clients = append(clients,
+			NewChatClient(NewSlackWebhookSender(config.SlackWebhookURL), chatIdentityStore, publicAccess))
+	}
+
+	if config.SlackBotToken != "" {
+		clients = append(clients,
+			NewChatClient(NewSlackBotSender(config.SlackBotToken), chatIdentityStore, publicAccess))
+	}
+
+	if config.TeamsWebhookURL != "" {
+		clients = append(clients,
+			NewChatClient(NewTeamsSender(config.TeamsWebhookURL), chatIdentityStore, publicAccess))
+	}
+
+	if config.ChatWebhookURL != "" {
+		sender, err := NewWebhookSender(config.ChatWebhookURL, config.ChatWebhookContentType,
+			config.ChatWebhookTemplate)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, NewChatClient(sender, chatIdentityStore, publicAccess))
+	}
+
+	if len(clients) == 1 {
+		return clients[0], nil
+	}
+
+	return NewMultiClient(clients...), nil
+}
+
+// ProvideDirectMessenger provides the messenger sending private messages through the configured chat services.
+func ProvideDirectMessenger(config Config) DirectMessenger {
+	return NewDirectMessenger(config.SlackBotToken)
 }
@@ -28,6 +28,7 @@ type Branch struct {
 	Bypass    DefBypass    `json:"bypass"`
 	PullReq   DefPullReq   `json:"pullreq"`
 	Lifecycle DefLifecycle `json:"lifecycle"`
+	Push      DefPush      `json:"push"`
 }
 
 var (
@@ -98,6 +99,13 @@ func (v *Branch) RefChangeVerify(
 		return nil, fmt.Errorf("lifecycle error: %w", err)
 	}
 
+	pushViolations, err := v.Push.RefChangeVerify(ctx, in)
+	if err != nil {
+		return nil, fmt.Errorf("push error: %w", err)
+	}
+
+	violations = append(violations, pushViolations...)
+
 	bypassable := v.Bypass.matches(ctx, in.Actor, in.IsRepoOwner, in.ResolveUserGroupID)
 	bypassed := in.AllowBypass && bypassable
 	for i := range violations {
@@ -129,5 +137,9 @@ func (v *Branch) Sanitize() error {
 		return fmt.Errorf("lifecycle: %w", err)
 	}
 
+	if err := v.Push.Sanitize(); err != nil {
+		return fmt.Errorf("push: %w", err)
+	}
+
 	return nil
 }
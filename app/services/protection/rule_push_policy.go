@@ -0,0 +1,344 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protection
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/harness/gitness/types"
+)
+
+const TypePushPolicy types.RuleType = "push_policy"
+
+// PushPolicy implements protection rules for the rule type TypePushPolicy.
+// The rules restrict the content of the commits pushed to the matching branches,
+// as well as of the commits pushed to any tag or other non-branch reference.
+type PushPolicy struct {
+	Bypass DefBypass     `json:"bypass"`
+	Policy DefPushPolicy `json:"policy"`
+}
+
+var (
+	// ensures that the PushPolicy type implements Definition interface.
+	_ Definition = (*PushPolicy)(nil)
+)
+
+// MergeVerify returns no violations, push policy rules don't apply to pull requests.
+func (v *PushPolicy) MergeVerify(
+	context.Context,
+	MergeVerifyInput,
+) (MergeVerifyOutput, []types.RuleViolations, error) {
+	return MergeVerifyOutput{}, nil, nil
+}
+
+// RequiredChecks returns no checks, push policy rules don't apply to pull requests.
+func (v *PushPolicy) RequiredChecks(
+	context.Context,
+	RequiredChecksInput,
+) (RequiredChecksOutput, error) {
+	return RequiredChecksOutput{}, nil
+}
+
+func (v *PushPolicy) RefChangeVerify(
+	ctx context.Context,
+	in RefChangeVerifyInput,
+) (violations []types.RuleViolations, err error) {
+	if len(in.RefNames) == 0 {
+		return []types.RuleViolations{}, nil
+	}
+
+	violations, err = v.Policy.RefChangeVerify(ctx, in)
+	if err != nil {
+		return nil, fmt.Errorf("policy error: %w", err)
+	}
+
+	bypassable := v.Bypass.matches(ctx, in.Actor, in.IsRepoOwner, in.ResolveUserGroupID)
+	bypassed := in.AllowBypass && bypassable
+	for i := range violations {
+		violations[i].Bypassable = bypassable
+		violations[i].Bypassed = bypassed
+	}
+
+	return
+}
+
+func (v *PushPolicy) UserIDs() ([]int64, error) {
+	return v.Bypass.UserIDs, nil
+}
+
+func (v *PushPolicy) UserGroupIDs() ([]int64, error) {
+	return v.Bypass.UserGroupIDs, nil
+}
+
+func (v *PushPolicy) Sanitize() error {
+	if err := v.Bypass.Sanitize(); err != nil {
+		return fmt.Errorf("bypass: %w", err)
+	}
+
+	if err := v.Policy.Sanitize(); err != nil {
+		return fmt.Errorf("policy: %w", err)
+	}
+
+	return nil
+}
+
+// PushedCommit holds the data of a newly pushed commit that is relevant for the push policy rules.
+type PushedCommit struct {
+	SHA            string
+	Message        string
+	AuthorEmail    string
+	CommitterEmail string
+	// Paths are the paths changed by the commit. Populated only if requested.
+	Paths []string
+}
+
+// DefPushPolicy restricts the commits that can be pushed.
+type DefPushPolicy struct {
+	// CommitMessagePattern is a regular expression that the message of every pushed commit must match.
+	CommitMessagePattern string `json:"commit_message_pattern,omitempty"`
+
+	// RequireVerifiedEmails requires the author and committer email of every pushed commit
+	// to be a verified email address of a user, unless its domain is listed in AllowedEmailDomains.
+	RequireVerifiedEmails bool `json:"require_verified_emails,omitempty"`
+
+	// AllowedEmailDomains restricts the author and committer emails of the pushed commits to the listed domains.
+	AllowedEmailDomains []string `json:"allowed_email_domains,omitempty"`
+
+	// BlockedPaths is a list of globstar patterns of the paths that the pushed commits must not change.
+	BlockedPaths []string `json:"blocked_paths,omitempty"`
+
+	// MaxCommits is the max number of new commits a single push can contain.
+	MaxCommits int `json:"max_commits,omitempty"`
+
+	commitMessageRegexp *regexp.Regexp
+}
+
+// ensures that the DefPushPolicy type implements Sanitizer and RefChangeVerifier interfaces.
+var (
+	_ Sanitizer         = (*DefPushPolicy)(nil)
+	_ RefChangeVerifier = (*DefPushPolicy)(nil)
+)
+
+const (
+	codePushPolicyCommitMessage = "push_policy.commit_message_pattern"
+	codePushPolicyEmail         = "push_policy.email"
+	codePushPolicyBlockedPaths  = "push_policy.blocked_paths"
+	codePushPolicyMaxCommits    = "push_policy.max_commits"
+)
+
+func (v *DefPushPolicy) isEmpty() bool {
+	return v.CommitMessagePattern == "" &&
+		!v.RequireVerifiedEmails &&
+		len(v.AllowedEmailDomains) == 0 &&
+		len(v.BlockedPaths) == 0 &&
+		v.MaxCommits == 0
+}
+
+//nolint:gocognit // it's easier to follow the policy checks in one place
+func (v *DefPushPolicy) RefChangeVerify(ctx context.Context, in RefChangeVerifyInput) ([]types.RuleViolations, error) {
+	if v.isEmpty() || in.ResolvePushedCommits == nil {
+		return nil, nil
+	}
+
+	if in.RefAction == RefActionDelete {
+		return nil, nil
+	}
+
+	commits, err := in.ResolvePushedCommits(ctx, in.RefNames, len(v.BlockedPaths) > 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve pushed commits: %w", err)
+	}
+
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	var violations types.RuleViolations
+
+	// the reference kind is part of the messages only, the params are the same for all reference types.
+	pushTo := "Push to " + refTypeName(in.RefType) + " %q"
+
+	if v.MaxCommits > 0 && len(commits) > v.MaxCommits {
+		violations.Addf(codePushPolicyMaxCommits,
+			pushTo+" contains %d new commits, the limit is %d.",
+			in.RefNames[0], len(commits), v.MaxCommits)
+	}
+
+	if v.commitMessageRegexp != nil {
+		var invalid []string
+		for _, commit := range commits {
+			if !v.commitMessageRegexp.MatchString(commit.Message) {
+				invalid = append(invalid, commit.SHA)
+			}
+		}
+
+		if len(invalid) > 0 {
+			violations.Addf(codePushPolicyCommitMessage,
+				pushTo+" contains %d commits with a message not matching the pattern %q, e.g. %s.",
+				in.RefNames[0], len(invalid), v.CommitMessagePattern, invalid[0])
+		}
+	}
+
+	if v.RequireVerifiedEmails || len(v.AllowedEmailDomains) > 0 {
+		verified := map[string]bool{}
+		var invalid []string
+		var invalidEmail string
+		for _, commit := range commits {
+			for _, email := range []string{commit.AuthorEmail, commit.CommitterEmail} {
+				allowed, err := v.isEmailAllowed(ctx, in.IsVerifiedEmail, verified, email)
+				if err != nil {
+					return nil, err
+				}
+				if !allowed {
+					if len(invalid) == 0 {
+						invalidEmail = email
+					}
+					invalid = append(invalid, commit.SHA)
+					break
+				}
+			}
+		}
+
+		if len(invalid) > 0 {
+			violations.Addf(codePushPolicyEmail,
+				pushTo+" contains %d commits with an author or committer email that isn't allowed, "+
+					"e.g. %s with %q.",
+				in.RefNames[0], len(invalid), invalid[0], invalidEmail)
+		}
+	}
+
+	if len(v.BlockedPaths) > 0 {
+		var invalid []string
+		var invalidPath string
+		for _, commit := range commits {
+			if path, ok := v.findBlockedPath(commit.Paths); ok {
+				if len(invalid) == 0 {
+					invalidPath = path
+				}
+				invalid = append(invalid, commit.SHA)
+			}
+		}
+
+		if len(invalid) > 0 {
+			violations.Addf(codePushPolicyBlockedPaths,
+				pushTo+" contains %d commits changing blocked paths, e.g. %s changes %q.",
+				in.RefNames[0], len(invalid), invalid[0], invalidPath)
+		}
+	}
+
+	if len(violations.Violations) > 0 {
+		return []types.RuleViolations{violations}, nil
+	}
+
+	return nil, nil
+}
+
+func refTypeName(refType RefType) string {
+	switch refType {
+	case RefTypeBranch:
+		return "branch"
+	case RefTypeTag:
+		return "tag"
+	case RefTypeRaw:
+		return "reference"
+	}
+
+	return "reference"
+}
+
+func (v *DefPushPolicy) isEmailAllowed(
+	ctx context.Context,
+	isVerifiedEmail func(ctx context.Context, email string) (bool, error),
+	verified map[string]bool,
+	email string,
+) (bool, error) {
+	email = strings.ToLower(email)
+
+	if _, domain, ok := strings.Cut(email, "@"); ok {
+		for _, allowedDomain := range v.AllowedEmailDomains {
+			if domain == allowedDomain {
+				return true, nil
+			}
+		}
+	}
+
+	if !v.RequireVerifiedEmails || isVerifiedEmail == nil {
+		return false, nil
+	}
+
+	if ok, exists := verified[email]; exists {
+		return ok, nil
+	}
+
+	ok, err := isVerifiedEmail(ctx, email)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if email %q is verified: %w", email, err)
+	}
+
+	verified[email] = ok
+
+	return ok, nil
+}
+
+func (v *DefPushPolicy) findBlockedPath(paths []string) (string, bool) {
+	for _, path := range paths {
+		for _, pattern := range v.BlockedPaths {
+			if patternMatches(pattern, path) {
+				return path, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func (v *DefPushPolicy) Sanitize() error {
+	v.commitMessageRegexp = nil
+	if v.CommitMessagePattern != "" {
+		re, err := regexp.Compile(v.CommitMessagePattern)
+		if err != nil {
+			return fmt.Errorf("invalid commit message pattern: %w", err)
+		}
+
+		v.commitMessageRegexp = re
+	}
+
+	domains := make([]string, 0, len(v.AllowedEmailDomains))
+	for _, domain := range v.AllowedEmailDomains {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+		if domain == "" || strings.Contains(domain, "@") {
+			return fmt.Errorf("invalid email domain: %q", domain)
+		}
+
+		domains = append(domains, domain)
+	}
+
+	v.AllowedEmailDomains = domains
+
+	for _, pattern := range v.BlockedPaths {
+		if err := patternValidate(pattern); err != nil {
+			return fmt.Errorf("invalid blocked path pattern %q: %w", pattern, err)
+		}
+	}
+
+	if v.MaxCommits < 0 {
+		return fmt.Errorf("max commits can't be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/harness/gitness/types"
+)
+
+func TestDefPushPolicy_RefChangeVerify(t *testing.T) {
+	const refName = "main"
+
+	commits := []PushedCommit{
+		{
+			SHA:            "aaa",
+			Message:        "PROJ-1: add feature",
+			AuthorEmail:    "john@example.com",
+			CommitterEmail: "john@example.com",
+			Paths:          []string{"main.go"},
+		},
+		{
+			SHA:            "bbb",
+			Message:        "update deps",
+			AuthorEmail:    "jane@Corp.io",
+			CommitterEmail: "bot@other.io",
+			Paths:          []string{"vendor/lib/lib.go", "go.mod"},
+		},
+	}
+
+	resolve := func(_ context.Context, _ []string, withPaths bool) ([]PushedCommit, error) {
+		if withPaths {
+			return commits, nil
+		}
+
+		result := make([]PushedCommit, len(commits))
+		for i := range commits {
+			result[i] = commits[i]
+			result[i].Paths = nil
+		}
+		return result, nil
+	}
+
+	isVerifiedEmail := func(_ context.Context, email string) (bool, error) {
+		return email == "john@example.com", nil
+	}
+
+	tests := []struct {
+		name      string
+		def       DefPushPolicy
+		action    RefAction
+		expCodes  []string
+		expParams [][]any
+	}{
+		{
+			name:   "empty",
+			action: RefActionUpdate,
+		},
+		{
+			name:      "commit_message_pattern-fail",
+			def:       DefPushPolicy{CommitMessagePattern: `^[A-Z]+-\d+: `},
+			action:    RefActionUpdate,
+			expCodes:  []string{codePushPolicyCommitMessage},
+			expParams: [][]any{{refName, 1, `^[A-Z]+-\d+: `, "bbb"}},
+		},
+		{
+			name:   "commit_message_pattern-success",
+			def:    DefPushPolicy{CommitMessagePattern: `\w+`},
+			action: RefActionUpdate,
+		},
+		{
+			name:      "verified_emails-fail",
+			def:       DefPushPolicy{RequireVerifiedEmails: true},
+			action:    RefActionCreate,
+			expCodes:  []string{codePushPolicyEmail},
+			expParams: [][]any{{refName, 1, "bbb", "jane@Corp.io"}},
+		},
+		{
+			name:      "allowed_domains-fail",
+			def:       DefPushPolicy{RequireVerifiedEmails: true, AllowedEmailDomains: []string{"@CORP.io"}},
+			action:    RefActionUpdate,
+			expCodes:  []string{codePushPolicyEmail},
+			expParams: [][]any{{refName, 1, "bbb", "bot@other.io"}},
+		},
+		{
+			name: "allowed_domains-success",
+			def: DefPushPolicy{
+				RequireVerifiedEmails: true,
+				AllowedEmailDomains:   []string{"corp.io", "other.io"},
+			},
+			action: RefActionUpdate,
+		},
+		{
+			name:      "blocked_paths-fail",
+			def:       DefPushPolicy{BlockedPaths: []string{"vendor/**"}},
+			action:    RefActionUpdateForce,
+			expCodes:  []string{codePushPolicyBlockedPaths},
+			expParams: [][]any{{refName, 1, "bbb", "vendor/lib/lib.go"}},
+		},
+		{
+			name:      "max_commits-fail",
+			def:       DefPushPolicy{MaxCommits: 1},
+			action:    RefActionUpdate,
+			expCodes:  []string{codePushPolicyMaxCommits},
+			expParams: [][]any{{refName, 2, 1}},
+		},
+		{
+			name: "delete",
+			def: DefPushPolicy{
+				CommitMessagePattern: `^x$`,
+				BlockedPaths:         []string{"**"},
+				MaxCommits:           1,
+			},
+			action: RefActionDelete,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.def.Sanitize(); err != nil {
+				t.Errorf("invalid: %s", err.Error())
+				return
+			}
+
+			in := RefChangeVerifyInput{
+				ResolvePushedCommits: resolve,
+				IsVerifiedEmail:      isVerifiedEmail,
+				RefNames:             []string{refName},
+				RefAction:            test.action,
+				RefType:              RefTypeBranch,
+			}
+
+			violations, err := test.def.RefChangeVerify(context.Background(), in)
+			if err != nil {
+				t.Errorf("got an error: %s", err.Error())
+				return
+			}
+
+			inspectBranchViolations(t, test.expCodes, test.expParams, violations)
+		})
+	}
+}
+
+func TestDefPushPolicy_Sanitize(t *testing.T) {
+	tests := []struct {
+		name   string
+		def    DefPushPolicy
+		expErr bool
+	}{
+		{
+			name: "valid",
+			def: DefPushPolicy{
+				CommitMessagePattern: `^[A-Z]+-\d+`,
+				AllowedEmailDomains:  []string{"example.com"},
+				BlockedPaths:         []string{"vendor/**"},
+				MaxCommits:           10,
+			},
+		},
+		{
+			name:   "invalid-regexp",
+			def:    DefPushPolicy{CommitMessagePattern: `[`},
+			expErr: true,
+		},
+		{
+			name:   "invalid-domain",
+			def:    DefPushPolicy{AllowedEmailDomains: []string{"a@b.com"}},
+			expErr: true,
+		},
+		{
+			name:   "empty-path",
+			def:    DefPushPolicy{BlockedPaths: []string{""}},
+			expErr: true,
+		},
+		{
+			name:   "negative-max-commits",
+			def:    DefPushPolicy{MaxCommits: -1},
+			expErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.def.Sanitize()
+			if test.expErr != (err != nil) {
+				t.Errorf("error mismatch: want error=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestPushPolicy_RefChangeVerify_RefTypes(t *testing.T) {
+	policy := PushPolicy{Policy: DefPushPolicy{MaxCommits: 1}}
+	if err := policy.Sanitize(); err != nil {
+		t.Fatalf("invalid: %s", err.Error())
+	}
+
+	resolve := func(context.Context, []string, bool) ([]PushedCommit, error) {
+		return []PushedCommit{{SHA: "aaa"}, {SHA: "bbb"}}, nil
+	}
+
+	tests := []struct {
+		refType RefType
+		refName string
+		exp     string
+	}{
+		{refType: RefTypeBranch, refName: "main", exp: `Push to branch "main" contains 2 new commits, the limit is 1.`},
+		{refType: RefTypeTag, refName: "v1", exp: `Push to tag "v1" contains 2 new commits, the limit is 1.`},
+		{
+			refType: RefTypeRaw,
+			refName: "refs/notes/commits",
+			exp:     `Push to reference "refs/notes/commits" contains 2 new commits, the limit is 1.`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.refName, func(t *testing.T) {
+			violations, err := policy.RefChangeVerify(context.Background(), RefChangeVerifyInput{
+				ResolvePushedCommits: resolve,
+				Actor:                &types.Principal{ID: 1},
+				RefNames:             []string{test.refName},
+				RefAction:            RefActionCreate,
+				RefType:              test.refType,
+			})
+			if err != nil {
+				t.Fatalf("got an error: %s", err.Error())
+			}
+
+			if len(violations) != 1 || len(violations[0].Violations) != 1 {
+				t.Fatalf("expected one violation, got %+v", violations)
+			}
+
+			if msg := violations[0].Violations[0].Message; msg != test.exp {
+				t.Errorf("expected message %q, got %q", test.exp, msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protection
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/types"
+)
+
+const TypeTag types.RuleType = "tag"
+
+// Tag implements protection rules for the rule type TypeTag.
+type Tag struct {
+	Bypass    DefBypass       `json:"bypass"`
+	Lifecycle DefTagLifecycle `json:"lifecycle"`
+}
+
+var (
+	// ensures that the Tag type implements Definition interface.
+	_ Definition = (*Tag)(nil)
+)
+
+// MergeVerify returns no violations, tag rules don't apply to pull requests.
+func (v *Tag) MergeVerify(
+	context.Context,
+	MergeVerifyInput,
+) (MergeVerifyOutput, []types.RuleViolations, error) {
+	return MergeVerifyOutput{}, nil, nil
+}
+
+// RequiredChecks returns no checks, tag rules don't apply to pull requests.
+func (v *Tag) RequiredChecks(
+	context.Context,
+	RequiredChecksInput,
+) (RequiredChecksOutput, error) {
+	return RequiredChecksOutput{}, nil
+}
+
+func (v *Tag) RefChangeVerify(
+	ctx context.Context,
+	in RefChangeVerifyInput,
+) (violations []types.RuleViolations, err error) {
+	if in.RefType != RefTypeTag || len(in.RefNames) == 0 {
+		return []types.RuleViolations{}, nil
+	}
+
+	violations, err = v.Lifecycle.RefChangeVerify(ctx, in)
+	if err != nil {
+		return nil, fmt.Errorf("lifecycle error: %w", err)
+	}
+
+	bypassable := v.Bypass.matches(ctx, in.Actor, in.IsRepoOwner, in.ResolveUserGroupID)
+	bypassed := in.AllowBypass && bypassable
+	for i := range violations {
+		violations[i].Bypassable = bypassable
+		violations[i].Bypassed = bypassed
+	}
+
+	return
+}
+
+func (v *Tag) UserIDs() ([]int64, error) {
+	return v.Bypass.UserIDs, nil
+}
+
+func (v *Tag) UserGroupIDs() ([]int64, error) {
+	return v.Bypass.UserGroupIDs, nil
+}
+
+func (v *Tag) Sanitize() error {
+	if err := v.Bypass.Sanitize(); err != nil {
+		return fmt.Errorf("bypass: %w", err)
+	}
+
+	if err := v.Lifecycle.Sanitize(); err != nil {
+		return fmt.Errorf("lifecycle: %w", err)
+	}
+
+	return nil
+}
@@ -58,6 +58,8 @@ func (s ruleSet) MergeVerify(
 			out.RequiresCodeOwnersApprovalLatest = out.RequiresCodeOwnersApprovalLatest || rOut.RequiresCodeOwnersApprovalLatest
 			out.RequiresCommentResolution = out.RequiresCommentResolution || rOut.RequiresCommentResolution
 			out.RequiresNoChangeRequests = out.RequiresNoChangeRequests || rOut.RequiresNoChangeRequests
+			out.RequiresSignedCommits = out.RequiresSignedCommits || rOut.RequiresSignedCommits
+			out.RequiresMergeQueue = out.RequiresMergeQueue || rOut.RequiresMergeQueue
 
 			return nil
 		})
@@ -107,7 +109,7 @@ func (s ruleSet) RequiredChecks(
 func (s ruleSet) RefChangeVerify(ctx context.Context, in RefChangeVerifyInput) ([]types.RuleViolations, error) {
 	var violations []types.RuleViolations
 
-	err := s.forEachRuleMatchRefs(in.Repo.DefaultBranch, in.RefNames,
+	err := s.forEachRuleMatchRefs(in.Repo.DefaultBranch, in.RefType, in.RefNames,
 		func(r *types.RuleInfoInternal, p Protection, matched []string) error {
 			ruleIn := in
 			ruleIn.RefNames = matched
@@ -236,15 +238,31 @@ func (s ruleSet) forEachRuleMatchBranch(
 
 func (s ruleSet) forEachRuleMatchRefs(
 	defaultBranch string,
+	refType RefType,
 	refNames []string,
 	fn func(r *types.RuleInfoInternal, p Protection, matched []string) error,
 ) error {
 	for i := range s.rules {
 		r := s.rules[i]
 
-		matched, err := matchedNames(r.Pattern, defaultBranch, refNames...)
-		if err != nil {
-			return err
+		// Tag rules aren't related to the default branch.
+		defaultName := defaultBranch
+		if r.Type == TypeTag {
+			defaultName = ""
+		}
+
+		var matched []string
+		var err error
+		if r.Type == TypePushPolicy && refType != RefTypeBranch {
+			// Push policies restrict the commits that enter the repository. The commits pushed to a branch
+			// later aren't checked again, as they're already referenced, so the push policies apply to
+			// all tags and other references, regardless of the branch pattern.
+			matched = refNames
+		} else {
+			matched, err = matchedNames(r.Pattern, defaultName, refNames...)
+			if err != nil {
+				return err
+			}
 		}
 		if len(matched) == 0 {
 			continue
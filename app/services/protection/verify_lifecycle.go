@@ -27,13 +27,23 @@ type (
 
 	RefChangeVerifyInput struct {
 		ResolveUserGroupID func(ctx context.Context, userGroupIDs []int64) ([]int64, error)
-		Actor              *types.Principal
-		AllowBypass        bool
-		IsRepoOwner        bool
-		Repo               *types.Repository
-		RefAction          RefAction
-		RefType            RefType
-		RefNames           []string
+		// ResolveUnverifiedCommits returns SHAs of the new commits of the provided references
+		// that don't have a verified signature. It's only called when a rule requires it.
+		// If not provided, rules that require signed commits are violated.
+		ResolveUnverifiedCommits func(ctx context.Context, refNames []string) ([]string, error)
+		// ResolvePushedCommits returns the new commits of the provided references. The changed paths
+		// are resolved only if requested. It's optional and is only called when a rule requires it.
+		ResolvePushedCommits func(ctx context.Context, refNames []string, withPaths bool) ([]PushedCommit, error)
+		// IsVerifiedEmail returns true if the email address is a verified email address of a user.
+		// It's optional and is only called when a rule requires it.
+		IsVerifiedEmail func(ctx context.Context, email string) (bool, error)
+		Actor           *types.Principal
+		AllowBypass     bool
+		IsRepoOwner     bool
+		Repo            *types.Repository
+		RefAction       RefAction
+		RefType         RefType
+		RefNames        []string
 	}
 
 	RefType int
@@ -46,6 +56,14 @@ type (
 		UpdateForbidden      bool `json:"update_forbidden,omitempty"`
 		UpdateForceForbidden bool `json:"update_force_forbidden,omitempty"`
 	}
+
+	// DefTagLifecycle restricts the changes of tags. Tags aren't expected to move,
+	// so every update of a tag is treated like a force push.
+	DefTagLifecycle struct {
+		CreateForbidden bool `json:"create_forbidden,omitempty"`
+		DeleteForbidden bool `json:"delete_forbidden,omitempty"`
+		UpdateForbidden bool `json:"update_forbidden,omitempty"`
+	}
 )
 
 const (
@@ -61,10 +79,12 @@ const (
 	RefActionUpdateForce
 )
 
-// ensures that the DefLifecycle type implements Sanitizer and RefChangeVerifier interfaces.
+// ensures that the DefLifecycle and DefTagLifecycle types implement Sanitizer and RefChangeVerifier interfaces.
 var (
 	_ Sanitizer         = (*DefLifecycle)(nil)
 	_ RefChangeVerifier = (*DefLifecycle)(nil)
+	_ Sanitizer         = (*DefTagLifecycle)(nil)
+	_ RefChangeVerifier = (*DefTagLifecycle)(nil)
 )
 
 const (
@@ -110,3 +130,35 @@ func (v *DefLifecycle) RefChangeVerify(_ context.Context, in RefChangeVerifyInpu
 func (*DefLifecycle) Sanitize() error {
 	return nil
 }
+
+func (v *DefTagLifecycle) RefChangeVerify(_ context.Context, in RefChangeVerifyInput) ([]types.RuleViolations, error) {
+	var violations types.RuleViolations
+
+	switch in.RefAction {
+	case RefActionCreate:
+		if v.CreateForbidden {
+			violations.Addf(codeLifecycleCreate,
+				"Creation of tag %q is not allowed.", in.RefNames[0])
+		}
+	case RefActionDelete:
+		if v.DeleteForbidden {
+			violations.Addf(codeLifecycleDelete,
+				"Delete of tag %q is not allowed.", in.RefNames[0])
+		}
+	case RefActionUpdate, RefActionUpdateForce:
+		if v.UpdateForbidden {
+			violations.Addf(codeLifecycleUpdate,
+				"Update of tag %q is not allowed.", in.RefNames[0])
+		}
+	}
+
+	if len(violations.Violations) > 0 {
+		return []types.RuleViolations{violations}, nil
+	}
+
+	return nil, nil
+}
+
+func (*DefTagLifecycle) Sanitize() error {
+	return nil
+}
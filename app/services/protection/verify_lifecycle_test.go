@@ -119,3 +119,72 @@ func inspectBranchViolations(t *testing.T,
 		}
 	}
 }
+
+func TestDefPush_RefChangeVerify(t *testing.T) {
+	const refName = "a"
+	unverified := func(context.Context, []string) ([]string, error) {
+		return []string{"abc"}, nil
+	}
+	tests := []struct {
+		name      string
+		def       DefPush
+		action    RefAction
+		resolve   func(ctx context.Context, refNames []string) ([]string, error)
+		expCodes  []string
+		expParams [][]any
+	}{
+		{
+			name:    "empty",
+			action:  RefActionUpdate,
+			resolve: unverified,
+		},
+		{
+			name:      "push.require_signed_commits-fail",
+			def:       DefPush{RequireSignedCommits: true},
+			action:    RefActionUpdate,
+			resolve:   unverified,
+			expCodes:  []string{"push.require_signed_commits"},
+			expParams: [][]any{{refName, 1, "abc"}},
+		},
+		{
+			name:    "push.require_signed_commits-delete",
+			def:     DefPush{RequireSignedCommits: true},
+			action:  RefActionDelete,
+			resolve: unverified,
+		},
+		{
+			name:      "push.require_signed_commits-no-resolver",
+			def:       DefPush{RequireSignedCommits: true},
+			action:    RefActionUpdate,
+			expCodes:  []string{"push.require_signed_commits"},
+			expParams: [][]any{{refName}},
+		},
+		{
+			name:   "push.require_signed_commits-success",
+			def:    DefPush{RequireSignedCommits: true},
+			action: RefActionCreate,
+			resolve: func(context.Context, []string) ([]string, error) {
+				return nil, nil
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			in := RefChangeVerifyInput{
+				ResolveUnverifiedCommits: test.resolve,
+				RefNames:                 []string{refName},
+				RefAction:                test.action,
+				RefType:                  RefTypeBranch,
+			}
+
+			violations, err := test.def.RefChangeVerify(context.Background(), in)
+			if err != nil {
+				t.Errorf("got an error: %s", err.Error())
+				return
+			}
+
+			inspectBranchViolations(t, test.expCodes, test.expParams, violations)
+		})
+	}
+}
@@ -36,16 +36,21 @@ type (
 
 	MergeVerifyInput struct {
 		ResolveUserGroupID func(ctx context.Context, userGroupIDs []int64) ([]int64, error)
-		Actor              *types.Principal
-		AllowBypass        bool
-		IsRepoOwner        bool
-		TargetRepo         *types.Repository
-		SourceRepo         *types.Repository
-		PullReq            *types.PullReq
-		Reviewers          []*types.PullReqReviewer
-		Method             enum.MergeMethod
-		CheckResults       []types.CheckResult
-		CodeOwners         *codeowners.Evaluation
+		// ResolveUnverifiedCommits returns SHAs of the pull request commits that don't have a verified signature.
+		// It's only called when a rule requires it. If not provided, rules that require signed commits are violated.
+		ResolveUnverifiedCommits func(ctx context.Context) ([]string, error)
+		Actor                    *types.Principal
+		AllowBypass              bool
+		IsRepoOwner              bool
+		TargetRepo               *types.Repository
+		SourceRepo               *types.Repository
+		PullReq                  *types.PullReq
+		Reviewers                []*types.PullReqReviewer
+		Method                   enum.MergeMethod
+		CheckResults             []types.CheckResult
+		CodeOwners               *codeowners.Evaluation
+		// MergeQueue is set when the pull request is merged or enqueued through the merge queue.
+		MergeQueue bool
 	}
 
 	MergeVerifyOutput struct {
@@ -57,6 +62,8 @@ type (
 		RequiresCodeOwnersApprovalLatest    bool
 		RequiresCommentResolution           bool
 		RequiresNoChangeRequests            bool
+		RequiresSignedCommits               bool
+		RequiresMergeQueue                  bool
 	}
 
 	RequiredChecksInput struct {
@@ -93,14 +100,16 @@ const (
 	codePullReqMergeStrategiesAllowed = "pullreq.merge.strategies_allowed"
 	codePullReqMergeDeleteBranch      = "pullreq.merge.delete_branch"
 	codePullReqMergeBlock             = "pullreq.merge.blocked"
+	codePullReqMergeQueue             = "pullreq.merge.queue"
 
 	codePullReqCommentsReqResolveAll      = "pullreq.comments.require_resolve_all"
 	codePullReqStatusChecksReqIdentifiers = "pullreq.status_checks.required_identifiers"
+	codePullReqCommitsReqSigned           = "pullreq.commits.require_signed"
 )
 
 //nolint:gocognit,gocyclo,cyclop // well aware of this
 func (v *DefPullReq) MergeVerify(
-	_ context.Context,
+	ctx context.Context,
 	in MergeVerifyInput,
 ) (MergeVerifyOutput, []types.RuleViolations, error) {
 	var out MergeVerifyOutput
@@ -110,6 +119,8 @@ func (v *DefPullReq) MergeVerify(
 	out.DeleteSourceBranch = v.Merge.DeleteBranch
 	out.RequiresCommentResolution = v.Comments.RequireResolveAll
 	out.RequiresNoChangeRequests = v.Approvals.RequireNoChangeRequest
+	out.RequiresSignedCommits = v.Commits.RequireSigned
+	out.RequiresMergeQueue = v.Merge.Queue
 
 	// output that depends on approval of latest commit
 	if v.Approvals.RequireLatestCommit {
@@ -226,6 +237,26 @@ func (v *DefPullReq) MergeVerify(
 		)
 	}
 
+	// pullreq.commits
+
+	if v.Commits.RequireSigned {
+		if in.ResolveUnverifiedCommits == nil {
+			violations.Add(codePullReqCommitsReqSigned,
+				"All commits must have a verified signature, but the commit signatures can't be verified.")
+		} else {
+			unverified, err := in.ResolveUnverifiedCommits(ctx)
+			if err != nil {
+				return out, nil, fmt.Errorf("failed to resolve unverified commits: %w", err)
+			}
+
+			if len(unverified) > 0 {
+				violations.Addf(codePullReqCommitsReqSigned,
+					"All commits must have a verified signature. There are %d commits without one, e.g. %s.",
+					len(unverified), unverified[0])
+			}
+		}
+	}
+
 	// pullreq.merge
 
 	out.AllowedMethods = enum.MergeMethods
@@ -251,6 +282,13 @@ func (v *DefPullReq) MergeVerify(
 			"The merge for the branch %s is not allowed.", in.PullReq.TargetBranch)
 	}
 
+	if v.Merge.Queue && !in.MergeQueue {
+		violations.Addf(
+			codePullReqMergeQueue,
+			"Pull requests targeting the branch %s must be merged through the merge queue.",
+			in.PullReq.TargetBranch)
+	}
+
 	if len(violations.Violations) > 0 {
 		return out, []types.RuleViolations{violations}, nil
 	}
@@ -349,6 +387,8 @@ type DefMerge struct {
 	StrategiesAllowed []enum.MergeMethod `json:"strategies_allowed,omitempty"`
 	DeleteBranch      bool               `json:"delete_branch,omitempty"`
 	Block             bool               `json:"block,omitempty"`
+	// Queue requires pull requests to be merged through the merge queue of the target branch.
+	Queue bool `json:"queue,omitempty"`
 }
 
 func (v *DefMerge) Sanitize() error {
@@ -370,8 +410,60 @@ func (v *DefMerge) Sanitize() error {
 	return nil
 }
 
+type DefCommits struct {
+	RequireSigned bool `json:"require_signed,omitempty"`
+}
+
+func (DefCommits) Sanitize() error {
+	return nil
+}
+
 type DefPush struct {
-	Block bool `json:"block,omitempty"`
+	Block                bool `json:"block,omitempty"`
+	RequireSignedCommits bool `json:"require_signed_commits,omitempty"`
+}
+
+// ensures that the DefPush type implements Sanitizer and RefChangeVerifier interfaces.
+var (
+	_ Sanitizer         = (*DefPush)(nil)
+	_ RefChangeVerifier = (*DefPush)(nil)
+)
+
+const (
+	codePushRequireSignedCommits = "push.require_signed_commits"
+)
+
+func (v *DefPush) RefChangeVerify(ctx context.Context, in RefChangeVerifyInput) ([]types.RuleViolations, error) {
+	if !v.RequireSignedCommits || in.RefAction == RefActionDelete {
+		return nil, nil
+	}
+
+	var violations types.RuleViolations
+
+	// Without a resolver the commits can't be verified, e.g. because the operation
+	// creates new commits on the server that are never signed.
+	if in.ResolveUnverifiedCommits == nil {
+		violations.Addf(codePushRequireSignedCommits,
+			"Commits pushed to branch %q must have a verified signature, which can't be ensured for this operation.",
+			in.RefNames[0])
+
+		return []types.RuleViolations{violations}, nil
+	}
+
+	unverified, err := in.ResolveUnverifiedCommits(ctx, in.RefNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve unverified commits: %w", err)
+	}
+
+	if len(unverified) == 0 {
+		return nil, nil
+	}
+
+	violations.Addf(codePushRequireSignedCommits,
+		"Push to branch %q contains %d commits without a verified signature, e.g. %s.",
+		in.RefNames[0], len(unverified), unverified[0])
+
+	return []types.RuleViolations{violations}, nil
 }
 
 func (v *DefPush) Sanitize() error {
@@ -383,6 +475,7 @@ type DefPullReq struct {
 	Comments     DefComments     `json:"comments"`
 	StatusChecks DefStatusChecks `json:"status_checks"`
 	Merge        DefMerge        `json:"merge"`
+	Commits      DefCommits      `json:"commits"`
 }
 
 func (v *DefPullReq) Sanitize() error {
@@ -402,6 +495,10 @@ func (v *DefPullReq) Sanitize() error {
 		return fmt.Errorf("merge: %w", err)
 	}
 
+	if err := v.Commits.Sanitize(); err != nil {
+		return fmt.Errorf("commits: %w", err)
+	}
+
 	return nil
 }
 
@@ -368,6 +368,33 @@ func TestDefPullReq_MergeVerify(t *testing.T) {
 				DeleteSourceBranch: true,
 			},
 		},
+		{
+			name: codePullReqMergeQueue + "-fail",
+			def:  DefPullReq{Merge: DefMerge{Queue: true}},
+			in: MergeVerifyInput{
+				PullReq: &types.PullReq{TargetBranch: "main"},
+				Method:  enum.MergeMethodMerge,
+			},
+			expCodes:  []string{codePullReqMergeQueue},
+			expParams: [][]any{{"main"}},
+			expOut: MergeVerifyOutput{
+				AllowedMethods:     enum.MergeMethods,
+				RequiresMergeQueue: true,
+			},
+		},
+		{
+			name: codePullReqMergeQueue + "-success",
+			def:  DefPullReq{Merge: DefMerge{Queue: true}},
+			in: MergeVerifyInput{
+				PullReq:    &types.PullReq{TargetBranch: "main"},
+				Method:     enum.MergeMethodMerge,
+				MergeQueue: true,
+			},
+			expOut: MergeVerifyOutput{
+				AllowedMethods:     enum.MergeMethods,
+				RequiresMergeQueue: true,
+			},
+		},
 		{
 			name: codePullReqApprovalReqChangeRequested + "-true",
 			def: DefPullReq{
@@ -463,6 +490,47 @@ func TestDefPullReq_MergeVerify(t *testing.T) {
 				AllowedMethods: enum.MergeMethods,
 			},
 		},
+		{
+			name: codePullReqCommitsReqSigned + "-fail",
+			def:  DefPullReq{Commits: DefCommits{RequireSigned: true}},
+			in: MergeVerifyInput{
+				ResolveUnverifiedCommits: func(context.Context) ([]string, error) {
+					return []string{"abc", "def"}, nil
+				},
+				Method: enum.MergeMethodMerge,
+			},
+			expCodes:  []string{codePullReqCommitsReqSigned},
+			expParams: [][]any{{2, "abc"}},
+			expOut: MergeVerifyOutput{
+				AllowedMethods:        enum.MergeMethods,
+				RequiresSignedCommits: true,
+			},
+		},
+		{
+			name:      codePullReqCommitsReqSigned + "-no-resolver",
+			def:       DefPullReq{Commits: DefCommits{RequireSigned: true}},
+			in:        MergeVerifyInput{Method: enum.MergeMethodMerge},
+			expCodes:  []string{codePullReqCommitsReqSigned},
+			expParams: [][]any{nil},
+			expOut: MergeVerifyOutput{
+				AllowedMethods:        enum.MergeMethods,
+				RequiresSignedCommits: true,
+			},
+		},
+		{
+			name: codePullReqCommitsReqSigned + "-success",
+			def:  DefPullReq{Commits: DefCommits{RequireSigned: true}},
+			in: MergeVerifyInput{
+				ResolveUnverifiedCommits: func(context.Context) ([]string, error) {
+					return nil, nil
+				},
+				Method: enum.MergeMethodMerge,
+			},
+			expOut: MergeVerifyOutput{
+				AllowedMethods:        enum.MergeMethods,
+				RequiresSignedCommits: true,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -17,14 +17,22 @@ package publickey
 import (
 	"crypto/sha256"
 	"encoding/base64"
+	"fmt"
+	"strings"
 
 	"github.com/harness/gitness/errors"
 
 	"github.com/gliderlabs/ssh"
+	"golang.org/x/crypto/openpgp" //nolint:staticcheck // deprecated, but sufficient to verify signatures
 	gossh "golang.org/x/crypto/ssh"
 	"golang.org/x/exp/slices"
 )
 
+// KeyTypePGP is the type of OpenPGP public keys.
+const KeyTypePGP = "pgp"
+
+const pgpPublicKeyBeginToken = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+
 var AllowedTypes = []string{
 	gossh.KeyAlgoRSA,
 	gossh.KeyAlgoECDSA256,
@@ -98,3 +106,53 @@ func (key KeyInfo) Fingerprint() string {
 func (key KeyInfo) Type() string {
 	return key.Key.Type()
 }
+
+// IsPGP returns true if the key data is an ASCII armored OpenPGP public key.
+func IsPGP(keyData string) bool {
+	return strings.HasPrefix(strings.TrimSpace(keyData), pgpPublicKeyBeginToken)
+}
+
+// ParsePGP parses an ASCII armored OpenPGP public key.
+func ParsePGP(keyData string) (PGPKeyInfo, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(keyData))
+	if err != nil {
+		return PGPKeyInfo{}, err
+	}
+
+	if len(entities) != 1 {
+		return PGPKeyInfo{}, errors.InvalidArgument("exactly one public key expected, got %d", len(entities))
+	}
+
+	return PGPKeyInfo{
+		Entity: entities[0],
+	}, nil
+}
+
+type PGPKeyInfo struct {
+	Entity *openpgp.Entity
+}
+
+func (key PGPKeyInfo) Fingerprint() string {
+	return fmt.Sprintf("%X", key.Entity.PrimaryKey.Fingerprint)
+}
+
+// Comment returns the name of the primary identity of the key.
+func (key PGPKeyInfo) Comment() string {
+	for name, identity := range key.Entity.Identities {
+		if identity.SelfSignature != nil && identity.SelfSignature.IsPrimaryId != nil && *identity.SelfSignature.IsPrimaryId {
+			return name
+		}
+	}
+
+	names := make([]string, 0, len(key.Entity.Identities))
+	for name := range key.Entity.Identities {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+
+	slices.Sort(names)
+
+	return names[0]
+}
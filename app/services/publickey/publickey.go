@@ -29,20 +29,24 @@ import (
 
 type Service interface {
 	ValidateKey(ctx context.Context, publicKey ssh.PublicKey, usage enum.PublicKeyUsage) (*types.PrincipalInfo, error)
+	VerifySignature(ctx context.Context, signature, payload, email string) (*types.GitSignature, error)
 }
 
 func NewService(
 	publicKeyStore store.PublicKeyStore,
+	principalStore store.PrincipalStore,
 	pCache store.PrincipalInfoCache,
 ) LocalService {
 	return LocalService{
 		publicKeyStore: publicKeyStore,
+		principalStore: principalStore,
 		pCache:         pCache,
 	}
 }
 
 type LocalService struct {
 	publicKeyStore store.PublicKeyStore
+	principalStore store.PrincipalStore
 	pCache         store.PrincipalInfoCache
 }
 
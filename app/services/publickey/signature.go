@@ -0,0 +1,296 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package publickey
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/harness/gitness/errors"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"golang.org/x/crypto/openpgp"        //nolint:staticcheck // deprecated, but sufficient to verify signatures
+	"golang.org/x/crypto/openpgp/armor"  //nolint:staticcheck // deprecated, but sufficient to verify signatures
+	"golang.org/x/crypto/openpgp/packet" //nolint:staticcheck // deprecated, but sufficient to verify signatures
+	gossh "golang.org/x/crypto/ssh"
+)
+
+const (
+	pgpSignatureBeginToken = "-----BEGIN PGP SIGNATURE-----" //#nosec G101
+	sshSignatureBeginToken = "-----BEGIN SSH SIGNATURE-----" //#nosec G101
+
+	// sshSignatureMagic is the preamble of SSH signatures, see PROTOCOL.sshsig of OpenSSH.
+	sshSignatureMagic = "SSHSIG"
+	// sshSignatureVersion is the only supported version of SSH signatures.
+	sshSignatureVersion = 1
+	// sshSignatureNamespace is the namespace git uses for SSH signatures.
+	sshSignatureNamespace = "git"
+
+	// maxSigningKeys is the max number of signing keys of a user that are checked against an OpenPGP signature.
+	maxSigningKeys = 100
+)
+
+// VerifySignature verifies the signature of a git object (commit or tag) against the registered signing keys.
+// The email is the email of the committer (or the tagger) the object is attributed to.
+func (s LocalService) VerifySignature(
+	ctx context.Context,
+	signature string,
+	payload string,
+	email string,
+) (*types.GitSignature, error) {
+	signature = strings.TrimSpace(signature)
+
+	switch {
+	case strings.HasPrefix(signature, sshSignatureBeginToken):
+		return s.verifySSHSignature(ctx, signature, payload, email)
+	case strings.HasPrefix(signature, pgpSignatureBeginToken):
+		return s.verifyPGPSignature(ctx, signature, payload, email)
+	default:
+		// unsupported signature format (e.g. x509)
+		return &types.GitSignature{Status: enum.GitSignatureStatusUnverified}, nil
+	}
+}
+
+func (s LocalService) verifySSHSignature(
+	ctx context.Context,
+	signature string,
+	payload string,
+	email string,
+) (*types.GitSignature, error) {
+	sig, err := parseSSHSignature(signature)
+	if err != nil {
+		return &types.GitSignature{Status: enum.GitSignatureStatusUnverified}, nil //nolint:nilerr
+	}
+
+	key := From(sig.publicKey)
+	result := &types.GitSignature{
+		Status:         enum.GitSignatureStatusUnknownKey,
+		KeyType:        key.Type(),
+		KeyFingerprint: key.Fingerprint(),
+	}
+
+	existingKeys, err := s.publicKeyStore.ListByFingerprint(ctx, result.KeyFingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keys by fingerprint: %w", err)
+	}
+
+	var principalID int64
+	for _, existingKey := range existingKeys {
+		if existingKey.Usage == enum.PublicKeyUsageSign && key.Matches(existingKey.Content) {
+			principalID = existingKey.PrincipalID
+			break
+		}
+	}
+
+	if principalID == 0 {
+		return result, nil
+	}
+
+	result.Signer, err = s.pCache.Get(ctx, principalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get principal info of signing key owner: %w", err)
+	}
+
+	result.Status = enum.GitSignatureStatusUnverified
+	if sig.verify([]byte(payload)) == nil && strings.EqualFold(result.Signer.Email, email) {
+		result.Status = enum.GitSignatureStatusVerified
+	}
+
+	return result, nil
+}
+
+func (s LocalService) verifyPGPSignature(
+	ctx context.Context,
+	signature string,
+	payload string,
+	email string,
+) (*types.GitSignature, error) {
+	issuerKeyID, err := parsePGPSignatureIssuer(signature)
+	if err != nil {
+		return &types.GitSignature{Status: enum.GitSignatureStatusUnverified}, nil //nolint:nilerr
+	}
+
+	result := &types.GitSignature{
+		Status:         enum.GitSignatureStatusUnknownKey,
+		KeyType:        KeyTypePGP,
+		KeyFingerprint: fmt.Sprintf("%016X", issuerKeyID),
+	}
+
+	// OpenPGP signatures only reference the (sub)key ID, so only the keys of the user the email belongs to are checked.
+	principal, err := s.principalStore.FindByEmail(ctx, email)
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find principal by email: %w", err)
+	}
+
+	keys, err := s.publicKeyStore.List(ctx, principal.ID, &types.PublicKeyFilter{
+		ListQueryFilter: types.ListQueryFilter{Pagination: types.Pagination{Size: maxSigningKeys}},
+		Usages:          []enum.PublicKeyUsage{enum.PublicKeyUsageSign},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signing keys: %w", err)
+	}
+
+	for _, k := range keys {
+		if k.Type != KeyTypePGP {
+			continue
+		}
+
+		keyInfo, err := ParsePGP(k.Content)
+		if err != nil {
+			continue
+		}
+
+		keyRing := openpgp.EntityList{keyInfo.Entity}
+		if len(keyRing.KeysById(issuerKeyID)) == 0 {
+			continue
+		}
+
+		result.KeyFingerprint = keyInfo.Fingerprint()
+		result.Signer = principal.ToPrincipalInfo()
+		result.Status = enum.GitSignatureStatusUnverified
+
+		_, err = openpgp.CheckArmoredDetachedSignature(keyRing,
+			strings.NewReader(payload), strings.NewReader(signature))
+		if err == nil {
+			result.Status = enum.GitSignatureStatusVerified
+		}
+
+		return result, nil
+	}
+
+	return result, nil
+}
+
+// parsePGPSignatureIssuer returns the ID of the key that created the ASCII armored OpenPGP signature.
+func parsePGPSignatureIssuer(signature string) (uint64, error) {
+	block, err := armor.Decode(strings.NewReader(signature))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode armored signature: %w", err)
+	}
+
+	p, err := packet.Read(block.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read signature packet: %w", err)
+	}
+
+	switch sig := p.(type) {
+	case *packet.Signature:
+		if sig.IssuerKeyId == nil {
+			return 0, errors.InvalidArgument("signature has no issuer")
+		}
+		return *sig.IssuerKeyId, nil
+	case *packet.SignatureV3:
+		return sig.IssuerKeyId, nil
+	default:
+		return 0, errors.InvalidArgument("not a signature packet")
+	}
+}
+
+type sshSignature struct {
+	publicKey gossh.PublicKey
+	namespace string
+	reserved  string
+	hashAlg   string
+	signature *gossh.Signature
+}
+
+// parseSSHSignature parses an ASCII armored SSH signature as described in PROTOCOL.sshsig of OpenSSH.
+func parseSSHSignature(signature string) (*sshSignature, error) {
+	block, _ := pem.Decode([]byte(signature))
+	if block == nil || block.Type != "SSH SIGNATURE" {
+		return nil, errors.InvalidArgument("invalid armored ssh signature")
+	}
+
+	if !bytes.HasPrefix(block.Bytes, []byte(sshSignatureMagic)) {
+		return nil, errors.InvalidArgument("invalid ssh signature preamble")
+	}
+
+	var raw struct {
+		Version   uint32
+		PublicKey []byte
+		Namespace string
+		Reserved  string
+		HashAlg   string
+		Signature []byte
+	}
+	if err := gossh.Unmarshal(block.Bytes[len(sshSignatureMagic):], &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ssh signature: %w", err)
+	}
+
+	if raw.Version != sshSignatureVersion {
+		return nil, errors.InvalidArgument("unsupported ssh signature version %d", raw.Version)
+	}
+
+	publicKey, err := gossh.ParsePublicKey(raw.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key of ssh signature: %w", err)
+	}
+
+	sig := &gossh.Signature{}
+	if err := gossh.Unmarshal(raw.Signature, sig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ssh signature blob: %w", err)
+	}
+
+	return &sshSignature{
+		publicKey: publicKey,
+		namespace: raw.Namespace,
+		reserved:  raw.Reserved,
+		hashAlg:   raw.HashAlg,
+		signature: sig,
+	}, nil
+}
+
+// verify verifies that the ssh signature was created for the message in the git namespace.
+func (sig *sshSignature) verify(message []byte) error {
+	if sig.namespace != sshSignatureNamespace {
+		return errors.InvalidArgument("unexpected ssh signature namespace %q", sig.namespace)
+	}
+
+	var hash crypto.Hash
+	switch sig.hashAlg {
+	case "sha256":
+		hash = crypto.SHA256
+	case "sha512":
+		hash = crypto.SHA512
+	default:
+		return errors.InvalidArgument("unsupported ssh signature hash algorithm %q", sig.hashAlg)
+	}
+
+	h := hash.New()
+	_, _ = h.Write(message)
+
+	signed := gossh.Marshal(struct {
+		Namespace string
+		Reserved  string
+		HashAlg   string
+		Hash      []byte
+	}{
+		Namespace: sig.namespace,
+		Reserved:  sig.reserved,
+		HashAlg:   sig.hashAlg,
+		Hash:      h.Sum(nil),
+	})
+
+	return sig.publicKey.Verify(append([]byte(sshSignatureMagic), signed...), sig.signature)
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/git/sha"
+)
+
+// fetchSourceObjects copies the source commit of a pull request opened from a fork into the target repository.
+// It's a no-op for pull requests where the source and the target repository are the same.
+func (s *Service) fetchSourceObjects(
+	ctx context.Context,
+	sourceRepoID int64,
+	targetRepoID int64,
+	sourceSHA string,
+) error {
+	if sourceRepoID == targetRepoID {
+		return nil
+	}
+
+	sourceRepo, err := s.repoGitInfoCache.Get(ctx, sourceRepoID)
+	if err != nil {
+		return fmt.Errorf("failed to get source repo git info: %w", err)
+	}
+
+	targetRepo, err := s.repoGitInfoCache.Get(ctx, targetRepoID)
+	if err != nil {
+		return fmt.Errorf("failed to get target repo git info: %w", err)
+	}
+
+	writeParams, err := createSystemRPCWriteParams(ctx, s.urlProvider, targetRepo.ID, targetRepo.GitUID)
+	if err != nil {
+		return fmt.Errorf("failed to generate rpc write params: %w", err)
+	}
+
+	err = s.git.FetchObjects(ctx, &git.FetchObjectsParams{
+		WriteParams: writeParams,
+		Source:      sourceRepo.GitUID,
+		ObjectSHAs:  []sha.SHA{sha.Must(sourceSHA)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch source commit from the fork: %w", err)
+	}
+
+	return nil
+}
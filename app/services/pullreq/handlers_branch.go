@@ -80,10 +80,13 @@ func (s *Service) triggerPREventOnBranchUpdate(ctx context.Context,
 		log.Ctx(ctx).Warn().Err(err).Msgf("failed to get commit info from git")
 	}
 
-	// TODO: This function is currently executed directly on branch update event.
-	// TODO: But it should be executed after the PR's head ref has been updated.
-	// TODO: This is to make sure the commit exists on the target repository for forked repositories.
 	s.forEveryOpenPR(ctx, event.Payload.RepoID, event.Payload.Ref, func(pr *types.PullReq) error {
+		// commits of pull requests opened from a fork must be pulled into the target repository first.
+		err := s.fetchSourceObjects(ctx, pr.SourceRepoID, pr.TargetRepoID, event.Payload.NewSHA)
+		if err != nil {
+			return err
+		}
+
 		// First check if the merge base has changed
 
 		targetRepo, err := s.repoGitInfoCache.Get(ctx, pr.TargetRepoID)
@@ -41,8 +41,12 @@ func (s *Service) createHeadRefOnCreated(ctx context.Context,
 		return fmt.Errorf("failed to generate rpc write params: %w", err)
 	}
 
-	// TODO: This doesn't work for forked repos (only works when sourceRepo==targetRepo).
-	// This is because commits from the source repository must be first pulled into the target repository.
+	// commits of pull requests opened from a fork must be pulled into the target repository first.
+	err = s.fetchSourceObjects(ctx, event.Payload.SourceRepoID, event.Payload.TargetRepoID, event.Payload.SourceSHA)
+	if err != nil {
+		return err
+	}
+
 	err = s.git.UpdateRef(ctx, git.UpdateRefParams{
 		WriteParams: writeParams,
 		Name:        strconv.Itoa(int(event.Payload.Number)),
@@ -72,8 +76,7 @@ func (s *Service) updateHeadRefOnBranchUpdate(ctx context.Context,
 		return fmt.Errorf("failed to generate rpc write params: %w", err)
 	}
 
-	// TODO: This doesn't work for forked repos (only works when sourceRepo==targetRepo)
-	// This is because commits from the source repository must be first pulled into the target repository.
+	// commits of pull requests opened from a fork are pulled into the target repository on the branch update.
 	err = s.git.UpdateRef(ctx, git.UpdateRefParams{
 		WriteParams: writeParams,
 		Name:        strconv.Itoa(int(event.Payload.Number)),
@@ -103,8 +106,12 @@ func (s *Service) updateHeadRefOnReopen(ctx context.Context,
 		return fmt.Errorf("failed to generate rpc write params: %w", err)
 	}
 
-	// TODO: This doesn't work for forked repos (only works when sourceRepo==targetRepo)
-	// This is because commits from the source repository must be first pulled into the target repository.
+	// commits of pull requests opened from a fork must be pulled into the target repository first.
+	err = s.fetchSourceObjects(ctx, event.Payload.SourceRepoID, event.Payload.TargetRepoID, event.Payload.SourceSHA)
+	if err != nil {
+		return err
+	}
+
 	err = s.git.UpdateRef(ctx, git.UpdateRefParams{
 		WriteParams: writeParams,
 		Name:        strconv.Itoa(int(event.Payload.Number)),
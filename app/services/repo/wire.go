@@ -38,6 +38,7 @@ func ProvideCalculator(
 	config *types.Config,
 	git git.Interface,
 	repoStore store.RepoStore,
+	lfsStore store.LFSObjectStore,
 	scheduler *job.Scheduler,
 	executor *job.Executor,
 ) (*SizeCalculator, error) {
@@ -48,6 +49,7 @@ func ProvideCalculator(
 		numWorkers: config.RepoSize.NumWorkers,
 		git:        git,
 		repoStore:  repoStore,
+		lfsStore:   lfsStore,
 		scheduler:  scheduler,
 	}
 
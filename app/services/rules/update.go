@@ -121,6 +121,9 @@ func (s *Service) Update(ctx context.Context,
 		rule.Description = *in.Description
 	}
 	if in.Pattern != nil {
+		if rule.Type == protection.TypeTag && in.Pattern.Default {
+			return nil, usererror.BadRequest("default branch pattern is not supported for tag rules")
+		}
 		rule.Pattern = in.Pattern.JSON()
 	}
 	if in.Definition != nil {
@@ -14,6 +14,8 @@
 
 package settings
 
+import "github.com/harness/gitness/types/enum"
+
 type Key string
 
 var (
@@ -24,4 +26,7 @@ var (
 	DefaultFileSizeLimit             = int64(1e+8) // 100 MB
 	KeyInstallID                 Key = "install_id"
 	DefaultInstallID                 = string("")
+	// KeyTwoFactorRequirement [enum.TwoFactorRequirement] defines which users are required to use 2FA.
+	KeyTwoFactorRequirement     Key = "two_factor_requirement"
+	DefaultTwoFactorRequirement     = enum.TwoFactorRequirementNone
 )
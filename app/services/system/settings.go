@@ -19,28 +19,32 @@ import (
 	"fmt"
 
 	"github.com/harness/gitness/app/services/settings"
+	"github.com/harness/gitness/types/enum"
 
 	"github.com/gotidy/ptr"
 )
 
 type Settings struct {
-	InstallID *string `json:"install_id" yaml:"install_id"`
+	InstallID            *string                    `json:"install_id" yaml:"install_id"`
+	TwoFactorRequirement *enum.TwoFactorRequirement `json:"two_factor_requirement" yaml:"two_factor_requirement"`
 }
 
 func getDefaultSystemSettings() *Settings {
 	return &Settings{
-		InstallID: ptr.String(settings.DefaultInstallID),
+		InstallID:            ptr.String(settings.DefaultInstallID),
+		TwoFactorRequirement: ptr.Of(settings.DefaultTwoFactorRequirement),
 	}
 }
 
 func getSystemSettingsMappings(s *Settings) []settings.SettingHandler {
 	return []settings.SettingHandler{
 		settings.Mapping(settings.KeyInstallID, s.InstallID),
+		settings.Mapping(settings.KeyTwoFactorRequirement, s.TwoFactorRequirement),
 	}
 }
 
 func getSystemSettingsAsKeyValues(s *Settings) []settings.KeyValue {
-	kvs := make([]settings.KeyValue, 0, 1)
+	kvs := make([]settings.KeyValue, 0, 2)
 
 	if s.InstallID != nil {
 		kvs = append(kvs, settings.KeyValue{
@@ -48,6 +52,12 @@ func getSystemSettingsAsKeyValues(s *Settings) []settings.KeyValue {
 			Value: s.InstallID,
 		})
 	}
+	if s.TwoFactorRequirement != nil {
+		kvs = append(kvs, settings.KeyValue{
+			Key:   settings.KeyTwoFactorRequirement,
+			Value: s.TwoFactorRequirement,
+		})
+	}
 	return kvs
 }
 
@@ -0,0 +1,185 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/app/bootstrap"
+	"github.com/harness/gitness/app/pipeline/triggerer"
+	"github.com/harness/gitness/job"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/drone/go-scm/scm"
+	"github.com/gorhill/cronexpr"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	jobTypeCron        = "gitness:trigger:cron"
+	jobCronCron        = "* * * * *" // Every minute.
+	jobMaxDurationCron = 5 * time.Minute
+)
+
+// NextCronRun returns the first time after the provided time that matches the cron expression
+// evaluated in the provided timezone. An empty timezone stands for UTC.
+func NextCronRun(expr, timezone string, after time.Time) (time.Time, error) {
+	exp, err := cronexpr.Parse(expr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid cron expression: %w", err)
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timezone: %w", err)
+	}
+
+	next := exp.Next(after.In(loc))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("cron expression %q never matches", expr)
+	}
+
+	return next, nil
+}
+
+// Register registers the recurring job that fires the cron triggers.
+func (s *Service) Register(ctx context.Context) error {
+	err := s.executor.Register(jobTypeCron, &cronJob{service: s})
+	if err != nil {
+		return fmt.Errorf("failed to register job handler for cron triggers: %w", err)
+	}
+
+	err = s.scheduler.AddRecurring(ctx, jobTypeCron, jobTypeCron, jobCronCron, jobMaxDurationCron)
+	if err != nil {
+		return fmt.Errorf("failed to schedule cron triggers job: %w", err)
+	}
+
+	return nil
+}
+
+type cronJob struct {
+	service *Service
+}
+
+// Handle fires all cron triggers that are due. The job scheduler runs a recurring job
+// on a single instance at a time, and the next run of each trigger is moved forward
+// with a compare-and-swap so a tick never fires the same trigger twice.
+func (j *cronJob) Handle(ctx context.Context, _ string, _ job.ProgressReporter) (string, error) {
+	now := time.Now()
+
+	triggers, err := j.service.triggerStore.ListCronDue(ctx, now.UnixMilli())
+	if err != nil {
+		return "", fmt.Errorf("failed to list due cron triggers: %w", err)
+	}
+
+	fired := 0
+	for _, t := range triggers {
+		ok, err := j.service.fireCron(ctx, t, now)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).
+				Int64("trigger.id", t.ID).
+				Int64("pipeline.id", t.PipelineID).
+				Msg("failed to fire cron trigger")
+			continue
+		}
+		if ok {
+			fired++
+		}
+	}
+
+	if fired == 0 {
+		return "", nil
+	}
+
+	return fmt.Sprintf("fired %d cron triggers", fired), nil
+}
+
+// fireCron moves the next run of the cron trigger forward and triggers an execution
+// on the head of the trigger branch. Returns false if the trigger was claimed by someone else.
+func (s *Service) fireCron(ctx context.Context, t *types.Trigger, now time.Time) (bool, error) {
+	next, err := NextCronRun(t.Cron, t.Timezone, now)
+	if err != nil {
+		return false, err
+	}
+
+	ok, err := s.triggerStore.UpdateCronNext(ctx, t.ID, t.CronNext, next.UnixMilli())
+	if err != nil {
+		return false, fmt.Errorf("failed to update next cron run: %w", err)
+	}
+	if !ok {
+		return false, nil
+	}
+
+	pipeline, err := s.pipelineStore.Find(ctx, t.PipelineID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find pipeline: %w", err)
+	}
+
+	// Don't fire triggers for disabled pipelines
+	if pipeline.Disabled {
+		return false, nil
+	}
+
+	repo, err := s.repoStore.Find(ctx, pipeline.RepoID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find repo: %w", err)
+	}
+
+	// If the branch is empty, use the default branch specified in the pipeline.
+	// If that is also empty, use the repo default branch.
+	branch := t.Branch
+	if branch == "" {
+		branch = pipeline.DefaultBranch
+		if branch == "" {
+			branch = repo.DefaultBranch
+		}
+	}
+	ref := scm.ExpandRef(branch, "refs/heads")
+
+	commit, err := s.commitSvc.FindRef(ctx, repo, ref)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch commit: %w", err)
+	}
+
+	principal := bootstrap.NewSystemServiceSession().Principal
+	hook := &triggerer.Hook{
+		Trigger:     enum.TriggerCron,
+		Cron:        t.Identifier,
+		TriggeredBy: principal.ID,
+		Sender:      principal.UID,
+		AuthorLogin: commit.Author.Identity.Name,
+		AuthorName:  commit.Author.Identity.Name,
+		AuthorEmail: commit.Author.Identity.Email,
+		Ref:         ref,
+		Message:     commit.Message,
+		Title:       commit.Title,
+		Before:      commit.SHA,
+		After:       commit.SHA,
+		Source:      branch,
+		Target:      branch,
+		Params:      map[string]string{},
+		Timestamp:   commit.Author.When.UnixMilli(),
+	}
+
+	_, err = s.triggerSvc.Trigger(ctx, pipeline, hook)
+	if err != nil {
+		return false, fmt.Errorf("failed to trigger execution: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,234 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/api/controller/service"
+	"github.com/harness/gitness/app/bootstrap"
+	"github.com/harness/gitness/app/pipeline/triggerer"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNextCronRun(t *testing.T) {
+	after := time.Date(2024, time.March, 10, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		expr     string
+		timezone string
+		exp      time.Time
+		expErr   bool
+	}{
+		{
+			name: "hourly in utc",
+			expr: "0 * * * *",
+			exp:  time.Date(2024, time.March, 10, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily in timezone",
+			expr:     "0 9 * * *",
+			timezone: "America/New_York",
+			// 10:30 UTC is 06:30 in New York (EDT starts on 2024-03-10 at 02:00).
+			exp: time.Date(2024, time.March, 10, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily already passed in timezone",
+			expr:     "0 9 * * *",
+			timezone: "Europe/Berlin",
+			exp:      time.Date(2024, time.March, 11, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "invalid expression",
+			expr:   "not a cron",
+			expErr: true,
+		},
+		{
+			name:     "invalid timezone",
+			expr:     "0 * * * *",
+			timezone: "Nowhere/Land",
+			expErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next, err := NextCronRun(test.expr, test.timezone, after)
+			if test.expErr {
+				if err == nil {
+					t.Errorf("expected an error, got next run %s", next)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !next.Equal(test.exp) {
+				t.Errorf("expected next run %s, got %s", test.exp, next)
+			}
+		})
+	}
+}
+
+func TestCronJobHandle(t *testing.T) {
+	ctx := context.Background()
+
+	config := &types.Config{}
+	config.Principal.System.UID = "gitness"
+	serviceCtrl := service.NewController(nil, nil, fakeServicePrincipalStore{})
+	if err := bootstrap.SystemService(ctx, config, serviceCtrl); err != nil {
+		t.Fatalf("failed to setup system service: %v", err)
+	}
+
+	triggers := &fakeCronTriggerStore{
+		triggers: map[int64]*types.Trigger{
+			1: {ID: 1, Identifier: "nightly", PipelineID: 10, Cron: "0 0 * * *", CronNext: 1000},
+			2: {ID: 2, Identifier: "on-branch", PipelineID: 10, Cron: "0 0 * * *", CronNext: 1000, Branch: "dev"},
+			3: {ID: 3, Identifier: "disabled", PipelineID: 20, Cron: "0 0 * * *", CronNext: 1000},
+			4: {ID: 4, Identifier: "claimed", PipelineID: 10, Cron: "0 0 * * *", CronNext: 1000},
+		},
+		claimed: map[int64]bool{4: true},
+	}
+	fired := &fakeTriggerer{}
+
+	s := &Service{
+		triggerStore: triggers,
+		pipelineStore: fakeCronPipelineStore{pipelines: map[int64]*types.Pipeline{
+			10: {ID: 10, RepoID: 100},
+			20: {ID: 20, RepoID: 100, Disabled: true},
+		}},
+		repoStore:  fakeCronRepoStore{repos: map[int64]*types.Repository{100: {ID: 100, DefaultBranch: "main"}}},
+		commitSvc:  fakeCommitService{},
+		triggerSvc: fired,
+	}
+
+	before := time.Now()
+
+	result, err := (&cronJob{service: s}).Handle(ctx, "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "fired 2 cron triggers"; result != want {
+		t.Errorf("expected result %q, got %q", want, result)
+	}
+
+	var refs []string
+	for _, hook := range fired.hooks {
+		if hook.Trigger != enum.TriggerCron {
+			t.Errorf("expected trigger %q, got %q", enum.TriggerCron, hook.Trigger)
+		}
+		if hook.Sender != "gitness" {
+			t.Errorf("expected the system service to trigger the execution, got %q", hook.Sender)
+		}
+		refs = append(refs, hook.Cron+":"+hook.Ref+"@"+hook.After)
+	}
+	expRefs := []string{"nightly:refs/heads/main@sha-refs/heads/main", "on-branch:refs/heads/dev@sha-refs/heads/dev"}
+	if diff := cmp.Diff(expRefs, refs); diff != "" {
+		t.Errorf("fired hooks mismatch (-want +got):\n%s", diff)
+	}
+
+	// Every trigger that was claimed by this run must be moved to the next midnight.
+	for _, id := range []int64{1, 2, 3} {
+		next := triggers.triggers[id].CronNext
+		if next <= before.UnixMilli() {
+			t.Errorf("trigger %d: expected the next run to move past now, got %d", id, next)
+		}
+		if time.UnixMilli(next).UTC().Hour() != 0 {
+			t.Errorf("trigger %d: expected the next run at midnight, got %s", id, time.UnixMilli(next).UTC())
+		}
+	}
+}
+
+type fakeServicePrincipalStore struct {
+	store.PrincipalStore
+}
+
+func (fakeServicePrincipalStore) FindServiceByUID(_ context.Context, uid string) (*types.Service, error) {
+	return &types.Service{ID: 1, UID: uid, Admin: true}, nil
+}
+
+type fakeCronTriggerStore struct {
+	store.TriggerStore
+	triggers map[int64]*types.Trigger
+	claimed  map[int64]bool
+}
+
+func (f *fakeCronTriggerStore) ListCronDue(_ context.Context, now int64) ([]*types.Trigger, error) {
+	var due []*types.Trigger
+	for id := int64(1); id <= int64(len(f.triggers)); id++ {
+		t := f.triggers[id]
+		if t.CronNext > 0 && t.CronNext <= now {
+			c := *t
+			due = append(due, &c)
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeCronTriggerStore) UpdateCronNext(_ context.Context, id int64, prev, next int64) (bool, error) {
+	t := f.triggers[id]
+	if f.claimed[id] || t.CronNext != prev {
+		return false, nil
+	}
+	t.CronNext = next
+	return true, nil
+}
+
+type fakeCronPipelineStore struct {
+	store.PipelineStore
+	pipelines map[int64]*types.Pipeline
+}
+
+func (f fakeCronPipelineStore) Find(_ context.Context, id int64) (*types.Pipeline, error) {
+	return f.pipelines[id], nil
+}
+
+type fakeCronRepoStore struct {
+	store.RepoStore
+	repos map[int64]*types.Repository
+}
+
+func (f fakeCronRepoStore) Find(_ context.Context, id int64) (*types.Repository, error) {
+	return f.repos[id], nil
+}
+
+type fakeCommitService struct{}
+
+func (fakeCommitService) FindRef(_ context.Context, _ *types.Repository, ref string) (*types.Commit, error) {
+	return &types.Commit{SHA: "sha-" + ref}, nil
+}
+
+func (fakeCommitService) FindCommit(_ context.Context, _ *types.Repository, sha string) (*types.Commit, error) {
+	return &types.Commit{SHA: sha}, nil
+}
+
+type fakeTriggerer struct {
+	triggerer.Triggerer
+	hooks []*triggerer.Hook
+}
+
+func (f *fakeTriggerer) Trigger(
+	_ context.Context, _ *types.Pipeline, hook *triggerer.Hook,
+) (*types.Execution, error) {
+	f.hooks = append(f.hooks, hook)
+	return &types.Execution{}, nil
+}
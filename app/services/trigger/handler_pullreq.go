@@ -102,6 +102,28 @@ func (s *Service) handleEventPullReqMerged(
 	return s.trigger(ctx, event.Payload.SourceRepoID, enum.TriggerActionPullReqMerged, hook)
 }
 
+// handleEventPullReqMergeQueueBuilt triggers the pipelines of the target repository
+// for the speculative merge commit of a merge queue entry.
+func (s *Service) handleEventPullReqMergeQueueBuilt(
+	ctx context.Context,
+	event *events.Event[*pullreqevents.MergeQueueBuiltPayload],
+) error {
+	hook := &triggerer.Hook{
+		Trigger:     enum.TriggerHook,
+		Action:      enum.TriggerActionPullReqMergeQueue,
+		TriggeredBy: bootstrap.NewSystemServiceSession().Principal.ID,
+		After:       event.Payload.MergeSHA,
+	}
+	err := s.augmentPullReqInfo(ctx, hook, event.Payload.PullReqID)
+	if err != nil {
+		return fmt.Errorf("could not augment pull request info: %w", err)
+	}
+	// the merge commit is built on top of the target branch or the entries ahead in the queue.
+	hook.Before = event.Payload.BaseSHA
+	hook.Ref = event.Payload.Ref
+	return s.trigger(ctx, event.Payload.TargetRepoID, enum.TriggerActionPullReqMergeQueue, hook)
+}
+
 // augmentPullReqInfo adds in information into the hook pertaining to the pull request
 // by querying the database.
 func (s *Service) augmentPullReqInfo(
@@ -26,6 +26,7 @@ import (
 	"github.com/harness/gitness/app/pipeline/triggerer"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/events"
+	"github.com/harness/gitness/job"
 	"github.com/harness/gitness/stream"
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
@@ -67,6 +68,8 @@ type Service struct {
 	pipelineStore store.PipelineStore
 	triggerSvc    triggerer.Triggerer
 	commitSvc     commit.Service
+	scheduler     *job.Scheduler
+	executor      *job.Executor
 }
 
 func New(
@@ -78,6 +81,8 @@ func New(
 	pipelineStore store.PipelineStore,
 	triggerSvc triggerer.Triggerer,
 	commitSvc commit.Service,
+	scheduler *job.Scheduler,
+	executor *job.Executor,
 	gitReaderFactory *events.ReaderFactory[*gitevents.Reader],
 	pullreqEvReaderFactory *events.ReaderFactory[*pullreqevents.Reader],
 ) (*Service, error) {
@@ -92,6 +97,8 @@ func New(
 		commitSvc:     commitSvc,
 		pipelineStore: pipelineStore,
 		triggerSvc:    triggerSvc,
+		scheduler:     scheduler,
+		executor:      executor,
 	}
 
 	_, err := gitReaderFactory.Launch(ctx, eventsReaderGroupName, config.EventReaderName,
@@ -132,6 +139,7 @@ func New(
 			_ = r.RegisterReopened(service.handleEventPullReqReopened)
 			_ = r.RegisterClosed(service.handleEventPullReqClosed)
 			_ = r.RegisterMerged(service.handleEventPullReqMerged)
+			_ = r.RegisterMergeQueueBuilt(service.handleEventPullReqMergeQueueBuilt)
 
 			return nil
 		})
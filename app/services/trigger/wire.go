@@ -23,6 +23,7 @@ import (
 	"github.com/harness/gitness/app/pipeline/triggerer"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/events"
+	"github.com/harness/gitness/job"
 
 	"github.com/google/wire"
 )
@@ -40,9 +41,11 @@ func ProvideService(
 	repoStore store.RepoStore,
 	pipelineStore store.PipelineStore,
 	triggerSvc triggerer.Triggerer,
+	scheduler *job.Scheduler,
+	executor *job.Executor,
 	gitReaderFactory *events.ReaderFactory[*gitevents.Reader],
 	pullReqEvFactory *events.ReaderFactory[*pullreqevents.Reader],
 ) (*Service, error) {
 	return New(ctx, config, triggerStore, pullReqStore, repoStore, pipelineStore, triggerSvc,
-		commitSvc, gitReaderFactory, pullReqEvFactory)
+		commitSvc, scheduler, executor, gitReaderFactory, pullReqEvFactory)
 }
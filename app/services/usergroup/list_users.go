@@ -16,19 +16,48 @@ package usergroup
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/harness/gitness/app/auth"
 	"github.com/harness/gitness/types"
 )
 
+// ListUsers returns UIDs of the members of the usergroup.
 func (s *searchService) ListUsers(
-	_ context.Context,
+	ctx context.Context,
 	_ *auth.Session,
-	_ *types.UserGroup,
+	userGroup *types.UserGroup,
 ) ([]string, error) {
-	return nil, nil
+	principalIDs, err := s.memberStore.ListPrincipalIDs(ctx, []int64{userGroup.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usergroup members: %w", err)
+	}
+
+	if len(principalIDs) == 0 {
+		return nil, nil
+	}
+
+	principalInfos, err := s.principalInfoCache.Map(ctx, principalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load principal infos of usergroup members: %w", err)
+	}
+
+	uids := make([]string, 0, len(principalIDs))
+	for _, principalID := range principalIDs {
+		if principalInfo, ok := principalInfos[principalID]; ok {
+			uids = append(uids, principalInfo.UID)
+		}
+	}
+
+	return uids, nil
 }
 
-func (s *searchService) ListUserIDsByGroupIDs(_ context.Context, _ []int64) ([]int64, error) {
-	return nil, nil
+// ListUserIDsByGroupIDs returns IDs of principals that are members of any of the usergroups.
+func (s *searchService) ListUserIDsByGroupIDs(ctx context.Context, userGroupIDs []int64) ([]int64, error) {
+	principalIDs, err := s.memberStore.ListPrincipalIDs(ctx, userGroupIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usergroup members: %w", err)
+	}
+
+	return principalIDs, nil
 }
@@ -16,20 +16,72 @@ package usergroup
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
 	"github.com/harness/gitness/types"
 )
 
 var _ Resolver = (*GitnessResolver)(nil)
 
+// GitnessResolver resolves usergroups referenced as "<space path>/<identifier>".
+// A usergroup referenced only by its identifier is looked up in the space
+// in which the directory groups are synced.
 type GitnessResolver struct {
+	defaultSpacePath string
+	spaceCache       refcache.SpaceCache
+	userGroupStore   store.UserGroupStore
+	searchService    SearchService
 }
 
-func NewGitnessResolver() *GitnessResolver {
-	return &GitnessResolver{}
+func NewGitnessResolver(
+	defaultSpacePath string,
+	spaceCache refcache.SpaceCache,
+	userGroupStore store.UserGroupStore,
+	searchService SearchService,
+) *GitnessResolver {
+	return &GitnessResolver{
+		defaultSpacePath: defaultSpacePath,
+		spaceCache:       spaceCache,
+		userGroupStore:   userGroupStore,
+		searchService:    searchService,
+	}
 }
 
-func (s *GitnessResolver) Resolve(context.Context, string) (*types.UserGroup, error) {
-	// todo: implement once usergroup is supported
-	return nil, ErrNotFound
+func (s *GitnessResolver) Resolve(ctx context.Context, scopedID string) (*types.UserGroup, error) {
+	spacePath, identifier := s.defaultSpacePath, scopedID
+	if idx := strings.LastIndex(scopedID, "/"); idx >= 0 {
+		spacePath, identifier = scopedID[:idx], scopedID[idx+1:]
+	}
+
+	if spacePath == "" || identifier == "" {
+		return nil, ErrNotFound
+	}
+
+	space, err := s.spaceCache.Get(ctx, spacePath)
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find space: %w", err)
+	}
+
+	userGroup, err := s.userGroupStore.FindByIdentifier(ctx, space.ID, identifier)
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find usergroup: %w", err)
+	}
+
+	userGroup.Users, err = s.searchService.ListUsers(ctx, nil, userGroup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users of usergroup: %w", err)
+	}
+
+	return userGroup, nil
 }
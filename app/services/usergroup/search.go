@@ -18,20 +18,51 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/types"
 )
 
 type searchService struct {
+	spaceCache         refcache.SpaceCache
+	userGroupStore     store.UserGroupStore
+	memberStore        store.UserGroupMemberStore
+	principalInfoCache store.PrincipalInfoCache
 }
 
-func NewSearchService() SearchService {
-	return &searchService{}
+func NewSearchService(
+	spaceCache refcache.SpaceCache,
+	userGroupStore store.UserGroupStore,
+	memberStore store.UserGroupMemberStore,
+	principalInfoCache store.PrincipalInfoCache,
+) SearchService {
+	return &searchService{
+		spaceCache:         spaceCache,
+		userGroupStore:     userGroupStore,
+		memberStore:        memberStore,
+		principalInfoCache: principalInfoCache,
+	}
 }
 
 func (s *searchService) Search(
-	_ context.Context,
-	_ *types.ListQueryFilter,
-	_ string,
+	ctx context.Context,
+	filter *types.ListQueryFilter,
+	spacePath string,
 ) ([]*types.UserGroupInfo, error) {
-	return nil, fmt.Errorf("not implemented")
+	space, err := s.spaceCache.Get(ctx, spacePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find space: %w", err)
+	}
+
+	userGroups, err := s.userGroupStore.List(ctx, space.ID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usergroups: %w", err)
+	}
+
+	userGroupInfos := make([]*types.UserGroupInfo, len(userGroups))
+	for i, userGroup := range userGroups {
+		userGroupInfos[i] = userGroup.ToUserGroupInfo()
+	}
+
+	return userGroupInfos, nil
 }
@@ -15,6 +15,10 @@
 package usergroup
 
 import (
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/types"
+
 	"github.com/google/wire"
 )
 
@@ -24,10 +28,20 @@ var WireSet = wire.NewSet(
 	ProvideSearchService,
 )
 
-func ProvideUserGroupResolver() Resolver {
-	return NewGitnessResolver()
+func ProvideUserGroupResolver(
+	config *types.Config,
+	spaceCache refcache.SpaceCache,
+	userGroupStore store.UserGroupStore,
+	searchService SearchService,
+) Resolver {
+	return NewGitnessResolver(config.LDAP.GroupSpace, spaceCache, userGroupStore, searchService)
 }
 
-func ProvideSearchService() SearchService {
-	return NewSearchService()
+func ProvideSearchService(
+	spaceCache refcache.SpaceCache,
+	userGroupStore store.UserGroupStore,
+	memberStore store.UserGroupMemberStore,
+	principalInfoCache store.PrincipalInfoCache,
+) SearchService {
+	return NewSearchService(spaceCache, userGroupStore, memberStore, principalInfoCache)
 }
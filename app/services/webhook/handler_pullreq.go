@@ -687,3 +687,57 @@ func (s *Service) handleEventPullReqReviewSubmitted(
 			}, nil
 		})
 }
+
+// PullReqMergeQueuePayload describes the body of the pullreq merge queue trigger.
+type PullReqMergeQueuePayload struct {
+	BaseSegment
+	PullReqSegment
+	PullReqTargetReferenceSegment
+	ReferenceSegment
+	ReferenceDetailsSegment
+}
+
+// handleEventPullReqMergeQueueBuilt handles merge queue built events for pull requests
+// and triggers pullreq merge queue webhooks for the speculative merge commit.
+func (s *Service) handleEventPullReqMergeQueueBuilt(
+	ctx context.Context,
+	event *events.Event[*pullreqevents.MergeQueueBuiltPayload],
+) error {
+	return s.triggerForEventWithPullReq(ctx, enum.WebhookTriggerPullReqMergeQueue,
+		event.ID, event.Payload.PrincipalID, event.Payload.PullReqID,
+		func(principal *types.Principal, pr *types.PullReq, targetRepo, _ *types.Repository) (any, error) {
+			commitInfo, err := s.fetchCommitInfoForEvent(ctx, targetRepo.GitUID, event.Payload.MergeSHA)
+			if err != nil {
+				return nil, err
+			}
+			targetRepoInfo := repositoryInfoFrom(ctx, targetRepo, s.urlProvider)
+
+			return &PullReqMergeQueuePayload{
+				BaseSegment: BaseSegment{
+					Trigger:   enum.WebhookTriggerPullReqMergeQueue,
+					Repo:      targetRepoInfo,
+					Principal: principalInfoFrom(principal.ToPrincipalInfo()),
+				},
+				PullReqSegment: PullReqSegment{
+					PullReq: pullReqInfoFrom(ctx, pr, targetRepo, s.urlProvider),
+				},
+				PullReqTargetReferenceSegment: PullReqTargetReferenceSegment{
+					TargetRef: ReferenceInfo{
+						Name: gitReferenceNamePrefixBranch + pr.TargetBranch,
+						Repo: targetRepoInfo,
+					},
+				},
+				ReferenceSegment: ReferenceSegment{
+					Ref: ReferenceInfo{
+						Name: event.Payload.Ref,
+						Repo: targetRepoInfo,
+					},
+				},
+				ReferenceDetailsSegment: ReferenceDetailsSegment{
+					SHA:        event.Payload.MergeSHA,
+					Commit:     &commitInfo,
+					HeadCommit: &commitInfo,
+				},
+			}, nil
+		})
+}
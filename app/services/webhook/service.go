@@ -207,6 +207,7 @@ func NewService(
 			_ = r.RegisterLabelAssigned(service.handleEventPullReqLabelAssigned)
 			_ = r.RegisterReviewSubmitted(service.handleEventPullReqReviewSubmitted)
 			_ = r.RegisterCommentStatusUpdated(service.handleEventPullReqCommentStatusUpdated)
+			_ = r.RegisterMergeQueueBuilt(service.handleEventPullReqMergeQueueBuilt)
 
 			return nil
 		})
@@ -15,6 +15,7 @@
 package services
 
 import (
+	"github.com/harness/gitness/app/services/automerge"
 	"github.com/harness/gitness/app/services/cleanup"
 	"github.com/harness/gitness/app/services/gitspace"
 	"github.com/harness/gitness/app/services/gitspaceevent"
@@ -22,13 +23,17 @@ import (
 	"github.com/harness/gitness/app/services/infraprovider"
 	"github.com/harness/gitness/app/services/instrument"
 	"github.com/harness/gitness/app/services/keywordsearch"
+	"github.com/harness/gitness/app/services/ldapsync"
+	"github.com/harness/gitness/app/services/mergequeue"
 	"github.com/harness/gitness/app/services/metric"
+	"github.com/harness/gitness/app/services/mirror"
 	"github.com/harness/gitness/app/services/notification"
 	"github.com/harness/gitness/app/services/pullreq"
 	"github.com/harness/gitness/app/services/repo"
 	"github.com/harness/gitness/app/services/trigger"
 	"github.com/harness/gitness/app/services/webhook"
 	"github.com/harness/gitness/job"
+	registrycleanup "github.com/harness/gitness/registry/cleanup"
 
 	"github.com/google/wire"
 )
@@ -41,11 +46,16 @@ type Services struct {
 	Webhook               *webhook.Service
 	PullReq               *pullreq.Service
 	Trigger               *trigger.Service
+	Mirror                *mirror.Service
+	AutoMerge             *automerge.Service
+	MergeQueue            *mergequeue.Service
+	LDAPSync              *ldapsync.Service
 	JobScheduler          *job.Scheduler
 	MetricCollector       *metric.Collector
 	RepoSizeCalculator    *repo.SizeCalculator
 	Repo                  *repo.Service
 	Cleanup               *cleanup.Service
+	RegistryCleanup       *registrycleanup.Service
 	Notification          *notification.Service
 	Keywordsearch         *keywordsearch.Service
 	GitspaceService       *GitspaceServices
@@ -79,11 +89,16 @@ func ProvideServices(
 	webhooksSvc *webhook.Service,
 	pullReqSvc *pullreq.Service,
 	triggerSvc *trigger.Service,
+	mirrorSvc *mirror.Service,
+	autoMergeSvc *automerge.Service,
+	mergeQueueSvc *mergequeue.Service,
+	ldapSyncSvc *ldapsync.Service,
 	jobScheduler *job.Scheduler,
 	metricCollector *metric.Collector,
 	repoSizeCalculator *repo.SizeCalculator,
 	repo *repo.Service,
 	cleanupSvc *cleanup.Service,
+	registryCleanupSvc *registrycleanup.Service,
 	notificationSvc *notification.Service,
 	keywordsearchSvc *keywordsearch.Service,
 	gitspaceSvc *GitspaceServices,
@@ -95,11 +110,16 @@ func ProvideServices(
 		Webhook:               webhooksSvc,
 		PullReq:               pullReqSvc,
 		Trigger:               triggerSvc,
+		Mirror:                mirrorSvc,
+		AutoMerge:             autoMergeSvc,
+		MergeQueue:            mergeQueueSvc,
+		LDAPSync:              ldapSyncSvc,
 		JobScheduler:          jobScheduler,
 		MetricCollector:       metricCollector,
 		RepoSizeCalculator:    repoSizeCalculator,
 		Repo:                  repo,
 		Cleanup:               cleanupSvc,
+		RegistryCleanup:       registryCleanupSvc,
 		Notification:          notificationSvc,
 		Keywordsearch:         keywordsearchSvc,
 		GitspaceService:       gitspaceSvc,
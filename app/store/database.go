@@ -290,6 +290,9 @@ type (
 
 		// ListSizeInfos returns a list of all active repo sizes.
 		ListSizeInfos(ctx context.Context) ([]*types.RepositorySizeInfo, error)
+
+		// ListForks returns all forks of a repo, including the deleted ones.
+		ListForks(ctx context.Context, repoID int64) ([]*types.Repository, error)
 	}
 
 	// SettingsStore defines the settings storage.
@@ -488,6 +491,52 @@ type (
 		Create(ctx context.Context, v *types.PullReqReview) error
 	}
 
+	// PullReqAutoMergeStore defines the storage of the pull request auto-merge settings.
+	PullReqAutoMergeStore interface {
+		// Find returns the auto-merge settings of the pull request or an error if auto-merge isn't enabled.
+		Find(ctx context.Context, pullreqID int64) (*types.PullReqAutoMerge, error)
+
+		// Upsert enables auto-merge of the pull request or replaces its existing auto-merge settings.
+		Upsert(ctx context.Context, autoMerge *types.PullReqAutoMerge) error
+
+		// Delete disables auto-merge of the pull request.
+		Delete(ctx context.Context, pullreqID int64) error
+
+		// ListByRepo returns auto-merge settings of all pull requests targeting the repository.
+		ListByRepo(ctx context.Context, repoID int64) ([]*types.PullReqAutoMerge, error)
+	}
+
+	// MergeQueueEntryStore defines the storage of the merge queue entries.
+	MergeQueueEntryStore interface {
+		// Find returns the merge queue entry by id.
+		Find(ctx context.Context, id int64) (*types.MergeQueueEntry, error)
+
+		// FindByPullReq returns the merge queue entry of the pull request.
+		FindByPullReq(ctx context.Context, pullreqID int64) (*types.MergeQueueEntry, error)
+
+		// Create adds a new entry to the merge queue.
+		Create(ctx context.Context, e *types.MergeQueueEntry) error
+
+		// Update updates the state and the speculative merge commit of the merge queue entry.
+		Update(ctx context.Context, e *types.MergeQueueEntry) error
+
+		// UpdateOptLock updates the merge queue entry using the optimistic locking mechanism.
+		UpdateOptLock(ctx context.Context, e *types.MergeQueueEntry,
+			mutateFn func(e *types.MergeQueueEntry) error) (*types.MergeQueueEntry, error)
+
+		// Delete removes the entry from the merge queue.
+		Delete(ctx context.Context, id int64) error
+
+		// List returns the entries of the merge queue of a branch in the order they were added to the queue.
+		List(ctx context.Context, repoID int64, branch string) ([]*types.MergeQueueEntry, error)
+
+		// ListByRepo returns the entries of all merge queues of a repository.
+		ListByRepo(ctx context.Context, repoID int64) ([]*types.MergeQueueEntry, error)
+
+		// ListBranches returns all branches with a non-empty merge queue.
+		ListBranches(ctx context.Context) ([]types.MergeQueueBranch, error)
+	}
+
 	PullReqReviewerStore interface {
 		// Find returns the pull request reviewer or an error if it doesn't exist.
 		Find(ctx context.Context, prID, principalID int64) (*types.PullReqReviewer, error)
@@ -917,9 +966,15 @@ type (
 
 		// Create creates a new stage.
 		Create(ctx context.Context, stage *types.Stage) error
+
+		// Offer records that the unassigned stage was offered to the remote runner until the given time.
+		Offer(ctx context.Context, stageID, runnerID, expires int64) error
 	}
 
 	StepStore interface {
+		// Find returns a step from the datastore by ID.
+		Find(ctx context.Context, id int64) (*types.Step, error)
+
 		// FindByNumber returns a step from the datastore by number.
 		FindByNumber(ctx context.Context, stageID int64, stepNum int) (*types.Step, error)
 
@@ -1031,6 +1086,13 @@ type (
 		// ListAllEnabled lists all enabled triggers for a given repo without pagination.
 		// It's used only internally to trigger builds.
 		ListAllEnabled(ctx context.Context, repoID int64) ([]*types.Trigger, error)
+
+		// ListCronDue lists all enabled cron triggers that are due to fire at the provided time.
+		ListCronDue(ctx context.Context, now int64) ([]*types.Trigger, error)
+
+		// UpdateCronNext moves the next cron run of a trigger from prev to next.
+		// Returns false if the next run was already moved by someone else.
+		UpdateCronNext(ctx context.Context, id int64, prev, next int64) (bool, error)
 	}
 
 	PluginStore interface {
@@ -1074,6 +1136,9 @@ type (
 		// FindByIdentifier returns a types.UserGroup given a space ID and identifier.
 		FindByIdentifier(ctx context.Context, spaceID int64, identifier string) (*types.UserGroup, error)
 
+		// List returns the usergroups of a space. If the filter is nil, all usergroups of the space are returned.
+		List(ctx context.Context, spaceID int64, filter *types.ListQueryFilter) ([]*types.UserGroup, error)
+
 		// Create creates a new usergroup
 		Create(ctx context.Context, spaceID int64, userGroup *types.UserGroup) error
 
@@ -1082,6 +1147,91 @@ type (
 			spaceID int64,
 			userGroup *types.UserGroup,
 		) error
+
+		// Update updates the name and the description of a usergroup.
+		Update(ctx context.Context, userGroup *types.UserGroup) error
+	}
+
+	UserGroupMemberStore interface {
+		// Add adds a principal to a usergroup. It's a no-op if the principal is already a member.
+		Add(ctx context.Context, member *types.UserGroupMember) error
+
+		// Remove removes a principal from a usergroup.
+		Remove(ctx context.Context, userGroupID, principalID int64) error
+
+		// ListByUserGroup returns all memberships of a usergroup.
+		ListByUserGroup(ctx context.Context, userGroupID int64) ([]*types.UserGroupMember, error)
+
+		// ListByPrincipal returns all usergroup memberships of a principal.
+		ListByPrincipal(ctx context.Context, principalID int64) ([]*types.UserGroupMember, error)
+
+		// ListPrincipalIDs returns IDs of all principals that are members of any of the provided usergroups.
+		ListPrincipalIDs(ctx context.Context, userGroupIDs []int64) ([]int64, error)
+	}
+
+	OIDCIdentityStore interface {
+		// Find returns the identity given the issuer and the subject.
+		Find(ctx context.Context, issuer, subject string) (*types.OIDCIdentity, error)
+
+		// Create links a new identity to a principal.
+		Create(ctx context.Context, identity *types.OIDCIdentity) error
+
+		// UpdateLastLogin updates the time of the last login with the identity.
+		UpdateLastLogin(ctx context.Context, issuer, subject string, lastLogin int64) error
+	}
+
+	LDAPIdentityStore interface {
+		// Find returns the identity given the normalized DN of the directory user.
+		Find(ctx context.Context, dn string) (*types.LDAPIdentity, error)
+
+		// FindByPrincipalID returns the identity linked to the principal.
+		FindByPrincipalID(ctx context.Context, principalID int64) (*types.LDAPIdentity, error)
+
+		// List returns all identities.
+		List(ctx context.Context) ([]*types.LDAPIdentity, error)
+
+		// Create links a new identity to a principal.
+		Create(ctx context.Context, identity *types.LDAPIdentity) error
+
+		// UpdateLastLogin updates the time of the last login with the identity.
+		UpdateLastLogin(ctx context.Context, dn string, lastLogin int64) error
+
+		// DeleteByPrincipalID removes the identity linked to the principal.
+		DeleteByPrincipalID(ctx context.Context, principalID int64) error
+	}
+
+	UserTOTPStore interface {
+		// Find returns the TOTP second factor of the user.
+		Find(ctx context.Context, principalID int64) (*types.UserTOTP, error)
+
+		// Create creates the TOTP second factor of the user.
+		Create(ctx context.Context, totp *types.UserTOTP) error
+
+		// Update updates the TOTP second factor of the user.
+		// It returns store.ErrVersionConflict if it was updated concurrently.
+		Update(ctx context.Context, totp *types.UserTOTP) error
+
+		// UpdateOptLock updates the TOTP second factor of the user using the optimistic locking mechanism.
+		UpdateOptLock(
+			ctx context.Context,
+			totp *types.UserTOTP,
+			mutateFn func(totp *types.UserTOTP) error,
+		) (*types.UserTOTP, error)
+
+		// Delete deletes the TOTP second factor of the user.
+		Delete(ctx context.Context, principalID int64) error
+	}
+
+	UserMailLimitStore interface {
+		// Increment increments the number of mails of the type sent to the user in the current window
+		// and returns the new count. A new window is started if the current one began before windowStart.
+		Increment(
+			ctx context.Context,
+			principalID int64,
+			mailType enum.UserMailType,
+			now int64,
+			windowStart int64,
+		) (int, error)
 	}
 
 	PublicKeyStore interface {
@@ -1291,4 +1441,100 @@ type (
 			end int64,
 		) ([]types.UsageMetric, error)
 	}
+
+	LFSObjectStore interface {
+		// Find finds an LFS object of a repository by its OID.
+		Find(ctx context.Context, repoID int64, oid string) (*types.LFSObject, error)
+
+		// FindMany finds the LFS objects of a repository with the provided OIDs.
+		FindMany(ctx context.Context, repoID int64, oids []string) ([]*types.LFSObject, error)
+
+		// Create creates a new LFS object.
+		Create(ctx context.Context, obj *types.LFSObject) error
+
+		// ListOIDs returns the OIDs of all LFS objects of a repository.
+		ListOIDs(ctx context.Context, repoID int64) ([]string, error)
+
+		// GetSizeInKiB returns the total size of all LFS objects of a repository in KiB.
+		GetSizeInKiB(ctx context.Context, repoID int64) (int64, error)
+	}
+
+	MirrorStore interface {
+		// Find finds the mirror by id.
+		Find(ctx context.Context, id int64) (*types.Mirror, error)
+
+		// FindByIdentifier finds the mirror of a repository by its identifier.
+		FindByIdentifier(ctx context.Context, repoID int64, identifier string) (*types.Mirror, error)
+
+		// Create creates a new mirror.
+		Create(ctx context.Context, mirror *types.Mirror) error
+
+		// Update updates an existing mirror.
+		Update(ctx context.Context, mirror *types.Mirror) error
+
+		// UpdateOptLock updates the mirror using the optimistic locking mechanism.
+		UpdateOptLock(
+			ctx context.Context,
+			mirror *types.Mirror,
+			mutateFn func(mirror *types.Mirror) error,
+		) (*types.Mirror, error)
+
+		// Delete deletes the mirror with the given id.
+		Delete(ctx context.Context, id int64) error
+
+		// List lists all mirrors of a repository, optionally filtered by direction.
+		List(ctx context.Context, repoID int64, direction enum.MirrorDirection) ([]*types.Mirror, error)
+
+		// ListPullDue lists all enabled pull mirrors that are due to be synchronized at the provided time.
+		ListPullDue(ctx context.Context, now int64) ([]*types.Mirror, error)
+
+		// UpdateNextSync moves the next synchronization of a mirror from prev to next.
+		// Returns false if the next synchronization was already moved by someone else.
+		UpdateNextSync(ctx context.Context, id int64, prev, next int64) (bool, error)
+	}
+
+	RunnerStore interface {
+		// Find returns the runner with the given id.
+		Find(ctx context.Context, id int64) (*types.Runner, error)
+
+		// FindByIdentifier returns the runner with the given identifier.
+		FindByIdentifier(ctx context.Context, identifier string) (*types.Runner, error)
+
+		// FindByTokenHash returns the runner with the given token hash.
+		FindByTokenHash(ctx context.Context, tokenHash string) (*types.Runner, error)
+
+		// List returns all runners ordered by identifier.
+		List(ctx context.Context) ([]*types.Runner, error)
+
+		// Create creates a new runner that authenticates with the token of the given hash.
+		Create(ctx context.Context, runner *types.Runner, tokenHash string) error
+
+		// UpdateHeartbeat stores the platform and the labels the runner reported and the time it was last seen.
+		UpdateHeartbeat(ctx context.Context, id int64, heartbeat *types.RunnerHeartbeat) error
+
+		// Delete deletes the runner with the given id.
+		Delete(ctx context.Context, id int64) error
+	}
+
+	UserChatIdentityStore interface {
+		// Find returns the chat identity the user linked for the provider.
+		Find(ctx context.Context, principalID int64, provider enum.ChatProvider) (*types.UserChatIdentity, error)
+
+		// List returns all chat identities linked by the user.
+		List(ctx context.Context, principalID int64) ([]*types.UserChatIdentity, error)
+
+		// ListExternalIDs returns the verified external IDs the given principals linked for the chat provider,
+		// keyed by principal ID. Principals without a verified identity are omitted.
+		ListExternalIDs(
+			ctx context.Context,
+			provider enum.ChatProvider,
+			principalIDs []int64,
+		) (map[int64]string, error)
+
+		// Upsert links the chat identity, replacing the identity the user linked before for the provider.
+		Upsert(ctx context.Context, identity *types.UserChatIdentity) error
+
+		// Delete unlinks the chat identity of the user for the provider.
+		Delete(ctx context.Context, principalID int64, provider enum.ChatProvider) error
+	}
 )
@@ -0,0 +1,353 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/audit"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ audit.Store = (*AuditEventStore)(nil)
+
+// NewAuditEventStore returns a new AuditEventStore.
+func NewAuditEventStore(db *sqlx.DB, spacePathStore store.SpacePathStore) *AuditEventStore {
+	return &AuditEventStore{
+		db:             db,
+		spacePathStore: spacePathStore,
+	}
+}
+
+// AuditEventStore implements an audit.Store backed by a relational database.
+type AuditEventStore struct {
+	db             *sqlx.DB
+	spacePathStore store.SpacePathStore
+}
+
+type auditEvent struct {
+	ID        string `db:"audit_event_id"`
+	Timestamp int64  `db:"audit_event_timestamp"`
+	Action    string `db:"audit_event_action"`
+
+	PrincipalID          int64  `db:"audit_event_principal_id"`
+	PrincipalUID         string `db:"audit_event_principal_uid"`
+	PrincipalType        string `db:"audit_event_principal_type"`
+	PrincipalDisplayName string `db:"audit_event_principal_display_name"`
+	PrincipalEmail       string `db:"audit_event_principal_email"`
+
+	SpacePath string   `db:"audit_event_space_path"`
+	SpaceID   null.Int `db:"audit_event_space_id"`
+
+	ResourceType       string          `db:"audit_event_resource_type"`
+	ResourceIdentifier string          `db:"audit_event_resource_identifier"`
+	ResourceData       json.RawMessage `db:"audit_event_resource_data"`
+
+	OldObject json.RawMessage `db:"audit_event_old_object"`
+	NewObject json.RawMessage `db:"audit_event_new_object"`
+
+	ClientIP      string          `db:"audit_event_client_ip"`
+	RequestMethod string          `db:"audit_event_request_method"`
+	Data          json.RawMessage `db:"audit_event_data"`
+}
+
+const (
+	auditEventColumns = `
+		 audit_event_id
+		,audit_event_timestamp
+		,audit_event_action
+		,audit_event_principal_id
+		,audit_event_principal_uid
+		,audit_event_principal_type
+		,audit_event_principal_display_name
+		,audit_event_principal_email
+		,audit_event_space_path
+		,audit_event_space_id
+		,audit_event_resource_type
+		,audit_event_resource_identifier
+		,audit_event_resource_data
+		,audit_event_old_object
+		,audit_event_new_object
+		,audit_event_client_ip
+		,audit_event_request_method
+		,audit_event_data`
+)
+
+// Create saves the audit event.
+func (s *AuditEventStore) Create(ctx context.Context, event *audit.Event) error {
+	const sqlQuery = `
+		INSERT INTO audit_events (` + auditEventColumns + `
+		) values (
+			 :audit_event_id
+			,:audit_event_timestamp
+			,:audit_event_action
+			,:audit_event_principal_id
+			,:audit_event_principal_uid
+			,:audit_event_principal_type
+			,:audit_event_principal_display_name
+			,:audit_event_principal_email
+			,:audit_event_space_path
+			,:audit_event_space_id
+			,:audit_event_resource_type
+			,:audit_event_resource_identifier
+			,:audit_event_resource_data
+			,:audit_event_old_object
+			,:audit_event_new_object
+			,:audit_event_client_ip
+			,:audit_event_request_method
+			,:audit_event_data
+		)`
+
+	dbEvent, err := mapToInternalAuditEvent(event)
+	if err != nil {
+		return fmt.Errorf("failed to map audit event: %w", err)
+	}
+
+	// the path of a space changes when it's renamed or moved, so the events are found by the space id.
+	spacePath, err := s.spacePathStore.FindByPath(ctx, event.SpacePath)
+	if err != nil && !errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return fmt.Errorf("failed to find space of audit event: %w", err)
+	}
+	if err == nil {
+		dbEvent.SpaceID = null.IntFrom(spacePath.SpaceID)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, dbEvent)
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to bind audit event object")
+	}
+
+	if _, err = db.ExecContext(ctx, query, arg...); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Insert audit event query failed")
+	}
+
+	return nil
+}
+
+// Count returns the number of audit events matching the provided filter.
+func (s *AuditEventStore) Count(ctx context.Context, filter *audit.EventFilter) (int64, error) {
+	stmt := database.Builder.
+		Select("count(*)").
+		From("audit_events")
+
+	stmt = s.applyFilter(stmt, filter)
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	var count int64
+	if err = db.QueryRowContext(ctx, sql, args...).Scan(&count); err != nil {
+		return 0, database.ProcessSQLErrorf(ctx, err, "Failed to count audit events")
+	}
+
+	return count, nil
+}
+
+// List returns the audit events matching the provided filter, newest first.
+func (s *AuditEventStore) List(ctx context.Context, filter *audit.EventFilter) ([]*audit.Event, error) {
+	stmt := database.Builder.
+		Select(auditEventColumns).
+		From("audit_events")
+
+	stmt = s.applyFilter(stmt, filter)
+
+	stmt = stmt.Limit(database.Limit(filter.Size))
+	stmt = stmt.Offset(database.Offset(filter.Page, filter.Size))
+	stmt = stmt.OrderBy("audit_event_timestamp DESC", "audit_event_id DESC")
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := make([]*auditEvent, 0)
+	if err = db.SelectContext(ctx, &dst, sql, args...); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to list audit events")
+	}
+
+	return mapToAuditEvents(dst)
+}
+
+// DeleteOld removes all audit events that are older than the provided time.
+func (s *AuditEventStore) DeleteOld(ctx context.Context, olderThan time.Time) (int64, error) {
+	stmt := database.Builder.
+		Delete("audit_events").
+		Where("audit_event_timestamp < ?", olderThan.UnixMilli())
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert delete audit events query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	result, err := db.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, database.ProcessSQLErrorf(ctx, err, "failed to execute delete audit events query")
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, database.ProcessSQLErrorf(ctx, err, "failed to get number of deleted audit events")
+	}
+
+	return n, nil
+}
+
+func (*AuditEventStore) applyFilter(
+	stmt squirrel.SelectBuilder,
+	filter *audit.EventFilter,
+) squirrel.SelectBuilder {
+	if len(filter.SpaceIDs) > 0 {
+		stmt = stmt.Where(squirrel.Eq{"audit_event_space_id": filter.SpaceIDs})
+	}
+
+	if len(filter.ResourceTypes) > 0 {
+		stmt = stmt.Where(squirrel.Eq{"audit_event_resource_type": filter.ResourceTypes})
+	}
+
+	if len(filter.Actions) > 0 {
+		stmt = stmt.Where(squirrel.Eq{"audit_event_action": filter.Actions})
+	}
+
+	if len(filter.PrincipalIDs) > 0 {
+		stmt = stmt.Where(squirrel.Eq{"audit_event_principal_id": filter.PrincipalIDs})
+	}
+
+	if filter.From > 0 {
+		stmt = stmt.Where("audit_event_timestamp >= ?", filter.From)
+	}
+
+	if filter.To > 0 {
+		stmt = stmt.Where("audit_event_timestamp <= ?", filter.To)
+	}
+
+	return stmt
+}
+
+func mapToInternalAuditEvent(in *audit.Event) (*auditEvent, error) {
+	resourceData, err := json.Marshal(in.Resource.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource data: %w", err)
+	}
+
+	oldObject, err := json.Marshal(in.DiffObject.OldObject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal old object: %w", err)
+	}
+
+	newObject, err := json.Marshal(in.DiffObject.NewObject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal new object: %w", err)
+	}
+
+	data, err := json.Marshal(in.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	return &auditEvent{
+		ID:                   in.ID,
+		Timestamp:            in.Timestamp,
+		Action:               string(in.Action),
+		PrincipalID:          in.User.ID,
+		PrincipalUID:         in.User.UID,
+		PrincipalType:        string(in.User.Type),
+		PrincipalDisplayName: in.User.DisplayName,
+		PrincipalEmail:       in.User.Email,
+		SpacePath:            in.SpacePath,
+		ResourceType:         string(in.Resource.Type),
+		ResourceIdentifier:   in.Resource.Identifier,
+		ResourceData:         resourceData,
+		OldObject:            oldObject,
+		NewObject:            newObject,
+		ClientIP:             in.ClientIP,
+		RequestMethod:        in.RequestMethod,
+		Data:                 data,
+	}, nil
+}
+
+func mapToAuditEvent(in *auditEvent) (*audit.Event, error) {
+	event := &audit.Event{
+		ID:        in.ID,
+		Timestamp: in.Timestamp,
+		Action:    audit.Action(in.Action),
+		User: types.Principal{
+			ID:          in.PrincipalID,
+			UID:         in.PrincipalUID,
+			Type:        enum.PrincipalType(in.PrincipalType),
+			DisplayName: in.PrincipalDisplayName,
+			Email:       in.PrincipalEmail,
+		},
+		SpacePath: in.SpacePath,
+		Resource: audit.Resource{
+			Type:       audit.ResourceType(in.ResourceType),
+			Identifier: in.ResourceIdentifier,
+		},
+		ClientIP:      in.ClientIP,
+		RequestMethod: in.RequestMethod,
+	}
+
+	if err := json.Unmarshal(in.ResourceData, &event.Resource.Data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resource data: %w", err)
+	}
+
+	if err := json.Unmarshal(in.OldObject, &event.DiffObject.OldObject); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal old object: %w", err)
+	}
+
+	if err := json.Unmarshal(in.NewObject, &event.DiffObject.NewObject); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal new object: %w", err)
+	}
+
+	if err := json.Unmarshal(in.Data, &event.Data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event data: %w", err)
+	}
+
+	return event, nil
+}
+
+func mapToAuditEvents(events []*auditEvent) ([]*audit.Event, error) {
+	res := make([]*audit.Event, len(events))
+	for i := range events {
+		event, err := mapToAuditEvent(events[i])
+		if err != nil {
+			return nil, err
+		}
+		res[i] = event
+	}
+	return res, nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/app/store/database"
+	"github.com/harness/gitness/audit"
+	"github.com/harness/gitness/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditEventStore(t *testing.T) {
+	db, teardown := setupDB(t)
+	defer teardown()
+
+	ctx := context.Background()
+	principalStore, spaceStore, spacePathStore, _ := setupStores(t, db)
+	auditStore := database.NewAuditEventStore(db, spacePathStore)
+
+	createUser(ctx, t, principalStore)
+	createAuditTestSpace(ctx, t, spaceStore, spacePathStore, 1, 0, "acme")
+	createAuditTestSpace(ctx, t, spaceStore, spacePathStore, 2, 1, "team")
+
+	now := time.Now()
+	events := []*audit.Event{
+		{
+			ID:        "1",
+			Timestamp: now.Add(-48 * time.Hour).UnixMilli(),
+			Action:    audit.ActionCreated,
+			User:      types.Principal{ID: 1, UID: "admin"},
+			SpacePath: "acme",
+			Resource:  audit.NewResource(audit.ResourceTypeRepository, "repo1"),
+		},
+		{
+			ID:        "2",
+			Timestamp: now.Add(-time.Hour).UnixMilli(),
+			Action:    audit.ActionBypassed,
+			User:      types.Principal{ID: 2, UID: "dev"},
+			SpacePath: "acme/team",
+			Resource: audit.NewResource(audit.ResourceTypeRepository, "repo2",
+				audit.BypassAction, audit.BypassActionMerged),
+			DiffObject: audit.DiffObject{NewObject: map[string]any{"number": 7}},
+			ClientIP:   "10.0.0.1",
+		},
+		{
+			ID:        "3",
+			Timestamp: now.UnixMilli(),
+			Action:    audit.ActionUpdated,
+			User:      types.Principal{ID: 1, UID: "admin"},
+			SpacePath: "acme-other",
+			Resource:  audit.NewResource(audit.ResourceTypeBranchRule, "rule1"),
+		},
+	}
+	for _, event := range events {
+		require.NoError(t, auditStore.Create(ctx, event))
+	}
+
+	list := func(filter audit.EventFilter) []string {
+		filter.Size = 100
+		result, err := auditStore.List(ctx, &filter)
+		require.NoError(t, err)
+
+		count, err := auditStore.Count(ctx, &filter)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(result)), count)
+
+		ids := make([]string, len(result))
+		for i, event := range result {
+			ids[i] = event.ID
+		}
+		return ids
+	}
+
+	require.Equal(t, []string{"3", "2", "1"}, list(audit.EventFilter{}))
+	require.Equal(t, []string{"2", "1"}, list(audit.EventFilter{SpaceIDs: []int64{1, 2}}))
+	require.Equal(t, []string{"2"}, list(audit.EventFilter{SpaceIDs: []int64{2}}))
+	require.Equal(t, []string{"3", "1"}, list(audit.EventFilter{PrincipalIDs: []int64{1}}))
+	require.Equal(t, []string{"2"}, list(audit.EventFilter{Actions: []audit.Action{audit.ActionBypassed}}))
+	require.Equal(t, []string{"3"}, list(audit.EventFilter{
+		ResourceTypes: []audit.ResourceType{audit.ResourceTypeBranchRule},
+	}))
+	require.Equal(t, []string{"2"}, list(audit.EventFilter{
+		From: now.Add(-2 * time.Hour).UnixMilli(),
+		To:   now.Add(-time.Minute).UnixMilli(),
+	}))
+
+	result, err := auditStore.List(ctx, &audit.EventFilter{Actions: []audit.Action{audit.ActionBypassed}})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	require.Equal(t, "dev", result[0].User.UID)
+	require.Equal(t, "10.0.0.1", result[0].ClientIP)
+	require.Equal(t, audit.BypassActionMerged, result[0].Resource.Data[audit.BypassAction])
+	require.Equal(t, map[string]any{"number": float64(7)}, result[0].DiffObject.NewObject)
+	require.Nil(t, result[0].DiffObject.OldObject)
+
+	n, err := auditStore.DeleteOld(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	require.Equal(t, []string{"3", "2"}, list(audit.EventFilter{}))
+}
+
+func TestAuditEventStore_SpaceRenamed(t *testing.T) {
+	db, teardown := setupDB(t)
+	defer teardown()
+
+	ctx := context.Background()
+	principalStore, spaceStore, spacePathStore, _ := setupStores(t, db)
+	auditStore := database.NewAuditEventStore(db, spacePathStore)
+
+	createUser(ctx, t, principalStore)
+	createAuditTestSpace(ctx, t, spaceStore, spacePathStore, 1, 0, "acme")
+	createAuditTestSpace(ctx, t, spaceStore, spacePathStore, 2, 1, "team")
+
+	create := func(id string, spacePath string) {
+		require.NoError(t, auditStore.Create(ctx, &audit.Event{
+			ID:        id,
+			Timestamp: time.Now().UnixMilli(),
+			Action:    audit.ActionUpdated,
+			User:      types.Principal{ID: userID, UID: "admin"},
+			SpacePath: spacePath,
+			Resource:  audit.NewResource(audit.ResourceTypeRepository, "repo1"),
+		}))
+	}
+
+	list := func(spaceID int64) []string {
+		result, err := auditStore.List(ctx, &audit.EventFilter{Size: 100, SpaceIDs: []int64{spaceID}})
+		require.NoError(t, err)
+
+		ids := make([]string, len(result))
+		for i, event := range result {
+			ids[i] = event.ID
+		}
+		return ids
+	}
+
+	create("1", "acme/team")
+
+	// the space is renamed and a new space takes over its old path.
+	require.NoError(t, spacePathStore.DeletePrimarySegment(ctx, 2))
+	require.NoError(t, spacePathStore.InsertSegment(ctx, &types.SpacePathSegment{
+		Identifier: "squad", IsPrimary: true, SpaceID: 2, ParentID: 1, CreatedBy: userID,
+	}))
+	createAuditTestSpace(ctx, t, spaceStore, spacePathStore, 3, 1, "team")
+
+	create("2", "acme/squad")
+	create("3", "acme/team")
+
+	require.ElementsMatch(t, []string{"1", "2"}, list(2))
+	require.Equal(t, []string{"3"}, list(3))
+}
+
+func createAuditTestSpace(
+	ctx context.Context,
+	t *testing.T,
+	spaceStore *database.SpaceStore,
+	spacePathStore store.SpacePathStore,
+	spaceID int64,
+	parentID int64,
+	identifier string,
+) {
+	t.Helper()
+
+	space := types.Space{ID: spaceID, Identifier: identifier, CreatedBy: userID, ParentID: parentID}
+	require.NoError(t, spaceStore.Create(ctx, &space))
+
+	require.NoError(t, spacePathStore.InsertSegment(ctx, &types.SpacePathSegment{
+		Identifier: identifier, IsPrimary: true, SpaceID: spaceID, ParentID: parentID, CreatedBy: userID,
+	}))
+}
@@ -0,0 +1,186 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ store.LDAPIdentityStore = (*LDAPIdentityStore)(nil)
+
+func NewLDAPIdentityStore(db *sqlx.DB) *LDAPIdentityStore {
+	return &LDAPIdentityStore{
+		db: db,
+	}
+}
+
+// LDAPIdentityStore implements store.LDAPIdentityStore backed by a relational database.
+type LDAPIdentityStore struct {
+	db *sqlx.DB
+}
+
+type ldapIdentity struct {
+	DN          string `db:"ldap_identity_dn"`
+	PrincipalID int64  `db:"ldap_identity_principal_id"`
+	Created     int64  `db:"ldap_identity_created"`
+	LastLogin   int64  `db:"ldap_identity_last_login"`
+}
+
+const (
+	ldapIdentityColumns = `
+		 ldap_identity_dn
+		,ldap_identity_principal_id
+		,ldap_identity_created
+		,ldap_identity_last_login`
+
+	ldapIdentitySelectBase = `
+	SELECT` + ldapIdentityColumns + `
+	FROM ldap_identities`
+)
+
+// Find returns the identity given the normalized DN of the directory user.
+func (s *LDAPIdentityStore) Find(ctx context.Context, dn string) (*types.LDAPIdentity, error) {
+	const sqlQuery = ldapIdentitySelectBase + `
+	WHERE ldap_identity_dn = $1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &ldapIdentity{}
+	if err := db.GetContext(ctx, dst, sqlQuery, dn); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to find ldap identity")
+	}
+
+	return mapLDAPIdentity(dst), nil
+}
+
+// FindByPrincipalID returns the identity linked to the principal.
+func (s *LDAPIdentityStore) FindByPrincipalID(ctx context.Context, principalID int64) (*types.LDAPIdentity, error) {
+	const sqlQuery = ldapIdentitySelectBase + `
+	WHERE ldap_identity_principal_id = $1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &ldapIdentity{}
+	if err := db.GetContext(ctx, dst, sqlQuery, principalID); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to find ldap identity by principal id")
+	}
+
+	return mapLDAPIdentity(dst), nil
+}
+
+// List returns all identities.
+func (s *LDAPIdentityStore) List(ctx context.Context) ([]*types.LDAPIdentity, error) {
+	const sqlQuery = ldapIdentitySelectBase + `
+	ORDER BY ldap_identity_dn`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	var dst []*ldapIdentity
+	if err := db.SelectContext(ctx, &dst, sqlQuery); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to list ldap identities")
+	}
+
+	identities := make([]*types.LDAPIdentity, len(dst))
+	for i, identity := range dst {
+		identities[i] = mapLDAPIdentity(identity)
+	}
+
+	return identities, nil
+}
+
+// Create links a new identity to a principal.
+func (s *LDAPIdentityStore) Create(ctx context.Context, identity *types.LDAPIdentity) error {
+	const sqlQuery = `
+		INSERT INTO ldap_identities (
+			 ldap_identity_dn
+			,ldap_identity_principal_id
+			,ldap_identity_created
+			,ldap_identity_last_login
+		) VALUES (
+			 :ldap_identity_dn
+			,:ldap_identity_principal_id
+			,:ldap_identity_created
+			,:ldap_identity_last_login
+		)`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, mapInternalLDAPIdentity(identity))
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to bind ldap identity object")
+	}
+
+	if _, err = db.ExecContext(ctx, query, arg...); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to insert ldap identity")
+	}
+
+	return nil
+}
+
+// UpdateLastLogin updates the time of the last login with the identity.
+func (s *LDAPIdentityStore) UpdateLastLogin(ctx context.Context, dn string, lastLogin int64) error {
+	const sqlQuery = `
+		UPDATE ldap_identities
+		SET ldap_identity_last_login = $1
+		WHERE ldap_identity_dn = $2`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	if _, err := db.ExecContext(ctx, sqlQuery, lastLogin, dn); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to update last login of ldap identity")
+	}
+
+	return nil
+}
+
+// DeleteByPrincipalID removes the identity linked to the principal.
+func (s *LDAPIdentityStore) DeleteByPrincipalID(ctx context.Context, principalID int64) error {
+	const sqlQuery = `
+		DELETE FROM ldap_identities
+		WHERE ldap_identity_principal_id = $1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	if _, err := db.ExecContext(ctx, sqlQuery, principalID); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to delete ldap identity")
+	}
+
+	return nil
+}
+
+func mapInternalLDAPIdentity(identity *types.LDAPIdentity) *ldapIdentity {
+	return &ldapIdentity{
+		DN:          identity.DN,
+		PrincipalID: identity.PrincipalID,
+		Created:     identity.Created,
+		LastLogin:   identity.LastLogin,
+	}
+}
+
+func mapLDAPIdentity(identity *ldapIdentity) *types.LDAPIdentity {
+	return &types.LDAPIdentity{
+		DN:          identity.DN,
+		PrincipalID: identity.PrincipalID,
+		Created:     identity.Created,
+		LastLogin:   identity.LastLogin,
+	}
+}
@@ -372,6 +372,10 @@ func applyMembershipSpaceFilter(
 		stmt = stmt.Where(PartialMatch("space_uid", opts.Query))
 	}
 
+	if opts.Role != "" {
+		stmt = stmt.Where("membership_role = ?", opts.Role)
+	}
+
 	return stmt
 }
 
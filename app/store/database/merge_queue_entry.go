@@ -0,0 +1,346 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+var _ store.MergeQueueEntryStore = (*MergeQueueEntryStore)(nil)
+
+// NewMergeQueueEntryStore returns a new MergeQueueEntryStore.
+func NewMergeQueueEntryStore(db *sqlx.DB) *MergeQueueEntryStore {
+	return &MergeQueueEntryStore{
+		db: db,
+	}
+}
+
+// MergeQueueEntryStore implements store.MergeQueueEntryStore backed by a relational database.
+type MergeQueueEntryStore struct {
+	db *sqlx.DB
+}
+
+// mergeQueueEntry is an internal representation used to store merge queue entries in the database.
+type mergeQueueEntry struct {
+	ID            int64                     `db:"merge_queue_entry_id"`
+	Version       int64                     `db:"merge_queue_entry_version"`
+	RepoID        int64                     `db:"merge_queue_entry_repo_id"`
+	Branch        string                    `db:"merge_queue_entry_branch"`
+	PullReqID     int64                     `db:"merge_queue_entry_pullreq_id"`
+	PullReqNumber int64                     `db:"pullreq_number"`
+	SourceSHA     string                    `db:"merge_queue_entry_source_sha"`
+	Method        enum.MergeMethod          `db:"merge_queue_entry_method"`
+	Title         string                    `db:"merge_queue_entry_title"`
+	Message       string                    `db:"merge_queue_entry_message"`
+	State         enum.MergeQueueEntryState `db:"merge_queue_entry_state"`
+	BaseSHA       string                    `db:"merge_queue_entry_base_sha"`
+	MergeSHA      string                    `db:"merge_queue_entry_merge_sha"`
+	CreatedBy     int64                     `db:"merge_queue_entry_created_by"`
+	Created       int64                     `db:"merge_queue_entry_created"`
+	Updated       int64                     `db:"merge_queue_entry_updated"`
+}
+
+const (
+	mergeQueueEntryColumns = `
+		 merge_queue_entry_id
+		,merge_queue_entry_version
+		,merge_queue_entry_repo_id
+		,merge_queue_entry_branch
+		,merge_queue_entry_pullreq_id
+		,pullreq_number
+		,merge_queue_entry_source_sha
+		,merge_queue_entry_method
+		,merge_queue_entry_title
+		,merge_queue_entry_message
+		,merge_queue_entry_state
+		,merge_queue_entry_base_sha
+		,merge_queue_entry_merge_sha
+		,merge_queue_entry_created_by
+		,merge_queue_entry_created
+		,merge_queue_entry_updated`
+
+	mergeQueueEntrySelectBase = `
+	SELECT` + mergeQueueEntryColumns + `
+	FROM merge_queue_entries
+	INNER JOIN pullreqs ON pullreq_id = merge_queue_entry_pullreq_id`
+)
+
+// Find finds the merge queue entry by id.
+func (s *MergeQueueEntryStore) Find(ctx context.Context, id int64) (*types.MergeQueueEntry, error) {
+	const sqlQuery = mergeQueueEntrySelectBase + `
+		WHERE merge_queue_entry_id = $1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &mergeQueueEntry{}
+	if err := db.GetContext(ctx, dst, sqlQuery, id); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to find merge queue entry")
+	}
+
+	return mapToMergeQueueEntry(dst), nil
+}
+
+// FindByPullReq finds the merge queue entry of a pull request.
+func (s *MergeQueueEntryStore) FindByPullReq(ctx context.Context, pullreqID int64) (*types.MergeQueueEntry, error) {
+	const sqlQuery = mergeQueueEntrySelectBase + `
+		WHERE merge_queue_entry_pullreq_id = $1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &mergeQueueEntry{}
+	if err := db.GetContext(ctx, dst, sqlQuery, pullreqID); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to find merge queue entry of pull request")
+	}
+
+	return mapToMergeQueueEntry(dst), nil
+}
+
+// Create creates a new merge queue entry.
+func (s *MergeQueueEntryStore) Create(ctx context.Context, e *types.MergeQueueEntry) error {
+	const sqlQuery = `
+		INSERT INTO merge_queue_entries (
+			 merge_queue_entry_version
+			,merge_queue_entry_repo_id
+			,merge_queue_entry_branch
+			,merge_queue_entry_pullreq_id
+			,merge_queue_entry_source_sha
+			,merge_queue_entry_method
+			,merge_queue_entry_title
+			,merge_queue_entry_message
+			,merge_queue_entry_state
+			,merge_queue_entry_base_sha
+			,merge_queue_entry_merge_sha
+			,merge_queue_entry_created_by
+			,merge_queue_entry_created
+			,merge_queue_entry_updated
+		) values (
+			 :merge_queue_entry_version
+			,:merge_queue_entry_repo_id
+			,:merge_queue_entry_branch
+			,:merge_queue_entry_pullreq_id
+			,:merge_queue_entry_source_sha
+			,:merge_queue_entry_method
+			,:merge_queue_entry_title
+			,:merge_queue_entry_message
+			,:merge_queue_entry_state
+			,:merge_queue_entry_base_sha
+			,:merge_queue_entry_merge_sha
+			,:merge_queue_entry_created_by
+			,:merge_queue_entry_created
+			,:merge_queue_entry_updated
+		) RETURNING merge_queue_entry_id`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, mapToInternalMergeQueueEntry(e))
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to bind merge queue entry object")
+	}
+
+	if err = db.QueryRowContext(ctx, query, arg...).Scan(&e.ID); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Insert query failed")
+	}
+
+	return nil
+}
+
+// Update updates an existing merge queue entry.
+func (s *MergeQueueEntryStore) Update(ctx context.Context, e *types.MergeQueueEntry) error {
+	const sqlQuery = `
+		UPDATE merge_queue_entries
+		SET
+			 merge_queue_entry_version = :merge_queue_entry_version
+			,merge_queue_entry_updated = :merge_queue_entry_updated
+			,merge_queue_entry_state = :merge_queue_entry_state
+			,merge_queue_entry_base_sha = :merge_queue_entry_base_sha
+			,merge_queue_entry_merge_sha = :merge_queue_entry_merge_sha
+		WHERE merge_queue_entry_id = :merge_queue_entry_id
+			AND merge_queue_entry_version = :merge_queue_entry_version - 1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dbEntry := mapToInternalMergeQueueEntry(e)
+
+	// update Version (used for optimistic locking) and Updated time
+	dbEntry.Version++
+	dbEntry.Updated = time.Now().UnixMilli()
+
+	query, arg, err := db.BindNamed(sqlQuery, dbEntry)
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to bind merge queue entry object")
+	}
+
+	result, err := db.ExecContext(ctx, query, arg...)
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to update merge queue entry")
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to get number of updated rows")
+	}
+
+	if count == 0 {
+		return gitness_store.ErrVersionConflict
+	}
+
+	e.Version = dbEntry.Version
+	e.Updated = dbEntry.Updated
+
+	return nil
+}
+
+// UpdateOptLock updates the merge queue entry using the optimistic locking mechanism.
+func (s *MergeQueueEntryStore) UpdateOptLock(
+	ctx context.Context,
+	e *types.MergeQueueEntry,
+	mutateFn func(e *types.MergeQueueEntry) error,
+) (*types.MergeQueueEntry, error) {
+	for {
+		dup := *e
+
+		err := mutateFn(&dup)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.Update(ctx, &dup)
+		if err == nil {
+			return &dup, nil
+		}
+		if !errors.Is(err, gitness_store.ErrVersionConflict) {
+			return nil, err
+		}
+
+		e, err = s.Find(ctx, e.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Delete deletes the merge queue entry with the given id.
+func (s *MergeQueueEntryStore) Delete(ctx context.Context, id int64) error {
+	const sqlQuery = `
+		DELETE FROM merge_queue_entries
+		WHERE merge_queue_entry_id = $1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	if _, err := db.ExecContext(ctx, sqlQuery, id); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "The delete query failed")
+	}
+
+	return nil
+}
+
+// List lists the entries of the merge queue of a branch in the order they were added to the queue.
+func (s *MergeQueueEntryStore) List(
+	ctx context.Context,
+	repoID int64,
+	branch string,
+) ([]*types.MergeQueueEntry, error) {
+	const sqlQuery = mergeQueueEntrySelectBase + `
+		WHERE merge_queue_entry_repo_id = $1 AND merge_queue_entry_branch = $2
+		ORDER BY merge_queue_entry_id ASC`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := []*mergeQueueEntry{}
+	if err := db.SelectContext(ctx, &dst, sqlQuery, repoID, branch); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed executing list merge queue entries query")
+	}
+
+	return mapToMergeQueueEntries(dst), nil
+}
+
+// ListByRepo lists the entries of all merge queues of a repository.
+func (s *MergeQueueEntryStore) ListByRepo(ctx context.Context, repoID int64) ([]*types.MergeQueueEntry, error) {
+	const sqlQuery = mergeQueueEntrySelectBase + `
+		WHERE merge_queue_entry_repo_id = $1
+		ORDER BY merge_queue_entry_id ASC`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := []*mergeQueueEntry{}
+	if err := db.SelectContext(ctx, &dst, sqlQuery, repoID); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed executing list merge queue entries query")
+	}
+
+	return mapToMergeQueueEntries(dst), nil
+}
+
+// ListBranches lists all branches with a non-empty merge queue.
+func (s *MergeQueueEntryStore) ListBranches(ctx context.Context) ([]types.MergeQueueBranch, error) {
+	stmt := database.Builder.
+		Select("DISTINCT merge_queue_entry_repo_id, merge_queue_entry_branch").
+		From("merge_queue_entries")
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	rows, err := db.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed executing list merge queue branches query")
+	}
+	defer rows.Close()
+
+	var result []types.MergeQueueBranch
+	for rows.Next() {
+		var b types.MergeQueueBranch
+		if err = rows.Scan(&b.RepoID, &b.Branch); err != nil {
+			return nil, database.ProcessSQLErrorf(ctx, err, "Failed to scan merge queue branch")
+		}
+		result = append(result, b)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to list merge queue branches")
+	}
+
+	return result, nil
+}
+
+func mapToMergeQueueEntry(e *mergeQueueEntry) *types.MergeQueueEntry {
+	return (*types.MergeQueueEntry)(e) // the two types are identical, except for the tags
+}
+
+func mapToMergeQueueEntries(es []*mergeQueueEntry) []*types.MergeQueueEntry {
+	res := make([]*types.MergeQueueEntry, len(es))
+	for i, e := range es {
+		res[i] = mapToMergeQueueEntry(e)
+	}
+	return res
+}
+
+func mapToInternalMergeQueueEntry(e *types.MergeQueueEntry) *mergeQueueEntry {
+	return (*mergeQueueEntry)(e) // the two types are identical, except for the tags
+}
@@ -0,0 +1,20 @@
+-- the review queues already exist in postgres, only fix the tag delete trigger to look up the tagged manifest.
+CREATE OR REPLACE FUNCTION gc_track_deleted_tags()
+    RETURNS TRIGGER
+AS
+$$
+BEGIN
+    IF EXISTS (SELECT 1
+               FROM manifests
+               WHERE manifest_registry_id = OLD.tag_registry_id
+                 AND manifest_id = OLD.tag_manifest_id) THEN
+        INSERT INTO gc_manifest_review_queue (registry_id, manifest_id, review_after, event)
+        VALUES (OLD.tag_registry_id, OLD.tag_manifest_id, gc_review_after('tag_delete'), 'tag_delete')
+        ON CONFLICT (registry_id, manifest_id)
+            DO UPDATE SET review_after = gc_review_after('tag_delete'),
+                          event        = 'tag_delete';
+    END IF;
+    RETURN NULL;
+END;
+$$
+    LANGUAGE plpgsql;
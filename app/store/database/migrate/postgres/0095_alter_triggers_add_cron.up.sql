@@ -0,0 +1,8 @@
+ALTER TABLE triggers ADD COLUMN trigger_cron TEXT NOT NULL DEFAULT '';
+ALTER TABLE triggers ADD COLUMN trigger_cron_branch TEXT NOT NULL DEFAULT '';
+ALTER TABLE triggers ADD COLUMN trigger_cron_timezone TEXT NOT NULL DEFAULT '';
+ALTER TABLE triggers ADD COLUMN trigger_cron_next BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX triggers_cron_next
+    ON triggers(trigger_cron_next)
+    WHERE trigger_cron <> '';
@@ -0,0 +1,35 @@
+CREATE TABLE mirrors (
+    mirror_id SERIAL PRIMARY KEY,
+    mirror_version INTEGER NOT NULL,
+    mirror_repo_id INTEGER NOT NULL,
+    mirror_uid TEXT NOT NULL,
+    mirror_direction TEXT NOT NULL,
+    mirror_remote_url TEXT NOT NULL,
+    mirror_username TEXT NOT NULL,
+    mirror_secret_identifier TEXT NOT NULL,
+    mirror_connector_identifier TEXT NOT NULL,
+    mirror_interval BIGINT NOT NULL,
+    mirror_enabled BOOLEAN NOT NULL,
+    mirror_next_sync BIGINT NOT NULL,
+    mirror_sync_state TEXT NOT NULL,
+    mirror_last_sync BIGINT NOT NULL,
+    mirror_last_error TEXT NOT NULL,
+    mirror_created_by INTEGER NOT NULL,
+    mirror_created BIGINT NOT NULL,
+    mirror_updated BIGINT NOT NULL,
+    CONSTRAINT fk_mirror_created_by FOREIGN KEY (mirror_created_by)
+        REFERENCES principals (principal_id)
+        ON UPDATE NO ACTION
+        ON DELETE NO ACTION,
+    CONSTRAINT fk_mirror_repo_id FOREIGN KEY (mirror_repo_id)
+        REFERENCES repositories (repo_id)
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX mirrors_repo_id_uid
+    ON mirrors(mirror_repo_id, LOWER(mirror_uid));
+
+CREATE INDEX mirrors_next_sync
+    ON mirrors(mirror_next_sync)
+    WHERE mirror_direction = 'pull' AND mirror_enabled = TRUE;
@@ -0,0 +1,2 @@
+ALTER TABLE pullreqs
+    ADD COLUMN pullreq_allow_maintainer_edit BOOLEAN NOT NULL DEFAULT FALSE;
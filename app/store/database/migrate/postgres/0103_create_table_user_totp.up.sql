@@ -0,0 +1,14 @@
+CREATE TABLE user_totp (
+    user_totp_principal_id INTEGER PRIMARY KEY,
+    user_totp_secret BYTEA NOT NULL,
+    user_totp_enabled BOOLEAN NOT NULL,
+    user_totp_recovery_codes TEXT NOT NULL,
+    user_totp_last_used_step BIGINT NOT NULL,
+    user_totp_version BIGINT NOT NULL,
+    user_totp_created BIGINT NOT NULL,
+    user_totp_updated BIGINT NOT NULL,
+    CONSTRAINT fk_user_totp_principal_id FOREIGN KEY (user_totp_principal_id)
+        REFERENCES principals (principal_id)
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
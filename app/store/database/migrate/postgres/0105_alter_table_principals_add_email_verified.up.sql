@@ -0,0 +1,4 @@
+ALTER TABLE principals ADD COLUMN principal_user_email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- existing users were never required to verify their email, so they are treated as verified.
+UPDATE principals SET principal_user_email_verified = TRUE WHERE principal_type = 'user';
@@ -0,0 +1,11 @@
+CREATE TABLE user_mail_limits (
+    user_mail_limit_principal_id INTEGER NOT NULL,
+    user_mail_limit_type TEXT NOT NULL,
+    user_mail_limit_window_start BIGINT NOT NULL,
+    user_mail_limit_count INTEGER NOT NULL,
+    CONSTRAINT pk_user_mail_limits PRIMARY KEY (user_mail_limit_principal_id, user_mail_limit_type),
+    CONSTRAINT fk_user_mail_limit_principal_id FOREIGN KEY (user_mail_limit_principal_id)
+        REFERENCES principals (principal_id)
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
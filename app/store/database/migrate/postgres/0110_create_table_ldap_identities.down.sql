@@ -0,0 +1 @@
+DROP TABLE ldap_identities;
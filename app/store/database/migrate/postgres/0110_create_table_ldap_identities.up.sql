@@ -0,0 +1,13 @@
+CREATE TABLE ldap_identities (
+    ldap_identity_dn TEXT NOT NULL,
+    ldap_identity_principal_id INTEGER NOT NULL,
+    ldap_identity_created BIGINT NOT NULL,
+    ldap_identity_last_login BIGINT NOT NULL,
+    CONSTRAINT pk_ldap_identities PRIMARY KEY (ldap_identity_dn),
+    CONSTRAINT fk_ldap_identity_principal_id FOREIGN KEY (ldap_identity_principal_id)
+        REFERENCES principals (principal_id)
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX ldap_identities_principal_id ON ldap_identities (ldap_identity_principal_id);
@@ -0,0 +1,3 @@
+ALTER TABLE user_totp DROP COLUMN user_totp_challenges_revoked;
+ALTER TABLE user_totp DROP COLUMN user_totp_locked_until;
+ALTER TABLE user_totp DROP COLUMN user_totp_failed_attempts;
@@ -0,0 +1,3 @@
+ALTER TABLE user_totp ADD COLUMN user_totp_failed_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE user_totp ADD COLUMN user_totp_locked_until BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE user_totp ADD COLUMN user_totp_challenges_revoked BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+ALTER TABLE user_chat_identities DROP COLUMN user_chat_identity_confirmation_attempts;
+ALTER TABLE user_chat_identities DROP COLUMN user_chat_identity_confirmation_expires;
+ALTER TABLE user_chat_identities DROP COLUMN user_chat_identity_confirmation_hash;
+ALTER TABLE user_chat_identities DROP COLUMN user_chat_identity_verified;
@@ -0,0 +1,4 @@
+ALTER TABLE user_chat_identities ADD COLUMN user_chat_identity_verified BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE user_chat_identities ADD COLUMN user_chat_identity_confirmation_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_chat_identities ADD COLUMN user_chat_identity_confirmation_expires BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE user_chat_identities ADD COLUMN user_chat_identity_confirmation_attempts INTEGER NOT NULL DEFAULT 0;
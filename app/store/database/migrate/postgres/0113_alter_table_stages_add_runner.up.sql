@@ -0,0 +1,2 @@
+ALTER TABLE stages ADD COLUMN stage_runner_id INTEGER;
+ALTER TABLE stages ADD COLUMN stage_runner_offer_expires BIGINT NOT NULL DEFAULT 0;
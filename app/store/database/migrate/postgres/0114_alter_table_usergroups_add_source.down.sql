@@ -0,0 +1,2 @@
+ALTER TABLE usergroup_members DROP COLUMN usergroup_member_source;
+ALTER TABLE usergroups DROP COLUMN usergroup_managed_by;
@@ -0,0 +1,2 @@
+ALTER TABLE usergroups ADD COLUMN usergroup_managed_by TEXT NOT NULL DEFAULT 'manual';
+ALTER TABLE usergroup_members ADD COLUMN usergroup_member_source TEXT NOT NULL DEFAULT 'manual';
@@ -0,0 +1,22 @@
+ALTER TABLE audit_events ADD COLUMN audit_event_space_id INTEGER;
+
+-- the existing events are assigned to the spaces that have their paths now.
+WITH RECURSIVE space_full_paths(space_id, space_full_path) AS (
+    SELECT space_id, space_uid
+    FROM spaces
+    WHERE space_parent_id IS NULL
+
+    UNION ALL
+
+    SELECT spaces.space_id, space_full_paths.space_full_path || '/' || spaces.space_uid
+    FROM spaces
+    JOIN space_full_paths ON spaces.space_parent_id = space_full_paths.space_id
+)
+UPDATE audit_events
+SET audit_event_space_id = (
+    SELECT space_id
+    FROM space_full_paths
+    WHERE LOWER(space_full_path) = LOWER(audit_event_space_path)
+);
+
+CREATE INDEX audit_events_space_id_timestamp ON audit_events(audit_event_space_id, audit_event_timestamp);
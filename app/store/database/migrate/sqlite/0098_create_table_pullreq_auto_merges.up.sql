@@ -0,0 +1,24 @@
+CREATE TABLE pullreq_auto_merges (
+    pullreq_auto_merge_pullreq_id INTEGER PRIMARY KEY
+    ,pullreq_auto_merge_repo_id INTEGER NOT NULL
+    ,pullreq_auto_merge_method TEXT NOT NULL
+    ,pullreq_auto_merge_title TEXT NOT NULL
+    ,pullreq_auto_merge_message TEXT NOT NULL
+    ,pullreq_auto_merge_created_by INTEGER NOT NULL
+    ,pullreq_auto_merge_created BIGINT NOT NULL
+    ,CONSTRAINT fk_pullreq_auto_merge_pullreq_id FOREIGN KEY (pullreq_auto_merge_pullreq_id)
+        REFERENCES pullreqs (pullreq_id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+    ,CONSTRAINT fk_pullreq_auto_merge_repo_id FOREIGN KEY (pullreq_auto_merge_repo_id)
+        REFERENCES repositories (repo_id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+    ,CONSTRAINT fk_pullreq_auto_merge_created_by FOREIGN KEY (pullreq_auto_merge_created_by)
+        REFERENCES principals (principal_id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE NO ACTION
+);
+
+CREATE INDEX pullreq_auto_merges_repo_id
+    ON pullreq_auto_merges(pullreq_auto_merge_repo_id);
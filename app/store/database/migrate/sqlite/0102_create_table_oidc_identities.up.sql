@@ -0,0 +1,14 @@
+CREATE TABLE oidc_identities (
+    oidc_identity_issuer TEXT NOT NULL
+    ,oidc_identity_subject TEXT NOT NULL
+    ,oidc_identity_principal_id INTEGER NOT NULL
+    ,oidc_identity_created BIGINT NOT NULL
+    ,oidc_identity_last_login BIGINT NOT NULL
+    ,CONSTRAINT pk_oidc_identities PRIMARY KEY (oidc_identity_issuer, oidc_identity_subject)
+    ,CONSTRAINT fk_oidc_identity_principal_id FOREIGN KEY (oidc_identity_principal_id)
+        REFERENCES principals (principal_id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+
+CREATE INDEX oidc_identities_principal_id ON oidc_identities (oidc_identity_principal_id);
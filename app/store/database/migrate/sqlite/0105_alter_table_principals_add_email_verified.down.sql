@@ -0,0 +1 @@
+ALTER TABLE principals DROP COLUMN principal_user_email_verified;
@@ -0,0 +1,3 @@
+ALTER TABLE stages DROP COLUMN stage_approval_decided;
+ALTER TABLE stages DROP COLUMN stage_approval_by;
+ALTER TABLE stages DROP COLUMN stage_approval_required;
@@ -0,0 +1,3 @@
+ALTER TABLE stages ADD COLUMN stage_approval_required BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE stages ADD COLUMN stage_approval_by INTEGER;
+ALTER TABLE stages ADD COLUMN stage_approval_decided BIGINT NOT NULL DEFAULT 0;
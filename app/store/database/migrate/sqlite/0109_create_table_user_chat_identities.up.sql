@@ -0,0 +1,12 @@
+CREATE TABLE user_chat_identities (
+    user_chat_identity_principal_id INTEGER NOT NULL
+    ,user_chat_identity_provider TEXT NOT NULL
+    ,user_chat_identity_external_id TEXT NOT NULL
+    ,user_chat_identity_created BIGINT NOT NULL
+    ,user_chat_identity_updated BIGINT NOT NULL
+    ,CONSTRAINT pk_user_chat_identities PRIMARY KEY (user_chat_identity_principal_id, user_chat_identity_provider)
+    ,CONSTRAINT fk_user_chat_identity_principal_id FOREIGN KEY (user_chat_identity_principal_id)
+        REFERENCES principals (principal_id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE stages DROP COLUMN stage_runner_offer_expires;
+ALTER TABLE stages DROP COLUMN stage_runner_id;
@@ -0,0 +1,2 @@
+DROP INDEX audit_events_space_id_timestamp;
+ALTER TABLE audit_events DROP COLUMN audit_event_space_id;
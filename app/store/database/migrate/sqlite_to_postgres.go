@@ -0,0 +1,720 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// importStateTable keeps track of the progress of the sqlite to postgres import.
+	// It exists in the postgres database only while the import isn't completed, which allows resuming it.
+	importStateTable = "sqlite_import_state"
+
+	// importStateTruncated is the key of the state row that marks that the target tables have been emptied.
+	importStateTruncated = "$truncated"
+
+	// postgresMaxParams is the max number of parameters of a single postgres statement.
+	postgresMaxParams = 65535
+
+	// DefaultImportBatchSize is the default number of rows copied in a single transaction.
+	DefaultImportBatchSize = 1000
+)
+
+// importTable holds the schema information of a table that is copied from sqlite to postgres.
+type importTable struct {
+	name    string
+	columns []string
+	// primaryKey is the primary key column, empty if the table doesn't have a single column primary key.
+	primaryKey string
+	// selfRefs are the columns with a foreign key referencing the table itself.
+	selfRefs []string
+	// deps are the other tables the table references.
+	deps []string
+}
+
+// Latest returns the version of the latest migration known for the driver of the database.
+func Latest(db *sqlx.DB) (string, error) {
+	opts, err := getMigrator(db)
+	if err != nil {
+		return "", err
+	}
+
+	entries, err := fs.ReadDir(opts.FS, ".")
+	if err != nil {
+		return "", fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	var latest string
+	for _, entry := range entries {
+		version, ok := strings.CutSuffix(entry.Name(), ".up.sql")
+		if ok && version > latest {
+			latest = version
+		}
+	}
+
+	return latest, nil
+}
+
+// SqliteToPostgres copies all data of the sqlite database to the postgres database.
+//
+// The sqlite database must be at the latest schema version and must not be modified during the import.
+// The postgres database must be empty, the schema is created by running the postgres migrations.
+// The tables are copied in dependency order in batches, each batch is committed together with the progress,
+// so a failed import can be resumed by calling the function again. Columns referencing their own table are
+// filled in after all rows of the table are copied. User triggers (e.g. the registry garbage collection triggers)
+// are disabled while the data is copied, as their effects are already part of the copied data.
+// Finally, the sequences are moved past the copied IDs and the row counts of all tables are verified.
+func SqliteToPostgres(ctx context.Context, src, dst *sqlx.DB, batchSize int) error {
+	if src.DriverName() != sqliteDriverName {
+		return fmt.Errorf("source database must be %s, got %s", sqliteDriverName, src.DriverName())
+	}
+	if dst.DriverName() != postgresDriverName {
+		return fmt.Errorf("target database must be %s, got %s", postgresDriverName, dst.DriverName())
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultImportBatchSize
+	}
+
+	version, err := checkSqliteVersion(ctx, src)
+	if err != nil {
+		return err
+	}
+
+	tables, err := listSqliteTables(ctx, src)
+	if err != nil {
+		return err
+	}
+
+	if err := prepareImportTarget(ctx, dst, version, tables); err != nil {
+		return err
+	}
+
+	if err := setUserTriggers(ctx, dst, tables, false); err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		if err := importTableData(ctx, src, dst, table, batchSize); err != nil {
+			return fmt.Errorf("failed to copy table %s: %w", table.name, err)
+		}
+	}
+
+	if err := verifyRowCounts(ctx, src, dst, tables); err != nil {
+		return err
+	}
+
+	if err := fixSequences(ctx, dst); err != nil {
+		return err
+	}
+
+	if err := setUserTriggers(ctx, dst, tables, true); err != nil {
+		return err
+	}
+
+	if _, err := dst.ExecContext(ctx, "DROP TABLE "+importStateTable); err != nil {
+		return fmt.Errorf("failed to drop the import state table: %w", err)
+	}
+
+	log.Ctx(ctx).Info().Msgf("copied %d tables from sqlite to postgres", len(tables))
+
+	return nil
+}
+
+func checkSqliteVersion(ctx context.Context, src *sqlx.DB) (string, error) {
+	version, err := Current(ctx, src)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the sqlite database version: %w", err)
+	}
+
+	latest, err := Latest(src)
+	if err != nil {
+		return "", err
+	}
+
+	if version != latest {
+		return "", fmt.Errorf("sqlite database is at version %q, but must be at the latest version %q, "+
+			"start the server or use the migrate command to upgrade it first", version, latest)
+	}
+
+	return version, nil
+}
+
+// prepareImportTarget creates the schema of the postgres database and empties the tables
+// that are filled by the migrations. For a resumed import, it only verifies the schema version.
+func prepareImportTarget(ctx context.Context, dst *sqlx.DB, version string, tables []importTable) error {
+	var stateExists bool
+	err := dst.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM information_schema.tables
+			WHERE table_schema = current_schema() AND table_name = $1
+		)`, importStateTable).Scan(&stateExists)
+	if err != nil {
+		return fmt.Errorf("failed to check the import state table: %w", err)
+	}
+
+	if !stateExists {
+		var tableCount int
+		err = dst.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM information_schema.tables
+			WHERE table_schema = current_schema()`).Scan(&tableCount)
+		if err != nil {
+			return fmt.Errorf("failed to count the postgres tables: %w", err)
+		}
+		if tableCount > 0 {
+			return errors.New("postgres database isn't empty and has no import in progress")
+		}
+
+		_, err = dst.ExecContext(ctx, `
+			CREATE TABLE `+importStateTable+` (
+				 state_table TEXT PRIMARY KEY
+				,state_last_rowid BIGINT NOT NULL DEFAULT 0
+				,state_rows BIGINT NOT NULL DEFAULT 0
+				,state_completed BOOLEAN NOT NULL DEFAULT FALSE
+			)`)
+		if err != nil {
+			return fmt.Errorf("failed to create the import state table: %w", err)
+		}
+	} else {
+		log.Ctx(ctx).Info().Msg("resuming the import into postgres")
+	}
+
+	if err := Migrate(ctx, dst); err != nil {
+		return fmt.Errorf("failed to migrate the postgres database: %w", err)
+	}
+
+	dstVersion, err := Current(ctx, dst)
+	if err != nil {
+		return fmt.Errorf("failed to get the postgres database version: %w", err)
+	}
+	if dstVersion != version {
+		return fmt.Errorf("postgres database is at version %q, expected %q", dstVersion, version)
+	}
+
+	for _, table := range tables {
+		columns, err := postgresColumnTypes(ctx, dst, table.name)
+		if err != nil {
+			return err
+		}
+		for _, column := range table.columns {
+			if _, ok := columns[column]; !ok {
+				return fmt.Errorf("column %s.%s doesn't exist in postgres", table.name, column)
+			}
+		}
+	}
+
+	var truncated bool
+	err = dst.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM "+importStateTable+" WHERE state_table = $1)",
+		importStateTruncated).Scan(&truncated)
+	if err != nil {
+		return fmt.Errorf("failed to read the import state: %w", err)
+	}
+	if truncated {
+		return nil
+	}
+
+	// The migrations insert some static data (e.g. media types), which is part of the sqlite data as well.
+	names := make([]string, len(tables))
+	for i, table := range tables {
+		names[i] = table.name
+	}
+
+	tx, err := dst.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "TRUNCATE TABLE "+strings.Join(quoteAll(names), ", ")+" CASCADE"); err != nil {
+		return fmt.Errorf("failed to empty the postgres tables: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO "+importStateTable+" (state_table, state_completed) VALUES ($1, TRUE)",
+		importStateTruncated)
+	if err != nil {
+		return fmt.Errorf("failed to update the import state: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// listSqliteTables returns the tables of the sqlite database, without the migration table,
+// ordered so that every table comes after the tables it references.
+func listSqliteTables(ctx context.Context, src *sqlx.DB) ([]importTable, error) {
+	var names []string
+	err := src.SelectContext(ctx, &names, `
+		SELECT name FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name <> ?
+		ORDER BY name`, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sqlite tables: %w", err)
+	}
+
+	tables := make([]importTable, 0, len(names))
+	for _, name := range names {
+		table, err := readSqliteTable(ctx, src, name)
+		if err != nil {
+			return nil, err
+		}
+
+		tables = append(tables, table)
+	}
+
+	return sortTablesByDependencies(tables)
+}
+
+func readSqliteTable(ctx context.Context, src *sqlx.DB, name string) (importTable, error) {
+	table := importTable{name: name}
+
+	rows, err := src.QueryContext(ctx, "SELECT name, pk FROM pragma_table_info(?) ORDER BY cid", name)
+	if err != nil {
+		return importTable{}, fmt.Errorf("failed to read columns of table %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	var pkColumns []string
+	for rows.Next() {
+		var column string
+		var pk int
+		if err := rows.Scan(&column, &pk); err != nil {
+			return importTable{}, fmt.Errorf("failed to scan column of table %s: %w", name, err)
+		}
+
+		table.columns = append(table.columns, column)
+		if pk > 0 {
+			pkColumns = append(pkColumns, column)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return importTable{}, fmt.Errorf("failed to read columns of table %s: %w", name, err)
+	}
+
+	if len(pkColumns) == 1 {
+		table.primaryKey = pkColumns[0]
+	}
+
+	fkRows, err := src.QueryContext(ctx, `SELECT "table", "from" FROM pragma_foreign_key_list(?)`, name)
+	if err != nil {
+		return importTable{}, fmt.Errorf("failed to read foreign keys of table %s: %w", name, err)
+	}
+	defer fkRows.Close()
+
+	for fkRows.Next() {
+		var refTable, column string
+		if err := fkRows.Scan(&refTable, &column); err != nil {
+			return importTable{}, fmt.Errorf("failed to scan foreign key of table %s: %w", name, err)
+		}
+
+		switch {
+		case refTable == name:
+			if !slices.Contains(table.selfRefs, column) {
+				table.selfRefs = append(table.selfRefs, column)
+			}
+		case !slices.Contains(table.deps, refTable):
+			table.deps = append(table.deps, refTable)
+		}
+	}
+	if err := fkRows.Err(); err != nil {
+		return importTable{}, fmt.Errorf("failed to read foreign keys of table %s: %w", name, err)
+	}
+
+	if len(table.selfRefs) > 0 && table.primaryKey == "" {
+		return importTable{}, fmt.Errorf("table %s references itself, but has no single column primary key", name)
+	}
+
+	return table, nil
+}
+
+// sortTablesByDependencies orders the tables so that every table comes after the tables it references.
+// The order of the input is kept where possible.
+func sortTablesByDependencies(tables []importTable) ([]importTable, error) {
+	byName := make(map[string]importTable, len(tables))
+	for _, table := range tables {
+		byName[table.name] = table
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	state := make(map[string]int, len(tables))
+	sorted := make([]importTable, 0, len(tables))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("cyclic foreign key dependency: %s", strings.Join(append(path, name), " -> "))
+		}
+
+		table, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("table %s is referenced, but doesn't exist", name)
+		}
+
+		state[name] = visiting
+		for _, dep := range table.deps {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+
+		sorted = append(sorted, table)
+
+		return nil
+	}
+
+	for _, table := range tables {
+		if err := visit(table.name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
+
+func postgresColumnTypes(ctx context.Context, dst *sqlx.DB, table string) (map[string]string, error) {
+	rows, err := dst.QueryContext(ctx, `
+		SELECT column_name, data_type FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1`, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read postgres columns of table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns := map[string]string{}
+	for rows.Next() {
+		var column, dataType string
+		if err := rows.Scan(&column, &dataType); err != nil {
+			return nil, fmt.Errorf("failed to scan postgres column of table %s: %w", table, err)
+		}
+		columns[column] = dataType
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read postgres columns of table %s: %w", table, err)
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s doesn't exist in postgres", table)
+	}
+
+	return columns, nil
+}
+
+// setUserTriggers enables or disables the user defined triggers of the tables. Foreign keys aren't affected.
+func setUserTriggers(ctx context.Context, dst *sqlx.DB, tables []importTable, enable bool) error {
+	action := "DISABLE"
+	if enable {
+		action = "ENABLE"
+	}
+
+	for _, table := range tables {
+		_, err := dst.ExecContext(ctx, "ALTER TABLE "+pq.QuoteIdentifier(table.name)+" "+action+" TRIGGER USER")
+		if err != nil {
+			return fmt.Errorf("failed to %s triggers of table %s: %w", strings.ToLower(action), table.name, err)
+		}
+	}
+
+	return nil
+}
+
+//nolint:gocognit // the copy is easier to follow as a single function
+func importTableData(ctx context.Context, src, dst *sqlx.DB, table importTable, batchSize int) error {
+	_, err := dst.ExecContext(ctx,
+		"INSERT INTO "+importStateTable+" (state_table) VALUES ($1) ON CONFLICT DO NOTHING", table.name)
+	if err != nil {
+		return fmt.Errorf("failed to initialize the import state: %w", err)
+	}
+
+	var lastRowID, copied int64
+	var completed bool
+	err = dst.QueryRowContext(ctx,
+		"SELECT state_last_rowid, state_rows, state_completed FROM "+importStateTable+" WHERE state_table = $1",
+		table.name).Scan(&lastRowID, &copied, &completed)
+	if err != nil {
+		return fmt.Errorf("failed to read the import state: %w", err)
+	}
+	if completed {
+		log.Ctx(ctx).Debug().Msgf("table %s already copied", table.name)
+		return nil
+	}
+
+	columnTypes, err := postgresColumnTypes(ctx, dst, table.name)
+	if err != nil {
+		return err
+	}
+
+	if maxRows := postgresMaxParams / len(table.columns); batchSize > maxRows {
+		batchSize = maxRows
+	}
+
+	quoted := quoteAll(table.columns)
+
+	selectQuery := "SELECT rowid, " + strings.Join(quoted, ", ") + " FROM " + pq.QuoteIdentifier(table.name) +
+		" WHERE rowid > ? ORDER BY rowid LIMIT ?"
+
+	for {
+		batch, batchLastRowID, err := readSqliteBatch(ctx, src, selectQuery, lastRowID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		args := make([]any, 0, len(batch)*len(table.columns))
+		values := make([]string, len(batch))
+		for i, row := range batch {
+			placeholders := make([]string, len(table.columns))
+			for j, column := range table.columns {
+				value := convertValue(row[j], columnTypes[column])
+				if slices.Contains(table.selfRefs, column) {
+					value = nil
+				}
+
+				args = append(args, value)
+				placeholders[j] = fmt.Sprintf("$%d", len(args))
+			}
+			values[i] = "(" + strings.Join(placeholders, ", ") + ")"
+		}
+
+		err = withTx(ctx, dst, func(tx *sqlx.Tx) error {
+			_, err := tx.ExecContext(ctx, "INSERT INTO "+pq.QuoteIdentifier(table.name)+
+				" ("+strings.Join(quoted, ", ")+") VALUES "+strings.Join(values, ", "), args...)
+			if err != nil {
+				return fmt.Errorf("failed to insert rows: %w", err)
+			}
+
+			_, err = tx.ExecContext(ctx, "UPDATE "+importStateTable+
+				" SET state_last_rowid = $1, state_rows = state_rows + $2 WHERE state_table = $3",
+				batchLastRowID, len(batch), table.name)
+			if err != nil {
+				return fmt.Errorf("failed to update the import state: %w", err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		lastRowID = batchLastRowID
+		copied += int64(len(batch))
+	}
+
+	err = withTx(ctx, dst, func(tx *sqlx.Tx) error {
+		if err := fixSelfReferences(ctx, src, tx, table); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx,
+			"UPDATE "+importStateTable+" SET state_completed = TRUE WHERE state_table = $1", table.name)
+		if err != nil {
+			return fmt.Errorf("failed to update the import state: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Ctx(ctx).Info().Msgf("copied table %s: %d rows", table.name, copied)
+
+	return nil
+}
+
+// readSqliteBatch reads the next batch of rows. The first selected column must be the rowid,
+// it's returned separately for the last row of the batch.
+func readSqliteBatch(
+	ctx context.Context,
+	src *sqlx.DB,
+	query string,
+	lastRowID int64,
+	limit int,
+) ([][]any, int64, error) {
+	rows, err := src.QueryxContext(ctx, query, lastRowID, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read rows: %w", err)
+	}
+	defer rows.Close()
+
+	var batch [][]any
+	for rows.Next() {
+		row, err := rows.SliceScan()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		rowID, ok := row[0].(int64)
+		if !ok {
+			return nil, 0, fmt.Errorf("unexpected rowid type %T", row[0])
+		}
+
+		lastRowID = rowID
+		batch = append(batch, row[1:])
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return batch, lastRowID, nil
+}
+
+// convertValue converts a value read from sqlite to the representation expected by the postgres column.
+func convertValue(value any, dataType string) any {
+	switch v := value.(type) {
+	case []byte:
+		if dataType != "bytea" {
+			return string(v)
+		}
+	case int64:
+		if dataType == "boolean" {
+			return v != 0
+		}
+	}
+
+	return value
+}
+
+// fixSelfReferences sets the columns referencing the table itself, which are left empty while the rows are copied.
+func fixSelfReferences(ctx context.Context, src *sqlx.DB, tx *sqlx.Tx, table importTable) error {
+	if len(table.selfRefs) == 0 {
+		return nil
+	}
+
+	conditions := make([]string, len(table.selfRefs))
+	assignments := make([]string, len(table.selfRefs))
+	for i, column := range table.selfRefs {
+		conditions[i] = pq.QuoteIdentifier(column) + " IS NOT NULL"
+		assignments[i] = fmt.Sprintf("%s = $%d", pq.QuoteIdentifier(column), i+1)
+	}
+
+	rows, err := src.QueryxContext(ctx, "SELECT "+pq.QuoteIdentifier(table.primaryKey)+", "+
+		strings.Join(quoteAll(table.selfRefs), ", ")+" FROM "+pq.QuoteIdentifier(table.name)+
+		" WHERE "+strings.Join(conditions, " OR "))
+	if err != nil {
+		return fmt.Errorf("failed to read self references: %w", err)
+	}
+	defer rows.Close()
+
+	update := "UPDATE " + pq.QuoteIdentifier(table.name) + " SET " + strings.Join(assignments, ", ") +
+		fmt.Sprintf(" WHERE %s = $%d", pq.QuoteIdentifier(table.primaryKey), len(table.selfRefs)+1)
+
+	for rows.Next() {
+		row, err := rows.SliceScan()
+		if err != nil {
+			return fmt.Errorf("failed to scan self references: %w", err)
+		}
+
+		args := append(row[1:], row[0])
+		if _, err := tx.ExecContext(ctx, update, args...); err != nil {
+			return fmt.Errorf("failed to update self references: %w", err)
+		}
+	}
+
+	return rows.Err()
+}
+
+func verifyRowCounts(ctx context.Context, src, dst *sqlx.DB, tables []importTable) error {
+	var mismatches []string
+	for _, table := range tables {
+		query := "SELECT COUNT(*) FROM " + pq.QuoteIdentifier(table.name)
+
+		var srcCount, dstCount int64
+		if err := src.QueryRowContext(ctx, query).Scan(&srcCount); err != nil {
+			return fmt.Errorf("failed to count sqlite rows of table %s: %w", table.name, err)
+		}
+		if err := dst.QueryRowContext(ctx, query).Scan(&dstCount); err != nil {
+			return fmt.Errorf("failed to count postgres rows of table %s: %w", table.name, err)
+		}
+
+		if srcCount != dstCount {
+			mismatches = append(mismatches, fmt.Sprintf("%s (sqlite=%d postgres=%d)", table.name, srcCount, dstCount))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("row count mismatch: %s", strings.Join(mismatches, ", "))
+	}
+
+	return nil
+}
+
+// fixSequences moves all sequences used as column defaults past the max value of their column.
+func fixSequences(ctx context.Context, dst *sqlx.DB) error {
+	type serialColumn struct {
+		Table  string `db:"table_name"`
+		Column string `db:"column_name"`
+	}
+
+	var columns []serialColumn
+	err := dst.SelectContext(ctx, &columns, `
+		SELECT table_name, column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND column_default LIKE 'nextval(%'`)
+	if err != nil {
+		return fmt.Errorf("failed to list serial columns: %w", err)
+	}
+
+	for _, c := range columns {
+		_, err := dst.ExecContext(ctx, fmt.Sprintf(
+			"SELECT setval(pg_get_serial_sequence($1, $2), COALESCE(MAX(%s), 0) + 1, false) FROM %s",
+			pq.QuoteIdentifier(c.Column), pq.QuoteIdentifier(c.Table)),
+			c.Table, c.Column)
+		if err != nil {
+			return fmt.Errorf("failed to update sequence of %s.%s: %w", c.Table, c.Column, err)
+		}
+	}
+
+	return nil
+}
+
+func withTx(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+	tx, err := db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func quoteAll(names []string) []string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = pq.QuoteIdentifier(name)
+	}
+	return quoted
+}
@@ -0,0 +1,412 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+var _ store.MirrorStore = (*MirrorStore)(nil)
+
+// NewMirrorStore returns a new MirrorStore.
+func NewMirrorStore(db *sqlx.DB) *MirrorStore {
+	return &MirrorStore{
+		db: db,
+	}
+}
+
+// MirrorStore implements store.MirrorStore backed by a relational database.
+type MirrorStore struct {
+	db *sqlx.DB
+}
+
+// mirror is an internal representation used to store mirror data in the database.
+type mirror struct {
+	ID                  int64                `db:"mirror_id"`
+	Version             int64                `db:"mirror_version"`
+	RepoID              int64                `db:"mirror_repo_id"`
+	Identifier          string               `db:"mirror_uid"`
+	Direction           enum.MirrorDirection `db:"mirror_direction"`
+	RemoteURL           string               `db:"mirror_remote_url"`
+	Username            string               `db:"mirror_username"`
+	SecretIdentifier    string               `db:"mirror_secret_identifier"`
+	ConnectorIdentifier string               `db:"mirror_connector_identifier"`
+	Interval            int64                `db:"mirror_interval"`
+	Enabled             bool                 `db:"mirror_enabled"`
+	NextSync            int64                `db:"mirror_next_sync"`
+	SyncState           enum.MirrorSyncState `db:"mirror_sync_state"`
+	LastSync            int64                `db:"mirror_last_sync"`
+	LastError           string               `db:"mirror_last_error"`
+	CreatedBy           int64                `db:"mirror_created_by"`
+	Created             int64                `db:"mirror_created"`
+	Updated             int64                `db:"mirror_updated"`
+}
+
+const (
+	mirrorColumns = `
+		 mirror_id
+		,mirror_version
+		,mirror_repo_id
+		,mirror_uid
+		,mirror_direction
+		,mirror_remote_url
+		,mirror_username
+		,mirror_secret_identifier
+		,mirror_connector_identifier
+		,mirror_interval
+		,mirror_enabled
+		,mirror_next_sync
+		,mirror_sync_state
+		,mirror_last_sync
+		,mirror_last_error
+		,mirror_created_by
+		,mirror_created
+		,mirror_updated`
+
+	mirrorSelectBase = `
+	SELECT` + mirrorColumns + `
+	FROM mirrors`
+)
+
+// Find finds the mirror by id.
+func (s *MirrorStore) Find(ctx context.Context, id int64) (*types.Mirror, error) {
+	const sqlQuery = mirrorSelectBase + `
+		WHERE mirror_id = $1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &mirror{}
+	if err := db.GetContext(ctx, dst, sqlQuery, id); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Select query failed")
+	}
+
+	return mapToMirror(dst), nil
+}
+
+// FindByIdentifier finds the mirror of a repository by its identifier.
+func (s *MirrorStore) FindByIdentifier(
+	ctx context.Context,
+	repoID int64,
+	identifier string,
+) (*types.Mirror, error) {
+	const sqlQuery = mirrorSelectBase + `
+		WHERE mirror_repo_id = $1 AND LOWER(mirror_uid) = $2`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &mirror{}
+	if err := db.GetContext(ctx, dst, sqlQuery, repoID, strings.ToLower(identifier)); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Select query failed")
+	}
+
+	return mapToMirror(dst), nil
+}
+
+// Create creates a new mirror.
+func (s *MirrorStore) Create(ctx context.Context, m *types.Mirror) error {
+	const sqlQuery = `
+		INSERT INTO mirrors (
+			 mirror_version
+			,mirror_repo_id
+			,mirror_uid
+			,mirror_direction
+			,mirror_remote_url
+			,mirror_username
+			,mirror_secret_identifier
+			,mirror_connector_identifier
+			,mirror_interval
+			,mirror_enabled
+			,mirror_next_sync
+			,mirror_sync_state
+			,mirror_last_sync
+			,mirror_last_error
+			,mirror_created_by
+			,mirror_created
+			,mirror_updated
+		) values (
+			 :mirror_version
+			,:mirror_repo_id
+			,:mirror_uid
+			,:mirror_direction
+			,:mirror_remote_url
+			,:mirror_username
+			,:mirror_secret_identifier
+			,:mirror_connector_identifier
+			,:mirror_interval
+			,:mirror_enabled
+			,:mirror_next_sync
+			,:mirror_sync_state
+			,:mirror_last_sync
+			,:mirror_last_error
+			,:mirror_created_by
+			,:mirror_created
+			,:mirror_updated
+		) RETURNING mirror_id`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, mapToInternalMirror(m))
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to bind mirror object")
+	}
+
+	if err = db.QueryRowContext(ctx, query, arg...).Scan(&m.ID); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Insert query failed")
+	}
+
+	return nil
+}
+
+// Update updates an existing mirror.
+func (s *MirrorStore) Update(ctx context.Context, m *types.Mirror) error {
+	const sqlQuery = `
+		UPDATE mirrors
+		SET
+			 mirror_version = :mirror_version
+			,mirror_updated = :mirror_updated
+			,mirror_uid = :mirror_uid
+			,mirror_remote_url = :mirror_remote_url
+			,mirror_username = :mirror_username
+			,mirror_secret_identifier = :mirror_secret_identifier
+			,mirror_connector_identifier = :mirror_connector_identifier
+			,mirror_interval = :mirror_interval
+			,mirror_enabled = :mirror_enabled
+			,mirror_next_sync = :mirror_next_sync
+			,mirror_sync_state = :mirror_sync_state
+			,mirror_last_sync = :mirror_last_sync
+			,mirror_last_error = :mirror_last_error
+		WHERE mirror_id = :mirror_id AND mirror_version = :mirror_version - 1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dbMirror := mapToInternalMirror(m)
+
+	// update Version (used for optimistic locking) and Updated time
+	dbMirror.Version++
+	dbMirror.Updated = time.Now().UnixMilli()
+
+	query, arg, err := db.BindNamed(sqlQuery, dbMirror)
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to bind mirror object")
+	}
+
+	result, err := db.ExecContext(ctx, query, arg...)
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to update mirror")
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to get number of updated rows")
+	}
+
+	if count == 0 {
+		return gitness_store.ErrVersionConflict
+	}
+
+	m.Version = dbMirror.Version
+	m.Updated = dbMirror.Updated
+
+	return nil
+}
+
+// UpdateOptLock updates the mirror using the optimistic locking mechanism.
+func (s *MirrorStore) UpdateOptLock(
+	ctx context.Context,
+	m *types.Mirror,
+	mutateFn func(mirror *types.Mirror) error,
+) (*types.Mirror, error) {
+	for {
+		dup := *m
+
+		err := mutateFn(&dup)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.Update(ctx, &dup)
+		if err == nil {
+			return &dup, nil
+		}
+		if !errors.Is(err, gitness_store.ErrVersionConflict) {
+			return nil, err
+		}
+
+		m, err = s.Find(ctx, m.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Delete deletes the mirror with the given id.
+func (s *MirrorStore) Delete(ctx context.Context, id int64) error {
+	const sqlQuery = `
+		DELETE FROM mirrors
+		WHERE mirror_id = $1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	if _, err := db.ExecContext(ctx, sqlQuery, id); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "The delete query failed")
+	}
+
+	return nil
+}
+
+// List lists all mirrors of a repository, optionally filtered by direction.
+func (s *MirrorStore) List(
+	ctx context.Context,
+	repoID int64,
+	direction enum.MirrorDirection,
+) ([]*types.Mirror, error) {
+	stmt := database.Builder.
+		Select(mirrorColumns).
+		From("mirrors").
+		Where("mirror_repo_id = ?", repoID).
+		OrderBy("mirror_uid ASC")
+
+	if direction != "" {
+		stmt = stmt.Where("mirror_direction = ?", direction)
+	}
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := []*mirror{}
+	if err = db.SelectContext(ctx, &dst, sql, args...); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed executing list mirrors query")
+	}
+
+	return mapToMirrors(dst), nil
+}
+
+// ListPullDue lists all enabled pull mirrors that are due to be synchronized at the provided time.
+func (s *MirrorStore) ListPullDue(ctx context.Context, now int64) ([]*types.Mirror, error) {
+	stmt := database.Builder.
+		Select(mirrorColumns).
+		From("mirrors").
+		Where("mirror_direction = ? AND mirror_enabled = true", enum.MirrorDirectionPull).
+		Where("mirror_next_sync > 0 AND mirror_next_sync <= ?", now).
+		OrderBy("mirror_next_sync ASC")
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := []*mirror{}
+	if err = db.SelectContext(ctx, &dst, sql, args...); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed executing list due pull mirrors query")
+	}
+
+	return mapToMirrors(dst), nil
+}
+
+// UpdateNextSync moves the next synchronization of a mirror from prev to next.
+// Returns false if the next synchronization was already moved by someone else.
+func (s *MirrorStore) UpdateNextSync(ctx context.Context, id int64, prev, next int64) (bool, error) {
+	const sqlQuery = `
+		UPDATE mirrors
+		SET mirror_next_sync = $1
+		WHERE mirror_id = $2 AND mirror_next_sync = $3`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	result, err := db.ExecContext(ctx, sqlQuery, next, id, prev)
+	if err != nil {
+		return false, database.ProcessSQLErrorf(ctx, err, "Failed to update next sync of mirror")
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, database.ProcessSQLErrorf(ctx, err, "Failed to get number of updated rows")
+	}
+
+	return count > 0, nil
+}
+
+func mapToMirror(m *mirror) *types.Mirror {
+	return &types.Mirror{
+		ID:                  m.ID,
+		Version:             m.Version,
+		RepoID:              m.RepoID,
+		Identifier:          m.Identifier,
+		Direction:           m.Direction,
+		RemoteURL:           m.RemoteURL,
+		Username:            m.Username,
+		SecretIdentifier:    m.SecretIdentifier,
+		ConnectorIdentifier: m.ConnectorIdentifier,
+		Interval:            m.Interval,
+		Enabled:             m.Enabled,
+		NextSync:            m.NextSync,
+		SyncState:           m.SyncState,
+		LastSync:            m.LastSync,
+		LastError:           m.LastError,
+		CreatedBy:           m.CreatedBy,
+		Created:             m.Created,
+		Updated:             m.Updated,
+	}
+}
+
+func mapToMirrors(ms []*mirror) []*types.Mirror {
+	res := make([]*types.Mirror, len(ms))
+	for i, m := range ms {
+		res[i] = mapToMirror(m)
+	}
+	return res
+}
+
+func mapToInternalMirror(m *types.Mirror) *mirror {
+	return &mirror{
+		ID:                  m.ID,
+		Version:             m.Version,
+		RepoID:              m.RepoID,
+		Identifier:          m.Identifier,
+		Direction:           m.Direction,
+		RemoteURL:           m.RemoteURL,
+		Username:            m.Username,
+		SecretIdentifier:    m.SecretIdentifier,
+		ConnectorIdentifier: m.ConnectorIdentifier,
+		Interval:            m.Interval,
+		Enabled:             m.Enabled,
+		NextSync:            m.NextSync,
+		SyncState:           m.SyncState,
+		LastSync:            m.LastSync,
+		LastError:           m.LastError,
+		CreatedBy:           m.CreatedBy,
+		Created:             m.Created,
+		Updated:             m.Updated,
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ store.OIDCIdentityStore = (*OIDCIdentityStore)(nil)
+
+func NewOIDCIdentityStore(db *sqlx.DB) *OIDCIdentityStore {
+	return &OIDCIdentityStore{
+		db: db,
+	}
+}
+
+// OIDCIdentityStore implements store.OIDCIdentityStore backed by a relational database.
+type OIDCIdentityStore struct {
+	db *sqlx.DB
+}
+
+type oidcIdentity struct {
+	Issuer      string `db:"oidc_identity_issuer"`
+	Subject     string `db:"oidc_identity_subject"`
+	PrincipalID int64  `db:"oidc_identity_principal_id"`
+	Created     int64  `db:"oidc_identity_created"`
+	LastLogin   int64  `db:"oidc_identity_last_login"`
+}
+
+const (
+	oidcIdentityColumns = `
+		 oidc_identity_issuer
+		,oidc_identity_subject
+		,oidc_identity_principal_id
+		,oidc_identity_created
+		,oidc_identity_last_login`
+
+	oidcIdentitySelectBase = `
+	SELECT` + oidcIdentityColumns + `
+	FROM oidc_identities`
+)
+
+// Find returns the identity given the issuer and the subject.
+func (s *OIDCIdentityStore) Find(ctx context.Context, issuer, subject string) (*types.OIDCIdentity, error) {
+	const sqlQuery = oidcIdentitySelectBase + `
+	WHERE oidc_identity_issuer = $1 AND oidc_identity_subject = $2`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &oidcIdentity{}
+	if err := db.GetContext(ctx, dst, sqlQuery, issuer, subject); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to find oidc identity")
+	}
+
+	return mapOIDCIdentity(dst), nil
+}
+
+// Create links a new identity to a principal.
+func (s *OIDCIdentityStore) Create(ctx context.Context, identity *types.OIDCIdentity) error {
+	const sqlQuery = `
+		INSERT INTO oidc_identities (
+			 oidc_identity_issuer
+			,oidc_identity_subject
+			,oidc_identity_principal_id
+			,oidc_identity_created
+			,oidc_identity_last_login
+		) VALUES (
+			 :oidc_identity_issuer
+			,:oidc_identity_subject
+			,:oidc_identity_principal_id
+			,:oidc_identity_created
+			,:oidc_identity_last_login
+		)`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, mapInternalOIDCIdentity(identity))
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to bind oidc identity object")
+	}
+
+	if _, err = db.ExecContext(ctx, query, arg...); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to insert oidc identity")
+	}
+
+	return nil
+}
+
+// UpdateLastLogin updates the time of the last login with the identity.
+func (s *OIDCIdentityStore) UpdateLastLogin(ctx context.Context, issuer, subject string, lastLogin int64) error {
+	const sqlQuery = `
+		UPDATE oidc_identities
+		SET oidc_identity_last_login = $1
+		WHERE oidc_identity_issuer = $2 AND oidc_identity_subject = $3`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	if _, err := db.ExecContext(ctx, sqlQuery, lastLogin, issuer, subject); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to update last login of oidc identity")
+	}
+
+	return nil
+}
+
+func mapInternalOIDCIdentity(identity *types.OIDCIdentity) *oidcIdentity {
+	return &oidcIdentity{
+		Issuer:      identity.Issuer,
+		Subject:     identity.Subject,
+		PrincipalID: identity.PrincipalID,
+		Created:     identity.Created,
+		LastLogin:   identity.LastLogin,
+	}
+}
+
+func mapOIDCIdentity(identity *oidcIdentity) *types.OIDCIdentity {
+	return &types.OIDCIdentity{
+		Issuer:      identity.Issuer,
+		Subject:     identity.Subject,
+		PrincipalID: identity.PrincipalID,
+		Created:     identity.Created,
+		LastLogin:   identity.LastLogin,
+	}
+}
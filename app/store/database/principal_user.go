@@ -37,7 +37,8 @@ type user struct {
 }
 
 const userColumns = principalCommonColumns + `
-	,principal_user_password`
+	,principal_user_password
+	,principal_user_email_verified`
 
 const userSelectBase = `
 	SELECT` + userColumns + `
@@ -111,6 +112,7 @@ func (s *PrincipalStore) CreateUser(ctx context.Context, user *types.User) error
 			,principal_created
 			,principal_updated
 			,principal_user_password
+			,principal_user_email_verified
 		) values (
 			'user'
 			,:principal_uid
@@ -123,6 +125,7 @@ func (s *PrincipalStore) CreateUser(ctx context.Context, user *types.User) error
 			,:principal_created
 			,:principal_updated
 			,:principal_user_password
+			,:principal_user_email_verified
 		) RETURNING principal_id`
 
 	dbUser, err := s.mapToDBUser(user)
@@ -158,6 +161,7 @@ func (s *PrincipalStore) UpdateUser(ctx context.Context, user *types.User) error
 			,principal_salt           = :principal_salt
 			,principal_updated        = :principal_updated
 			,principal_user_password  = :principal_user_password
+			,principal_user_email_verified = :principal_user_email_verified
 		WHERE principal_type = 'user' AND principal_id = :principal_id`
 
 	dbUser, err := s.mapToDBUser(user)
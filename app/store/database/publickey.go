@@ -289,6 +289,10 @@ func (PublicKeyStore) applyQueryFilter(
 		stmt = stmt.Where(PartialMatch("public_key_identifier", filter.Query))
 	}
 
+	if len(filter.Usages) > 0 {
+		stmt = stmt.Where(squirrel.Eq{"public_key_usage": filter.Usages})
+	}
+
 	return stmt
 }
 
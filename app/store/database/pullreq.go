@@ -79,6 +79,8 @@ type pullReq struct {
 	TargetRepoID int64  `db:"pullreq_target_repo_id"`
 	TargetBranch string `db:"pullreq_target_branch"`
 
+	AllowMaintainerEdit bool `db:"pullreq_allow_maintainer_edit"`
+
 	ActivitySeq int64 `db:"pullreq_activity_seq"`
 
 	MergedBy    null.Int    `db:"pullreq_merged_by"`
@@ -120,6 +122,7 @@ const (
 		,pullreq_source_sha
 		,pullreq_target_repo_id
 		,pullreq_target_branch
+		,pullreq_allow_maintainer_edit
 		,pullreq_activity_seq
 		,pullreq_merged_by
 		,pullreq_merged
@@ -219,6 +222,7 @@ func (s *PullReqStore) Create(ctx context.Context, pr *types.PullReq) error {
 		,pullreq_source_sha
 		,pullreq_target_repo_id
 		,pullreq_target_branch
+		,pullreq_allow_maintainer_edit
 		,pullreq_activity_seq
 		,pullreq_merged_by
 		,pullreq_merged
@@ -253,6 +257,7 @@ func (s *PullReqStore) Create(ctx context.Context, pr *types.PullReq) error {
 		,:pullreq_source_sha
 		,:pullreq_target_repo_id
 		,:pullreq_target_branch
+		,:pullreq_allow_maintainer_edit
 		,:pullreq_activity_seq
 		,:pullreq_merged_by
 		,:pullreq_merged
@@ -301,6 +306,7 @@ func (s *PullReqStore) Update(ctx context.Context, pr *types.PullReq) error {
 		,pullreq_description = :pullreq_description
 		,pullreq_activity_seq = :pullreq_activity_seq
 		,pullreq_source_sha = :pullreq_source_sha
+		,pullreq_allow_maintainer_edit = :pullreq_allow_maintainer_edit
 		,pullreq_merged_by = :pullreq_merged_by
 		,pullreq_merged = :pullreq_merged
 		,pullreq_merge_method = :pullreq_merge_method
@@ -761,38 +767,39 @@ func mapPullReq(pr *pullReq) *types.PullReq {
 	}
 
 	return &types.PullReq{
-		ID:                pr.ID,
-		Version:           pr.Version,
-		Number:            pr.Number,
-		CreatedBy:         pr.CreatedBy,
-		Created:           pr.Created,
-		Updated:           pr.Updated,
-		Edited:            pr.Edited, // TODO: When we remove the DB column, make Edited equal to Updated
-		Closed:            pr.Closed.Ptr(),
-		State:             pr.State,
-		IsDraft:           pr.IsDraft,
-		CommentCount:      pr.CommentCount,
-		UnresolvedCount:   pr.UnresolvedCount,
-		Title:             pr.Title,
-		Description:       pr.Description,
-		SourceRepoID:      pr.SourceRepoID,
-		SourceBranch:      pr.SourceBranch,
-		SourceSHA:         pr.SourceSHA,
-		TargetRepoID:      pr.TargetRepoID,
-		TargetBranch:      pr.TargetBranch,
-		ActivitySeq:       pr.ActivitySeq,
-		MergedBy:          pr.MergedBy.Ptr(),
-		Merged:            pr.Merged.Ptr(),
-		MergeMethod:       (*enum.MergeMethod)(pr.MergeMethod.Ptr()),
-		MergeCheckStatus:  pr.MergeCheckStatus,
-		MergeTargetSHA:    pr.MergeTargetSHA.Ptr(),
-		MergeBaseSHA:      pr.MergeBaseSHA,
-		MergeSHA:          pr.MergeSHA.Ptr(),
-		MergeConflicts:    mergeConflicts,
-		RebaseCheckStatus: pr.RebaseCheckStatus,
-		RebaseConflicts:   rebaseConflicts,
-		Author:            types.PrincipalInfo{},
-		Merger:            nil,
+		ID:                  pr.ID,
+		Version:             pr.Version,
+		Number:              pr.Number,
+		CreatedBy:           pr.CreatedBy,
+		Created:             pr.Created,
+		Updated:             pr.Updated,
+		Edited:              pr.Edited, // TODO: When we remove the DB column, make Edited equal to Updated
+		Closed:              pr.Closed.Ptr(),
+		State:               pr.State,
+		IsDraft:             pr.IsDraft,
+		CommentCount:        pr.CommentCount,
+		UnresolvedCount:     pr.UnresolvedCount,
+		Title:               pr.Title,
+		Description:         pr.Description,
+		SourceRepoID:        pr.SourceRepoID,
+		SourceBranch:        pr.SourceBranch,
+		SourceSHA:           pr.SourceSHA,
+		TargetRepoID:        pr.TargetRepoID,
+		TargetBranch:        pr.TargetBranch,
+		AllowMaintainerEdit: pr.AllowMaintainerEdit,
+		ActivitySeq:         pr.ActivitySeq,
+		MergedBy:            pr.MergedBy.Ptr(),
+		Merged:              pr.Merged.Ptr(),
+		MergeMethod:         (*enum.MergeMethod)(pr.MergeMethod.Ptr()),
+		MergeCheckStatus:    pr.MergeCheckStatus,
+		MergeTargetSHA:      pr.MergeTargetSHA.Ptr(),
+		MergeBaseSHA:        pr.MergeBaseSHA,
+		MergeSHA:            pr.MergeSHA.Ptr(),
+		MergeConflicts:      mergeConflicts,
+		RebaseCheckStatus:   pr.RebaseCheckStatus,
+		RebaseConflicts:     rebaseConflicts,
+		Author:              types.PrincipalInfo{},
+		Merger:              nil,
 		Stats: types.PullReqStats{
 			Conversations:   pr.CommentCount,
 			UnresolvedCount: pr.UnresolvedCount,
@@ -810,40 +817,41 @@ func mapInternalPullReq(pr *types.PullReq) *pullReq {
 	mergeConflicts := strings.Join(pr.MergeConflicts, "\n")
 	rebaseConflicts := strings.Join(pr.RebaseConflicts, "\n")
 	m := &pullReq{
-		ID:                pr.ID,
-		Version:           pr.Version,
-		Number:            pr.Number,
-		CreatedBy:         pr.CreatedBy,
-		Created:           pr.Created,
-		Updated:           pr.Updated,
-		Edited:            pr.Edited, // TODO: When we remove the DB column, make Edited equal to Updated
-		Closed:            null.IntFromPtr(pr.Closed),
-		State:             pr.State,
-		IsDraft:           pr.IsDraft,
-		CommentCount:      pr.CommentCount,
-		UnresolvedCount:   pr.UnresolvedCount,
-		Title:             pr.Title,
-		Description:       pr.Description,
-		SourceRepoID:      pr.SourceRepoID,
-		SourceBranch:      pr.SourceBranch,
-		SourceSHA:         pr.SourceSHA,
-		TargetRepoID:      pr.TargetRepoID,
-		TargetBranch:      pr.TargetBranch,
-		ActivitySeq:       pr.ActivitySeq,
-		MergedBy:          null.IntFromPtr(pr.MergedBy),
-		Merged:            null.IntFromPtr(pr.Merged),
-		MergeMethod:       null.StringFromPtr((*string)(pr.MergeMethod)),
-		MergeCheckStatus:  pr.MergeCheckStatus,
-		MergeTargetSHA:    null.StringFromPtr(pr.MergeTargetSHA),
-		MergeBaseSHA:      pr.MergeBaseSHA,
-		MergeSHA:          null.StringFromPtr(pr.MergeSHA),
-		MergeConflicts:    null.NewString(mergeConflicts, mergeConflicts != ""),
-		RebaseCheckStatus: pr.RebaseCheckStatus,
-		RebaseConflicts:   null.NewString(rebaseConflicts, rebaseConflicts != ""),
-		CommitCount:       null.IntFromPtr(pr.Stats.Commits),
-		FileCount:         null.IntFromPtr(pr.Stats.FilesChanged),
-		Additions:         null.IntFromPtr(pr.Stats.Additions),
-		Deletions:         null.IntFromPtr(pr.Stats.Deletions),
+		ID:                  pr.ID,
+		Version:             pr.Version,
+		Number:              pr.Number,
+		CreatedBy:           pr.CreatedBy,
+		Created:             pr.Created,
+		Updated:             pr.Updated,
+		Edited:              pr.Edited, // TODO: When we remove the DB column, make Edited equal to Updated
+		Closed:              null.IntFromPtr(pr.Closed),
+		State:               pr.State,
+		IsDraft:             pr.IsDraft,
+		CommentCount:        pr.CommentCount,
+		UnresolvedCount:     pr.UnresolvedCount,
+		Title:               pr.Title,
+		Description:         pr.Description,
+		SourceRepoID:        pr.SourceRepoID,
+		SourceBranch:        pr.SourceBranch,
+		SourceSHA:           pr.SourceSHA,
+		TargetRepoID:        pr.TargetRepoID,
+		TargetBranch:        pr.TargetBranch,
+		AllowMaintainerEdit: pr.AllowMaintainerEdit,
+		ActivitySeq:         pr.ActivitySeq,
+		MergedBy:            null.IntFromPtr(pr.MergedBy),
+		Merged:              null.IntFromPtr(pr.Merged),
+		MergeMethod:         null.StringFromPtr((*string)(pr.MergeMethod)),
+		MergeCheckStatus:    pr.MergeCheckStatus,
+		MergeTargetSHA:      null.StringFromPtr(pr.MergeTargetSHA),
+		MergeBaseSHA:        pr.MergeBaseSHA,
+		MergeSHA:            null.StringFromPtr(pr.MergeSHA),
+		MergeConflicts:      null.NewString(mergeConflicts, mergeConflicts != ""),
+		RebaseCheckStatus:   pr.RebaseCheckStatus,
+		RebaseConflicts:     null.NewString(rebaseConflicts, rebaseConflicts != ""),
+		CommitCount:         null.IntFromPtr(pr.Stats.Commits),
+		FileCount:           null.IntFromPtr(pr.Stats.FilesChanged),
+		Additions:           null.IntFromPtr(pr.Stats.Additions),
+		Deletions:           null.IntFromPtr(pr.Stats.Deletions),
 	}
 
 	return m
@@ -0,0 +1,161 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ store.PullReqAutoMergeStore = (*PullReqAutoMergeStore)(nil)
+
+// NewPullReqAutoMergeStore returns a new PullReqAutoMergeStore.
+func NewPullReqAutoMergeStore(db *sqlx.DB) *PullReqAutoMergeStore {
+	return &PullReqAutoMergeStore{
+		db: db,
+	}
+}
+
+// PullReqAutoMergeStore implements store.PullReqAutoMergeStore backed by a relational database.
+type PullReqAutoMergeStore struct {
+	db *sqlx.DB
+}
+
+// pullReqAutoMerge is used to fetch pull request auto-merge data from the database.
+type pullReqAutoMerge struct {
+	PullReqID int64            `db:"pullreq_auto_merge_pullreq_id"`
+	RepoID    int64            `db:"pullreq_auto_merge_repo_id"`
+	Method    enum.MergeMethod `db:"pullreq_auto_merge_method"`
+	Title     string           `db:"pullreq_auto_merge_title"`
+	Message   string           `db:"pullreq_auto_merge_message"`
+	CreatedBy int64            `db:"pullreq_auto_merge_created_by"`
+	Created   int64            `db:"pullreq_auto_merge_created"`
+}
+
+const (
+	pullreqAutoMergeColumns = `
+		 pullreq_auto_merge_pullreq_id
+		,pullreq_auto_merge_repo_id
+		,pullreq_auto_merge_method
+		,pullreq_auto_merge_title
+		,pullreq_auto_merge_message
+		,pullreq_auto_merge_created_by
+		,pullreq_auto_merge_created`
+
+	pullreqAutoMergeSelectBase = `
+	SELECT` + pullreqAutoMergeColumns + `
+	FROM pullreq_auto_merges`
+)
+
+// Find returns the auto-merge settings of the pull request.
+func (s *PullReqAutoMergeStore) Find(ctx context.Context, pullreqID int64) (*types.PullReqAutoMerge, error) {
+	const sqlQuery = pullreqAutoMergeSelectBase + `
+	WHERE pullreq_auto_merge_pullreq_id = $1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &pullReqAutoMerge{}
+	if err := db.GetContext(ctx, dst, sqlQuery, pullreqID); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to find pull request auto-merge")
+	}
+
+	return mapPullReqAutoMerge(dst), nil
+}
+
+// Upsert enables auto-merge of the pull request or replaces its existing auto-merge settings.
+func (s *PullReqAutoMergeStore) Upsert(ctx context.Context, v *types.PullReqAutoMerge) error {
+	const sqlQuery = `
+	INSERT INTO pullreq_auto_merges (` + pullreqAutoMergeColumns + `
+	) values (
+		 :pullreq_auto_merge_pullreq_id
+		,:pullreq_auto_merge_repo_id
+		,:pullreq_auto_merge_method
+		,:pullreq_auto_merge_title
+		,:pullreq_auto_merge_message
+		,:pullreq_auto_merge_created_by
+		,:pullreq_auto_merge_created
+	)
+	ON CONFLICT (pullreq_auto_merge_pullreq_id) DO
+	UPDATE SET
+		 pullreq_auto_merge_method = :pullreq_auto_merge_method
+		,pullreq_auto_merge_title = :pullreq_auto_merge_title
+		,pullreq_auto_merge_message = :pullreq_auto_merge_message
+		,pullreq_auto_merge_created_by = :pullreq_auto_merge_created_by
+		,pullreq_auto_merge_created = :pullreq_auto_merge_created`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, mapInternalPullReqAutoMerge(v))
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to bind pull request auto-merge object")
+	}
+
+	if _, err = db.ExecContext(ctx, query, arg...); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to upsert pull request auto-merge")
+	}
+
+	return nil
+}
+
+// Delete disables auto-merge of the pull request.
+func (s *PullReqAutoMergeStore) Delete(ctx context.Context, pullreqID int64) error {
+	const sqlQuery = `
+	DELETE FROM pullreq_auto_merges
+	WHERE pullreq_auto_merge_pullreq_id = $1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	if _, err := db.ExecContext(ctx, sqlQuery, pullreqID); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to delete pull request auto-merge")
+	}
+
+	return nil
+}
+
+// ListByRepo returns auto-merge settings of all pull requests targeting the repository.
+func (s *PullReqAutoMergeStore) ListByRepo(ctx context.Context, repoID int64) ([]*types.PullReqAutoMerge, error) {
+	const sqlQuery = pullreqAutoMergeSelectBase + `
+	WHERE pullreq_auto_merge_repo_id = $1
+	ORDER BY pullreq_auto_merge_created`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := make([]*pullReqAutoMerge, 0)
+	if err := db.SelectContext(ctx, &dst, sqlQuery, repoID); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to list pull request auto-merges")
+	}
+
+	result := make([]*types.PullReqAutoMerge, len(dst))
+	for i, v := range dst {
+		result[i] = mapPullReqAutoMerge(v)
+	}
+
+	return result, nil
+}
+
+func mapPullReqAutoMerge(v *pullReqAutoMerge) *types.PullReqAutoMerge {
+	return (*types.PullReqAutoMerge)(v) // the two types are identical, except for the tags
+}
+
+func mapInternalPullReqAutoMerge(v *types.PullReqAutoMerge) *pullReqAutoMerge {
+	return (*pullReqAutoMerge)(v) // the two types are identical, except for the tags
+}
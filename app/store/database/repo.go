@@ -719,6 +719,29 @@ type repoSize struct {
 	SizeUpdated int64  `db:"repo_size_updated"`
 }
 
+// ListForks returns all forks of a repo, including the deleted ones.
+func (s *RepoStore) ListForks(ctx context.Context, repoID int64) ([]*types.Repository, error) {
+	stmt := database.Builder.
+		Select(repoColumnsForJoin).
+		From("repositories").
+		Where("repo_fork_id = ?", repoID).
+		OrderBy("repo_id")
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to convert query to sql")
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := []*repository{}
+	if err = db.SelectContext(ctx, &dst, sql, args...); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed executing list forks query")
+	}
+
+	return s.mapToRepos(ctx, dst)
+}
+
 func (s *RepoStore) ListSizeInfos(ctx context.Context) ([]*types.RepositorySizeInfo, error) {
 	stmt := database.Builder.
 		Select("repo_id", "repo_git_uid", "repo_size", "repo_size_updated").
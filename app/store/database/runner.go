@@ -0,0 +1,253 @@
+// Copyright 2023 Harness, Inc.
+//
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+
+	"github.com/jmoiron/sqlx"
+	sqlxtypes "github.com/jmoiron/sqlx/types"
+)
+
+var _ store.RunnerStore = (*RunnerStore)(nil)
+
+func NewRunnerStore(db *sqlx.DB) *RunnerStore {
+	return &RunnerStore{
+		db: db,
+	}
+}
+
+// RunnerStore implements store.RunnerStore backed by a relational database.
+type RunnerStore struct {
+	db *sqlx.DB
+}
+
+type runner struct {
+	ID          int64              `db:"runner_id"`
+	Identifier  string             `db:"runner_identifier"`
+	Description string             `db:"runner_description"`
+	TokenHash   string             `db:"runner_token_hash"`
+	OS          string             `db:"runner_os"`
+	Arch        string             `db:"runner_arch"`
+	Labels      sqlxtypes.JSONText `db:"runner_labels"`
+	LastSeen    int64              `db:"runner_last_seen"`
+	CreatedBy   int64              `db:"runner_created_by"`
+	Created     int64              `db:"runner_created"`
+	Updated     int64              `db:"runner_updated"`
+}
+
+const (
+	runnerColumns = `
+		 runner_id
+		,runner_identifier
+		,runner_description
+		,runner_token_hash
+		,runner_os
+		,runner_arch
+		,runner_labels
+		,runner_last_seen
+		,runner_created_by
+		,runner_created
+		,runner_updated`
+
+	runnerSelectBase = `
+	SELECT` + runnerColumns + `
+	FROM runners`
+)
+
+// Find returns the runner with the given id.
+func (s *RunnerStore) Find(ctx context.Context, id int64) (*types.Runner, error) {
+	const sqlQuery = runnerSelectBase + `
+	WHERE runner_id = $1`
+
+	return s.find(ctx, sqlQuery, id)
+}
+
+// FindByIdentifier returns the runner with the given identifier.
+func (s *RunnerStore) FindByIdentifier(ctx context.Context, identifier string) (*types.Runner, error) {
+	const sqlQuery = runnerSelectBase + `
+	WHERE LOWER(runner_identifier) = LOWER($1)`
+
+	return s.find(ctx, sqlQuery, identifier)
+}
+
+// FindByTokenHash returns the runner with the given token hash.
+func (s *RunnerStore) FindByTokenHash(ctx context.Context, tokenHash string) (*types.Runner, error) {
+	const sqlQuery = runnerSelectBase + `
+	WHERE runner_token_hash = $1`
+
+	return s.find(ctx, sqlQuery, tokenHash)
+}
+
+func (s *RunnerStore) find(ctx context.Context, sqlQuery string, arg any) (*types.Runner, error) {
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &runner{}
+	if err := db.GetContext(ctx, dst, sqlQuery, arg); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to find runner")
+	}
+
+	return mapRunner(dst)
+}
+
+// List returns all runners ordered by identifier.
+func (s *RunnerStore) List(ctx context.Context) ([]*types.Runner, error) {
+	const sqlQuery = runnerSelectBase + `
+	ORDER BY LOWER(runner_identifier)`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := []*runner{}
+	if err := db.SelectContext(ctx, &dst, sqlQuery); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to list runners")
+	}
+
+	runners := make([]*types.Runner, len(dst))
+	for i, r := range dst {
+		var err error
+		if runners[i], err = mapRunner(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return runners, nil
+}
+
+// Create creates a new runner that authenticates with the token of the given hash.
+func (s *RunnerStore) Create(ctx context.Context, r *types.Runner, tokenHash string) error {
+	const sqlQuery = `
+		INSERT INTO runners (
+			 runner_identifier
+			,runner_description
+			,runner_token_hash
+			,runner_os
+			,runner_arch
+			,runner_labels
+			,runner_last_seen
+			,runner_created_by
+			,runner_created
+			,runner_updated
+		) VALUES (
+			 :runner_identifier
+			,:runner_description
+			,:runner_token_hash
+			,:runner_os
+			,:runner_arch
+			,:runner_labels
+			,:runner_last_seen
+			,:runner_created_by
+			,:runner_created
+			,:runner_updated
+		) RETURNING runner_id`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	in := mapInternalRunner(r)
+	in.TokenHash = tokenHash
+
+	query, arg, err := db.BindNamed(sqlQuery, in)
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to bind runner object")
+	}
+
+	if err = db.QueryRowContext(ctx, query, arg...).Scan(&r.ID); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to insert runner")
+	}
+
+	return nil
+}
+
+// UpdateHeartbeat stores the platform and the labels the runner reported and the time it was last seen.
+func (s *RunnerStore) UpdateHeartbeat(ctx context.Context, id int64, heartbeat *types.RunnerHeartbeat) error {
+	const sqlQuery = `
+		UPDATE runners
+		SET
+			 runner_os = $1
+			,runner_arch = $2
+			,runner_labels = $3
+			,runner_last_seen = $4
+		WHERE runner_id = $5`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	_, err := db.ExecContext(ctx, sqlQuery,
+		heartbeat.OS,
+		heartbeat.Arch,
+		EncodeToSQLXJSON(heartbeat.Labels),
+		heartbeat.Time,
+		id,
+	)
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to update runner heartbeat")
+	}
+
+	return nil
+}
+
+// Delete deletes the runner with the given id.
+func (s *RunnerStore) Delete(ctx context.Context, id int64) error {
+	const sqlQuery = `
+		DELETE FROM runners
+		WHERE runner_id = $1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	if _, err := db.ExecContext(ctx, sqlQuery, id); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to delete runner")
+	}
+
+	return nil
+}
+
+func mapInternalRunner(r *types.Runner) *runner {
+	return &runner{
+		ID:          r.ID,
+		Identifier:  r.Identifier,
+		Description: r.Description,
+		OS:          r.OS,
+		Arch:        r.Arch,
+		Labels:      EncodeToSQLXJSON(r.Labels),
+		LastSeen:    r.LastSeen,
+		CreatedBy:   r.CreatedBy,
+		Created:     r.Created,
+		Updated:     r.Updated,
+	}
+}
+
+func mapRunner(r *runner) (*types.Runner, error) {
+	var labels map[string]string
+	if err := json.Unmarshal(r.Labels, &labels); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal runner labels: %w", err)
+	}
+
+	return &types.Runner{
+		ID:          r.ID,
+		Identifier:  r.Identifier,
+		Description: r.Description,
+		OS:          r.OS,
+		Arch:        r.Arch,
+		Labels:      labels,
+		LastSeen:    r.LastSeen,
+		CreatedBy:   r.CreatedBy,
+		Created:     r.Created,
+		Updated:     r.Updated,
+	}, nil
+}
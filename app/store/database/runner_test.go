@@ -0,0 +1,79 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/harness/gitness/app/store/database"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunnerStore(t *testing.T) {
+	db, teardown := setupDB(t)
+	defer teardown()
+
+	principalStore, _, _, _ := setupStores(t, db)
+
+	ctx := context.Background()
+
+	createUser(ctx, t, principalStore)
+
+	runnerStore := database.NewRunnerStore(db)
+
+	r := &types.Runner{
+		Identifier: "Linux-1",
+		CreatedBy:  userID,
+		Created:    1000,
+		Updated:    1000,
+	}
+	require.NoError(t, runnerStore.Create(ctx, r, "hash"))
+	require.NotZero(t, r.ID)
+
+	err := runnerStore.Create(ctx, &types.Runner{Identifier: "linux-1", CreatedBy: userID}, "other")
+	require.ErrorIs(t, err, gitness_store.ErrDuplicate)
+
+	err = runnerStore.UpdateHeartbeat(ctx, r.ID, &types.RunnerHeartbeat{
+		OS:     "linux",
+		Arch:   "arm64",
+		Labels: map[string]string{"gpu": "true"},
+		Time:   2000,
+	})
+	require.NoError(t, err)
+
+	found, err := runnerStore.FindByTokenHash(ctx, "hash")
+	require.NoError(t, err)
+	require.Equal(t, "Linux-1", found.Identifier)
+	require.Equal(t, "arm64", found.Arch)
+	require.Equal(t, map[string]string{"gpu": "true"}, found.Labels)
+	require.Equal(t, int64(2000), found.LastSeen)
+
+	found, err = runnerStore.FindByIdentifier(ctx, "LINUX-1")
+	require.NoError(t, err)
+	require.Equal(t, r.ID, found.ID)
+
+	require.NoError(t, runnerStore.Delete(ctx, r.ID))
+
+	_, err = runnerStore.Find(ctx, r.ID)
+	require.ErrorIs(t, err, gitness_store.ErrResourceNotFound)
+
+	runners, err := runnerStore.List(ctx)
+	require.NoError(t, err)
+	require.Empty(t, runners)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
 
+	"github.com/guregu/null"
 	"github.com/jmoiron/sqlx"
 	sqlxtypes "github.com/jmoiron/sqlx/types"
 )
@@ -61,6 +62,11 @@ const (
 	,stage_on_failure
 	,stage_depends_on
 	,stage_labels
+	,stage_approval_required
+	,stage_approval_by
+	,stage_approval_decided
+	,stage_runner_id
+	,stage_runner_offer_expires
 	`
 )
 
@@ -93,6 +99,13 @@ type stage struct {
 	OnFailure     bool               `db:"stage_on_failure"`
 	DependsOn     sqlxtypes.JSONText `db:"stage_depends_on"`
 	Labels        sqlxtypes.JSONText `db:"stage_labels"`
+
+	ApprovalRequired bool     `db:"stage_approval_required"`
+	ApprovalBy       null.Int `db:"stage_approval_by"`
+	ApprovalDecided  int64    `db:"stage_approval_decided"`
+
+	RunnerID           null.Int `db:"stage_runner_id"`
+	RunnerOfferExpires int64    `db:"stage_runner_offer_expires"`
 }
 
 // NewStageStore returns a new StageStore.
@@ -152,6 +165,9 @@ func (s *stageStore) Create(ctx context.Context, st *types.Stage) error {
 			,stage_on_failure
 			,stage_depends_on
 			,stage_labels
+			,stage_approval_required
+			,stage_approval_by
+			,stage_approval_decided
 		) VALUES (
 			:stage_execution_id
 			,:stage_repo_id
@@ -180,7 +196,9 @@ func (s *stageStore) Create(ctx context.Context, st *types.Stage) error {
 			,:stage_on_failure
 			,:stage_depends_on
 			,:stage_labels
-
+			,:stage_approval_required
+			,:stage_approval_by
+			,:stage_approval_decided
 		) RETURNING stage_id`
 	db := dbtx.GetAccessor(ctx, s.db)
 
@@ -192,6 +210,7 @@ func (s *stageStore) Create(ctx context.Context, st *types.Stage) error {
 	if err = db.QueryRowContext(ctx, query, arg...).Scan(&stage.ID); err != nil {
 		return database.ProcessSQLErrorf(ctx, err, "Stage query failed")
 	}
+	st.ID = stage.ID
 	return nil
 }
 
@@ -269,7 +288,8 @@ func (s *stageStore) List(ctx context.Context, executionID int64) ([]*types.Stag
 }
 
 // Update tries to update a stage in the datastore and returns a locking error
-// if it was unable to do so.
+// if it was unable to do so. A recorded approval decision is never cleared, as the
+// stages reported by runners don't carry it.
 func (s *stageStore) Update(ctx context.Context, st *types.Stage) error {
 	const stageUpdateStmt = `
 	UPDATE stages
@@ -287,6 +307,11 @@ func (s *stageStore) Update(ctx context.Context, st *types.Stage) error {
 		,stage_errignore = :stage_errignore
 		,stage_depends_on = :stage_depends_on
 		,stage_labels = :stage_labels
+		,stage_approval_by = COALESCE(:stage_approval_by, stage_approval_by)
+		,stage_approval_decided = CASE
+			WHEN CAST(:stage_approval_decided AS BIGINT) > 0 THEN :stage_approval_decided
+			ELSE stage_approval_decided
+		END
 	WHERE stage_id = :stage_id AND stage_version = :stage_version - 1`
 	updatedAt := time.Now()
 	steps := st.Steps
@@ -327,3 +352,37 @@ func (s *stageStore) Update(ctx context.Context, st *types.Stage) error {
 	st.Steps = steps // steps is not mapped in database.
 	return nil
 }
+
+// Offer records that the unassigned stage was offered to the remote runner until the given time.
+// It returns a not found error if the stage doesn't exist or is already assigned.
+func (s *stageStore) Offer(ctx context.Context, stageID, runnerID, expires int64) error {
+	stmt := database.Builder.
+		Update("stages").
+		Set("stage_runner_id", runnerID).
+		Set("stage_runner_offer_expires", expires).
+		Where("stage_id = ?", stageID).
+		Where("stage_machine = ''")
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to convert query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	result, err := db.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to offer stage")
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to get number of updated rows")
+	}
+
+	if count == 0 {
+		return gitness_store.ErrResourceNotFound
+	}
+
+	return nil
+}
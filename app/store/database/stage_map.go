@@ -22,6 +22,7 @@ import (
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
 
+	"github.com/guregu/null"
 	sqlxtypes "github.com/jmoiron/sqlx/types"
 	"github.com/pkg/errors"
 )
@@ -110,6 +111,13 @@ func mapInternalToStage(in *stage) (*types.Stage, error) {
 		OnFailure:   in.OnFailure,
 		DependsOn:   dependsOn,
 		Labels:      labels,
+
+		ApprovalRequired: in.ApprovalRequired,
+		ApprovalBy:       in.ApprovalBy.Ptr(),
+		ApprovalDecided:  in.ApprovalDecided,
+
+		RunnerID:           in.RunnerID.Ptr(),
+		RunnerOfferExpires: in.RunnerOfferExpires,
 	}, nil
 }
 
@@ -142,6 +150,13 @@ func mapStageToInternal(in *types.Stage) *stage {
 		OnFailure:   in.OnFailure,
 		DependsOn:   EncodeToSQLXJSON(in.DependsOn),
 		Labels:      EncodeToSQLXJSON(in.Labels),
+
+		ApprovalRequired: in.ApprovalRequired,
+		ApprovalBy:       null.IntFromPtr(in.ApprovalBy),
+		ApprovalDecided:  in.ApprovalDecided,
+
+		RunnerID:           null.IntFromPtr(in.RunnerID),
+		RunnerOfferExpires: in.RunnerOfferExpires,
 	}
 }
 
@@ -192,6 +207,8 @@ func scanRowStep(rows *sql.Rows, stage *types.Stage, step *nullstep) error {
 	depJSON := sqlxtypes.JSONText{}
 	labJSON := sqlxtypes.JSONText{}
 	stepDepJSON := sqlxtypes.JSONText{}
+	approvalBy := null.Int{}
+	runnerID := null.Int{}
 	err := rows.Scan(
 		&stage.ID,
 		&stage.ExecutionID,
@@ -220,6 +237,11 @@ func scanRowStep(rows *sql.Rows, stage *types.Stage, step *nullstep) error {
 		&stage.OnFailure,
 		&depJSON,
 		&labJSON,
+		&stage.ApprovalRequired,
+		&approvalBy,
+		&stage.ApprovalDecided,
+		&runnerID,
+		&stage.RunnerOfferExpires,
 		&step.ID,
 		&step.StageID,
 		&step.Number,
@@ -239,6 +261,8 @@ func scanRowStep(rows *sql.Rows, stage *types.Stage, step *nullstep) error {
 	if err != nil {
 		return fmt.Errorf("failed to scan row: %w", err)
 	}
+	stage.ApprovalBy = approvalBy.Ptr()
+	stage.RunnerID = runnerID.Ptr()
 	err = json.Unmarshal(depJSON, &stage.DependsOn)
 	if err != nil {
 		return fmt.Errorf("failed to unmarshal depJSON: %w", err)
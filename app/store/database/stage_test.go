@@ -0,0 +1,93 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/harness/gitness/app/store/database"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStageStore_Offer(t *testing.T) {
+	db, teardown := setupDB(t)
+	defer teardown()
+
+	principalStore, spaceStore, spacePathStore, repoStore := setupStores(t, db)
+
+	ctx := context.Background()
+
+	createUser(ctx, t, principalStore)
+	createSpace(ctx, t, spaceStore, spacePathStore, userID, 1, 0)
+	createRepo(ctx, t, repoStore, 1, 1, 0)
+
+	pipelineStore := database.NewPipelineStore(db)
+	executionStore := database.NewExecutionStore(db)
+	stageStore := database.NewStageStore(db)
+
+	pipeline := &types.Pipeline{
+		Identifier:    "pipeline",
+		RepoID:        1,
+		CreatedBy:     userID,
+		DefaultBranch: "main",
+		ConfigPath:    ".harness/pipeline.yaml",
+	}
+	require.NoError(t, pipelineStore.Create(ctx, pipeline))
+
+	execution := &types.Execution{
+		PipelineID: pipeline.ID,
+		RepoID:     1,
+		CreatedBy:  userID,
+		Number:     1,
+		Status:     enum.CIStatusPending,
+	}
+	require.NoError(t, executionStore.Create(ctx, execution))
+
+	stage := &types.Stage{
+		ExecutionID: execution.ID,
+		RepoID:      1,
+		Number:      1,
+		Name:        "build",
+		Status:      enum.CIStatusPending,
+	}
+	require.NoError(t, stageStore.Create(ctx, stage))
+
+	require.NoError(t, stageStore.Offer(ctx, stage.ID, 7, 5000))
+
+	found, err := stageStore.Find(ctx, stage.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found.RunnerID)
+	require.Equal(t, int64(7), *found.RunnerID)
+	require.Equal(t, int64(5000), found.RunnerOfferExpires)
+
+	// updates of the execution manager keep the offer.
+	found.Machine = "runner-7"
+	require.NoError(t, stageStore.Update(ctx, found))
+
+	stages, err := stageStore.ListWithSteps(ctx, execution.ID)
+	require.NoError(t, err)
+	require.Len(t, stages, 1)
+	require.Equal(t, int64(7), *stages[0].RunnerID)
+	require.Equal(t, "runner-7", stages[0].Machine)
+
+	// assigned stages can't be offered anymore.
+	err = stageStore.Offer(ctx, stage.ID, 8, 6000)
+	require.ErrorIs(t, err, gitness_store.ErrResourceNotFound)
+}
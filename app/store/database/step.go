@@ -81,6 +81,21 @@ type stepStore struct {
 	db *sqlx.DB
 }
 
+// Find returns a step given its ID.
+func (s *stepStore) Find(ctx context.Context, id int64) (*types.Step, error) {
+	const findQueryStmt = `
+		SELECT` + stepColumns + `
+		FROM steps
+		WHERE step_id = $1`
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := new(step)
+	if err := db.GetContext(ctx, dst, findQueryStmt, id); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to find step")
+	}
+	return mapInternalToStep(dst)
+}
+
 // FindByNumber returns a step given a stage ID and a step number.
 func (s *stepStore) FindByNumber(ctx context.Context, stageID int64, stepNum int) (*types.Step, error) {
 	const findQueryStmt = `
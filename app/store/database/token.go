@@ -16,6 +16,7 @@ package database
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
+	sqlxtypes "github.com/jmoiron/sqlx/types"
 )
 
 var _ store.TokenStore = (*TokenStore)(nil)
@@ -42,23 +44,37 @@ type TokenStore struct {
 	db *sqlx.DB
 }
 
+// token is the database representation of a token.
+type token struct {
+	types.Token
+	ScopeJSON sqlxtypes.NullJSONText `db:"token_scope"`
+}
+
+// tokenScope is the stored scope of a token. Only the IDs are stored, so the scope follows renamed
+// spaces and repos, and a new resource created under the old path doesn't inherit the access.
+type tokenScope struct {
+	Permissions []enum.Permission `json:"permissions,omitempty"`
+	SpaceIDs    []int64           `json:"space_ids,omitempty"`
+	RepoIDs     []int64           `json:"repo_ids,omitempty"`
+}
+
 // Find finds the token by id.
 func (s *TokenStore) Find(ctx context.Context, id int64) (*types.Token, error) {
 	db := dbtx.GetAccessor(ctx, s.db)
 
-	dst := new(types.Token)
+	dst := new(token)
 	if err := db.GetContext(ctx, dst, TokenSelectByID, id); err != nil {
 		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to find token")
 	}
 
-	return dst, nil
+	return mapToToken(dst)
 }
 
 // FindByIdentifier finds the token by principalId and token identifier.
 func (s *TokenStore) FindByIdentifier(ctx context.Context, principalID int64, identifier string) (*types.Token, error) {
 	db := dbtx.GetAccessor(ctx, s.db)
 
-	dst := new(types.Token)
+	dst := new(token)
 	if err := db.GetContext(
 		ctx,
 		dst,
@@ -69,19 +85,19 @@ func (s *TokenStore) FindByIdentifier(ctx context.Context, principalID int64, id
 		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to find token by identifier")
 	}
 
-	return dst, nil
+	return mapToToken(dst)
 }
 
 // Create saves the token details.
-func (s *TokenStore) Create(ctx context.Context, token *types.Token) error {
+func (s *TokenStore) Create(ctx context.Context, tkn *types.Token) error {
 	db := dbtx.GetAccessor(ctx, s.db)
 
-	query, arg, err := db.BindNamed(tokenInsert, token)
+	query, arg, err := db.BindNamed(tokenInsert, mapToInternalToken(tkn))
 	if err != nil {
 		return database.ProcessSQLErrorf(ctx, err, "Failed to bind token object")
 	}
 
-	if err = db.QueryRowContext(ctx, query, arg...).Scan(&token.ID); err != nil {
+	if err = db.QueryRowContext(ctx, query, arg...).Scan(&tkn.ID); err != nil {
 		return database.ProcessSQLErrorf(ctx, err, "Insert query failed")
 	}
 
@@ -153,7 +169,7 @@ func (s *TokenStore) List(ctx context.Context,
 	principalID int64, tokenType enum.TokenType) ([]*types.Token, error) {
 	db := dbtx.GetAccessor(ctx, s.db)
 
-	dst := []*types.Token{}
+	dst := []*token{}
 
 	// TODO: custom filters / sorting for tokens.
 
@@ -161,7 +177,51 @@ func (s *TokenStore) List(ctx context.Context,
 	if err != nil {
 		return nil, database.ProcessSQLErrorf(ctx, err, "Failed executing token list query")
 	}
-	return dst, nil
+
+	tokens := make([]*types.Token, len(dst))
+	for i, t := range dst {
+		if tokens[i], err = mapToToken(t); err != nil {
+			return nil, err
+		}
+	}
+
+	return tokens, nil
+}
+
+func mapToInternalToken(t *types.Token) *token {
+	dst := &token{Token: *t}
+
+	if t.Scope != nil {
+		dst.ScopeJSON = sqlxtypes.NullJSONText{
+			JSONText: EncodeToSQLXJSON(tokenScope{
+				Permissions: t.Scope.Permissions,
+				SpaceIDs:    t.Scope.SpaceIDs,
+				RepoIDs:     t.Scope.RepoIDs,
+			}),
+			Valid: true,
+		}
+	}
+
+	return dst
+}
+
+func mapToToken(t *token) (*types.Token, error) {
+	dst := t.Token
+
+	if t.ScopeJSON.Valid {
+		scope := tokenScope{}
+		if err := json.Unmarshal(t.ScopeJSON.JSONText, &scope); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal scope of token %d: %w", t.ID, err)
+		}
+
+		dst.Scope = &types.TokenScope{
+			Permissions: scope.Permissions,
+			SpaceIDs:    scope.SpaceIDs,
+			RepoIDs:     scope.RepoIDs,
+		}
+	}
+
+	return &dst, nil
 }
 
 const tokenSelectBase = `
@@ -173,6 +233,7 @@ token_id
 ,token_expires_at
 ,token_issued_at
 ,token_created_by
+,token_scope
 FROM tokens
 ` //#nosec G101
 
@@ -208,6 +269,7 @@ INSERT INTO tokens (
 	,token_expires_at
 	,token_issued_at
 	,token_created_by
+	,token_scope
 ) values (
 	:token_type
 	,:token_uid
@@ -215,5 +277,6 @@ INSERT INTO tokens (
 	,:token_expires_at
 	,:token_issued_at
 	,:token_created_by
+	,:token_scope
 ) RETURNING token_id
 `
@@ -45,6 +45,10 @@ type trigger struct {
 	CreatedBy   int64              `db:"trigger_created_by"`
 	Disabled    bool               `db:"trigger_disabled"`
 	Actions     sqlxtypes.JSONText `db:"trigger_actions"`
+	Cron        string             `db:"trigger_cron"`
+	Branch      string             `db:"trigger_cron_branch"`
+	Timezone    string             `db:"trigger_cron_timezone"`
+	CronNext    int64              `db:"trigger_cron_next"`
 	Created     int64              `db:"trigger_created"`
 	Updated     int64              `db:"trigger_updated"`
 	Version     int64              `db:"trigger_version"`
@@ -68,6 +72,10 @@ func mapInternalToTrigger(trigger *trigger) (*types.Trigger, error) {
 		Disabled:    trigger.Disabled,
 		Actions:     actions,
 		Identifier:  trigger.Identifier,
+		Cron:        trigger.Cron,
+		Branch:      trigger.Branch,
+		Timezone:    trigger.Timezone,
+		CronNext:    trigger.CronNext,
 		Created:     trigger.Created,
 		Updated:     trigger.Updated,
 		Version:     trigger.Version,
@@ -98,6 +106,10 @@ func mapTriggerToInternal(t *types.Trigger) *trigger {
 		CreatedBy:   t.CreatedBy,
 		Disabled:    t.Disabled,
 		Actions:     EncodeToSQLXJSON(t.Actions),
+		Cron:        t.Cron,
+		Branch:      t.Branch,
+		Timezone:    t.Timezone,
+		CronNext:    t.CronNext,
 		Created:     t.Created,
 		Updated:     t.Updated,
 		Version:     t.Version,
@@ -122,7 +134,14 @@ const (
 		,trigger_disabled
 		,trigger_actions
 		,trigger_description
+		,trigger_type
 		,trigger_pipeline_id
+		,trigger_repo_id
+		,trigger_created_by
+		,trigger_cron
+		,trigger_cron_branch
+		,trigger_cron_timezone
+		,trigger_cron_next
 		,trigger_created
 		,trigger_updated
 		,trigger_version
@@ -161,6 +180,10 @@ func (s *triggerStore) Create(ctx context.Context, t *types.Trigger) error {
 		,trigger_created_by
 		,trigger_pipeline_id
 		,trigger_repo_id
+		,trigger_cron
+		,trigger_cron_branch
+		,trigger_cron_timezone
+		,trigger_cron_next
 		,trigger_created
 		,trigger_updated
 		,trigger_version
@@ -174,6 +197,10 @@ func (s *triggerStore) Create(ctx context.Context, t *types.Trigger) error {
 		,:trigger_created_by
 		,:trigger_pipeline_id
 		,:trigger_repo_id
+		,:trigger_cron
+		,:trigger_cron_branch
+		,:trigger_cron_timezone
+		,:trigger_cron_next
 		,:trigger_created
 		,:trigger_updated
 		,:trigger_version
@@ -203,6 +230,11 @@ func (s *triggerStore) Update(ctx context.Context, t *types.Trigger) error {
 		,trigger_disabled = :trigger_disabled
 		,trigger_updated = :trigger_updated
 		,trigger_actions = :trigger_actions
+		,trigger_type = :trigger_type
+		,trigger_cron = :trigger_cron
+		,trigger_cron_branch = :trigger_cron_branch
+		,trigger_cron_timezone = :trigger_cron_timezone
+		,trigger_cron_next = :trigger_cron_next
 		,trigger_version = :trigger_version
 	WHERE trigger_id = :trigger_id AND trigger_version = :trigger_version - 1`
 	updatedAt := time.Now()
@@ -322,6 +354,53 @@ func (s *triggerStore) ListAllEnabled(
 	return mapInternalToTriggerList(dst)
 }
 
+// ListCronDue lists all enabled cron triggers that are due to fire at the provided time.
+func (s *triggerStore) ListCronDue(ctx context.Context, now int64) ([]*types.Trigger, error) {
+	stmt := database.Builder.
+		Select(triggerColumns).
+		From("triggers").
+		Where("trigger_cron <> '' AND trigger_disabled = false").
+		Where("trigger_cron_next > 0 AND trigger_cron_next <= ?", now).
+		OrderBy("trigger_cron_next ASC")
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to convert query to sql")
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := []*trigger{}
+	if err = db.SelectContext(ctx, &dst, sql, args...); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed executing list due cron triggers query")
+	}
+
+	return mapInternalToTriggerList(dst)
+}
+
+// UpdateCronNext moves the next cron run of a trigger from prev to next.
+// Returns false if the next run was already moved by someone else.
+func (s *triggerStore) UpdateCronNext(ctx context.Context, id int64, prev, next int64) (bool, error) {
+	const triggerUpdateCronNextStmt = `
+		UPDATE triggers
+		SET trigger_cron_next = $1
+		WHERE trigger_id = $2 AND trigger_cron_next = $3`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	result, err := db.ExecContext(ctx, triggerUpdateCronNextStmt, next, id, prev)
+	if err != nil {
+		return false, database.ProcessSQLErrorf(ctx, err, "Failed to update next cron run of trigger")
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, database.ProcessSQLErrorf(ctx, err, "Failed to get number of updated rows")
+	}
+
+	return count > 0, nil
+}
+
 // Count of triggers under a given pipeline.
 func (s *triggerStore) Count(ctx context.Context, pipelineID int64, filter types.ListQueryFilter) (int64, error) {
 	stmt := database.Builder.
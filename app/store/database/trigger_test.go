@@ -0,0 +1,106 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/harness/gitness/app/store/database"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerStore_Cron(t *testing.T) {
+	db, teardown := setupDB(t)
+	defer teardown()
+
+	principalStore, spaceStore, spacePathStore, repoStore := setupStores(t, db)
+
+	ctx := context.Background()
+
+	createUser(ctx, t, principalStore)
+	createSpace(ctx, t, spaceStore, spacePathStore, userID, 1, 0)
+	createRepo(ctx, t, repoStore, 1, 1, 0)
+
+	pipelineStore := database.NewPipelineStore(db)
+	triggerStore := database.NewTriggerStore(db)
+
+	pipeline := &types.Pipeline{
+		Identifier:    "pipeline",
+		RepoID:        1,
+		CreatedBy:     userID,
+		DefaultBranch: "main",
+		ConfigPath:    ".harness/pipeline.yaml",
+	}
+	require.NoError(t, pipelineStore.Create(ctx, pipeline))
+
+	createTrigger := func(identifier, cron string, next int64, disabled bool) *types.Trigger {
+		trigger := &types.Trigger{
+			Identifier: identifier,
+			PipelineID: pipeline.ID,
+			RepoID:     1,
+			CreatedBy:  userID,
+			Disabled:   disabled,
+			Actions:    []enum.TriggerAction{},
+			Cron:       cron,
+			Timezone:   "UTC",
+			CronNext:   next,
+		}
+		if cron != "" {
+			trigger.Type = enum.TriggerCron
+		}
+		require.NoError(t, triggerStore.Create(ctx, trigger))
+
+		created, err := triggerStore.FindByIdentifier(ctx, pipeline.ID, identifier)
+		require.NoError(t, err)
+		return created
+	}
+
+	dueLater := createTrigger("due-later", "*/5 * * * *", 2000, false)
+	dueFirst := createTrigger("due-first", "0 * * * *", 1000, false)
+	createTrigger("not-due", "0 0 * * *", 5000, false)
+	createTrigger("disabled", "0 0 * * *", 1000, true)
+	createTrigger("no-cron", "", 0, false)
+
+	due, err := triggerStore.ListCronDue(ctx, 3000)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	require.Equal(t, dueFirst.ID, due[0].ID)
+	require.Equal(t, dueLater.ID, due[1].ID)
+	require.Equal(t, "0 * * * *", due[0].Cron)
+	require.Equal(t, "UTC", due[0].Timezone)
+	require.Equal(t, int64(1000), due[0].CronNext)
+
+	ok, err := triggerStore.UpdateCronNext(ctx, dueFirst.ID, 1000, 4000)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// The next run was already moved, the stale update must not be applied.
+	ok, err = triggerStore.UpdateCronNext(ctx, dueFirst.ID, 1000, 9000)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	found, err := triggerStore.FindByIdentifier(ctx, pipeline.ID, dueFirst.Identifier)
+	require.NoError(t, err)
+	require.Equal(t, int64(4000), found.CronNext)
+
+	due, err = triggerStore.ListCronDue(ctx, 3000)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, dueLater.ID, due[0].ID)
+}
@@ -0,0 +1,232 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ store.UserChatIdentityStore = (*UserChatIdentityStore)(nil)
+
+func NewUserChatIdentityStore(db *sqlx.DB) *UserChatIdentityStore {
+	return &UserChatIdentityStore{
+		db: db,
+	}
+}
+
+// UserChatIdentityStore implements store.UserChatIdentityStore backed by a relational database.
+type UserChatIdentityStore struct {
+	db *sqlx.DB
+}
+
+type userChatIdentity struct {
+	PrincipalID int64             `db:"user_chat_identity_principal_id"`
+	Provider    enum.ChatProvider `db:"user_chat_identity_provider"`
+	ExternalID  string            `db:"user_chat_identity_external_id"`
+	Verified    bool              `db:"user_chat_identity_verified"`
+	Created     int64             `db:"user_chat_identity_created"`
+	Updated     int64             `db:"user_chat_identity_updated"`
+
+	ConfirmationHash     string `db:"user_chat_identity_confirmation_hash"`
+	ConfirmationExpires  int64  `db:"user_chat_identity_confirmation_expires"`
+	ConfirmationAttempts int    `db:"user_chat_identity_confirmation_attempts"`
+}
+
+const (
+	userChatIdentityColumns = `
+		 user_chat_identity_principal_id
+		,user_chat_identity_provider
+		,user_chat_identity_external_id
+		,user_chat_identity_verified
+		,user_chat_identity_created
+		,user_chat_identity_updated
+		,user_chat_identity_confirmation_hash
+		,user_chat_identity_confirmation_expires
+		,user_chat_identity_confirmation_attempts`
+
+	userChatIdentitySelectBase = `
+	SELECT` + userChatIdentityColumns + `
+	FROM user_chat_identities`
+)
+
+// Find returns the chat identity the user linked for the provider.
+func (s *UserChatIdentityStore) Find(
+	ctx context.Context,
+	principalID int64,
+	provider enum.ChatProvider,
+) (*types.UserChatIdentity, error) {
+	const sqlQuery = userChatIdentitySelectBase + `
+	WHERE user_chat_identity_principal_id = $1 AND user_chat_identity_provider = $2`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &userChatIdentity{}
+	if err := db.GetContext(ctx, dst, sqlQuery, principalID, provider); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to find user chat identity")
+	}
+
+	return mapUserChatIdentity(dst), nil
+}
+
+// List returns all chat identities linked by the user.
+func (s *UserChatIdentityStore) List(ctx context.Context, principalID int64) ([]*types.UserChatIdentity, error) {
+	const sqlQuery = userChatIdentitySelectBase + `
+	WHERE user_chat_identity_principal_id = $1
+	ORDER BY user_chat_identity_provider`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := []*userChatIdentity{}
+	if err := db.SelectContext(ctx, &dst, sqlQuery, principalID); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to list user chat identities")
+	}
+
+	identities := make([]*types.UserChatIdentity, len(dst))
+	for i, identity := range dst {
+		identities[i] = mapUserChatIdentity(identity)
+	}
+
+	return identities, nil
+}
+
+// ListExternalIDs returns the verified external IDs the given principals linked for the chat provider.
+func (s *UserChatIdentityStore) ListExternalIDs(
+	ctx context.Context,
+	provider enum.ChatProvider,
+	principalIDs []int64,
+) (map[int64]string, error) {
+	if len(principalIDs) == 0 {
+		return map[int64]string{}, nil
+	}
+
+	stmt := database.Builder.
+		Select("user_chat_identity_principal_id", "user_chat_identity_external_id").
+		From("user_chat_identities").
+		Where("user_chat_identity_provider = ?", provider).
+		Where("user_chat_identity_verified = ?", true).
+		Where(squirrel.Eq{"user_chat_identity_principal_id": principalIDs})
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to convert query to sql")
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := []*userChatIdentity{}
+	if err = db.SelectContext(ctx, &dst, sql, args...); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to list chat identity external ids")
+	}
+
+	externalIDs := make(map[int64]string, len(dst))
+	for _, identity := range dst {
+		externalIDs[identity.PrincipalID] = identity.ExternalID
+	}
+
+	return externalIDs, nil
+}
+
+// Upsert links the chat identity, replacing the identity the user linked before for the provider.
+func (s *UserChatIdentityStore) Upsert(ctx context.Context, identity *types.UserChatIdentity) error {
+	const sqlQuery = `
+		INSERT INTO user_chat_identities (` + userChatIdentityColumns + `
+		) VALUES (
+			 :user_chat_identity_principal_id
+			,:user_chat_identity_provider
+			,:user_chat_identity_external_id
+			,:user_chat_identity_verified
+			,:user_chat_identity_created
+			,:user_chat_identity_updated
+			,:user_chat_identity_confirmation_hash
+			,:user_chat_identity_confirmation_expires
+			,:user_chat_identity_confirmation_attempts
+		)
+		ON CONFLICT (user_chat_identity_principal_id, user_chat_identity_provider) DO
+		UPDATE SET
+			 user_chat_identity_external_id = :user_chat_identity_external_id
+			,user_chat_identity_verified = :user_chat_identity_verified
+			,user_chat_identity_updated = :user_chat_identity_updated
+			,user_chat_identity_confirmation_hash = :user_chat_identity_confirmation_hash
+			,user_chat_identity_confirmation_expires = :user_chat_identity_confirmation_expires
+			,user_chat_identity_confirmation_attempts = :user_chat_identity_confirmation_attempts
+		RETURNING user_chat_identity_created`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, mapInternalUserChatIdentity(identity))
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to bind user chat identity object")
+	}
+
+	if err = db.QueryRowContext(ctx, query, arg...).Scan(&identity.Created); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to upsert user chat identity")
+	}
+
+	return nil
+}
+
+// Delete unlinks the chat identity of the user for the provider.
+func (s *UserChatIdentityStore) Delete(ctx context.Context, principalID int64, provider enum.ChatProvider) error {
+	const sqlQuery = `
+		DELETE FROM user_chat_identities
+		WHERE user_chat_identity_principal_id = $1 AND user_chat_identity_provider = $2`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	if _, err := db.ExecContext(ctx, sqlQuery, principalID, provider); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to delete user chat identity")
+	}
+
+	return nil
+}
+
+func mapInternalUserChatIdentity(identity *types.UserChatIdentity) *userChatIdentity {
+	return &userChatIdentity{
+		PrincipalID: identity.PrincipalID,
+		Provider:    identity.Provider,
+		ExternalID:  identity.ExternalID,
+		Verified:    identity.Verified,
+		Created:     identity.Created,
+		Updated:     identity.Updated,
+
+		ConfirmationHash:     identity.ConfirmationHash,
+		ConfirmationExpires:  identity.ConfirmationExpires,
+		ConfirmationAttempts: identity.ConfirmationAttempts,
+	}
+}
+
+func mapUserChatIdentity(identity *userChatIdentity) *types.UserChatIdentity {
+	return &types.UserChatIdentity{
+		PrincipalID: identity.PrincipalID,
+		Provider:    identity.Provider,
+		ExternalID:  identity.ExternalID,
+		Verified:    identity.Verified,
+		Created:     identity.Created,
+		Updated:     identity.Updated,
+
+		ConfirmationHash:     identity.ConfirmationHash,
+		ConfirmationExpires:  identity.ConfirmationExpires,
+		ConfirmationAttempts: identity.ConfirmationAttempts,
+	}
+}
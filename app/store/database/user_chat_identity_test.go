@@ -0,0 +1,100 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/harness/gitness/app/store/database"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserChatIdentityStore(t *testing.T) {
+	db, teardown := setupDB(t)
+	defer teardown()
+
+	principalStore, _, _, _ := setupStores(t, db)
+
+	ctx := context.Background()
+
+	createUser(ctx, t, principalStore)
+
+	identityStore := database.NewUserChatIdentityStore(db)
+
+	err := identityStore.Upsert(ctx, &types.UserChatIdentity{
+		PrincipalID: userID,
+		Provider:    enum.ChatProviderSlack,
+		ExternalID:  "U001",
+		Verified:    true,
+		Created:     1000,
+		Updated:     1000,
+	})
+	require.NoError(t, err)
+
+	relinked := &types.UserChatIdentity{
+		PrincipalID: userID,
+		Provider:    enum.ChatProviderSlack,
+		ExternalID:  "U002",
+		Created:     2000,
+		Updated:     2000,
+
+		ConfirmationHash:    "hash",
+		ConfirmationExpires: 3000,
+	}
+	err = identityStore.Upsert(ctx, relinked)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), relinked.Created)
+
+	identities, err := identityStore.List(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, identities, 1)
+	require.Equal(t, "U002", identities[0].ExternalID)
+	require.Equal(t, int64(2000), identities[0].Updated)
+
+	require.False(t, identities[0].Verified)
+
+	// unverified identities don't receive notifications
+	externalIDs, err := identityStore.ListExternalIDs(ctx, enum.ChatProviderSlack, []int64{userID})
+	require.NoError(t, err)
+	require.Empty(t, externalIDs)
+
+	relinked.Verified = true
+	relinked.ConfirmationHash = ""
+	err = identityStore.Upsert(ctx, relinked)
+	require.NoError(t, err)
+
+	identity, err := identityStore.Find(ctx, userID, enum.ChatProviderSlack)
+	require.NoError(t, err)
+	require.True(t, identity.Verified)
+
+	externalIDs, err = identityStore.ListExternalIDs(ctx, enum.ChatProviderSlack, []int64{userID, userID + 1})
+	require.NoError(t, err)
+	require.Equal(t, map[int64]string{userID: "U002"}, externalIDs)
+
+	externalIDs, err = identityStore.ListExternalIDs(ctx, enum.ChatProviderTeams, []int64{userID})
+	require.NoError(t, err)
+	require.Empty(t, externalIDs)
+
+	err = identityStore.Delete(ctx, userID, enum.ChatProviderSlack)
+	require.NoError(t, err)
+
+	identities, err = identityStore.List(ctx, userID)
+	require.NoError(t, err)
+	require.Empty(t, identities)
+}
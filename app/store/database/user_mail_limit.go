@@ -0,0 +1,77 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ store.UserMailLimitStore = (*UserMailLimitStore)(nil)
+
+func NewUserMailLimitStore(db *sqlx.DB) *UserMailLimitStore {
+	return &UserMailLimitStore{
+		db: db,
+	}
+}
+
+// UserMailLimitStore implements store.UserMailLimitStore backed by a relational database.
+type UserMailLimitStore struct {
+	db *sqlx.DB
+}
+
+// Increment increments the number of mails of the type sent to the user in the current window
+// and returns the new count. A new window is started if the current one began before windowStart.
+func (s *UserMailLimitStore) Increment(
+	ctx context.Context,
+	principalID int64,
+	mailType enum.UserMailType,
+	now int64,
+	windowStart int64,
+) (int, error) {
+	const sqlQuery = `
+	INSERT INTO user_mail_limits (
+		 user_mail_limit_principal_id
+		,user_mail_limit_type
+		,user_mail_limit_window_start
+		,user_mail_limit_count
+	) VALUES ($1, $2, $3, 1)
+	ON CONFLICT (user_mail_limit_principal_id, user_mail_limit_type) DO UPDATE
+	SET
+		 user_mail_limit_count = CASE
+			WHEN user_mail_limits.user_mail_limit_window_start < $4 THEN 1
+			ELSE user_mail_limits.user_mail_limit_count + 1
+		END
+		,user_mail_limit_window_start = CASE
+			WHEN user_mail_limits.user_mail_limit_window_start < $4 THEN $3
+			ELSE user_mail_limits.user_mail_limit_window_start
+		END
+	RETURNING user_mail_limit_count`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	var count int
+	if err := db.QueryRowContext(ctx, sqlQuery, principalID, mailType, now, windowStart).Scan(&count); err != nil {
+		return 0, database.ProcessSQLErrorf(ctx, err, "Failed to increment user mail limit")
+	}
+
+	return count, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/harness/gitness/app/store/database"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserMailLimitStore_Increment(t *testing.T) {
+	db, teardown := setupDB(t)
+	defer teardown()
+
+	principalStore, _, _, _ := setupStores(t, db)
+
+	ctx := context.Background()
+
+	createUser(ctx, t, principalStore)
+
+	limitStore := database.NewUserMailLimitStore(db)
+
+	tests := []struct {
+		name        string
+		mailType    enum.UserMailType
+		now         int64
+		windowStart int64
+		want        int
+	}{
+		{name: "first", mailType: enum.UserMailTypePasswordReset, now: 1000, windowStart: 0, want: 1},
+		{name: "same-window", mailType: enum.UserMailTypePasswordReset, now: 2000, windowStart: 1000, want: 2},
+		{name: "other-type", mailType: enum.UserMailTypeEmailVerification, now: 2000, windowStart: 1000, want: 1},
+		{name: "window-end", mailType: enum.UserMailTypePasswordReset, now: 3000, windowStart: 1000, want: 3},
+		{name: "new-window", mailType: enum.UserMailTypePasswordReset, now: 5000, windowStart: 1001, want: 1},
+		{name: "next-in-window", mailType: enum.UserMailTypePasswordReset, now: 5500, windowStart: 4000, want: 2},
+	}
+
+	for _, test := range tests {
+		count, err := limitStore.Increment(ctx, userID, test.mailType, test.now, test.windowStart)
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.want, count, test.name)
+	}
+}
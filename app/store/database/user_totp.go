@@ -0,0 +1,261 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+
+	"github.com/jmoiron/sqlx"
+	sqlxtypes "github.com/jmoiron/sqlx/types"
+)
+
+var _ store.UserTOTPStore = (*UserTOTPStore)(nil)
+
+func NewUserTOTPStore(db *sqlx.DB) *UserTOTPStore {
+	return &UserTOTPStore{
+		db: db,
+	}
+}
+
+// UserTOTPStore implements store.UserTOTPStore backed by a relational database.
+type UserTOTPStore struct {
+	db *sqlx.DB
+}
+
+type userTOTP struct {
+	PrincipalID       int64              `db:"user_totp_principal_id"`
+	Secret            []byte             `db:"user_totp_secret"`
+	Enabled           bool               `db:"user_totp_enabled"`
+	RecoveryCodes     sqlxtypes.JSONText `db:"user_totp_recovery_codes"`
+	LastUsedStep      int64              `db:"user_totp_last_used_step"`
+	FailedAttempts    int                `db:"user_totp_failed_attempts"`
+	LockedUntil       int64              `db:"user_totp_locked_until"`
+	ChallengesRevoked int64              `db:"user_totp_challenges_revoked"`
+	Version           int64              `db:"user_totp_version"`
+	Created           int64              `db:"user_totp_created"`
+	Updated           int64              `db:"user_totp_updated"`
+}
+
+const (
+	userTOTPColumns = `
+		 user_totp_principal_id
+		,user_totp_secret
+		,user_totp_enabled
+		,user_totp_recovery_codes
+		,user_totp_last_used_step
+		,user_totp_failed_attempts
+		,user_totp_locked_until
+		,user_totp_challenges_revoked
+		,user_totp_version
+		,user_totp_created
+		,user_totp_updated`
+
+	userTOTPSelectBase = `
+	SELECT` + userTOTPColumns + `
+	FROM user_totp`
+)
+
+// Find returns the TOTP second factor of the user.
+func (s *UserTOTPStore) Find(ctx context.Context, principalID int64) (*types.UserTOTP, error) {
+	const sqlQuery = userTOTPSelectBase + `
+	WHERE user_totp_principal_id = $1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &userTOTP{}
+	if err := db.GetContext(ctx, dst, sqlQuery, principalID); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to find user totp")
+	}
+
+	return mapUserTOTP(dst)
+}
+
+// Create creates the TOTP second factor of the user.
+func (s *UserTOTPStore) Create(ctx context.Context, totp *types.UserTOTP) error {
+	const sqlQuery = `
+		INSERT INTO user_totp (` + userTOTPColumns + `
+		) VALUES (
+			 :user_totp_principal_id
+			,:user_totp_secret
+			,:user_totp_enabled
+			,:user_totp_recovery_codes
+			,:user_totp_last_used_step
+			,:user_totp_failed_attempts
+			,:user_totp_locked_until
+			,:user_totp_challenges_revoked
+			,:user_totp_version
+			,:user_totp_created
+			,:user_totp_updated
+		)`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, mapInternalUserTOTP(totp))
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to bind user totp object")
+	}
+
+	if _, err = db.ExecContext(ctx, query, arg...); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to insert user totp")
+	}
+
+	return nil
+}
+
+// Update updates the TOTP second factor of the user. It uses optimistic locking,
+// so concurrent uses of the same code or recovery code can't both succeed.
+func (s *UserTOTPStore) Update(ctx context.Context, totp *types.UserTOTP) error {
+	const sqlQuery = `
+		UPDATE user_totp
+		SET
+			 user_totp_secret = :user_totp_secret
+			,user_totp_enabled = :user_totp_enabled
+			,user_totp_recovery_codes = :user_totp_recovery_codes
+			,user_totp_last_used_step = :user_totp_last_used_step
+			,user_totp_failed_attempts = :user_totp_failed_attempts
+			,user_totp_locked_until = :user_totp_locked_until
+			,user_totp_challenges_revoked = :user_totp_challenges_revoked
+			,user_totp_version = :user_totp_version
+			,user_totp_updated = :user_totp_updated
+		WHERE user_totp_principal_id = :user_totp_principal_id AND user_totp_version = :user_totp_version - 1`
+
+	dbTOTP := mapInternalUserTOTP(totp)
+
+	// update Version (used for optimistic locking) and Updated time
+	dbTOTP.Version++
+	dbTOTP.Updated = time.Now().UnixMilli()
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, dbTOTP)
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to bind user totp object")
+	}
+
+	result, err := db.ExecContext(ctx, query, arg...)
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to update user totp")
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to get number of updated rows")
+	}
+
+	if count == 0 {
+		return gitness_store.ErrVersionConflict
+	}
+
+	totp.Version = dbTOTP.Version
+	totp.Updated = dbTOTP.Updated
+
+	return nil
+}
+
+// UpdateOptLock updates the TOTP second factor of the user using the optimistic locking mechanism.
+func (s *UserTOTPStore) UpdateOptLock(
+	ctx context.Context,
+	totp *types.UserTOTP,
+	mutateFn func(totp *types.UserTOTP) error,
+) (*types.UserTOTP, error) {
+	for {
+		dup := *totp
+
+		err := mutateFn(&dup)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.Update(ctx, &dup)
+		if err == nil {
+			return &dup, nil
+		}
+		if !errors.Is(err, gitness_store.ErrVersionConflict) {
+			return nil, err
+		}
+
+		totp, err = s.Find(ctx, totp.PrincipalID)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Delete deletes the TOTP second factor of the user.
+func (s *UserTOTPStore) Delete(ctx context.Context, principalID int64) error {
+	const sqlQuery = `
+		DELETE FROM user_totp
+		WHERE user_totp_principal_id = $1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	if _, err := db.ExecContext(ctx, sqlQuery, principalID); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to delete user totp")
+	}
+
+	return nil
+}
+
+func mapInternalUserTOTP(totp *types.UserTOTP) *userTOTP {
+	recoveryCodes := totp.RecoveryCodes
+	if recoveryCodes == nil {
+		recoveryCodes = []string{}
+	}
+
+	return &userTOTP{
+		PrincipalID:       totp.PrincipalID,
+		Secret:            totp.Secret,
+		Enabled:           totp.Enabled,
+		RecoveryCodes:     EncodeToSQLXJSON(recoveryCodes),
+		LastUsedStep:      totp.LastUsedStep,
+		FailedAttempts:    totp.FailedAttempts,
+		LockedUntil:       totp.LockedUntil,
+		ChallengesRevoked: totp.ChallengesRevoked,
+		Version:           totp.Version,
+		Created:           totp.Created,
+		Updated:           totp.Updated,
+	}
+}
+
+func mapUserTOTP(totp *userTOTP) (*types.UserTOTP, error) {
+	var recoveryCodes []string
+	if err := json.Unmarshal(totp.RecoveryCodes, &recoveryCodes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal recovery codes: %w", err)
+	}
+
+	return &types.UserTOTP{
+		PrincipalID:       totp.PrincipalID,
+		Secret:            totp.Secret,
+		Enabled:           totp.Enabled,
+		RecoveryCodes:     recoveryCodes,
+		LastUsedStep:      totp.LastUsedStep,
+		FailedAttempts:    totp.FailedAttempts,
+		LockedUntil:       totp.LockedUntil,
+		ChallengesRevoked: totp.ChallengesRevoked,
+		Version:           totp.Version,
+		Created:           totp.Created,
+		Updated:           totp.Updated,
+	}, nil
+}
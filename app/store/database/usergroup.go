@@ -22,6 +22,7 @@ import (
 	"github.com/harness/gitness/store/database"
 	"github.com/harness/gitness/store/database/dbtx"
 	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
@@ -47,6 +48,8 @@ type UserGroup struct {
 	Description string `db:"usergroup_description"`
 	Created     int64  `db:"usergroup_created"`
 	Updated     int64  `db:"usergroup_updated"`
+
+	ManagedBy enum.UserGroupSource `db:"usergroup_managed_by"`
 }
 
 const (
@@ -57,7 +60,8 @@ const (
 	,usergroup_description
 	,usergroup_space_id
 	,usergroup_created
-	,usergroup_updated`
+	,usergroup_updated
+	,usergroup_managed_by`
 
 	userGroupSelectBase = `SELECT ` + userGroupColumns + ` FROM usergroups`
 )
@@ -71,6 +75,7 @@ func mapUserGroup(ug *UserGroup) *types.UserGroup {
 		SpaceID:     ug.SpaceID,
 		Created:     ug.Created,
 		Updated:     ug.Updated,
+		ManagedBy:   ug.ManagedBy,
 	}
 }
 
@@ -168,6 +173,50 @@ func (s *UserGroupStore) FindManyByIdentifiersAndSpaceID(
 	return result, nil
 }
 
+// List returns the usergroups of a space. If the filter is nil, all usergroups of the space are returned.
+func (s *UserGroupStore) List(
+	ctx context.Context,
+	spaceID int64,
+	filter *types.ListQueryFilter,
+) ([]*types.UserGroup, error) {
+	stmt := database.Builder.
+		Select(userGroupColumns).
+		From("usergroups").
+		Where("usergroup_space_id = ?", spaceID).
+		OrderBy("usergroup_identifier")
+
+	if filter != nil {
+		if filter.Query != "" {
+			stmt = stmt.Where(squirrel.Or{
+				squirrel.Expr(PartialMatch("usergroup_identifier", filter.Query)),
+				squirrel.Expr(PartialMatch("usergroup_name", filter.Query)),
+			})
+		}
+
+		stmt = stmt.Limit(database.Limit(filter.Size))
+		stmt = stmt.Offset(database.Offset(filter.Page, filter.Size))
+	}
+
+	sqlQuery, params, err := stmt.ToSql()
+	if err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "failed to generate list usergroups query")
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	var dst []*UserGroup
+	if err := db.SelectContext(ctx, &dst, sqlQuery, params...); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "list usergroups query failed")
+	}
+
+	result := make([]*types.UserGroup, len(dst))
+	for i, u := range dst {
+		result[i] = mapUserGroup(u)
+	}
+
+	return result, nil
+}
+
 // Create Creates a usergroup in the database.
 func (s *UserGroupStore) Create(
 	ctx context.Context,
@@ -182,6 +231,7 @@ func (s *UserGroupStore) Create(
 		,usergroup_space_id	
 		,usergroup_created
 		,usergroup_updated
+		,usergroup_managed_by
 	) values (	
 		:usergroup_identifier
 		,:usergroup_name
@@ -189,6 +239,7 @@ func (s *UserGroupStore) Create(
 		,:usergroup_space_id	
 		,:usergroup_created
 		,:usergroup_updated
+		,:usergroup_managed_by
 	) RETURNING usergroup_id`
 
 	db := dbtx.GetAccessor(ctx, s.db)
@@ -218,6 +269,7 @@ func (s *UserGroupStore) CreateOrUpdate(
 		,usergroup_space_id	
 		,usergroup_created
 		,usergroup_updated
+		,usergroup_managed_by
 	) values (	
 		:usergroup_identifier
 		,:usergroup_name
@@ -225,6 +277,7 @@ func (s *UserGroupStore) CreateOrUpdate(
 		,:usergroup_space_id	
 		,:usergroup_created
 		,:usergroup_updated
+		,:usergroup_managed_by
 	) ON CONFLICT (usergroup_space_id, LOWER(usergroup_identifier)) DO UPDATE SET
 		usergroup_name = EXCLUDED.usergroup_name,
 		usergroup_description = EXCLUDED.usergroup_description,
@@ -245,7 +298,46 @@ func (s *UserGroupStore) CreateOrUpdate(
 	return nil
 }
 
+// Update updates the name and the description of a usergroup.
+func (s *UserGroupStore) Update(ctx context.Context, userGroup *types.UserGroup) error {
+	const sqlQuery = `
+	UPDATE usergroups
+	SET
+		usergroup_name = :usergroup_name
+		,usergroup_description = :usergroup_description
+		,usergroup_updated = :usergroup_updated
+	WHERE usergroup_id = :usergroup_id`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, mapInternalUserGroup(userGroup, userGroup.SpaceID))
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to bind usergroup object")
+	}
+
+	result, err := db.ExecContext(ctx, query, arg...)
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to update usergroup")
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to get number of updated rows")
+	}
+
+	if count == 0 {
+		return store.ErrResourceNotFound
+	}
+
+	return nil
+}
+
 func mapInternalUserGroup(u *types.UserGroup, spaceID int64) *UserGroup {
+	managedBy := u.ManagedBy
+	if managedBy == "" {
+		managedBy = enum.UserGroupSourceManual
+	}
+
 	return &UserGroup{
 		ID:          u.ID,
 		SpaceID:     spaceID,
@@ -254,6 +346,7 @@ func mapInternalUserGroup(u *types.UserGroup, spaceID int64) *UserGroup {
 		Description: u.Description,
 		Created:     u.Created,
 		Updated:     u.Updated,
+		ManagedBy:   managedBy,
 	}
 }
 
@@ -265,5 +358,6 @@ func (u *UserGroup) toUserGroupType() *types.UserGroup {
 		Description: u.Description,
 		Created:     u.Created,
 		Updated:     u.Updated,
+		ManagedBy:   u.ManagedBy,
 	}
 }
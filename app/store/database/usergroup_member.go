@@ -0,0 +1,210 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ store.UserGroupMemberStore = (*UserGroupMemberStore)(nil)
+
+func NewUserGroupMemberStore(db *sqlx.DB) *UserGroupMemberStore {
+	return &UserGroupMemberStore{
+		db: db,
+	}
+}
+
+// UserGroupMemberStore implements store.UserGroupMemberStore backed by a relational database.
+type UserGroupMemberStore struct {
+	db *sqlx.DB
+}
+
+type userGroupMember struct {
+	UserGroupID int64 `db:"usergroup_member_usergroup_id"`
+	PrincipalID int64 `db:"usergroup_member_principal_id"`
+	CreatedBy   int64 `db:"usergroup_member_created_by"`
+	Created     int64 `db:"usergroup_member_created"`
+
+	Source enum.UserGroupSource `db:"usergroup_member_source"`
+}
+
+const (
+	userGroupMemberColumns = `
+		 usergroup_member_usergroup_id
+		,usergroup_member_principal_id
+		,usergroup_member_created_by
+		,usergroup_member_created
+		,usergroup_member_source`
+
+	userGroupMemberSelectBase = `
+	SELECT` + userGroupMemberColumns + `
+	FROM usergroup_members`
+)
+
+// Add adds a principal to a usergroup. It's a no-op if the principal is already a member.
+func (s *UserGroupMemberStore) Add(ctx context.Context, member *types.UserGroupMember) error {
+	const sqlQuery = `
+		INSERT INTO usergroup_members (
+			 usergroup_member_usergroup_id
+			,usergroup_member_principal_id
+			,usergroup_member_created_by
+			,usergroup_member_created
+			,usergroup_member_source
+		) VALUES (
+			 :usergroup_member_usergroup_id
+			,:usergroup_member_principal_id
+			,:usergroup_member_created_by
+			,:usergroup_member_created
+			,:usergroup_member_source
+		)
+		ON CONFLICT DO NOTHING`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, mapInternalUserGroupMember(member))
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to bind usergroup member object")
+	}
+
+	if _, err = db.ExecContext(ctx, query, arg...); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to insert usergroup member")
+	}
+
+	return nil
+}
+
+// Remove removes a principal from a usergroup.
+func (s *UserGroupMemberStore) Remove(ctx context.Context, userGroupID, principalID int64) error {
+	const sqlQuery = `
+		DELETE FROM usergroup_members
+		WHERE usergroup_member_usergroup_id = $1 AND usergroup_member_principal_id = $2`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	if _, err := db.ExecContext(ctx, sqlQuery, userGroupID, principalID); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to delete usergroup member")
+	}
+
+	return nil
+}
+
+// ListByUserGroup returns all memberships of a usergroup.
+func (s *UserGroupMemberStore) ListByUserGroup(
+	ctx context.Context,
+	userGroupID int64,
+) ([]*types.UserGroupMember, error) {
+	const sqlQuery = userGroupMemberSelectBase + `
+	WHERE usergroup_member_usergroup_id = $1
+	ORDER BY usergroup_member_principal_id`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	var dst []*userGroupMember
+	if err := db.SelectContext(ctx, &dst, sqlQuery, userGroupID); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to list usergroup memberships")
+	}
+
+	result := make([]*types.UserGroupMember, len(dst))
+	for i, m := range dst {
+		result[i] = mapUserGroupMember(m)
+	}
+
+	return result, nil
+}
+
+// ListByPrincipal returns all usergroup memberships of a principal.
+func (s *UserGroupMemberStore) ListByPrincipal(
+	ctx context.Context,
+	principalID int64,
+) ([]*types.UserGroupMember, error) {
+	const sqlQuery = userGroupMemberSelectBase + `
+	WHERE usergroup_member_principal_id = $1
+	ORDER BY usergroup_member_usergroup_id`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	var dst []*userGroupMember
+	if err := db.SelectContext(ctx, &dst, sqlQuery, principalID); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to list usergroup memberships of principal")
+	}
+
+	result := make([]*types.UserGroupMember, len(dst))
+	for i, m := range dst {
+		result[i] = mapUserGroupMember(m)
+	}
+
+	return result, nil
+}
+
+// ListPrincipalIDs returns IDs of all principals that are members of any of the provided usergroups.
+func (s *UserGroupMemberStore) ListPrincipalIDs(ctx context.Context, userGroupIDs []int64) ([]int64, error) {
+	if len(userGroupIDs) == 0 {
+		return nil, nil
+	}
+
+	stmt := database.Builder.
+		Select("DISTINCT usergroup_member_principal_id").
+		From("usergroup_members").
+		Where(squirrel.Eq{"usergroup_member_usergroup_id": userGroupIDs}).
+		OrderBy("usergroup_member_principal_id")
+
+	sqlQuery, params, err := stmt.ToSql()
+	if err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to generate list usergroup members query")
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	var dst []int64
+	if err := db.SelectContext(ctx, &dst, sqlQuery, params...); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to list usergroup members")
+	}
+
+	return dst, nil
+}
+
+func mapInternalUserGroupMember(m *types.UserGroupMember) *userGroupMember {
+	source := m.Source
+	if source == "" {
+		source = enum.UserGroupSourceManual
+	}
+
+	return &userGroupMember{
+		UserGroupID: m.UserGroupID,
+		PrincipalID: m.PrincipalID,
+		CreatedBy:   m.CreatedBy,
+		Created:     m.Created,
+		Source:      source,
+	}
+}
+
+func mapUserGroupMember(m *userGroupMember) *types.UserGroupMember {
+	return &types.UserGroupMember{
+		UserGroupID: m.UserGroupID,
+		PrincipalID: m.PrincipalID,
+		CreatedBy:   m.CreatedBy,
+		Created:     m.Created,
+		Source:      m.Source,
+	}
+}
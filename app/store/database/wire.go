@@ -19,6 +19,7 @@ import (
 
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/app/store/database/migrate"
+	"github.com/harness/gitness/audit"
 	"github.com/harness/gitness/job"
 	"github.com/harness/gitness/store/database"
 
@@ -32,6 +33,7 @@ var WireSet = wire.NewSet(
 	ProvidePrincipalStore,
 	ProvideUserGroupStore,
 	ProvideUserGroupReviewerStore,
+	ProvideUserGroupMemberStore,
 	ProvidePrincipalInfoView,
 	ProvideInfraProviderResourceView,
 	ProvideSpacePathStore,
@@ -51,6 +53,8 @@ var WireSet = wire.NewSet(
 	ProvidePullReqActivityStore,
 	ProvideCodeCommentView,
 	ProvidePullReqReviewStore,
+	ProvidePullReqAutoMergeStore,
+	ProvideMergeQueueEntryStore,
 	ProvidePullReqReviewerStore,
 	ProvidePullReqFileViewStore,
 	ProvideWebhookStore,
@@ -74,6 +78,15 @@ var WireSet = wire.NewSet(
 	ProvideInfraProviderTemplateStore,
 	ProvideInfraProvisionedStore,
 	ProvideUsageMetricStore,
+	ProvideAuditEventStore,
+	ProvideLFSObjectStore,
+	ProvideMirrorStore,
+	ProvideOIDCIdentityStore,
+	ProvideLDAPIdentityStore,
+	ProvideUserTOTPStore,
+	ProvideUserMailLimitStore,
+	ProvideRunnerStore,
+	ProvideUserChatIdentityStore,
 )
 
 // migrator is helper function to set up the database by performing automated
@@ -111,6 +124,11 @@ func ProvideUserGroupReviewerStore(
 	return NewUsergroupReviewerStore(db, pInfoCache, userGroupStore)
 }
 
+// ProvideUserGroupMemberStore provides a usergroup member store.
+func ProvideUserGroupMemberStore(db *sqlx.DB) store.UserGroupMemberStore {
+	return NewUserGroupMemberStore(db)
+}
+
 // ProvidePrincipalInfoView provides a principal info store.
 func ProvidePrincipalInfoView(db *sqlx.DB) store.PrincipalInfoView {
 	return NewPrincipalInfoView(db)
@@ -270,6 +288,16 @@ func ProvideCodeCommentView(db *sqlx.DB) store.CodeCommentView {
 	return NewCodeCommentView(db)
 }
 
+// ProvideMergeQueueEntryStore provides a merge queue entry store.
+func ProvideMergeQueueEntryStore(db *sqlx.DB) store.MergeQueueEntryStore {
+	return NewMergeQueueEntryStore(db)
+}
+
+// ProvidePullReqAutoMergeStore provides a pull request auto-merge store.
+func ProvidePullReqAutoMergeStore(db *sqlx.DB) store.PullReqAutoMergeStore {
+	return NewPullReqAutoMergeStore(db)
+}
+
 // ProvidePullReqReviewStore provides a pull request review store.
 func ProvidePullReqReviewStore(db *sqlx.DB) store.PullReqReviewStore {
 	return NewPullReqReviewStore(db)
@@ -354,3 +382,48 @@ func ProvideInfraProvisionedStore(db *sqlx.DB) store.InfraProvisionedStore {
 func ProvideUsageMetricStore(db *sqlx.DB) store.UsageMetricStore {
 	return NewUsageMetricsStore(db)
 }
+
+// ProvideAuditEventStore provides an audit event store.
+func ProvideAuditEventStore(db *sqlx.DB, spacePathStore store.SpacePathStore) audit.Store {
+	return NewAuditEventStore(db, spacePathStore)
+}
+
+// ProvideLFSObjectStore provides an LFS object store.
+func ProvideLFSObjectStore(db *sqlx.DB) store.LFSObjectStore {
+	return NewLFSObjectStore(db)
+}
+
+// ProvideMirrorStore provides a repository mirror store.
+func ProvideMirrorStore(db *sqlx.DB) store.MirrorStore {
+	return NewMirrorStore(db)
+}
+
+// ProvideOIDCIdentityStore provides an OpenID Connect identity store.
+func ProvideOIDCIdentityStore(db *sqlx.DB) store.OIDCIdentityStore {
+	return NewOIDCIdentityStore(db)
+}
+
+// ProvideLDAPIdentityStore provides an LDAP identity store.
+func ProvideLDAPIdentityStore(db *sqlx.DB) store.LDAPIdentityStore {
+	return NewLDAPIdentityStore(db)
+}
+
+// ProvideUserTOTPStore provides a user TOTP store.
+func ProvideUserTOTPStore(db *sqlx.DB) store.UserTOTPStore {
+	return NewUserTOTPStore(db)
+}
+
+// ProvideUserMailLimitStore provides a user mail limit store.
+func ProvideUserMailLimitStore(db *sqlx.DB) store.UserMailLimitStore {
+	return NewUserMailLimitStore(db)
+}
+
+// ProvideRunnerStore provides a pipeline runner store.
+func ProvideRunnerStore(db *sqlx.DB) store.RunnerStore {
+	return NewRunnerStore(db)
+}
+
+// ProvideUserChatIdentityStore provides a user chat identity store.
+func ProvideUserChatIdentityStore(db *sqlx.DB) store.UserChatIdentityStore {
+	return NewUserChatIdentityStore(db)
+}
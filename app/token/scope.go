@@ -0,0 +1,70 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/harness/gitness/app/services/refcache"
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+)
+
+// PopulateScopePaths sets the current paths of the spaces and repositories in the scopes of the tokens.
+// Spaces and repositories that don't exist anymore are omitted.
+func PopulateScopePaths(
+	ctx context.Context,
+	spaceStore store.SpaceStore,
+	repoFinder refcache.RepoFinder,
+	tokens []*types.Token,
+) error {
+	for _, t := range tokens {
+		if t.Scope == nil {
+			continue
+		}
+
+		t.Scope.Spaces = make([]string, 0, len(t.Scope.SpaceIDs))
+		for _, spaceID := range t.Scope.SpaceIDs {
+			space, err := spaceStore.Find(ctx, spaceID)
+			if errors.Is(err, gitness_store.ErrResourceNotFound) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to find space %d of token scope: %w", spaceID, err)
+			}
+
+			t.Scope.Spaces = append(t.Scope.Spaces, space.Path)
+		}
+
+		t.Scope.Repos = make([]string, 0, len(t.Scope.RepoIDs))
+		for _, repoID := range t.Scope.RepoIDs {
+			repo, err := repoFinder.FindByRef(ctx, strconv.FormatInt(repoID, 10))
+			if errors.Is(err, gitness_store.ErrResourceNotFound) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to find repository %d of token scope: %w", repoID, err)
+			}
+
+			t.Scope.Repos = append(t.Scope.Repos, repo.Path)
+		}
+	}
+
+	return nil
+}
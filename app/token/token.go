@@ -61,6 +61,7 @@ func CreateUserSession(
 		principal,
 		identifier,
 		ptr.Duration(userSessionTokenLifeTime),
+		nil,
 	)
 }
 
@@ -71,6 +72,7 @@ func CreatePAT(
 	createdFor *types.User,
 	identifier string,
 	lifetime *time.Duration,
+	scope *types.TokenScope,
 ) (*types.Token, string, error) {
 	return create(
 		ctx,
@@ -80,6 +82,7 @@ func CreatePAT(
 		createdFor.ToPrincipal(),
 		identifier,
 		lifetime,
+		scope,
 	)
 }
 
@@ -90,6 +93,7 @@ func CreateSAT(
 	createdFor *types.ServiceAccount,
 	identifier string,
 	lifetime *time.Duration,
+	scope *types.TokenScope,
 ) (*types.Token, string, error) {
 	return create(
 		ctx,
@@ -99,6 +103,7 @@ func CreateSAT(
 		createdFor.ToPrincipal(),
 		identifier,
 		lifetime,
+		scope,
 	)
 }
 
@@ -110,6 +115,7 @@ func create(
 	createdFor *types.Principal,
 	identifier string,
 	lifetime *time.Duration,
+	scope *types.TokenScope,
 ) (*types.Token, string, error) {
 	issuedAt := time.Now()
 
@@ -126,6 +132,7 @@ func create(
 		IssuedAt:    issuedAt.UnixMilli(),
 		ExpiresAt:   expiresAt,
 		CreatedBy:   createdBy.ID,
+		Scope:       scope,
 	}
 
 	err := tokenStore.Create(ctx, &token)
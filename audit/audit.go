@@ -40,6 +40,7 @@ const (
 	BypassedResourceTypePullRequest = "pull_request"
 	BypassedResourceTypeBranch      = "branch"
 	BypassedResourceTypeCommit      = "commit"
+	BypassedResourceTypeTag         = "tag"
 	BypassAction                    = "bypass_action"
 	BypassActionDeleted             = "deleted"
 	BypassActionCreated             = "created"
@@ -98,9 +99,9 @@ func (a ResourceType) Validate() error {
 }
 
 type Resource struct {
-	Type       ResourceType
-	Identifier string
-	Data       map[string]string
+	Type       ResourceType      `json:"type"`
+	Identifier string            `json:"identifier"`
+	Data       map[string]string `json:"data,omitempty"`
 }
 
 func NewResource(rtype ResourceType, identifier string, keyValues ...string) Resource {
@@ -135,21 +136,21 @@ func (r Resource) DataAsSlice() []string {
 }
 
 type DiffObject struct {
-	OldObject any
-	NewObject any
+	OldObject any `json:"old_object,omitempty"`
+	NewObject any `json:"new_object,omitempty"`
 }
 
 type Event struct {
-	ID            string
-	Timestamp     int64
-	Action        Action          // example: ActionCreated
-	User          types.Principal // example: Admin
-	SpacePath     string          // example: /root/projects
-	Resource      Resource
-	DiffObject    DiffObject
-	ClientIP      string
-	RequestMethod string
-	Data          map[string]string // internal data like correlationID/requestID
+	ID            string            `json:"id"`
+	Timestamp     int64             `json:"timestamp"`
+	Action        Action            `json:"action"`     // example: ActionCreated
+	User          types.Principal   `json:"user"`       // example: Admin
+	SpacePath     string            `json:"space_path"` // example: /root/projects
+	Resource      Resource          `json:"resource"`
+	DiffObject    DiffObject        `json:"diff_object"`
+	ClientIP      string            `json:"client_ip,omitempty"`
+	RequestMethod string            `json:"request_method,omitempty"`
+	Data          map[string]string `json:"data,omitempty"` // internal data like correlationID/requestID
 }
 
 func (e *Event) Validate() error {
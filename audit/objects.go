@@ -50,6 +50,12 @@ type BranchObject struct {
 	RuleViolations []types.RuleViolations `yaml:"rule_violations"`
 }
 
+type TagObject struct {
+	TagName        string                 `yaml:"tag_name"`
+	RepoPath       string                 `yaml:"repo_path"`
+	RuleViolations []types.RuleViolations `yaml:"rule_violations"`
+}
+
 type RegistryUpstreamProxyConfigObject struct {
 	ID         int64
 	RegistryID int64
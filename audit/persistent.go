@@ -0,0 +1,76 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/types"
+
+	"github.com/google/uuid"
+)
+
+const dataKeyRequestID = "requestID"
+
+// Persistent is an audit Service that stores every logged event in the provided Store.
+type Persistent struct {
+	store Store
+}
+
+func NewPersistent(store Store) *Persistent {
+	return &Persistent{
+		store: store,
+	}
+}
+
+func (s *Persistent) Log(
+	ctx context.Context,
+	user types.Principal,
+	resource Resource,
+	action Action,
+	spacePath string,
+	options ...Option,
+) error {
+	event := Event{
+		ID:            uuid.New().String(),
+		Timestamp:     time.Now().UnixMilli(),
+		Action:        action,
+		User:          user,
+		SpacePath:     spacePath,
+		Resource:      resource,
+		ClientIP:      GetRealIP(ctx),
+		RequestMethod: GetRequestMethod(ctx),
+	}
+
+	if requestID := GetRequestID(ctx); requestID != "" {
+		event.Data = map[string]string{dataKeyRequestID: requestID}
+	}
+
+	for _, option := range options {
+		option.Apply(&event)
+	}
+
+	if err := event.Validate(); err != nil {
+		return fmt.Errorf("invalid audit event: %w", err)
+	}
+
+	if err := s.store.Create(ctx, &event); err != nil {
+		return fmt.Errorf("failed to store audit event: %w", err)
+	}
+
+	return nil
+}
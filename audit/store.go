@@ -0,0 +1,52 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+// EventFilter stores audit event query parameters.
+type EventFilter struct {
+	Page int `json:"page"`
+	Size int `json:"size"`
+
+	// SpaceIDs restricts the result to the events of the spaces, ignored if empty.
+	SpaceIDs []int64 `json:"space_ids"`
+
+	ResourceTypes []ResourceType `json:"resource_types"`
+	Actions       []Action       `json:"actions"`
+	PrincipalIDs  []int64        `json:"principal_ids"`
+
+	// From and To are the inclusive time range boundaries (unix milliseconds), ignored if zero.
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// Store defines the audit event data storage.
+type Store interface {
+	// Create saves the audit event.
+	Create(ctx context.Context, event *Event) error
+
+	// Count returns the number of audit events matching the provided filter.
+	Count(ctx context.Context, filter *EventFilter) (int64, error)
+
+	// List returns the audit events matching the provided filter, newest first.
+	List(ctx context.Context, filter *EventFilter) ([]*Event, error)
+
+	// DeleteOld removes all audit events that are older than the provided time.
+	DeleteOld(ctx context.Context, olderThan time.Time) (int64, error)
+}
@@ -34,4 +34,10 @@ type Store interface {
 
 	// Download returns a reader for a file in the blob store.
 	Download(ctx context.Context, filePath string) (io.ReadCloser, error)
+
+	// Delete deletes a file from the blob store. Deleting a non-existing file isn't an error.
+	Delete(ctx context.Context, filePath string) error
+
+	// Move moves a file within the blob store, replacing the destination file if it exists.
+	Move(ctx context.Context, srcPath string, dstPath string) error
 }
@@ -0,0 +1,86 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/harness/gitness/app/services/backup"
+	"github.com/harness/gitness/blob"
+	"github.com/harness/gitness/cli/operations/server"
+	"github.com/harness/gitness/git"
+
+	"github.com/joho/godotenv"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const registryStorageFilesystem = "filesystem"
+
+// Register the backup command.
+func Register(app *kingpin.Application) {
+	cmd := app.Command("backup", "instance backup and restore tool")
+	registerCreate(cmd)
+	registerRestore(cmd)
+}
+
+// loadConfig loads the server configuration and extracts the locations of the instance data from it.
+func loadConfig(envfile string) (backup.Config, error) {
+	_ = godotenv.Load(envfile)
+
+	config, err := server.LoadConfig()
+	if err != nil {
+		return backup.Config{}, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	blobConfig, err := server.ProvideBlobStoreConfig(config)
+	if err != nil {
+		return backup.Config{}, fmt.Errorf("failed to load blob store configuration: %w", err)
+	}
+
+	backupConfig := backup.Config{
+		DatabaseDriver:     config.Database.Driver,
+		DatabaseDatasource: config.Database.Datasource,
+		ReposRoot:          filepath.Join(config.Git.Root, git.ReposSubdirName),
+		HookPath:           config.Git.HookPath,
+	}
+
+	if blobConfig.Provider == blob.ProviderFileSystem {
+		backupConfig.BlobStoreRoot = blobConfig.Bucket
+	} else {
+		log.Warn().Msgf("blob store provider %q isn't backed up, use the provider's own backup tools",
+			blobConfig.Provider)
+	}
+
+	storage := config.Registry.Storage
+	switch {
+	case !config.Registry.Enable:
+	case storage.StorageType == registryStorageFilesystem && storage.FileSystemStorage.RootDirectory != "":
+		backupConfig.RegistryRoot = storage.FileSystemStorage.RootDirectory
+	default:
+		log.Warn().Msg("registry storage isn't on a configured local directory and isn't backed up")
+	}
+
+	return backupConfig, nil
+}
+
+func setupLoggingContext(ctx context.Context) context.Context {
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	log := log.Logger.With().Logger()
+	return log.WithContext(ctx)
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/harness/gitness/app/services/backup"
+	"github.com/harness/gitness/store/database"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type commandCreate struct {
+	file    string
+	envfile string
+}
+
+func (c *commandCreate) run(*kingpin.ParseContext) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ctx = setupLoggingContext(ctx)
+
+	config, err := loadConfig(c.envfile)
+	if err != nil {
+		return err
+	}
+
+	db, err := database.Connect(ctx, config.DatabaseDriver, config.DatabaseDatasource)
+	if err != nil {
+		return fmt.Errorf("failed to create database handle: %w", err)
+	}
+	defer db.Close()
+
+	f, err := os.OpenFile(c.file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create the backup file: %w", err)
+	}
+
+	manifest, err := backup.Create(ctx, config, db, f)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(c.file)
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close the backup file: %w", err)
+	}
+
+	fmt.Printf("backup %s created (migration version %s, components %v)\n",
+		c.file, manifest.MigrationVersion, manifest.Components)
+
+	return nil
+}
+
+func registerCreate(app *kingpin.CmdClause) {
+	c := &commandCreate{}
+
+	cmd := app.Command("create", "create a backup archive of the instance data").
+		Action(c.run)
+
+	cmd.Arg("file", "path of the backup archive to create").
+		Required().
+		StringVar(&c.file)
+
+	cmd.Arg("envfile", "load the environment variable file").
+		Default("").
+		StringVar(&c.envfile)
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/harness/gitness/app/services/backup"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type commandRestore struct {
+	file    string
+	envfile string
+}
+
+func (c *commandRestore) run(*kingpin.ParseContext) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ctx = setupLoggingContext(ctx)
+
+	config, err := loadConfig(c.envfile)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(c.file)
+	if err != nil {
+		return fmt.Errorf("failed to open the backup file: %w", err)
+	}
+	defer f.Close()
+
+	manifest, err := backup.Restore(ctx, config, f)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("backup %s restored (created by version %s, migration version %s)\n",
+		c.file, manifest.GitnessVersion, manifest.MigrationVersion)
+
+	return nil
+}
+
+func registerRestore(app *kingpin.CmdClause) {
+	c := &commandRestore{}
+
+	cmd := app.Command("restore", "restore a backup archive onto a fresh instance, the server must not be running").
+		Action(c.run)
+
+	cmd.Arg("file", "path of the backup archive to restore").
+		Required().
+		StringVar(&c.file)
+
+	cmd.Arg("envfile", "load the environment variable file").
+		Default("").
+		StringVar(&c.envfile)
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/harness/gitness/app/store/database/migrate"
+	"github.com/harness/gitness/store/database"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const driverPostgres = "postgres"
+
+type commandPostgres struct {
+	envfile    string
+	datasource string
+	batchSize  int
+}
+
+func (c *commandPostgres) run(_ *kingpin.ParseContext) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ctx = setupLoggingContext(ctx)
+
+	src, err := getDB(ctx, c.envfile)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := database.Connect(ctx, driverPostgres, c.datasource)
+	if err != nil {
+		return fmt.Errorf("failed to create postgres database handle: %w", err)
+	}
+	defer dst.Close()
+
+	if err := migrate.SqliteToPostgres(ctx, src, dst, c.batchSize); err != nil {
+		return fmt.Errorf("failed to copy the database, fix the problem and run the command again to resume: %w", err)
+	}
+
+	fmt.Println("database copied, set GITNESS_DATABASE_DRIVER=postgres and GITNESS_DATABASE_DATASOURCE " +
+		"to use the postgres database")
+
+	return nil
+}
+
+func registerPostgres(app *kingpin.CmdClause) {
+	c := &commandPostgres{}
+
+	cmd := app.Command("sqlite-to-postgres", "copies the configured sqlite database into an empty "+
+		"postgres database, the server must not be running").
+		Action(c.run)
+
+	cmd.Arg("datasource", "datasource of the target postgres database").
+		Required().
+		StringVar(&c.datasource)
+
+	cmd.Arg("envfile", "load the environment variable file").
+		Default("").
+		StringVar(&c.envfile)
+
+	cmd.Flag("batch-size", "number of rows copied in a single transaction").
+		Default(fmt.Sprint(migrate.DefaultImportBatchSize)).
+		IntVar(&c.batchSize)
+}
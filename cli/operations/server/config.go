@@ -29,6 +29,7 @@ import (
 	"github.com/harness/gitness/app/services/codeowners"
 	"github.com/harness/gitness/app/services/gitspaceevent"
 	"github.com/harness/gitness/app/services/keywordsearch"
+	"github.com/harness/gitness/app/services/mirror"
 	"github.com/harness/gitness/app/services/notification"
 	"github.com/harness/gitness/app/services/trigger"
 	"github.com/harness/gitness/app/services/webhook"
@@ -39,6 +40,7 @@ import (
 	"github.com/harness/gitness/job"
 	"github.com/harness/gitness/lock"
 	"github.com/harness/gitness/pubsub"
+	registrycleanup "github.com/harness/gitness/registry/cleanup"
 	"github.com/harness/gitness/store/database"
 	"github.com/harness/gitness/types"
 
@@ -49,11 +51,12 @@ import (
 )
 
 const (
-	schemeHTTP     = "http"
-	schemeHTTPS    = "https"
-	schemeSSH      = "ssh"
-	gitnessHomeDir = ".gitness"
-	blobDir        = "blob"
+	schemeHTTP            = "http"
+	schemeHTTPS           = "https"
+	schemeSSH             = "ssh"
+	gitnessHomeDir        = ".gitness"
+	blobDir               = "blob"
+	keywordSearchIndexDir = "keywordsearch"
 )
 
 // LoadConfig returns the system configuration from the
@@ -334,11 +337,26 @@ func ProvideWebhookConfig(config *types.Config) webhook.Config {
 	}
 }
 
+// ProvideMirrorConfig loads the mirror service config from the main config.
+func ProvideMirrorConfig(config *types.Config) mirror.Config {
+	return mirror.Config{
+		AllowPrivateNetwork: config.Mirror.AllowPrivateNetwork,
+		AllowLoopback:       config.Mirror.AllowLoopback,
+	}
+}
+
 func ProvideNotificationConfig(config *types.Config) notification.Config {
 	return notification.Config{
 		EventReaderName: config.InstanceID,
 		Concurrency:     config.Notification.Concurrency,
 		MaxRetries:      config.Notification.MaxRetries,
+
+		SlackWebhookURL:        config.Notification.Slack.WebhookURL,
+		SlackBotToken:          config.Notification.Slack.BotToken,
+		TeamsWebhookURL:        config.Notification.Teams.WebhookURL,
+		ChatWebhookURL:         config.Notification.ChatWebhook.URL,
+		ChatWebhookTemplate:    config.Notification.ChatWebhook.Template,
+		ChatWebhookContentType: config.Notification.ChatWebhook.ContentType,
 	}
 }
 
@@ -382,6 +400,14 @@ func ProvideCleanupConfig(config *types.Config) cleanup.Config {
 	return cleanup.Config{
 		WebhookExecutionsRetentionTime:   config.Webhook.RetentionTime,
 		DeletedRepositoriesRetentionTime: config.Repos.DeletedRetentionTime,
+		AuditEventsRetentionTime:         config.Audit.RetentionTime,
+	}
+}
+
+// ProvideRegistryCleanupConfig loads the registry cleanup service config from the main config.
+func ProvideRegistryCleanupConfig(config *types.Config) registrycleanup.Config {
+	return registrycleanup.Config{
+		DryRun: config.Registry.CleanupPolicy.DryRun,
 	}
 }
 
@@ -394,10 +420,17 @@ func ProvideCodeOwnerConfig(config *types.Config) codeowners.Config {
 
 // ProvideKeywordSearchConfig loads the keyword search service config from the main config.
 func ProvideKeywordSearchConfig(config *types.Config) keywordsearch.Config {
+	indexRoot := config.KeywordSearch.IndexRoot
+	if indexRoot == "" {
+		indexRoot = filepath.Join(config.Git.Root, keywordSearchIndexDir)
+	}
+
 	return keywordsearch.Config{
 		EventReaderName: config.InstanceID,
 		Concurrency:     config.KeywordSearch.Concurrency,
 		MaxRetries:      config.KeywordSearch.MaxRetries,
+		IndexRoot:       indexRoot,
+		IndexCacheSize:  config.KeywordSearch.IndexCacheSize,
 	}
 }
 
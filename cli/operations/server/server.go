@@ -105,6 +105,31 @@ func (c *command) run(*kingpin.ParseContext) error {
 			return err
 		}
 
+		if err := system.services.Trigger.Register(gCtx); err != nil {
+			log.Error().Err(err).Msg("failed to register cron trigger job")
+			return err
+		}
+
+		if err := system.services.Mirror.Register(gCtx); err != nil {
+			log.Error().Err(err).Msg("failed to register mirror scheduling job")
+			return err
+		}
+
+		if err := system.services.MergeQueue.Register(gCtx); err != nil {
+			log.Error().Err(err).Msg("failed to register merge queue processing job")
+			return err
+		}
+
+		if err := system.services.LDAPSync.Register(gCtx); err != nil {
+			log.Error().Err(err).Msg("failed to register LDAP group sync job")
+			return err
+		}
+
+		if err := system.services.RegistryCleanup.Register(gCtx); err != nil {
+			log.Error().Err(err).Msg("failed to register registry cleanup service")
+			return err
+		}
+
 		return system.services.JobScheduler.Run(gCtx)
 	})
 
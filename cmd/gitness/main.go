@@ -18,6 +18,7 @@ import (
 	"github.com/harness/gitness/app/api/openapi"
 	"github.com/harness/gitness/cli"
 	"github.com/harness/gitness/cli/operations/account"
+	"github.com/harness/gitness/cli/operations/backup"
 	"github.com/harness/gitness/cli/operations/hooks"
 	"github.com/harness/gitness/cli/operations/migrate"
 	"github.com/harness/gitness/cli/operations/server"
@@ -40,6 +41,7 @@ func main() {
 	app := kingpin.New(application, description)
 
 	migrate.Register(app)
+	backup.Register(app)
 	server.Register(app, initSystem)
 
 	user.Register(app)
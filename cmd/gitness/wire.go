@@ -21,6 +21,7 @@ import (
 	"context"
 
 	"github.com/harness/gitness/app/api/controller/aiagent"
+	controlleraudit "github.com/harness/gitness/app/api/controller/audit"
 	"github.com/harness/gitness/app/api/controller/capabilities"
 	checkcontroller "github.com/harness/gitness/app/api/controller/check"
 	"github.com/harness/gitness/app/api/controller/connector"
@@ -29,15 +30,19 @@ import (
 	gitspaceCtrl "github.com/harness/gitness/app/api/controller/gitspace"
 	infraproviderCtrl "github.com/harness/gitness/app/api/controller/infraprovider"
 	controllerkeywordsearch "github.com/harness/gitness/app/api/controller/keywordsearch"
+	"github.com/harness/gitness/app/api/controller/lfs"
 	"github.com/harness/gitness/app/api/controller/limiter"
 	controllerlogs "github.com/harness/gitness/app/api/controller/logs"
 	"github.com/harness/gitness/app/api/controller/migrate"
+	controllermirror "github.com/harness/gitness/app/api/controller/mirror"
+	controlleroidc "github.com/harness/gitness/app/api/controller/oidc"
 	"github.com/harness/gitness/app/api/controller/pipeline"
 	"github.com/harness/gitness/app/api/controller/plugin"
 	"github.com/harness/gitness/app/api/controller/principal"
 	"github.com/harness/gitness/app/api/controller/pullreq"
 	"github.com/harness/gitness/app/api/controller/repo"
 	"github.com/harness/gitness/app/api/controller/reposettings"
+	controllerrunner "github.com/harness/gitness/app/api/controller/runner"
 	"github.com/harness/gitness/app/api/controller/secret"
 	"github.com/harness/gitness/app/api/controller/service"
 	"github.com/harness/gitness/app/api/controller/serviceaccount"
@@ -52,8 +57,11 @@ import (
 	"github.com/harness/gitness/app/api/openapi"
 	"github.com/harness/gitness/app/auth/authn"
 	"github.com/harness/gitness/app/auth/authz"
+	"github.com/harness/gitness/app/auth/ldap"
+	authoidc "github.com/harness/gitness/app/auth/oidc"
 	"github.com/harness/gitness/app/bootstrap"
 	connectorservice "github.com/harness/gitness/app/connector"
+	checkevents "github.com/harness/gitness/app/events/check"
 	gitevents "github.com/harness/gitness/app/events/git"
 	gitspaceevents "github.com/harness/gitness/app/events/gitspace"
 	gitspaceinfraevents "github.com/harness/gitness/app/events/gitspaceinfra"
@@ -81,7 +89,9 @@ import (
 	"github.com/harness/gitness/app/router"
 	"github.com/harness/gitness/app/server"
 	"github.com/harness/gitness/app/services"
+	"github.com/harness/gitness/app/services/accountmail"
 	aiagentservice "github.com/harness/gitness/app/services/aiagent"
+	"github.com/harness/gitness/app/services/automerge"
 	capabilitiesservice "github.com/harness/gitness/app/services/capabilities"
 	"github.com/harness/gitness/app/services/cleanup"
 	"github.com/harness/gitness/app/services/codecomments"
@@ -93,10 +103,13 @@ import (
 	"github.com/harness/gitness/app/services/instrument"
 	"github.com/harness/gitness/app/services/keywordsearch"
 	svclabel "github.com/harness/gitness/app/services/label"
+	"github.com/harness/gitness/app/services/ldapsync"
 	locker "github.com/harness/gitness/app/services/locker"
+	"github.com/harness/gitness/app/services/mergequeue"
 	messagingservice "github.com/harness/gitness/app/services/messaging"
 	"github.com/harness/gitness/app/services/metric"
 	migrateservice "github.com/harness/gitness/app/services/migrate"
+	mirrorservice "github.com/harness/gitness/app/services/mirror"
 	"github.com/harness/gitness/app/services/notification"
 	"github.com/harness/gitness/app/services/notification/mailer"
 	"github.com/harness/gitness/app/services/protection"
@@ -133,6 +146,7 @@ import (
 	"github.com/harness/gitness/lock"
 	"github.com/harness/gitness/pubsub"
 	"github.com/harness/gitness/registry/app/pkg/docker"
+	registrycleanup "github.com/harness/gitness/registry/cleanup"
 	"github.com/harness/gitness/ssh"
 	"github.com/harness/gitness/store/database/dbtx"
 	"github.com/harness/gitness/types"
@@ -173,6 +187,18 @@ func initSystem(ctx context.Context, config *types.Config) (*cliserver.System, e
 		serviceaccount.WireSet,
 		user.WireSet,
 		upload.WireSet,
+		lfs.WireSet,
+		controllermirror.WireSet,
+		mirrorservice.WireSet,
+		cliserver.ProvideMirrorConfig,
+		authoidc.WireSet,
+		ldap.WireSet,
+		ldapsync.WireSet,
+		accountmail.WireSet,
+		controlleroidc.WireSet,
+		controllerrunner.WireSet,
+		automerge.WireSet,
+		mergequeue.WireSet,
 		service.WireSet,
 		principal.WireSet,
 		usergroupservice.WireSet,
@@ -187,6 +213,7 @@ func initSystem(ctx context.Context, config *types.Config) (*cliserver.System, e
 		gitspaceCtrl.WireSet,
 		gitevents.WireSet,
 		pullreqevents.WireSet,
+		checkevents.WireSet,
 		repoevents.WireSet,
 		storage.WireSet,
 		api.WireSet,
@@ -213,6 +240,8 @@ func initSystem(ctx context.Context, config *types.Config) (*cliserver.System, e
 		job.WireSet,
 		cliserver.ProvideCleanupConfig,
 		cleanup.WireSet,
+		cliserver.ProvideRegistryCleanupConfig,
+		registrycleanup.WireSet,
 		codecomments.WireSet,
 		protection.WireSet,
 		checkcontroller.WireSet,
@@ -255,6 +284,7 @@ func initSystem(ctx context.Context, config *types.Config) (*cliserver.System, e
 		openapi.WireSet,
 		repo.ProvideRepoCheck,
 		audit.WireSet,
+		controlleraudit.WireSet,
 		ssh.WireSet,
 		publickey.WireSet,
 		migrate.WireSet,
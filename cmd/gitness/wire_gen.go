@@ -10,6 +10,7 @@ import (
 	"context"
 
 	aiagent2 "github.com/harness/gitness/app/api/controller/aiagent"
+	audit2 "github.com/harness/gitness/app/api/controller/audit"
 	capabilities2 "github.com/harness/gitness/app/api/controller/capabilities"
 	check2 "github.com/harness/gitness/app/api/controller/check"
 	connector2 "github.com/harness/gitness/app/api/controller/connector"
@@ -18,15 +19,19 @@ import (
 	gitspace2 "github.com/harness/gitness/app/api/controller/gitspace"
 	infraprovider3 "github.com/harness/gitness/app/api/controller/infraprovider"
 	keywordsearch2 "github.com/harness/gitness/app/api/controller/keywordsearch"
+	"github.com/harness/gitness/app/api/controller/lfs"
 	"github.com/harness/gitness/app/api/controller/limiter"
 	logs2 "github.com/harness/gitness/app/api/controller/logs"
 	migrate2 "github.com/harness/gitness/app/api/controller/migrate"
+	mirror2 "github.com/harness/gitness/app/api/controller/mirror"
+	oidc2 "github.com/harness/gitness/app/api/controller/oidc"
 	"github.com/harness/gitness/app/api/controller/pipeline"
 	"github.com/harness/gitness/app/api/controller/plugin"
 	"github.com/harness/gitness/app/api/controller/principal"
 	pullreq2 "github.com/harness/gitness/app/api/controller/pullreq"
 	"github.com/harness/gitness/app/api/controller/repo"
 	"github.com/harness/gitness/app/api/controller/reposettings"
+	runner2 "github.com/harness/gitness/app/api/controller/runner"
 	secret2 "github.com/harness/gitness/app/api/controller/secret"
 	"github.com/harness/gitness/app/api/controller/service"
 	"github.com/harness/gitness/app/api/controller/serviceaccount"
@@ -41,8 +46,11 @@ import (
 	"github.com/harness/gitness/app/api/openapi"
 	"github.com/harness/gitness/app/auth/authn"
 	"github.com/harness/gitness/app/auth/authz"
+	"github.com/harness/gitness/app/auth/ldap"
+	"github.com/harness/gitness/app/auth/oidc"
 	"github.com/harness/gitness/app/bootstrap"
 	"github.com/harness/gitness/app/connector"
+	events8 "github.com/harness/gitness/app/events/check"
 	events7 "github.com/harness/gitness/app/events/git"
 	events3 "github.com/harness/gitness/app/events/gitspace"
 	events4 "github.com/harness/gitness/app/events/gitspaceinfra"
@@ -70,7 +78,9 @@ import (
 	router2 "github.com/harness/gitness/app/router"
 	server2 "github.com/harness/gitness/app/server"
 	"github.com/harness/gitness/app/services"
+	"github.com/harness/gitness/app/services/accountmail"
 	"github.com/harness/gitness/app/services/aiagent"
+	"github.com/harness/gitness/app/services/automerge"
 	"github.com/harness/gitness/app/services/capabilities"
 	"github.com/harness/gitness/app/services/cleanup"
 	"github.com/harness/gitness/app/services/codecomments"
@@ -84,10 +94,13 @@ import (
 	"github.com/harness/gitness/app/services/instrument"
 	"github.com/harness/gitness/app/services/keywordsearch"
 	"github.com/harness/gitness/app/services/label"
+	"github.com/harness/gitness/app/services/ldapsync"
 	"github.com/harness/gitness/app/services/locker"
+	"github.com/harness/gitness/app/services/mergequeue"
 	"github.com/harness/gitness/app/services/messaging"
 	"github.com/harness/gitness/app/services/metric"
 	"github.com/harness/gitness/app/services/migrate"
+	"github.com/harness/gitness/app/services/mirror"
 	"github.com/harness/gitness/app/services/notification"
 	"github.com/harness/gitness/app/services/notification/mailer"
 	"github.com/harness/gitness/app/services/protection"
@@ -128,6 +141,7 @@ import (
 	"github.com/harness/gitness/registry/app/pkg"
 	"github.com/harness/gitness/registry/app/pkg/docker"
 	database2 "github.com/harness/gitness/registry/app/store/database"
+	cleanup2 "github.com/harness/gitness/registry/cleanup"
 	"github.com/harness/gitness/registry/gc"
 	"github.com/harness/gitness/ssh"
 	"github.com/harness/gitness/store/database/dbtx"
@@ -163,26 +177,40 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	repoStore := database.ProvideRepoStore(db, spacePathCache, spacePathStore, spaceStore)
 	repoFinder := refcache.ProvideRepoFinder(repoStore, spaceCache)
 	publicaccessService := publicaccess.ProvidePublicAccess(config, publicAccessStore, spaceCache, repoFinder)
-	authorizer := authz.ProvideAuthorizer(permissionCache, spaceStore, publicaccessService)
+	authorizer := authz.ProvideAuthorizer(permissionCache, spaceStore, repoFinder, publicaccessService)
 	principalUIDTransformation := store.ProvidePrincipalUIDTransformation()
 	principalStore := database.ProvidePrincipalStore(db, principalUIDTransformation)
 	tokenStore := database.ProvideTokenStore(db)
 	publicKeyStore := database.ProvidePublicKeyStore(db)
-	controller := user.ProvideController(transactor, principalUID, authorizer, principalStore, tokenStore, membershipStore, publicKeyStore)
-	serviceController := service.NewController(principalUID, authorizer, principalStore)
-	bootstrapBootstrap := bootstrap.ProvideBootstrap(config, controller, serviceController)
-	authenticator := authn.ProvideAuthenticator(config, principalStore, tokenStore)
+	directory := ldap.ProvideDirectory(config)
+	ldapIdentityStore := database.ProvideLDAPIdentityStore(db)
+	userTOTPStore := database.ProvideUserTOTPStore(db)
+	encrypter, err := encrypt.ProvideEncrypter(config)
+	if err != nil {
+		return nil, err
+	}
+	settingsStore := database.ProvideSettingsStore(db)
+	settingsService := settings.ProvideService(settingsStore)
+	systemService := system2.ProvideService(settingsService)
 	provider, err := url.ProvideURLProvider(config)
 	if err != nil {
 		return nil, err
 	}
+	mailerMailer := mailer.ProvideMailClient(config)
+	userMailLimitStore := database.ProvideUserMailLimitStore(db)
+	accountmailService := accountmail.ProvideService(config, mailerMailer, provider, principalStore, userMailLimitStore)
+	userChatIdentityStore := database.ProvideUserChatIdentityStore(db)
+	notificationConfig := server.ProvideNotificationConfig(config)
+	directMessenger := notification.ProvideDirectMessenger(notificationConfig)
+	controller := user.ProvideController(config, transactor, principalUID, authorizer, principalStore, tokenStore, membershipStore, publicKeyStore, directory, ldapIdentityStore, userTOTPStore, encrypter, systemService, spaceStore, repoFinder, accountmailService, userChatIdentityStore, directMessenger)
+	serviceController := service.NewController(principalUID, authorizer, principalStore)
+	bootstrapBootstrap := bootstrap.ProvideBootstrap(config, controller, serviceController)
+	authenticator := authn.ProvideAuthenticator(config, principalStore, tokenStore)
 	pipelineStore := database.ProvidePipelineStore(db)
 	executionStore := database.ProvideExecutionStore(db)
 	ruleStore := database.ProvideRuleStore(db, principalInfoCache)
 	checkStore := database.ProvideCheckStore(db, principalInfoCache)
 	pullReqStore := database.ProvidePullReqStore(db, principalInfoCache)
-	settingsStore := database.ProvideSettingsStore(db)
-	settingsService := settings.ProvideService(settingsStore)
 	protectionManager, err := protection.ProvideManager(ruleStore)
 	if err != nil {
 		return nil, err
@@ -207,10 +235,6 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 		return nil, err
 	}
 	triggerStore := database.ProvideTriggerStore(db)
-	encrypter, err := encrypt.ProvideEncrypter(config)
-	if err != nil {
-		return nil, err
-	}
 	jobStore := database.ProvideJobStore(db)
 	pubsubConfig := server.ProvidePubsubConfig(config)
 	pubSub := pubsub.ProvidePubSub(pubsubConfig, universalClient)
@@ -223,15 +247,20 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 		return nil, err
 	}
 	streamer := sse.ProvideEventsStreaming(pubSub)
-	localIndexSearcher := keywordsearch.ProvideLocalIndexSearcher()
+	keywordsearchConfig := server.ProvideKeywordSearchConfig(config)
+	localIndexSearcher := keywordsearch.ProvideLocalIndexSearcher(keywordsearchConfig, gitInterface, repoStore)
 	indexer := keywordsearch.ProvideIndexer(localIndexSearcher)
-	auditService := audit.ProvideAuditService()
+	auditStore := database.ProvideAuditEventStore(db, spacePathStore)
+	auditService := audit.ProvideAuditService(auditStore)
 	repository, err := importer.ProvideRepoImporter(config, provider, gitInterface, transactor, repoStore, pipelineStore, triggerStore, encrypter, jobScheduler, executor, streamer, indexer, publicaccessService, auditService)
 	if err != nil {
 		return nil, err
 	}
 	codeownersConfig := server.ProvideCodeOwnerConfig(config)
-	usergroupResolver := usergroup.ProvideUserGroupResolver()
+	userGroupStore := database.ProvideUserGroupStore(db)
+	userGroupMemberStore := database.ProvideUserGroupMemberStore(db)
+	searchService := usergroup.ProvideSearchService(spaceCache, userGroupStore, userGroupMemberStore, principalInfoCache)
+	usergroupResolver := usergroup.ProvideUserGroupResolver(config, spaceCache, userGroupStore, searchService)
 	codeownersService := codeowners.ProvideCodeOwners(gitInterface, repoStore, codeownersConfig, principalStore, usergroupResolver)
 	eventsConfig := server.ProvideEventsConfig(config)
 	eventsSystem, err := events.ProvideSystem(eventsConfig, universalClient)
@@ -254,10 +283,18 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	pullReqLabelAssignmentStore := database.ProvidePullReqLabelStore(db)
 	labelService := label.ProvideLabel(transactor, spaceStore, labelStore, labelValueStore, pullReqLabelAssignmentStore)
 	instrumentService := instrument.ProvideService()
-	userGroupStore := database.ProvideUserGroupStore(db)
-	searchService := usergroup.ProvideSearchService()
 	rulesService := rules.ProvideService(transactor, ruleStore, repoStore, spaceStore, protectionManager, auditService, instrumentService, principalInfoCache, userGroupStore, searchService, streamer)
-	repoController := repo.ProvideController(config, transactor, provider, authorizer, repoStore, spaceStore, pipelineStore, principalStore, executionStore, ruleStore, checkStore, pullReqStore, settingsService, principalInfoCache, protectionManager, gitInterface, spaceCache, repoFinder, repository, codeownersService, reporter, indexer, resourceLimiter, lockerLocker, auditService, mutexManager, repoIdentifier, repoCheck, publicaccessService, labelService, instrumentService, userGroupStore, searchService, rulesService, streamer)
+	publickeyService := publickey.ProvidePublicKey(publicKeyStore, principalStore, principalInfoCache)
+	lfsObjectStore := database.ProvideLFSObjectStore(db)
+	blobConfig, err := server.ProvideBlobStoreConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	blobStore, err := blob.ProvideStore(ctx, blobConfig)
+	if err != nil {
+		return nil, err
+	}
+	repoController := repo.ProvideController(config, transactor, provider, authorizer, repoStore, spaceStore, pipelineStore, principalStore, executionStore, ruleStore, checkStore, pullReqStore, settingsService, principalInfoCache, protectionManager, gitInterface, spaceCache, repoFinder, repository, codeownersService, reporter, indexer, resourceLimiter, lockerLocker, auditService, mutexManager, repoIdentifier, repoCheck, publicaccessService, labelService, instrumentService, userGroupStore, searchService, rulesService, streamer, publickeyService, lfsObjectStore, blobStore)
 	reposettingsController := reposettings.ProvideController(authorizer, repoFinder, settingsService, auditService)
 	stageStore := database.ProvideStageStore(db)
 	schedulerScheduler, err := scheduler.ProvideScheduler(stageStore, mutexManager)
@@ -271,13 +308,18 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	converterService := converter.ProvideService(fileService, publicaccessService)
 	templateStore := database.ProvideTemplateStore(db)
 	pluginStore := database.ProvidePluginStore(db)
-	triggererTriggerer := triggerer.ProvideTriggerer(executionStore, checkStore, stageStore, transactor, pipelineStore, fileService, converterService, schedulerScheduler, repoStore, provider, templateStore, pluginStore, publicaccessService)
-	executionController := execution.ProvideController(transactor, authorizer, executionStore, checkStore, cancelerCanceler, commitService, triggererTriggerer, stageStore, pipelineStore, repoFinder)
+	triggererTriggerer := triggerer.ProvideTriggerer(executionStore, checkStore, stageStore, stepStore, transactor, pipelineStore, fileService, converterService, schedulerScheduler, repoStore, provider, templateStore, pluginStore, publicaccessService)
 	logStore := logs.ProvideLogStore(db, config)
 	logStream := livelog.ProvideLogStream()
+	secretStore := database.ProvideSecretStore(db)
+	reporter3, err := events5.ProvideReporter(eventsSystem)
+	if err != nil {
+		return nil, err
+	}
+	executionManager := manager.ProvideExecutionManager(config, executionStore, pipelineStore, provider, streamer, fileService, converterService, logStore, logStream, checkStore, repoStore, schedulerScheduler, secretStore, stageStore, stepStore, principalStore, publicaccessService, reporter3)
+	executionController := execution.ProvideController(transactor, authorizer, executionStore, checkStore, cancelerCanceler, commitService, triggererTriggerer, stageStore, pipelineStore, repoFinder, schedulerScheduler, executionManager)
 	logsController := logs2.ProvideController(authorizer, executionStore, pipelineStore, stageStore, stepStore, logStore, logStream, repoFinder)
 	spaceIdentifier := check.ProvideSpaceIdentifierCheck()
-	secretStore := database.ProvideSecretStore(db)
 	connectorStore := database.ProvideConnectorStore(db, secretStore)
 	repoGitInfoView := database.ProvideRepoGitInfoView(db)
 	repoGitInfoCache := cache.ProvideRepoGitInfoCache(repoGitInfoView)
@@ -341,11 +383,21 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	orchestratorOrchestrator := orchestrator.ProvideOrchestrator(scmSCM, platformConnector, infraProvisioner, containerOrchestrator, eventsReporter, orchestratorConfig, ideFactory, resolverFactory)
 	gitspaceService := gitspace.ProvideGitspace(transactor, gitspaceConfigStore, gitspaceInstanceStore, eventsReporter, gitspaceEventStore, spaceStore, infraproviderService, orchestratorOrchestrator, scmSCM, config)
 	usageMetricStore := database.ProvideUsageMetricStore(db)
-	spaceController := space.ProvideController(config, transactor, provider, streamer, spaceIdentifier, authorizer, spacePathStore, pipelineStore, secretStore, connectorStore, templateStore, spaceStore, repoStore, principalStore, repoController, membershipStore, listService, spaceCache, repository, exporterRepository, resourceLimiter, publicaccessService, auditService, gitspaceService, labelService, instrumentService, executionStore, rulesService, usageMetricStore)
-	reporter3, err := events5.ProvideReporter(eventsSystem)
+	pullReqActivityStore := database.ProvidePullReqActivityStore(db, principalInfoCache)
+	pullReqReviewerStore := database.ProvidePullReqReviewerStore(db, principalInfoCache)
+	pullReq := migrate.ProvidePullReqImporter(provider, gitInterface, principalStore, spaceStore, repoStore, pullReqStore, pullReqActivityStore, labelStore, labelValueStore, pullReqLabelAssignmentStore, transactor, mutexManager)
+	webhookConfig := server.ProvideWebhookConfig(config)
+	webhookStore := database.ProvideWebhookStore(db)
+	rule := migrate.ProvideRuleImporter(ruleStore, transactor, principalStore)
+	migrateWebhook := migrate.ProvideWebhookImporter(webhookConfig, transactor, webhookStore)
+	migrateLabel := migrate.ProvideLabelImporter(transactor, labelStore, labelValueStore, spaceStore)
+	archive := exporter.ProvideArchiveExporter(gitInterface, repoStore, principalInfoCache, pullReqStore, pullReqActivityStore, pullReqReviewerStore, labelStore, labelValueStore, pullReqLabelAssignmentStore, ruleStore, webhookStore, pipelineStore, triggerStore, publicaccessService)
+	repoDeleter := repo.ProvideRepoDeleter(repoController)
+	importerArchive, err := importer.ProvideArchiveImporter(config, provider, gitInterface, transactor, repoIdentifier, spaceStore, repoStore, principalStore, pullReqReviewerStore, pipelineStore, triggerStore, labelStore, blobStore, repoDeleter, publicaccessService, indexer, jobScheduler, executor, streamer, auditService, instrumentService, pullReq, rule, migrateWebhook, migrateLabel)
 	if err != nil {
 		return nil, err
 	}
+	spaceController := space.ProvideController(config, transactor, provider, streamer, spaceIdentifier, authorizer, spacePathStore, pipelineStore, secretStore, connectorStore, templateStore, spaceStore, repoStore, principalStore, repoController, membershipStore, listService, spaceCache, repository, exporterRepository, archive, importerArchive, resourceLimiter, publicaccessService, auditService, gitspaceService, labelService, instrumentService, executionStore, rulesService, usageMetricStore)
 	pipelineController := pipeline.ProvideController(triggerStore, authorizer, pipelineStore, reporter3, repoFinder)
 	secretController := secret2.ProvideController(encrypter, secretStore, authorizer, spaceStore)
 	triggerController := trigger.ProvideController(authorizer, triggerStore, pipelineStore, repoFinder)
@@ -354,12 +406,12 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	connectorController := connector2.ProvideController(connectorStore, connectorService, authorizer, spaceCache)
 	templateController := template.ProvideController(templateStore, authorizer, spaceStore)
 	pluginController := plugin.ProvideController(pluginStore)
-	pullReqActivityStore := database.ProvidePullReqActivityStore(db, principalInfoCache)
 	codeCommentView := database.ProvideCodeCommentView(db)
 	pullReqReviewStore := database.ProvidePullReqReviewStore(db)
-	pullReqReviewerStore := database.ProvidePullReqReviewerStore(db, principalInfoCache)
 	userGroupReviewersStore := database.ProvideUserGroupReviewerStore(db, principalInfoCache, userGroupStore)
 	pullReqFileViewStore := database.ProvidePullReqFileViewStore(db)
+	pullReqAutoMergeStore := database.ProvidePullReqAutoMergeStore(db)
+	mergeQueueEntryStore := database.ProvideMergeQueueEntryStore(db)
 	reporter4, err := events6.ProvideReporter(eventsSystem)
 	if err != nil {
 		return nil, err
@@ -377,10 +429,7 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	if err != nil {
 		return nil, err
 	}
-	pullReq := migrate.ProvidePullReqImporter(provider, gitInterface, principalStore, spaceStore, repoStore, pullReqStore, pullReqActivityStore, labelStore, labelValueStore, pullReqLabelAssignmentStore, transactor, mutexManager)
-	pullreqController := pullreq2.ProvideController(transactor, provider, authorizer, auditService, pullReqStore, pullReqActivityStore, codeCommentView, pullReqReviewStore, pullReqReviewerStore, repoStore, principalStore, userGroupStore, userGroupReviewersStore, principalInfoCache, pullReqFileViewStore, membershipStore, checkStore, gitInterface, repoFinder, reporter4, migrator, pullreqService, listService, protectionManager, streamer, codeownersService, lockerLocker, pullReq, labelService, instrumentService, searchService)
-	webhookConfig := server.ProvideWebhookConfig(config)
-	webhookStore := database.ProvideWebhookStore(db)
+	pullreqController := pullreq2.ProvideController(transactor, provider, authorizer, auditService, pullReqStore, pullReqActivityStore, codeCommentView, pullReqReviewStore, pullReqReviewerStore, repoStore, principalStore, userGroupStore, userGroupReviewersStore, principalInfoCache, pullReqFileViewStore, membershipStore, checkStore, pullReqAutoMergeStore, mergeQueueEntryStore, gitInterface, repoFinder, reporter4, migrator, pullreqService, listService, protectionManager, streamer, codeownersService, lockerLocker, pullReq, labelService, instrumentService, searchService, publickeyService)
 	webhookExecutionStore := database.ProvideWebhookExecutionStore(db)
 	urlProvider := webhook.ProvideURLProvider(ctx)
 	webhookService, err := webhook.ProvideService(ctx, webhookConfig, transactor, readerFactory, eventsReaderFactory, webhookStore, webhookExecutionStore, spaceStore, repoStore, pullReqStore, pullReqActivityStore, provider, principalStore, gitInterface, encrypter, labelStore, urlProvider, labelValueStore, streamer)
@@ -405,30 +454,33 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	if err != nil {
 		return nil, err
 	}
-	githookController := githook.ProvideController(authorizer, principalStore, repoStore, reporter5, reporter, gitInterface, pullReqStore, provider, protectionManager, clientFactory, resourceLimiter, settingsService, preReceiveExtender, updateExtender, postReceiveExtender, streamer)
-	serviceaccountController := serviceaccount.NewController(principalUID, authorizer, principalStore, spaceStore, repoStore, tokenStore)
-	principalController := principal.ProvideController(principalStore, authorizer)
-	usergroupController := usergroup2.ProvideController(userGroupStore, spaceStore, authorizer, searchService)
-	v := check2.ProvideCheckSanitizers()
-	checkController := check2.ProvideController(transactor, authorizer, spaceStore, checkStore, spaceCache, repoFinder, gitInterface, v, streamer)
-	systemController := system.NewController(principalStore, config)
-	blobConfig, err := server.ProvideBlobStoreConfig(config)
+	mirrorStore := database.ProvideMirrorStore(db)
+	secretService := secret3.ProvideSecretService(secretStore, encrypter, spacePathStore)
+	mirrorConfig := server.ProvideMirrorConfig(config)
+	mirrorService, err := mirror.ProvideService(mirrorConfig, mirrorStore, repoStore, connectorStore, secretService, provider, gitInterface, jobScheduler, executor)
 	if err != nil {
 		return nil, err
 	}
-	blobStore, err := blob.ProvideStore(ctx, blobConfig)
+	githookController := githook.ProvideController(authorizer, principalStore, repoStore, reporter5, reporter, gitInterface, pullReqStore, provider, protectionManager, clientFactory, resourceLimiter, settingsService, preReceiveExtender, updateExtender, postReceiveExtender, streamer, publickeyService, mirrorService)
+	serviceaccountController := serviceaccount.NewController(principalUID, authorizer, principalStore, spaceStore, repoStore, tokenStore, repoFinder)
+	principalController := principal.ProvideController(principalStore, authorizer)
+	usergroupController := usergroup2.ProvideController(userGroupStore, spaceStore, authorizer, searchService)
+	v := check2.ProvideCheckSanitizers()
+	reporter6, err := events8.ProvideReporter(eventsSystem)
 	if err != nil {
 		return nil, err
 	}
+	checkController := check2.ProvideController(transactor, authorizer, spaceStore, checkStore, spaceCache, repoFinder, gitInterface, v, streamer, reporter6)
+	gcBlobTaskRepository := database2.ProvideGCBlobTaskDao(db)
+	gcManifestTaskRepository := database2.ProvideGCManifestTaskDao(db)
+	gcService := gc.ServiceProvider(transactor, gcBlobTaskRepository, gcManifestTaskRepository, settingsService)
+	systemController := system.NewController(principalStore, systemService, gcService, config)
 	uploadController := upload.ProvideController(authorizer, repoFinder, blobStore)
 	searcher := keywordsearch.ProvideSearcher(localIndexSearcher)
 	keywordsearchController := keywordsearch2.ProvideController(authorizer, searcher, repoController, spaceController)
 	infraproviderController := infraprovider3.ProvideController(authorizer, spaceStore, infraproviderService)
 	limiterGitspace := limiter.ProvideGitspaceLimiter()
 	gitspaceController := gitspace2.ProvideController(transactor, authorizer, infraproviderService, gitspaceConfigStore, gitspaceInstanceStore, spaceStore, gitspaceEventStore, statefulLogger, scmSCM, gitspaceService, limiterGitspace, repoFinder)
-	rule := migrate.ProvideRuleImporter(ruleStore, transactor, principalStore)
-	migrateWebhook := migrate.ProvideWebhookImporter(webhookConfig, transactor, webhookStore)
-	migrateLabel := migrate.ProvideLabelImporter(transactor, labelStore, labelValueStore, spaceStore)
 	migrateController := migrate2.ProvideController(authorizer, publicaccessService, gitInterface, provider, pullReq, rule, migrateWebhook, migrateLabel, resourceLimiter, auditService, repoIdentifier, transactor, spaceStore, repoStore, spaceCache, repoFinder)
 	registry, err := capabilities.ProvideCapabilities()
 	if err != nil {
@@ -444,6 +496,7 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 		return nil, err
 	}
 	aiagentController := aiagent2.ProvideController(authorizer, intelligence, repoFinder, pipelineStore, executionStore, gitInterface, provider, slack)
+	auditController := audit2.ProvideController(transactor, authorizer, spaceCache, spaceStore, auditStore)
 	openapiService := openapi.ProvideOpenAPIService()
 	storageDriver, err := api2.BlobStorageProvider(config)
 	if err != nil {
@@ -453,7 +506,6 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	mediaTypesRepository := database2.ProvideMediaTypeDao(db)
 	blobRepository := database2.ProvideBlobDao(db, mediaTypesRepository)
 	storageService := docker.StorageServiceProvider(config, storageDriver)
-	gcService := gc.ServiceProvider()
 	app := docker.NewApp(ctx, storageDeleter, blobRepository, spaceStore, config, storageService, gcService)
 	registryRepository := database2.ProvideRepoDao(db, mediaTypesRepository)
 	manifestRepository := database2.ProvideManifestDao(db, mediaTypesRepository)
@@ -470,7 +522,6 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	downloadStatRepository := database2.ProvideDownloadStatDao(db)
 	localRegistry := docker.LocalRegistryProvider(app, manifestService, blobRepository, registryRepository, manifestRepository, registryBlobRepository, mediaTypesRepository, tagRepository, imageRepository, artifactRepository, bandwidthStatRepository, downloadStatRepository, gcService, transactor)
 	upstreamProxyConfigRepository := database2.ProvideUpstreamDao(db, registryRepository, spacePathStore)
-	secretService := secret3.ProvideSecretService(secretStore, encrypter, spacePathStore)
 	proxyController := docker.ProvideProxyController(localRegistry, manifestService, secretService, spacePathStore)
 	remoteRegistry := docker.RemoteRegistryProvider(localRegistry, app, upstreamProxyConfigRepository, spacePathStore, secretService, proxyController)
 	coreController := pkg.CoreControllerProvider(registryRepository)
@@ -482,12 +533,20 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	apiHandler := router.APIHandlerProvider(registryRepository, upstreamProxyConfigRepository, tagRepository, manifestRepository, cleanupPolicyRepository, imageRepository, storageDriver, spaceStore, transactor, authenticator, provider, authorizer, auditService, spacePathStore)
 	appRouter := router.AppRouterProvider(registryOCIHandler, apiHandler)
 	sender := usage.ProvideMediator(ctx, config, spaceStore, usageMetricStore)
-	routerRouter := router2.ProvideRouter(ctx, config, authenticator, repoController, reposettingsController, executionController, logsController, spaceController, pipelineController, secretController, triggerController, connectorController, templateController, pluginController, pullreqController, webhookController, githookController, gitInterface, serviceaccountController, controller, principalController, usergroupController, checkController, systemController, uploadController, keywordsearchController, infraproviderController, gitspaceController, migrateController, aiagentController, capabilitiesController, provider, openapiService, appRouter, sender)
+	lfsController := lfs.ProvideController(authorizer, repoFinder, lfsObjectStore, blobStore, provider, resourceLimiter)
+	mirrorController := mirror2.ProvideController(authorizer, repoFinder, mirrorStore, connectorStore, secretStore, mirrorService)
+	oidcProvider := oidc.ProvideProvider(config)
+	oidcIdentityStore := database.ProvideOIDCIdentityStore(db)
+	oidcController, err := oidc2.ProvideController(config, transactor, oidcProvider, controller, principalStore, tokenStore, oidcIdentityStore, membershipStore, userGroupStore, userGroupMemberStore, spaceCache)
+	if err != nil {
+		return nil, err
+	}
+	client := manager.ProvideExecutionClient(executionManager, provider, config)
+	runnerStore := database.ProvideRunnerStore(db)
+	runnerController := runner2.ProvideController(config, runnerStore, stageStore, stepStore, client)
+	routerRouter := router2.ProvideRouter(ctx, config, authenticator, repoController, reposettingsController, executionController, logsController, spaceController, pipelineController, secretController, triggerController, connectorController, templateController, pluginController, pullreqController, webhookController, githookController, gitInterface, serviceaccountController, controller, principalController, usergroupController, checkController, systemController, uploadController, keywordsearchController, infraproviderController, gitspaceController, migrateController, aiagentController, capabilitiesController, auditController, provider, openapiService, appRouter, sender, lfsController, mirrorController, oidcController, runnerController)
 	serverServer := server2.ProvideServer(config, routerRouter)
-	publickeyService := publickey.ProvidePublicKey(publicKeyStore, principalInfoCache)
 	sshServer := ssh.ProvideServer(config, publickeyService, repoController)
-	executionManager := manager.ProvideExecutionManager(config, executionStore, pipelineStore, provider, streamer, fileService, converterService, logStore, logStream, checkStore, repoStore, schedulerScheduler, secretStore, stageStore, stepStore, principalStore, publicaccessService, reporter3)
-	client := manager.ProvideExecutionClient(executionManager, provider, config)
 	resolverManager := resolver.ProvideResolver(config, pluginStore, templateStore, executionStore, repoStore)
 	runtimeRunner, err := runner.ProvideExecutionRunner(config, client, resolverManager)
 	if err != nil {
@@ -495,58 +554,79 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	}
 	poller := runner.ProvideExecutionPoller(runtimeRunner, client)
 	triggerConfig := server.ProvideTriggerConfig(config)
-	triggerService, err := trigger2.ProvideService(ctx, triggerConfig, triggerStore, commitService, pullReqStore, repoStore, pipelineStore, triggererTriggerer, readerFactory, eventsReaderFactory)
+	triggerService, err := trigger2.ProvideService(ctx, triggerConfig, triggerStore, commitService, pullReqStore, repoStore, pipelineStore, triggererTriggerer, jobScheduler, executor, readerFactory, eventsReaderFactory)
 	if err != nil {
 		return nil, err
 	}
-	systemService := system2.ProvideService(settingsService)
+	readerFactory2, err := events8.ProvideReaderFactory(eventsSystem)
+	if err != nil {
+		return nil, err
+	}
+	readerFactory3, err := events5.ProvideReaderFactory(eventsSystem)
+	if err != nil {
+		return nil, err
+	}
+	automergeService, err := automerge.ProvideService(ctx, config, pullreqController, pullReqStore, pullReqAutoMergeStore, eventsReaderFactory, readerFactory2, readerFactory3)
+	if err != nil {
+		return nil, err
+	}
+	mergequeueService, err := mergequeue.ProvideService(ctx, config, pullreqController, mergeQueueEntryStore, jobScheduler, executor, eventsReaderFactory, readerFactory, readerFactory2, readerFactory3)
+	if err != nil {
+		return nil, err
+	}
+	ldapsyncService := ldapsync.ProvideService(config, directory, transactor, spaceCache, ldapIdentityStore, userGroupStore, userGroupMemberStore, jobScheduler, executor)
 	collector, err := metric.ProvideCollector(config, principalStore, repoStore, pipelineStore, executionStore, jobScheduler, executor, gitspaceConfigStore, systemService, registryRepository, artifactRepository)
 	if err != nil {
 		return nil, err
 	}
-	sizeCalculator, err := repo2.ProvideCalculator(config, gitInterface, repoStore, jobScheduler, executor)
+	sizeCalculator, err := repo2.ProvideCalculator(config, gitInterface, repoStore, lfsObjectStore, jobScheduler, executor)
 	if err != nil {
 		return nil, err
 	}
-	readerFactory2, err := events2.ProvideReaderFactory(eventsSystem)
+	readerFactory4, err := events2.ProvideReaderFactory(eventsSystem)
 	if err != nil {
 		return nil, err
 	}
-	repoService, err := repo2.ProvideService(ctx, config, reporter, readerFactory2, repoStore, provider, gitInterface, lockerLocker)
+	repoService, err := repo2.ProvideService(ctx, config, reporter, readerFactory4, repoStore, provider, gitInterface, lockerLocker)
 	if err != nil {
 		return nil, err
 	}
 	cleanupConfig := server.ProvideCleanupConfig(config)
-	cleanupService, err := cleanup.ProvideService(cleanupConfig, jobScheduler, executor, webhookExecutionStore, tokenStore, repoStore, repoController)
+	cleanupService, err := cleanup.ProvideService(cleanupConfig, jobScheduler, executor, webhookExecutionStore, tokenStore, repoStore, repoController, auditStore)
+	if err != nil {
+		return nil, err
+	}
+	config2 := server.ProvideRegistryCleanupConfig(config)
+	service2, err := cleanup2.ProvideService(config2, jobScheduler, executor, transactor, registryRepository, cleanupPolicyRepository, tagRepository, artifactRepository, manifestRepository, spacePathStore, eventReporter)
+	if err != nil {
+		return nil, err
+	}
+	notificationClient, err := notification.ProvideNotificationClient(notificationConfig, mailerMailer, userChatIdentityStore, publicaccessService)
 	if err != nil {
 		return nil, err
 	}
-	mailerMailer := mailer.ProvideMailClient(config)
-	notificationClient := notification.ProvideMailClient(mailerMailer)
-	notificationConfig := server.ProvideNotificationConfig(config)
 	notificationService, err := notification.ProvideNotificationService(ctx, notificationClient, notificationConfig, eventsReaderFactory, pullReqStore, repoStore, principalInfoView, principalInfoCache, pullReqReviewerStore, pullReqActivityStore, spacePathStore, provider)
 	if err != nil {
 		return nil, err
 	}
-	keywordsearchConfig := server.ProvideKeywordSearchConfig(config)
-	keywordsearchService, err := keywordsearch.ProvideService(ctx, keywordsearchConfig, readerFactory, readerFactory2, repoStore, indexer)
+	keywordsearchService, err := keywordsearch.ProvideService(ctx, keywordsearchConfig, readerFactory, readerFactory4, repoStore, indexer)
 	if err != nil {
 		return nil, err
 	}
 	gitspaceeventConfig := server.ProvideGitspaceEventConfig(config)
-	readerFactory3, err := events3.ProvideReaderFactory(eventsSystem)
+	readerFactory5, err := events3.ProvideReaderFactory(eventsSystem)
 	if err != nil {
 		return nil, err
 	}
-	gitspaceeventService, err := gitspaceevent.ProvideService(ctx, gitspaceeventConfig, readerFactory3, gitspaceEventStore)
+	gitspaceeventService, err := gitspaceevent.ProvideService(ctx, gitspaceeventConfig, readerFactory5, gitspaceEventStore)
 	if err != nil {
 		return nil, err
 	}
-	readerFactory4, err := events4.ProvideReaderFactory(eventsSystem)
+	readerFactory6, err := events4.ProvideReaderFactory(eventsSystem)
 	if err != nil {
 		return nil, err
 	}
-	gitspaceinfraeventService, err := gitspaceinfraevent.ProvideService(ctx, gitspaceeventConfig, readerFactory4, orchestratorOrchestrator, gitspaceService, eventsReporter)
+	gitspaceinfraeventService, err := gitspaceinfraevent.ProvideService(ctx, gitspaceeventConfig, readerFactory6, orchestratorOrchestrator, gitspaceService, eventsReporter)
 	if err != nil {
 		return nil, err
 	}
@@ -559,7 +639,7 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	if err != nil {
 		return nil, err
 	}
-	servicesServices := services.ProvideServices(webhookService, pullreqService, triggerService, jobScheduler, collector, sizeCalculator, repoService, cleanupService, notificationService, keywordsearchService, gitspaceServices, instrumentService, consumer, repositoryCount)
+	servicesServices := services.ProvideServices(webhookService, pullreqService, triggerService, mirrorService, automergeService, mergequeueService, ldapsyncService, jobScheduler, collector, sizeCalculator, repoService, cleanupService, service2, notificationService, keywordsearchService, gitspaceServices, instrumentService, consumer, repositoryCount)
 	serverSystem := server.NewSystem(bootstrapBootstrap, serverServer, sshServer, poller, resolverManager, servicesServices)
 	return serverSystem, nil
 }
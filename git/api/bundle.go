@@ -0,0 +1,45 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+
+	"github.com/harness/gitness/git/command"
+)
+
+// CreateBundle writes all references of the repository and the objects reachable from them
+// into a git bundle file located at bundlePath.
+func (g *Git) CreateBundle(
+	ctx context.Context,
+	repoPath string,
+	bundlePath string,
+) error {
+	if repoPath == "" {
+		return ErrRepositoryPathEmpty
+	}
+
+	cmd := command.New("bundle",
+		command.WithAction("create"),
+		command.WithArg(bundlePath, "--all"),
+	)
+
+	err := cmd.Run(ctx, command.WithDir(repoPath))
+	if err != nil {
+		return processGitErrorf(err, "failed to create bundle")
+	}
+
+	return nil
+}
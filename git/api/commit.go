@@ -130,6 +130,10 @@ func getCommits(
 		commits = append(commits, commit)
 	}
 
+	if err := fillCommitSignatures(ctx, repoPath, nil, commits); err != nil {
+		return nil, fmt.Errorf("failed to get commit signatures: %w", err)
+	}
+
 	return commits, nil
 }
 
@@ -548,7 +552,16 @@ func (g *Git) GetCommit(
 		return nil, ErrRepositoryPathEmpty
 	}
 
-	return getCommit(ctx, repoPath, rev, "")
+	commit, err := getCommit(ctx, repoPath, rev, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fillCommitSignatures(ctx, repoPath, nil, []*Commit{commit}); err != nil {
+		return nil, fmt.Errorf("failed to get commit signature: %w", err)
+	}
+
+	return commit, nil
 }
 
 func (g *Git) GetFullCommitID(
@@ -903,6 +916,12 @@ readLoop:
 				continue
 			}
 
+			// continuation lines of multi-line headers (e.g. mergetag) are part of the signed payload.
+			if line[0] == ' ' {
+				_, _ = payloadSB.Write(line)
+				continue
+			}
+
 			split := bytes.SplitN(trimmed, []byte{' '}, 2)
 			var data []byte
 			if len(split) > 1 {
@@ -910,28 +929,27 @@ readLoop:
 			}
 
 			switch string(split[0]) {
-			case "tree":
-				_, _ = payloadSB.Write(line)
 			case "parent":
 				commit.ParentSHAs = append(commit.ParentSHAs, sha.Must(string(data)))
-				_, _ = payloadSB.Write(line)
 			case "author":
 				commit.Author, err = DecodeSignature(data)
 				if err != nil {
 					return nil, fmt.Errorf("failed to parse author signature: %w", err)
 				}
-				_, _ = payloadSB.Write(line)
 			case "committer":
 				commit.Committer, err = DecodeSignature(data)
 				if err != nil {
 					return nil, fmt.Errorf("failed to parse committer signature: %w", err)
 				}
-				_, _ = payloadSB.Write(line)
-			case "gpgsig":
+			case "gpgsig", "gpgsig-sha256":
 				_, _ = signatureSB.Write(data)
 				_ = signatureSB.WriteByte('\n')
 				pgpsig = true
+				continue
 			}
+
+			// all headers except for the signature itself are part of the signed payload.
+			_, _ = payloadSB.Write(line)
 		} else {
 			_, _ = messageSB.Write(line)
 			_, _ = payloadSB.Write(line)
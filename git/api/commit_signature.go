@@ -0,0 +1,167 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git/command"
+)
+
+const (
+	sshSignatureBeginToken = "\n-----BEGIN SSH SIGNATURE-----\n" //#nosec G101
+)
+
+// signatureBeginTokens are the tokens with which the signatures appended to tag messages start.
+var signatureBeginTokens = []string{
+	pgpSignatureBeginToken,
+	sshSignatureBeginToken,
+}
+
+// splitSignedTagData splits the raw data of a tag object into the signed payload and the signature.
+// It returns a nil signature if the tag isn't signed.
+func splitSignedTagData(data []byte) ([]byte, *CommitGPGSignature) {
+	idx := -1
+	for _, token := range signatureBeginTokens {
+		if i := bytes.LastIndex(data, []byte(token)); i > idx {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return data, nil
+	}
+
+	return data[:idx+1], &CommitGPGSignature{
+		Signature: string(data[idx+1:]),
+		Payload:   string(data[:idx+1]),
+	}
+}
+
+// fillCommitSignatures reads the raw commit objects and sets the signature of all signed commits.
+func fillCommitSignatures(
+	ctx context.Context,
+	repoPath string,
+	alternateObjectDirs []string,
+	commits []*Commit,
+) error {
+	if len(commits) == 0 {
+		return nil
+	}
+
+	writer, reader, cancel := CatFileBatch(ctx, repoPath, alternateObjectDirs)
+	defer func() {
+		cancel()
+		_ = writer.Close()
+	}()
+
+	for _, commit := range commits {
+		if _, err := writer.Write([]byte(commit.SHA.String() + "\n")); err != nil {
+			return fmt.Errorf("failed to write to cat-file batch: %w", err)
+		}
+
+		output, err := ReadBatchHeaderLine(reader)
+		if err != nil {
+			return fmt.Errorf("failed to read cat-file header line: %w", err)
+		}
+		if output.Type != string(GitObjectTypeCommit) {
+			return fmt.Errorf("git object %s is of type '%s', expected commit", output.SHA, output.Type)
+		}
+
+		raw, err := CommitFromReader(output.SHA, io.LimitReader(reader, output.Size))
+		if err != nil {
+			return fmt.Errorf("failed to read commit %s: %w", output.SHA, err)
+		}
+		if _, err = reader.Discard(1); err != nil {
+			return fmt.Errorf("commit reader Discard failed: %w", err)
+		}
+
+		commit.Signature = raw.Signature
+	}
+
+	return nil
+}
+
+// ListCommitSignatures lists the commits reachable from rev, but not from baseRev, together with their signatures.
+// If baseRev is empty or excludeReferenced is set, all commits reachable from any existing reference are excluded,
+// which in a pre-receive hook means that only the newly pushed commits are returned.
+// Note: Only the commit data stored in the commit objects is populated (e.g. no title).
+func (g *Git) ListCommitSignatures(
+	ctx context.Context,
+	repoPath string,
+	alternateObjectDirs []string,
+	rev string,
+	baseRev string,
+	excludeReferenced bool,
+	limit int,
+) ([]*Commit, error) {
+	if repoPath == "" {
+		return nil, ErrRepositoryPathEmpty
+	}
+
+	cmd := command.New("rev-list",
+		command.WithAlternateObjectDirs(alternateObjectDirs...),
+		command.WithArg(rev),
+	)
+	if baseRev != "" {
+		cmd.Add(command.WithArg("^" + baseRev))
+	}
+	if baseRev == "" || excludeReferenced {
+		cmd.Add(command.WithArg("--not", "--all"))
+	}
+	if limit > 0 {
+		cmd.Add(command.WithFlag("--max-count", strconv.Itoa(limit)))
+	}
+
+	output := &bytes.Buffer{}
+	err := cmd.Run(ctx, command.WithDir(repoPath), command.WithStdout(output))
+	if cErr := command.AsError(err); cErr != nil {
+		if cErr.IsExitCode(128) && cErr.IsAmbiguousArgErr() {
+			return nil, errors.NotFound("revision %q not found", rev)
+		}
+		return nil, processGitErrorf(err, "failed to trigger rev-list command")
+	}
+
+	commitSHAs := parseLinesToSlice(output.Bytes())
+	if len(commitSHAs) == 0 {
+		return []*Commit{}, nil
+	}
+
+	writer, reader, cancel := CatFileBatch(ctx, repoPath, alternateObjectDirs)
+	defer func() {
+		cancel()
+		_ = writer.Close()
+	}()
+
+	commits := make([]*Commit, 0, len(commitSHAs))
+	for _, commitSHA := range commitSHAs {
+		if _, err := writer.Write([]byte(commitSHA + "\n")); err != nil {
+			return nil, fmt.Errorf("failed to write to cat-file batch: %w", err)
+		}
+
+		commit, err := getCommitFromBatchReader(ctx, repoPath, reader, commitSHA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read commit %s: %w", commitSHA, err)
+		}
+
+		commits = append(commits, commit)
+	}
+
+	return commits, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestListCommitSignatures(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoPath := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=author", "GIT_AUTHOR_EMAIL=author@example.com",
+			"GIT_COMMITTER_NAME=author", "GIT_COMMITTER_EMAIL=author@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1",
+		)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v: %s", strings.Join(args, " "), err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	run("init", "--initial-branch=main")
+	run("commit", "--allow-empty", "-m", "initial")
+	run("checkout", "-b", "feature")
+	run("commit", "--allow-empty", "-m", "feature")
+	featureSHA := run("rev-parse", "HEAD")
+	run("checkout", "main")
+	run("commit", "--allow-empty", "-m", "main")
+	mainSHA := run("rev-parse", "HEAD")
+
+	// Merge main into the feature branch without updating the branch,
+	// which is what the repository looks like during pre-receive of such a push.
+	run("checkout", "--detach", "feature")
+	run("merge", "--no-ff", "-m", "merge main", "main")
+	mergeSHA := run("rev-parse", "HEAD")
+	run("checkout", "main")
+
+	g := &Git{}
+	ctx := context.Background()
+
+	tests := []struct {
+		name              string
+		baseRev           string
+		excludeReferenced bool
+		exp               []string
+	}{
+		{
+			name:    "range",
+			baseRev: featureSHA,
+			exp:     []string{mergeSHA, mainSHA},
+		},
+		{
+			name:              "range excluding referenced",
+			baseRev:           featureSHA,
+			excludeReferenced: true,
+			exp:               []string{mergeSHA},
+		},
+		{
+			name: "no base",
+			exp:  []string{mergeSHA},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			commits, err := g.ListCommitSignatures(ctx, repoPath, nil, mergeSHA, test.baseRev, test.excludeReferenced, 0)
+			if err != nil {
+				t.Fatalf("failed to list commits: %v", err)
+			}
+
+			shas := make([]string, len(commits))
+			for i, commit := range commits {
+				shas[i] = commit.SHA.String()
+			}
+
+			if diff := cmp.Diff(test.exp, shas); diff != "" {
+				t.Errorf("commits mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
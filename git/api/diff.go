@@ -22,6 +22,7 @@ import (
 	"io"
 	"math"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -629,6 +630,74 @@ func (g *Git) DiffFileName(ctx context.Context,
 	return parseLinesToSlice(stdout.Bytes()), nil
 }
 
+// CommitPaths holds the paths changed by a commit.
+type CommitPaths struct {
+	SHA   sha.SHA
+	Paths []string
+}
+
+// ListCommitPaths returns the paths changed by each of the provided commits compared to its first parent.
+// Merge commits and commits that don't change any path aren't included in the result.
+func (g *Git) ListCommitPaths(
+	ctx context.Context,
+	repoPath string,
+	alternateObjectDirs []string,
+	commitSHAs []string,
+) ([]CommitPaths, error) {
+	if repoPath == "" {
+		return nil, ErrRepositoryPathEmpty
+	}
+	if len(commitSHAs) == 0 {
+		return []CommitPaths{}, nil
+	}
+
+	cmd := command.New("diff-tree",
+		command.WithAlternateObjectDirs(alternateObjectDirs...),
+		command.WithFlag("--stdin", "-r", "--name-only", "--root", "-z"),
+	)
+
+	stdin := strings.NewReader(strings.Join(commitSHAs, "\n") + "\n")
+	stdout := &bytes.Buffer{}
+	err := cmd.Run(ctx,
+		command.WithDir(repoPath),
+		command.WithStdin(stdin),
+		command.WithStdout(stdout),
+	)
+	if err != nil {
+		return nil, processGitErrorf(err, "failed to trigger diff-tree command")
+	}
+
+	// The output contains, in the order of the input, the commit SHA followed by the changed paths,
+	// all separated by the NUL character. Commits without changes are omitted.
+	result := make([]CommitPaths, 0, len(commitSHAs))
+	next := 0
+	for _, token := range strings.Split(stdout.String(), "\x00") {
+		if token == "" {
+			continue
+		}
+
+		idx := slices.Index(commitSHAs[next:], token)
+		if idx >= 0 {
+			commitSHA, err := sha.New(token)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse commit SHA %q: %w", token, err)
+			}
+
+			result = append(result, CommitPaths{SHA: commitSHA})
+			next += idx + 1
+			continue
+		}
+
+		if len(result) == 0 {
+			return nil, fmt.Errorf("unexpected diff-tree output %q", token)
+		}
+
+		result[len(result)-1].Paths = append(result[len(result)-1].Paths, token)
+	}
+
+	return result, nil
+}
+
 // GetDiffShortStat counts number of changed files, number of additions and deletions.
 func GetDiffShortStat(
 	ctx context.Context,
@@ -51,6 +51,36 @@ type PushOptions struct {
 	Env            []string
 	Timeout        time.Duration
 	Mirror         bool
+	Access         *RemoteAccess
+}
+
+// RemoteAccess restricts how git connects to a remote repository.
+type RemoteAccess struct {
+	// Resolve pins hosts to addresses, each entry is in the "HOST:PORT:ADDRESS[,ADDRESS]" format of curl.
+	Resolve []string
+	// NoRedirects stops git from following http redirects of the remote.
+	NoRedirects bool
+	// Protocols limits the protocols git can use to connect to the remote, all are allowed if it's empty.
+	Protocols []string
+}
+
+func (a *RemoteAccess) cmdOptions() []command.CmdOptionFunc {
+	if a == nil {
+		return nil
+	}
+
+	opts := make([]command.CmdOptionFunc, 0, len(a.Resolve)+2)
+	for _, resolve := range a.Resolve {
+		opts = append(opts, command.WithConfig("http.curloptResolve", resolve))
+	}
+	if a.NoRedirects {
+		opts = append(opts, command.WithConfig("http.followRedirects", "false"))
+	}
+	if len(a.Protocols) > 0 {
+		opts = append(opts, command.WithEnv(command.GitAllowProtocol, strings.Join(a.Protocols, ":")))
+	}
+
+	return opts
 }
 
 // ObjectCount represents the parsed information from the `git count-objects -v` command.
@@ -255,6 +285,7 @@ func (g *Git) Sync(
 	repoPath string,
 	source string,
 	refSpecs []string,
+	access *RemoteAccess,
 ) error {
 	if repoPath == "" {
 		return ErrRepositoryPathEmpty
@@ -276,6 +307,7 @@ func (g *Git) Sync(
 		command.WithArg(source),
 		command.WithArg(refSpecs...),
 	)
+	cmd.Add(access.cmdOptions()...)
 
 	err := cmd.Run(ctx, command.WithDir(repoPath))
 	if err != nil {
@@ -285,6 +317,63 @@ func (g *Git) Sync(
 	return nil
 }
 
+// FetchObjects fetches the provided objects from the source repository without updating any references.
+// NOTE: This is a read operation and doesn't trigger any server side hooks.
+func (g *Git) FetchObjects(
+	ctx context.Context,
+	repoPath string,
+	source string,
+	objectSHAs []string,
+) error {
+	if repoPath == "" {
+		return ErrRepositoryPathEmpty
+	}
+	if len(objectSHAs) == 0 {
+		return nil
+	}
+
+	cmd := command.New("fetch",
+		command.WithConfig("credential.helper", ""),
+		command.WithFlag(
+			"--quiet",
+			"--no-tags",
+			"--no-write-fetch-head",
+		),
+		command.WithArg(source),
+		command.WithArg(objectSHAs...),
+	)
+
+	err := cmd.Run(ctx, command.WithDir(repoPath))
+	if err != nil {
+		return processGitErrorf(err, "failed to fetch objects")
+	}
+
+	return nil
+}
+
+// Repack packs all objects reachable from the repository into a single pack, including the objects
+// the repository borrows from its alternate object stores, which allows the alternates to be removed.
+// Unreachable objects of the old packs are kept as loose objects, as forks of the repository might still use them.
+func (g *Git) Repack(
+	ctx context.Context,
+	repoPath string,
+) error {
+	if repoPath == "" {
+		return ErrRepositoryPathEmpty
+	}
+
+	cmd := command.New("repack",
+		command.WithFlag("-A", "-d", "-q"),
+	)
+
+	err := cmd.Run(ctx, command.WithDir(repoPath))
+	if err != nil {
+		return processGitErrorf(err, "failed to repack repository")
+	}
+
+	return nil
+}
+
 func (g *Git) AddFiles(
 	ctx context.Context,
 	repoPath string,
@@ -353,6 +442,7 @@ func (g *Git) Push(
 	cmd := command.New("push",
 		command.WithConfig("credential.helper", ""),
 	)
+	cmd.Add(opts.Access.cmdOptions()...)
 	if opts.Force {
 		cmd.Add(command.WithFlag("-f"))
 	}
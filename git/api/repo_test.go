@@ -0,0 +1,94 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os/exec"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSyncRemoteAccess(t *testing.T) {
+	var targetHits atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		targetHits.Add(1)
+		http.NotFound(w, nil)
+	}))
+	defer target.Close()
+
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL+r.URL.RequestURI(), http.StatusFound)
+	}))
+	defer redirect.Close()
+
+	targetURL, err := url.Parse(target.URL)
+	if err != nil {
+		t.Fatalf("failed to parse target url: %s", err)
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		access  *RemoteAccess
+		expHits int32
+	}{
+		{
+			name:    "redirect-followed",
+			source:  redirect.URL + "/repo.git",
+			expHits: 1,
+		},
+		{
+			name:   "redirect-not-followed",
+			source: redirect.URL + "/repo.git",
+			access: &RemoteAccess{NoRedirects: true},
+		},
+		{
+			name:    "resolve-pinned",
+			source:  "http://remote.invalid:" + targetURL.Port() + "/repo.git",
+			access:  &RemoteAccess{Resolve: []string{"remote.invalid:" + targetURL.Port() + ":127.0.0.1"}},
+			expHits: 1,
+		},
+		{
+			name:   "protocol-not-allowed",
+			source: target.URL + "/repo.git",
+			access: &RemoteAccess{Protocols: []string{"https"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoPath := t.TempDir()
+			if out, err := exec.Command("git", "init", "--bare", repoPath).CombinedOutput(); err != nil {
+				t.Fatalf("failed to init repository: %s: %s", err, out)
+			}
+
+			targetHits.Store(0)
+
+			// the target doesn't serve a repository, so the sync always fails.
+			g := &Git{}
+			if err := g.Sync(context.Background(), repoPath, test.source, nil, test.access); err == nil {
+				t.Fatalf("expected the sync to fail")
+			}
+
+			if hits := targetHits.Load(); hits != test.expHits {
+				t.Errorf("expected %d requests to the target, got %d", test.expHits, hits)
+			}
+		})
+	}
+}
@@ -211,6 +211,13 @@ func parseTagDataFromCatFile(data []byte) (tag Tag, err error) {
 		return tag, err
 	}
 
+	// signatures of signed tags are appended to the message
+	payload, signature := splitSignedTagData(data)
+	if signature != nil && len(payload) >= p {
+		tag.Signature = signature
+		data = payload
+	}
+
 	// remainder is message and gpg (remove leading and tailing new lines)
 	message := string(bytes.Trim(data[p:], "\n"))
 
@@ -385,15 +392,10 @@ l:
 			break l
 		}
 	}
-	idx := strings.LastIndex(tag.Message, pgpSignatureBeginToken)
-	if idx > 0 {
-		endSigIdx := strings.Index(tag.Message[idx:], pgpSignatureEndToken)
-		if endSigIdx > 0 {
-			tag.Signature = &CommitGPGSignature{
-				Signature: tag.Message[idx+1 : idx+endSigIdx+len(pgpSignatureEndToken)],
-				Payload:   string(data[:bytes.LastIndex(data, []byte(pgpSignatureBeginToken))+1]),
-			}
-			tag.Message = tag.Message[:idx+1]
+	if _, signature := splitSignedTagData(data); signature != nil {
+		tag.Signature = signature
+		if idx := strings.LastIndex(tag.Message, signature.Signature); idx >= 0 {
+			tag.Message = tag.Message[:idx]
 		}
 	}
 	return tag, nil
@@ -0,0 +1,68 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/errors"
+)
+
+type CreateBundleParams struct {
+	ReadParams
+	// Path is the location of the bundle file that is going to be created.
+	Path string
+}
+
+func (p *CreateBundleParams) Validate() error {
+	if p == nil {
+		return ErrNoParamsProvided
+	}
+
+	if err := p.ReadParams.Validate(); err != nil {
+		return err
+	}
+
+	if p.Path == "" {
+		return errors.InvalidArgument("bundle path cannot be empty")
+	}
+
+	return nil
+}
+
+// CreateBundle writes all references of a repository into a git bundle file.
+// The bundle can be used as a sync source for SyncRepository.
+func (s *Service) CreateBundle(ctx context.Context, params *CreateBundleParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+	isEmpty, err := s.git.HasBranches(ctx, repoPath)
+	if err != nil {
+		return errors.Internal(err, "bundle of repo failed")
+	}
+	if isEmpty {
+		return errors.InvalidArgument("cannot bundle empty repo")
+	}
+
+	err = s.git.CreateBundle(ctx, repoPath, params.Path)
+	if err != nil {
+		return fmt.Errorf("CreateBundle: failed to create bundle: %w", err)
+	}
+
+	return nil
+}
@@ -70,7 +70,19 @@ var descriptions = map[string]builder{
 	},
 	"branch": {},
 	"bundle": {
-		flags: NoRefUpdates,
+		// git-bundle(1) expects the rev-list arguments (like `--all`) after the bundle file.
+		flags: NoRefUpdates | NoEndOfOptions,
+		validatePositionalArgs: func(args []string) error {
+			for _, arg := range args {
+				if arg == "--all" {
+					continue
+				}
+				if err := validatePositionalArg(arg); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
 	},
 	"cat-file": {
 		flags: NoRefUpdates,
@@ -31,6 +31,8 @@ const (
 
 	GitObjectDir           = "GIT_OBJECT_DIRECTORY"
 	GitAlternateObjectDirs = "GIT_ALTERNATE_OBJECT_DIRECTORIES"
+
+	GitAllowProtocol = "GIT_ALLOW_PROTOCOL"
 )
 
 // Envs custom key value store for environment variables.
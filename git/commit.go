@@ -50,6 +50,14 @@ type Commit struct {
 	Author     Signature         `json:"author"`
 	Committer  Signature         `json:"committer"`
 	FileStats  []CommitFileStats `json:"file_stats,omitempty"`
+	Signature  *CommitSignature  `json:"-"`
+}
+
+// CommitSignature contains the raw signature of a signed git object (commit or tag)
+// together with the payload that was signed.
+type CommitSignature struct {
+	Signature string
+	Payload   string
 }
 
 type GetCommitOutput struct {
@@ -210,6 +218,67 @@ func (s *Service) ListCommits(ctx context.Context, params *ListCommitsParams) (*
 	}, nil
 }
 
+type ListCommitSignaturesParams struct {
+	ReadParams
+	// Rev is the revision from which the commits are listed.
+	Rev string
+	// BaseRev is the optional revision up to which (exclusive) the commits are listed.
+	// If not provided, only commits that aren't reachable from any existing reference are listed.
+	BaseRev string
+	// ExcludeReferenced excludes the commits reachable from any existing reference also if BaseRev is provided.
+	// In a pre-receive hook this limits the list to the newly pushed commits.
+	ExcludeReferenced bool
+	// Limit is the optional max number of commits that are listed.
+	Limit int
+}
+
+type ListCommitSignaturesOutput struct {
+	Commits []Commit
+}
+
+// ListCommitSignatures lists the commits between two revisions together with their raw signatures.
+// It can be used on quarantined data during pre-receive to list only the newly pushed commits.
+func (s *Service) ListCommitSignatures(
+	ctx context.Context,
+	params *ListCommitSignaturesParams,
+) (*ListCommitSignaturesOutput, error) {
+	if params == nil {
+		return nil, ErrNoParamsProvided
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+
+	gitCommits, err := s.git.ListCommitSignatures(
+		ctx,
+		repoPath,
+		params.AlternateObjectDirs,
+		params.Rev,
+		params.BaseRev,
+		params.ExcludeReferenced,
+		params.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]Commit, len(gitCommits))
+	for i := range gitCommits {
+		commit, err := mapCommit(gitCommits[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to map rpc commit: %w", err)
+		}
+
+		commits[i] = *commit
+	}
+
+	return &ListCommitSignaturesOutput{
+		Commits: commits,
+	}, nil
+}
+
 type GetCommitDivergencesParams struct {
 	ReadParams
 	MaxCount int32
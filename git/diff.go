@@ -434,3 +434,48 @@ func (s *Service) DiffFileNames(ctx context.Context, params *DiffParams) (DiffFi
 		Files: fileNames,
 	}, nil
 }
+
+type ListCommitPathsParams struct {
+	ReadParams
+	// CommitSHAs are the commits for which the changed paths are listed.
+	CommitSHAs []string
+}
+
+type CommitPaths struct {
+	SHA   sha.SHA
+	Paths []string
+}
+
+type ListCommitPathsOutput struct {
+	Commits []CommitPaths
+}
+
+// ListCommitPaths lists the paths changed by each of the provided commits.
+// It can be used on quarantined data during pre-receive to inspect the newly pushed commits.
+func (s *Service) ListCommitPaths(ctx context.Context, params *ListCommitPathsParams) (*ListCommitPathsOutput, error) {
+	if params == nil {
+		return nil, ErrNoParamsProvided
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+
+	commitPaths, err := s.git.ListCommitPaths(ctx, repoPath, params.AlternateObjectDirs, params.CommitSHAs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commit paths: %w", err)
+	}
+
+	commits := make([]CommitPaths, len(commitPaths))
+	for i := range commitPaths {
+		commits[i] = CommitPaths{
+			SHA:   commitPaths[i].SHA,
+			Paths: commitPaths[i].Paths,
+		}
+	}
+
+	return &ListCommitPathsOutput{
+		Commits: commits,
+	}, nil
+}
@@ -0,0 +1,244 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git/sha"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// gitAlternatesFile lists the object stores the repository borrows objects from (relative to the objects dir).
+	gitAlternatesFile = "objects/info/alternates"
+	gitObjectsDir     = "objects"
+
+	fileMode600 = 0o600
+)
+
+type ForkRepositoryParams struct {
+	// Fork operation is similar to the create operation, as the UID of the fork doesn't exist yet.
+	RepoUID string
+	Actor   Identity
+	EnvVars map[string]string
+
+	// UpstreamRepoUID is the UID of the repository that is being forked.
+	UpstreamRepoUID string
+
+	// DefaultBranch is the default branch of the fork.
+	DefaultBranch string
+	// OnlyDefaultBranch limits the branches copied to the fork to the default branch.
+	OnlyDefaultBranch bool
+}
+
+func (p *ForkRepositoryParams) Validate() error {
+	if p.UpstreamRepoUID == "" {
+		return errors.InvalidArgument("upstream repository UID is mandatory")
+	}
+
+	if p.DefaultBranch == "" {
+		return errors.InvalidArgument("default branch is mandatory")
+	}
+
+	return p.Actor.Validate()
+}
+
+type DetachForkParams struct {
+	WriteParams
+}
+
+type FetchObjectsParams struct {
+	WriteParams
+
+	// Source is the UID of the repository the objects are fetched from.
+	Source     string
+	ObjectSHAs []sha.SHA
+}
+
+func (p *FetchObjectsParams) Validate() error {
+	if err := p.WriteParams.Validate(); err != nil {
+		return err
+	}
+
+	if p.Source == "" {
+		return errors.InvalidArgument("source repository UID is mandatory")
+	}
+
+	return nil
+}
+
+// ForkRepository creates a new repository that shares the objects of the upstream repository using
+// git alternates. The branches and tags of the upstream repository are copied to the fork.
+func (s *Service) ForkRepository(
+	ctx context.Context,
+	params *ForkRepositoryParams,
+) (*CreateRepositoryOutput, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	if params.RepoUID == "" {
+		uid, err := NewRepositoryUID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new uid: %w", err)
+		}
+		params.RepoUID = uid
+	}
+
+	log.Ctx(ctx).Info().
+		Msgf("Fork git repository with uid '%s' into repository with uid '%s'", params.UpstreamRepoUID, params.RepoUID)
+
+	upstreamPath := getFullPathForRepo(s.reposRoot, params.UpstreamRepoUID)
+	if _, err := os.Stat(upstreamPath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errors.NotFound("upstream repository not found")
+		}
+		return nil, fmt.Errorf("failed to check the status of the upstream repository: %w", err)
+	}
+
+	writeParams := WriteParams{
+		RepoUID: params.RepoUID,
+		Actor:   params.Actor,
+		EnvVars: params.EnvVars,
+	}
+
+	err := s.createRepositoryInternal(ctx, &writeParams, params.DefaultBranch, nil, nil, time.Time{}, nil, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+
+	err = func() error {
+		// Garbage collection of the upstream repository only considers the references of the upstream repository.
+		// Objects the fork borrows could be pruned once they become unreachable in the upstream repository
+		// (e.g. after a force push or a branch deletion), so unreachable objects are kept in upstream repositories.
+		if err := s.git.Config(ctx, upstreamPath, "gc.pruneExpire", "never"); err != nil {
+			return fmt.Errorf("failed to disable pruning of the upstream repository: %w", err)
+		}
+
+		if err := linkAlternates(repoPath, upstreamPath); err != nil {
+			return err
+		}
+
+		refSpecBranches := "+" + gitReferenceNamePrefixBranch + "*:" + gitReferenceNamePrefixBranch + "*"
+		if params.OnlyDefaultBranch {
+			defaultBranchRef := gitReferenceNamePrefixBranch + params.DefaultBranch
+			refSpecBranches = "+" + defaultBranchRef + ":" + defaultBranchRef
+		}
+
+		refSpecs := []string{refSpecBranches, "+" + gitReferenceNamePrefixTag + "*:" + gitReferenceNamePrefixTag + "*"}
+
+		// the objects are shared, so fetching from the upstream repository only copies the references.
+		if err := s.git.Sync(ctx, repoPath, upstreamPath, refSpecs, nil); err != nil {
+			return fmt.Errorf("failed to fetch references from upstream repo: %w", err)
+		}
+
+		return nil
+	}()
+	if err != nil {
+		if cleanupErr := s.DeleteRepositoryBestEffort(ctx, params.RepoUID); cleanupErr != nil {
+			log.Ctx(ctx).Warn().Err(cleanupErr).Msg("failed to cleanup fork repo dir")
+		}
+		return nil, err
+	}
+
+	return &CreateRepositoryOutput{
+		UID: params.RepoUID,
+	}, nil
+}
+
+// DetachFork copies all objects the fork borrows from its upstream repository into the fork
+// and removes the link to the upstream object store. It must be called before the upstream repository is deleted.
+func (s *Service) DetachFork(ctx context.Context, params *DetachForkParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+	alternatesPath := filepath.Join(repoPath, gitAlternatesFile)
+
+	if _, err := os.Stat(alternatesPath); errors.Is(err, fs.ErrNotExist) {
+		return nil // the repository doesn't borrow any objects - nothing to do
+	} else if err != nil {
+		return fmt.Errorf("failed to check the alternates of the repository: %w", err)
+	}
+
+	if err := s.git.Repack(ctx, repoPath); err != nil {
+		return err
+	}
+
+	if err := os.Remove(alternatesPath); err != nil {
+		return fmt.Errorf("failed to remove the alternates of the repository: %w", err)
+	}
+
+	return nil
+}
+
+// FetchObjects copies the provided objects, including their history, from the source repository.
+// No references are updated, so the objects have to be referenced afterward to not be garbage collected.
+func (s *Service) FetchObjects(ctx context.Context, params *FetchObjectsParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+	sourcePath := getFullPathForRepo(s.reposRoot, params.Source)
+
+	objectSHAs := make([]string, len(params.ObjectSHAs))
+	for i, objectSHA := range params.ObjectSHAs {
+		objectSHAs[i] = objectSHA.String()
+	}
+
+	if err := s.git.FetchObjects(ctx, repoPath, sourcePath, objectSHAs); err != nil {
+		return fmt.Errorf("failed to fetch objects from repository %q: %w", params.Source, err)
+	}
+
+	return nil
+}
+
+// linkAlternates configures the repository to borrow objects from the object store of the upstream repository.
+// The path is stored relative to the objects directory, so the repositories root can be moved.
+func linkAlternates(repoPath, upstreamPath string) error {
+	upstreamObjects, err := filepath.Rel(
+		filepath.Join(repoPath, gitObjectsDir),
+		filepath.Join(upstreamPath, gitObjectsDir),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get relative path of upstream objects: %w", err)
+	}
+
+	alternatesPath := filepath.Join(repoPath, gitAlternatesFile)
+
+	err = os.MkdirAll(filepath.Dir(alternatesPath), fileMode700)
+	if err != nil {
+		return fmt.Errorf("failed to create info directory: %w", err)
+	}
+
+	err = os.WriteFile(alternatesPath, []byte(upstreamObjects+"\n"), fileMode600)
+	if err != nil {
+		return fmt.Errorf("failed to write alternates: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/git/api"
+)
+
+//nolint:funlen
+func TestForkRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	ctx := context.Background()
+	root := t.TempDir()
+	s := &Service{
+		reposRoot:   filepath.Join(root, ReposSubdirName),
+		git:         &api.Git{},
+		gitHookPath: "/bin/true",
+	}
+	actor := Identity{Name: "author", Email: "author@example.com"}
+
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=author", "GIT_AUTHOR_EMAIL=author@example.com",
+			"GIT_COMMITTER_NAME=author", "GIT_COMMITTER_EMAIL=author@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1",
+		)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v: %s", strings.Join(args, " "), err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	upstreamPath := getFullPathForRepo(s.reposRoot, "upstream")
+	forkPath := getFullPathForRepo(s.reposRoot, "fork")
+	forkOfForkPath := getFullPathForRepo(s.reposRoot, "fork-of-fork")
+
+	err := s.createRepositoryInternal(ctx, &WriteParams{RepoUID: "upstream", Actor: actor},
+		"main", nil, nil, time.Time{}, nil, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to create upstream repository: %v", err)
+	}
+
+	work := t.TempDir()
+	run(work, "init", "--initial-branch=main")
+	run(work, "commit", "--allow-empty", "-m", "initial")
+	mainSHA := run(work, "rev-parse", "HEAD")
+	run(work, "checkout", "-b", "other")
+	run(work, "commit", "--allow-empty", "-m", "other")
+	run(work, "push", upstreamPath, "main", "other")
+
+	_, err = s.ForkRepository(ctx, &ForkRepositoryParams{
+		RepoUID:           "fork",
+		Actor:             actor,
+		UpstreamRepoUID:   "upstream",
+		DefaultBranch:     "main",
+		OnlyDefaultBranch: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to fork repository: %v", err)
+	}
+
+	if got := run(forkPath, "for-each-ref", "--format=%(refname) %(objectname)"); got != "refs/heads/main "+mainSHA {
+		t.Errorf("expected only the default branch to be copied to the fork, got %q", got)
+	}
+	if _, err = os.Stat(filepath.Join(forkPath, gitAlternatesFile)); err != nil {
+		t.Errorf("expected the fork to borrow the objects of the upstream repository: %v", err)
+	}
+	if got := run(upstreamPath, "config", "gc.pruneExpire"); got != "never" {
+		t.Errorf("expected pruning of the upstream repository to be disabled, got %q", got)
+	}
+
+	// a commit that only exists in the fork, and is packed there
+	run(work, "checkout", "-b", "feature", "main")
+	run(work, "commit", "--allow-empty", "-m", "feature")
+	featureSHA := run(work, "rev-parse", "HEAD")
+	run(work, "push", forkPath, "feature")
+	run(forkPath, "repack", "-a", "-d", "-q")
+
+	_, err = s.ForkRepository(ctx, &ForkRepositoryParams{
+		RepoUID:         "fork-of-fork",
+		Actor:           actor,
+		UpstreamRepoUID: "fork",
+		DefaultBranch:   "main",
+	})
+	if err != nil {
+		t.Fatalf("failed to fork the fork: %v", err)
+	}
+
+	// the fork of the fork still uses the commit after the branch got deleted in the fork.
+	run(forkPath, "update-ref", "-d", "refs/heads/feature")
+
+	err = s.DetachFork(ctx, &DetachForkParams{WriteParams: WriteParams{RepoUID: "fork", Actor: actor}})
+	if err != nil {
+		t.Fatalf("failed to detach fork: %v", err)
+	}
+
+	if _, err = os.Stat(filepath.Join(forkPath, gitAlternatesFile)); !os.IsNotExist(err) {
+		t.Errorf("expected the alternates of the detached fork to be removed: %v", err)
+	}
+
+	// the upstream repository can be deleted once the fork is detached
+	if err = os.RemoveAll(upstreamPath); err != nil {
+		t.Fatalf("failed to delete upstream repository: %v", err)
+	}
+
+	run(forkPath, "fsck", "--connectivity-only", "--no-dangling")
+	run(forkOfForkPath, "cat-file", "-e", featureSHA)
+	run(forkOfForkPath, "fsck", "--connectivity-only", "--no-dangling")
+}
@@ -49,6 +49,13 @@ type Interface interface {
 
 	SyncRepository(ctx context.Context, params *SyncRepositoryParams) (*SyncRepositoryOutput, error)
 
+	// ForkRepository creates a new repository sharing the objects of the upstream repository.
+	ForkRepository(ctx context.Context, params *ForkRepositoryParams) (*CreateRepositoryOutput, error)
+	// DetachFork makes the fork independent of the object store of its upstream repository.
+	DetachFork(ctx context.Context, params *DetachForkParams) error
+	// FetchObjects copies objects from another repository without updating any references.
+	FetchObjects(ctx context.Context, params *FetchObjectsParams) error
+
 	MatchFiles(ctx context.Context, params *MatchFilesParams) (*MatchFilesOutput, error)
 
 	/*
@@ -57,6 +64,7 @@ type Interface interface {
 	GetCommit(ctx context.Context, params *GetCommitParams) (*GetCommitOutput, error)
 	ListCommits(ctx context.Context, params *ListCommitsParams) (*ListCommitsOutput, error)
 	ListCommitTags(ctx context.Context, params *ListCommitTagsParams) (*ListCommitTagsOutput, error)
+	ListCommitSignatures(ctx context.Context, params *ListCommitSignaturesParams) (*ListCommitSignaturesOutput, error)
 	GetCommitDivergences(ctx context.Context, params *GetCommitDivergencesParams) (*GetCommitDivergencesOutput, error)
 	CommitFiles(ctx context.Context, params *CommitFilesParams) (CommitFilesResponse, error)
 	MergeBase(ctx context.Context, params MergeBaseParams) (MergeBaseOutput, error)
@@ -78,6 +86,7 @@ type Interface interface {
 	RawDiff(ctx context.Context, w io.Writer, in *DiffParams, files ...api.FileDiffRequest) error
 	Diff(ctx context.Context, in *DiffParams, files ...api.FileDiffRequest) (<-chan *FileDiff, <-chan error)
 	DiffFileNames(ctx context.Context, in *DiffParams) (DiffFileNamesOutput, error)
+	ListCommitPaths(ctx context.Context, params *ListCommitPathsParams) (*ListCommitPathsOutput, error)
 	CommitDiff(ctx context.Context, params *GetCommitParams, w io.Writer) error
 	DiffShortStat(ctx context.Context, params *DiffParams) (DiffShortStatOutput, error)
 	DiffStats(ctx context.Context, params *DiffParams) (DiffStatsOutput, error)
@@ -95,6 +104,7 @@ type Interface interface {
 	 */
 	Blame(ctx context.Context, params *BlameParams) (<-chan *BlamePart, <-chan error)
 	PushRemote(ctx context.Context, params *PushRemoteParams) error
+	CreateBundle(ctx context.Context, params *CreateBundleParams) error
 
 	GeneratePipeline(ctx context.Context, params *GeneratePipelineParams) (GeneratePipelinesOutput, error)
 
@@ -25,6 +25,8 @@ import (
 type PushRemoteParams struct {
 	ReadParams
 	RemoteURL string
+	// RemoteAccess [OPTIONAL] restricts how git connects to the remote repository.
+	RemoteAccess *RemoteAccess
 }
 
 func (p *PushRemoteParams) Validate() error {
@@ -61,6 +63,7 @@ func (s *Service) PushRemote(ctx context.Context, params *PushRemoteParams) erro
 		Force:  false,
 		Env:    nil,
 		Mirror: true,
+		Access: (*api.RemoteAccess)(params.RemoteAccess),
 	})
 	if err != nil {
 		return fmt.Errorf("PushRemote: failed to push to remote repository: %w", err)
@@ -114,6 +114,18 @@ type SyncRepositoryParams struct {
 	// If empty, the default branch will be set to match the remote repository's default branch.
 	// WARNING: If the remote repo is empty and no value is provided, an api.ErrNoDefaultBranch error is returned.
 	DefaultBranch string
+	// RemoteAccess [OPTIONAL] restricts how git connects to the source repository.
+	RemoteAccess *RemoteAccess
+}
+
+// RemoteAccess restricts how git connects to a remote repository.
+type RemoteAccess struct {
+	// Resolve pins hosts to addresses, each entry is in the "HOST:PORT:ADDRESS[,ADDRESS]" format of curl.
+	Resolve []string
+	// NoRedirects stops git from following http redirects of the remote.
+	NoRedirects bool
+	// Protocols limits the protocols git can use to connect to the remote, all are allowed if it's empty.
+	Protocols []string
 }
 
 type SyncRepositoryOutput struct {
@@ -295,7 +307,7 @@ func (s *Service) SyncRepository(
 	}
 
 	// sync repo content
-	err = s.git.Sync(ctx, repoPath, params.Source, params.RefSpecs)
+	err = s.git.Sync(ctx, repoPath, params.Source, params.RefSpecs, (*api.RemoteAccess)(params.RemoteAccess))
 	if err != nil {
 		return nil, fmt.Errorf("failed to sync from source repo: %w", err)
 	}
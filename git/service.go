@@ -27,7 +27,7 @@ import (
 )
 
 const (
-	repoSubdirName           = "repos"
+	ReposSubdirName          = "repos"
 	ReposGraveyardSubdirName = "cleanup"
 )
 
@@ -48,7 +48,7 @@ func New(
 	storage storage.Store,
 ) (*Service, error) {
 	// Create repos folder
-	reposRoot := filepath.Join(config.Root, repoSubdirName)
+	reposRoot := filepath.Join(config.Root, ReposSubdirName)
 	if _, err := os.Stat(reposRoot); errors.Is(err, os.ErrNotExist) {
 		if err = os.MkdirAll(reposRoot, fileMode700); err != nil {
 			return nil, err
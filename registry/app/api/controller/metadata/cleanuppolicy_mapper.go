@@ -70,7 +70,7 @@ func getCleanupPolicyDto(
 ) *artifact.CleanupPolicy {
 	packagePrefix := cleanupPolicy.PackagePrefix
 	versionPrefix := cleanupPolicy.VersionPrefix
-	expiryDays := int(time.Duration(cleanupPolicy.ExpiryTime).Hours() / 24)
+	expiryDays := int((time.Duration(cleanupPolicy.ExpiryTime) * time.Millisecond).Hours() / 24)
 
 	return &artifact.CleanupPolicy{
 		Name:          &cleanupPolicy.Name,
@@ -30,6 +30,15 @@ type ArtifactDetails struct {
 	PackageType  PackageType `json:"package_type,omitempty"`
 }
 
+// CleanupDetails holds the result of the enforcement of a cleanup policy of a registry.
+type CleanupDetails struct {
+	RegistryID   int64    `json:"registry_id,omitempty"`
+	RegistryName string   `json:"registry_name,omitempty"`
+	PolicyName   string   `json:"policy_name,omitempty"`
+	ImagePaths   []string `json:"image_paths,omitempty"` // format = image:tag
+	DryRun       bool     `json:"dry_run,omitempty"`
+}
+
 // PackageType constants using iota.
 const (
 	PackageTypeDOCKER = iota
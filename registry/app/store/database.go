@@ -51,6 +51,8 @@ type BlobRepository interface {
 type CleanupPolicyRepository interface {
 	// GetIdsByRegistryId the CleanupPolicy Ids specified by Registry Key
 	GetIDsByRegistryID(ctx context.Context, id int64) (ids []int64, err error)
+	// GetAllRegistryIDs the IDs of all Registries that have at least one CleanupPolicy
+	GetAllRegistryIDs(ctx context.Context) (ids []int64, err error)
 	// GetByRegistryId the CleanupPolicy specified by Registry Key
 	GetByRegistryID(
 		ctx context.Context,
@@ -257,6 +259,14 @@ type TagRepository interface {
 		ctx context.Context, repoID int64, imageName string,
 		name string,
 	) (*types.Tag, error)
+	// GetTagsUpdatedBefore returns a page of tags of the registry that weren't updated since the provided time.
+	// Tags are ordered by ID, the page starts after the tag with the provided ID.
+	GetTagsUpdatedBefore(
+		ctx context.Context, registryID int64, before time.Time,
+		afterID int64, limit int,
+	) ([]*types.Tag, error)
+	// GetTagsByManifestID returns all tags of the registry that point to the manifest.
+	GetTagsByManifestID(ctx context.Context, registryID int64, manifestID int64) ([]*types.Tag, error)
 }
 
 // UpstreamProxyConfig holds the record of a config of upstream proxy in DB.
@@ -442,6 +452,14 @@ type ArtifactRepository interface {
 	// Create an Artifact
 	CreateOrUpdate(ctx context.Context, artifact *types.Artifact) error
 	Count(ctx context.Context) (int64, error)
+	// GetArtifactsUpdatedBefore returns a page of artifacts of the registry that weren't updated since
+	// the provided time. Artifacts are ordered by ID, the page starts after the artifact with the provided ID.
+	GetArtifactsUpdatedBefore(
+		ctx context.Context, registryID int64, before time.Time,
+		afterID int64, limit int,
+	) ([]*types.ImageArtifact, error)
+	// DeleteByID deletes the artifact together with its download statistics.
+	DeleteByID(ctx context.Context, id int64) error
 }
 
 type DownloadStatRepository interface {
@@ -121,6 +121,78 @@ func (a ArtifactDao) Count(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
+type imageArtifactDB struct {
+	artifactDB
+	ImageName string `db:"image_name"`
+}
+
+func (a ArtifactDao) GetArtifactsUpdatedBefore(
+	ctx context.Context, registryID int64, before time.Time,
+	afterID int64, limit int,
+) ([]*types.ImageArtifact, error) {
+	stmt := databaseg.Builder.
+		Select(util.ArrToStringByDelimiter(util.GetDBTagsFromStruct(artifactDB{}), ",")+", image_name").
+		From("artifacts").
+		Join("images ON image_id = artifact_image_id").
+		Where("image_registry_id = ? AND artifact_updated_at < ? AND artifact_id > ?",
+			registryID, before.UnixMilli(), afterID).
+		OrderBy("artifact_id").
+		Limit(uint64(limit))
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to convert query to sql")
+	}
+
+	db := dbtx.GetAccessor(ctx, a.db)
+
+	dst := []*imageArtifactDB{}
+	if err = db.SelectContext(ctx, &dst, sql, args...); err != nil {
+		return nil, databaseg.ProcessSQLErrorf(ctx, err, "Failed to find artifacts updated before %s", before)
+	}
+
+	artifacts := make([]*types.ImageArtifact, len(dst))
+	for i, d := range dst {
+		artifact, err := a.mapToArtifact(ctx, &d.artifactDB)
+		if err != nil {
+			return nil, err
+		}
+		artifacts[i] = &types.ImageArtifact{Artifact: *artifact, ImageName: d.ImageName}
+	}
+
+	return artifacts, nil
+}
+
+func (a ArtifactDao) DeleteByID(ctx context.Context, id int64) error {
+	db := dbtx.GetAccessor(ctx, a.db)
+
+	delStmt := databaseg.Builder.Delete("download_stats").
+		Where("download_stat_artifact_id = ?", id)
+
+	delQuery, delArgs, err := delStmt.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "Failed to convert query to sql")
+	}
+
+	if _, err = db.ExecContext(ctx, delQuery, delArgs...); err != nil {
+		return databaseg.ProcessSQLErrorf(ctx, err, "Failed to delete download stats of artifact")
+	}
+
+	delStmt = databaseg.Builder.Delete("artifacts").
+		Where("artifact_id = ?", id)
+
+	delQuery, delArgs, err = delStmt.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "Failed to convert query to sql")
+	}
+
+	if _, err = db.ExecContext(ctx, delQuery, delArgs...); err != nil {
+		return databaseg.ProcessSQLErrorf(ctx, err, "Failed to delete artifact")
+	}
+
+	return nil
+}
+
 func (a ArtifactDao) mapToInternalArtifact(ctx context.Context, in *types.Artifact) *artifactDB {
 	session, _ := request.AuthSessionFrom(ctx)
 
@@ -88,6 +88,22 @@ func (c CleanupPolicyDao) GetIDsByRegistryID(ctx context.Context, id int64) (ids
 	return res, nil
 }
 
+func (c CleanupPolicyDao) GetAllRegistryIDs(ctx context.Context) (ids []int64, err error) {
+	stmt := databaseg.Builder.Select("DISTINCT cp_registry_id").From("cleanup_policies").
+		OrderBy("cp_registry_id")
+	db := dbtx.GetAccessor(ctx, c.db)
+	var res []int64
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err = db.SelectContext(ctx, &res, query, args...); err != nil {
+		return nil, databaseg.ProcessSQLErrorf(ctx, err, "failed to get registry ids of cleanup policies")
+	}
+
+	return res, nil
+}
+
 func (c CleanupPolicyDao) GetByRegistryID(
 	ctx context.Context,
 	id int64,
@@ -0,0 +1,225 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/harness/gitness/registry/app/store"
+	"github.com/harness/gitness/registry/types"
+	store2 "github.com/harness/gitness/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type gcBlobTaskDao struct {
+	db *sqlx.DB
+}
+
+func NewGCBlobTaskDao(db *sqlx.DB) store.GCBlobTaskRepository {
+	return &gcBlobTaskDao{
+		db: db,
+	}
+}
+
+type gcBlobTaskDB struct {
+	BlobID      int64  `db:"blob_id"`
+	ReviewAfter int64  `db:"review_after"`
+	ReviewCount int    `db:"review_count"`
+	CreatedAt   int64  `db:"created_at"`
+	Event       string `db:"event"`
+}
+
+const gcBlobTaskSelectBase = `
+	SELECT
+		 blob_id
+		,review_after
+		,review_count
+		,created_at
+		,event
+	FROM gc_blob_review_queue`
+
+// FindAll returns all blob review tasks.
+func (dao gcBlobTaskDao) FindAll(ctx context.Context) ([]*types.GCBlobTask, error) {
+	const sqlQuery = gcBlobTaskSelectBase + `
+	ORDER BY review_after, blob_id`
+
+	db := dbtx.GetAccessor(ctx, dao.db)
+
+	dst := make([]*gcBlobTaskDB, 0)
+	if err := db.SelectContext(ctx, &dst, sqlQuery); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to find blob review tasks")
+	}
+
+	result := make([]*types.GCBlobTask, len(dst))
+	for i, t := range dst {
+		result[i] = mapToGCBlobTask(t)
+	}
+
+	return result, nil
+}
+
+// FindAndLockBefore finds the review task of a blob and locks it against writes,
+// but only if it's due for review before the provided date. Returns nil if there is no such task.
+func (dao gcBlobTaskDao) FindAndLockBefore(
+	ctx context.Context,
+	blobID int64,
+	date time.Time,
+) (*types.GCBlobTask, error) {
+	sqlQuery := gcBlobTaskSelectBase + `
+	WHERE blob_id = $1 AND review_after < $2`
+	sqlQuery += gcLockClause(dao.db, false)
+
+	return dao.get(ctx, sqlQuery, blobID, date.Unix())
+}
+
+// Count returns the number of blob review tasks.
+func (dao gcBlobTaskDao) Count(ctx context.Context) (int, error) {
+	const sqlQuery = `SELECT count(*) FROM gc_blob_review_queue`
+
+	db := dbtx.GetAccessor(ctx, dao.db)
+
+	var count int
+	if err := db.QueryRowContext(ctx, sqlQuery).Scan(&count); err != nil {
+		return 0, database.ProcessSQLErrorf(ctx, err, "Failed to count blob review tasks")
+	}
+
+	return count, nil
+}
+
+// Next returns the oldest blob review task that is due for review and locks it against writes.
+// Tasks that are already locked by other transactions are skipped. Returns nil if there is no such task.
+func (dao gcBlobTaskDao) Next(ctx context.Context) (*types.GCBlobTask, error) {
+	sqlQuery := gcBlobTaskSelectBase + `
+	WHERE review_after < $1
+	ORDER BY review_after
+	LIMIT 1`
+	sqlQuery += gcLockClause(dao.db, true)
+
+	return dao.get(ctx, sqlQuery, time.Now().Unix())
+}
+
+// Reschedule delays the review of the blob by the provided duration.
+func (dao gcBlobTaskDao) Reschedule(ctx context.Context, b *types.GCBlobTask, d time.Duration) error {
+	const sqlQuery = `
+	UPDATE gc_blob_review_queue
+	SET review_after = review_after + $1
+	WHERE blob_id = $2`
+
+	return dao.update(ctx, sqlQuery, int64(d.Seconds()), b.BlobID)
+}
+
+// Postpone increments the review count of the blob and sets the next review to be after the provided duration.
+func (dao gcBlobTaskDao) Postpone(ctx context.Context, b *types.GCBlobTask, d time.Duration) error {
+	const sqlQuery = `
+	UPDATE gc_blob_review_queue
+	SET review_after = $1, review_count = review_count + 1
+	WHERE blob_id = $2`
+
+	return dao.update(ctx, sqlQuery, time.Now().Add(d).Unix(), b.BlobID)
+}
+
+// IsDangling returns true if the blob isn't referenced by any manifest, either as a layer or as a configuration.
+func (dao gcBlobTaskDao) IsDangling(ctx context.Context, b *types.GCBlobTask) (bool, error) {
+	const sqlQuery = `
+	SELECT
+		NOT EXISTS (SELECT 1 FROM layers WHERE layer_blob_id = $1)
+		AND NOT EXISTS (SELECT 1 FROM manifests WHERE manifest_configuration_blob_id = $1)`
+
+	db := dbtx.GetAccessor(ctx, dao.db)
+
+	var dangling bool
+	if err := db.QueryRowContext(ctx, sqlQuery, b.BlobID).Scan(&dangling); err != nil {
+		return false, database.ProcessSQLErrorf(ctx, err, "Failed to check if blob is dangling")
+	}
+
+	return dangling, nil
+}
+
+// Delete removes the blob review task.
+func (dao gcBlobTaskDao) Delete(ctx context.Context, b *types.GCBlobTask) error {
+	const sqlQuery = `DELETE FROM gc_blob_review_queue WHERE blob_id = $1`
+
+	db := dbtx.GetAccessor(ctx, dao.db)
+
+	if _, err := db.ExecContext(ctx, sqlQuery, b.BlobID); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to delete blob review task")
+	}
+
+	return nil
+}
+
+func (dao gcBlobTaskDao) get(ctx context.Context, sqlQuery string, args ...any) (*types.GCBlobTask, error) {
+	db := dbtx.GetAccessor(ctx, dao.db)
+
+	dst := &gcBlobTaskDB{}
+	if err := db.GetContext(ctx, dst, sqlQuery, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to find blob review task")
+	}
+
+	return mapToGCBlobTask(dst), nil
+}
+
+func (dao gcBlobTaskDao) update(ctx context.Context, sqlQuery string, args ...any) error {
+	db := dbtx.GetAccessor(ctx, dao.db)
+
+	result, err := db.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to update blob review task")
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to get number of updated blob review tasks")
+	}
+
+	if count == 0 {
+		return store2.ErrResourceNotFound
+	}
+
+	return nil
+}
+
+// gcLockClause returns the row locking clause of the GC review queue queries.
+// SQLite doesn't support row locking, but there all write transactions are serialized anyway.
+func gcLockClause(db *sqlx.DB, skipLocked bool) string {
+	if db.DriverName() == SQLITE3 {
+		return ""
+	}
+	if skipLocked {
+		return `
+	FOR UPDATE SKIP LOCKED`
+	}
+	return `
+	FOR UPDATE`
+}
+
+func mapToGCBlobTask(in *gcBlobTaskDB) *types.GCBlobTask {
+	return &types.GCBlobTask{
+		BlobID:      in.BlobID,
+		ReviewAfter: in.ReviewAfter,
+		ReviewCount: in.ReviewCount,
+		CreatedAt:   in.CreatedAt,
+		Event:       in.Event,
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/registry/app/store"
+	"github.com/harness/gitness/registry/app/store/database/util"
+	"github.com/harness/gitness/registry/types"
+	store2 "github.com/harness/gitness/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/opencontainers/go-digest"
+)
+
+type gcManifestTaskDao struct {
+	db *sqlx.DB
+}
+
+func NewGCManifestTaskDao(db *sqlx.DB) store.GCManifestTaskRepository {
+	return &gcManifestTaskDao{
+		db: db,
+	}
+}
+
+type gcManifestTaskDB struct {
+	RegistryID  int64  `db:"registry_id"`
+	ManifestID  int64  `db:"manifest_id"`
+	ReviewAfter int64  `db:"review_after"`
+	ReviewCount int    `db:"review_count"`
+	CreatedAt   int64  `db:"created_at"`
+	Event       string `db:"event"`
+}
+
+const (
+	gcManifestTaskColumns = `
+		 registry_id
+		,manifest_id
+		,review_after
+		,review_count
+		,created_at
+		,event`
+
+	gcManifestTaskSelectBase = `
+	SELECT` + gcManifestTaskColumns + `
+	FROM gc_manifest_review_queue`
+)
+
+// FindAndLock finds the review task of a manifest and locks it against writes.
+// Returns nil if there is no such task.
+func (dao gcManifestTaskDao) FindAndLock(
+	ctx context.Context,
+	registryID, manifestID int64,
+) (*types.GCManifestTask, error) {
+	sqlQuery := gcManifestTaskSelectBase + `
+	WHERE registry_id = $1 AND manifest_id = $2`
+	sqlQuery += gcLockClause(dao.db, false)
+
+	return dao.get(ctx, sqlQuery, registryID, manifestID)
+}
+
+// FindAndLockBefore finds the review task of a manifest and locks it against writes,
+// but only if it's due for review before the provided date. Returns nil if there is no such task.
+func (dao gcManifestTaskDao) FindAndLockBefore(
+	ctx context.Context,
+	registryID, manifestID int64,
+	date time.Time,
+) (*types.GCManifestTask, error) {
+	sqlQuery := gcManifestTaskSelectBase + `
+	WHERE registry_id = $1 AND manifest_id = $2 AND review_after < $3`
+	sqlQuery += gcLockClause(dao.db, false)
+
+	return dao.get(ctx, sqlQuery, registryID, manifestID, date.Unix())
+}
+
+// FindAndLockNBefore finds the review tasks of the provided manifests and locks them against writes,
+// but only those that are due for review before the provided date.
+func (dao gcManifestTaskDao) FindAndLockNBefore(
+	ctx context.Context,
+	registryID int64,
+	manifestIDs []int64,
+	date time.Time,
+) ([]*types.GCManifestTask, error) {
+	if len(manifestIDs) == 0 {
+		return []*types.GCManifestTask{}, nil
+	}
+
+	stmt := database.Builder.
+		Select(gcManifestTaskColumns).
+		From("gc_manifest_review_queue").
+		Where("registry_id = ?", registryID).
+		Where(squirrel.Eq{"manifest_id": manifestIDs}).
+		Where("review_after < ?", date.Unix()).
+		OrderBy("manifest_id") // consistent lock order to avoid deadlocks
+
+	sqlQuery, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert query to sql: %w", err)
+	}
+	sqlQuery += gcLockClause(dao.db, false)
+
+	db := dbtx.GetAccessor(ctx, dao.db)
+
+	dst := make([]*gcManifestTaskDB, 0)
+	if err = db.SelectContext(ctx, &dst, sqlQuery, args...); err != nil {
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to find manifest review tasks")
+	}
+
+	result := make([]*types.GCManifestTask, len(dst))
+	for i, t := range dst {
+		result[i] = mapToGCManifestTask(t)
+	}
+
+	return result, nil
+}
+
+// Next returns the oldest manifest review task that is due for review and locks it against writes.
+// Tasks that are already locked by other transactions are skipped. Returns nil if there is no such task.
+func (dao gcManifestTaskDao) Next(ctx context.Context) (*types.GCManifestTask, error) {
+	sqlQuery := gcManifestTaskSelectBase + `
+	WHERE review_after < $1
+	ORDER BY review_after
+	LIMIT 1`
+	sqlQuery += gcLockClause(dao.db, true)
+
+	return dao.get(ctx, sqlQuery, time.Now().Unix())
+}
+
+// Postpone increments the review count of the manifest and sets the next review to be after the provided duration.
+func (dao gcManifestTaskDao) Postpone(ctx context.Context, m *types.GCManifestTask, d time.Duration) error {
+	const sqlQuery = `
+	UPDATE gc_manifest_review_queue
+	SET review_after = $1, review_count = review_count + 1
+	WHERE registry_id = $2 AND manifest_id = $3`
+
+	db := dbtx.GetAccessor(ctx, dao.db)
+
+	result, err := db.ExecContext(ctx, sqlQuery, time.Now().Add(d).Unix(), m.RegistryID, m.ManifestID)
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to postpone manifest review task")
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to get number of updated manifest review tasks")
+	}
+
+	if count == 0 {
+		return store2.ErrResourceNotFound
+	}
+
+	return nil
+}
+
+// IsDangling returns true if the manifest isn't tagged and isn't referenced by any manifest list.
+func (dao gcManifestTaskDao) IsDangling(ctx context.Context, m *types.GCManifestTask) (bool, error) {
+	const sqlQuery = `
+	SELECT
+		NOT EXISTS (
+			SELECT 1 FROM tags
+			WHERE tag_registry_id = $1 AND tag_manifest_id = $2
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM manifest_references
+			WHERE manifest_ref_registry_id = $1 AND manifest_ref_child_id = $2
+		)`
+
+	db := dbtx.GetAccessor(ctx, dao.db)
+
+	var dangling bool
+	if err := db.QueryRowContext(ctx, sqlQuery, m.RegistryID, m.ManifestID).Scan(&dangling); err != nil {
+		return false, database.ProcessSQLErrorf(ctx, err, "Failed to check if manifest is dangling")
+	}
+
+	return dangling, nil
+}
+
+// Delete removes the manifest review task.
+func (dao gcManifestTaskDao) Delete(ctx context.Context, m *types.GCManifestTask) error {
+	const sqlQuery = `DELETE FROM gc_manifest_review_queue WHERE registry_id = $1 AND manifest_id = $2`
+
+	db := dbtx.GetAccessor(ctx, dao.db)
+
+	if _, err := db.ExecContext(ctx, sqlQuery, m.RegistryID, m.ManifestID); err != nil {
+		return database.ProcessSQLErrorf(ctx, err, "Failed to delete manifest review task")
+	}
+
+	return nil
+}
+
+// DeleteManifest deletes the manifest and returns its digest. Returns nil if the manifest doesn't exist.
+func (dao gcManifestTaskDao) DeleteManifest(
+	ctx context.Context,
+	registryID, id int64,
+) (*digest.Digest, error) {
+	const sqlQuery = `
+	DELETE FROM manifests
+	WHERE manifest_registry_id = $1 AND manifest_id = $2
+	RETURNING manifest_digest`
+
+	db := dbtx.GetAccessor(ctx, dao.db)
+
+	var digestBytes []byte
+	if err := db.QueryRowContext(ctx, sqlQuery, registryID, id).Scan(&digestBytes); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to delete manifest")
+	}
+
+	d, err := types.Digest(util.GetHexEncodedString(digestBytes)).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse digest of deleted manifest: %w", err)
+	}
+
+	return &d, nil
+}
+
+func (dao gcManifestTaskDao) get(ctx context.Context, sqlQuery string, args ...any) (*types.GCManifestTask, error) {
+	db := dbtx.GetAccessor(ctx, dao.db)
+
+	dst := &gcManifestTaskDB{}
+	if err := db.GetContext(ctx, dst, sqlQuery, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+		return nil, database.ProcessSQLErrorf(ctx, err, "Failed to find manifest review task")
+	}
+
+	return mapToGCManifestTask(dst), nil
+}
+
+func mapToGCManifestTask(in *gcManifestTaskDB) *types.GCManifestTask {
+	return &types.GCManifestTask{
+		RegistryID:  in.RegistryID,
+		ManifestID:  in.ManifestID,
+		ReviewAfter: in.ReviewAfter,
+		ReviewCount: in.ReviewCount,
+		CreatedAt:   in.CreatedAt,
+		Event:       in.Event,
+	}
+}
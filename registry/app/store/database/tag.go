@@ -934,6 +934,57 @@ func (t tagDao) FindTag(
 	return t.mapToTag(ctx, dst)
 }
 
+func (t tagDao) GetTagsUpdatedBefore(
+	ctx context.Context, registryID int64, before time.Time,
+	afterID int64, limit int,
+) ([]*types.Tag, error) {
+	stmt := databaseg.Builder.
+		Select(util.ArrToStringByDelimiter(util.GetDBTagsFromStruct(tagDB{}), ",")).
+		From("tags").
+		Where("tag_registry_id = ? AND tag_updated_at < ? AND tag_id > ?",
+			registryID, before.UnixMilli(), afterID).
+		OrderBy("tag_id").
+		Limit(uint64(limit))
+
+	db := dbtx.GetAccessor(ctx, t.db)
+
+	dst := []*tagDB{}
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to convert query to sql")
+	}
+
+	if err = db.SelectContext(ctx, &dst, sql, args...); err != nil {
+		return nil, databaseg.ProcessSQLErrorf(ctx, err, "Failed to find tags updated before %s", before)
+	}
+
+	return t.mapToTagList(ctx, dst)
+}
+
+func (t tagDao) GetTagsByManifestID(
+	ctx context.Context, registryID int64, manifestID int64,
+) ([]*types.Tag, error) {
+	stmt := databaseg.Builder.
+		Select(util.ArrToStringByDelimiter(util.GetDBTagsFromStruct(tagDB{}), ",")).
+		From("tags").
+		Where("tag_registry_id = ? AND tag_manifest_id = ?", registryID, manifestID).
+		OrderBy("tag_id")
+
+	db := dbtx.GetAccessor(ctx, t.db)
+
+	dst := []*tagDB{}
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to convert query to sql")
+	}
+
+	if err = db.SelectContext(ctx, &dst, sql, args...); err != nil {
+		return nil, databaseg.ProcessSQLErrorf(ctx, err, "Failed to find tags of manifest %d", manifestID)
+	}
+
+	return t.mapToTagList(ctx, dst)
+}
+
 func (t tagDao) mapToInternalTag(ctx context.Context, in *types.Tag) *tagDB {
 	if in.CreatedAt.IsZero() {
 		in.CreatedAt = time.Now()
@@ -87,6 +87,14 @@ func ProvideCleanupPolicyDao(db *sqlx.DB, tx dbtx.Transactor) store.CleanupPolic
 	return NewCleanupPolicyDao(db, tx)
 }
 
+func ProvideGCBlobTaskDao(db *sqlx.DB) store.GCBlobTaskRepository {
+	return NewGCBlobTaskDao(db)
+}
+
+func ProvideGCManifestTaskDao(db *sqlx.DB) store.GCManifestTaskRepository {
+	return NewGCManifestTaskDao(db)
+}
+
 var WireSet = wire.NewSet(
 	ProvideUpstreamDao,
 	ProvideRepoDao,
@@ -103,4 +111,6 @@ var WireSet = wire.NewSet(
 	ProvideArtifactDao,
 	ProvideDownloadStatDao,
 	ProvideBandwidthStatDao,
+	ProvideGCBlobTaskDao,
+	ProvideGCManifestTaskDao,
 )
@@ -0,0 +1,385 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corestore "github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/job"
+	"github.com/harness/gitness/registry/app/event"
+	"github.com/harness/gitness/registry/app/store"
+	"github.com/harness/gitness/registry/types"
+	gitnessstore "github.com/harness/gitness/store"
+	"github.com/harness/gitness/store/database/dbtx"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	jobTypeCleanupPolicies        = "gitness:registry:cleanup-policies"
+	jobCronCleanupPolicies        = "15 3 * * *" // At minute 15 past hour 3 every day.
+	jobMaxDurationCleanupPolicies = 30 * time.Minute
+
+	// cleanupPoliciesBatchSize is the number of tags or artifacts that are evaluated at once.
+	cleanupPoliciesBatchSize = 500
+)
+
+type cleanupPoliciesJob struct {
+	dryRun bool
+
+	tx                 dbtx.Transactor
+	registryStore      store.RegistryRepository
+	cleanupPolicyStore store.CleanupPolicyRepository
+	tagStore           store.TagRepository
+	artifactStore      store.ArtifactRepository
+	manifestStore      store.ManifestRepository
+	spacePathStore     corestore.SpacePathStore
+	reporter           event.Reporter
+}
+
+func newCleanupPoliciesJob(
+	dryRun bool,
+	tx dbtx.Transactor,
+	registryStore store.RegistryRepository,
+	cleanupPolicyStore store.CleanupPolicyRepository,
+	tagStore store.TagRepository,
+	artifactStore store.ArtifactRepository,
+	manifestStore store.ManifestRepository,
+	spacePathStore corestore.SpacePathStore,
+	reporter event.Reporter,
+) *cleanupPoliciesJob {
+	return &cleanupPoliciesJob{
+		dryRun: dryRun,
+
+		tx:                 tx,
+		registryStore:      registryStore,
+		cleanupPolicyStore: cleanupPolicyStore,
+		tagStore:           tagStore,
+		artifactStore:      artifactStore,
+		manifestStore:      manifestStore,
+		spacePathStore:     spacePathStore,
+		reporter:           reporter,
+	}
+}
+
+// Handle evaluates the cleanup policies of all registries and deletes the expired versions.
+// The deleted tags are picked up by the registry garbage collector, which removes the unreferenced data.
+func (j *cleanupPoliciesJob) Handle(ctx context.Context, _ string, _ job.ProgressReporter) (string, error) {
+	registryIDs, err := j.cleanupPolicyStore.GetAllRegistryIDs(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list registries with cleanup policies: %w", err)
+	}
+
+	log.Ctx(ctx).Info().Msgf("start enforcing cleanup policies of %d registries (dry run: %t)",
+		len(registryIDs), j.dryRun)
+
+	total := 0
+	failed := 0
+	for _, registryID := range registryIDs {
+		n, err := j.enforce(ctx, registryID)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("failed to enforce cleanup policies of registry %d", registryID)
+			failed++
+		}
+		total += n
+	}
+
+	verb := "deleted"
+	if j.dryRun {
+		verb = "would delete"
+	}
+
+	result := fmt.Sprintf("%s %d expired versions in %d registries", verb, total, len(registryIDs))
+	if failed > 0 {
+		result += fmt.Sprintf(", failed for %d registries", failed)
+	}
+
+	log.Ctx(ctx).Info().Msg(result)
+
+	return result, nil
+}
+
+// enforce deletes the tags and artifacts of the registry that match any of its cleanup policies.
+// Returns the number of deleted versions.
+func (j *cleanupPoliciesJob) enforce(ctx context.Context, registryID int64) (int, error) {
+	registry, err := j.registryStore.Get(ctx, registryID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find registry: %w", err)
+	}
+
+	policies, err := j.cleanupPolicyStore.GetByRegistryID(ctx, registryID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cleanup policies: %w", err)
+	}
+	if policies == nil {
+		return 0, nil
+	}
+
+	active := activePolicies(*policies)
+	if len(active) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+
+	// only versions that are older than the shortest expiry time can match any of the policies
+	before := now.Add(-time.Duration(active[0].ExpiryTime) * time.Millisecond)
+	for _, policy := range active[1:] {
+		before = maxTime(before, now.Add(-time.Duration(policy.ExpiryTime)*time.Millisecond))
+	}
+
+	deleted := make(map[string][]string)
+
+	count, err := j.enforceTags(ctx, registryID, active, before, now, deleted)
+	if err == nil {
+		var n int
+		n, err = j.enforceArtifacts(ctx, registryID, active, before, now, deleted)
+		count += n
+	}
+
+	j.report(ctx, registry, deleted)
+
+	return count, err
+}
+
+// enforceTags deletes the tags that match any of the policies.
+// The deleted tags are recorded per policy in the deleted map.
+func (j *cleanupPoliciesJob) enforceTags(
+	ctx context.Context,
+	registryID int64,
+	policies []types.CleanupPolicy,
+	before time.Time,
+	now time.Time,
+	deleted map[string][]string,
+) (int, error) {
+	count := 0
+	afterID := int64(0)
+
+	for {
+		tags, err := j.tagStore.GetTagsUpdatedBefore(ctx, registryID, before, afterID, cleanupPoliciesBatchSize)
+		if err != nil {
+			return count, fmt.Errorf("failed to list tags: %w", err)
+		}
+
+		for _, tag := range tags {
+			policy := matchPolicy(policies, tag.ImageName, tag.Name, tag.UpdatedAt, now)
+			if policy == nil {
+				continue
+			}
+
+			if !j.dryRun {
+				err = j.tx.WithTx(ctx, func(ctx context.Context) error {
+					return j.tagStore.DeleteTag(ctx, registryID, tag.ImageName, tag.Name)
+				})
+				if err != nil {
+					return count, fmt.Errorf("failed to delete tag %s:%s: %w", tag.ImageName, tag.Name, err)
+				}
+			}
+
+			deleted[policy.Name] = append(deleted[policy.Name], tag.ImageName+":"+tag.Name)
+			count++
+		}
+
+		if len(tags) < cleanupPoliciesBatchSize {
+			return count, nil
+		}
+		afterID = tags[len(tags)-1].ID
+	}
+}
+
+// enforceArtifacts deletes the artifacts that match any of the policies.
+// An artifact that is still referenced by a tag that isn't expired is kept, as the version is still in use.
+// The deleted artifacts are recorded per policy in the deleted map.
+//
+//nolint:gocognit // the per artifact checks are easier to follow in one place.
+func (j *cleanupPoliciesJob) enforceArtifacts(
+	ctx context.Context,
+	registryID int64,
+	policies []types.CleanupPolicy,
+	before time.Time,
+	now time.Time,
+	deleted map[string][]string,
+) (int, error) {
+	count := 0
+	afterID := int64(0)
+
+	for {
+		artifacts, err := j.artifactStore.GetArtifactsUpdatedBefore(
+			ctx, registryID, before, afterID, cleanupPoliciesBatchSize)
+		if err != nil {
+			return count, fmt.Errorf("failed to list artifacts: %w", err)
+		}
+
+		for _, artifact := range artifacts {
+			policy := matchPolicy(policies, artifact.ImageName, artifact.Version, artifact.UpdatedAt, now)
+			if policy == nil {
+				continue
+			}
+
+			manifest, err := j.manifestStore.FindManifestByDigest(
+				ctx, registryID, artifact.ImageName, types.Digest(artifact.Version))
+			if err != nil && !errors.Is(err, gitnessstore.ErrResourceNotFound) {
+				return count, fmt.Errorf("failed to find manifest of artifact %d: %w", artifact.ID, err)
+			}
+
+			var tags []*types.Tag
+			if manifest != nil {
+				tags, err = j.tagStore.GetTagsByManifestID(ctx, registryID, manifest.ID)
+				if err != nil {
+					return count, fmt.Errorf("failed to list tags of artifact %d: %w", artifact.ID, err)
+				}
+			}
+
+			if !allTagsExpired(tags, policy, now) {
+				continue
+			}
+
+			if !j.dryRun {
+				err = j.tx.WithTx(ctx, func(ctx context.Context) error {
+					if len(tags) > 0 {
+						if _, err := j.tagStore.DeleteTagByManifestID(ctx, registryID, manifest.ID); err != nil {
+							return fmt.Errorf("failed to delete tags: %w", err)
+						}
+					}
+					return j.artifactStore.DeleteByID(ctx, artifact.ID)
+				})
+				if err != nil {
+					return count, fmt.Errorf("failed to delete artifact %d: %w", artifact.ID, err)
+				}
+			}
+
+			deleted[policy.Name] = append(deleted[policy.Name], artifactPath(artifact))
+			count++
+		}
+
+		if len(artifacts) < cleanupPoliciesBatchSize {
+			return count, nil
+		}
+		afterID = artifacts[len(artifacts)-1].ID
+	}
+}
+
+// allTagsExpired returns true if none of the tags was updated within the expiry time of the policy.
+func allTagsExpired(tags []*types.Tag, policy *types.CleanupPolicy, now time.Time) bool {
+	expiry := time.Duration(policy.ExpiryTime) * time.Millisecond
+	for _, tag := range tags {
+		if !tag.UpdatedAt.Before(now.Add(-expiry)) {
+			return false
+		}
+	}
+	return true
+}
+
+func artifactPath(artifact *types.ImageArtifact) string {
+	if dgst, err := types.Digest(artifact.Version).Parse(); err == nil {
+		return artifact.ImageName + "@" + dgst.String()
+	}
+	return artifact.ImageName + "@" + artifact.Version
+}
+
+func (j *cleanupPoliciesJob) report(ctx context.Context, registry *types.Registry, deleted map[string][]string) {
+	if len(deleted) == 0 {
+		return
+	}
+
+	spacePath := ""
+	path, err := j.spacePathStore.FindPrimaryBySpaceID(ctx, registry.ParentID)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to find path of space %d", registry.ParentID)
+	} else {
+		spacePath = path.Value
+	}
+
+	for policyName, imagePaths := range deleted {
+		log.Ctx(ctx).Info().Msgf("cleanup policy %q of registry %q matched %d versions (dry run: %t)",
+			policyName, registry.Name, len(imagePaths), j.dryRun)
+
+		j.reporter.ReportEvent(ctx, &event.CleanupDetails{
+			RegistryID:   registry.ID,
+			RegistryName: registry.Name,
+			PolicyName:   policyName,
+			ImagePaths:   imagePaths,
+			DryRun:       j.dryRun,
+		}, spacePath)
+	}
+}
+
+// activePolicies returns the policies that have an expiry time, ordered by ID.
+func activePolicies(policies []types.CleanupPolicy) []types.CleanupPolicy {
+	active := make([]types.CleanupPolicy, 0, len(policies))
+	for _, policy := range policies {
+		if policy.ExpiryTime > 0 {
+			active = append(active, policy)
+		}
+	}
+
+	sort.Slice(active, func(i, k int) bool { return active[i].ID < active[k].ID })
+
+	return active
+}
+
+// matchPolicy returns the first policy that the version of the package is expired for, or nil if there is none.
+// Empty package or version prefix lists match all packages or versions respectively.
+func matchPolicy(
+	policies []types.CleanupPolicy,
+	packageName string,
+	version string,
+	updated time.Time,
+	now time.Time,
+) *types.CleanupPolicy {
+	for i := range policies {
+		policy := &policies[i]
+
+		expiry := time.Duration(policy.ExpiryTime) * time.Millisecond
+		if !updated.Before(now.Add(-expiry)) {
+			continue
+		}
+
+		if !hasAnyPrefix(packageName, policy.PackagePrefix) || !hasAnyPrefix(version, policy.VersionPrefix) {
+			continue
+		}
+
+		return policy
+	}
+
+	return nil
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,288 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cleanup
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	corestore "github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/registry/app/event"
+	"github.com/harness/gitness/registry/app/store"
+	"github.com/harness/gitness/registry/types"
+	gitnessstore "github.com/harness/gitness/store"
+	coretypes "github.com/harness/gitness/types"
+)
+
+func TestMatchPolicy(t *testing.T) {
+	now := time.Now()
+	day := int64(24 * time.Hour / time.Millisecond)
+
+	policies := activePolicies([]types.CleanupPolicy{
+		{ID: 3, Name: "all", ExpiryTime: 30 * day},
+		{ID: 2, Name: "disabled", ExpiryTime: 0},
+		{ID: 1, Name: "snapshots", ExpiryTime: 7 * day, PackagePrefix: []string{"app"}, VersionPrefix: []string{"dev-"}},
+	})
+
+	tests := []struct {
+		name   string
+		tag    types.Tag
+		expect string
+	}{
+		{
+			name:   "recent",
+			tag:    types.Tag{ImageName: "app", Name: "dev-1", UpdatedAt: now.Add(-time.Hour)},
+			expect: "",
+		},
+		{
+			name:   "expired snapshot",
+			tag:    types.Tag{ImageName: "app", Name: "dev-1", UpdatedAt: now.Add(-10 * 24 * time.Hour)},
+			expect: "snapshots",
+		},
+		{
+			name:   "release not expired",
+			tag:    types.Tag{ImageName: "app", Name: "v1.0.0", UpdatedAt: now.Add(-10 * 24 * time.Hour)},
+			expect: "",
+		},
+		{
+			name:   "release expired",
+			tag:    types.Tag{ImageName: "app", Name: "v1.0.0", UpdatedAt: now.Add(-40 * 24 * time.Hour)},
+			expect: "all",
+		},
+		{
+			name:   "other package",
+			tag:    types.Tag{ImageName: "lib", Name: "dev-1", UpdatedAt: now.Add(-10 * 24 * time.Hour)},
+			expect: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := ""
+			policy := matchPolicy(policies, test.tag.ImageName, test.tag.Name, test.tag.UpdatedAt, now)
+			if policy != nil {
+				got = policy.Name
+			}
+			if got != test.expect {
+				t.Errorf("expected policy %q, got %q", test.expect, got)
+			}
+		})
+	}
+}
+
+type testTx struct{}
+
+func (testTx) WithTx(ctx context.Context, txFn func(ctx context.Context) error, _ ...interface{}) error {
+	return txFn(ctx)
+}
+
+type testRegistryStore struct {
+	store.RegistryRepository
+}
+
+func (testRegistryStore) Get(_ context.Context, id int64) (*types.Registry, error) {
+	return &types.Registry{ID: id, Name: "registry", ParentID: 1}, nil
+}
+
+type testCleanupPolicyStore struct {
+	store.CleanupPolicyRepository
+	policies []types.CleanupPolicy
+}
+
+func (s testCleanupPolicyStore) GetAllRegistryIDs(context.Context) ([]int64, error) {
+	return []int64{1}, nil
+}
+
+func (s testCleanupPolicyStore) GetByRegistryID(context.Context, int64) (*[]types.CleanupPolicy, error) {
+	return &s.policies, nil
+}
+
+type testTagStore struct {
+	store.TagRepository
+	tags []*types.Tag
+}
+
+func (s *testTagStore) GetTagsUpdatedBefore(
+	_ context.Context, _ int64, before time.Time, afterID int64, _ int,
+) ([]*types.Tag, error) {
+	var tags []*types.Tag
+	for _, tag := range s.tags {
+		if tag.ID > afterID && tag.UpdatedAt.Before(before) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+func (s *testTagStore) GetTagsByManifestID(_ context.Context, _ int64, manifestID int64) ([]*types.Tag, error) {
+	var tags []*types.Tag
+	for _, tag := range s.tags {
+		if tag.ManifestID == manifestID {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+func (s *testTagStore) DeleteTag(_ context.Context, _ int64, imageName string, name string) error {
+	s.tags = slices.DeleteFunc(s.tags, func(tag *types.Tag) bool {
+		return tag.ImageName == imageName && tag.Name == name
+	})
+	return nil
+}
+
+func (s *testTagStore) DeleteTagByManifestID(_ context.Context, _ int64, manifestID int64) (bool, error) {
+	n := len(s.tags)
+	s.tags = slices.DeleteFunc(s.tags, func(tag *types.Tag) bool { return tag.ManifestID == manifestID })
+	return len(s.tags) < n, nil
+}
+
+type testArtifactStore struct {
+	store.ArtifactRepository
+	artifacts []*types.ImageArtifact
+}
+
+func (s *testArtifactStore) GetArtifactsUpdatedBefore(
+	_ context.Context, _ int64, before time.Time, afterID int64, _ int,
+) ([]*types.ImageArtifact, error) {
+	var artifacts []*types.ImageArtifact
+	for _, artifact := range s.artifacts {
+		if artifact.ID > afterID && artifact.UpdatedAt.Before(before) {
+			artifacts = append(artifacts, artifact)
+		}
+	}
+	return artifacts, nil
+}
+
+func (s *testArtifactStore) DeleteByID(_ context.Context, id int64) error {
+	s.artifacts = slices.DeleteFunc(s.artifacts, func(artifact *types.ImageArtifact) bool { return artifact.ID == id })
+	return nil
+}
+
+type testManifestStore struct {
+	store.ManifestRepository
+	manifests map[types.Digest]int64
+}
+
+func (s testManifestStore) FindManifestByDigest(
+	_ context.Context, _ int64, _ string, dgst types.Digest,
+) (*types.Manifest, error) {
+	id, ok := s.manifests[dgst]
+	if !ok {
+		return nil, gitnessstore.ErrResourceNotFound
+	}
+	return &types.Manifest{ID: id}, nil
+}
+
+type testSpacePathStore struct {
+	corestore.SpacePathStore
+}
+
+func (testSpacePathStore) FindPrimaryBySpaceID(_ context.Context, spaceID int64) (*coretypes.SpacePath, error) {
+	return &coretypes.SpacePath{Value: "root", SpaceID: spaceID}, nil
+}
+
+type testReporter struct {
+	events []*event.CleanupDetails
+}
+
+func (r *testReporter) ReportEvent(_ context.Context, payload interface{}, _ string) {
+	r.events = append(r.events, payload.(*event.CleanupDetails)) //nolint:errcheck
+}
+
+func TestCleanupPoliciesJob(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	old := now.Add(-10 * day)
+
+	newTestJob := func(dryRun bool) (*cleanupPoliciesJob, *testTagStore, *testArtifactStore, *testReporter) {
+		tags := &testTagStore{tags: []*types.Tag{
+			{ID: 1, ImageName: "app", Name: "v1", ManifestID: 10, UpdatedAt: old},
+			{ID: 2, ImageName: "app", Name: "latest", ManifestID: 20, UpdatedAt: now},
+			{ID: 3, ImageName: "app", Name: "v2", ManifestID: 20, UpdatedAt: old},
+		}}
+		artifacts := &testArtifactStore{artifacts: []*types.ImageArtifact{
+			// tagged only by an expired tag
+			{Artifact: types.Artifact{ID: 1, Version: "a1", UpdatedAt: old}, ImageName: "app"},
+			// still tagged by a recently updated tag
+			{Artifact: types.Artifact{ID: 2, Version: "a2", UpdatedAt: old}, ImageName: "app"},
+			// manifest is already gone
+			{Artifact: types.Artifact{ID: 3, Version: "a3", UpdatedAt: old}, ImageName: "app"},
+			// not expired
+			{Artifact: types.Artifact{ID: 4, Version: "a4", UpdatedAt: now}, ImageName: "app"},
+		}}
+		manifests := testManifestStore{manifests: map[types.Digest]int64{"a1": 10, "a2": 20, "a4": 40}}
+		reporter := &testReporter{}
+
+		policies := testCleanupPolicyStore{policies: []types.CleanupPolicy{
+			{ID: 1, Name: "week", ExpiryTime: int64(7 * day / time.Millisecond)},
+		}}
+
+		j := newCleanupPoliciesJob(dryRun, testTx{}, testRegistryStore{}, policies, tags, artifacts, manifests,
+			testSpacePathStore{}, reporter)
+
+		return j, tags, artifacts, reporter
+	}
+
+	t.Run("delete", func(t *testing.T) {
+		j, tags, artifacts, reporter := newTestJob(false)
+
+		result, err := j.Handle(context.Background(), "", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if result != "deleted 4 expired versions in 1 registries" {
+			t.Errorf("unexpected result: %q", result)
+		}
+
+		if len(tags.tags) != 1 || tags.tags[0].Name != "latest" {
+			t.Errorf("expected only the recent tag to remain, got %v", tags.tags)
+		}
+
+		var remaining []int64
+		for _, artifact := range artifacts.artifacts {
+			remaining = append(remaining, artifact.ID)
+		}
+		if !slices.Equal(remaining, []int64{2, 4}) {
+			t.Errorf("expected artifacts 2 and 4 to remain, got %v", remaining)
+		}
+
+		if len(reporter.events) != 1 || len(reporter.events[0].ImagePaths) != 4 || reporter.events[0].DryRun {
+			t.Errorf("unexpected events: %+v", reporter.events)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		j, tags, artifacts, reporter := newTestJob(true)
+
+		result, err := j.Handle(context.Background(), "", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !strings.HasPrefix(result, "would delete") {
+			t.Errorf("unexpected result: %q", result)
+		}
+
+		if len(tags.tags) != 3 || len(artifacts.artifacts) != 4 {
+			t.Error("expected nothing to be deleted in dry run mode")
+		}
+		if len(reporter.events) != 1 || !reporter.events[0].DryRun {
+			t.Errorf("unexpected events: %+v", reporter.events)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corestore "github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/job"
+	"github.com/harness/gitness/registry/app/event"
+	"github.com/harness/gitness/registry/app/store"
+	"github.com/harness/gitness/store/database/dbtx"
+)
+
+type Config struct {
+	// DryRun makes the cleanup policy job only report the versions that would be deleted.
+	DryRun bool
+}
+
+func (c *Config) Prepare() error {
+	if c == nil {
+		return errors.New("config is required")
+	}
+	return nil
+}
+
+// Service is responsible for enforcing the cleanup policies of the registries.
+type Service struct {
+	config             Config
+	scheduler          *job.Scheduler
+	executor           *job.Executor
+	tx                 dbtx.Transactor
+	registryStore      store.RegistryRepository
+	cleanupPolicyStore store.CleanupPolicyRepository
+	tagStore           store.TagRepository
+	artifactStore      store.ArtifactRepository
+	manifestStore      store.ManifestRepository
+	spacePathStore     corestore.SpacePathStore
+	reporter           event.Reporter
+}
+
+func NewService(
+	config Config,
+	scheduler *job.Scheduler,
+	executor *job.Executor,
+	tx dbtx.Transactor,
+	registryStore store.RegistryRepository,
+	cleanupPolicyStore store.CleanupPolicyRepository,
+	tagStore store.TagRepository,
+	artifactStore store.ArtifactRepository,
+	manifestStore store.ManifestRepository,
+	spacePathStore corestore.SpacePathStore,
+	reporter event.Reporter,
+) (*Service, error) {
+	if err := config.Prepare(); err != nil {
+		return nil, fmt.Errorf("provided registry cleanup config is invalid: %w", err)
+	}
+
+	return &Service{
+		config: config,
+
+		scheduler:          scheduler,
+		executor:           executor,
+		tx:                 tx,
+		registryStore:      registryStore,
+		cleanupPolicyStore: cleanupPolicyStore,
+		tagStore:           tagStore,
+		artifactStore:      artifactStore,
+		manifestStore:      manifestStore,
+		spacePathStore:     spacePathStore,
+		reporter:           reporter,
+	}, nil
+}
+
+func (s *Service) Register(ctx context.Context) error {
+	if err := s.executor.Register(
+		jobTypeCleanupPolicies,
+		newCleanupPoliciesJob(
+			s.config.DryRun,
+			s.tx,
+			s.registryStore,
+			s.cleanupPolicyStore,
+			s.tagStore,
+			s.artifactStore,
+			s.manifestStore,
+			s.spacePathStore,
+			s.reporter,
+		),
+	); err != nil {
+		return fmt.Errorf("failed to register job handler for registry cleanup policies: %w", err)
+	}
+
+	err := s.scheduler.AddRecurring(
+		ctx,
+		jobTypeCleanupPolicies,
+		jobTypeCleanupPolicies,
+		jobCronCleanupPolicies,
+		jobMaxDurationCleanupPolicies,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to schedule registry cleanup policies job: %w", err)
+	}
+
+	return nil
+}
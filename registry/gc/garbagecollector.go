@@ -1,4 +1,4 @@
-//  Copyright 2023 Harness, Inc.
+// Copyright 2023 Harness, Inc.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -16,51 +16,398 @@ package gc
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
 	"time"
 
+	"github.com/harness/gitness/app/services/backup/pause"
+	"github.com/harness/gitness/app/services/settings"
 	corestore "github.com/harness/gitness/app/store"
 	storagedriver "github.com/harness/gitness/registry/app/driver"
+	"github.com/harness/gitness/registry/app/storage"
 	"github.com/harness/gitness/registry/app/store"
 	registrytypes "github.com/harness/gitness/registry/types"
+	gitnessstore "github.com/harness/gitness/store"
+	"github.com/harness/gitness/store/database/dbtx"
 	"github.com/harness/gitness/types"
+
+	"github.com/rs/zerolog/log"
 )
 
-type Noop struct{}
+const (
+	// postponeBaseDelay is the delay of the review of a task after the first failed attempt.
+	// The delay doubles with every subsequent failure, up to postponeMaxDelay.
+	postponeBaseDelay = 5 * time.Minute
+	postponeMaxDelay  = 24 * time.Hour
+)
 
-func New() Service {
-	return &Noop{}
-}
+// service is an online garbage collector of the artifact registry.
+// It processes the blob and manifest review queues, which are filled by database triggers
+// whenever a blob or a manifest might have become unreferenced.
+type service struct {
+	tx               dbtx.Transactor
+	blobTaskRepo     store.GCBlobTaskRepository
+	manifestTaskRepo store.GCManifestTaskRepository
+	settingsService  *settings.Service
 
-func (s *Noop) Start(
-	context.Context, corestore.SpaceStore,
-	store.BlobRepository, storagedriver.StorageDeleter,
-	*types.Config,
-) {
-	// NOOP
+	// set on Start
+	spaceStore    corestore.SpaceStore
+	blobRepo      store.BlobRepository
+	storageClient *storage.GcStorageClient
+	config        gcConfig
+
+	started          atomic.Int64
+	reclaimedBytes   atomic.Int64
+	deletedBlobs     atomic.Int64
+	deletedManifests atomic.Int64
+	lastDeleted      atomic.Int64
 }
 
-func (s *Noop) BlobFindAndLockBefore(context.Context, int64, time.Time) (*registrytypes.GCBlobTask, error) {
-	// NOOP
-	//nolint:nilnil
-	return nil, nil
+type gcConfig struct {
+	noIdleBackoff       bool
+	maxBackoff          time.Duration
+	initialInterval     time.Duration
+	transactionTimeout  time.Duration
+	blobsStorageTimeout time.Duration
 }
 
-func (s *Noop) BlobReschedule(context.Context, *registrytypes.GCBlobTask, time.Duration) error {
-	// NOOP
-	return nil
+func New(
+	tx dbtx.Transactor,
+	blobTaskRepo store.GCBlobTaskRepository,
+	manifestTaskRepo store.GCManifestTaskRepository,
+	settingsService *settings.Service,
+) Service {
+	return &service{
+		tx:               tx,
+		blobTaskRepo:     blobTaskRepo,
+		manifestTaskRepo: manifestTaskRepo,
+		settingsService:  settingsService,
+	}
 }
 
-func (s *Noop) ManifestFindAndLockBefore(context.Context, int64, int64, time.Time) (
-	*registrytypes.GCManifestTask, error,
+// Start starts the blob and the manifest garbage collection workers in the background.
+// The workers stop when the provided context is canceled.
+func (s *service) Start(
+	ctx context.Context,
+	spaceStore corestore.SpaceStore,
+	blobRepo store.BlobRepository,
+	storageDeleter storagedriver.StorageDeleter,
+	config *types.Config,
 ) {
-	// NOOP
-	//nolint:nilnil
-	return nil, nil
+	cfg := config.Registry.GarbageCollection
+	if !cfg.Enabled {
+		log.Ctx(ctx).Info().Msg("registry garbage collection is disabled")
+		return
+	}
+
+	s.spaceStore = spaceStore
+	s.blobRepo = blobRepo
+	s.storageClient = storage.NewGcStorageClient(storageDeleter)
+	s.config = gcConfig{
+		noIdleBackoff:       cfg.NoIdleBackoff,
+		maxBackoff:          cfg.MaxBackoffDuration,
+		initialInterval:     cfg.InitialIntervalDuration,
+		transactionTimeout:  cfg.TransactionTimeoutDuration,
+		blobsStorageTimeout: cfg.BlobsStorageTimeoutDuration,
+	}
+
+	s.started.Store(time.Now().UnixMilli())
+
+	go s.runWorker(ctx, "blob", s.processBlobTask)
+	go s.runWorker(ctx, "manifest", s.processManifestTask)
 }
 
-func (s *Noop) ManifestFindAndLockNBefore(context.Context, int64, []int64, time.Time) (
-	[]*registrytypes.GCManifestTask, error,
+// runWorker periodically calls the provided process function until the context is canceled.
+// If there was nothing to process or the processing failed, the interval between the calls
+// is doubled, up to the configured maximum. Nothing is processed while the deletions are paused by a backup.
+func (s *service) runWorker(
+	ctx context.Context,
+	name string,
+	process func(ctx context.Context) (bool, error),
 ) {
-	// NOOP
-	return nil, nil
+	logger := log.Ctx(ctx).With().Str("gc.worker", name).Logger()
+	ctx = logger.WithContext(ctx)
+
+	logger.Info().Msg("registry garbage collection worker started")
+
+	interval := s.config.initialInterval
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info().Msg("registry garbage collection worker stopped")
+			return
+		case <-timer.C:
+		}
+
+		// the backup expects that the blobs referenced by its database snapshot are still in the storage.
+		if err := pause.Check(ctx, s.settingsService); err != nil {
+			if !errors.Is(err, pause.ErrDeletionsPaused) {
+				logger.Warn().Err(err).Msg("registry garbage collection skipped")
+			}
+			timer.Reset(s.config.initialInterval)
+			continue
+		}
+
+		found, err := process(ctx)
+
+		switch {
+		case err != nil:
+			logger.Error().Err(err).Msg("registry garbage collection failed")
+			interval = s.backoff(interval)
+		case found, s.config.noIdleBackoff:
+			interval = s.config.initialInterval
+		default:
+			interval = s.backoff(interval)
+		}
+
+		timer.Reset(interval)
+	}
+}
+
+func (s *service) backoff(interval time.Duration) time.Duration {
+	return min(2*interval, max(s.config.maxBackoff, s.config.initialInterval))
+}
+
+// processBlobTask processes the next blob that is due for review.
+// Dangling blobs are removed from the storage and the database.
+// Returns true if there was a blob to process.
+func (s *service) processBlobTask(ctx context.Context) (bool, error) {
+	var task *registrytypes.GCBlobTask
+	var reclaimed int64
+	var deleted bool
+
+	txCtx, cancel := context.WithTimeout(ctx, s.config.transactionTimeout)
+	defer cancel()
+
+	err := s.tx.WithTx(txCtx, func(ctx context.Context) error {
+		var err error
+		task, err = s.blobTaskRepo.Next(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get next blob review task: %w", err)
+		}
+		if task == nil {
+			return nil
+		}
+
+		dangling, err := s.blobTaskRepo.IsDangling(ctx, task)
+		if err != nil {
+			return fmt.Errorf("failed to check if blob is dangling: %w", err)
+		}
+
+		if dangling {
+			deleted, reclaimed, err = s.deleteBlob(ctx, task.BlobID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err = s.blobTaskRepo.Delete(ctx, task); err != nil {
+			return fmt.Errorf("failed to delete blob review task: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		if task != nil {
+			s.postponeBlobTask(ctx, task)
+		}
+		return false, err
+	}
+
+	if task == nil {
+		return false, nil
+	}
+
+	if deleted {
+		s.deletedBlobs.Add(1)
+		s.lastDeleted.Store(time.Now().UnixMilli())
+	}
+
+	if reclaimed > 0 {
+		total := s.reclaimedBytes.Add(reclaimed)
+		log.Ctx(ctx).Info().
+			Int64("blob_id", task.BlobID).
+			Int64("reclaimed_bytes", reclaimed).
+			Int64("total_reclaimed_bytes", total).
+			Msg("registry garbage collection deleted dangling blob")
+	}
+
+	return true, nil
+}
+
+// deleteBlob deletes the blob from the storage and from the database.
+// Returns whether the blob was deleted and the size of the deleted blob.
+func (s *service) deleteBlob(ctx context.Context, blobID int64) (bool, int64, error) {
+	blob, err := s.blobRepo.FindByID(ctx, blobID)
+	if errors.Is(err, gitnessstore.ErrResourceNotFound) {
+		return false, 0, nil
+	}
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to find blob: %w", err)
+	}
+
+	rootSpace, err := s.spaceStore.Find(ctx, blob.RootParentID)
+	switch {
+	case errors.Is(err, gitnessstore.ErrResourceNotFound):
+		log.Ctx(ctx).Warn().Msgf("root space %d of blob %d not found, skipping removal from storage",
+			blob.RootParentID, blob.ID)
+	case err != nil:
+		return false, 0, fmt.Errorf("failed to find root space of blob: %w", err)
+	default:
+		storageCtx, cancel := context.WithTimeout(ctx, s.config.blobsStorageTimeout)
+		err = s.storageClient.RemoveBlob(storageCtx, blob.Digest, rootSpace.Identifier)
+		cancel()
+
+		var notFoundErr storagedriver.PathNotFoundError
+		if err != nil && !errors.As(err, &notFoundErr) {
+			return false, 0, fmt.Errorf("failed to remove blob from storage: %w", err)
+		}
+	}
+
+	if err = s.blobRepo.DeleteByID(ctx, blob.ID); err != nil {
+		return false, 0, fmt.Errorf("failed to delete blob: %w", err)
+	}
+
+	return true, blob.Size, nil
+}
+
+func (s *service) postponeBlobTask(ctx context.Context, task *registrytypes.GCBlobTask) {
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		return s.blobTaskRepo.Postpone(ctx, task, postponeDelay(task.ReviewCount))
+	})
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to postpone review of blob %d", task.BlobID)
+	}
+}
+
+// processManifestTask processes the next manifest that is due for review.
+// Dangling manifests are deleted from the database, which in turn queues their blobs for review.
+// Returns true if there was a manifest to process.
+func (s *service) processManifestTask(ctx context.Context) (bool, error) {
+	var task *registrytypes.GCManifestTask
+	var deleted bool
+
+	txCtx, cancel := context.WithTimeout(ctx, s.config.transactionTimeout)
+	defer cancel()
+
+	err := s.tx.WithTx(txCtx, func(ctx context.Context) error {
+		var err error
+		task, err = s.manifestTaskRepo.Next(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get next manifest review task: %w", err)
+		}
+		if task == nil {
+			return nil
+		}
+
+		dangling, err := s.manifestTaskRepo.IsDangling(ctx, task)
+		if err != nil {
+			return fmt.Errorf("failed to check if manifest is dangling: %w", err)
+		}
+
+		if dangling {
+			// the review task gets deleted together with the manifest
+			dgst, err := s.manifestTaskRepo.DeleteManifest(ctx, task.RegistryID, task.ManifestID)
+			if err != nil {
+				return fmt.Errorf("failed to delete manifest: %w", err)
+			}
+			deleted = dgst != nil
+		}
+
+		if err = s.manifestTaskRepo.Delete(ctx, task); err != nil {
+			return fmt.Errorf("failed to delete manifest review task: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		if task != nil {
+			s.postponeManifestTask(ctx, task)
+		}
+		return false, err
+	}
+
+	if task == nil {
+		return false, nil
+	}
+
+	if deleted {
+		s.deletedManifests.Add(1)
+		s.lastDeleted.Store(time.Now().UnixMilli())
+
+		log.Ctx(ctx).Info().
+			Int64("registry_id", task.RegistryID).
+			Int64("manifest_id", task.ManifestID).
+			Msg("registry garbage collection deleted dangling manifest")
+	}
+
+	return true, nil
+}
+
+func (s *service) postponeManifestTask(ctx context.Context, task *registrytypes.GCManifestTask) {
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		return s.manifestTaskRepo.Postpone(ctx, task, postponeDelay(task.ReviewCount))
+	})
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to postpone review of manifest %d in registry %d",
+			task.ManifestID, task.RegistryID)
+	}
+}
+
+// Stats returns the statistics of the garbage collection since the server was started.
+func (s *service) Stats() Stats {
+	started := s.started.Load()
+	return Stats{
+		Enabled:          started > 0,
+		Started:          started,
+		ReclaimedBytes:   s.reclaimedBytes.Load(),
+		DeletedBlobs:     s.deletedBlobs.Load(),
+		DeletedManifests: s.deletedManifests.Load(),
+		LastDeleted:      s.lastDeleted.Load(),
+	}
+}
+
+func postponeDelay(reviewCount int) time.Duration {
+	delay := postponeBaseDelay
+	for i := 0; i < reviewCount && delay < postponeMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, postponeMaxDelay)
+}
+
+// BlobFindAndLockBefore locks the review task of the blob if it's due for review before the provided date.
+func (s *service) BlobFindAndLockBefore(
+	ctx context.Context,
+	blobID int64,
+	date time.Time,
+) (*registrytypes.GCBlobTask, error) {
+	return s.blobTaskRepo.FindAndLockBefore(ctx, blobID, date)
+}
+
+// BlobReschedule delays the review of the blob.
+func (s *service) BlobReschedule(ctx context.Context, b *registrytypes.GCBlobTask, d time.Duration) error {
+	return s.blobTaskRepo.Reschedule(ctx, b, d)
+}
+
+// ManifestFindAndLockBefore locks the review task of the manifest if it's due for review before the provided date.
+func (s *service) ManifestFindAndLockBefore(
+	ctx context.Context,
+	registryID, manifestID int64,
+	date time.Time,
+) (*registrytypes.GCManifestTask, error) {
+	return s.manifestTaskRepo.FindAndLockBefore(ctx, registryID, manifestID, date)
+}
+
+// ManifestFindAndLockNBefore locks the review tasks of the manifests that are due for review before the provided date.
+func (s *service) ManifestFindAndLockNBefore(
+	ctx context.Context,
+	registryID int64,
+	manifestIDs []int64,
+	date time.Time,
+) ([]*registrytypes.GCManifestTask, error) {
+	return s.manifestTaskRepo.FindAndLockNBefore(ctx, registryID, manifestIDs, date)
 }
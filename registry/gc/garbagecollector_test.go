@@ -0,0 +1,333 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/services/backup/pause"
+	"github.com/harness/gitness/app/services/settings"
+	corestore "github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/registry/app/storage"
+	"github.com/harness/gitness/registry/app/store"
+	registrytypes "github.com/harness/gitness/registry/types"
+	gitnessstore "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/opencontainers/go-digest"
+)
+
+const testDigest = digest.Digest("sha256:0000000000000000000000000000000000000000000000000000000000000001")
+
+type testTx struct{}
+
+func (testTx) WithTx(ctx context.Context, txFn func(ctx context.Context) error, _ ...interface{}) error {
+	return txFn(ctx)
+}
+
+type testBlobTaskRepo struct {
+	store.GCBlobTaskRepository
+	tasks     []*registrytypes.GCBlobTask
+	dangling  bool
+	postponed []time.Duration
+}
+
+func (r *testBlobTaskRepo) Next(context.Context) (*registrytypes.GCBlobTask, error) {
+	if len(r.tasks) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+	return r.tasks[0], nil
+}
+
+func (r *testBlobTaskRepo) IsDangling(context.Context, *registrytypes.GCBlobTask) (bool, error) {
+	return r.dangling, nil
+}
+
+func (r *testBlobTaskRepo) Delete(context.Context, *registrytypes.GCBlobTask) error {
+	r.tasks = r.tasks[1:]
+	return nil
+}
+
+func (r *testBlobTaskRepo) Postpone(_ context.Context, _ *registrytypes.GCBlobTask, d time.Duration) error {
+	r.postponed = append(r.postponed, d)
+	return nil
+}
+
+type testManifestTaskRepo struct {
+	store.GCManifestTaskRepository
+	tasks    []*registrytypes.GCManifestTask
+	dangling bool
+	deleted  []int64
+}
+
+func (r *testManifestTaskRepo) Next(context.Context) (*registrytypes.GCManifestTask, error) {
+	if len(r.tasks) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+	return r.tasks[0], nil
+}
+
+func (r *testManifestTaskRepo) IsDangling(context.Context, *registrytypes.GCManifestTask) (bool, error) {
+	return r.dangling, nil
+}
+
+func (r *testManifestTaskRepo) DeleteManifest(_ context.Context, _, id int64) (*digest.Digest, error) {
+	r.deleted = append(r.deleted, id)
+	d := testDigest
+	return &d, nil
+}
+
+func (r *testManifestTaskRepo) Delete(context.Context, *registrytypes.GCManifestTask) error {
+	r.tasks = r.tasks[1:]
+	return nil
+}
+
+type testBlobRepo struct {
+	store.BlobRepository
+	blobs map[int64]*registrytypes.Blob
+}
+
+func (r *testBlobRepo) FindByID(_ context.Context, id int64) (*registrytypes.Blob, error) {
+	blob, ok := r.blobs[id]
+	if !ok {
+		return nil, gitnessstore.ErrResourceNotFound
+	}
+	return blob, nil
+}
+
+func (r *testBlobRepo) DeleteByID(_ context.Context, id int64) error {
+	delete(r.blobs, id)
+	return nil
+}
+
+type testSpaceStore struct {
+	corestore.SpaceStore
+}
+
+func (testSpaceStore) Find(_ context.Context, id int64) (*types.Space, error) {
+	return &types.Space{ID: id, Identifier: "root"}, nil
+}
+
+type testStorageDeleter struct {
+	paths []string
+	err   error
+}
+
+func (d *testStorageDeleter) Delete(_ context.Context, path string) error {
+	d.paths = append(d.paths, path)
+	return d.err
+}
+
+type testSettingsStore struct {
+	corestore.SettingsStore
+	values map[string]json.RawMessage
+}
+
+func (s *testSettingsStore) Find(
+	_ context.Context,
+	_ enum.SettingsScope,
+	_ int64,
+	key string,
+) (json.RawMessage, error) {
+	value, ok := s.values[key]
+	if !ok {
+		return nil, gitnessstore.ErrResourceNotFound
+	}
+	return value, nil
+}
+
+func (s *testSettingsStore) Upsert(
+	_ context.Context,
+	_ enum.SettingsScope,
+	_ int64,
+	key string,
+	value json.RawMessage,
+) error {
+	if s.values == nil {
+		s.values = map[string]json.RawMessage{}
+	}
+	s.values[key] = value
+	return nil
+}
+
+func newTestService(
+	blobTasks *testBlobTaskRepo,
+	manifestTasks *testManifestTaskRepo,
+	blobs *testBlobRepo,
+	deleter *testStorageDeleter,
+) *service {
+	s := New(testTx{}, blobTasks, manifestTasks, settings.NewService(&testSettingsStore{})).(*service) //nolint:errcheck
+	s.spaceStore = testSpaceStore{}
+	s.blobRepo = blobs
+	s.storageClient = storage.NewGcStorageClient(deleter)
+	s.config = gcConfig{transactionTimeout: time.Minute, blobsStorageTimeout: time.Minute}
+	return s
+}
+
+func TestProcessBlobTask(t *testing.T) {
+	blobTasks := &testBlobTaskRepo{
+		tasks:    []*registrytypes.GCBlobTask{{BlobID: 1}, {BlobID: 2}},
+		dangling: true,
+	}
+	blobs := &testBlobRepo{blobs: map[int64]*registrytypes.Blob{
+		1: {ID: 1, RootParentID: 10, Digest: testDigest, Size: 100},
+	}}
+	deleter := &testStorageDeleter{}
+	s := newTestService(blobTasks, &testManifestTaskRepo{}, blobs, deleter)
+
+	// the first blob exists and gets deleted from the storage and the database
+	found, err := s.processBlobTask(context.Background())
+	if err != nil || !found {
+		t.Fatalf("expected blob task to be processed, got %t, %v", found, err)
+	}
+	if len(deleter.paths) != 1 || len(blobs.blobs) != 0 {
+		t.Fatalf("expected blob to be deleted, got paths %v and blobs %v", deleter.paths, blobs.blobs)
+	}
+
+	// the second blob is already gone, only the task gets deleted
+	found, err = s.processBlobTask(context.Background())
+	if err != nil || !found {
+		t.Fatalf("expected blob task to be processed, got %t, %v", found, err)
+	}
+
+	// the queue is empty
+	found, err = s.processBlobTask(context.Background())
+	if err != nil || found {
+		t.Fatalf("expected no blob task, got %t, %v", found, err)
+	}
+
+	stats := s.Stats()
+	if stats.ReclaimedBytes != 100 || stats.DeletedBlobs != 1 || stats.LastDeleted == 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestProcessBlobTaskReferenced(t *testing.T) {
+	blobTasks := &testBlobTaskRepo{tasks: []*registrytypes.GCBlobTask{{BlobID: 1}}}
+	blobs := &testBlobRepo{blobs: map[int64]*registrytypes.Blob{1: {ID: 1, Digest: testDigest, Size: 100}}}
+	deleter := &testStorageDeleter{}
+	s := newTestService(blobTasks, &testManifestTaskRepo{}, blobs, deleter)
+
+	found, err := s.processBlobTask(context.Background())
+	if err != nil || !found {
+		t.Fatalf("expected blob task to be processed, got %t, %v", found, err)
+	}
+	if len(deleter.paths) != 0 || len(blobs.blobs) != 1 || len(blobTasks.tasks) != 0 {
+		t.Errorf("expected referenced blob to be kept and its task to be deleted")
+	}
+	if stats := s.Stats(); stats.ReclaimedBytes != 0 || stats.DeletedBlobs != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestProcessBlobTaskStorageFailure(t *testing.T) {
+	blobTasks := &testBlobTaskRepo{
+		tasks:    []*registrytypes.GCBlobTask{{BlobID: 1, ReviewCount: 2}},
+		dangling: true,
+	}
+	blobs := &testBlobRepo{blobs: map[int64]*registrytypes.Blob{1: {ID: 1, Digest: testDigest, Size: 100}}}
+	deleter := &testStorageDeleter{err: errors.New("storage unavailable")}
+	s := newTestService(blobTasks, &testManifestTaskRepo{}, blobs, deleter)
+
+	if _, err := s.processBlobTask(context.Background()); err == nil {
+		t.Fatal("expected storage failure to be returned")
+	}
+
+	if len(blobs.blobs) != 1 {
+		t.Error("expected blob to be kept in the database")
+	}
+	if len(blobTasks.postponed) != 1 || blobTasks.postponed[0] != 4*postponeBaseDelay {
+		t.Errorf("expected task to be postponed with backoff, got %v", blobTasks.postponed)
+	}
+}
+
+func TestProcessManifestTask(t *testing.T) {
+	manifestTasks := &testManifestTaskRepo{
+		tasks:    []*registrytypes.GCManifestTask{{RegistryID: 1, ManifestID: 5}},
+		dangling: true,
+	}
+	s := newTestService(&testBlobTaskRepo{}, manifestTasks, &testBlobRepo{}, &testStorageDeleter{})
+
+	found, err := s.processManifestTask(context.Background())
+	if err != nil || !found {
+		t.Fatalf("expected manifest task to be processed, got %t, %v", found, err)
+	}
+	if len(manifestTasks.deleted) != 1 || manifestTasks.deleted[0] != 5 {
+		t.Errorf("expected manifest 5 to be deleted, got %v", manifestTasks.deleted)
+	}
+	if stats := s.Stats(); stats.DeletedManifests != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestRunWorkerPaused(t *testing.T) {
+	s := newTestService(&testBlobTaskRepo{}, &testManifestTaskRepo{}, &testBlobRepo{}, &testStorageDeleter{})
+	s.config.initialInterval = time.Millisecond
+	s.config.maxBackoff = time.Millisecond
+
+	var calls int
+	process := func(context.Context) (bool, error) {
+		calls++
+		return false, nil
+	}
+
+	run := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		s.runWorker(ctx, "test", process)
+	}
+
+	if err := pause.Pause(context.Background(), s.settingsService, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("failed to pause deletions: %s", err)
+	}
+
+	run()
+
+	if calls != 0 {
+		t.Errorf("expected nothing to be processed while the deletions are paused, got %d calls", calls)
+	}
+
+	if err := pause.Resume(context.Background(), s.settingsService); err != nil {
+		t.Fatalf("failed to resume deletions: %s", err)
+	}
+
+	run()
+
+	if calls == 0 {
+		t.Error("expected the tasks to be processed after the deletions are resumed")
+	}
+}
+
+func TestPostponeDelay(t *testing.T) {
+	tests := []struct {
+		reviewCount int
+		want        time.Duration
+	}{
+		{reviewCount: 0, want: postponeBaseDelay},
+		{reviewCount: 1, want: 2 * postponeBaseDelay},
+		{reviewCount: 3, want: 8 * postponeBaseDelay},
+		{reviewCount: 100, want: postponeMaxDelay},
+	}
+	for _, test := range tests {
+		if got := postponeDelay(test.reviewCount); got != test.want {
+			t.Errorf("postponeDelay(%d) = %s, want %s", test.reviewCount, got, test.want)
+		}
+	}
+}
@@ -41,4 +41,15 @@ type Service interface {
 		ctx context.Context, registryID int64, manifestIDs []int64,
 		date time.Time,
 	) ([]*registrytypes.GCManifestTask, error)
+	Stats() Stats
+}
+
+// Stats holds the statistics of the garbage collection since the server was started.
+type Stats struct {
+	Enabled          bool  `json:"enabled"`
+	Started          int64 `json:"started,omitempty"`
+	ReclaimedBytes   int64 `json:"reclaimed_bytes"`
+	DeletedBlobs     int64 `json:"deleted_blobs"`
+	DeletedManifests int64 `json:"deleted_manifests"`
+	LastDeleted      int64 `json:"last_deleted,omitempty"`
 }
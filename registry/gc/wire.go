@@ -15,7 +15,10 @@
 package gc
 
 import (
+	"github.com/harness/gitness/app/services/settings"
 	storagedriver "github.com/harness/gitness/registry/app/driver"
+	"github.com/harness/gitness/registry/app/store"
+	"github.com/harness/gitness/store/database/dbtx"
 
 	"github.com/google/wire"
 )
@@ -24,8 +27,13 @@ func StorageDeleterProvider(driver storagedriver.StorageDriver) storagedriver.St
 	return driver
 }
 
-func ServiceProvider() Service {
-	return New()
+func ServiceProvider(
+	tx dbtx.Transactor,
+	blobTaskRepo store.GCBlobTaskRepository,
+	manifestTaskRepo store.GCManifestTaskRepository,
+	settingsService *settings.Service,
+) Service {
+	return New(tx, blobTaskRepo, manifestTaskRepo, settingsService)
 }
 
 var WireSet = wire.NewSet(StorageDeleterProvider, ServiceProvider)
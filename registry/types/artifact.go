@@ -28,3 +28,9 @@ type Artifact struct {
 	CreatedBy int64
 	UpdatedBy int64
 }
+
+// ImageArtifact is an artifact together with the name of the image it belongs to.
+type ImageArtifact struct {
+	Artifact
+	ImageName string
+}
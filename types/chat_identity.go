@@ -0,0 +1,36 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "github.com/harness/gitness/types/enum"
+
+// UserChatIdentity links a user to their account on a chat service,
+// so chat notifications can mention or directly message the user.
+type UserChatIdentity struct {
+	PrincipalID int64             `json:"-"`
+	Provider    enum.ChatProvider `json:"provider"`
+	// ExternalID identifies the user on the chat service, e.g. the Slack member ID
+	// or the Microsoft Teams user principal name.
+	ExternalID string `json:"external_id"`
+	// Verified is set once the user proved ownership of the external ID, or an admin linked it.
+	// Only verified identities receive notifications.
+	Verified bool  `json:"verified"`
+	Created  int64 `json:"created"`
+	Updated  int64 `json:"updated"`
+
+	ConfirmationHash     string `json:"-"`
+	ConfirmationExpires  int64  `json:"-"`
+	ConfirmationAttempts int    `json:"-"`
+}
@@ -15,12 +15,18 @@
 package check
 
 import (
+	"slices"
 	"time"
+
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
 )
 
 const (
 	minTokenLifeTime = 24 * time.Hour       // 1 day
 	maxTokenLifeTime = 365 * 24 * time.Hour // 1 year
+
+	maxTokenScopeResources = 50
 )
 
 var (
@@ -30,6 +36,12 @@ var (
 	ErrTokenLifeTimeRequired = &ValidationError{
 		"The life time of a token is required.",
 	}
+	ErrTokenScopeEmpty = &ValidationError{
+		"The scope of a token has to restrict either the permissions, the spaces or the repositories.",
+	}
+	ErrTokenScopeTooManyResources = &ValidationError{
+		"The scope of a token can contain at most 50 spaces and repositories.",
+	}
 )
 
 // TokenLifetime returns true if the lifetime is valid for a token.
@@ -48,3 +60,37 @@ func TokenLifetime(lifetime *time.Duration, optional bool) error {
 
 	return nil
 }
+
+// TokenScope returns nil if the scope is valid for a token.
+func TokenScope(scope *types.TokenScopeInput) error {
+	if scope == nil {
+		return nil
+	}
+
+	if len(scope.Permissions) == 0 && len(scope.Spaces) == 0 && len(scope.Repos) == 0 {
+		return ErrTokenScopeEmpty
+	}
+
+	if len(scope.Spaces)+len(scope.Repos) > maxTokenScopeResources {
+		return ErrTokenScopeTooManyResources
+	}
+
+	for _, permission := range scope.Permissions {
+		if !isTokenScopePermission(permission) {
+			return NewValidationErrorf("Permission %q can't be granted to a token.", permission)
+		}
+	}
+
+	return nil
+}
+
+// isTokenScopePermission returns true if the permission can be granted to a scoped token.
+// These are the permissions of resources in spaces and the permissions of the user on itself.
+func isTokenScopePermission(permission enum.Permission) bool {
+	if permission == enum.PermissionUserView || permission == enum.PermissionUserEdit {
+		return true
+	}
+
+	_, ok := slices.BinarySearch(enum.MembershipRoleSpaceOwner.Permissions(), permission)
+	return ok
+}
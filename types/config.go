@@ -173,6 +173,13 @@ type Config struct {
 		// In that case, GITNESS_URL_CONTAINER should also be changed
 		// (eg to http://<gitness_container_name>:<port>).
 		ContainerNetworks []string `envconfig:"GITNESS_CI_CONTAINER_NETWORKS"`
+
+		// RemoteRunnerPollTimeout is how long the requests of remote runners for stages and
+		// for cancellations are held open before the runners have to poll again.
+		RemoteRunnerPollTimeout time.Duration `envconfig:"GITNESS_CI_REMOTE_RUNNER_POLL_TIMEOUT" default:"30s"`
+
+		// RemoteRunnerOfflineAfter is the time without contact after which a remote runner is offline.
+		RemoteRunnerOfflineAfter time.Duration `envconfig:"GITNESS_CI_REMOTE_RUNNER_OFFLINE_AFTER" default:"2m"`
 	}
 
 	// Database defines the database configuration parameters.
@@ -204,6 +211,94 @@ type Config struct {
 		Expire     time.Duration `envconfig:"GITNESS_TOKEN_EXPIRE" default:"720h"`
 	}
 
+	// AccountMail defines the configuration of the self-service account flows that send links by email.
+	AccountMail struct {
+		// PasswordResetEnabled allows users to reset their password using a link sent to their email address.
+		PasswordResetEnabled      bool          `envconfig:"GITNESS_ACCOUNT_MAIL_PASSWORD_RESET_ENABLED" default:"false"`
+		PasswordResetLinkLifetime time.Duration `envconfig:"GITNESS_ACCOUNT_MAIL_PASSWORD_RESET_LINK_LIFETIME" default:"1h"`
+
+		// EmailVerificationRequired requires new users to verify their email address before they can log in.
+		EmailVerificationRequired     bool          `envconfig:"GITNESS_ACCOUNT_MAIL_EMAIL_VERIFICATION_REQUIRED" default:"false"`
+		EmailVerificationLinkLifetime time.Duration `envconfig:"GITNESS_ACCOUNT_MAIL_EMAIL_VERIFICATION_LINK_LIFETIME" default:"24h"`
+
+		// RateLimit is the maximum number of mails of each type sent to a user within RateLimitWindow.
+		RateLimit       int           `envconfig:"GITNESS_ACCOUNT_MAIL_RATE_LIMIT" default:"3"`
+		RateLimitWindow time.Duration `envconfig:"GITNESS_ACCOUNT_MAIL_RATE_LIMIT_WINDOW" default:"1h"`
+	}
+
+	// OIDC defines the configuration of the OpenID Connect single sign-on.
+	OIDC struct {
+		Enabled bool `envconfig:"GITNESS_OIDC_ENABLED" default:"false"`
+
+		// ProviderName is the name of the identity provider shown on the login page.
+		ProviderName string   `envconfig:"GITNESS_OIDC_PROVIDER_NAME" default:"SSO"`
+		Issuer       string   `envconfig:"GITNESS_OIDC_ISSUER"`
+		ClientID     string   `envconfig:"GITNESS_OIDC_CLIENT_ID"`
+		ClientSecret string   `envconfig:"GITNESS_OIDC_CLIENT_SECRET"`
+		Scopes       []string `envconfig:"GITNESS_OIDC_SCOPES" default:"openid,email,profile"`
+
+		// RedirectURL is the callback URL registered with the identity provider.
+		// Value is derived from URL.API unless explicitly specified (e.g. http://localhost:3000/api/v1/login/oidc/callback).
+		RedirectURL string `envconfig:"GITNESS_OIDC_REDIRECT_URL"`
+
+		// AutoProvision creates a new user on the first sign-in if the identity doesn't match an existing user.
+		AutoProvision bool `envconfig:"GITNESS_OIDC_AUTO_PROVISION" default:"true"`
+
+		// MatchVerifiedEmail links an identity to the existing user with the same email, if the email is verified.
+		MatchVerifiedEmail bool `envconfig:"GITNESS_OIDC_MATCH_VERIFIED_EMAIL" default:"false"`
+
+		// GroupsClaim is the name of the ID token claim that holds the groups of the user.
+		GroupsClaim string `envconfig:"GITNESS_OIDC_GROUPS_CLAIM" default:"groups"`
+
+		// SpaceRoleMappings grant space roles to members of groups, in the format "<group>=<space path>:<role>".
+		SpaceRoleMappings []string `envconfig:"GITNESS_OIDC_SPACE_ROLE_MAPPINGS"`
+
+		// UserGroupMappings add members of groups to usergroups,
+		// in the format "<group>=<space path>:<usergroup identifier>".
+		UserGroupMappings []string `envconfig:"GITNESS_OIDC_USERGROUP_MAPPINGS"`
+
+		// PasswordLoginDisabled disables sign-in and sign-up with local passwords.
+		PasswordLoginDisabled bool `envconfig:"GITNESS_OIDC_PASSWORD_LOGIN_DISABLED" default:"false"`
+	}
+
+	// LDAP defines the configuration of the LDAP (or Active Directory) authentication and group sync.
+	LDAP struct {
+		Enabled bool `envconfig:"GITNESS_LDAP_ENABLED" default:"false"`
+
+		// URL of the directory server, e.g. ldap://localhost:389 or ldaps://ldap.example.com:636.
+		URL                string        `envconfig:"GITNESS_LDAP_URL"`
+		StartTLS           bool          `envconfig:"GITNESS_LDAP_START_TLS" default:"false"`
+		InsecureSkipVerify bool          `envconfig:"GITNESS_LDAP_INSECURE_SKIP_VERIFY" default:"false"`
+		Timeout            time.Duration `envconfig:"GITNESS_LDAP_TIMEOUT" default:"10s"`
+
+		// BindDN and BindPassword are the credentials used to search the directory.
+		BindDN       string `envconfig:"GITNESS_LDAP_BIND_DN"`
+		BindPassword string `envconfig:"GITNESS_LDAP_BIND_PASSWORD"`
+
+		// UserFilter is used to find the user that signs in, %s is replaced with the escaped login identifier.
+		// It's also used with the %s replaced with * to list all users during the group sync.
+		UserBaseDN         string `envconfig:"GITNESS_LDAP_USER_BASE_DN"`
+		UserFilter         string `envconfig:"GITNESS_LDAP_USER_FILTER" default:"(&(objectClass=person)(uid=%s))"`
+		UserAttributeUID   string `envconfig:"GITNESS_LDAP_USER_ATTRIBUTE_UID" default:"uid"`
+		UserAttributeEmail string `envconfig:"GITNESS_LDAP_USER_ATTRIBUTE_EMAIL" default:"mail"`
+		UserAttributeName  string `envconfig:"GITNESS_LDAP_USER_ATTRIBUTE_NAME" default:"cn"`
+
+		// AutoProvision creates a new user on the first sign-in if the directory user doesn't match an existing user.
+		AutoProvision bool `envconfig:"GITNESS_LDAP_AUTO_PROVISION" default:"true"`
+
+		// GroupSpace is the path of the space in which the directory groups are synced as usergroups.
+		// Group sync is disabled if it's empty.
+		GroupSpace string `envconfig:"GITNESS_LDAP_GROUP_SPACE"`
+
+		// GroupAttributeMember holds the members of a group, either as DNs (member) or as UIDs (memberUid).
+		GroupBaseDN               string `envconfig:"GITNESS_LDAP_GROUP_BASE_DN"`
+		GroupFilter               string `envconfig:"GITNESS_LDAP_GROUP_FILTER" default:"(objectClass=groupOfNames)"`
+		GroupAttributeIdentifier  string `envconfig:"GITNESS_LDAP_GROUP_ATTRIBUTE_IDENTIFIER" default:"cn"`
+		GroupAttributeDescription string `envconfig:"GITNESS_LDAP_GROUP_ATTRIBUTE_DESCRIPTION" default:"description"`
+		GroupAttributeMember      string `envconfig:"GITNESS_LDAP_GROUP_ATTRIBUTE_MEMBER" default:"member"`
+		GroupSyncCron             string `envconfig:"GITNESS_LDAP_GROUP_SYNC_CRON" default:"*/30 * * * *"`
+	}
+
 	Logs struct {
 		// S3 provides optional storage option for logs.
 		S3 struct {
@@ -341,6 +436,28 @@ type Config struct {
 		RetentionTime time.Duration `envconfig:"GITNESS_WEBHOOK_RETENTION_TIME" default:"168h"` // 7 days
 	}
 
+	Mirror struct {
+		// AllowPrivateNetwork allows mirroring from and to hosts that resolve to private or link-local addresses.
+		AllowPrivateNetwork bool `envconfig:"GITNESS_MIRROR_ALLOW_PRIVATE_NETWORK" default:"false"`
+		// AllowLoopback allows mirroring from and to hosts that resolve to loopback addresses.
+		AllowLoopback bool `envconfig:"GITNESS_MIRROR_ALLOW_LOOPBACK" default:"false"`
+	}
+
+	Audit struct {
+		// RetentionTime is the duration after which audit events will be purged from the DB.
+		RetentionTime time.Duration `envconfig:"GITNESS_AUDIT_RETENTION_TIME" default:"8760h"` // 365 days
+	}
+
+	// SpaceArchive holds the limits of the space archives imported through the API.
+	SpaceArchive struct {
+		// MaxSize is the max size (in bytes) of an uploaded space archive.
+		MaxSize int64 `envconfig:"GITNESS_SPACE_ARCHIVE_MAX_SIZE" default:"10737418240"` // 10 GiB
+		// MaxExtractedSize is the max total size (in bytes) of the extracted files of a space archive.
+		MaxExtractedSize int64 `envconfig:"GITNESS_SPACE_ARCHIVE_MAX_EXTRACTED_SIZE" default:"21474836480"` // 20 GiB
+		// MaxEntries is the max number of files in a space archive.
+		MaxEntries int `envconfig:"GITNESS_SPACE_ARCHIVE_MAX_ENTRIES" default:"100000"`
+	}
+
 	Trigger struct {
 		Concurrency int `envconfig:"GITNESS_TRIGGER_CONCURRENCY" default:"4"`
 		MaxRetries  int `envconfig:"GITNESS_TRIGGER_MAX_RETRIES" default:"3"`
@@ -375,11 +492,41 @@ type Config struct {
 	Notification struct {
 		MaxRetries  int `envconfig:"GITNESS_NOTIFICATION_MAX_RETRIES" default:"3"`
 		Concurrency int `envconfig:"GITNESS_NOTIFICATION_CONCURRENCY" default:"4"`
+
+		// Chat notifications are delivered in addition to mails, to the recipients that verified
+		// their identity on the chat service. The webhooks post to a channel shared by all recipients,
+		// so they only receive the notifications of public repositories.
+		Slack struct {
+			// WebhookURL is the Slack incoming webhook the notifications are posted to, mentioning the recipients.
+			WebhookURL string `envconfig:"GITNESS_NOTIFICATION_SLACK_WEBHOOK_URL"`
+			// BotToken is the token of the Slack bot that sends the notifications as direct messages.
+			// It's required for users to confirm their Slack identity themselves.
+			BotToken string `envconfig:"GITNESS_NOTIFICATION_SLACK_BOT_TOKEN"`
+		}
+
+		Teams struct {
+			// WebhookURL is the Microsoft Teams incoming webhook the notifications are posted to.
+			WebhookURL string `envconfig:"GITNESS_NOTIFICATION_TEAMS_WEBHOOK_URL"`
+		}
+
+		ChatWebhook struct {
+			// URL is the generic chat webhook the notifications are posted to.
+			URL string `envconfig:"GITNESS_NOTIFICATION_CHAT_WEBHOOK_URL"`
+			// Template is the Go template of the request body. It defaults to a JSON object with a text field.
+			Template    string `envconfig:"GITNESS_NOTIFICATION_CHAT_WEBHOOK_TEMPLATE"`
+			ContentType string `envconfig:"GITNESS_NOTIFICATION_CHAT_WEBHOOK_CONTENT_TYPE" default:"application/json"`
+		}
 	}
 
 	KeywordSearch struct {
 		Concurrency int `envconfig:"GITNESS_KEYWORD_SEARCH_CONCURRENCY" default:"4"`
 		MaxRetries  int `envconfig:"GITNESS_KEYWORD_SEARCH_MAX_RETRIES" default:"3"`
+
+		// IndexRoot is the directory of the local search indexes. Defaults to a subdirectory of the git root.
+		IndexRoot string `envconfig:"GITNESS_KEYWORD_SEARCH_INDEX_ROOT"`
+
+		// IndexCacheSize is the max total size (in bytes) of the repository indexes kept in memory for searching.
+		IndexCacheSize int64 `envconfig:"GITNESS_KEYWORD_SEARCH_INDEX_CACHE_SIZE" default:"268435456"` // 256 MiB
 	}
 
 	Repos struct {
@@ -496,6 +643,11 @@ type Config struct {
 			TransactionTimeoutDuration  time.Duration `envconfig:"GITNESS_REGISTRY_GARBAGE_COLLECTION_TRANSACTION_TIMEOUT_DURATION" default:"10s"` //nolint:lll
 			BlobsStorageTimeoutDuration time.Duration `envconfig:"GITNESS_REGISTRY_GARBAGE_COLLECTION_BLOB_STORAGE_TIMEOUT_DURATION" default:"5s"` //nolint:lll
 		}
+
+		CleanupPolicy struct {
+			// DryRun makes the cleanup policy job only report the versions that would be deleted.
+			DryRun bool `envconfig:"GITNESS_REGISTRY_CLEANUP_POLICY_DRY_RUN" default:"false"`
+		}
 	}
 
 	Instrumentation struct {
@@ -114,3 +114,32 @@ func ParseGitServiceType(s string) (GitServiceType, error) {
 		return "", fmt.Errorf("unknown git service type provided: %q", s)
 	}
 }
+
+// GitSignatureStatus defines the verification status of the signature of a git object (commit or tag).
+type GitSignatureStatus string
+
+func (GitSignatureStatus) Enum() []interface{} { return toInterfaceSlice(gitSignatureStatuses) }
+func (s GitSignatureStatus) Sanitize() (GitSignatureStatus, bool) {
+	return Sanitize(s, GetAllGitSignatureStatuses)
+}
+func GetAllGitSignatureStatuses() ([]GitSignatureStatus, GitSignatureStatus) {
+	return gitSignatureStatuses, ""
+}
+
+// GitSignatureStatus enumeration.
+const (
+	// GitSignatureStatusVerified means the signature is valid and was created with
+	// a signing key registered by the user the object is attributed to.
+	GitSignatureStatusVerified GitSignatureStatus = "verified"
+	// GitSignatureStatusUnverified means the signature is invalid, or it doesn't belong to
+	// the user the object is attributed to.
+	GitSignatureStatusUnverified GitSignatureStatus = "unverified"
+	// GitSignatureStatusUnknownKey means the signature was created with a key that isn't registered.
+	GitSignatureStatusUnknownKey GitSignatureStatus = "unknown_key"
+)
+
+var gitSignatureStatuses = sortEnum([]GitSignatureStatus{
+	GitSignatureStatusVerified,
+	GitSignatureStatusUnverified,
+	GitSignatureStatusUnknownKey,
+})
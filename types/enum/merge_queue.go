@@ -0,0 +1,53 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enum
+
+// MergeQueueEntryState defines the state of a pull request in the merge queue.
+type MergeQueueEntryState string
+
+func (MergeQueueEntryState) Enum() []interface{} { return toInterfaceSlice(mergeQueueEntryStates) }
+
+const (
+	// MergeQueueEntryStateQueued describes an entry whose speculative merge commit isn't created yet.
+	MergeQueueEntryStateQueued MergeQueueEntryState = "queued"
+
+	// MergeQueueEntryStateTesting describes an entry whose speculative merge commit
+	// is waiting for the required status checks.
+	MergeQueueEntryStateTesting MergeQueueEntryState = "testing"
+)
+
+var mergeQueueEntryStates = sortEnum([]MergeQueueEntryState{
+	MergeQueueEntryStateQueued,
+	MergeQueueEntryStateTesting,
+})
+
+// MergeQueueRemoveReason defines why a pull request got removed from the merge queue.
+type MergeQueueRemoveReason string
+
+func (MergeQueueRemoveReason) Enum() []interface{} { return toInterfaceSlice(mergeQueueRemoveReasons) }
+
+const (
+	// MergeQueueRemoveReasonCancelled is used when a user removed the pull request from the merge queue.
+	MergeQueueRemoveReasonCancelled MergeQueueRemoveReason = "cancelled"
+
+	// MergeQueueRemoveReasonFailed is used when the pull request got ejected from the merge queue,
+	// e.g. because of failed status checks or merge conflicts.
+	MergeQueueRemoveReasonFailed MergeQueueRemoveReason = "failed"
+)
+
+var mergeQueueRemoveReasons = sortEnum([]MergeQueueRemoveReason{
+	MergeQueueRemoveReasonCancelled,
+	MergeQueueRemoveReasonFailed,
+})
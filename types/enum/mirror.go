@@ -0,0 +1,69 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enum
+
+// MirrorDirection defines the direction of a repository mirror.
+type MirrorDirection string
+
+func (MirrorDirection) Enum() []interface{} { return toInterfaceSlice(mirrorDirections) }
+func (d MirrorDirection) Sanitize() (MirrorDirection, bool) {
+	return Sanitize(d, GetAllMirrorDirections)
+}
+func GetAllMirrorDirections() ([]MirrorDirection, MirrorDirection) {
+	return mirrorDirections, ""
+}
+
+const (
+	// MirrorDirectionPull describes a mirror that periodically fetches from the remote repository.
+	MirrorDirectionPull MirrorDirection = "pull"
+
+	// MirrorDirectionPush describes a mirror that pushes to the remote repository after every change.
+	MirrorDirectionPush MirrorDirection = "push"
+)
+
+var mirrorDirections = sortEnum([]MirrorDirection{
+	MirrorDirectionPull,
+	MirrorDirectionPush,
+})
+
+// MirrorSyncState defines the state of the latest synchronization of a repository mirror.
+type MirrorSyncState string
+
+func (MirrorSyncState) Enum() []interface{} { return toInterfaceSlice(mirrorSyncStates) }
+
+const (
+	// MirrorSyncStateIdle describes a mirror that hasn't been synchronized yet.
+	MirrorSyncStateIdle MirrorSyncState = "idle"
+
+	// MirrorSyncStateScheduled describes a mirror with a pending synchronization job.
+	MirrorSyncStateScheduled MirrorSyncState = "scheduled"
+
+	// MirrorSyncStateRunning describes a mirror that is being synchronized.
+	MirrorSyncStateRunning MirrorSyncState = "running"
+
+	// MirrorSyncStateSuccess describes a mirror whose latest synchronization succeeded.
+	MirrorSyncStateSuccess MirrorSyncState = "success"
+
+	// MirrorSyncStateFailed describes a mirror whose latest synchronization failed.
+	MirrorSyncStateFailed MirrorSyncState = "failed"
+)
+
+var mirrorSyncStates = sortEnum([]MirrorSyncState{
+	MirrorSyncStateIdle,
+	MirrorSyncStateScheduled,
+	MirrorSyncStateRunning,
+	MirrorSyncStateSuccess,
+	MirrorSyncStateFailed,
+})
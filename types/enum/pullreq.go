@@ -90,6 +90,12 @@ const (
 	PullReqActivityTypeBranchRestore  PullReqActivityType = "branch-restore"
 	PullReqActivityTypeMerge          PullReqActivityType = "merge"
 	PullReqActivityTypeLabelModify    PullReqActivityType = "label-modify"
+
+	PullReqActivityTypeAutoMergeEnable  PullReqActivityType = "auto-merge-enable"
+	PullReqActivityTypeAutoMergeDisable PullReqActivityType = "auto-merge-disable"
+
+	PullReqActivityTypeMergeQueueAdd    PullReqActivityType = "merge-queue-add"
+	PullReqActivityTypeMergeQueueRemove PullReqActivityType = "merge-queue-remove"
 )
 
 var pullReqActivityTypes = sortEnum([]PullReqActivityType{
@@ -105,6 +111,31 @@ var pullReqActivityTypes = sortEnum([]PullReqActivityType{
 	PullReqActivityTypeBranchRestore,
 	PullReqActivityTypeMerge,
 	PullReqActivityTypeLabelModify,
+	PullReqActivityTypeAutoMergeEnable,
+	PullReqActivityTypeAutoMergeDisable,
+	PullReqActivityTypeMergeQueueAdd,
+	PullReqActivityTypeMergeQueueRemove,
+})
+
+// PullReqAutoMergeDisableReason defines why auto-merge of a pull request got disabled.
+type PullReqAutoMergeDisableReason string
+
+func (PullReqAutoMergeDisableReason) Enum() []interface{} {
+	return toInterfaceSlice(pullReqAutoMergeDisableReasons)
+}
+
+// PullReqAutoMergeDisableReason enumeration.
+const (
+	// PullReqAutoMergeDisableReasonCancelled is used when a user disabled auto-merge.
+	PullReqAutoMergeDisableReasonCancelled PullReqAutoMergeDisableReason = "cancelled"
+
+	// PullReqAutoMergeDisableReasonFailed is used when the automatic merge of the pull request failed.
+	PullReqAutoMergeDisableReasonFailed PullReqAutoMergeDisableReason = "failed"
+)
+
+var pullReqAutoMergeDisableReasons = sortEnum([]PullReqAutoMergeDisableReason{
+	PullReqAutoMergeDisableReasonCancelled,
+	PullReqAutoMergeDisableReasonFailed,
 })
 
 // PullReqActivityKind defines kind of pull request activity system message.
@@ -40,6 +40,8 @@ const (
 	TriggerActionPullReqClosed TriggerAction = "pullreq_closed"
 	// TriggerActionPullReqMerged gets triggered when a pull request is merged.
 	TriggerActionPullReqMerged TriggerAction = "pullreq_merged"
+	// TriggerActionPullReqMergeQueue gets triggered when a merge queue commit gets created for a pull request.
+	TriggerActionPullReqMergeQueue TriggerAction = "pullreq_merge_queue"
 )
 
 func (TriggerAction) Enum() []interface{}               { return toInterfaceSlice(triggerActions) }
@@ -49,7 +51,8 @@ func (t TriggerAction) GetTriggerEvent() TriggerEvent {
 		t == TriggerActionPullReqBranchUpdated ||
 		t == TriggerActionPullReqReopened ||
 		t == TriggerActionPullReqClosed ||
-		t == TriggerActionPullReqMerged {
+		t == TriggerActionPullReqMerged ||
+		t == TriggerActionPullReqMergeQueue {
 		return TriggerEventPullRequest
 	}
 	if t == TriggerActionTagCreated || t == TriggerActionTagUpdated {
@@ -75,6 +78,7 @@ var triggerActions = sortEnum([]TriggerAction{
 	TriggerActionPullReqBranchUpdated,
 	TriggerActionPullReqClosed,
 	TriggerActionPullReqMerged,
+	TriggerActionPullReqMergeQueue,
 })
 
 // Trigger types.
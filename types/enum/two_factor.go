@@ -0,0 +1,44 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enum
+
+// TwoFactorRequirement defines which users are required to use two-factor authentication.
+type TwoFactorRequirement string
+
+func (TwoFactorRequirement) Enum() []interface{} { return toInterfaceSlice(twoFactorRequirements) }
+func (r TwoFactorRequirement) Sanitize() (TwoFactorRequirement, bool) {
+	return Sanitize(r, GetAllTwoFactorRequirements)
+}
+func GetAllTwoFactorRequirements() ([]TwoFactorRequirement, TwoFactorRequirement) {
+	return twoFactorRequirements, TwoFactorRequirementNone
+}
+
+const (
+	// TwoFactorRequirementNone leaves two-factor authentication optional for all users.
+	TwoFactorRequirementNone TwoFactorRequirement = "none"
+
+	// TwoFactorRequirementAll requires two-factor authentication from all users.
+	TwoFactorRequirementAll TwoFactorRequirement = "all"
+
+	// TwoFactorRequirementSpaceOwners requires two-factor authentication from admins
+	// and from users that are owners of at least one space.
+	TwoFactorRequirementSpaceOwners TwoFactorRequirement = "space_owners"
+)
+
+var twoFactorRequirements = sortEnum([]TwoFactorRequirement{
+	TwoFactorRequirementNone,
+	TwoFactorRequirementAll,
+	TwoFactorRequirementSpaceOwners,
+})
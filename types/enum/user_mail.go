@@ -0,0 +1,26 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enum
+
+// UserMailType defines the type of an account mail sent to a user.
+type UserMailType string
+
+const (
+	// UserMailTypePasswordReset is the mail with the link to reset the password of the user.
+	UserMailTypePasswordReset UserMailType = "password_reset"
+
+	// UserMailTypeEmailVerification is the mail with the link to verify the email address of the user.
+	UserMailTypeEmailVerification UserMailType = "email_verification"
+)
@@ -0,0 +1,30 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enum
+
+// UserGroupSource defines what manages a usergroup or a usergroup membership.
+// The directory syncs only change the usergroups and memberships they manage themselves.
+type UserGroupSource string
+
+const (
+	// UserGroupSourceManual is the source of usergroups and memberships managed through the API.
+	UserGroupSourceManual UserGroupSource = "manual"
+
+	// UserGroupSourceLDAP is the source of usergroups and memberships managed by the LDAP group sync.
+	UserGroupSourceLDAP UserGroupSource = "ldap"
+
+	// UserGroupSourceOIDC is the source of memberships managed by the OIDC usergroup mappings.
+	UserGroupSourceOIDC UserGroupSource = "oidc"
+)
@@ -190,6 +190,8 @@ const (
 	WebhookTriggerPullReqLabelAssigned WebhookTrigger = "pullreq_label_assigned"
 	// WebhookTriggerPullReqReviewSubmitted gets triggered when a pull request review is submitted.
 	WebhookTriggerPullReqReviewSubmitted = "pullreq_review_submitted"
+	// WebhookTriggerPullReqMergeQueue gets triggered when a merge queue commit gets created for a pull request.
+	WebhookTriggerPullReqMergeQueue WebhookTrigger = "pullreq_merge_queue"
 )
 
 var webhookTriggers = sortEnum([]WebhookTrigger{
@@ -210,4 +212,5 @@ var webhookTriggers = sortEnum([]WebhookTrigger{
 	WebhookTriggerPullReqMerged,
 	WebhookTriggerPullReqLabelAssigned,
 	WebhookTriggerPullReqReviewSubmitted,
+	WebhookTriggerPullReqMergeQueue,
 })
@@ -89,13 +89,22 @@ type CommitStats struct {
 }
 
 type Commit struct {
-	SHA        string       `json:"sha"`
-	ParentSHAs []string     `json:"parent_shas,omitempty"`
-	Title      string       `json:"title"`
-	Message    string       `json:"message"`
-	Author     Signature    `json:"author"`
-	Committer  Signature    `json:"committer"`
-	Stats      *CommitStats `json:"stats,omitempty"`
+	SHA        string        `json:"sha"`
+	ParentSHAs []string      `json:"parent_shas,omitempty"`
+	Title      string        `json:"title"`
+	Message    string        `json:"message"`
+	Author     Signature     `json:"author"`
+	Committer  Signature     `json:"committer"`
+	Stats      *CommitStats  `json:"stats,omitempty"`
+	Signature  *GitSignature `json:"signature,omitempty"`
+}
+
+// GitSignature contains the verification result of the signature of a git object (commit or tag).
+type GitSignature struct {
+	Status         enum.GitSignatureStatus `json:"status"`
+	KeyType        string                  `json:"key_type,omitempty"`
+	KeyFingerprint string                  `json:"key_fingerprint,omitempty"`
+	Signer         *PrincipalInfo          `json:"signer,omitempty"`
 }
 
 type Signature struct {
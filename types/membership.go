@@ -61,4 +61,5 @@ type MembershipSpaceFilter struct {
 	ListQueryFilter
 	Sort  enum.MembershipSpaceSort `json:"sort"`
 	Order enum.Order               `json:"order"`
+	Role  enum.MembershipRole      `json:"role"`
 }
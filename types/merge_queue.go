@@ -0,0 +1,52 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"github.com/harness/gitness/types/enum"
+)
+
+// MergeQueueEntry represents a pull request in the merge queue of its target branch.
+// The pull requests in a queue are speculatively merged on top of each other and the
+// required status checks run against the resulting merge commits.
+type MergeQueueEntry struct {
+	ID            int64                     `json:"id"`
+	Version       int64                     `json:"-"`
+	RepoID        int64                     `json:"repo_id"`
+	Branch        string                    `json:"branch"`
+	PullReqID     int64                     `json:"-"`
+	PullReqNumber int64                     `json:"pullreq_number"`
+	SourceSHA     string                    `json:"source_sha"`
+	Method        enum.MergeMethod          `json:"method"`
+	Title         string                    `json:"title,omitempty"`
+	Message       string                    `json:"message,omitempty"`
+	State         enum.MergeQueueEntryState `json:"state"`
+
+	// BaseSHA is the commit the speculative merge commit is built on top of. It's either the head
+	// of the target branch or the speculative merge commit of the preceding entry in the queue.
+	BaseSHA string `json:"base_sha,omitempty"`
+	// MergeSHA is the speculative merge commit the required status checks run against.
+	MergeSHA string `json:"merge_sha,omitempty"`
+
+	CreatedBy int64 `json:"created_by"`
+	Created   int64 `json:"created"`
+	Updated   int64 `json:"updated"`
+}
+
+// MergeQueueBranch identifies the merge queue of a branch.
+type MergeQueueBranch struct {
+	RepoID int64
+	Branch string
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"github.com/harness/gitness/types/enum"
+)
+
+// Mirror represents a pull or push mirror of a repository.
+type Mirror struct {
+	ID         int64                `json:"-"`
+	Version    int64                `json:"-"`
+	RepoID     int64                `json:"repo_id"`
+	Identifier string               `json:"identifier"`
+	Direction  enum.MirrorDirection `json:"direction"`
+	RemoteURL  string               `json:"remote_url"`
+
+	// Username is used together with the secret to authenticate with the remote repository.
+	Username string `json:"username"`
+	// SecretIdentifier is the identifier of a secret in the parent space of the repository
+	// holding the password or the access token for the remote repository.
+	SecretIdentifier string `json:"secret_identifier"`
+	// ConnectorIdentifier is the identifier of a connector in the parent space of the repository
+	// whose credentials are used for the remote repository. It takes precedence over the secret.
+	ConnectorIdentifier string `json:"connector_identifier"`
+
+	// Interval is the number of seconds between two synchronizations of a pull mirror.
+	Interval int64 `json:"interval"`
+	Enabled  bool  `json:"enabled"`
+
+	NextSync  int64                `json:"next_sync,omitempty"`
+	SyncState enum.MirrorSyncState `json:"sync_state"`
+	LastSync  int64                `json:"last_sync,omitempty"`
+	LastError string               `json:"last_error,omitempty"`
+
+	CreatedBy int64 `json:"created_by"`
+	Created   int64 `json:"created"`
+	Updated   int64 `json:"updated"`
+}
+
+// MirrorCreateInput is used to create a new repository mirror.
+type MirrorCreateInput struct {
+	Identifier          string               `json:"identifier"`
+	Direction           enum.MirrorDirection `json:"direction"`
+	RemoteURL           string               `json:"remote_url"`
+	Username            string               `json:"username"`
+	SecretIdentifier    string               `json:"secret_identifier"`
+	ConnectorIdentifier string               `json:"connector_identifier"`
+	Interval            int64                `json:"interval"`
+	Enabled             bool                 `json:"enabled"`
+}
+
+// MirrorUpdateInput is used to update a repository mirror.
+type MirrorUpdateInput struct {
+	Identifier          *string `json:"identifier"`
+	RemoteURL           *string `json:"remote_url"`
+	Username            *string `json:"username"`
+	SecretIdentifier    *string `json:"secret_identifier"`
+	ConnectorIdentifier *string `json:"connector_identifier"`
+	Interval            *int64  `json:"interval"`
+	Enabled             *bool   `json:"enabled"`
+}
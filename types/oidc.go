@@ -0,0 +1,35 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// OIDCIdentity links an identity of an OpenID Connect provider to a principal.
+type OIDCIdentity struct {
+	Issuer      string `json:"issuer"`
+	Subject     string `json:"subject"`
+	PrincipalID int64  `json:"principal_id"`
+	Created     int64  `json:"created"`
+	LastLogin   int64  `json:"last_login"`
+}
+
+// OIDCClaims holds the claims of an OpenID Connect ID token that are used to sign in a user.
+type OIDCClaims struct {
+	Issuer            string   `json:"iss"`
+	Subject           string   `json:"sub"`
+	Email             string   `json:"email"`
+	EmailVerified     bool     `json:"email_verified"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+	Groups            []string `json:"-"` // taken from the configured groups claim
+}
@@ -45,6 +45,10 @@ type PullReq struct {
 	TargetRepoID int64  `json:"target_repo_id"`
 	TargetBranch string `json:"target_branch"`
 
+	// AllowMaintainerEdit allows principals with push access to the target repository
+	// to push to the source branch of a pull request opened from a fork.
+	AllowMaintainerEdit bool `json:"allow_maintainer_edit"`
+
 	ActivitySeq int64 `json:"-"` // not returned, because it's a server's internal field
 
 	MergedBy    *int64            `json:"-"` // not returned, because the merger info is in the Merger field
@@ -67,6 +71,7 @@ type PullReq struct {
 	Labels       []*LabelPullReqAssignmentInfo `json:"labels,omitempty"`
 	CheckSummary *CheckCountSummary            `json:"check_summary,omitempty"`
 	Rules        []RuleInfo                    `json:"rules,omitempty"`
+	AutoMerge    *PullReqAutoMerge             `json:"auto_merge,omitempty"`
 }
 
 func (pr *PullReq) UpdateMergeOutcome(method enum.MergeMethod, conflictFiles []string) {
@@ -273,6 +278,8 @@ type MergeResponse struct {
 	RequiresCodeOwnersApprovalLatest    bool               `json:"requires_code_owners_approval_latest,omitempty"`
 	RequiresCommentResolution           bool               `json:"requires_comment_resolution,omitempty"`
 	RequiresNoChangeRequests            bool               `json:"requires_no_change_requests,omitempty"`
+	RequiresSignedCommits               bool               `json:"requires_signed_commits,omitempty"`
+	RequiresMergeQueue                  bool               `json:"requires_merge_queue,omitempty"`
 }
 
 type MergeViolations struct {
@@ -61,6 +61,10 @@ var allPullReqActivityPayloads = func(
 	func() PullReqActivityPayload { return &PullRequestActivityPayloadBranchUpdate{} },
 	func() PullReqActivityPayload { return &PullRequestActivityPayloadBranchDelete{} },
 	func() PullReqActivityPayload { return &PullRequestActivityPayloadBranchRestore{} },
+	func() PullReqActivityPayload { return &PullRequestActivityPayloadAutoMergeEnable{} },
+	func() PullReqActivityPayload { return &PullRequestActivityPayloadAutoMergeDisable{} },
+	func() PullReqActivityPayload { return &PullRequestActivityPayloadMergeQueueAdd{} },
+	func() PullReqActivityPayload { return &PullRequestActivityPayloadMergeQueueRemove{} },
 })
 
 // newPayloadForActivity returns a new payload instance for the requested activity type.
@@ -102,6 +106,40 @@ func (a *PullRequestActivityPayloadMerge) ActivityType() enum.PullReqActivityTyp
 	return enum.PullReqActivityTypeMerge
 }
 
+type PullRequestActivityPayloadAutoMergeEnable struct {
+	MergeMethod enum.MergeMethod `json:"merge_method"`
+}
+
+func (a *PullRequestActivityPayloadAutoMergeEnable) ActivityType() enum.PullReqActivityType {
+	return enum.PullReqActivityTypeAutoMergeEnable
+}
+
+type PullRequestActivityPayloadAutoMergeDisable struct {
+	Reason enum.PullReqAutoMergeDisableReason `json:"reason"`
+	Error  string                             `json:"error,omitempty"`
+}
+
+func (a *PullRequestActivityPayloadAutoMergeDisable) ActivityType() enum.PullReqActivityType {
+	return enum.PullReqActivityTypeAutoMergeDisable
+}
+
+type PullRequestActivityPayloadMergeQueueAdd struct {
+	MergeMethod enum.MergeMethod `json:"merge_method"`
+}
+
+func (a *PullRequestActivityPayloadMergeQueueAdd) ActivityType() enum.PullReqActivityType {
+	return enum.PullReqActivityTypeMergeQueueAdd
+}
+
+type PullRequestActivityPayloadMergeQueueRemove struct {
+	Reason enum.MergeQueueRemoveReason `json:"reason"`
+	Error  string                      `json:"error,omitempty"`
+}
+
+func (a *PullRequestActivityPayloadMergeQueueRemove) ActivityType() enum.PullReqActivityType {
+	return enum.PullReqActivityTypeMergeQueueRemove
+}
+
 type PullRequestActivityPayloadStateChange struct {
 	Old      enum.PullReqState `json:"old"`
 	New      enum.PullReqState `json:"new"`
@@ -0,0 +1,33 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"github.com/harness/gitness/types/enum"
+)
+
+// PullReqAutoMerge holds the settings of a pull request that is merged
+// automatically as soon as all of its merge requirements are satisfied.
+type PullReqAutoMerge struct {
+	PullReqID int64            `json:"-"`
+	RepoID    int64            `json:"-"`
+	Method    enum.MergeMethod `json:"method"`
+	Title     string           `json:"title,omitempty"`
+	Message   string           `json:"message,omitempty"`
+
+	// CreatedBy is the principal who enabled auto-merge. The pull request is merged on its behalf.
+	CreatedBy int64 `json:"created_by"`
+	Created   int64 `json:"created"`
+}
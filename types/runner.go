@@ -0,0 +1,42 @@
+// Copyright 2023 Harness, Inc.
+//
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "github.com/harness/gitness/types/enum"
+
+// Runner is a standalone pipeline runner process that executes stages on another machine.
+// The platform and the labels are the ones the runner reported when it last polled for stages.
+type Runner struct {
+	ID          int64             `json:"-"`
+	Identifier  string            `json:"identifier"`
+	Description string            `json:"description"`
+	OS          string            `json:"os,omitempty"`
+	Arch        string            `json:"arch,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	LastSeen    int64             `json:"last_seen,omitempty"`
+	CreatedBy   int64             `json:"created_by"`
+	Created     int64             `json:"created"`
+	Updated     int64             `json:"updated"`
+
+	// Status is derived from the time the runner was last seen, it's not stored.
+	Status enum.RunnerStatus `json:"status"`
+}
+
+// RunnerHeartbeat holds the information a runner reports when contacting the server.
+type RunnerHeartbeat struct {
+	OS     string
+	Arch   string
+	Labels map[string]string
+	Time   int64
+}
@@ -45,4 +45,14 @@ type Stage struct {
 	DependsOn   []string          `json:"depends_on,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Steps       []*Step           `json:"steps,omitempty"`
+
+	// ApprovalRequired holds the stage as blocked until it gets approved or declined.
+	ApprovalRequired bool   `json:"approval_required,omitempty"`
+	ApprovalBy       *int64 `json:"approval_by,omitempty"`
+	ApprovalDecided  int64  `json:"approval_decided,omitempty"`
+
+	// RunnerID is the remote runner the stage was last offered to. Once the stage is accepted,
+	// it's the remote runner executing the stage.
+	RunnerID           *int64 `json:"runner_id,omitempty"`
+	RunnerOfferExpires int64  `json:"-"`
 }
@@ -32,6 +32,32 @@ type Token struct {
 	// IssuedAt is the unix time at which the token was issued.
 	IssuedAt  int64 `db:"token_issued_at"          json:"issued_at"`
 	CreatedBy int64 `db:"token_created_by"         json:"created_by"`
+	// Scope optionally restricts the permissions of the token.
+	Scope *TokenScope `db:"-"                        json:"scope,omitempty"`
+}
+
+// TokenScope restricts a token to a subset of the permissions of its principal.
+// A token with a scope never grants more than the principal is allowed to do.
+type TokenScope struct {
+	// Permissions are the only permissions granted by the token, empty means all permissions.
+	Permissions []enum.Permission `json:"permissions,omitempty"`
+
+	// SpaceIDs and RepoIDs restrict the token to the spaces (including their subspaces and repos)
+	// and to the repos. If both are empty, the token isn't restricted to any resources.
+	SpaceIDs []int64 `json:"-"`
+	RepoIDs  []int64 `json:"-"`
+
+	// Spaces and Repos are the paths of the spaces and repos the token is restricted to.
+	// They're populated only when listing tokens.
+	Spaces []string `json:"spaces,omitempty"`
+	Repos  []string `json:"repos,omitempty"`
+}
+
+// TokenScopeInput is used to request a scoped token.
+type TokenScopeInput struct {
+	Permissions []enum.Permission `json:"permissions"`
+	Spaces      []string          `json:"spaces"`
+	Repos       []string          `json:"repos"`
 }
 
 // TODO [CODE-1363]: remove after identifier migration.
@@ -31,6 +31,10 @@ type Trigger struct {
 	Disabled    bool                 `json:"disabled"`
 	Actions     []enum.TriggerAction `json:"actions"`
 	Identifier  string               `json:"identifier"`
+	Cron        string               `json:"cron,omitempty"`
+	Branch      string               `json:"branch,omitempty"`
+	Timezone    string               `json:"timezone,omitempty"`
+	CronNext    int64                `json:"cron_next,omitempty"`
 	Created     int64                `json:"created"`
 	Updated     int64                `json:"updated"`
 	Version     int64                `json:"-"`
@@ -0,0 +1,72 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// UserTOTP holds the time-based one-time password (TOTP) second factor of a user.
+type UserTOTP struct {
+	PrincipalID int64 `json:"-"`
+
+	// Secret is the encrypted shared secret of the authenticator app.
+	Secret []byte `json:"-"`
+
+	// Enabled is false while the enrollment isn't confirmed with a valid code.
+	Enabled bool `json:"enabled"`
+
+	// RecoveryCodes holds the hashes of the unused one-time recovery codes.
+	RecoveryCodes []string `json:"-"`
+
+	// LastUsedStep is the time step of the last accepted code, used to prevent code reuse.
+	LastUsedStep int64 `json:"-"`
+
+	// FailedAttempts is the number of invalid codes since the last accepted code or the last lockout.
+	FailedAttempts int `json:"-"`
+
+	// LockedUntil is the time until which no codes are accepted after too many failed attempts.
+	LockedUntil int64 `json:"-"`
+
+	// ChallengesRevoked is the time until which all issued two-factor challenges are rejected.
+	// It's updated when a challenge is used, so each challenge can be used only once.
+	ChallengesRevoked int64 `json:"-"`
+
+	Version int64 `json:"-"`
+	Created int64 `json:"created"`
+	Updated int64 `json:"updated"`
+}
+
+// TwoFactorStatus describes the two-factor authentication of a user.
+type TwoFactorStatus struct {
+	Enabled                bool  `json:"enabled"`
+	Required               bool  `json:"required"`
+	RecoveryCodesRemaining int   `json:"recovery_codes_remaining"`
+	Created                int64 `json:"created,omitempty"`
+}
+
+// TOTPEnrollment holds the data required to set up an authenticator app.
+type TOTPEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// TwoFactorRecoveryCodes holds newly generated recovery codes. They're shown to the user only once.
+type TwoFactorRecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorLoginResponse is returned after a successful two-factor authentication challenge.
+// The recovery codes are returned only if the challenge completed the enrollment.
+type TwoFactorLoginResponse struct {
+	TokenResponse
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
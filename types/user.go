@@ -34,7 +34,8 @@ type (
 		Updated     int64  `db:"principal_updated"        json:"updated"`
 
 		// User specific fields
-		Password string `db:"principal_user_password"    json:"-"`
+		Password      string `db:"principal_user_password"         json:"-"`
+		EmailVerified bool   `db:"principal_user_email_verified"   json:"email_verified"`
 	}
 
 	// UserInput store user account details used to
@@ -15,6 +15,8 @@
 // Package types defines common data structures.
 package types
 
+import "github.com/harness/gitness/types/enum"
+
 type UserGroup struct {
 	ID          int64    `json:"-"`
 	Identifier  string   `json:"identifier"`
@@ -24,6 +26,8 @@ type UserGroup struct {
 	Created     int64    `json:"created"`
 	Updated     int64    `json:"updated"`
 	Users       []string // Users are used by the code owners code
+	// ManagedBy is the source that manages the usergroup.
+	ManagedBy enum.UserGroupSource `json:"managed_by"`
 }
 
 type UserGroupInfo struct {
@@ -41,3 +45,13 @@ func (u *UserGroup) ToUserGroupInfo() *UserGroupInfo {
 		Description: u.Description,
 	}
 }
+
+// UserGroupMember represents a membership of a principal in a usergroup.
+type UserGroupMember struct {
+	UserGroupID int64 `json:"usergroup_id"`
+	PrincipalID int64 `json:"principal_id"`
+	CreatedBy   int64 `json:"-"`
+	Created     int64 `json:"created"`
+	// Source is the source that manages the membership.
+	Source enum.UserGroupSource `json:"source"`
+}
@@ -56,7 +56,8 @@ const pullRequestActions: TriggerAction[] = [
   { name: 'Pull Request Updated', value: 'pullreq_branch_updated' },
   { name: 'Pull Request Reopened', value: 'pullreq_reopened' },
   { name: 'Pull Request Closed', value: 'pullreq_closed' },
-  { name: 'Pull Request Merged', value: 'pullreq_merged' }
+  { name: 'Pull Request Merged', value: 'pullreq_merged' },
+  { name: 'Pull Request Merge Queue', value: 'pullreq_merge_queue' }
 ]
 
 const tagActions: TriggerAction[] = [
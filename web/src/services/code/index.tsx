@@ -163,6 +163,7 @@ export type EnumTriggerAction =
   | 'pullreq_branch_updated'
   | 'pullreq_closed'
   | 'pullreq_created'
+  | 'pullreq_merge_queue'
   | 'pullreq_merged'
   | 'pullreq_reopened'
   | 'tag_created'
@@ -186,6 +187,7 @@ export type EnumWebhookTrigger =
   | 'pullreq_review_submitted'
   | 'pullreq_created'
   | 'pullreq_label_assigned'
+  | 'pullreq_merge_queue'
   | 'pullreq_merged'
   | 'pullreq_reopened'
   | 'pullreq_updated'